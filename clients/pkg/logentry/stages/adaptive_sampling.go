@@ -0,0 +1,441 @@
+package stages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+const (
+	ErrAdaptiveSamplingTargetRequired    = "adaptive_sampling stage requires a positive target_rate_bytes or hints"
+	ErrAdaptiveSamplingInvalidTargetRate = "adaptive_sampling stage target_rate_bytes must not be negative"
+	ErrAdaptiveSamplingInvalidDuration   = "adaptive_sampling stage failed to parse %s: %v"
+	ErrAdaptiveSamplingInvalidExpression = "adaptive_sampling stage keep_expression compilation error: %v"
+	ErrAdaptiveSamplingHintsURLRequired  = "adaptive_sampling stage hints require an url"
+)
+
+const (
+	defaultAdaptiveSamplingWindow      = time.Minute
+	defaultAdaptiveSamplingMaxPatterns = 1000
+	defaultAdaptiveSamplingRateKey     = "sample_rate"
+	defaultAdaptiveSamplingLevelSource = "level"
+	defaultHintsRefreshInterval        = time.Minute
+	defaultHintsTimeout                = 10 * time.Second
+
+	// maxPatternTokens is the number of tokens of a line used to compute its pattern.
+	maxPatternTokens = 20
+	// overflowPattern holds the lines of new patterns once a stream tracks
+	// max_patterns patterns.
+	overflowPattern = 0
+)
+
+var (
+	defaultAdaptiveSamplingDropReason = "adaptive_sampling_stage"
+	defaultAdaptiveSamplingKeepLevels = []string{"error", "fatal", "critical", "panic"}
+)
+
+// AdaptiveSamplingConfig contains the configuration for an adaptiveSamplingStage
+type AdaptiveSamplingConfig struct {
+	// TargetRateBytes is the rate in bytes per second every stream is sampled
+	// down to, when hints are not configured or not available yet.
+	TargetRateBytes float64  `mapstructure:"target_rate_bytes"`
+	Window          *string  `mapstructure:"window"`
+	LevelSource     *string  `mapstructure:"level_source"`
+	KeepLevels      []string `mapstructure:"keep_levels"`
+	KeepExpression  *string  `mapstructure:"keep_expression"`
+	MaxPatterns     int      `mapstructure:"max_patterns"`
+	SampleRateKey   *string  `mapstructure:"sample_rate_key"`
+	DropReason      *string  `mapstructure:"drop_counter_reason"`
+
+	Hints *AdaptiveSamplingHintsConfig `mapstructure:"hints"`
+
+	window    time.Duration
+	keepRegex *regexp.Regexp
+}
+
+// AdaptiveSamplingHintsConfig configures the Loki endpoint target rates are
+// pulled from.
+type AdaptiveSamplingHintsConfig struct {
+	URL             string  `mapstructure:"url"`
+	TenantID        string  `mapstructure:"tenant_id"`
+	RefreshInterval *string `mapstructure:"refresh_interval"`
+	Timeout         *string `mapstructure:"timeout"`
+
+	refreshInterval time.Duration
+	timeout         time.Duration
+}
+
+// validateAdaptiveSamplingConfig validates the config and sets defaults.
+func validateAdaptiveSamplingConfig(cfg *AdaptiveSamplingConfig) error {
+	if cfg.TargetRateBytes < 0 {
+		return errors.New(ErrAdaptiveSamplingInvalidTargetRate)
+	}
+	if cfg.TargetRateBytes == 0 && cfg.Hints == nil {
+		return errors.New(ErrAdaptiveSamplingTargetRequired)
+	}
+
+	var err error
+	if cfg.window, err = parsePositiveDuration(cfg.Window, "window", defaultAdaptiveSamplingWindow); err != nil {
+		return err
+	}
+	if cfg.KeepExpression != nil {
+		if cfg.keepRegex, err = regexp.Compile(*cfg.KeepExpression); err != nil {
+			return errors.Errorf(ErrAdaptiveSamplingInvalidExpression, err)
+		}
+	}
+	if cfg.LevelSource == nil {
+		source := defaultAdaptiveSamplingLevelSource
+		cfg.LevelSource = &source
+	}
+	if cfg.KeepLevels == nil {
+		cfg.KeepLevels = defaultAdaptiveSamplingKeepLevels
+	}
+	if cfg.MaxPatterns <= 0 {
+		cfg.MaxPatterns = defaultAdaptiveSamplingMaxPatterns
+	}
+	if cfg.SampleRateKey == nil {
+		key := defaultAdaptiveSamplingRateKey
+		cfg.SampleRateKey = &key
+	}
+	if cfg.DropReason == nil || *cfg.DropReason == "" {
+		cfg.DropReason = &defaultAdaptiveSamplingDropReason
+	}
+
+	if cfg.Hints != nil {
+		if cfg.Hints.URL == "" {
+			return errors.New(ErrAdaptiveSamplingHintsURLRequired)
+		}
+		if cfg.Hints.refreshInterval, err = parsePositiveDuration(cfg.Hints.RefreshInterval, "hints refresh_interval", defaultHintsRefreshInterval); err != nil {
+			return err
+		}
+		if cfg.Hints.timeout, err = parsePositiveDuration(cfg.Hints.Timeout, "hints timeout", defaultHintsTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parsePositiveDuration(s *string, name string, def time.Duration) (time.Duration, error) {
+	if s == nil {
+		return def, nil
+	}
+	d, err := time.ParseDuration(*s)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("duration must be positive")
+	}
+	if err != nil {
+		return 0, errors.Errorf(ErrAdaptiveSamplingInvalidDuration, name, err)
+	}
+	return d, nil
+}
+
+// newAdaptiveSamplingStage creates an adaptiveSamplingStage from config.
+func newAdaptiveSamplingStage(logger log.Logger, config interface{}, registerer prometheus.Registerer) (Stage, error) {
+	cfg := &AdaptiveSamplingConfig{}
+	err := mapstructure.WeakDecode(config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = validateAdaptiveSamplingConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &adaptiveSamplingStage{
+		logger:     log.With(logger, "component", "stage", "type", "adaptive_sampling"),
+		cfg:        cfg,
+		dropCount:  getDropCountMetric(registerer),
+		hintedRate: atomic.NewFloat64(0),
+		streams:    map[model.Fingerprint]*sampledStream{},
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:        time.Now,
+		cancel:     cancel,
+	}
+	if cfg.Hints != nil {
+		s.wg.Add(1)
+		go s.pullHints(ctx)
+	}
+	return s, nil
+}
+
+// adaptiveSamplingStage samples every stream down to a target rate. Lines are
+// grouped by pattern and frequent patterns are sampled more aggressively than
+// rare ones, lines with an error level are always kept.
+type adaptiveSamplingStage struct {
+	logger     log.Logger
+	cfg        *AdaptiveSamplingConfig
+	dropCount  *prometheus.CounterVec
+	hintedRate *atomic.Float64
+
+	streams   map[model.Fingerprint]*sampledStream
+	lastSweep time.Time
+	random    *rand.Rand
+	now       func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// sampledStream tracks the bytes of every pattern of a stream over the
+// current and the previous window.
+type sampledStream struct {
+	windowStart time.Time
+	current     map[uint64]int
+	previous    map[uint64]int
+	// capBytes is the number of bytes kept per pattern and window, computed
+	// from the previous window. Patterns below the cap are fully kept.
+	capBytes float64
+}
+
+func (s *adaptiveSamplingStage) Run(in chan Entry) chan Entry {
+	return RunWithSkip(in, func(e Entry) (Entry, bool) {
+		rate, keep := s.sample(e)
+		if !keep {
+			s.dropCount.WithLabelValues(*s.cfg.DropReason).Inc()
+			return e, true
+		}
+		if *s.cfg.SampleRateKey != "" {
+			e.StructuredMetadata = append(e.StructuredMetadata, logproto.LabelAdapter{
+				Name:  *s.cfg.SampleRateKey,
+				Value: strconv.FormatFloat(rate, 'f', -1, 64),
+			})
+		}
+		return e, false
+	})
+}
+
+// sample returns whether the entry is kept and the number of entries it
+// stands for.
+func (s *adaptiveSamplingStage) sample(e Entry) (float64, bool) {
+	if s.alwaysKeep(e) {
+		return 1, true
+	}
+	target := s.targetRate()
+	if target <= 0 {
+		return 1, true
+	}
+
+	now := s.now()
+	s.sweep(now)
+
+	stream := s.stream(e.Labels.Fingerprint(), now)
+	stream.rotate(now, s.cfg.window, target*s.cfg.window.Seconds())
+
+	pattern := linePattern(e.Line)
+	if _, ok := stream.current[pattern]; !ok && len(stream.current) >= s.cfg.MaxPatterns {
+		pattern = overflowPattern
+	}
+	stream.current[pattern] += len(e.Line)
+
+	observed := stream.previous[pattern]
+	if observed == 0 || float64(observed) <= stream.capBytes {
+		return 1, true
+	}
+	p := stream.capBytes / float64(observed)
+	if s.random.Float64() >= p {
+		return 0, false
+	}
+	return math.Round(1000/p) / 1000, true
+}
+
+func (s *adaptiveSamplingStage) alwaysKeep(e Entry) bool {
+	if s.cfg.keepRegex != nil && s.cfg.keepRegex.MatchString(e.Line) {
+		return true
+	}
+	v, ok := e.Extracted[*s.cfg.LevelSource]
+	if !ok {
+		return false
+	}
+	lvl, err := getString(v)
+	if err != nil {
+		return false
+	}
+	for _, keep := range s.cfg.KeepLevels {
+		if strings.EqualFold(lvl, keep) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetRate returns the rate given by hints if any, the configured rate
+// otherwise.
+func (s *adaptiveSamplingStage) targetRate() float64 {
+	if rate := s.hintedRate.Load(); rate > 0 {
+		return rate
+	}
+	return s.cfg.TargetRateBytes
+}
+
+func (s *adaptiveSamplingStage) stream(fp model.Fingerprint, now time.Time) *sampledStream {
+	stream, ok := s.streams[fp]
+	if !ok {
+		stream = &sampledStream{
+			windowStart: now,
+			current:     map[uint64]int{},
+			capBytes:    math.Inf(1),
+		}
+		s.streams[fp] = stream
+	}
+	return stream
+}
+
+// sweep forgets the streams which didn't receive any entry during the last two
+// windows, it runs at most once per window.
+func (s *adaptiveSamplingStage) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.cfg.window {
+		return
+	}
+	s.lastSweep = now
+	for fp, stream := range s.streams {
+		if now.Sub(stream.windowStart) >= 2*s.cfg.window {
+			delete(s.streams, fp)
+		}
+	}
+}
+
+// rotate starts a new window once the current one is over and computes the
+// per pattern cap so the stream stays within budget bytes per window.
+func (st *sampledStream) rotate(now time.Time, window time.Duration, budget float64) {
+	elapsed := now.Sub(st.windowStart)
+	if elapsed < window {
+		return
+	}
+	st.previous = st.current
+	if elapsed >= 2*window {
+		// The stream was idle, what it sent a while ago doesn't tell much.
+		st.previous = nil
+	}
+	st.current = make(map[uint64]int, len(st.previous))
+	st.windowStart = now
+	st.capBytes = capPerPattern(st.previous, budget)
+}
+
+// capPerPattern returns the number of bytes each pattern can keep so that the
+// total is budget: patterns smaller than the cap are kept entirely and
+// the remaining budget is evenly shared between the larger ones.
+func capPerPattern(bytes map[uint64]int, budget float64) float64 {
+	sizes := make([]int, 0, len(bytes))
+	total := 0
+	for _, b := range bytes {
+		sizes = append(sizes, b)
+		total += b
+	}
+	if float64(total) <= budget {
+		return math.Inf(1)
+	}
+	sort.Ints(sizes)
+	for i, b := range sizes {
+		share := budget / float64(len(sizes)-i)
+		if float64(b) > share {
+			return share
+		}
+		budget -= float64(b)
+	}
+	return math.Inf(1)
+}
+
+// linePattern returns a hash of the first tokens of a line, tokens containing
+// digits are considered variable and ignored.
+func linePattern(line string) uint64 {
+	h := fnv.New64a()
+	tokens := 0
+	for _, tok := range strings.Fields(line) {
+		if tokens == maxPatternTokens {
+			break
+		}
+		tokens++
+		if strings.ContainsAny(tok, "0123456789") {
+			_, _ = h.Write([]byte("<_> "))
+			continue
+		}
+		_, _ = h.Write([]byte(tok))
+		_, _ = h.Write([]byte(" "))
+	}
+	if sum := h.Sum64(); sum != overflowPattern {
+		return sum
+	}
+	return overflowPattern + 1
+}
+
+type samplingHints struct {
+	TargetStreamRateBytes float64 `json:"target_stream_rate_bytes"`
+}
+
+// pullHints periodically fetches the target rate from Loki.
+func (s *adaptiveSamplingStage) pullHints(ctx context.Context) {
+	defer s.wg.Done()
+
+	client := &http.Client{Timeout: s.cfg.Hints.timeout}
+	ticker := time.NewTicker(s.cfg.Hints.refreshInterval)
+	defer ticker.Stop()
+	for {
+		rate, err := s.fetchHints(ctx, client)
+		if err != nil {
+			level.Warn(s.logger).Log("msg", "failed to pull sampling hints, keeping the current target rate", "url", s.cfg.Hints.URL, "err", err)
+		} else {
+			s.hintedRate.Store(rate)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *adaptiveSamplingStage) fetchHints(ctx context.Context, client *http.Client) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.Hints.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if s.cfg.Hints.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.cfg.Hints.TenantID)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	var hints samplingHints
+	if err := json.NewDecoder(resp.Body).Decode(&hints); err != nil {
+		return 0, err
+	}
+	return hints.TargetStreamRateBytes, nil
+}
+
+// Name implements Stage
+func (s *adaptiveSamplingStage) Name() string {
+	return StageTypeAdaptiveSampling
+}
+
+// Cleanup implements Stage.
+func (s *adaptiveSamplingStage) Cleanup() {
+	s.cancel()
+	s.wg.Wait()
+}
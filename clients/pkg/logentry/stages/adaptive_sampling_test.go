@@ -0,0 +1,188 @@
+package stages
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+var testAdaptiveSamplingYaml = `
+pipeline_stages:
+- logfmt:
+    mapping:
+      level:
+- adaptive_sampling:
+    target_rate_bytes: 100
+    window: 10s
+`
+
+func TestAdaptiveSamplingPipeline(t *testing.T) {
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testAdaptiveSamplingYaml), &plName, prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer pl.Cleanup()
+
+	// The first window of a stream is not sampled.
+	entries := make([]Entry, 0, 100)
+	for i := 0; i < 100; i++ {
+		entries = append(entries, newEntry(nil, model.LabelSet{"app": "foo"}, "level=info msg=ok", time.Now()))
+	}
+	out := processEntries(pl, entries...)
+	require.Len(t, out, 100)
+	require.Equal(t, "sample_rate", out[0].StructuredMetadata[0].Name)
+	require.Equal(t, "1", out[0].StructuredMetadata[0].Value)
+}
+
+func TestAdaptiveSampling(t *testing.T) {
+	st, err := newAdaptiveSamplingStage(util_log.Logger, map[string]interface{}{
+		"target_rate_bytes": 100,
+		"window":            "10s",
+	}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer st.Cleanup()
+
+	s := st.(*adaptiveSamplingStage)
+	s.random = rand.New(rand.NewSource(1))
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+
+	const (
+		frequent = "GET /health 200"           // 15 bytes
+		rare     = "user login failed for bob" // 25 bytes
+	)
+	lbs := model.LabelSet{"app": "foo"}
+	window := func() (kept map[string][]Entry) {
+		var entries []Entry
+		for i := 0; i < 1000; i++ {
+			entries = append(entries, newEntry(nil, lbs, frequent, now))
+		}
+		for i := 0; i < 10; i++ {
+			entries = append(entries, newEntry(nil, lbs, rare, now))
+			entries = append(entries, newEntry(map[string]interface{}{"level": "ERROR"}, lbs, frequent, now))
+		}
+		kept = map[string][]Entry{}
+		for _, e := range processEntries(s, entries...) {
+			kept[e.Line] = append(kept[e.Line], e)
+		}
+		return kept
+	}
+
+	kept := window()
+	require.Len(t, kept[frequent], 1010)
+	require.Len(t, kept[rare], 10)
+
+	// The budget of 1000 bytes per window is shared between patterns: the rare
+	// one is fully kept and the frequent one is capped to the 750 bytes left,
+	// one line every 20. Errors are always kept and don't count.
+	now = now.Add(10 * time.Second)
+	kept = window()
+	require.Len(t, kept[rare], 10)
+	require.Equal(t, "1", kept[rare][0].StructuredMetadata[0].Value)
+
+	var sampled, errors int
+	for _, e := range kept[frequent] {
+		switch e.StructuredMetadata[0].Value {
+		case "20":
+			sampled++
+		case "1":
+			errors++
+		default:
+			t.Fatalf("unexpected sample rate %s", e.StructuredMetadata[0].Value)
+		}
+	}
+	require.Equal(t, 10, errors)
+	require.InDelta(t, 50, sampled, 20)
+
+	// After being idle the stream starts over.
+	now = now.Add(time.Minute)
+	kept = window()
+	require.Len(t, kept[frequent], 1010)
+}
+
+func TestAdaptiveSamplingHints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Scope-OrgID") != "tenant-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"target_stream_rate_bytes":4096,"active_streams":12}`))
+	}))
+	defer srv.Close()
+
+	st, err := newAdaptiveSamplingStage(util_log.Logger, map[string]interface{}{
+		"hints": map[string]interface{}{
+			"url":              srv.URL,
+			"tenant_id":        "tenant-1",
+			"refresh_interval": "10ms",
+		},
+	}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	defer st.Cleanup()
+
+	s := st.(*adaptiveSamplingStage)
+	require.Eventually(t, func() bool {
+		return s.targetRate() == 4096
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_validateAdaptiveSamplingConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		config  *AdaptiveSamplingConfig
+		wantErr string
+	}{
+		{
+			name:    "missing target",
+			config:  &AdaptiveSamplingConfig{},
+			wantErr: ErrAdaptiveSamplingTargetRequired,
+		},
+		{
+			name:    "negative target",
+			config:  &AdaptiveSamplingConfig{TargetRateBytes: -1},
+			wantErr: ErrAdaptiveSamplingInvalidTargetRate,
+		},
+		{
+			name:    "invalid window",
+			config:  &AdaptiveSamplingConfig{TargetRateBytes: 1, Window: ptrFromString("0s")},
+			wantErr: "adaptive_sampling stage failed to parse window: duration must be positive",
+		},
+		{
+			name:    "invalid expression",
+			config:  &AdaptiveSamplingConfig{TargetRateBytes: 1, KeepExpression: ptrFromString("(")},
+			wantErr: "adaptive_sampling stage keep_expression compilation error: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:    "hints without url",
+			config:  &AdaptiveSamplingConfig{Hints: &AdaptiveSamplingHintsConfig{}},
+			wantErr: ErrAdaptiveSamplingHintsURLRequired,
+		},
+		{
+			name:   "hints",
+			config: &AdaptiveSamplingConfig{Hints: &AdaptiveSamplingHintsConfig{URL: "http://loki:3100/loki/api/v1/sampling_hints"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAdaptiveSamplingConfig(tt.config)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_capPerPattern(t *testing.T) {
+	require.Equal(t, 750.0, capPerPattern(map[uint64]int{1: 250, 2: 15000}, 1000))
+	require.Equal(t, 300.0, capPerPattern(map[uint64]int{1: 400, 2: 500, 3: 600}, 900))
+	require.True(t, capPerPattern(map[uint64]int{1: 400, 2: 500}, 900) > 500)
+	require.Equal(t, linePattern("took 10ms for user bob"), linePattern("took 250ms for user bob"))
+	require.NotEqual(t, linePattern("took 10ms for user bob"), linePattern("took 10ms for user alice"))
+}
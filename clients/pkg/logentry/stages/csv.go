@@ -0,0 +1,236 @@
+package stages
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+)
+
+// Config Errors
+const (
+	ErrCSVColumnsRequired  = "csv columns are required"
+	ErrEmptyCSVStageConfig = "empty csv stage configuration"
+	ErrEmptyCSVStageSource = "empty source"
+	ErrInvalidCSVDelimiter = "csv delimiter must be a single character"
+	ErrMalformedCSV        = "malformed csv"
+)
+
+const (
+	defaultCSVDelimiter = ","
+	// maxCSVHeadersCacheSize is the number of streams whose header is kept
+	// in memory.
+	maxCSVHeadersCacheSize = 10000
+)
+
+// CSVConfig represents a CSV Stage configuration
+type CSVConfig struct {
+	Columns       []string `mapstructure:"columns"`
+	Header        bool     `mapstructure:"header"`
+	Delimiter     string   `mapstructure:"delimiter"`
+	Source        *string  `mapstructure:"source"`
+	DropMalformed bool     `mapstructure:"drop_malformed"`
+}
+
+// validateCSVConfig validates a csv config and returns the delimiter to use.
+func validateCSVConfig(c *CSVConfig) (rune, error) {
+	if c == nil {
+		return 0, errors.New(ErrEmptyCSVStageConfig)
+	}
+
+	// Columns cannot be read from the header only, since a stream does not
+	// start with its header when Promtail resumes reading a file.
+	if len(c.Columns) == 0 {
+		return 0, errors.New(ErrCSVColumnsRequired)
+	}
+
+	if c.Source != nil && *c.Source == "" {
+		return 0, errors.New(ErrEmptyCSVStageSource)
+	}
+
+	if c.Delimiter == "" {
+		c.Delimiter = defaultCSVDelimiter
+	}
+	delimiter, size := utf8.DecodeRuneInString(c.Delimiter)
+	if size != len(c.Delimiter) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' || delimiter == utf8.RuneError {
+		return 0, errors.New(ErrInvalidCSVDelimiter)
+	}
+	return delimiter, nil
+}
+
+// csvStage sets extracted data from the columns of a csv line.
+type csvStage struct {
+	cfg       *CSVConfig
+	delimiter rune
+	logger    log.Logger
+
+	// names are the configured column names, used to recognize header lines.
+	names map[string]struct{}
+	// headers holds the column names in the order of the last header line of
+	// every stream, keyed by the fingerprint of the stream labels.
+	headers *lru.Cache
+}
+
+// newCSVStage creates a new csv pipeline stage from a config.
+func newCSVStage(logger log.Logger, config interface{}) (Stage, error) {
+	cfg, err := parseCSVConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	delimiter, err := validateCSVConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &csvStage{
+		cfg:       cfg,
+		delimiter: delimiter,
+		logger:    log.With(logger, "component", "stage", "type", "csv"),
+	}
+	if cfg.Header {
+		s.names = make(map[string]struct{}, len(cfg.Columns))
+		for _, name := range cfg.Columns {
+			if name != "" {
+				s.names[name] = struct{}{}
+			}
+		}
+		s.headers, err = lru.New(maxCSVHeadersCacheSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func parseCSVConfig(config interface{}) (*CSVConfig, error) {
+	cfg := &CSVConfig{}
+	err := mapstructure.Decode(config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *csvStage) Run(in chan Entry) chan Entry {
+	return RunWithSkip(in, func(e Entry) (Entry, bool) {
+		skip, err := c.processEntry(e.Labels, e.Extracted, &e.Line)
+		return e, skip || (err != nil && c.cfg.DropMalformed)
+	})
+}
+
+// processEntry extracts the columns of an entry, it returns true if the entry
+// is a header line that must be dropped.
+func (c *csvStage) processEntry(labels model.LabelSet, extracted map[string]interface{}, entry *string) (bool, error) {
+	// If a source key is provided, the csv stage should process it
+	// from the extracted map, otherwise should fallback to the entry
+	input := entry
+
+	if c.cfg.Source != nil {
+		if _, ok := extracted[*c.cfg.Source]; !ok {
+			if Debug {
+				level.Debug(c.logger).Log("msg", "source does not exist in the set of extracted values", "source", *c.cfg.Source)
+			}
+			return false, nil
+		}
+
+		value, err := getString(extracted[*c.cfg.Source])
+		if err != nil {
+			if Debug {
+				level.Debug(c.logger).Log("msg", "failed to convert source value to string", "source", *c.cfg.Source, "err", err, "type", reflect.TypeOf(extracted[*c.cfg.Source]))
+			}
+			return false, nil
+		}
+
+		input = &value
+	}
+
+	if input == nil {
+		if Debug {
+			level.Debug(c.logger).Log("msg", "cannot parse a nil entry")
+		}
+		return false, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(*input))
+	r.Comma = c.delimiter
+	r.FieldsPerRecord = -1
+	record, err := r.Read()
+	if err != nil {
+		if Debug {
+			level.Debug(c.logger).Log("msg", "failed to parse log line", "err", err)
+		}
+		return false, errors.New(ErrMalformedCSV)
+	}
+
+	columns := c.cfg.Columns
+	if c.cfg.Header {
+		fp := labels.Fingerprint()
+		if header, ok := c.headerColumns(record); ok {
+			c.headers.Add(fp, header)
+			return true, nil
+		}
+		if header, ok := c.headers.Get(fp); ok {
+			columns = header.([]string)
+		}
+	}
+
+	for i, name := range columns {
+		if name == "" || i >= len(record) {
+			continue
+		}
+		extracted[name] = record[i]
+	}
+	if Debug {
+		level.Debug(c.logger).Log("msg", "extracted data debug in csv stage", "extracted data", fmt.Sprintf("%v", extracted))
+	}
+	return false, nil
+}
+
+// headerColumns returns whether a record is a header line, which holds all
+// the configured column names in any order, along with the columns to use
+// for the following lines. Names of the header which are not configured are
+// skipped.
+func (c *csvStage) headerColumns(record []string) ([]string, bool) {
+	if len(c.names) == 0 || len(record) < len(c.names) {
+		return nil, false
+	}
+
+	var columns []string
+	for i, name := range record {
+		if _, ok := c.names[name]; !ok {
+			continue
+		}
+		if columns == nil {
+			columns = make([]string, len(record))
+		}
+		columns[i] = name
+	}
+	if columns == nil {
+		return nil, false
+	}
+
+	found := make(map[string]struct{}, len(c.names))
+	for _, name := range columns {
+		if name != "" {
+			found[name] = struct{}{}
+		}
+	}
+	return columns, len(found) == len(c.names)
+}
+
+// Name implements Stage
+func (c *csvStage) Name() string {
+	return StageTypeCSV
+}
+
+// Cleanup implements Stage.
+func (*csvStage) Cleanup() {
+	// no-op
+}
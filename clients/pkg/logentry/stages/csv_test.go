@@ -0,0 +1,124 @@
+package stages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+func TestPipeline_CSV(t *testing.T) {
+	t.Parallel()
+
+	cfg := `
+pipeline_stages:
+- csv:
+    columns: [ts, level, "", msg]
+`
+	pl, err := NewPipeline(util_log.Logger, loadConfig(cfg), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	out := processEntries(pl, newEntry(nil, nil, `2024-01-01T00:00:00Z,error,api,"disk full, retrying"`, time.Now()))
+	require.Len(t, out, 1)
+	require.Equal(t, map[string]interface{}{
+		"ts":    "2024-01-01T00:00:00Z",
+		"level": "error",
+		"msg":   "disk full, retrying",
+	}, out[0].Extracted)
+}
+
+func TestPipeline_CSVHeader(t *testing.T) {
+	t.Parallel()
+
+	cfg := `
+pipeline_stages:
+- csv:
+    columns: [method, path, status]
+    header: true
+    delimiter: ";"
+`
+	pl, err := NewPipeline(util_log.Logger, loadConfig(cfg), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	var (
+		access    = model.LabelSet{"filename": "access.csv"}
+		reordered = model.LabelSet{"filename": "reordered.csv"}
+		resumed   = model.LabelSet{"filename": "resumed.csv"}
+	)
+	out := processEntries(pl,
+		newEntry(nil, access, "method;path;status", time.Now()),
+		newEntry(nil, reordered, "status;host;method;path", time.Now()),
+		newEntry(nil, access, "GET;/;200", time.Now()),
+		newEntry(nil, reordered, "500;api;POST;/push", time.Now()),
+		// A stream read from the middle of a file uses the configured columns.
+		newEntry(nil, resumed, "GET;/ready;200", time.Now()),
+		// Repeated headers, for instance after a rotation, are dropped.
+		newEntry(nil, access, "method;path;status", time.Now()),
+		newEntry(nil, access, "POST;/push", time.Now()),
+	)
+	require.Len(t, out, 4)
+	require.Equal(t, map[string]interface{}{"filename": "access.csv", "method": "GET", "path": "/", "status": "200"}, out[0].Extracted)
+	require.Equal(t, map[string]interface{}{"filename": "reordered.csv", "method": "POST", "path": "/push", "status": "500"}, out[1].Extracted)
+	require.Equal(t, map[string]interface{}{"filename": "resumed.csv", "method": "GET", "path": "/ready", "status": "200"}, out[2].Extracted)
+	require.Equal(t, map[string]interface{}{"filename": "access.csv", "method": "POST", "path": "/push"}, out[3].Extracted)
+}
+
+func TestPipeline_CSVSource(t *testing.T) {
+	t.Parallel()
+
+	cfg := `
+pipeline_stages:
+- regex:
+    expression: "^(?P<level>\\w+) (?P<fields>.*)$"
+- csv:
+    source: fields
+    columns: [user, ip]
+    drop_malformed: true
+`
+	pl, err := NewPipeline(util_log.Logger, loadConfig(cfg), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	out := processEntries(pl,
+		newEntry(nil, nil, `info bob,10.0.0.1`, time.Now()),
+		newEntry(nil, nil, `info "bob,10.0.0.1`, time.Now()),
+	)
+	require.Len(t, out, 1)
+	require.Equal(t, "bob", out[0].Extracted["user"])
+	require.Equal(t, "10.0.0.1", out[0].Extracted["ip"])
+}
+
+func TestCSVConfig_validate(t *testing.T) {
+	t.Parallel()
+
+	source := ""
+	tests := map[string]struct {
+		config *CSVConfig
+		err    string
+	}{
+		"empty config":      {nil, ErrEmptyCSVStageConfig},
+		"no columns":        {&CSVConfig{}, ErrCSVColumnsRequired},
+		"header only":       {&CSVConfig{Header: true}, ErrCSVColumnsRequired},
+		"empty source":      {&CSVConfig{Columns: []string{"level"}, Source: &source}, ErrEmptyCSVStageSource},
+		"invalid delimiter": {&CSVConfig{Columns: []string{"level"}, Delimiter: "::"}, ErrInvalidCSVDelimiter},
+		"quote delimiter":   {&CSVConfig{Columns: []string{"level"}, Delimiter: `"`}, ErrInvalidCSVDelimiter},
+		"valid columns":     {&CSVConfig{Columns: []string{"level"}}, ""},
+		"valid header":      {&CSVConfig{Columns: []string{"level"}, Header: true, Delimiter: "\t"}, ""},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := validateCSVConfig(tt.config)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -16,31 +16,34 @@ import (
 )
 
 const (
-	StageTypeJSON            = "json"
-	StageTypeLogfmt          = "logfmt"
-	StageTypeRegex           = "regex"
-	StageTypeReplace         = "replace"
-	StageTypeMetric          = "metrics"
-	StageTypeLabel           = "labels"
-	StageTypeLabelDrop       = "labeldrop"
-	StageTypeTimestamp       = "timestamp"
-	StageTypeOutput          = "output"
-	StageTypeDocker          = "docker"
-	StageTypeCRI             = "cri"
-	StageTypeMatch           = "match"
-	StageTypeTemplate        = "template"
-	StageTypePipeline        = "pipeline"
-	StageTypeTenant          = "tenant"
-	StageTypeDrop            = "drop"
-	StageTypeSampling        = "sampling"
-	StageTypeLimit           = "limit"
-	StageTypeMultiline       = "multiline"
-	StageTypePack            = "pack"
-	StageTypeLabelAllow      = "labelallow"
-	StageTypeStaticLabels    = "static_labels"
-	StageTypeDecolorize      = "decolorize"
-	StageTypeEventLogMessage = "eventlogmessage"
-	StageTypeGeoIP           = "geoip"
+	StageTypeJSON             = "json"
+	StageTypeLogfmt           = "logfmt"
+	StageTypeRegex            = "regex"
+	StageTypeReplace          = "replace"
+	StageTypeMetric           = "metrics"
+	StageTypeLabel            = "labels"
+	StageTypeLabelDrop        = "labeldrop"
+	StageTypeTimestamp        = "timestamp"
+	StageTypeOutput           = "output"
+	StageTypeDocker           = "docker"
+	StageTypeCRI              = "cri"
+	StageTypeMatch            = "match"
+	StageTypeTemplate         = "template"
+	StageTypePipeline         = "pipeline"
+	StageTypeTenant           = "tenant"
+	StageTypeDrop             = "drop"
+	StageTypeSampling         = "sampling"
+	StageTypeLimit            = "limit"
+	StageTypeMultiline        = "multiline"
+	StageTypePack             = "pack"
+	StageTypeLabelAllow       = "labelallow"
+	StageTypeStaticLabels     = "static_labels"
+	StageTypeDecolorize       = "decolorize"
+	StageTypeEventLogMessage  = "eventlogmessage"
+	StageTypeGeoIP            = "geoip"
+	StageTypeXML              = "xml"
+	StageTypeCSV              = "csv"
+	StageTypeAdaptiveSampling = "adaptive_sampling"
 	// Deprecated. Renamed to `structured_metadata`. Will be removed after the migration.
 	StageTypeNonIndexedLabels   = "non_indexed_labels"
 	StageTypeStructuredMetadata = "structured_metadata"
@@ -208,6 +211,15 @@ func initCreators() {
 		StageTypeGeoIP: func(params StageCreationParams) (Stage, error) {
 			return newGeoIPStage(params.logger, params.config)
 		},
+		StageTypeXML: func(params StageCreationParams) (Stage, error) {
+			return newXMLStage(params.logger, params.config)
+		},
+		StageTypeCSV: func(params StageCreationParams) (Stage, error) {
+			return newCSVStage(params.logger, params.config)
+		},
+		StageTypeAdaptiveSampling: func(params StageCreationParams) (Stage, error) {
+			return newAdaptiveSamplingStage(params.logger, params.config, params.registerer)
+		},
 		StageTypeNonIndexedLabels:   newStructuredMetadataStage,
 		StageTypeStructuredMetadata: newStructuredMetadataStage,
 	}
@@ -0,0 +1,160 @@
+package stages
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/v3/pkg/logql/log/xmlexpr"
+)
+
+// Config Errors
+const (
+	ErrXMLExpressionsRequired = "xml expression is required"
+	ErrCouldNotCompileXPath   = "could not compile xml expression"
+	ErrEmptyXMLStageConfig    = "empty xml stage configuration"
+	ErrEmptyXMLStageSource    = "empty source"
+	ErrMalformedXML           = "malformed xml"
+)
+
+// XMLConfig represents a XML Stage configuration
+type XMLConfig struct {
+	Expressions   map[string]string `mapstructure:"expressions"`
+	Source        *string           `mapstructure:"source"`
+	DropMalformed bool              `mapstructure:"drop_malformed"`
+}
+
+// validateXMLConfig validates a xml config and returns a map of compiled expressions.
+func validateXMLConfig(c *XMLConfig) (map[string]*xmlexpr.Expr, error) {
+	if c == nil {
+		return nil, errors.New(ErrEmptyXMLStageConfig)
+	}
+
+	if len(c.Expressions) == 0 {
+		return nil, errors.New(ErrXMLExpressionsRequired)
+	}
+
+	if c.Source != nil && *c.Source == "" {
+		return nil, errors.New(ErrEmptyXMLStageSource)
+	}
+
+	expressions := map[string]*xmlexpr.Expr{}
+
+	for n, e := range c.Expressions {
+		var err error
+		expr := e
+		// If there is no expression, look for the first element with the name.
+		if e == "" {
+			expr = "//" + n
+		}
+		expressions[n], err = xmlexpr.Compile(expr)
+		if err != nil {
+			return nil, errors.Wrap(err, ErrCouldNotCompileXPath)
+		}
+	}
+	return expressions, nil
+}
+
+// xmlStage sets extracted data using XPath expressions
+type xmlStage struct {
+	cfg         *XMLConfig
+	expressions map[string]*xmlexpr.Expr
+	logger      log.Logger
+}
+
+// newXMLStage creates a new xml pipeline stage from a config.
+func newXMLStage(logger log.Logger, config interface{}) (Stage, error) {
+	cfg, err := parseXMLConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	expressions, err := validateXMLConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &xmlStage{
+		cfg:         cfg,
+		expressions: expressions,
+		logger:      log.With(logger, "component", "stage", "type", "xml"),
+	}, nil
+}
+
+func parseXMLConfig(config interface{}) (*XMLConfig, error) {
+	cfg := &XMLConfig{}
+	err := mapstructure.Decode(config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (x *xmlStage) Run(in chan Entry) chan Entry {
+	return RunWithSkip(in, func(e Entry) (Entry, bool) {
+		err := x.processEntry(e.Extracted, &e.Line)
+		return e, err != nil && x.cfg.DropMalformed
+	})
+}
+
+func (x *xmlStage) processEntry(extracted map[string]interface{}, entry *string) error {
+	// If a source key is provided, the xml stage should process it
+	// from the extracted map, otherwise should fallback to the entry
+	input := entry
+
+	if x.cfg.Source != nil {
+		if _, ok := extracted[*x.cfg.Source]; !ok {
+			if Debug {
+				level.Debug(x.logger).Log("msg", "source does not exist in the set of extracted values", "source", *x.cfg.Source)
+			}
+			return nil
+		}
+
+		value, err := getString(extracted[*x.cfg.Source])
+		if err != nil {
+			if Debug {
+				level.Debug(x.logger).Log("msg", "failed to convert source value to string", "source", *x.cfg.Source, "err", err, "type", reflect.TypeOf(extracted[*x.cfg.Source]))
+			}
+			return nil
+		}
+
+		input = &value
+	}
+
+	if input == nil {
+		if Debug {
+			level.Debug(x.logger).Log("msg", "cannot parse a nil entry")
+		}
+		return nil
+	}
+
+	doc, err := xmlexpr.Parse([]byte(*input))
+	if err != nil {
+		if Debug {
+			level.Debug(x.logger).Log("msg", "failed to parse log line", "err", err)
+		}
+		return errors.New(ErrMalformedXML)
+	}
+
+	for n, e := range x.expressions {
+		if v, ok := e.Eval(doc); ok {
+			extracted[n] = v
+		}
+	}
+	if Debug {
+		level.Debug(x.logger).Log("msg", "extracted data debug in xml stage", "extracted data", fmt.Sprintf("%v", extracted))
+	}
+	return nil
+}
+
+// Name implements Stage
+func (x *xmlStage) Name() string {
+	return StageTypeXML
+}
+
+// Cleanup implements Stage.
+func (*xmlStage) Cleanup() {
+	// no-op
+}
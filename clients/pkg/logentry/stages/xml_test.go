@@ -0,0 +1,154 @@
+package stages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+var testXMLYamlSingleStageWithoutSource = `
+pipeline_stages:
+- xml:
+    expressions:
+      event_id: /Event/System/EventID
+      provider: //Provider/@Name
+      ip: //Data[@Name='IpAddress']
+      Level:
+      unknown:
+`
+
+var testXMLYamlMultiStageWithSource = `
+pipeline_stages:
+- xml:
+    expressions:
+      payload:
+- xml:
+    expressions:
+      user: /user/name
+    source: payload
+`
+
+var testXMLLogLine = `<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Microsoft-Windows-Security-Auditing"/>
+    <EventID>4625</EventID>
+    <Level>0</Level>
+  </System>
+  <EventData>
+    <Data Name="TargetUserName">bob</Data>
+    <Data Name="IpAddress">10.0.0.1</Data>
+  </EventData>
+  <payload>&lt;user&gt;&lt;name&gt;marco&lt;/name&gt;&lt;/user&gt;</payload>
+</Event>`
+
+func TestPipeline_XML(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config          string
+		entry           string
+		expectedExtract map[string]interface{}
+	}{
+		"successfully run a pipeline with 1 xml stage without source": {
+			testXMLYamlSingleStageWithoutSource,
+			testXMLLogLine,
+			map[string]interface{}{
+				"event_id": "4625",
+				"provider": "Microsoft-Windows-Security-Auditing",
+				"ip":       "10.0.0.1",
+				"Level":    "0",
+			},
+		},
+		"successfully run a pipeline with 2 xml stages with source": {
+			testXMLYamlMultiStageWithSource,
+			testXMLLogLine,
+			map[string]interface{}{
+				"payload": "<user><name>marco</name></user>",
+				"user":    "marco",
+			},
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			pl, err := NewPipeline(util_log.Logger, loadConfig(testData.config), nil, prometheus.DefaultRegisterer)
+			assert.NoError(t, err, "Expected pipeline creation to not result in error")
+			out := processEntries(pl, newEntry(nil, nil, testData.entry, time.Now()))[0]
+			assert.Equal(t, testData.expectedExtract, out.Extracted)
+		})
+	}
+}
+
+func TestXMLConfig_validate(t *testing.T) {
+	t.Parallel()
+
+	source := ""
+	tests := map[string]struct {
+		config *XMLConfig
+		err    string
+	}{
+		"empty config": {
+			nil,
+			ErrEmptyXMLStageConfig,
+		},
+		"no expressions": {
+			&XMLConfig{},
+			ErrXMLExpressionsRequired,
+		},
+		"empty source": {
+			&XMLConfig{Expressions: map[string]string{"level": ""}, Source: &source},
+			ErrEmptyXMLStageSource,
+		},
+		"invalid expression": {
+			&XMLConfig{Expressions: map[string]string{"level": "/Event["}},
+			ErrCouldNotCompileXPath,
+		},
+		"valid": {
+			&XMLConfig{Expressions: map[string]string{"level": "/Event/System/Level"}},
+			"",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := validateXMLConfig(tt.config)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestXMLParser_DropMalformed(t *testing.T) {
+	t.Parallel()
+
+	cfg := `
+pipeline_stages:
+- xml:
+    drop_malformed: true
+    expressions:
+      level:
+`
+	pl, err := NewPipeline(util_log.Logger, loadConfig(cfg), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	out := processEntries(pl,
+		newEntry(nil, nil, `<event><level>info</level></event>`, time.Now()),
+		newEntry(nil, nil, `level=info`, time.Now()),
+	)
+	require.Len(t, out, 1)
+	require.Equal(t, "info", out[0].Extracted["level"])
+}
@@ -0,0 +1,129 @@
+package positions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/storage/bucket"
+)
+
+// ObjectStorageConfig configures the object storage positions backend.
+type ObjectStorageConfig struct {
+	bucket.Config `yaml:",inline"`
+	// Prefix is the directory of the bucket holding the leases.
+	Prefix string `yaml:"prefix"`
+}
+
+// RegisterFlagsWithPrefix registers flags where every name is prefixed by
+// prefix, which should end with a period.
+func (cfg *ObjectStorageConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.Config.RegisterFlagsWithPrefix(prefix, f)
+	f.StringVar(&cfg.Prefix, prefix+"prefix", "positions/", "Directory of the bucket holding the positions.")
+}
+
+// maxCASRetries is how many times a lease is read again when it was
+// concurrently modified.
+const maxCASRetries = 10
+
+// bucketLeaseClient stores every lease as a JSON object in a bucket, updated
+// with conditional writes.
+type bucketLeaseClient struct {
+	bucket  objstore.Bucket
+	objects conditionalObjects
+	prefix  string
+}
+
+func newBucketStore(cfg Config, reg prometheus.Registerer, logger log.Logger) (*leaseStore, error) {
+	if cfg.LeaseDuration <= 0 {
+		return nil, fmt.Errorf("positions lease duration must be greater than 0")
+	}
+	instanceID, err := leaseInstanceID(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := newConditionalObjects(cfg.ObjectStorage.Config, logger)
+	if err != nil {
+		return nil, err
+	}
+	bkt, err := bucket.NewClient(context.Background(), cfg.ObjectStorage.Config, "positions", logger, reg)
+	if err != nil {
+		return nil, err
+	}
+	client := bucketLeaseClient{bucket: bkt, objects: objects, prefix: cfg.ObjectStorage.Prefix}
+	return newLeaseStore(client, instanceID, cfg.LeaseDuration, reg, logger), nil
+}
+
+func (c bucketLeaseClient) list(ctx context.Context) ([]string, error) {
+	var keys []string
+	err := c.bucket.Iter(ctx, c.prefix, func(name string) error {
+		// Skip the sub-directories, leases are stored at the top level.
+		if strings.HasSuffix(name, objstore.DirDelim) {
+			return nil
+		}
+		keys = append(keys, path.Base(name))
+		return nil
+	})
+	return keys, err
+}
+
+func (c bucketLeaseClient) get(ctx context.Context, key string) (*Lease, error) {
+	l, _, err := c.read(ctx, key)
+	return l, err
+}
+
+func (c bucketLeaseClient) cas(ctx context.Context, key string, f func(*Lease) (*Lease, bool)) error {
+	for i := 0; i < maxCASRetries; i++ {
+		in, version, err := c.read(ctx, key)
+		if err != nil {
+			return err
+		}
+		out, ok := f(in)
+		if !ok {
+			return nil
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			return err
+		}
+		err = c.objects.put(ctx, c.name(key), b, version)
+		if !errors.Is(err, errVersionConflict) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to update lease %s: %w", key, errVersionConflict)
+}
+
+// read returns the lease stored at key and the version of its object.
+func (c bucketLeaseClient) read(ctx context.Context, key string) (*Lease, string, error) {
+	b, version, err := c.objects.get(ctx, c.name(key))
+	if err != nil || b == nil {
+		return nil, "", err
+	}
+	l := &Lease{}
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, "", err
+	}
+	return l, version, nil
+}
+
+func (c bucketLeaseClient) delete(ctx context.Context, key string) error {
+	err := c.bucket.Delete(ctx, c.name(key))
+	if c.bucket.IsObjNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+func (c bucketLeaseClient) name(key string) string {
+	return path.Join(c.prefix, key)
+}
@@ -0,0 +1,147 @@
+package positions
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/storage/bucket"
+)
+
+// inMemObjects implements conditional writes on top of an in-memory bucket,
+// using a counter per object as version.
+type inMemObjects struct {
+	bucket objstore.Bucket
+
+	mtx      sync.Mutex
+	versions map[string]int
+}
+
+func (o *inMemObjects) get(ctx context.Context, name string) ([]byte, string, error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	r, err := o.bucket.Get(ctx, name)
+	if o.bucket.IsObjNotFoundErr(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(r); err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), strconv.Itoa(o.versions[name]), nil
+}
+
+func (o *inMemObjects) put(ctx context.Context, name string, b []byte, version string) error {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	exists, err := o.bucket.Exists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if (version == "" && exists) || (version != "" && version != strconv.Itoa(o.versions[name])) {
+		return errVersionConflict
+	}
+	o.versions[name]++
+	return o.bucket.Upload(ctx, name, bytes.NewReader(b))
+}
+
+func newTestBucketStores(bkt objstore.Bucket, instanceIDs ...string) []*leaseStore {
+	objects := &inMemObjects{bucket: bkt, versions: map[string]int{}}
+	stores := make([]*leaseStore, 0, len(instanceIDs))
+	for _, id := range instanceIDs {
+		client := bucketLeaseClient{bucket: bkt, objects: objects, prefix: "positions/"}
+		stores = append(stores, newLeaseStore(client, id, time.Minute, nil, log.NewNopLogger()))
+	}
+	return stores
+}
+
+func TestBucketStore_Lease(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	stores := newTestBucketStores(bkt, "a", "b")
+	a, b := stores[0], stores[1]
+
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	b.now = func() time.Time { return now }
+
+	_, owned, err := a.own("cursor-cloudflare-zone-123")
+	require.NoError(t, err)
+	require.True(t, owned)
+	_, err = a.write(map[string]string{"cursor-cloudflare-zone-123": "1700000000"})
+	require.NoError(t, err)
+
+	exists, err := bkt.Exists(context.Background(), "positions/cursor-cloudflare-zone-123")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	positions, err := b.read()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"cursor-cloudflare-zone-123": "1700000000"}, positions)
+	_, owned, err = b.own("cursor-cloudflare-zone-123")
+	require.NoError(t, err)
+	require.False(t, owned)
+
+	// Once the lease of a expires b takes it over along with its position.
+	now = now.Add(2 * time.Minute)
+	pos, owned, err := b.own("cursor-cloudflare-zone-123")
+	require.NoError(t, err)
+	require.True(t, owned)
+	require.Equal(t, "1700000000", pos)
+
+	adopted, err := a.write(map[string]string{"cursor-cloudflare-zone-123": "1700000100"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"cursor-cloudflare-zone-123": "1700000000"}, adopted)
+
+	// The lease is released once b stops tracking the path.
+	_, err = b.write(map[string]string{})
+	require.NoError(t, err)
+	keys, err := b.client.list(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestBucketStore_ConcurrentOwn(t *testing.T) {
+	stores := newTestBucketStores(objstore.NewInMemBucket(), "a", "b", "c")
+
+	var (
+		wg    sync.WaitGroup
+		mtx   sync.Mutex
+		owned []string
+	)
+	for _, s := range stores {
+		wg.Add(1)
+		go func(s *leaseStore) {
+			defer wg.Done()
+			_, ok, err := s.own("/var/log/app.log")
+			require.NoError(t, err)
+			if ok {
+				mtx.Lock()
+				owned = append(owned, s.instanceID)
+				mtx.Unlock()
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	// Only one instance acquires the lease.
+	require.Len(t, owned, 1)
+}
+
+func TestNewConditionalObjects_UnsupportedBackend(t *testing.T) {
+	_, err := newConditionalObjects(bucket.Config{Backend: bucket.Filesystem}, log.NewNopLogger())
+	require.Error(t, err)
+}
@@ -0,0 +1,201 @@
+package positions
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"cloud.google.com/go/storage"
+	"github.com/go-kit/log"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"google.golang.org/api/googleapi"
+
+	"github.com/grafana/loki/v3/pkg/storage/bucket"
+	"github.com/grafana/loki/v3/pkg/storage/bucket/gcs"
+	"github.com/grafana/loki/v3/pkg/storage/bucket/s3"
+)
+
+// errVersionConflict is returned by conditional writes when the object was
+// modified since it was read.
+var errVersionConflict = errors.New("object was concurrently modified")
+
+// conditionalObjects reads and writes objects with optimistic concurrency
+// control, which the objstore buckets do not expose.
+type conditionalObjects interface {
+	// get returns the content of an object and its version, or nil if it
+	// does not exist.
+	get(ctx context.Context, name string) ([]byte, string, error)
+	// put writes an object if its version is still version, or if it does
+	// not exist when version is empty. errVersionConflict is returned
+	// otherwise.
+	put(ctx context.Context, name string, b []byte, version string) error
+}
+
+// newConditionalObjects returns the conditional objects of the configured
+// bucket. Only GCS and S3 support conditional writes.
+func newConditionalObjects(cfg bucket.Config, logger log.Logger) (conditionalObjects, error) {
+	switch cfg.Backend {
+	case bucket.GCS:
+		bkt, err := gcs.NewBucketClient(context.Background(), cfg.GCS, "positions", logger)
+		if err != nil {
+			return nil, err
+		}
+		h, ok := bkt.(interface{ Handle() *storage.BucketHandle })
+		if !ok {
+			return nil, fmt.Errorf("unexpected gcs bucket client %T", bkt)
+		}
+		return gcsObjects{bucket: h.Handle()}, nil
+	case bucket.S3:
+		return newS3Objects(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unsupported positions object storage backend %q, supported values are: %s, %s", cfg.Backend, bucket.GCS, bucket.S3)
+	}
+}
+
+// gcsObjects uses the object generations as versions.
+type gcsObjects struct {
+	bucket *storage.BucketHandle
+}
+
+func (o gcsObjects) get(ctx context.Context, name string) ([]byte, string, error) {
+	r, err := o.bucket.Object(name).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, strconv.FormatInt(r.Attrs.Generation, 10), nil
+}
+
+func (o gcsObjects) put(ctx context.Context, name string, b []byte, version string) error {
+	cond := storage.Conditions{DoesNotExist: true}
+	if version != "" {
+		generation, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return err
+		}
+		cond = storage.Conditions{GenerationMatch: generation}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := o.bucket.Object(name).If(cond).NewWriter(ctx)
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	err := w.Close()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return errVersionConflict
+	}
+	return err
+}
+
+// s3Objects uses the ETags as versions, with If-Match and If-None-Match
+// conditional writes.
+type s3Objects struct {
+	client     *minio.Client
+	bucketName string
+	sse        minio.PutObjectOptions
+}
+
+func newS3Objects(cfg s3.Config) (*s3Objects, error) {
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.FileAWSCredentials{},
+		&credentials.IAM{Client: &http.Client{Transport: http.DefaultTransport}},
+	})
+	if cfg.AccessKeyID != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey.String(), cfg.SessionToken.String())
+	}
+
+	transport := cfg.HTTP.Transport
+	if transport == nil {
+		transport = &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			IdleConnTimeout:       cfg.HTTP.IdleConnTimeout,
+			ResponseHeaderTimeout: cfg.HTTP.ResponseHeaderTimeout,
+			TLSHandshakeTimeout:   cfg.HTTP.TLSHandshakeTimeout,
+			ExpectContinueTimeout: cfg.HTTP.ExpectContinueTimeout,
+			MaxIdleConns:          cfg.HTTP.MaxIdleConns,
+			MaxIdleConnsPerHost:   cfg.HTTP.MaxIdleConnsPerHost,
+			MaxConnsPerHost:       cfg.HTTP.MaxConnsPerHost,
+			TLSClientConfig:       &tls.Config{InsecureSkipVerify: cfg.HTTP.InsecureSkipVerify}, //#nosec G402 -- User has explicitly requested to disable TLS
+		}
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:     creds,
+		Secure:    !cfg.Insecure,
+		Region:    cfg.Region,
+		Transport: transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DisableDualstack {
+		client.SetS3EnableDualstack(false)
+	}
+
+	sse, err := cfg.SSE.BuildMinioConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &s3Objects{
+		client:     client,
+		bucketName: cfg.BucketName,
+		sse:        minio.PutObjectOptions{ServerSideEncryption: sse, StorageClass: cfg.StorageClass},
+	}, nil
+}
+
+func (o *s3Objects) get(ctx context.Context, name string) ([]byte, string, error) {
+	obj, err := o.client.GetObject(ctx, o.bucketName, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+	defer obj.Close()
+
+	info, err := obj.Stat()
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, info.ETag, nil
+}
+
+func (o *s3Objects) put(ctx context.Context, name string, b []byte, version string) error {
+	opts := o.sse
+	opts.ContentType = "application/json"
+	if version == "" {
+		opts.SetMatchETagExcept("*")
+	} else {
+		opts.SetMatchETag(version)
+	}
+
+	_, err := o.client.PutObject(ctx, o.bucketName, name, bytes.NewReader(b), int64(len(b)), opts)
+	switch minio.ToErrorResponse(err).StatusCode {
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		// S3 answers 409 when a conditional write races with another one.
+		return errVersionConflict
+	}
+	return err
+}
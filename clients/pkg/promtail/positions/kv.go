@@ -0,0 +1,83 @@
+package positions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// leaseCodec encodes leases as JSON so they can be inspected with the
+// Consul or etcd tooling.
+type leaseCodec struct{}
+
+func (leaseCodec) CodecID() string {
+	return "promtailPositionLease"
+}
+
+func (leaseCodec) Decode(b []byte) (interface{}, error) {
+	l := &Lease{}
+	if err := json.Unmarshal(b, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (leaseCodec) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// kvLeaseClient stores leases in a Consul or etcd key-value store.
+type kvLeaseClient struct {
+	client kv.Client
+}
+
+func newKVStore(cfg Config, reg prometheus.Registerer, logger log.Logger) (*leaseStore, error) {
+	if cfg.KVStore.Store == "memberlist" {
+		return nil, fmt.Errorf("memberlist is not supported as a positions kv store")
+	}
+	if cfg.LeaseDuration <= 0 {
+		return nil, fmt.Errorf("positions lease duration must be greater than 0")
+	}
+	instanceID, err := leaseInstanceID(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kv.NewClient(cfg.KVStore, leaseCodec{}, kv.RegistererWithKVName(reg, "positions"), logger)
+	if err != nil {
+		return nil, err
+	}
+	return newLeaseStore(kvLeaseClient{client: client}, instanceID, cfg.LeaseDuration, reg, logger), nil
+}
+
+func (c kvLeaseClient) list(ctx context.Context) ([]string, error) {
+	return c.client.List(ctx, "")
+}
+
+func (c kvLeaseClient) get(ctx context.Context, key string) (*Lease, error) {
+	v, err := c.client.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	l, _ := v.(*Lease)
+	return l, nil
+}
+
+func (c kvLeaseClient) cas(ctx context.Context, key string, f func(*Lease) (*Lease, bool)) error {
+	return c.client.CAS(ctx, key, func(in interface{}) (interface{}, bool, error) {
+		l, _ := in.(*Lease)
+		out, ok := f(l)
+		if !ok {
+			return nil, false, nil
+		}
+		return out, true, nil
+	})
+}
+
+func (c kvLeaseClient) delete(ctx context.Context, key string) error {
+	return c.client.Delete(ctx, key)
+}
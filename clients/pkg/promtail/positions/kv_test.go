@@ -0,0 +1,144 @@
+package positions
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKVStores(t *testing.T, instanceIDs ...string) []*leaseStore {
+	t.Helper()
+
+	client, closer := consul.NewInMemoryClient(leaseCodec{}, log.NewNopLogger(), nil)
+	t.Cleanup(func() { _ = closer.Close() })
+
+	stores := make([]*leaseStore, 0, len(instanceIDs))
+	for _, id := range instanceIDs {
+		stores = append(stores, newLeaseStore(kvLeaseClient{client: client}, id, time.Minute, nil, log.NewNopLogger()))
+	}
+	return stores
+}
+
+func TestKVStore_ReadWrite(t *testing.T) {
+	stores := newTestKVStores(t, "a")
+	s := stores[0]
+
+	for _, path := range []string{"/var/log/app.log", "cursor-cloudflare-zone-123"} {
+		pos, owned, err := s.own(path)
+		require.NoError(t, err)
+		require.True(t, owned)
+		require.Empty(t, pos)
+	}
+
+	adopted, err := s.write(map[string]string{
+		"/var/log/app.log":           "100",
+		"cursor-cloudflare-zone-123": "1700000000",
+	})
+	require.NoError(t, err)
+	require.Empty(t, adopted)
+
+	// Every path is leased under its own key.
+	keys, err := s.client.list(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"%2Fvar%2Flog%2Fapp.log", "cursor-cloudflare-zone-123"}, keys)
+
+	positions, err := s.read()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"/var/log/app.log":           "100",
+		"cursor-cloudflare-zone-123": "1700000000",
+	}, positions)
+
+	// The leases of the paths no longer tracked are released.
+	_, err = s.write(map[string]string{"/var/log/app.log": "200"})
+	require.NoError(t, err)
+
+	positions, err = s.read()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"/var/log/app.log": "200"}, positions)
+}
+
+func TestKVStore_Lease(t *testing.T) {
+	stores := newTestKVStores(t, "a", "b")
+	a, b := stores[0], stores[1]
+
+	now := time.Now()
+	a.now = func() time.Time { return now }
+	b.now = func() time.Time { return now }
+
+	_, owned, err := a.own("/var/log/app.log")
+	require.NoError(t, err)
+	require.True(t, owned)
+	_, err = a.write(map[string]string{"/var/log/app.log": "100"})
+	require.NoError(t, err)
+
+	// b reads the position of the path leased by a but does not own it.
+	positions, err := b.read()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"/var/log/app.log": "100"}, positions)
+	_, owned, err = b.own("/var/log/app.log")
+	require.NoError(t, err)
+	require.False(t, owned)
+
+	// Positions of paths which are not owned are not written.
+	_, err = b.write(map[string]string{"/var/log/app.log": "50"})
+	require.NoError(t, err)
+	positions, err = a.read()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"/var/log/app.log": "100"}, positions)
+
+	// Once the lease of a expires b takes it over along with its position.
+	now = now.Add(2 * time.Minute)
+	pos, owned, err := b.own("/var/log/app.log")
+	require.NoError(t, err)
+	require.True(t, owned)
+	require.Equal(t, "100", pos)
+
+	// a loses the lease at its next sync and adopts the position of b.
+	adopted, err := a.write(map[string]string{"/var/log/app.log": "120"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"/var/log/app.log": "100"}, adopted)
+	require.Equal(t, 1.0, testutil.ToFloat64(a.leaseConflicts))
+
+	_, owned, err = a.own("/var/log/app.log")
+	require.NoError(t, err)
+	require.False(t, owned)
+}
+
+func TestKVStore_ConcurrentOwn(t *testing.T) {
+	stores := newTestKVStores(t, "a", "b", "c")
+
+	var (
+		wg    sync.WaitGroup
+		mtx   sync.Mutex
+		owned []string
+	)
+	for _, s := range stores {
+		wg.Add(1)
+		go func(s *leaseStore) {
+			defer wg.Done()
+			_, ok, err := s.own("/var/log/app.log")
+			require.NoError(t, err)
+			if ok {
+				mtx.Lock()
+				owned = append(owned, s.instanceID)
+				mtx.Unlock()
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	// Only one instance acquires the lease.
+	require.Len(t, owned, 1)
+}
+
+func TestNew_InvalidBackend(t *testing.T) {
+	_, err := New(log.NewNopLogger(), Config{Backend: "s3"})
+	require.Error(t, err)
+}
@@ -0,0 +1,281 @@
+package positions
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const leaseStoreTimeout = 10 * time.Second
+
+// Lease is the value stored for every position key in the shared backends.
+// Only the instance owning the lease may update the position until it
+// expires.
+type Lease struct {
+	Position string    `json:"position"`
+	Owner    string    `json:"owner"`
+	Expires  time.Time `json:"expires"`
+}
+
+// acquirableBy returns whether the lease can be taken or renewed by instanceID.
+func (l *Lease) acquirableBy(instanceID string, now time.Time) bool {
+	return l.Owner == instanceID || now.After(l.Expires)
+}
+
+// leaseClient reads and writes the leases of the position keys in a backend
+// shared by several instances. Keys are escaped paths.
+type leaseClient interface {
+	// list returns the keys of all the leases.
+	list(ctx context.Context) ([]string, error)
+	// get returns the lease stored at key, or nil if there is none.
+	get(ctx context.Context, key string) (*Lease, error)
+	// cas atomically replaces the lease stored at key, nil if there is
+	// none, with the one returned by f. Nothing is written if f returns
+	// false. f may be called several times if the lease is concurrently
+	// modified.
+	cas(ctx context.Context, key string, f func(*Lease) (*Lease, bool)) error
+	// delete deletes the lease stored at key.
+	delete(ctx context.Context, key string) error
+}
+
+// ownedLease is a lease held by this instance.
+type ownedLease struct {
+	position string
+	expires  time.Time
+}
+
+// leaseStore persists positions to a backend shared by several Promtail
+// instances. Every position key is leased with a compare-and-swap to the
+// instance reading it, which renews the lease along with the position at
+// each sync. A path leased by another instance is not owned and must not be
+// read until the lease expires, at which point its position is adopted by
+// the instance taking it over.
+type leaseStore struct {
+	client        leaseClient
+	instanceID    string
+	leaseDuration time.Duration
+	logger        log.Logger
+	now           func() time.Time
+
+	mtx sync.Mutex
+	// owned are the leases held by this instance.
+	owned map[string]ownedLease
+	// leased are the last known leases of the paths owned by other instances.
+	leased map[string]*Lease
+
+	leaseConflicts prometheus.Counter
+}
+
+// leaseInstanceID returns the configured instance id, defaulting to the hostname.
+func leaseInstanceID(cfg Config) (string, error) {
+	if cfg.InstanceID != "" {
+		return cfg.InstanceID, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine positions instance id: %w", err)
+	}
+	return hostname, nil
+}
+
+func newLeaseStore(client leaseClient, instanceID string, leaseDuration time.Duration, reg prometheus.Registerer, logger log.Logger) *leaseStore {
+	return &leaseStore{
+		client:        client,
+		instanceID:    instanceID,
+		leaseDuration: leaseDuration,
+		logger:        logger,
+		now:           time.Now,
+		owned:         map[string]ownedLease{},
+		leased:        map[string]*Lease{},
+		leaseConflicts: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "promtail",
+			Name:      "positions_lease_conflicts_total",
+			Help:      "Number of paths whose lease was lost to another instance.",
+		}),
+	}
+}
+
+// read returns the positions of all the leases. The live leases of this
+// instance are owned again, the paths leased by other instances are not
+// read until their lease expires.
+func (s *leaseStore) read() (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), leaseStoreTimeout)
+	defer cancel()
+
+	keys, err := s.client.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	positions := make(map[string]string, len(keys))
+	for _, key := range keys {
+		l, err := s.client.get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if l == nil {
+			continue
+		}
+		path, err := url.PathUnescape(key)
+		if err != nil {
+			level.Warn(s.logger).Log("msg", "ignoring invalid positions key", "key", key, "error", err)
+			continue
+		}
+		positions[path] = l.Position
+		switch {
+		case now.After(l.Expires):
+		case l.Owner == s.instanceID:
+			s.owned[path] = ownedLease{position: l.Position, expires: l.Expires}
+		default:
+			s.leased[path] = l
+		}
+	}
+	return positions, nil
+}
+
+// own returns whether this instance owns path, acquiring its lease if it is
+// not held by another instance. The position of the previous owner is
+// returned when the lease is taken over, it must replace the local one.
+func (s *leaseStore) own(path string) (string, bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	if l, ok := s.owned[path]; ok && now.Before(l.expires) {
+		return "", true, nil
+	}
+	if l, ok := s.leased[path]; ok && !now.After(l.Expires) {
+		return "", false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseStoreTimeout)
+	defer cancel()
+
+	var (
+		current *Lease
+		adopted string
+	)
+	err := s.client.cas(ctx, url.PathEscape(path), func(in *Lease) (*Lease, bool) {
+		now := s.now()
+		if in != nil && !in.acquirableBy(s.instanceID, now) {
+			current = in
+			return nil, false
+		}
+		current = &Lease{Owner: s.instanceID, Expires: now.Add(s.leaseDuration)}
+		adopted = ""
+		if in != nil {
+			// Keep the position in the lease until it is renewed, in case
+			// this instance stops before its first sync.
+			current.Position = in.Position
+			if in.Owner != s.instanceID {
+				adopted = in.Position
+			}
+		}
+		return current, true
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	if current.Owner != s.instanceID {
+		s.leased[path] = current
+		delete(s.owned, path)
+		return "", false, nil
+	}
+	if adopted != "" {
+		level.Info(s.logger).Log("msg", "took over position of expired lease", "path", path)
+	}
+	delete(s.leased, path)
+	s.owned[path] = ownedLease{position: current.Position, expires: current.Expires}
+	return adopted, true, nil
+}
+
+// write renews the leases of the given positions owned by this instance and
+// releases the owned paths which are no longer tracked. It returns the
+// positions of the paths whose lease was lost to another instance, which
+// replace the local ones.
+func (s *leaseStore) write(positions map[string]string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), leaseStoreTimeout)
+	defer cancel()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var adopted map[string]string
+	for path, owned := range s.owned {
+		pos, ok := positions[path]
+		if !ok {
+			if err := s.release(ctx, path); err != nil {
+				return nil, err
+			}
+			delete(s.owned, path)
+			continue
+		}
+		// Leases are renewed when the position moved or once half of
+		// their duration elapsed.
+		if pos == owned.position && s.now().Before(owned.expires.Add(-s.leaseDuration/2)) {
+			continue
+		}
+
+		lease, err := s.renew(ctx, path, pos)
+		if err != nil {
+			return nil, err
+		}
+		if lease.Owner == s.instanceID {
+			s.owned[path] = ownedLease{position: lease.Position, expires: lease.Expires}
+			continue
+		}
+
+		level.Warn(s.logger).Log("msg", "lost position lease to another instance", "path", path, "owner", lease.Owner)
+		s.leaseConflicts.Inc()
+		delete(s.owned, path)
+		s.leased[path] = lease
+		if adopted == nil {
+			adopted = map[string]string{}
+		}
+		adopted[path] = lease.Position
+	}
+	return adopted, nil
+}
+
+// renew extends the lease of this instance on path and returns the lease
+// stored after the update, which is owned by another instance if it was taken
+// over after expiring. A deleted lease is acquired again.
+func (s *leaseStore) renew(ctx context.Context, path, pos string) (*Lease, error) {
+	var current *Lease
+	err := s.client.cas(ctx, url.PathEscape(path), func(in *Lease) (*Lease, bool) {
+		if in != nil && in.Owner != s.instanceID {
+			current = in
+			return nil, false
+		}
+		current = &Lease{Position: pos, Owner: s.instanceID, Expires: s.now().Add(s.leaseDuration)}
+		return current, true
+	})
+	return current, err
+}
+
+// release deletes the lease on path unless it has been taken over by another
+// instance in the meantime.
+func (s *leaseStore) release(ctx context.Context, path string) error {
+	key := url.PathEscape(path)
+	l, err := s.client.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if l == nil || l.Owner != s.instanceID {
+		return nil
+	}
+	return s.client.delete(ctx, key)
+}
@@ -12,10 +12,21 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/kv"
+	"github.com/prometheus/client_golang/prometheus"
 	yaml "gopkg.in/yaml.v2"
 )
 
 const (
+	// BackendFile persists positions to a local YAML file.
+	BackendFile = "file"
+	// BackendKV persists positions to a shared key-value store, leasing each
+	// key to a single instance.
+	BackendKV = "kv"
+	// BackendObjectStorage persists positions to a shared bucket, leasing
+	// each key to a single instance.
+	BackendObjectStorage = "object_storage"
+
 	positionFileMode = 0600
 	cursorKeyPrefix  = "cursor-"
 	journalKeyPrefix = "journal-"
@@ -27,6 +38,14 @@ type Config struct {
 	PositionsFile     string        `mapstructure:"filename" yaml:"filename"`
 	IgnoreInvalidYaml bool          `mapstructure:"ignore_invalid_yaml" yaml:"ignore_invalid_yaml"`
 	ReadOnly          bool          `mapstructure:"-" yaml:"-"`
+
+	// Backend selects where positions are persisted, either a local file, a
+	// shared key-value store or a shared bucket.
+	Backend       string              `mapstructure:"backend" yaml:"backend"`
+	KVStore       kv.Config           `mapstructure:"-" yaml:"kvstore"`
+	ObjectStorage ObjectStorageConfig `mapstructure:"-" yaml:"object_storage"`
+	InstanceID    string              `mapstructure:"instance_id" yaml:"instance_id"`
+	LeaseDuration time.Duration       `mapstructure:"lease_duration" yaml:"lease_duration"`
 }
 
 // RegisterFlags with prefix registers flags where every name is prefixed by
@@ -35,6 +54,11 @@ func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.DurationVar(&cfg.SyncPeriod, prefix+"positions.sync-period", 10*time.Second, "Period with this to sync the position file.")
 	f.StringVar(&cfg.PositionsFile, prefix+"positions.file", "/var/log/positions.yaml", "Location to read/write positions from.")
 	f.BoolVar(&cfg.IgnoreInvalidYaml, prefix+"positions.ignore-invalid-yaml", false, "whether to ignore & later overwrite positions files that are corrupted")
+	f.StringVar(&cfg.Backend, prefix+"positions.backend", BackendFile, "Where to persist positions. Supported values are: file, kv, object_storage.")
+	f.StringVar(&cfg.InstanceID, prefix+"positions.instance-id", "", "Identifier used to lease positions in the kv and object_storage backends. Defaults to the hostname.")
+	f.DurationVar(&cfg.LeaseDuration, prefix+"positions.lease-duration", time.Minute, "How long the paths read by an instance stay leased to it in the kv and object_storage backends after its last sync.")
+	cfg.KVStore.RegisterFlagsWithPrefix(prefix+"positions.", "positions/", f)
+	cfg.ObjectStorage.RegisterFlagsWithPrefix(prefix+"positions.object-storage.", f)
 }
 
 // RegisterFlags register flags.
@@ -46,6 +70,7 @@ func (cfg *Config) RegisterFlags(flags *flag.FlagSet) {
 type positions struct {
 	logger    log.Logger
 	cfg       Config
+	store     store
 	mtx       sync.Mutex
 	positions map[string]string
 	quit      chan struct{}
@@ -74,15 +99,57 @@ type Positions interface {
 	Put(path string, pos int64)
 	// Remove removes the position tracking for a filepath
 	Remove(path string)
+	// Owned returns whether this instance may read a path. With the kv and
+	// object_storage backends the path is leased to this instance first,
+	// adopting the position of its previous owner, and is not owned while
+	// another instance holds its lease.
+	Owned(path string) bool
 	// SyncPeriod returns how often the positions file gets resynced
 	SyncPeriod() time.Duration
 	// Stop the Position tracker.
 	Stop()
 }
 
+// store persists positions outside of the process.
+type store interface {
+	// read returns all persisted positions.
+	read() (map[string]string, error)
+	// write persists positions and returns the positions which are owned by
+	// another instance and should replace the local ones.
+	write(positions map[string]string) (map[string]string, error)
+	// own returns whether this instance owns path, acquiring it if possible.
+	// The returned position, if any, was persisted by the previous owner and
+	// should replace the local one.
+	own(path string) (string, bool, error)
+}
+
 // New makes a new Positions.
 func New(logger log.Logger, cfg Config) (Positions, error) {
-	positionData, err := readPositionsFile(cfg, logger)
+	return NewWithRegisterer(logger, cfg, nil)
+}
+
+// NewWithRegisterer makes a new Positions registering the metrics of the
+// backend store, if any, with reg.
+func NewWithRegisterer(logger log.Logger, cfg Config, reg prometheus.Registerer) (Positions, error) {
+	var (
+		s   store
+		err error
+	)
+	switch cfg.Backend {
+	case "", BackendFile:
+		s = &fileStore{cfg: cfg, logger: logger}
+	case BackendKV:
+		s, err = newKVStore(cfg, reg, logger)
+	case BackendObjectStorage:
+		s, err = newBucketStore(cfg, reg, logger)
+	default:
+		err = fmt.Errorf("invalid positions backend %q, supported values are: %s, %s, %s", cfg.Backend, BackendFile, BackendKV, BackendObjectStorage)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	positionData, err := s.read()
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +157,7 @@ func New(logger log.Logger, cfg Config) (Positions, error) {
 	p := &positions{
 		logger:    logger,
 		cfg:       cfg,
+		store:     s,
 		positions: positionData,
 		quit:      make(chan struct{}),
 		done:      make(chan struct{}),
@@ -140,6 +208,23 @@ func (p *positions) remove(path string) {
 	delete(p.positions, path)
 }
 
+func (p *positions) Owned(path string) bool {
+	if p.cfg.ReadOnly {
+		return true
+	}
+	pos, owned, err := p.store.own(path)
+	if err != nil {
+		level.Error(p.logger).Log("msg", "error acquiring position", "backend", p.cfg.Backend, "path", path, "error", err)
+		return false
+	}
+	if pos != "" {
+		p.mtx.Lock()
+		p.positions[path] = pos
+		p.mtx.Unlock()
+	}
+	return owned
+}
+
 func (p *positions) SyncPeriod() time.Duration {
 	return p.cfg.SyncPeriod
 }
@@ -175,8 +260,18 @@ func (p *positions) save() {
 	}
 	p.mtx.Unlock()
 
-	if err := writePositionFile(p.cfg.PositionsFile, positions); err != nil {
-		level.Error(p.logger).Log("msg", "error writing positions file", "error", err)
+	adopted, err := p.store.write(positions)
+	if err != nil {
+		level.Error(p.logger).Log("msg", "error writing positions", "backend", p.cfg.Backend, "error", err)
+		return
+	}
+
+	// The positions of the paths lost to another instance are replaced by
+	// theirs, so that they are not read again from a stale position.
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for k, v := range adopted {
+		p.positions[k] = v
 	}
 }
 
@@ -213,6 +308,25 @@ func (p *positions) cleanup() {
 	}
 }
 
+// fileStore persists positions to a local YAML file.
+type fileStore struct {
+	cfg    Config
+	logger log.Logger
+}
+
+func (s *fileStore) read() (map[string]string, error) {
+	return readPositionsFile(s.cfg, s.logger)
+}
+
+func (s *fileStore) write(positions map[string]string) (map[string]string, error) {
+	return nil, writePositionFile(s.cfg.PositionsFile, positions)
+}
+
+// own always succeeds as the positions file is not shared.
+func (s *fileStore) own(string) (string, bool, error) {
+	return "", true, nil
+}
+
 func readPositionsFile(cfg Config, logger log.Logger) (map[string]string, error) {
 	cleanfn := filepath.Clean(cfg.PositionsFile)
 	buf, err := os.ReadFile(cleanfn)
@@ -29,23 +29,26 @@ import (
 
 	"github.com/grafana/loki/v3/clients/pkg/logentry/stages"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/discovery/consulagent"
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
 )
 
 // Config describes a job to scrape.
 type Config struct {
-	JobName              string                      `mapstructure:"job_name,omitempty" yaml:"job_name,omitempty"`
-	PipelineStages       stages.PipelineStages       `mapstructure:"pipeline_stages,omitempty" yaml:"pipeline_stages,omitempty"`
-	JournalConfig        *JournalTargetConfig        `mapstructure:"journal,omitempty" yaml:"journal,omitempty"`
-	SyslogConfig         *SyslogTargetConfig         `mapstructure:"syslog,omitempty" yaml:"syslog,omitempty"`
-	GcplogConfig         *GcplogTargetConfig         `mapstructure:"gcplog,omitempty" yaml:"gcplog,omitempty"`
-	PushConfig           *PushTargetConfig           `mapstructure:"loki_push_api,omitempty" yaml:"loki_push_api,omitempty"`
-	WindowsConfig        *WindowsEventsTargetConfig  `mapstructure:"windows_events,omitempty" yaml:"windows_events,omitempty"`
-	KafkaConfig          *KafkaTargetConfig          `mapstructure:"kafka,omitempty" yaml:"kafka,omitempty"`
-	AzureEventHubsConfig *AzureEventHubsTargetConfig `mapstructure:"azure_event_hubs,omitempty" yaml:"azure_event_hubs,omitempty"`
-	GelfConfig           *GelfTargetConfig           `mapstructure:"gelf,omitempty" yaml:"gelf,omitempty"`
-	CloudflareConfig     *CloudflareConfig           `mapstructure:"cloudflare,omitempty" yaml:"cloudflare,omitempty"`
-	HerokuDrainConfig    *HerokuDrainTargetConfig    `mapstructure:"heroku_drain,omitempty" yaml:"heroku_drain,omitempty"`
-	RelabelConfigs       []*relabel.Config           `mapstructure:"relabel_configs,omitempty" yaml:"relabel_configs,omitempty"`
+	JobName                string                        `mapstructure:"job_name,omitempty" yaml:"job_name,omitempty"`
+	PipelineStages         stages.PipelineStages         `mapstructure:"pipeline_stages,omitempty" yaml:"pipeline_stages,omitempty"`
+	JournalConfig          *JournalTargetConfig          `mapstructure:"journal,omitempty" yaml:"journal,omitempty"`
+	SyslogConfig           *SyslogTargetConfig           `mapstructure:"syslog,omitempty" yaml:"syslog,omitempty"`
+	GcplogConfig           *GcplogTargetConfig           `mapstructure:"gcplog,omitempty" yaml:"gcplog,omitempty"`
+	PushConfig             *PushTargetConfig             `mapstructure:"loki_push_api,omitempty" yaml:"loki_push_api,omitempty"`
+	WindowsConfig          *WindowsEventsTargetConfig    `mapstructure:"windows_events,omitempty" yaml:"windows_events,omitempty"`
+	KafkaConfig            *KafkaTargetConfig            `mapstructure:"kafka,omitempty" yaml:"kafka,omitempty"`
+	AzureEventHubsConfig   *AzureEventHubsTargetConfig   `mapstructure:"azure_event_hubs,omitempty" yaml:"azure_event_hubs,omitempty"`
+	GelfConfig             *GelfTargetConfig             `mapstructure:"gelf,omitempty" yaml:"gelf,omitempty"`
+	CloudflareConfig       *CloudflareConfig             `mapstructure:"cloudflare,omitempty" yaml:"cloudflare,omitempty"`
+	HerokuDrainConfig      *HerokuDrainTargetConfig      `mapstructure:"heroku_drain,omitempty" yaml:"heroku_drain,omitempty"`
+	KubernetesEventsConfig *KubernetesEventsTargetConfig `mapstructure:"kubernetes_events,omitempty" yaml:"kubernetes_events,omitempty"`
+	OTLPConfig             *OTLPTargetConfig             `mapstructure:"otlp,omitempty" yaml:"otlp,omitempty"`
+	RelabelConfigs         []*relabel.Config             `mapstructure:"relabel_configs,omitempty" yaml:"relabel_configs,omitempty"`
 	// List of Docker service discovery configurations.
 	DockerSDConfigs        []*moby.DockerSDConfig `mapstructure:"docker_sd_configs,omitempty" yaml:"docker_sd_configs,omitempty"`
 	ServiceDiscoveryConfig ServiceDiscoveryConfig `mapstructure:",squash" yaml:",inline"`
@@ -446,6 +449,29 @@ type HerokuDrainTargetConfig struct {
 	UseIncomingTimestamp bool `yaml:"use_incoming_timestamp"`
 }
 
+// KubernetesEventsTargetConfig describes a scrape config that watches the
+// Kubernetes Events API.
+type KubernetesEventsTargetConfig struct {
+	// KubeConfig is the path to a kubeconfig file. If empty, the in-cluster
+	// configuration is used.
+	KubeConfig string `yaml:"kubeconfig_path"`
+
+	// Namespaces to watch events in. Events of all namespaces are watched if empty.
+	Namespaces []string `yaml:"namespaces"`
+
+	// FieldSelector restricts the watched events by their fields, e.g. `type=Warning`.
+	FieldSelector string `yaml:"field_selector"`
+
+	// LabelSelector restricts the watched events by their labels.
+	LabelSelector string `yaml:"label_selector"`
+
+	// Format of the log lines, either `logfmt` or `json`. Defaults to `logfmt`.
+	Format string `yaml:"format"`
+
+	// Labels optionally holds labels to associate with each event.
+	Labels model.LabelSet `yaml:"labels"`
+}
+
 // PushTargetConfig describes a scrape config that listens for Loki push messages.
 type PushTargetConfig struct {
 	// Server is the weaveworks server config for listening connections
@@ -458,6 +484,27 @@ type PushTargetConfig struct {
 	KeepTimestamp bool `yaml:"use_incoming_timestamp"`
 }
 
+// OTLPTargetConfig describes a scrape config that receives OTLP logs over HTTP
+// and gRPC.
+type OTLPTargetConfig struct {
+	// Server is the weaveworks server config for listening connections
+	Server server.Config `yaml:"server"`
+
+	// Labels optionally holds labels to associate with each received log record.
+	Labels model.LabelSet `yaml:"labels"`
+
+	// If promtail should maintain the incoming log timestamp or replace it with the current time.
+	KeepTimestamp bool `yaml:"use_incoming_timestamp"`
+
+	// OTLP configures which attributes are stored as labels, structured metadata
+	// or dropped, the same way the Loki `otlp_config` limit does.
+	OTLP push.OTLPConfig `yaml:"otlp_config"`
+
+	// DefaultResourceAttributesAsIndexLabels overrides the resource attributes
+	// stored as labels unless `otlp_config` ignores defaults.
+	DefaultResourceAttributesAsIndexLabels []string `yaml:"default_resource_attributes_as_index_labels"`
+}
+
 // DefaultScrapeConfig is the default Config.
 var DefaultScrapeConfig = Config{
 	PipelineStages: stages.PipelineStages{},
@@ -71,6 +71,9 @@ func NewTarget(
 	if err != nil {
 		return nil, err
 	}
+	// Lease the zone before reading its position, which is replaced by the
+	// one of the previous owner when the lease is taken over.
+	owned := position.Owned(positions.CursorKey(config.ZoneID))
 	pos, err := position.Get(positions.CursorKey(config.ZoneID))
 	if err != nil {
 		return nil, err
@@ -79,6 +82,9 @@ func NewTarget(
 	if pos != 0 {
 		to = time.Unix(0, pos)
 	}
+	if owned {
+		position.Put(positions.CursorKey(config.ZoneID), to.UnixNano())
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	t := &Target{
 		logger:    logger,
@@ -106,6 +112,19 @@ func (t *Target) start() {
 			t.running.Store(false)
 		}()
 		for t.ctx.Err() == nil {
+			// Another instance sharing the positions is pulling the zone.
+			if !t.positions.Owned(positions.CursorKey(t.config.ZoneID)) {
+				t.waitForLease()
+				continue
+			}
+			// Resume from the stored position, which is the one of the
+			// previous owner if the zone was just taken over.
+			if pos, err := t.positions.Get(positions.CursorKey(t.config.ZoneID)); err != nil {
+				level.Warn(t.logger).Log("msg", "failed to read zone position", "zone_id", t.config.ZoneID, "err", err)
+			} else if pos != 0 {
+				t.to = time.Unix(0, pos)
+			}
+
 			end := t.to
 			maxEnd := time.Now().Add(-minDelay)
 			if end.After(maxEnd) {
@@ -142,6 +161,16 @@ func (t *Target) start() {
 	}()
 }
 
+// waitForLease waits for the next positions sync while the zone is leased by
+// another instance.
+func (t *Target) waitForLease() {
+	level.Debug(t.logger).Log("msg", "zone is leased by another instance, waiting", "zone_id", t.config.ZoneID)
+	select {
+	case <-time.After(t.positions.SyncPeriod()):
+	case <-t.ctx.Done():
+	}
+}
+
 // pull pulls logs from cloudflare for a given time range.
 // It will retry on errors.
 func (t *Target) pull(ctx context.Context, start, end time.Time) error {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
@@ -101,6 +102,64 @@ func Test_CloudflareTarget(t *testing.T) {
 	require.Greater(t, newPos, end.UnixNano())
 }
 
+func Test_CloudflareTargetLeased(t *testing.T) {
+	var (
+		logger = log.NewNopLogger()
+		cfg    = &scrapeconfig.CloudflareConfig{
+			APIToken:  "foo",
+			ZoneID:    "leased",
+			Labels:    model.LabelSet{"job": "cloudflare"},
+			PullRange: model.Duration(time.Minute),
+			Workers:   1,
+		}
+		end      = time.Unix(0, time.Hour.Nanoseconds())
+		client   = fake.New(func() {})
+		cfClient = newFakeCloudflareClient()
+	)
+	newPositions := func(instanceID string) positions.Positions {
+		ps, err := positions.New(logger, positions.Config{
+			SyncPeriod:    50 * time.Millisecond,
+			Backend:       positions.BackendKV,
+			KVStore:       kv.Config{Store: "inmemory"},
+			InstanceID:    instanceID,
+			LeaseDuration: 500 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		return ps
+	}
+
+	// a pulled the zone up to end and leases it.
+	a := newPositions("a")
+	require.True(t, a.Owned(positions.CursorKey(cfg.ZoneID)))
+	a.Put(positions.CursorKey(cfg.ZoneID), end.UnixNano())
+	a.Stop()
+
+	cfClient.On("LogpullReceived", mock.Anything, mock.Anything, mock.Anything).Return(&fakeLogIterator{}, nil)
+	getClient = func(apiKey, zoneID string, fields []string) (Client, error) {
+		return cfClient, nil
+	}
+
+	b := newPositions("b")
+	defer b.Stop()
+	require.False(t, b.Owned(positions.CursorKey(cfg.ZoneID)))
+
+	ta, err := NewTarget(NewMetrics(prometheus.NewRegistry()), logger, client, b, cfg)
+	require.NoError(t, err)
+	defer ta.Stop()
+
+	// b does not pull the zone until the lease of a expires, then resumes
+	// from its position.
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, 0, cfClient.CallCount())
+
+	require.Eventually(t, func() bool {
+		return cfClient.CallCount() > 0
+	}, 5*time.Second, 50*time.Millisecond)
+	cfClient.mu.Lock()
+	defer cfClient.mu.Unlock()
+	require.Equal(t, end.Add(-time.Minute), cfClient.Calls[0].Arguments.Get(1))
+}
+
 func Test_RetryErrorLogpullReceived(t *testing.T) {
 	var (
 		w        = log.NewSyncWriter(os.Stderr)
@@ -331,6 +331,9 @@ func (t *FileTarget) sync() error {
 	t.readersMutex.Unlock()
 	t.stopTailingAndRemovePosition(toStopTailing)
 
+	// Stop tailing any files which are now read by another instance sharing the positions
+	t.stopTailingNotOwned()
+
 	return nil
 }
 
@@ -378,6 +381,11 @@ func (t *FileTarget) startTailing(ps []string) {
 			continue
 		}
 
+		if !t.positions.Owned(p) {
+			level.Debug(t.logger).Log("msg", "not tailing file, it is leased by another instance", "filename", p)
+			continue
+		}
+
 		fi, err := os.Stat(p)
 		if err != nil {
 			level.Error(t.logger).Log("msg", "failed to tail file, stat failed", "error", err, "filename", p)
@@ -444,6 +452,25 @@ func (t *FileTarget) stopTailingAndRemovePosition(ps []string) {
 	}
 }
 
+// stopTailingNotOwned stops the tailers of the files leased by another instance, keeping their position
+// so that they are resumed from where the other instance left off once its lease expires.
+func (t *FileTarget) stopTailingNotOwned() {
+	t.readersMutex.Lock()
+	toStop := map[string]Reader{}
+	for p, reader := range t.readers {
+		if !t.positions.Owned(p) {
+			toStop[p] = reader
+		}
+	}
+	t.readersMutex.Unlock()
+
+	for p, reader := range toStop {
+		level.Info(t.logger).Log("msg", "stopping tailer, file is leased by another instance", "filename", p)
+		reader.Stop()
+		t.removeReader(p)
+	}
+}
+
 // pruneStoppedTailers removes any tailers which have stopped running from
 // the list of active tailers. This allows them to be restarted if there were errors.
 func (t *FileTarget) pruneStoppedTailers() {
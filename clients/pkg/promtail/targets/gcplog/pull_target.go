@@ -2,6 +2,7 @@ package gcplog
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/positions"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
 )
@@ -31,12 +33,15 @@ type pubsubSubscription interface {
 }
 
 // pullTarget represents the target specific to GCP project, with a pull subscription type.
-// It collects logs from GCP and push it to Loki.
+// It collects logs from GCP and push it to Loki. The publish time of the last message
+// is stored in positions, so that the subscription is only consumed by the instance
+// leasing it when the positions are shared.
 // nolint:revive
 type pullTarget struct {
 	metrics       *Metrics
 	logger        log.Logger
 	handler       api.EntryHandler
+	positions     positions.Positions
 	config        *scrapeconfig.GcplogTargetConfig
 	relabelConfig []*relabel.Config
 	jobName       string
@@ -51,6 +56,10 @@ type pullTarget struct {
 	ps   io.Closer
 	sub  pubsubSubscription
 	msgs chan *pubsub.Message
+
+	// publishTime is the publish time of the last acknowledged message, in
+	// nanoseconds, stored in positions at each sync by run.
+	publishTime int64
 }
 
 // newPullTarget returns the new instance of pullTarget for
@@ -63,6 +72,7 @@ func newPullTarget(
 	metrics *Metrics,
 	logger log.Logger,
 	handler api.EntryHandler,
+	position positions.Positions,
 	relabel []*relabel.Config,
 	jobName string,
 	config *scrapeconfig.GcplogTargetConfig,
@@ -79,6 +89,7 @@ func newPullTarget(
 		metrics:       metrics,
 		logger:        logger,
 		handler:       handler,
+		positions:     position,
 		relabelConfig: relabel,
 		config:        config,
 		jobName:       jobName,
@@ -103,10 +114,15 @@ func (t *pullTarget) run() error {
 
 	go t.consumeSubscription()
 
+	ticker := time.NewTicker(t.positions.SyncPeriod())
+	defer ticker.Stop()
 	for {
 		select {
 		case <-t.ctx.Done():
+			t.storePosition()
 			return t.ctx.Err()
+		case <-ticker.C:
+			t.storePosition()
 		case m := <-t.msgs:
 			entry, err := parseGCPLogsEntry(m.Data, t.config.Labels, nil, t.config.UseIncomingTimestamp, t.config.UseFullLine, t.relabelConfig)
 			if err != nil {
@@ -116,6 +132,9 @@ func (t *pullTarget) run() error {
 			}
 			t.handler.Chan() <- entry
 			m.Ack() // Ack only after log is sent.
+			if pt := m.PublishTime.UnixNano(); pt > t.publishTime {
+				t.publishTime = pt
+			}
 			t.metrics.gcplogEntries.WithLabelValues(t.config.ProjectID).Inc()
 		}
 	}
@@ -126,11 +145,29 @@ func (t *pullTarget) consumeSubscription() {
 	// It makesense as no more messages will be received.
 	defer t.cancel()
 
+	// Lease the subscription at the next positions sync rather than after the first message.
+	if t.positions.Owned(t.positionKey()) && t.positions.GetString(t.positionKey()) == "" {
+		t.positions.Put(t.positionKey(), time.Now().UnixNano())
+	}
+
 	for t.backoff.Ongoing() {
-		err := t.sub.Receive(t.ctx, func(ctx context.Context, m *pubsub.Message) {
+		// Another instance sharing the positions is consuming the subscription.
+		if !t.positions.Owned(t.positionKey()) {
+			level.Debug(t.logger).Log("msg", "subscription is leased by another instance, waiting", "subscription", t.config.Subscription)
+			select {
+			case <-time.After(t.positions.SyncPeriod()):
+			case <-t.ctx.Done():
+			}
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(t.ctx)
+		go t.cancelWhenLeased(ctx, cancel)
+		err := t.sub.Receive(ctx, func(ctx context.Context, m *pubsub.Message) {
 			t.msgs <- m
 			t.backoff.Reset()
 		})
+		cancel()
 		if err != nil {
 			level.Error(t.logger).Log("msg", "failed to receive pubsub messages", "error", err)
 			t.metrics.gcplogErrors.WithLabelValues(t.config.ProjectID).Inc()
@@ -140,6 +177,41 @@ func (t *pullTarget) consumeSubscription() {
 	}
 }
 
+// cancelWhenLeased cancels the receiving of messages once the subscription is
+// leased by another instance sharing the positions.
+func (t *pullTarget) cancelWhenLeased(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(t.positions.SyncPeriod())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !t.positions.Owned(t.positionKey()) {
+				level.Info(t.logger).Log("msg", "stopping to receive messages, subscription is leased by another instance", "subscription", t.config.Subscription)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// storePosition stores the publish time of the last acknowledged message in
+// positions, so that the subscription stays leased to this instance.
+func (t *pullTarget) storePosition() {
+	if t.publishTime == 0 {
+		return
+	}
+	t.positions.Put(t.positionKey(), t.publishTime)
+	t.publishTime = 0
+}
+
+// positionKey returns the positions key holding the publish time of the last
+// message received from the subscription.
+func (t *pullTarget) positionKey() string {
+	return positions.CursorKey(fmt.Sprintf("gcplog-%s-%s", t.config.ProjectID, t.config.Subscription))
+}
+
 func (t *pullTarget) Type() target.TargetType {
 	return target.GcplogTargetType
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/kv"
 	"github.com/pkg/errors"
 
 	"cloud.google.com/go/pubsub"
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/positions"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
 )
@@ -30,13 +32,18 @@ func TestPullTarget_RunStop(t *testing.T) {
 			runErr <- tc.target.run()
 		}()
 
-		tc.sub.messages <- &pubsub.Message{Data: []byte(gcpLogEntry)}
+		tc.sub.messages <- &pubsub.Message{Data: []byte(gcpLogEntry), PublishTime: time.Unix(100, 0)}
 		require.Eventually(t, func() bool {
 			return len(tc.promClient.Received()) > 0
 		}, time.Second, 50*time.Millisecond)
 
+		// The publish time of the last message is only stored at the next
+		// sync, or when stopping.
+		require.NotEqual(t, "100000000000", tc.target.positions.GetString(tc.target.positionKey()))
+
 		require.NoError(t, tc.target.Stop())
 		require.EqualError(t, <-runErr, "context canceled")
+		require.Equal(t, "100000000000", tc.target.positions.GetString(tc.target.positionKey()))
 	})
 
 	t.Run("it retries when there is an error", func(t *testing.T) {
@@ -90,6 +97,56 @@ func TestPullTarget_RunStop(t *testing.T) {
 	})
 }
 
+func TestPullTarget_Leased(t *testing.T) {
+	newPositions := func(instanceID string) positions.Positions {
+		ps, err := positions.New(log.NewNopLogger(), positions.Config{
+			SyncPeriod:    50 * time.Millisecond,
+			Backend:       positions.BackendKV,
+			KVStore:       kv.Config{Store: "inmemory"},
+			InstanceID:    instanceID,
+			LeaseDuration: 500 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		return ps
+	}
+
+	tc := testPullTarget(t)
+
+	// a consumed the subscription and leases it.
+	a := newPositions("a")
+	require.True(t, a.Owned(tc.target.positionKey()))
+	a.Put(tc.target.positionKey(), time.Now().UnixNano())
+	a.Stop()
+
+	b := newPositions("b")
+	defer b.Stop()
+	tc.target.positions = b
+
+	runErr := make(chan error)
+	go func() {
+		runErr <- tc.target.run()
+	}()
+
+	// b does not receive messages until the lease of a expires.
+	select {
+	case tc.sub.messages <- &pubsub.Message{Data: []byte(gcpLogEntry)}:
+		t.Fatal("subscription leased by another instance is consumed")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	select {
+	case tc.sub.messages <- &pubsub.Message{Data: []byte(gcpLogEntry)}:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription is not consumed once the lease expired")
+	}
+	require.Eventually(t, func() bool {
+		return len(tc.promClient.Received()) > 0
+	}, time.Second, 50*time.Millisecond)
+
+	require.NoError(t, tc.target.Stop())
+	require.EqualError(t, <-runErr, "context canceled")
+}
+
 func TestPullTarget_Type(t *testing.T) {
 	tc := testPullTarget(t)
 
@@ -124,6 +181,7 @@ func testPullTarget(t *testing.T) *testContext {
 		metrics:       NewMetrics(prometheus.NewRegistry()),
 		logger:        log.NewNopLogger(),
 		handler:       promClient,
+		positions:     newTestPositions(t),
 		relabelConfig: nil,
 		ctx:           ctx,
 		cancel:        cancel,
@@ -142,6 +200,17 @@ func testPullTarget(t *testing.T) *testContext {
 	}
 }
 
+func newTestPositions(t *testing.T) positions.Positions {
+	t.Helper()
+	ps, err := positions.New(log.NewNopLogger(), positions.Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: t.TempDir() + "/positions.yml",
+	})
+	require.NoError(t, err)
+	t.Cleanup(ps.Stop)
+	return ps
+}
+
 const (
 	project      = "test-project"
 	subscription = "test-subscription"
@@ -170,7 +170,7 @@ func TestPushTarget(t *testing.T) {
 
 			prometheus.DefaultRegisterer = prometheus.NewRegistry()
 			metrics := gcplog.NewMetrics(prometheus.DefaultRegisterer)
-			pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, tc.args.RelabelConfigs, outerName+"_test_job", config)
+			pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, nil, tc.args.RelabelConfigs, outerName+"_test_job", config)
 			require.NoError(t, err)
 			defer func() {
 				_ = pt.Stop()
@@ -232,7 +232,7 @@ func TestPushTarget_UseIncomingTimestamp(t *testing.T) {
 
 	prometheus.DefaultRegisterer = prometheus.NewRegistry()
 	metrics := gcplog.NewMetrics(prometheus.DefaultRegisterer)
-	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, nil, t.Name()+"_test_job", config)
+	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, nil, nil, t.Name()+"_test_job", config)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -285,7 +285,7 @@ func TestPushTarget_UseTenantIDHeaderIfPresent(t *testing.T) {
 			Action:       relabel.Replace,
 		},
 	}
-	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, tenantIDRelabelConfig, t.Name()+"_test_job", config)
+	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, nil, tenantIDRelabelConfig, t.Name()+"_test_job", config)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -328,7 +328,7 @@ func TestPushTarget_ErroneousPayloadsAreRejected(t *testing.T) {
 
 	prometheus.DefaultRegisterer = prometheus.NewRegistry()
 	metrics := gcplog.NewMetrics(prometheus.DefaultRegisterer)
-	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, nil, t.Name()+"_test_job", config)
+	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, nil, nil, t.Name()+"_test_job", config)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -419,7 +419,7 @@ func TestPushTarget_UsePushTimeout(t *testing.T) {
 			Action:       relabel.Replace,
 		},
 	}
-	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, tenantIDRelabelConfig, t.Name()+"_test_job", config)
+	pt, err := gcplog.NewGCPLogTarget(metrics, logger, eh, nil, tenantIDRelabelConfig, t.Name()+"_test_job", config)
 	require.NoError(t, err)
 	defer func() {
 		_ = pt.Stop()
@@ -8,6 +8,7 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/positions"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
 )
@@ -24,6 +25,7 @@ func NewGCPLogTarget(
 	metrics *Metrics,
 	logger log.Logger,
 	handler api.EntryHandler,
+	position positions.Positions,
 	relabel []*relabel.Config,
 	jobName string,
 	config *scrapeconfig.GcplogTargetConfig,
@@ -31,7 +33,7 @@ func NewGCPLogTarget(
 ) (Target, error) {
 	switch config.SubscriptionType {
 	case "pull", "":
-		return newPullTarget(metrics, logger, handler, relabel, jobName, config, clientOptions...)
+		return newPullTarget(metrics, logger, handler, position, relabel, jobName, config, clientOptions...)
 	case "push":
 		return newPushTarget(metrics, logger, handler, jobName, config, relabel)
 	default:
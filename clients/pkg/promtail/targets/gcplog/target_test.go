@@ -120,7 +120,7 @@ func TestNewGCPLogTarget(t *testing.T) {
 				t.Fatal(err)
 			}
 			tt.args.config.Server = serverConfig
-			got, err := NewGCPLogTarget(tt.args.metrics, tt.args.logger, tt.args.handler, tt.args.relabel, tt.args.jobName, tt.args.config, option.WithCredentials(&google.Credentials{}))
+			got, err := NewGCPLogTarget(tt.args.metrics, tt.args.logger, tt.args.handler, newTestPositions(t), tt.args.relabel, tt.args.jobName, tt.args.config, option.WithCredentials(&google.Credentials{}))
 			// If the target was started, stop it after test
 			if got != nil {
 				defer func() { _ = got.Stop() }()
@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/loki/v3/clients/pkg/logentry/stages"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/positions"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
 )
@@ -21,6 +22,7 @@ type GcplogTargetManager struct {
 func NewGcplogTargetManager(
 	metrics *Metrics,
 	logger log.Logger,
+	positions positions.Positions,
 	client api.EntryHandler,
 	scrape []scrapeconfig.Config,
 ) (*GcplogTargetManager, error) {
@@ -38,7 +40,7 @@ func NewGcplogTargetManager(
 			return nil, err
 		}
 
-		t, err := NewGCPLogTarget(metrics, logger, pipeline.Wrap(client), cf.RelabelConfigs, cf.JobName, cf.GcplogConfig)
+		t, err := NewGCPLogTarget(metrics, logger, pipeline.Wrap(client), positions, cf.RelabelConfigs, cf.JobName, cf.GcplogConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create pubsub target: %w", err)
 		}
@@ -0,0 +1,172 @@
+package kubernetesevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logfmt/logfmt"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// Supported log line formats.
+const (
+	formatLogfmt = "logfmt"
+	formatJSON   = "json"
+)
+
+// eventLine is the rendered representation of an event. The field order
+// is the order of the keys in the log line.
+type eventLine struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Count     int32  `json:"count,omitempty"`
+	Component string `json:"source_component,omitempty"`
+	Host      string `json:"source_host,omitempty"`
+	Message   string `json:"msg"`
+}
+
+func newEventLine(e *corev1.Event) eventLine {
+	return eventLine{
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Kind:      e.InvolvedObject.Kind,
+		Name:      e.InvolvedObject.Name,
+		Namespace: e.InvolvedObject.Namespace,
+		Count:     e.Count,
+		Component: eventSource(e),
+		Host:      e.Source.Host,
+		Message:   strings.TrimSpace(e.Message),
+	}
+}
+
+// formatEvent renders an event as a log line in the given format.
+func formatEvent(e *corev1.Event, format string) (string, error) {
+	l := newEventLine(e)
+	if format == formatJSON {
+		b, err := json.Marshal(l)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	var buf bytes.Buffer
+	enc := logfmt.NewEncoder(&buf)
+	kvs := []interface{}{
+		"type", l.Type,
+		"reason", l.Reason,
+		"kind", l.Kind,
+		"name", l.Name,
+	}
+	if l.Namespace != "" {
+		kvs = append(kvs, "namespace", l.Namespace)
+	}
+	if l.Count != 0 {
+		kvs = append(kvs, "count", l.Count)
+	}
+	if l.Component != "" {
+		kvs = append(kvs, "source_component", l.Component)
+	}
+	if l.Host != "" {
+		kvs = append(kvs, "source_host", l.Host)
+	}
+	kvs = append(kvs, "msg", l.Message)
+	if err := enc.EncodeKeyvals(kvs...); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// eventSource returns the component which reported the event, newer events
+// created through the events.k8s.io API set ReportingController instead of
+// Source.
+func eventSource(e *corev1.Event) string {
+	if e.Source.Component != "" {
+		return e.Source.Component
+	}
+	return e.ReportingController
+}
+
+// eventTimestamp returns the time of the last occurrence of the event.
+func eventTimestamp(e *corev1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	case !e.CreationTimestamp.IsZero():
+		return e.CreationTimestamp.Time
+	}
+	return time.Now()
+}
+
+// eventLabels returns the internal labels of an event which can be used in
+// relabel_configs.
+func eventLabels(e *corev1.Event) labels.Labels {
+	lbs := labels.NewBuilder(nil)
+	lbs.Set("__kubernetes_event_namespace", e.Namespace)
+	lbs.Set("__kubernetes_event_type", e.Type)
+	lbs.Set("__kubernetes_event_reason", e.Reason)
+	lbs.Set("__kubernetes_event_source_component", eventSource(e))
+	lbs.Set("__kubernetes_event_involved_object_kind", e.InvolvedObject.Kind)
+	lbs.Set("__kubernetes_event_involved_object_name", e.InvolvedObject.Name)
+	lbs.Set("__kubernetes_event_involved_object_namespace", e.InvolvedObject.Namespace)
+	return lbs.Labels()
+}
+
+// toEntry converts an event to an entry, relabeling its internal labels and
+// merging them with the configured labels. It returns false if the event was
+// dropped by relabeling.
+func toEntry(e *corev1.Event, format string, other model.LabelSet, relabelConfig []*relabel.Config) (api.Entry, bool, error) {
+	processed := eventLabels(e)
+	if len(relabelConfig) > 0 {
+		var keep bool
+		processed, keep = relabel.Process(processed, relabelConfig...)
+		if !keep {
+			return api.Entry{}, false, nil
+		}
+	}
+
+	line, err := formatEvent(e, format)
+	if err != nil {
+		return api.Entry{}, false, fmt.Errorf("failed to format event %s/%s: %w", e.Namespace, e.Name, err)
+	}
+
+	lbs := make(model.LabelSet, len(processed))
+	for _, lbl := range processed {
+		// ignore internal labels
+		if strings.HasPrefix(lbl.Name, "__") {
+			continue
+		}
+		// ignore invalid labels
+		if !model.LabelName(lbl.Name).IsValid() || !model.LabelValue(lbl.Value).IsValid() {
+			continue
+		}
+		lbs[model.LabelName(lbl.Name)] = model.LabelValue(lbl.Value)
+	}
+
+	return api.Entry{
+		Labels: lbs.Merge(other),
+		Entry: logproto.Entry{
+			Timestamp: eventTimestamp(e),
+			Line:      line,
+		},
+	}, true, nil
+}
@@ -0,0 +1,334 @@
+package kubernetesevents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
+)
+
+var defaultBackoff = backoff.Config{
+	MinBackoff: 1 * time.Second,
+	MaxBackoff: 30 * time.Second,
+}
+
+// Target watches the Kubernetes Events API and forwards events as log lines.
+// The resource version of the last handled event is stored in positions so
+// the watch resumes where it left off after a restart.
+type Target struct {
+	logger        log.Logger
+	handler       api.EntryHandler
+	positions     positions.Positions
+	jobName       string
+	config        *scrapeconfig.KubernetesEventsTargetConfig
+	relabelConfig []*relabel.Config
+	metrics       *Metrics
+	client        kubernetes.Interface
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running *atomic.Bool
+	err     *atomic.Error
+}
+
+// NewTarget creates a new Kubernetes events target connected to the cluster
+// described by the config.
+func NewTarget(
+	metrics *Metrics,
+	logger log.Logger,
+	handler api.EntryHandler,
+	position positions.Positions,
+	jobName string,
+	config *scrapeconfig.KubernetesEventsTargetConfig,
+	relabel []*relabel.Config,
+) (*Target, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+	client, err := newClient(config.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newTarget(metrics, logger, handler, position, jobName, config, relabel, client), nil
+}
+
+func newTarget(
+	metrics *Metrics,
+	logger log.Logger,
+	handler api.EntryHandler,
+	position positions.Positions,
+	jobName string,
+	config *scrapeconfig.KubernetesEventsTargetConfig,
+	relabel []*relabel.Config,
+	client kubernetes.Interface,
+) *Target {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &Target{
+		logger:        logger,
+		handler:       handler,
+		positions:     position,
+		jobName:       jobName,
+		config:        config,
+		relabelConfig: relabel,
+		metrics:       metrics,
+		client:        client,
+
+		ctx:     ctx,
+		cancel:  cancel,
+		running: atomic.NewBool(false),
+		err:     atomic.NewError(nil),
+	}
+	t.start()
+	return t
+}
+
+func newClient(kubeconfig string) (kubernetes.Interface, error) {
+	var (
+		cfg *rest.Config
+		err error
+	)
+	if kubeconfig == "" {
+		cfg, err = rest.InClusterConfig()
+	} else {
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes client config: %w", err)
+	}
+	cfg.UserAgent = "promtail"
+	return kubernetes.NewForConfig(cfg)
+}
+
+func (t *Target) start() {
+	namespaces := t.config.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	t.running.Store(true)
+	for _, ns := range namespaces {
+		t.wg.Add(1)
+		go func(ns string) {
+			defer t.wg.Done()
+			t.run(ns)
+		}(ns)
+	}
+	go func() {
+		t.wg.Wait()
+		t.running.Store(false)
+	}()
+}
+
+// run watches the events of a namespace until the target is stopped.
+func (t *Target) run(namespace string) {
+	var (
+		key     = positionKey(t.jobName, namespace)
+		backoff = backoff.New(t.ctx, defaultBackoff)
+	)
+
+	for t.ctx.Err() == nil {
+		// Another instance sharing the positions is watching the namespace.
+		if !t.positions.Owned(key) {
+			level.Debug(t.logger).Log("msg", "namespace is leased by another instance, waiting", "namespace", namespace)
+			select {
+			case <-time.After(t.positions.SyncPeriod()):
+			case <-t.ctx.Done():
+			}
+			continue
+		}
+
+		rv := t.positions.GetString(key)
+		if rv == "" {
+			list, err := t.client.CoreV1().Events(namespace).List(t.ctx, t.listOptions(""))
+			if err != nil {
+				t.fail(namespace, "failed to list events", err)
+				backoff.Wait()
+				continue
+			}
+			// Existing events are skipped when starting without a position,
+			// only events occurring from now on are forwarded.
+			rv = list.ResourceVersion
+			t.positions.PutString(key, rv)
+		}
+
+		err := t.watch(namespace, key, rv)
+		switch {
+		case err == nil:
+			backoff.Reset()
+		case apierrors.IsResourceExpired(err) || apierrors.IsGone(err):
+			level.Warn(t.logger).Log("msg", "resource version expired, events may have been missed", "namespace", namespace, "resource_version", rv)
+			t.metrics.relists.WithLabelValues(namespaceName(namespace)).Inc()
+			t.positions.Remove(key)
+		default:
+			t.fail(namespace, "failed to watch events", err)
+			backoff.Wait()
+		}
+	}
+}
+
+// watch forwards events from the given resource version until the watch is
+// closed by the server, the target is stopped, the namespace is leased by
+// another instance or an error occurs.
+func (t *Target) watch(namespace, key, rv string) error {
+	w, err := t.client.CoreV1().Events(namespace).Watch(t.ctx, t.listOptions(rv))
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+
+	leaseCheck := time.NewTicker(t.positions.SyncPeriod())
+	defer leaseCheck.Stop()
+
+	t.err.Store(nil)
+	for {
+		select {
+		case <-t.ctx.Done():
+			return nil
+		case <-leaseCheck.C:
+			if !t.positions.Owned(key) {
+				level.Info(t.logger).Log("msg", "stopping watch, namespace is leased by another instance", "namespace", namespace)
+				return nil
+			}
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				e, ok := ev.Object.(*corev1.Event)
+				if !ok {
+					continue
+				}
+				if err := t.handle(namespace, e); err != nil {
+					level.Warn(t.logger).Log("msg", "failed to handle event", "namespace", namespace, "err", err)
+				}
+				t.positions.PutString(key, e.ResourceVersion)
+			case watch.Bookmark:
+				if obj, err := meta.Accessor(ev.Object); err == nil {
+					t.positions.PutString(key, obj.GetResourceVersion())
+				}
+			case watch.Error:
+				return apierrors.FromObject(ev.Object)
+			}
+		}
+	}
+}
+
+func (t *Target) handle(namespace string, e *corev1.Event) error {
+	entry, ok, err := toEntry(e, t.config.Format, t.config.Labels, t.relabelConfig)
+	if err != nil || !ok {
+		return err
+	}
+	select {
+	case t.handler.Chan() <- entry:
+		t.metrics.entries.WithLabelValues(namespaceName(namespace)).Inc()
+	case <-t.ctx.Done():
+	}
+	return nil
+}
+
+func (t *Target) fail(namespace, msg string, err error) {
+	level.Error(t.logger).Log("msg", msg, "namespace", namespace, "err", err)
+	t.metrics.errors.WithLabelValues(namespaceName(namespace)).Inc()
+	t.err.Store(err)
+}
+
+func (t *Target) listOptions(rv string) metav1.ListOptions {
+	return metav1.ListOptions{
+		FieldSelector:       t.config.FieldSelector,
+		LabelSelector:       t.config.LabelSelector,
+		ResourceVersion:     rv,
+		AllowWatchBookmarks: rv != "",
+	}
+}
+
+func (t *Target) Stop() {
+	t.cancel()
+	t.wg.Wait()
+	t.handler.Stop()
+}
+
+func (t *Target) Type() target.TargetType {
+	return target.KubernetesEventsTargetType
+}
+
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+func (t *Target) Ready() bool {
+	return t.running.Load()
+}
+
+func (t *Target) Details() interface{} {
+	var errMsg string
+	if err := t.err.Load(); err != nil {
+		errMsg = err.Error()
+	}
+	namespaces := t.config.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	details := map[string]string{
+		"field_selector": t.config.FieldSelector,
+		"label_selector": t.config.LabelSelector,
+		"error":          errMsg,
+	}
+	names := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		names = append(names, namespaceName(ns))
+		details["resource_version_"+namespaceName(ns)] = t.positions.GetString(positionKey(t.jobName, ns))
+	}
+	details["namespaces"] = strings.Join(names, ",")
+	return details
+}
+
+// positionKey returns the positions key holding the last resource version of
+// a job and namespace.
+func positionKey(jobName, namespace string) string {
+	return positions.CursorKey(fmt.Sprintf("kubernetes-events-%s-%s", jobName, namespaceName(namespace)))
+}
+
+func namespaceName(namespace string) string {
+	if namespace == metav1.NamespaceAll {
+		return "all"
+	}
+	return namespace
+}
+
+func validateConfig(cfg *scrapeconfig.KubernetesEventsTargetConfig) error {
+	switch cfg.Format {
+	case "":
+		cfg.Format = formatLogfmt
+	case formatLogfmt, formatJSON:
+	default:
+		return fmt.Errorf("invalid kubernetes events format %q, supported values are: %s, %s", cfg.Format, formatLogfmt, formatJSON)
+	}
+	return nil
+}
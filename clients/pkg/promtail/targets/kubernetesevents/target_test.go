@@ -0,0 +1,277 @@
+package kubernetesevents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/grafana/loki/v3/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/positions"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
+)
+
+// fakeCluster is a fake clientset handing out a new watcher on every watch
+// request, along with the resource version the watch was started from.
+type fakeCluster struct {
+	*k8sfake.Clientset
+	lists   chan struct{}
+	watches chan fakeWatch
+}
+
+type fakeWatch struct {
+	*watch.FakeWatcher
+	resourceVersion string
+}
+
+func newFakeCluster(listResourceVersion string) *fakeCluster {
+	c := &fakeCluster{
+		Clientset: k8sfake.NewSimpleClientset(),
+		lists:     make(chan struct{}, 10),
+		watches:   make(chan fakeWatch, 10),
+	}
+	c.PrependReactor("list", "events", func(k8stesting.Action) (bool, runtime.Object, error) {
+		c.lists <- struct{}{}
+		return true, &corev1.EventList{ListMeta: metav1.ListMeta{ResourceVersion: listResourceVersion}}, nil
+	})
+	c.PrependWatchReactor("events", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFake()
+		c.watches <- fakeWatch{
+			FakeWatcher:     w,
+			resourceVersion: action.(k8stesting.WatchActionImpl).GetWatchRestrictions().ResourceVersion,
+		}
+		return true, w, nil
+	})
+	return c
+}
+
+func (c *fakeCluster) nextWatch(t *testing.T) fakeWatch {
+	t.Helper()
+	select {
+	case w := <-c.watches:
+		return w
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watch")
+	}
+	return fakeWatch{}
+}
+
+func newTestPositions(t *testing.T) positions.Positions {
+	t.Helper()
+	ps, err := positions.New(log.NewNopLogger(), positions.Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: t.TempDir() + "/positions.yml",
+	})
+	require.NoError(t, err)
+	t.Cleanup(ps.Stop)
+	return ps
+}
+
+func testEvent(rv string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "nginx-5d7f.17a8",
+			Namespace:       "default",
+			ResourceVersion: rv,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "nginx-5d7f",
+			Namespace: "default",
+		},
+		Type:          corev1.EventTypeWarning,
+		Reason:        "BackOff",
+		Message:       "Back-off restarting failed container",
+		Count:         3,
+		Source:        corev1.EventSource{Component: "kubelet", Host: "node-1"},
+		LastTimestamp: metav1.NewTime(time.Unix(100, 0)),
+	}
+}
+
+func Test_KubernetesEventsTarget(t *testing.T) {
+	var (
+		cluster = newFakeCluster("10")
+		client  = fake.New(func() {})
+		ps      = newTestPositions(t)
+		cfg     = &scrapeconfig.KubernetesEventsTargetConfig{
+			Namespaces: []string{"default"},
+			Labels:     model.LabelSet{"job": "kubernetes-events"},
+		}
+		relabels = []*relabel.Config{
+			{
+				SourceLabels: model.LabelNames{"__kubernetes_event_involved_object_kind"},
+				Regex:        relabel.MustNewRegexp("(.*)"),
+				Replacement:  "$1",
+				TargetLabel:  "kind",
+				Action:       relabel.Replace,
+			},
+		}
+	)
+	require.NoError(t, validateConfig(cfg))
+
+	ta := newTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), client, ps, "events", cfg, relabels, cluster)
+	defer ta.Stop()
+	require.True(t, ta.Ready())
+
+	// Without a stored position events are listed to find the resource version.
+	w := cluster.nextWatch(t)
+	require.Equal(t, "10", w.resourceVersion)
+
+	w.Add(testEvent("11"))
+	w.Modify(testEvent("12"))
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entry := client.Received()[0]
+	require.Equal(t, model.LabelSet{"job": "kubernetes-events", "kind": "Pod"}, entry.Labels)
+	require.Equal(t, time.Unix(100, 0), entry.Timestamp)
+	require.Equal(t, `type=Warning reason=BackOff kind=Pod name=nginx-5d7f namespace=default count=3 source_component=kubelet source_host=node-1 msg="Back-off restarting failed container"`, entry.Line)
+	require.Eventually(t, func() bool {
+		return ps.GetString(positionKey("events", "default")) == "12"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A closed watch resumes from the last handled event.
+	w.Stop()
+	w = cluster.nextWatch(t)
+	require.Equal(t, "12", w.resourceVersion)
+
+	// An expired resource version triggers a new list.
+	w.Error(&metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   410,
+		Reason: metav1.StatusReasonExpired,
+	})
+	w = cluster.nextWatch(t)
+	require.Equal(t, "10", w.resourceVersion)
+	require.Len(t, cluster.lists, 2)
+}
+
+func Test_KubernetesEventsTarget_ResumeFromPosition(t *testing.T) {
+	var (
+		cluster = newFakeCluster("10")
+		client  = fake.New(func() {})
+		ps      = newTestPositions(t)
+		cfg     = &scrapeconfig.KubernetesEventsTargetConfig{Format: formatJSON}
+	)
+	require.NoError(t, validateConfig(cfg))
+	ps.PutString(positionKey("events", metav1.NamespaceAll), "42")
+
+	ta := newTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), client, ps, "events", cfg, nil, cluster)
+	defer ta.Stop()
+
+	w := cluster.nextWatch(t)
+	require.Equal(t, "42", w.resourceVersion)
+	require.Len(t, cluster.lists, 0)
+
+	w.Add(testEvent("43"))
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entry := client.Received()[0]
+	require.Equal(t, model.LabelSet{}, entry.Labels)
+	require.JSONEq(t, `{"type":"Warning","reason":"BackOff","kind":"Pod","name":"nginx-5d7f","namespace":"default","count":3,"source_component":"kubelet","source_host":"node-1","msg":"Back-off restarting failed container"}`, entry.Line)
+
+	require.Equal(t, "all", ta.Details().(map[string]string)["namespaces"])
+	require.Eventually(t, func() bool {
+		return ta.Details().(map[string]string)["resource_version_all"] == "43"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_KubernetesEventsTarget_Leased(t *testing.T) {
+	var (
+		cluster = newFakeCluster("10")
+		client  = fake.New(func() {})
+		cfg     = &scrapeconfig.KubernetesEventsTargetConfig{Namespaces: []string{"default"}}
+		key     = positionKey("events", "default")
+	)
+	require.NoError(t, validateConfig(cfg))
+	newPositions := func(instanceID string) positions.Positions {
+		ps, err := positions.New(log.NewNopLogger(), positions.Config{
+			SyncPeriod:    50 * time.Millisecond,
+			Backend:       positions.BackendKV,
+			KVStore:       kv.Config{Store: "inmemory"},
+			InstanceID:    instanceID,
+			LeaseDuration: 500 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		return ps
+	}
+
+	// a watched the namespace up to resource version 42 and leases it.
+	a := newPositions("a")
+	require.True(t, a.Owned(key))
+	a.PutString(key, "42")
+	a.Stop()
+
+	b := newPositions("b")
+	defer b.Stop()
+	require.False(t, b.Owned(key))
+
+	ta := newTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), client, b, "events", cfg, nil, cluster)
+	defer ta.Stop()
+
+	// b does not watch the namespace until the lease of a expires, then
+	// resumes from its resource version.
+	select {
+	case <-cluster.watches:
+		t.Fatal("namespace leased by another instance is watched")
+	case <-time.After(200 * time.Millisecond):
+	}
+	w := cluster.nextWatch(t)
+	require.Equal(t, "42", w.resourceVersion)
+	require.Len(t, cluster.lists, 0)
+}
+
+func Test_KubernetesEventsTarget_DropByRelabel(t *testing.T) {
+	var (
+		cluster  = newFakeCluster("1")
+		client   = fake.New(func() {})
+		ps       = newTestPositions(t)
+		cfg      = &scrapeconfig.KubernetesEventsTargetConfig{}
+		relabels = []*relabel.Config{
+			{
+				SourceLabels: model.LabelNames{"__kubernetes_event_type"},
+				Regex:        relabel.MustNewRegexp("Normal"),
+				Action:       relabel.Drop,
+			},
+		}
+	)
+	require.NoError(t, validateConfig(cfg))
+
+	ta := newTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), client, ps, "events", cfg, relabels, cluster)
+	defer ta.Stop()
+
+	w := cluster.nextWatch(t)
+	normal := testEvent("2")
+	normal.Type = corev1.EventTypeNormal
+	w.Add(normal)
+	w.Add(testEvent("3"))
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return ps.GetString(positionKey("events", metav1.NamespaceAll)) == "3"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_ValidateConfig(t *testing.T) {
+	cfg := &scrapeconfig.KubernetesEventsTargetConfig{}
+	require.NoError(t, validateConfig(cfg))
+	require.Equal(t, formatLogfmt, cfg.Format)
+
+	require.Error(t, validateConfig(&scrapeconfig.KubernetesEventsTargetConfig{Format: "xml"}))
+}
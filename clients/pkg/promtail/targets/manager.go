@@ -21,7 +21,9 @@ import (
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/heroku"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/journal"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/kafka"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/kubernetesevents"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/lokipush"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/otlp"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/stdin"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/syslog"
 	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
@@ -41,6 +43,8 @@ const (
 	DockerSDConfigs             = "dockerSDConfigs"
 	HerokuDrainConfigs          = "herokuDrainConfigs"
 	AzureEventHubsScrapeConfigs = "azureeventhubsScrapeConfigs"
+	KubernetesEventsConfigs     = "kubernetesEventsConfigs"
+	OTLPConfigs                 = "otlpConfigs"
 )
 
 var (
@@ -52,6 +56,7 @@ var (
 	dockerMetrics      *docker.Metrics
 	journalMetrics     *journal.Metrics
 	herokuDrainMetrics *heroku.Metrics
+	k8sEventsMetrics   *kubernetesevents.Metrics
 )
 
 type targetManager interface {
@@ -117,6 +122,10 @@ func NewTargetManagers(
 			targetScrapeConfigs[DockerSDConfigs] = append(targetScrapeConfigs[DockerSDConfigs], cfg)
 		case cfg.HerokuDrainConfig != nil:
 			targetScrapeConfigs[HerokuDrainConfigs] = append(targetScrapeConfigs[HerokuDrainConfigs], cfg)
+		case cfg.KubernetesEventsConfig != nil:
+			targetScrapeConfigs[KubernetesEventsConfigs] = append(targetScrapeConfigs[KubernetesEventsConfigs], cfg)
+		case cfg.OTLPConfig != nil:
+			targetScrapeConfigs[OTLPConfigs] = append(targetScrapeConfigs[OTLPConfigs], cfg)
 		default:
 			return nil, fmt.Errorf("no valid target scrape config defined for %q", cfg.JobName)
 		}
@@ -128,7 +137,7 @@ func NewTargetManagers(
 	getPositionFile := func() (positions.Positions, error) {
 		if positionFile == nil {
 			var err error
-			positionFile, err = positions.New(logger, positionsConfig)
+			positionFile, err = positions.NewWithRegisterer(logger, positionsConfig, reg)
 			if err != nil {
 				return nil, err
 			}
@@ -160,6 +169,9 @@ func NewTargetManagers(
 	if len(targetScrapeConfigs[HerokuDrainConfigs]) > 0 && herokuDrainMetrics == nil {
 		herokuDrainMetrics = heroku.NewMetrics(reg)
 	}
+	if len(targetScrapeConfigs[KubernetesEventsConfigs]) > 0 && k8sEventsMetrics == nil {
+		k8sEventsMetrics = kubernetesevents.NewMetrics(reg)
+	}
 
 	for target, scrapeConfigs := range targetScrapeConfigs {
 		switch target {
@@ -209,9 +221,14 @@ func NewTargetManagers(
 			}
 			targetManagers = append(targetManagers, syslogTargetManager)
 		case GcplogScrapeConfigs:
+			pos, err := getPositionFile()
+			if err != nil {
+				return nil, err
+			}
 			pubsubTargetManager, err := gcplog.NewGcplogTargetManager(
 				gcplogMetrics,
 				logger,
+				pos,
 				client,
 				scrapeConfigs,
 			)
@@ -280,6 +297,27 @@ func NewTargetManagers(
 				return nil, errors.Wrap(err, "failed to make Docker service discovery target manager")
 			}
 			targetManagers = append(targetManagers, cfTargetManager)
+		case KubernetesEventsConfigs:
+			pos, err := getPositionFile()
+			if err != nil {
+				return nil, err
+			}
+			k8sEventsTargetManager, err := kubernetesevents.NewTargetManager(k8sEventsMetrics, logger, pos, client, scrapeConfigs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make kubernetes events target manager")
+			}
+			targetManagers = append(targetManagers, k8sEventsTargetManager)
+		case OTLPConfigs:
+			otlpTargetManager, err := otlp.NewTargetManager(
+				reg,
+				logger,
+				client,
+				scrapeConfigs,
+			)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make OTLP target manager")
+			}
+			targetManagers = append(targetManagers, otlpTargetManager)
 		default:
 			return nil, errors.New("unknown scrape config")
 		}
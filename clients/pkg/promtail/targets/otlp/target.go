@@ -0,0 +1,280 @@
+package otlp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/server"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/serverutils"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+// Target receives OTLP logs over HTTP and gRPC. Resource, scope and log
+// attributes are converted to labels and structured metadata the same way
+// the Loki OTLP endpoint does.
+type Target struct {
+	logger        log.Logger
+	handler       api.EntryHandler
+	config        *scrapeconfig.OTLPTargetConfig
+	otlpConfig    push.OTLPConfig
+	relabelConfig []*relabel.Config
+	jobName       string
+	server        *server.Server
+}
+
+// NewTarget creates and starts an OTLP target.
+func NewTarget(logger log.Logger,
+	handler api.EntryHandler,
+	relabel []*relabel.Config,
+	jobName string,
+	config *scrapeconfig.OTLPTargetConfig,
+) (*Target, error) {
+	if err := config.OTLP.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid otlp_config: %w", err)
+	}
+
+	t := &Target{
+		logger:        logger,
+		handler:       handler,
+		relabelConfig: relabel,
+		jobName:       jobName,
+		config:        config,
+		otlpConfig:    otlpConfig(config),
+	}
+
+	mergedServerConfigs, err := serverutils.MergeWithDefaults(config.Server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configs and override defaults when configuring otlp target: %w", err)
+	}
+	// Set the config to the new combined config.
+	config.Server = mergedServerConfigs
+
+	err = t.run()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// otlpConfig returns the attributes mapping of the target, with the default
+// resource attributes stored as labels by Loki unless overridden.
+func otlpConfig(config *scrapeconfig.OTLPTargetConfig) push.OTLPConfig {
+	var global push.GlobalOTLPConfig
+	global.RegisterFlags(flag.NewFlagSet("otlp", flag.ContinueOnError))
+	if config.DefaultResourceAttributesAsIndexLabels != nil {
+		global.DefaultOTLPResourceAttributesAsIndexLabels = config.DefaultResourceAttributesAsIndexLabels
+	}
+
+	cfg := config.OTLP
+	cfg.ApplyGlobalOTLPConfig(global)
+	return cfg
+}
+
+func (t *Target) run() error {
+	level.Info(t.logger).Log("msg", "starting otlp server", "job", t.jobName)
+	// To prevent metric collisions because all metrics are going to be registered in the global Prometheus registry.
+	t.config.Server.MetricsNamespace = "promtail_" + t.jobName
+
+	// We don't want the /debug and /metrics endpoints running
+	t.config.Server.RegisterInstrumentation = false
+
+	// The logger registers a metric which will cause a duplicate registry panic unless we provide an empty registry
+	// The metric created is for counting log lines and isn't likely to be missed.
+	serverCfg := &t.config.Server
+	serverCfg.Log = util_log.InitLogger(serverCfg, prometheus.NewRegistry(), false)
+
+	// Set new registry for upcoming metric server
+	// If not, it'll likely panic when the tool gets reloaded.
+	if t.config.Server.Registerer == nil {
+		t.config.Server.Registerer = prometheus.NewRegistry()
+	}
+
+	srv, err := server.New(t.config.Server)
+	if err != nil {
+		return err
+	}
+
+	t.server = srv
+	// /v1/logs is the default path of OTLP exporters, /otlp/v1/logs the one of Loki.
+	t.server.HTTP.Path("/v1/logs").Methods("POST").Handler(http.HandlerFunc(t.handleHTTP))
+	t.server.HTTP.Path("/otlp/v1/logs").Methods("POST").Handler(http.HandlerFunc(t.handleHTTP))
+	t.server.HTTP.Path("/ready").Methods("GET").Handler(http.HandlerFunc(t.ready))
+	plogotlp.RegisterGRPCServer(t.server.GRPC, &grpcHandler{target: t})
+
+	go func() {
+		err := srv.Run()
+		if err != nil {
+			level.Error(t.logger).Log("msg", "otlp server shutdown with error", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+func (t *Target) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), util_log.Logger)
+	userID, _ := tenant.TenantID(r.Context())
+	req, err := push.ParseRequest(logger, userID, r, nil, limits{otlpConfig: t.otlpConfig}, push.ParseOTLPRequest, nil)
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "failed to parse incoming otlp request", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := t.forward(r.Context(), req); err != nil {
+		level.Warn(t.logger).Log("msg", "at least one entry in the otlp request failed to process", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// forward relabels the streams of the request and sends their entries to the
+// handler.
+func (t *Target) forward(ctx context.Context, req *logproto.PushRequest) error {
+	var lastErr error
+	for _, stream := range req.Streams {
+		ls, err := promql_parser.ParseMetric(stream.Labels)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		sort.Sort(ls)
+
+		lb := labels.NewBuilder(ls)
+
+		// Add configured labels
+		for k, v := range t.config.Labels {
+			lb.Set(string(k), string(v))
+		}
+
+		// Apply relabeling
+		processed, keep := relabel.Process(lb.Labels(), t.relabelConfig...)
+		if !keep || len(processed) == 0 {
+			continue
+		}
+
+		// Convert to model.LabelSet
+		filtered := model.LabelSet{}
+		for i := range processed {
+			if strings.HasPrefix(processed[i].Name, "__") {
+				continue
+			}
+			filtered[model.LabelName(processed[i].Name)] = model.LabelValue(processed[i].Value)
+		}
+
+		for _, entry := range stream.Entries {
+			e := api.Entry{
+				Labels: filtered.Clone(),
+				Entry: logproto.Entry{
+					Line:               entry.Line,
+					StructuredMetadata: entry.StructuredMetadata,
+				},
+			}
+			if t.config.KeepTimestamp {
+				e.Timestamp = entry.Timestamp
+			} else {
+				e.Timestamp = time.Now()
+			}
+			select {
+			case t.handler.Chan() <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return lastErr
+}
+
+// grpcHandler implements the OTLP gRPC logs service.
+type grpcHandler struct {
+	plogotlp.UnimplementedGRPCServer
+	target *Target
+}
+
+func (h *grpcHandler) Export(ctx context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	pushReq := push.OTLPLogsToPushRequest(ctx, req.Logs(), h.target.otlpConfig)
+	if err := h.target.forward(ctx, pushReq); err != nil {
+		level.Warn(h.target.logger).Log("msg", "at least one entry in the otlp request failed to process", "err", err.Error())
+		return plogotlp.NewExportResponse(), status.Error(codes.InvalidArgument, err.Error())
+	}
+	return plogotlp.NewExportResponse(), nil
+}
+
+// limits hands the attributes mapping of the target to the push parser.
+type limits struct {
+	push.EmptyLimits
+	otlpConfig push.OTLPConfig
+}
+
+func (l limits) OTLPConfig(string) push.OTLPConfig {
+	return l.otlpConfig
+}
+
+// Type returns OTLPTargetType.
+func (t *Target) Type() target.TargetType {
+	return target.OTLPTargetType
+}
+
+// Ready indicates whether or not the target is ready to be read from.
+func (t *Target) Ready() bool {
+	return true
+}
+
+// DiscoveredLabels returns the set of labels discovered by the target, which
+// is always nil. Implements Target.
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+// Labels returns the set of labels that statically apply to all log entries
+// produced by the target.
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+// Details returns target-specific details.
+func (t *Target) Details() interface{} {
+	return map[string]string{}
+}
+
+// Stop shuts down the target.
+func (t *Target) Stop() error {
+	level.Info(t.logger).Log("msg", "stopping otlp server", "job", t.jobName)
+	t.server.Shutdown()
+	t.handler.Stop()
+	return nil
+}
+
+// ready function serves the ready endpoint
+func (t *Target) ready(w http.ResponseWriter, _ *http.Request) {
+	resp := "ready"
+	if _, err := w.Write([]byte(resp)); err != nil {
+		level.Error(t.logger).Log("msg", "failed to respond to ready endoint", "err", err)
+	}
+}
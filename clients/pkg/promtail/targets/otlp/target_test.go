@@ -0,0 +1,155 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/server"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/grafana/loki/v3/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+const localhost = "127.0.0.1"
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", localhost+":0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func newTestTarget(t *testing.T, config *scrapeconfig.OTLPTargetConfig, relabels []*relabel.Config) (*Target, *fake.Client, int, int) {
+	t.Helper()
+	httpPort, grpcPort := freePort(t), freePort(t)
+
+	defaults := server.Config{}
+	defaults.RegisterFlags(flag.NewFlagSet("empty", flag.ContinueOnError))
+	defaults.HTTPListenAddress = localhost
+	defaults.HTTPListenPort = httpPort
+	defaults.GRPCListenAddress = localhost
+	defaults.GRPCListenPort = grpcPort
+	config.Server = defaults
+
+	eh := fake.New(func() {})
+	tg, err := NewTarget(log.NewNopLogger(), eh, relabels, "job1", config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = tg.Stop() })
+	return tg, eh, httpPort, grpcPort
+}
+
+func testLogs() plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("host.name", "node-1")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("checkout/http")
+	for i := 0; i < 3; i++ {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(int64(i+1), 0)))
+		lr.Body().SetStr(fmt.Sprintf("request %d", i))
+		lr.SetSeverityText("INFO")
+		lr.Attributes().PutStr("http.method", "GET")
+		lr.Attributes().PutStr("password", "secret")
+	}
+	return ld
+}
+
+func TestOTLPTarget_HTTP(t *testing.T) {
+	var attrs push.OTLPConfig
+	attrs.LogAttributes = []push.AttributesConfig{
+		{Action: push.Drop, Attributes: []string{"password"}},
+	}
+	_, eh, httpPort, _ := newTestTarget(t, &scrapeconfig.OTLPTargetConfig{
+		Labels:        model.LabelSet{"job": "otlp"},
+		KeepTimestamp: true,
+		OTLP:          attrs,
+	}, nil)
+
+	body, err := plogotlp.NewExportRequestFromLogs(testLogs()).MarshalProto()
+	require.NoError(t, err)
+	resp, err := http.Post(fmt.Sprintf("http://%s:%d/v1/logs", localhost, httpPort), "application/x-protobuf", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	require.Eventually(t, func() bool {
+		return len(eh.Received()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entry := eh.Received()[0]
+	require.Equal(t, model.LabelSet{"job": "otlp", "service_name": "checkout"}, entry.Labels)
+	require.Equal(t, "request 0", entry.Line)
+	require.Equal(t, time.Unix(1, 0), entry.Timestamp)
+	require.Equal(t, []logproto.LabelAdapter{
+		{Name: "http_method", Value: "GET"},
+		{Name: "severity_text", Value: "INFO"},
+		{Name: "host_name", Value: "node-1"},
+		{Name: "scope_name", Value: "checkout/http"},
+	}, []logproto.LabelAdapter(entry.StructuredMetadata))
+}
+
+func TestOTLPTarget_GRPC(t *testing.T) {
+	_, eh, _, grpcPort := newTestTarget(t, &scrapeconfig.OTLPTargetConfig{
+		DefaultResourceAttributesAsIndexLabels: []string{"host.name"},
+	}, []*relabel.Config{
+		{
+			SourceLabels: model.LabelNames{"host_name"},
+			Regex:        relabel.MustNewRegexp("(.*)"),
+			Replacement:  "$1",
+			TargetLabel:  "node",
+			Action:       relabel.Replace,
+		},
+		{
+			Action: relabel.LabelDrop,
+			Regex:  relabel.MustNewRegexp("host_name"),
+		},
+	})
+
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", localhost, grpcPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = plogotlp.NewGRPCClient(conn).Export(ctx, plogotlp.NewExportRequestFromLogs(testLogs()))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(eh.Received()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entry := eh.Received()[2]
+	require.Equal(t, model.LabelSet{"node": "node-1"}, entry.Labels)
+	require.Equal(t, "request 2", entry.Line)
+	require.Contains(t, entry.StructuredMetadata, logproto.LabelAdapter{Name: "service_name", Value: "checkout"})
+	require.Contains(t, entry.StructuredMetadata, logproto.LabelAdapter{Name: "password", Value: "secret"})
+}
+
+func TestOTLPTarget_InvalidConfig(t *testing.T) {
+	_, err := NewTarget(log.NewNopLogger(), fake.New(func() {}), nil, "job1", &scrapeconfig.OTLPTargetConfig{
+		OTLP: push.OTLPConfig{
+			LogAttributes: []push.AttributesConfig{{Action: push.IndexLabel, Attributes: []string{"level"}}},
+		},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package otlp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/loki/v3/clients/pkg/logentry/stages"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/api"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/v3/clients/pkg/promtail/targets/target"
+)
+
+// TargetManager manages a series of targets.
+type TargetManager struct {
+	logger  log.Logger
+	targets map[string]*Target
+}
+
+// NewTargetManager creates a new TargetManager.
+func NewTargetManager(
+	reg prometheus.Registerer,
+	logger log.Logger,
+	client api.EntryHandler,
+	scrapeConfigs []scrapeconfig.Config,
+) (*TargetManager, error) {
+
+	tm := &TargetManager{
+		logger:  logger,
+		targets: make(map[string]*Target),
+	}
+
+	if err := validateJobName(scrapeConfigs); err != nil {
+		return nil, err
+	}
+
+	for _, cfg := range scrapeConfigs {
+		pipeline, err := stages.NewPipeline(log.With(logger, "component", "otlp_pipeline_"+cfg.JobName), cfg.PipelineStages, &cfg.JobName, reg)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := NewTarget(logger, pipeline.Wrap(client), cfg.RelabelConfigs, cfg.JobName, cfg.OTLPConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		tm.targets[cfg.JobName] = t
+	}
+
+	return tm, nil
+}
+
+func validateJobName(scrapeConfigs []scrapeconfig.Config) error {
+	jobNames := map[string]struct{}{}
+	for i, cfg := range scrapeConfigs {
+		if cfg.JobName == "" {
+			return errors.New("`job_name` must be defined for the `otlp` scrape_config with a " +
+				"unique name to properly register metrics, " +
+				"at least one `otlp` scrape_config has no `job_name` defined")
+		}
+		if _, ok := jobNames[cfg.JobName]; ok {
+			return fmt.Errorf("`job_name` must be unique for each `otlp` scrape_config, "+
+				"a duplicate `job_name` of %s was found", cfg.JobName)
+		}
+		jobNames[cfg.JobName] = struct{}{}
+
+		scrapeConfigs[i].JobName = strutil.SanitizeLabelName(cfg.JobName)
+	}
+	return nil
+}
+
+// Ready returns true if at least one target is also ready.
+func (tm *TargetManager) Ready() bool {
+	for _, t := range tm.targets {
+		if t.Ready() {
+			return true
+		}
+	}
+	return false
+}
+
+// Stop stops the TargetManager and all of its targets.
+func (tm *TargetManager) Stop() {
+	for _, t := range tm.targets {
+		if err := t.Stop(); err != nil {
+			level.Error(t.logger).Log("msg", "error stopping OTLP target", "err", err.Error())
+		}
+	}
+}
+
+// ActiveTargets returns the list of targets where OTLP logs
+// are being received. ActiveTargets is an alias to AllTargets as
+// OTLP targets cannot be deactivated, only stopped.
+func (tm *TargetManager) ActiveTargets() map[string][]target.Target {
+	return tm.AllTargets()
+}
+
+// AllTargets returns the list of all targets where OTLP logs
+// are currently being received.
+func (tm *TargetManager) AllTargets() map[string][]target.Target {
+	result := make(map[string][]target.Target, len(tm.targets))
+	for k, v := range tm.targets {
+		result[k] = []target.Target{v}
+	}
+	return result
+}
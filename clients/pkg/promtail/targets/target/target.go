@@ -44,6 +44,12 @@ const (
 
 	// HerokuDrainTargetType is a Heroku Logs target
 	HerokuDrainTargetType = TargetType("HerokuDrain")
+
+	// KubernetesEventsTargetType is a Kubernetes Events API target
+	KubernetesEventsTargetType = TargetType("KubernetesEvents")
+
+	// OTLPTargetType is an OTLP logs receiver target
+	OTLPTargetType = TargetType("OTLP")
 )
 
 // Target is a promtail scrape target
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 	"unsafe"
@@ -44,6 +45,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
 	"github.com/grafana/loki/v3/pkg/ingester"
 	"github.com/grafana/loki/v3/pkg/ingester/client"
+	"github.com/grafana/loki/v3/pkg/kafka"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/log/logfmt"
@@ -88,6 +90,16 @@ type Config struct {
 	WriteFailuresLogging writefailures.Cfg `yaml:"write_failures_logging" doc:"description=Customize the logging of write failures."`
 
 	OTLPConfig push.GlobalOTLPConfig `yaml:"otlp_config"`
+
+	// KafkaEnabled makes the distributor write streams to Kafka instead of sending them to ingesters.
+	KafkaEnabled bool         `yaml:"kafka_writes_enabled" category:"experimental"`
+	KafkaConfig  kafka.Config `yaml:"-"`
+
+	// For testing.
+	kafkaProducer kafka.Producer `yaml:"-"`
+
+	PriorityClasses     []PriorityClass `yaml:"priority_classes" category:"experimental" doc:"description=Ingestion priority classes. When one of the ingesters a stream is written to reports a load of at least the shed load of the class of the stream, the stream is shed with a 429 response instead of being sent to the ingesters, so the lowest priority classes are shed first. The other streams of the request are still written, even though the response is a 429. The load is only reported by the ingesters with overload_flush_queue_length or overload_memory set. The class of the streams is set by the ingestion_priority_class and ingestion_priority_streams limits."`
+	IngesterLoadTimeout time.Duration   `yaml:"ingester_load_timeout" category:"experimental"`
 }
 
 // RegisterFlags registers distributor-related flags.
@@ -96,11 +108,25 @@ func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
 	cfg.DistributorRing.RegisterFlags(fs)
 	cfg.RateStore.RegisterFlagsWithPrefix("distributor.rate-store", fs)
 	cfg.WriteFailuresLogging.RegisterFlagsWithPrefix("distributor.write-failures-logging", fs)
+	fs.BoolVar(&cfg.KafkaEnabled, "distributor.kafka-writes-enabled", false, "Enable writes to Kafka during Push requests instead of sending them to ingesters. Streams are written to the partition owning their hash in the partition ring. Requires the Kafka configuration block.")
+	fs.DurationVar(&cfg.IngesterLoadTimeout, "distributor.ingester-load-timeout", 10*time.Second, "How long the load reported by an ingester in its push responses is used to shed the streams of the priority classes. Older loads are ignored.")
+}
+
+// Validate validates the distributor config.
+func (cfg *Config) Validate() error {
+	if err := validatePriorityClasses(cfg.PriorityClasses); err != nil {
+		return err
+	}
+	if len(cfg.PriorityClasses) > 0 && cfg.IngesterLoadTimeout <= 0 {
+		return errors.New("the ingester load timeout must be greater than 0 when priority classes are configured")
+	}
+	return nil
 }
 
 // RateStore manages the ingestion rate of streams, populated by data fetched from ingesters.
 type RateStore interface {
 	RateFor(tenantID string, streamHash uint64) (int64, float64)
+	TenantRate(tenantID string) (int, int64)
 }
 
 // Distributor coordinates replicates and distribution of log streams.
@@ -113,6 +139,8 @@ type Distributor struct {
 	tenantConfigs    *runtime.TenantConfigs
 	tenantsRetention *retention.TenantsRetention
 	ingestersRing    ring.ReadRing
+	partitionRing    ring.PartitionRingReader
+	kafkaProducer    kafka.Producer
 	validator        *Validator
 	pool             *ring_client.Pool
 	tee              Tee
@@ -120,6 +148,10 @@ type Distributor struct {
 	rateStore    RateStore
 	shardTracker *ShardTracker
 
+	// Shed loads of the priority classes by name, and the last loads reported by the ingesters.
+	shedLoads     map[string]float64
+	ingesterLoads *ingesterLoads
+
 	// The global rate limiter requires a distributors ring to count
 	// the number of healthy instances.
 	distributorsLifecycler *ring.BasicLifecycler
@@ -133,6 +165,7 @@ type Distributor struct {
 	// Per-user rate limiter.
 	ingestionRateLimiter *limiter.RateLimiter
 	labelCache           *lru.Cache
+	labelCardinality     *labelCardinalityTracker
 
 	// Push failures rate limiter.
 	writeFailuresManager *writefailures.Manager
@@ -144,6 +177,12 @@ type Distributor struct {
 	ingesterAppendTimeouts *prometheus.CounterVec
 	replicationFactor      prometheus.Gauge
 	streamShardCount       prometheus.Counter
+	kafkaAppends           *prometheus.CounterVec
+	kafkaAppendFailures    *prometheus.CounterVec
+	kafkaWriteLatency      prometheus.Histogram
+	kafkaWriteBytesTotal   prometheus.Counter
+	relabeledStreams       *prometheus.CounterVec
+	promotedLabels         *prometheus.CounterVec
 
 	usageTracker push.UsageTracker
 }
@@ -154,6 +193,7 @@ func New(
 	clientCfg client.Config,
 	configs *runtime.TenantConfigs,
 	ingestersRing ring.ReadRing,
+	partitionRing ring.PartitionRingReader,
 	overrides Limits,
 	registerer prometheus.Registerer,
 	metricsNamespace string,
@@ -192,6 +232,19 @@ func New(
 		return nil, err
 	}
 
+	kafkaProducer := cfg.kafkaProducer
+	if cfg.KafkaEnabled {
+		if partitionRing == nil {
+			return nil, fmt.Errorf("partition ring is required for kafka writes")
+		}
+		if kafkaProducer == nil {
+			kafkaProducer, err = kafka.NewProducer(cfg.KafkaConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	d := &Distributor{
 		cfg:                   cfg,
 		logger:                logger,
@@ -199,10 +252,15 @@ func New(
 		tenantConfigs:         configs,
 		tenantsRetention:      retention.NewTenantsRetention(overrides),
 		ingestersRing:         ingestersRing,
+		partitionRing:         partitionRing,
+		kafkaProducer:         kafkaProducer,
 		validator:             validator,
 		pool:                  clientpool.NewPool("ingester", clientCfg.PoolConfig, ingestersRing, factory, logger, metricsNamespace),
 		labelCache:            labelCache,
+		labelCardinality:      newLabelCardinalityTracker(registerer),
 		shardTracker:          NewShardTracker(),
+		shedLoads:             make(map[string]float64, len(cfg.PriorityClasses)),
+		ingesterLoads:         newIngesterLoads(cfg.IngesterLoadTimeout, registerer),
 		healthyInstancesCount: atomic.NewUint32(0),
 		rateLimitStrat:        rateLimitStrat,
 		tee:                   tee,
@@ -227,9 +285,44 @@ func New(
 			Name:      "stream_sharding_count",
 			Help:      "Total number of times the distributor has sharded streams",
 		}),
+		kafkaAppends: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_kafka_appends_total",
+			Help:      "The total number of appends sent to kafka ingest path.",
+		}, []string{"partition"}),
+		kafkaAppendFailures: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_kafka_append_failures_total",
+			Help:      "The total number of failed appends sent to kafka ingest path.",
+		}, []string{"partition"}),
+		kafkaWriteLatency: promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_kafka_latency_seconds",
+			Help:      "Latency to write an incoming request to the ingest storage.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		kafkaWriteBytesTotal: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_kafka_sent_bytes_total",
+			Help:      "Total number of bytes sent to the ingest storage.",
+		}),
+		relabeledStreams: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_ingestion_relabel_streams_total",
+			Help:      "The total number of streams whose labels were changed or which were dropped by the ingestion relabel configs.",
+		}, []string{"tenant", "outcome"}),
+		promotedLabels: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_promoted_high_cardinality_labels_total",
+			Help:      "The total number of streams whose high cardinality label was moved to structured metadata.",
+		}, []string{"tenant", "label"}),
 		writeFailuresManager: writefailures.NewManager(logger, registerer, cfg.WriteFailuresLogging, configs, "distributor"),
 	}
 
+	for _, class := range cfg.PriorityClasses {
+		d.shedLoads[class.Name] = class.ShedLoad
+	}
+
 	if overrides.IngestionRateStrategy() == validation.GlobalIngestionRateStrategy {
 		d.rateLimitStrat = validation.GlobalIngestionRateStrategy
 
@@ -268,7 +361,7 @@ func New(
 	)
 	d.rateStore = rs
 
-	servs = append(servs, d.pool, rs)
+	servs = append(servs, d.pool, rs, d.labelCardinality)
 	d.subservices, err = services.NewManager(servs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "services manager")
@@ -294,7 +387,13 @@ func (d *Distributor) running(ctx context.Context) error {
 }
 
 func (d *Distributor) stopping(_ error) error {
-	return services.StopManagerAndAwaitStopped(context.Background(), d.subservices)
+	err := services.StopManagerAndAwaitStopped(context.Background(), d.subservices)
+	if d.kafkaProducer != nil {
+		if closeErr := d.kafkaProducer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
 type KeyedStream struct {
@@ -305,6 +404,9 @@ type KeyedStream struct {
 // TODO taken from Cortex, see if we can refactor out an usable interface.
 type streamTracker struct {
 	KeyedStream
+	// index and labels are the ones of the stream of the push request the stream comes from.
+	index       int
+	labels      string
 	minSuccess  int
 	maxFailures int
 	succeeded   atomic.Int32
@@ -316,7 +418,30 @@ type pushTracker struct {
 	streamsPending atomic.Int32
 	streamsFailed  atomic.Int32
 	done           chan struct{}
-	err            chan error
+
+	mtx sync.Mutex
+	// err is the first error of the streams which failed.
+	err error
+	// rejected holds the streams rejected by the ingesters.
+	rejected []logproto.RejectedStream
+}
+
+// fail records the failure of the given stream, along with its rejection by the ingesters if any.
+func (p *pushTracker) fail(stream *streamTracker, err error, rejection *logproto.RejectedStream) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+	if rejection != nil {
+		p.rejected = append(p.rejected, logproto.RejectedStream{
+			Index:           int32(stream.index),
+			Labels:          stream.labels,
+			Reason:          rejection.Reason,
+			Message:         rejection.Message,
+			RejectedEntries: rejection.RejectedEntries,
+		})
+	}
 }
 
 // Push a set of streams.
@@ -336,10 +461,15 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	// We use the heuristic of 1 sample per TS to size the array.
 	// We also work out the hash value at the same time.
 	streams := make([]KeyedStream, 0, len(req.Streams))
+	// priorities holds the priority of each of the streams, which are shed when their ingesters are overloaded.
+	priorities := make([]streamPriority, 0, len(req.Streams))
 	validatedLineSize := 0
 	validatedLineCount := 0
 
 	var validationErrors util.GroupedErrors
+	var rejected rejectedStreams
+	// validatedStreams holds the number of valid entries of each stream of the request.
+	validatedStreams := make(map[int]int, len(req.Streams))
 	validationContext := d.validator.getValidationContextForTime(time.Now(), tenantID)
 
 	func() {
@@ -350,7 +480,7 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				sp.LogKV("event", "finished to validate request")
 			}()
 		}
-		for _, stream := range req.Streams {
+		for i, stream := range req.Streams {
 			// Return early if stream does not contain any entries
 			if len(stream.Entries) == 0 {
 				continue
@@ -359,11 +489,11 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			// Truncate first so subsequent steps have consistent line lengths
 			d.truncateLines(validationContext, &stream)
 
-			var lbs labels.Labels
-			lbs, stream.Labels, stream.Hash, err = d.parseStreamLabels(validationContext, stream.Labels, stream)
+			ld, err := d.parseStreamLabels(validationContext, stream.Labels, stream)
 			if err != nil {
 				d.writeFailuresManager.Log(tenantID, err)
 				validationErrors.Add(err)
+				rejected.add(i, stream.Labels, validation.DiscardReason(err, validation.InvalidLabels), err.Error(), len(stream.Entries))
 				validation.DiscardedSamples.WithLabelValues(validation.InvalidLabels, tenantID).Add(float64(len(stream.Entries)))
 				bytes := 0
 				for _, e := range stream.Entries {
@@ -372,6 +502,25 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				validation.DiscardedBytes.WithLabelValues(validation.InvalidLabels, tenantID).Add(float64(bytes))
 				continue
 			}
+			if ld.dropped {
+				d.relabeledStreams.WithLabelValues(tenantID, "dropped").Inc()
+				updateMetrics(validation.RelabelDropped, tenantID, stream)
+				rejected.add(i, stream.Labels, validation.RelabelDropped, relabelDroppedMessage, len(stream.Entries))
+				continue
+			}
+			if ld.relabeled {
+				d.relabeledStreams.WithLabelValues(tenantID, "changed").Inc()
+			}
+			reqLabels := stream.Labels
+			lbs, structuredMetadata := ld.ls, ld.structuredMetadata
+			stream.Labels, stream.Hash = lbs.String(), ld.hash
+			if validationContext.maxLabelValueCardinality > 0 {
+				var promoted []logproto.LabelAdapter
+				if lbs, promoted = d.promoteHighCardinalityLabels(ctx, validationContext, lbs); len(promoted) > 0 {
+					stream.Labels, stream.Hash = lbs.String(), lbs.Hash()
+					structuredMetadata = append(append([]logproto.LabelAdapter{}, structuredMetadata...), promoted...)
+				}
+			}
 
 			n := 0
 			pushSize := 0
@@ -380,9 +529,13 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 			shouldDiscoverLevels := validationContext.allowStructuredMetadata && validationContext.discoverLogLevels
 			levelFromLabel, hasLevelLabel := hasAnyLevelLabels(lbs)
 			for _, entry := range stream.Entries {
+				if len(structuredMetadata) > 0 {
+					entry.StructuredMetadata = append(entry.StructuredMetadata, structuredMetadata...)
+				}
 				if err := d.validator.ValidateEntry(ctx, validationContext, lbs, entry); err != nil {
 					d.writeFailuresManager.Log(tenantID, err)
 					validationErrors.Add(err)
+					rejected.add(i, reqLabels, validation.DiscardReason(err, validation.InvalidLabels), err.Error(), 1)
 					continue
 				}
 
@@ -430,6 +583,7 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				// Empty stream after validating all the entries
 				continue
 			}
+			validatedStreams[i] += n
 
 			shardStreamsCfg := d.validator.Limits.ShardStreams(tenantID)
 			if shardStreamsCfg.Enabled {
@@ -440,6 +594,10 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 					Stream:  stream,
 				})
 			}
+			class, shedLoad := d.shedLoadFor(tenantID, lbs)
+			for len(priorities) < len(streams) {
+				priorities = append(priorities, streamPriority{index: i, labels: reqLabels, class: class, shedLoad: shedLoad})
+			}
 		}
 	}()
 
@@ -448,9 +606,11 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		validationErr = httpgrpc.Errorf(http.StatusBadRequest, validationErrors.Error())
 	}
 
+	resp := &logproto.PushResponse{RejectedStreams: rejected.streams}
+
 	// Return early if none of the streams contained entries
 	if len(streams) == 0 {
-		return &logproto.PushResponse{}, validationErr
+		return resp, validationErr
 	}
 
 	now := time.Now()
@@ -461,8 +621,8 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 
 		if d.usageTracker != nil {
 			for _, stream := range req.Streams {
-				lbs, _, _, err := d.parseStreamLabels(validationContext, stream.Labels, stream)
-				if err != nil {
+				ld, err := d.parseStreamLabels(validationContext, stream.Labels, stream)
+				if err != nil || ld.dropped {
 					continue
 				}
 
@@ -472,14 +632,20 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				}
 
 				if d.usageTracker != nil {
-					d.usageTracker.DiscardedBytesAdd(ctx, tenantID, validation.RateLimited, lbs, float64(discardedStreamBytes))
+					d.usageTracker.DiscardedBytesAdd(ctx, tenantID, validation.RateLimited, ld.ls, float64(discardedStreamBytes))
 				}
 			}
 		}
 
 		err = fmt.Errorf(validation.RateLimitedErrorMsg, tenantID, int(d.ingestionRateLimiter.Limit(now, tenantID)), validatedLineCount, validatedLineSize)
 		d.writeFailuresManager.Log(tenantID, err)
-		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, err.Error())
+		for i, stream := range req.Streams {
+			if n, ok := validatedStreams[i]; ok {
+				rejected.add(i, stream.Labels, validation.RateLimited, err.Error(), n)
+			}
+		}
+		resp.RejectedStreams = rejected.streams
+		return resp, httpgrpc.Errorf(http.StatusTooManyRequests, err.Error())
 	}
 
 	// Nil check for performance reasons, to avoid dynamic lookup and/or no-op
@@ -488,12 +654,22 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		d.tee.Duplicate(tenantID, streams)
 	}
 
+	if d.cfg.KafkaEnabled {
+		if err := d.sendStreamsToKafka(ctx, streams, tenantID); err != nil {
+			return resp, err
+		}
+		return resp, validationErr
+	}
+
 	const maxExpectedReplicationSet = 5 // typical replication factor 3 plus one for inactive plus one for luck
 	var descs [maxExpectedReplicationSet]ring.InstanceDesc
 
 	streamTrackers := make([]streamTracker, len(streams))
 	streamsByIngester := map[string][]*streamTracker{}
 	ingesterDescs := map[string]ring.InstanceDesc{}
+	streamsShed := 0
+	// shedIngesters holds the ingesters of the streams shed, the load of which is probed if they receive no streams.
+	shedIngesters := map[string]ring.InstanceDesc{}
 
 	if err := func() error {
 		sp := opentracing.SpanFromContext(ctx)
@@ -510,8 +686,21 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				return err
 			}
 
+			if p := priorities[i]; p.shedLoad > 0 {
+				if load := d.ingesterLoads.max(replicationSet); load >= p.shedLoad {
+					shedStream(&rejected, p.index, p.labels, tenantID, p.class, load, stream)
+					streamsShed++
+					for _, ingester := range replicationSet.Instances {
+						shedIngesters[ingester.Addr] = ingester
+					}
+					continue
+				}
+			}
+
 			streamTrackers[i] = streamTracker{
 				KeyedStream: stream,
+				index:       priorities[i].index,
+				labels:      priorities[i].labels,
 				minSuccess:  len(replicationSet.Instances) - replicationSet.MaxErrors,
 				maxFailures: replicationSet.MaxErrors,
 			}
@@ -522,14 +711,34 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		}
 		return nil
 	}(); err != nil {
-		return nil, err
+		resp.RejectedStreams = rejected.streams
+		return resp, err
+	}
+
+	for addr, ingester := range shedIngesters {
+		if _, ok := streamsByIngester[addr]; !ok && d.ingesterLoads.probe(addr) {
+			go d.probeIngesterLoad(tenantID, ingester)
+		}
+	}
+
+	if streamsShed > 0 {
+		resp.RejectedStreams = rejected.streams
+		err := fmt.Errorf("%d streams shed because their ingesters are overloaded, retry later", streamsShed)
+		d.writeFailuresManager.Log(tenantID, err)
+		// The 429 takes precedence over the validation errors, so the clients retry the streams shed. The other
+		// streams are still written, even though the request fails: the clients retrying the whole request push
+		// their entries again, which the ingesters ignore as duplicates, and the rejected streams of the response
+		// list the streams shed for the clients retrying only them.
+		validationErr = httpgrpc.Errorf(http.StatusTooManyRequests, err.Error())
+		if streamsShed == len(streams) {
+			return resp, validationErr
+		}
 	}
 
 	tracker := pushTracker{
 		done: make(chan struct{}, 1), // buffer avoids blocking if caller terminates - sendSamples() only sends once on each
-		err:  make(chan error, 1),
 	}
-	tracker.streamsPending.Store(int32(len(streams)))
+	tracker.streamsPending.Store(int32(len(streams) - streamsShed))
 	for ingester, streams := range streamsByIngester {
 		go func(ingester ring.InstanceDesc, samples []*streamTracker) {
 			// Use a background context to make sure all ingesters get samples even if we return early
@@ -543,15 +752,64 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		}(ingesterDescs[ingester], streams)
 	}
 	select {
-	case err := <-tracker.err:
-		return nil, err
 	case <-tracker.done:
-		return &logproto.PushResponse{}, validationErr
+		// The streams rejected by the ingesters are reported along with the ones rejected by the distributor.
+		sort.SliceStable(tracker.rejected, func(i, j int) bool {
+			return tracker.rejected[i].Index < tracker.rejected[j].Index
+		})
+		for _, r := range tracker.rejected {
+			rejected.add(int(r.Index), r.Labels, r.Reason, r.Message, int(r.RejectedEntries))
+		}
+		resp.RejectedStreams = rejected.streams
+		if tracker.err != nil {
+			return resp, tracker.err
+		}
+		return resp, validationErr
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return resp, ctx.Err()
 	}
 }
 
+// sendStreamsToKafka writes the streams to the partitions owning their hash key.
+// It returns once every record has been acknowledged by Kafka.
+func (d *Distributor) sendStreamsToKafka(ctx context.Context, streams []KeyedStream, tenantID string) error {
+	partitionRing := d.partitionRing.PartitionRing()
+
+	var records []*kafka.Record
+	for _, stream := range streams {
+		partitionID, err := partitionRing.ActivePartitionForKey(stream.HashKey)
+		if err != nil {
+			return fmt.Errorf("failed to find active partition for stream: %w", err)
+		}
+		streamRecords, err := kafka.Encode(partitionID, tenantID, stream.Stream, d.cfg.KafkaConfig.ProducerMaxRecordSizeBytes)
+		if err != nil {
+			return httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		records = append(records, streamRecords...)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.KafkaConfig.WriteTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := d.kafkaProducer.Produce(ctx, records)
+	d.kafkaWriteLatency.Observe(time.Since(start).Seconds())
+
+	for _, record := range records {
+		partition := strconv.Itoa(int(record.Partition))
+		if err != nil {
+			d.kafkaAppendFailures.WithLabelValues(partition).Inc()
+			continue
+		}
+		d.kafkaAppends.WithLabelValues(partition).Inc()
+		d.kafkaWriteBytesTotal.Add(float64(len(record.Value)))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write to kafka: %w", err)
+	}
+	return nil
+}
+
 func hasAnyLevelLabels(l labels.Labels) (string, bool) {
 	for lbl := range allowedLabelsForLevel {
 		if l.Has(lbl) {
@@ -700,41 +958,59 @@ func (d *Distributor) truncateLines(vContext validationContext, stream *logproto
 
 // TODO taken from Cortex, see if we can refactor out an usable interface.
 func (d *Distributor) sendStreams(ctx context.Context, ingester ring.InstanceDesc, streamTrackers []*streamTracker, pushTracker *pushTracker) {
-	err := d.sendStreamsErr(ctx, ingester, streamTrackers)
+	rejections, err := d.sendStreamsErr(ctx, ingester, streamTrackers)
 
 	// If we succeed, decrement each stream's pending count by one.
 	// If we reach the required number of successful puts on this stream, then
-	// decrement the number of pending streams by one.
-	// If we successfully push all streams to min success ingesters, wake up the
-	// waiting rpc so it can return early. Similarly, track the number of errors,
-	// and if it exceeds maxFailures shortcut the waiting rpc.
+	// decrement the number of pending streams by one. Similarly, track the number
+	// of errors, and once it exceeds maxFailures record the failure of the stream
+	// and decrement the number of pending streams by one.
+	// Once every stream succeeded or failed, wake up the waiting rpc, so it can
+	// report the streams rejected by the ingesters along with the accepted ones.
 	//
 	// The use of atomic increments here guarantees only a single sendStreams
-	// goroutine will write to either channel.
+	// goroutine will write to the channel.
 	for i := range streamTrackers {
-		if err != nil {
-			if streamTrackers[i].failed.Inc() <= int32(streamTrackers[i].maxFailures) {
-				continue
-			}
-			if pushTracker.streamsFailed.Inc() == 1 {
-				pushTracker.err <- err
-			}
-		} else {
-			if streamTrackers[i].succeeded.Inc() != int32(streamTrackers[i].minSuccess) {
+		streamErr := err
+		rejection, rejected := rejections[i]
+		if rejected && streamErr == nil {
+			streamErr = rejectionError(rejection)
+		}
+		if streamErr != nil {
+			if streamTrackers[i].failed.Inc() != int32(streamTrackers[i].maxFailures)+1 {
 				continue
 			}
-			if pushTracker.streamsPending.Dec() == 0 {
-				pushTracker.done <- struct{}{}
+			pushTracker.streamsFailed.Inc()
+			if rejected {
+				pushTracker.fail(streamTrackers[i], streamErr, &rejection)
+			} else {
+				pushTracker.fail(streamTrackers[i], streamErr, nil)
 			}
+		} else if streamTrackers[i].succeeded.Inc() != int32(streamTrackers[i].minSuccess) {
+			continue
 		}
+		if pushTracker.streamsPending.Dec() == 0 {
+			pushTracker.done <- struct{}{}
+		}
+	}
+}
+
+// rejectionError returns the error of a stream rejected by an ingester.
+func rejectionError(rejection logproto.RejectedStream) error {
+	switch rejection.Reason {
+	case validation.StreamRateLimit, validation.StreamLimit:
+		return httpgrpc.Errorf(http.StatusTooManyRequests, rejection.Message)
+	default:
+		return httpgrpc.Errorf(http.StatusBadRequest, rejection.Message)
 	}
 }
 
 // TODO taken from Cortex, see if we can refactor out an usable interface.
-func (d *Distributor) sendStreamsErr(ctx context.Context, ingester ring.InstanceDesc, streams []*streamTracker) error {
+// The streams rejected by the ingester are returned keyed by their position in streams.
+func (d *Distributor) sendStreamsErr(ctx context.Context, ingester ring.InstanceDesc, streams []*streamTracker) (map[int]logproto.RejectedStream, error) {
 	c, err := d.pool.GetClientFor(ingester.Addr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req := &logproto.PushRequest{
@@ -744,7 +1020,7 @@ func (d *Distributor) sendStreamsErr(ctx context.Context, ingester ring.Instance
 		req.Streams[i] = s.Stream
 	}
 
-	_, err = c.(logproto.PusherClient).Push(ctx, req)
+	resp, err := c.(logproto.PusherClient).Push(client.InjectRejectedStreams(ctx), req)
 	d.ingesterAppends.WithLabelValues(ingester.Addr).Inc()
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
@@ -753,34 +1029,104 @@ func (d *Distributor) sendStreamsErr(ctx context.Context, ingester ring.Instance
 				d.ingesterAppendTimeouts.WithLabelValues(ingester.Addr).Inc()
 			}
 		}
+		return nil, err
 	}
-	return err
+	d.ingesterLoads.update(ingester.Addr, resp.GetLoad())
+
+	var rejections map[int]logproto.RejectedStream
+	for _, r := range resp.GetRejectedStreams() {
+		if r.Index < 0 || int(r.Index) >= len(streams) {
+			continue
+		}
+		if rejections == nil {
+			rejections = make(map[int]logproto.RejectedStream, len(resp.GetRejectedStreams()))
+		}
+		rejections[int(r.Index)] = r
+	}
+	return rejections, nil
 }
 
 type labelData struct {
 	ls   labels.Labels
 	hash uint64
+
+	// Outcome of the ingestion relabel configs the labels were computed with.
+	relabelConfigsHash uint64
+	structuredMetadata []logproto.LabelAdapter
+	relabeled          bool
+	dropped            bool
 }
 
-func (d *Distributor) parseStreamLabels(vContext validationContext, key string, stream logproto.Stream) (labels.Labels, string, uint64, error) {
-	if val, ok := d.labelCache.Get(key); ok {
+func (d *Distributor) parseStreamLabels(vContext validationContext, key string, stream logproto.Stream) (labelData, error) {
+	cacheKey := key
+	if len(vContext.ingestionRelabelConfigs) > 0 {
+		// The relabeled labels depend on the tenant configuration.
+		cacheKey = vContext.userID + "\xff" + key
+	}
+	if val, ok := d.labelCache.Get(cacheKey); ok {
 		labelVal := val.(labelData)
-		return labelVal.ls, labelVal.ls.String(), labelVal.hash, nil
+		// Cached labels are stale when the relabel configs were reloaded since.
+		if labelVal.relabelConfigsHash == vContext.ingestionRelabelConfigsHash {
+			return labelVal, nil
+		}
 	}
 
 	ls, err := syntax.ParseLabels(key)
 	if err != nil {
-		return nil, "", 0, fmt.Errorf(validation.InvalidLabelsErrorMsg, key, err)
+		return labelData{}, fmt.Errorf(validation.InvalidLabelsErrorMsg, key, err)
+	}
+
+	ld := labelData{relabelConfigsHash: vContext.ingestionRelabelConfigsHash}
+	if len(vContext.ingestionRelabelConfigs) > 0 {
+		relabeled, structuredMetadata, keep := relabelStream(vContext.ingestionRelabelConfigs, ls)
+		if !keep {
+			ld.dropped = true
+			d.labelCache.Add(cacheKey, ld)
+			return ld, nil
+		}
+		ld.relabeled = !labels.Equal(ls, relabeled)
+		ld.structuredMetadata = structuredMetadata
+		ls = relabeled
 	}
 
 	if err := d.validator.ValidateLabels(vContext, ls, stream); err != nil {
-		return nil, "", 0, err
+		return labelData{}, err
+	}
+
+	ld.ls, ld.hash = ls, ls.Hash()
+	d.labelCache.Add(cacheKey, ld)
+	return ld, nil
+}
+
+// promoteHighCardinalityLabels reports the high cardinality labels of a stream in the push response warnings.
+// Depending on the tenant policy, it also moves them to structured metadata and returns them.
+func (d *Distributor) promoteHighCardinalityLabels(ctx context.Context, vContext validationContext, ls labels.Labels) (labels.Labels, []logproto.LabelAdapter) {
+	names := d.labelCardinality.observe(vContext.userID, ls, vContext.maxLabelValueCardinality)
+	if len(names) == 0 {
+		return ls, nil
 	}
 
-	lsHash := ls.Hash()
+	promote := vContext.highCardinalityLabelsPolicy == validation.HighCardinalityLabelsPolicyStructuredMetadata && vContext.allowStructuredMetadata
+	// A stream needs at least one label.
+	if promote && len(names) == len(ls) {
+		promote = false
+	}
+	if !promote {
+		for _, name := range names {
+			addPushWarning(ctx, fmt.Sprintf(highCardinalityLabelWarning, name, vContext.maxLabelValueCardinality))
+		}
+		return ls, nil
+	}
 
-	d.labelCache.Add(key, labelData{ls, lsHash})
-	return ls, ls.String(), lsHash, nil
+	lb := labels.NewBuilder(ls)
+	promoted := make([]logproto.LabelAdapter, 0, len(names))
+	for _, name := range names {
+		addPushWarning(ctx, fmt.Sprintf(promotedLabelWarning, name, vContext.maxLabelValueCardinality))
+		d.promotedLabels.WithLabelValues(vContext.userID, name).Inc()
+		promoted = append(promoted, logproto.LabelAdapter{Name: name, Value: ls.Get(name)})
+		lb.Del(name)
+	}
+	return lb.Labels(), promoted
 }
 
 // shardCountFor returns the right number of shards to be used by the given stream.
@@ -2,6 +2,7 @@ package distributor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -34,15 +35,19 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/ingester"
 	"github.com/grafana/loki/v3/pkg/ingester/client"
+	"github.com/grafana/loki/v3/pkg/kafka"
+	"github.com/grafana/loki/v3/pkg/kafka/kafkatest"
 	loghttp_push "github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/ruler/util"
 	"github.com/grafana/loki/v3/pkg/runtime"
 	"github.com/grafana/loki/v3/pkg/util/constants"
 	fe "github.com/grafana/loki/v3/pkg/util/flagext"
 	loki_flagext "github.com/grafana/loki/v3/pkg/util/flagext"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 	loki_net "github.com/grafana/loki/v3/pkg/util/net"
+	lokiring "github.com/grafana/loki/v3/pkg/util/ring"
 	"github.com/grafana/loki/v3/pkg/util/test"
 	"github.com/grafana/loki/v3/pkg/validation"
 )
@@ -69,30 +74,40 @@ func TestDistributor(t *testing.T) {
 			expectedResponse: success,
 		},
 		{
-			lines:          100,
-			streams:        1,
+			lines:   100,
+			streams: 1,
+			expectedResponse: &logproto.PushResponse{RejectedStreams: []logproto.RejectedStream{
+				{Index: 0, Labels: `{foo="bar"}`, Reason: validation.RateLimited, Message: fmt.Sprintf(validation.RateLimitedErrorMsg, "test", 100, 100, 1000), RejectedEntries: 100},
+			}},
 			expectedErrors: []error{httpgrpc.Errorf(http.StatusTooManyRequests, validation.RateLimitedErrorMsg, "test", 100, 100, 1000)},
 		},
 		{
-			lines:            100,
-			streams:          1,
-			maxLineSize:      1,
-			expectedResponse: success,
-			expectedErrors:   []error{httpgrpc.Errorf(http.StatusBadRequest, "100 errors like: %s", fmt.Sprintf(validation.LineTooLongErrorMsg, 1, "{foo=\"bar\"}", 10))},
+			lines:       100,
+			streams:     1,
+			maxLineSize: 1,
+			expectedResponse: &logproto.PushResponse{RejectedStreams: []logproto.RejectedStream{
+				{Index: 0, Labels: `{foo="bar"}`, Reason: validation.LineTooLong, Message: fmt.Sprintf(validation.LineTooLongErrorMsg, 1, "{foo=\"bar\"}", 10), RejectedEntries: 100},
+			}},
+			expectedErrors: []error{httpgrpc.Errorf(http.StatusBadRequest, "100 errors like: %s", fmt.Sprintf(validation.LineTooLongErrorMsg, 1, "{foo=\"bar\"}", 10))},
 		},
 		{
-			lines:            100,
-			streams:          1,
-			mangleLabels:     1,
-			expectedResponse: success,
-			expectedErrors:   []error{httpgrpc.Errorf(http.StatusBadRequest, validation.InvalidLabelsErrorMsg, "{ab\"", "1:4: parse error: unterminated quoted string")},
+			lines:        100,
+			streams:      1,
+			mangleLabels: 1,
+			expectedResponse: &logproto.PushResponse{RejectedStreams: []logproto.RejectedStream{
+				{Index: 0, Labels: `{ab"`, Reason: validation.InvalidLabels, Message: fmt.Sprintf(validation.InvalidLabelsErrorMsg, "{ab\"", "1:4: parse error: unterminated quoted string"), RejectedEntries: 100},
+			}},
+			expectedErrors: []error{httpgrpc.Errorf(http.StatusBadRequest, validation.InvalidLabelsErrorMsg, "{ab\"", "1:4: parse error: unterminated quoted string")},
 		},
 		{
-			lines:            10,
-			streams:          2,
-			mangleLabels:     1,
-			maxLineSize:      1,
-			expectedResponse: success,
+			lines:        10,
+			streams:      2,
+			mangleLabels: 1,
+			maxLineSize:  1,
+			expectedResponse: &logproto.PushResponse{RejectedStreams: []logproto.RejectedStream{
+				{Index: 0, Labels: `{ab"`, Reason: validation.InvalidLabels, Message: fmt.Sprintf(validation.InvalidLabelsErrorMsg, "{ab\"", "1:4: parse error: unterminated quoted string"), RejectedEntries: 10},
+				{Index: 1, Labels: `{foo="bar"}`, Reason: validation.LineTooLong, Message: fmt.Sprintf(validation.LineTooLongErrorMsg, 1, "{foo=\"bar\"}", 10), RejectedEntries: 10},
+			}},
 			expectedErrors: []error{
 				httpgrpc.Errorf(http.StatusBadRequest, ""),
 				fmt.Errorf("1 errors like: %s", fmt.Sprintf(validation.InvalidLabelsErrorMsg, "{ab\"", "1:4: parse error: unterminated quoted string")),
@@ -562,6 +577,73 @@ func Test_DiscardEmptyStreamsAfterValidation(t *testing.T) {
 	})
 }
 
+func Test_IngestionRelabelConfigs(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.DiscoverLogLevels = false
+	limits.IngestionRelabelConfigs = []*validation.IngestionRelabelConfig{
+		{RelabelConfig: util.RelabelConfig{SourceLabels: []string{"env"}, Regex: "debug", Action: "drop"}},
+		{RelabelConfig: util.RelabelConfig{SourceLabels: []string{"host"}, TargetLabel: "hostname"}},
+		{RelabelConfig: util.RelabelConfig{Regex: "host", Action: "labeldrop"}},
+		{RelabelConfig: util.RelabelConfig{Regex: "trace_id", Action: validation.StructuredMetadataAction}},
+	}
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	request := makeWriteRequest(1, 10)
+	request.Streams[0].Labels = `{app="foo", host="h1", trace_id="abc"}`
+	_, err := distributors[0].Push(ctx, request)
+	require.NoError(t, err)
+	topVal := ingester.Peek()
+	require.Equal(t, `{app="foo", hostname="h1"}`, topVal.Streams[0].Labels)
+	require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "abc"}}, topVal.Streams[0].Entries[0].StructuredMetadata)
+
+	// Streams dropped by a relabel config are discarded without failing the request.
+	pushed := len(ingester.pushed)
+	request = makeWriteRequest(1, 10)
+	request.Streams[0].Labels = `{app="foo", env="debug"}`
+	_, err = distributors[0].Push(ctx, request)
+	require.NoError(t, err)
+	require.Len(t, ingester.pushed, pushed)
+}
+
+func Test_IngestionRelabelConfigsReload(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	distributors, _ := prepare(t, 1, 5, limits, nil)
+	d := distributors[0]
+
+	// Reloading the overrides allocates new configs, with the same length.
+	validationContextFor := func(targetLabel string) validationContext {
+		reloaded := &validation.Limits{}
+		flagext.DefaultValues(reloaded)
+		reloaded.IngestionRelabelConfigs = []*validation.IngestionRelabelConfig{
+			{RelabelConfig: util.RelabelConfig{SourceLabels: []string{"host"}, TargetLabel: targetLabel}},
+		}
+		require.NoError(t, reloaded.Validate())
+
+		vContext := d.validator.getValidationContextForTime(time.Now(), "test")
+		vContext.ingestionRelabelConfigs = reloaded.IngestionRelabelConfigs
+		vContext.ingestionRelabelConfigsHash = validation.IngestionRelabelConfigsHash(reloaded.IngestionRelabelConfigs)
+		return vContext
+	}
+
+	stream := logproto.Stream{Labels: `{host="h1"}`}
+	ld, err := d.parseStreamLabels(validationContextFor("hostname"), stream.Labels, stream)
+	require.NoError(t, err)
+	require.Equal(t, `{host="h1", hostname="h1"}`, ld.ls.String())
+
+	// The cached labels are reused while the configs have the same content.
+	require.Equal(t, validationContextFor("hostname").ingestionRelabelConfigsHash, validationContextFor("hostname").ingestionRelabelConfigsHash)
+	require.NotEqual(t, validationContextFor("hostname").ingestionRelabelConfigsHash, validationContextFor("node").ingestionRelabelConfigsHash)
+
+	ld, err = d.parseStreamLabels(validationContextFor("node"), stream.Labels, stream)
+	require.NoError(t, err)
+	require.Equal(t, `{host="h1", node="h1"}`, ld.ls.String())
+}
+
 func TestStreamShard(t *testing.T) {
 	// setup base stream.
 	baseStream := logproto.Stream{}
@@ -820,7 +902,7 @@ func Benchmark_SortLabelsOnPush(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		stream := request.Streams[0]
 		stream.Labels = `{buzz="f", a="b"}`
-		_, _, _, err := d.parseStreamLabels(vCtx, stream.Labels, stream)
+		_, err := d.parseStreamLabels(vCtx, stream.Labels, stream)
 		if err != nil {
 			panic("parseStreamLabels fail,err:" + err.Error())
 		}
@@ -866,7 +948,7 @@ func TestParseStreamLabels(t *testing.T) {
 		vCtx := d.validator.getValidationContextForTime(testTime, "123")
 
 		t.Run(tc.name, func(t *testing.T) {
-			lbs, lbsString, hash, err := d.parseStreamLabels(vCtx, tc.origLabels, logproto.Stream{
+			ld, err := d.parseStreamLabels(vCtx, tc.origLabels, logproto.Stream{
 				Labels: tc.origLabels,
 			})
 			if tc.expectedErr != nil {
@@ -874,9 +956,9 @@ func TestParseStreamLabels(t *testing.T) {
 				return
 			}
 			require.NoError(t, err)
-			require.Equal(t, tc.expectedLabels.String(), lbsString)
-			require.Equal(t, tc.expectedLabels, lbs)
-			require.Equal(t, tc.expectedLabels.Hash(), hash)
+			require.Equal(t, tc.expectedLabels.String(), ld.ls.String())
+			require.Equal(t, tc.expectedLabels, ld.ls)
+			require.Equal(t, tc.expectedLabels.Hash(), ld.hash)
 		})
 	}
 }
@@ -1062,7 +1144,7 @@ func TestShardCountFor(t *testing.T) {
 			limits.ShardStreams.DesiredRate = tc.desiredRate
 
 			d := &Distributor{
-				rateStore: &fakeRateStore{tc.rate, tc.pushRate},
+				rateStore: &fakeRateStore{rate: tc.rate, pushRate: tc.pushRate},
 			}
 			got := d.shardCountFor(util_log.Logger, tc.stream, tc.pushSize, "fake", limits.ShardStreams)
 			require.Equal(t, tc.wantShards, got)
@@ -1163,7 +1245,8 @@ func TestDistributor_PushIngestionRateLimiter(t *testing.T) {
 					assert.Equal(t, success, response)
 					assert.Nil(t, err)
 				} else {
-					assert.Nil(t, response)
+					require.Len(t, response.RejectedStreams, 1)
+					assert.Equal(t, validation.RateLimited, response.RejectedStreams[0].Reason)
 					assert.Equal(t, push.expectedError, err)
 				}
 			}
@@ -1171,6 +1254,115 @@ func TestDistributor_PushIngestionRateLimiter(t *testing.T) {
 	}
 }
 
+func TestDistributor_PushShedsPriorityClasses(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.IngestionPriorityClass = "high"
+	matchers, err := syntax.ParseMatchers(`{app="debug"}`, true)
+	require.NoError(t, err)
+	limits.IngestionPriorityStreams = []validation.IngestionPriorityStream{
+		{Selector: `{app="debug"}`, Class: "low", Matchers: matchers},
+	}
+
+	distributors, ingesters := prepare(t, 1, 5, limits, nil)
+	d := distributors[0]
+	d.shedLoads = map[string]float64{"low": 0.8, "high": 1.5}
+	setLoad := func(load float64) {
+		for i := range ingesters {
+			d.ingesterLoads.update(fmt.Sprintf("ingester-%d", i), load)
+		}
+	}
+	pushedStreams := func() map[string]int {
+		pushed := map[string]int{}
+		for i := range ingesters {
+			ingesters[i].mu.Lock()
+			for _, req := range ingesters[i].pushed {
+				for _, s := range req.Streams {
+					pushed[s.Labels]++
+				}
+			}
+			ingesters[i].pushed = nil
+			ingesters[i].mu.Unlock()
+		}
+		return pushed
+	}
+	request := func() *logproto.PushRequest {
+		return makeWriteRequestWithLabels(10, 10, []string{`{app="debug"}`, `{app="api"}`})
+	}
+
+	// Only the streams of the classes shed at the load of their ingesters are shed.
+	setLoad(0.9)
+	response, err := d.Push(ctx, request())
+	require.Error(t, err)
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusTooManyRequests), resp.Code)
+	require.Len(t, response.RejectedStreams, 1)
+	require.Equal(t, int32(0), response.RejectedStreams[0].Index)
+	require.Equal(t, validation.IngestersOverloaded, response.RejectedStreams[0].Reason)
+	require.Equal(t, int32(10), response.RejectedStreams[0].RejectedEntries)
+	// The other streams are sent to their ingesters.
+	pushed := pushedStreams()
+	require.GreaterOrEqual(t, pushed[`{app="api"}`], 2)
+	require.Zero(t, pushed[`{app="debug"}`])
+
+	// Nothing is shed once the ingesters are no longer overloaded.
+	setLoad(0.5)
+	response, err = d.Push(ctx, request())
+	require.NoError(t, err)
+	require.Empty(t, response.RejectedStreams)
+
+	// Nor when the ingesters stop reporting their load.
+	setLoad(0.9)
+	d.ingesterLoads.timeout = 0
+	response, err = d.Push(ctx, request())
+	require.NoError(t, err)
+	require.Empty(t, response.RejectedStreams)
+
+	// The ingesters all the streams of which are shed are probed for their load, so their streams stop being shed
+	// once they are no longer overloaded.
+	d.ingesterLoads.timeout = time.Minute
+	pushedStreams()
+	d.ingesterLoads.mtx.Lock()
+	for i := range ingesters {
+		d.ingesterLoads.loads[fmt.Sprintf("ingester-%d", i)] = ingesterLoad{load: 0.9, updatedAt: time.Now().Add(-2 * loadProbePeriod)}
+		ingesters[i].load = 0.5
+	}
+	d.ingesterLoads.mtx.Unlock()
+	_, err = d.Push(ctx, makeWriteRequestWithLabels(10, 10, []string{`{app="debug"}`}))
+	require.Error(t, err)
+	require.Eventually(t, func() bool {
+		_, err := d.Push(ctx, makeWriteRequestWithLabels(10, 10, []string{`{app="debug"}`}))
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	require.GreaterOrEqual(t, pushedStreams()[`{app="debug"}`], 2)
+}
+
+func TestDistributor_PushIngesterRejectedStreams(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "test")
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+
+	distributors, ingesters := prepare(t, 1, 5, limits, nil)
+	for i := range ingesters {
+		ingesters[i].rejected = map[string]string{`{app="debug"}`: validation.StreamRateLimit}
+	}
+
+	request := makeWriteRequestWithLabels(10, 10, []string{`{app="api"}`, `{app="debug"}`})
+	response, err := distributors[0].Push(ctx, request)
+	require.Error(t, err)
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusTooManyRequests), resp.Code)
+	require.Equal(t, []logproto.RejectedStream{{
+		Index:           1,
+		Labels:          `{app="debug"}`,
+		Reason:          validation.StreamRateLimit,
+		Message:         "rejected by the ingester",
+		RejectedEntries: 10,
+	}}, response.RejectedStreams)
+}
+
 func prepare(t *testing.T, numDistributors, numIngesters int, limits *validation.Limits, factory func(addr string) (ring_client.PoolClient, error)) ([]*Distributor, []mockIngester) {
 	t.Helper()
 
@@ -1245,7 +1437,7 @@ func prepare(t *testing.T, numDistributors, numIngesters int, limits *validation
 		overrides, err := validation.NewOverrides(*limits, nil)
 		require.NoError(t, err)
 
-		d, err := New(distributorConfig, clientConfig, runtime.DefaultTenantConfigs(), ingestersRing, overrides, prometheus.NewPedanticRegistry(), constants.Loki, nil, nil, log.NewNopLogger())
+		d, err := New(distributorConfig, clientConfig, runtime.DefaultTenantConfigs(), ingestersRing, nil, overrides, prometheus.NewPedanticRegistry(), constants.Loki, nil, nil, log.NewNopLogger())
 		require.NoError(t, err)
 		require.NoError(t, services.StartAndAwaitRunning(context.Background(), d))
 		distributors[i] = d
@@ -1326,8 +1518,12 @@ type mockIngester struct {
 
 	failAfter    time.Duration
 	succeedAfter time.Duration
-	mu           sync.Mutex
-	pushed       []*logproto.PushRequest
+	// rejected holds the reasons the streams with the given labels are rejected for.
+	rejected map[string]string
+	// load is the load reported in the push responses.
+	load   float64
+	mu     sync.Mutex
+	pushed []*logproto.PushRequest
 }
 
 func (i *mockIngester) Push(_ context.Context, in *logproto.PushRequest, _ ...grpc.CallOption) (*logproto.PushResponse, error) {
@@ -1343,7 +1539,20 @@ func (i *mockIngester) Push(_ context.Context, in *logproto.PushRequest, _ ...gr
 	defer i.mu.Unlock()
 
 	i.pushed = append(i.pushed, in)
-	return nil, nil
+
+	resp := &logproto.PushResponse{Load: i.load}
+	for idx, stream := range in.Streams {
+		if reason, ok := i.rejected[stream.Labels]; ok {
+			resp.RejectedStreams = append(resp.RejectedStreams, logproto.RejectedStream{
+				Index:           int32(idx),
+				Labels:          stream.Labels,
+				Reason:          reason,
+				Message:         "rejected by the ingester",
+				RejectedEntries: int32(len(stream.Entries)),
+			})
+		}
+	}
+	return resp, nil
 }
 
 func (i *mockIngester) Peek() *logproto.PushRequest {
@@ -1368,12 +1577,17 @@ func (i *mockIngester) Close() error {
 type fakeRateStore struct {
 	rate     int64
 	pushRate float64
+	streams  int
 }
 
 func (s *fakeRateStore) RateFor(_ string, _ uint64) (int64, float64) {
 	return s.rate, s.pushRate
 }
 
+func (s *fakeRateStore) TenantRate(_ string) (int, int64) {
+	return s.streams, s.rate * int64(s.streams)
+}
+
 type mockTee struct {
 	mu         sync.Mutex
 	duplicated [][]KeyedStream
@@ -1440,6 +1654,90 @@ func TestDistributorTee(t *testing.T) {
 	}
 }
 
+type mockPartitionRingReader struct {
+	partitionRing *ring.PartitionRing
+}
+
+func (m mockPartitionRingReader) PartitionRing() *ring.PartitionRing {
+	return m.partitionRing
+}
+
+func TestDistributor_PushToKafka(t *testing.T) {
+	desc := ring.NewPartitionRingDesc()
+	desc.AddPartition(0, ring.PartitionActive, time.Now())
+	desc.AddPartition(1, ring.PartitionActive, time.Now())
+	partitionRing := ring.NewPartitionRing(*desc)
+
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	distributors, ingesters := prepare(t, 1, 3, limits, nil)
+
+	cluster := kafkatest.NewCluster()
+	d := distributors[0]
+	d.cfg.KafkaEnabled = true
+	flagext.DefaultValues(&d.cfg.KafkaConfig)
+	d.kafkaProducer = cluster
+	d.partitionRing = mockPartitionRingReader{partitionRing: partitionRing}
+
+	request := makeWriteRequestWithLabels(10, 64, []string{`{app="foo"}`, `{app="bar"}`, `{app="baz"}`})
+	_, err := d.Push(ctx, request)
+	require.NoError(t, err)
+
+	// Every stream is written to the partition owning its hash, and nothing is sent to ingesters.
+	decoder := kafka.NewDecoder()
+	received := map[string]int{}
+	for _, partitionID := range []int32{0, 1} {
+		for _, record := range cluster.Records(partitionID) {
+			require.Equal(t, "test", record.TenantID)
+			stream, _, err := decoder.Decode(record.Value)
+			require.NoError(t, err)
+
+			expected, err := partitionRing.ActivePartitionForKey(lokiring.TokenFor("test", stream.Labels))
+			require.NoError(t, err)
+			require.Equal(t, expected, partitionID)
+			received[stream.Labels] += len(stream.Entries)
+		}
+	}
+	require.Equal(t, map[string]int{`{app="foo"}`: 10, `{app="bar"}`: 10, `{app="baz"}`: 10}, received)
+	for i := range ingesters {
+		require.Empty(t, ingesters[i].pushed)
+	}
+
+	// Failing writes are returned to the client.
+	cluster.SetProduceError(errors.New("kafka unavailable"))
+	_, err = d.Push(ctx, request)
+	require.ErrorContains(t, err, "kafka unavailable")
+}
+
+func TestDistributor_PushToKafkaFailure(t *testing.T) {
+	desc := ring.NewPartitionRingDesc()
+	desc.AddPartition(0, ring.PartitionActive, time.Now())
+	partitionRing := ring.NewPartitionRing(*desc)
+
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	distributors, _ := prepare(t, 1, 3, limits, nil)
+
+	cluster := kafkatest.NewCluster()
+	cluster.SetProduceError(errors.New("kafka unavailable"))
+	d := distributors[0]
+	d.cfg.KafkaEnabled = true
+	flagext.DefaultValues(&d.cfg.KafkaConfig)
+	d.kafkaProducer = cluster
+	d.partitionRing = mockPartitionRingReader{partitionRing: partitionRing}
+
+	// The streams rejected by the validation are still reported when the
+	// write to Kafka fails.
+	request := makeWriteRequestWithLabels(10, 64, []string{`{app="foo"}`, `{ab"`})
+	response, err := d.Push(ctx, request)
+	require.ErrorContains(t, err, "kafka unavailable")
+	require.NotNil(t, response)
+	require.Len(t, response.RejectedStreams, 1)
+	require.Equal(t, int32(1), response.RejectedStreams[0].Index)
+	require.Equal(t, validation.InvalidLabels, response.RejectedStreams[0].Reason)
+	require.Empty(t, cluster.Records(0))
+}
+
 func Test_DetectLogLevels(t *testing.T) {
 	setup := func(discoverLogLevels bool) (*validation.Limits, *mockIngester) {
 		limits := &validation.Limits{}
@@ -1,30 +1,38 @@
 package distributor
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/httpgrpc"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"golang.org/x/time/rate"
 
 	"github.com/grafana/loki/v3/pkg/util"
 
 	"github.com/grafana/dskit/tenant"
 
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/logproto"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 	"github.com/grafana/loki/v3/pkg/validation"
 )
 
 // PushHandler reads a snappy-compressed proto from the HTTP body.
 func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
-	d.pushHandler(w, r, push.ParseLokiRequest)
+	d.pushHandler(w, r, push.ParseLokiRequest, writeRejectedStreams)
 }
 
 func (d *Distributor) OTLPPushHandler(w http.ResponseWriter, r *http.Request) {
 	interceptor := newOtelErrorHeaderInterceptor(w)
-	d.pushHandler(interceptor, r, push.ParseOTLPRequest)
+	d.pushHandler(interceptor, r, push.ParseOTLPRequest, writeOTLPPartialSuccess)
 }
 
 // otelErrorHeaderInterceptor maps 500 errors to 503.
@@ -45,7 +53,7 @@ func (i *otelErrorHeaderInterceptor) WriteHeader(statusCode int) {
 	i.ResponseWriter.WriteHeader(statusCode)
 }
 
-func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRequestParser push.RequestParser) {
+func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRequestParser push.RequestParser, writeRejections rejectionsWriter) {
 	logger := util_log.WithContext(r.Context(), util_log.Logger)
 	tenantID, err := tenant.TenantID(r.Context())
 	if err != nil {
@@ -84,7 +92,21 @@ func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRe
 		)
 	}
 
-	_, err = d.Push(r.Context(), req)
+	ctx, warnings := withPushWarnings(r.Context())
+	pushResp, err := d.Push(ctx, req)
+	for _, warning := range warnings.list() {
+		w.Header().Add(pushWarningHeader, warning)
+	}
+	if pushResp != nil && len(pushResp.RejectedStreams) > 0 && writeRejections(w, r, req, pushResp, err) {
+		if d.tenantConfigs.LogPushRequest(tenantID) {
+			level.Debug(logger).Log(
+				"msg", "push request partially rejected",
+				"rejected_streams", len(pushResp.RejectedStreams),
+				"err", err,
+			)
+		}
+		return
+	}
 	if err == nil {
 		if d.tenantConfigs.LogPushRequest(tenantID) {
 			level.Debug(logger).Log(
@@ -118,6 +140,197 @@ func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRe
 	}
 }
 
+// rejectionsWriter writes the response of a push request whose response lists rejected streams.
+// It returns false if the client did not ask for it, the default response being written instead.
+type rejectionsWriter func(w http.ResponseWriter, r *http.Request, req *logproto.PushRequest, resp *logproto.PushResponse, err error) bool
+
+// writeRejectedStreams writes the push response listing the rejected streams, if the client
+// accepts a protobuf or JSON response. The status code is the one of the push error, if any.
+func writeRejectedStreams(w http.ResponseWriter, r *http.Request, _ *logproto.PushRequest, resp *logproto.PushResponse, err error) bool {
+	code := http.StatusOK
+	if err != nil {
+		errResp, ok := httpgrpc.HTTPResponseFromError(err)
+		if !ok {
+			return false
+		}
+		code = int(errResp.Code)
+	}
+
+	var (
+		body        []byte
+		contentType string
+		marshalErr  error
+	)
+	switch {
+	case acceptsContentType(r, pbContentType):
+		body, marshalErr = resp.Marshal()
+		contentType = pbContentType
+	case acceptsContentType(r, jsonContentType):
+		body, marshalErr = json.Marshal(resp)
+		contentType = jsonContentType
+	default:
+		return false
+	}
+	if marshalErr != nil {
+		return false
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+	return true
+}
+
+// writeOTLPPartialSuccess writes an OTLP export response whose partial success holds the number of
+// rejected log records, in the encoding of the request. Requests whose log records were all rejected
+// keep failing with the error of the push.
+func writeOTLPPartialSuccess(w http.ResponseWriter, r *http.Request, req *logproto.PushRequest, resp *logproto.PushResponse, err error) bool {
+	message := resp.RejectedStreams[0].Message
+	if err != nil {
+		errResp, ok := httpgrpc.HTTPResponseFromError(err)
+		if !ok || errResp.Code != http.StatusBadRequest {
+			return false
+		}
+		message = string(errResp.Body)
+	}
+
+	var rejected, total int64
+	for _, s := range resp.RejectedStreams {
+		rejected += int64(s.RejectedEntries)
+	}
+	for _, s := range req.Streams {
+		total += int64(len(s.Entries))
+	}
+	if err != nil && rejected >= total {
+		return false
+	}
+
+	exportResp := plogotlp.NewExportResponse()
+	exportResp.PartialSuccess().SetRejectedLogRecords(rejected)
+	exportResp.PartialSuccess().SetErrorMessage(message)
+
+	var (
+		body        []byte
+		contentType string
+		marshalErr  error
+	)
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == jsonContentType {
+		body, marshalErr = exportResp.MarshalJSON()
+		contentType = jsonContentType
+	} else {
+		body, marshalErr = exportResp.MarshalProto()
+		contentType = pbContentType
+	}
+	if marshalErr != nil {
+		return false
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+	return true
+}
+
+const (
+	pbContentType   = "application/x-protobuf"
+	jsonContentType = "application/json"
+)
+
+// acceptsContentType returns whether the Accept header of the request explicitly lists contentType.
+func acceptsContentType(r *http.Request, contentType string) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mediaType == contentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pushWarningHeader is the response header holding the warnings of a push request.
+const pushWarningHeader = "X-Loki-Warning"
+
+type pushWarningsKey struct{}
+
+// pushWarnings collects the distinct warnings raised while handling a push request.
+type pushWarnings struct {
+	mtx      sync.Mutex
+	warnings []string
+}
+
+func withPushWarnings(ctx context.Context) (context.Context, *pushWarnings) {
+	w := &pushWarnings{}
+	return context.WithValue(ctx, pushWarningsKey{}, w), w
+}
+
+// addPushWarning adds a warning to the response of the push request, if it is returned over HTTP.
+func addPushWarning(ctx context.Context, warning string) {
+	w, ok := ctx.Value(pushWarningsKey{}).(*pushWarnings)
+	if !ok {
+		return
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if !slices.Contains(w.warnings, warning) {
+		w.warnings = append(w.warnings, warning)
+	}
+}
+
+func (w *pushWarnings) list() []string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.warnings
+}
+
+// SamplingHints are the rates clients should target when sampling the logs of
+// a tenant before pushing them.
+type SamplingHints struct {
+	// TargetStreamRateBytes is the rate in bytes per second each stream should
+	// be sampled down to, zero if streams are not limited.
+	TargetStreamRateBytes float64 `json:"target_stream_rate_bytes"`
+	StreamRateLimitBytes  float64 `json:"stream_rate_limit_bytes"`
+	IngestionRateBytes    float64 `json:"ingestion_rate_bytes"`
+	// ActiveStreams and TenantRateBytes are the number of streams and the sum
+	// of their rates as tracked by the rate store.
+	ActiveStreams   int   `json:"active_streams"`
+	TenantRateBytes int64 `json:"tenant_rate_bytes"`
+}
+
+// SamplingHintsHandler returns the sampling hints of the tenant of the request.
+//
+// The target rate of a stream is its rate limit, lowered to the fair share of
+// the tenant ingestion rate when the tenant has many active streams.
+func (d *Distributor) SamplingHintsHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	util.WriteJSONResponse(w, d.samplingHints(tenantID))
+}
+
+func (d *Distributor) samplingHints(tenantID string) SamplingHints {
+	activeStreams, tenantRate := d.rateStore.TenantRate(tenantID)
+	hints := SamplingHints{
+		IngestionRateBytes: d.validator.Limits.IngestionRateBytes(tenantID),
+		ActiveStreams:      activeStreams,
+		TenantRateBytes:    tenantRate,
+	}
+	if limit := d.validator.Limits.PerStreamRateLimit(tenantID).Limit; limit != rate.Inf {
+		hints.StreamRateLimitBytes = float64(limit)
+	}
+
+	hints.TargetStreamRateBytes = hints.StreamRateLimitBytes
+	if activeStreams > 0 {
+		fairShare := hints.IngestionRateBytes / float64(activeStreams)
+		if hints.TargetStreamRateBytes == 0 || fairShare < hints.TargetStreamRateBytes {
+			hints.TargetStreamRateBytes = fairShare
+		}
+	}
+	return hints
+}
+
 // ServeHTTP implements the distributor ring status page.
 //
 // If the rate limiting strategy is local instead of global, no ring is used by
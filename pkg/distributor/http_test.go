@@ -2,12 +2,16 @@ package distributor
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	ring_client "github.com/grafana/dskit/ring/client"
 	"github.com/grafana/dskit/user"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logproto"
@@ -77,7 +81,7 @@ func TestRequestParserWrapping(t *testing.T) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "fake-path", nil)
 	require.NoError(t, err)
 
-	distributors[0].pushHandler(httptest.NewRecorder(), req, stubParser)
+	distributors[0].pushHandler(httptest.NewRecorder(), req, stubParser, writeRejectedStreams)
 
 	require.True(t, called)
 }
@@ -114,6 +118,160 @@ func Test_OtelErrorHeaderInterceptor(t *testing.T) {
 	}
 }
 
+func TestPushHandlerHighCardinalityLabels(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectOldSamples = false
+	limits.DiscoverLogLevels = false
+	limits.MaxLabelValueCardinality = 2
+	limits.HighCardinalityLabelsPolicy = validation.HighCardinalityLabelsPolicyStructuredMetadata
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 3, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	push := func(requestID string) *httptest.ResponseRecorder {
+		parser := func(_ string, _ *http.Request, _ push.TenantsRetention, _ push.Limits, _ push.UsageTracker) (*logproto.PushRequest, *push.Stats, error) {
+			req := makeWriteRequest(1, 10)
+			req.Streams[0].Labels = fmt.Sprintf(`{app="foo", request_id="%s"}`, requestID)
+			return req, &push.Stats{}, nil
+		}
+		ctx := user.InjectOrgID(context.Background(), "test-user")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/loki/api/v1/push", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		distributors[0].pushHandler(rec, req, parser, writeRejectedStreams)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		return rec
+	}
+
+	require.Empty(t, push("1").Header().Values(pushWarningHeader))
+	require.Empty(t, push("2").Header().Values(pushWarningHeader))
+
+	// The third distinct value makes the label high cardinality, it is moved to structured metadata.
+	rec := push("3")
+	require.Equal(t, []string{fmt.Sprintf(promotedLabelWarning, "request_id", 2)}, rec.Header().Values(pushWarningHeader))
+	pushed := ingester.pushed[len(ingester.pushed)-1]
+	require.Equal(t, `{app="foo"}`, pushed.Streams[0].Labels)
+	require.Equal(t, logproto.LabelAdapter{Name: "request_id", Value: "3"}, pushed.Streams[0].Entries[0].StructuredMetadata[0])
+}
+
+func TestPushHandlerRejectedStreams(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.RejectOldSamples = false
+	limits.MaxLineSize = 5
+	distributors, _ := prepare(t, 1, 3, limits, nil)
+
+	// The first stream is valid, the lines of the second one are too long.
+	parser := func(_ string, _ *http.Request, _ push.TenantsRetention, _ push.Limits, _ push.UsageTracker) (*logproto.PushRequest, *push.Stats, error) {
+		req := makeWriteRequest(2, 1)
+		tooLong := makeWriteRequest(3, 10)
+		tooLong.Streams[0].Labels = `{app="bar"}`
+		req.Streams = append(req.Streams, tooLong.Streams...)
+		return req, &push.Stats{}, nil
+	}
+	pushWith := func(handle func(w http.ResponseWriter, r *http.Request), header http.Header) *httptest.ResponseRecorder {
+		ctx := user.InjectOrgID(context.Background(), "test-user")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/loki/api/v1/push", nil)
+		require.NoError(t, err)
+		req.Header = header
+
+		rec := httptest.NewRecorder()
+		handle(rec, req)
+		return rec
+	}
+	expected := logproto.RejectedStream{
+		Index:           1,
+		Labels:          `{app="bar"}`,
+		Reason:          validation.LineTooLong,
+		Message:         fmt.Sprintf(validation.LineTooLongErrorMsg, 5, `{app="bar"}`, 10),
+		RejectedEntries: 3,
+	}
+	loki := func(w http.ResponseWriter, r *http.Request) {
+		distributors[0].pushHandler(w, r, parser, writeRejectedStreams)
+	}
+	otlp := func(w http.ResponseWriter, r *http.Request) {
+		distributors[0].pushHandler(newOtelErrorHeaderInterceptor(w), r, parser, writeOTLPPartialSuccess)
+	}
+
+	t.Run("text response by default", func(t *testing.T) {
+		rec := pushWith(loki, http.Header{})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), expected.Message)
+	})
+
+	t.Run("json response", func(t *testing.T) {
+		rec := pushWith(loki, http.Header{"Accept": []string{"application/json"}})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var resp logproto.PushResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Equal(t, []logproto.RejectedStream{expected}, resp.RejectedStreams)
+	})
+
+	t.Run("protobuf response", func(t *testing.T) {
+		rec := pushWith(loki, http.Header{"Accept": []string{"text/plain, application/x-protobuf"}})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Equal(t, "application/x-protobuf", rec.Header().Get("Content-Type"))
+
+		var resp logproto.PushResponse
+		require.NoError(t, resp.Unmarshal(rec.Body.Bytes()))
+		require.Equal(t, []logproto.RejectedStream{expected}, resp.RejectedStreams)
+	})
+
+	t.Run("otlp partial success", func(t *testing.T) {
+		rec := pushWith(otlp, http.Header{"Content-Type": []string{"application/json"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		resp := plogotlp.NewExportResponse()
+		require.NoError(t, resp.UnmarshalJSON(rec.Body.Bytes()))
+		require.Equal(t, int64(3), resp.PartialSuccess().RejectedLogRecords())
+		require.Contains(t, resp.PartialSuccess().ErrorMessage(), expected.Message)
+	})
+}
+
 func stubParser(_ string, _ *http.Request, _ push.TenantsRetention, _ push.Limits, _ push.UsageTracker) (*logproto.PushRequest, *push.Stats, error) {
 	return &logproto.PushRequest{}, &push.Stats{}, nil
 }
+
+func TestSamplingHintsHandler(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.IngestionRateMB = 1
+	require.NoError(t, limits.PerStreamRateLimit.Set("102400"))
+	distributors, _ := prepare(t, 1, 3, limits, nil)
+
+	get := func() SamplingHints {
+		ctx := user.InjectOrgID(context.Background(), "test-user")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/loki/api/v1/sampling_hints", nil)
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		distributors[0].SamplingHintsHandler(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var hints SamplingHints
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hints))
+		return hints
+	}
+
+	// Without known stream rates the stream rate limit is the target.
+	distributors[0].rateStore = &fakeRateStore{}
+	require.Equal(t, SamplingHints{
+		TargetStreamRateBytes: 100 << 10,
+		StreamRateLimitBytes:  100 << 10,
+		IngestionRateBytes:    1 << 20,
+	}, get())
+
+	// Many active streams lower the target to the fair share of the tenant rate.
+	distributors[0].rateStore = &fakeRateStore{rate: 512, streams: 64}
+	require.Equal(t, SamplingHints{
+		TargetStreamRateBytes: 16 << 10,
+		StreamRateLimitBytes:  100 << 10,
+		IngestionRateBytes:    1 << 20,
+		ActiveStreams:         64,
+		TenantRateBytes:       512 * 64,
+	}, get())
+}
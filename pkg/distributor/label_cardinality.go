@@ -0,0 +1,219 @@
+package distributor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+const (
+	// labelCardinalityWindow is how long the values of a label are tracked, and how long a label
+	// stays high cardinality after it was last seen.
+	labelCardinalityWindow          = time.Hour
+	labelCardinalityCleanupInterval = time.Minute
+	// labelCardinalityShards is the number of shards the labels of a tenant are spread over, each
+	// with its own lock, so that concurrent pushes only contend when they observe the same labels.
+	labelCardinalityShards = 32
+
+	highCardinalityLabelWarning = "stream label '%s' has more than %d distinct values, consider sending it as structured metadata"
+	promotedLabelWarning        = "stream label '%s' has more than %d distinct values and was moved to structured metadata"
+)
+
+type labelCardinality struct {
+	// values holds the last time each value was seen, until the label becomes high cardinality.
+	values          map[string]time.Time
+	highCardinality bool
+	lastSeen        time.Time
+}
+
+type labelCardinalityShard struct {
+	mtx    sync.Mutex
+	labels map[string]*labelCardinality
+}
+
+type tenantLabelCardinality struct {
+	shards [labelCardinalityShards]labelCardinalityShard
+}
+
+func newTenantLabelCardinality() *tenantLabelCardinality {
+	tenant := &tenantLabelCardinality{}
+	for i := range tenant.shards {
+		tenant.shards[i].labels = make(map[string]*labelCardinality)
+	}
+	return tenant
+}
+
+func (t *tenantLabelCardinality) shard(name string) *labelCardinalityShard {
+	return &t.shards[xxhash.Sum64String(name)%labelCardinalityShards]
+}
+
+// empty returns whether the tenant has no label tracked.
+func (t *tenantLabelCardinality) empty() bool {
+	for i := range t.shards {
+		shard := &t.shards[i]
+		shard.mtx.Lock()
+		n := len(shard.labels)
+		shard.mtx.Unlock()
+		if n > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// labelCardinalityTracker tracks the number of distinct values of the stream labels of each tenant,
+// to detect the labels with a high cardinality.
+//
+// Each distributor only tracks the streams it receives, so the cardinality of a label is the
+// number of its distinct values pushed through this distributor, not across the cluster.
+type labelCardinalityTracker struct {
+	services.Service
+
+	mtx     sync.RWMutex
+	tenants map[string]*tenantLabelCardinality
+	now     func() time.Time
+
+	highCardinalityLabels *prometheus.GaugeVec
+}
+
+func newLabelCardinalityTracker(registerer prometheus.Registerer) *labelCardinalityTracker {
+	t := &labelCardinalityTracker{
+		tenants: make(map[string]*tenantLabelCardinality),
+		now:     time.Now,
+		highCardinalityLabels: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_high_cardinality_labels",
+			Help:      "Stream labels whose number of distinct values exceeds the max label value cardinality of the tenant.",
+		}, []string{"tenant", "label"}),
+	}
+	t.Service = services.NewTimerService(labelCardinalityCleanupInterval, nil, t.cleanup, nil).WithName("label cardinality tracker")
+	return t
+}
+
+// observe records the label values of a stream and returns the names of its labels having more
+// than maxCardinality distinct values.
+func (t *labelCardinalityTracker) observe(tenantID string, ls labels.Labels, maxCardinality int) []string {
+	tenant := t.tenant(tenantID)
+	now := t.now()
+
+	var highCardinality []string
+	for _, l := range ls {
+		if t.observeLabel(tenantID, tenant.shard(l.Name), l, now, maxCardinality) {
+			highCardinality = append(highCardinality, l.Name)
+		}
+	}
+	return highCardinality
+}
+
+// observeLabel records a label value and returns whether the label has more than maxCardinality
+// distinct values.
+func (t *labelCardinalityTracker) observeLabel(tenantID string, shard *labelCardinalityShard, l labels.Label, now time.Time, maxCardinality int) bool {
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	lc, ok := shard.labels[l.Name]
+	if !ok {
+		lc = &labelCardinality{values: make(map[string]time.Time)}
+		shard.labels[l.Name] = lc
+	}
+	lc.lastSeen = now
+
+	if lc.highCardinality {
+		return true
+	}
+	lc.values[l.Value] = now
+	if len(lc.values) <= maxCardinality {
+		return false
+	}
+	// The values are not needed anymore once the label is known to be high cardinality.
+	lc.highCardinality, lc.values = true, nil
+	t.highCardinalityLabels.WithLabelValues(tenantID, l.Name).Set(1)
+	return true
+}
+
+func (t *labelCardinalityTracker) tenant(tenantID string) *tenantLabelCardinality {
+	t.mtx.RLock()
+	tenant, ok := t.tenants[tenantID]
+	t.mtx.RUnlock()
+	if ok {
+		return tenant
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if tenant, ok = t.tenants[tenantID]; !ok {
+		tenant = newTenantLabelCardinality()
+		t.tenants[tenantID] = tenant
+	}
+	return tenant
+}
+
+// cleanup forgets the label values not seen within the cardinality window, and the high
+// cardinality labels not seen since.
+func (t *labelCardinalityTracker) cleanup(_ context.Context) error {
+	expired := t.now().Add(-labelCardinalityWindow)
+
+	// Pushes only wait for the shard being cleaned up, the tenants lock is only held to list
+	// the tenants and to delete the ones without labels.
+	t.mtx.RLock()
+	tenants := make(map[string]*tenantLabelCardinality, len(t.tenants))
+	for tenantID, tenant := range t.tenants {
+		tenants[tenantID] = tenant
+	}
+	t.mtx.RUnlock()
+
+	var empty []string
+	for tenantID, tenant := range tenants {
+		var tracked int
+		for i := range tenant.shards {
+			tracked += t.cleanupShard(tenantID, &tenant.shards[i], expired)
+		}
+		if tracked == 0 {
+			empty = append(empty, tenantID)
+		}
+	}
+	if len(empty) == 0 {
+		return nil
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for _, tenantID := range empty {
+		// The tenant may have observed labels since its shards were cleaned up.
+		if tenant, ok := t.tenants[tenantID]; ok && tenant == tenants[tenantID] && tenant.empty() {
+			delete(t.tenants, tenantID)
+		}
+	}
+	return nil
+}
+
+// cleanupShard forgets the expired label values and labels of a shard, and returns the number
+// of labels still tracked.
+func (t *labelCardinalityTracker) cleanupShard(tenantID string, shard *labelCardinalityShard, expired time.Time) int {
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	for name, lc := range shard.labels {
+		if lc.lastSeen.Before(expired) {
+			if lc.highCardinality {
+				t.highCardinalityLabels.DeleteLabelValues(tenantID, name)
+			}
+			delete(shard.labels, name)
+			continue
+		}
+		for value, lastSeen := range lc.values {
+			if lastSeen.Before(expired) {
+				delete(lc.values, value)
+			}
+		}
+	}
+	return len(shard.labels)
+}
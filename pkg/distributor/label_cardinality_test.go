@@ -0,0 +1,85 @@
+package distributor
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelCardinalityTracker(t *testing.T) {
+	now := time.Unix(0, 0)
+	tracker := newLabelCardinalityTracker(prometheus.NewRegistry())
+	tracker.now = func() time.Time { return now }
+
+	observe := func(tenant, requestID string) []string {
+		return tracker.observe(tenant, labels.FromStrings("app", "foo", "request_id", requestID), 2)
+	}
+
+	require.Empty(t, observe("a", "1"))
+	require.Empty(t, observe("a", "2"))
+	require.Empty(t, observe("a", "2"))
+	require.Equal(t, []string{"request_id"}, observe("a", "3"))
+	require.Equal(t, []string{"request_id"}, observe("a", "1"))
+	require.Equal(t, 1.0, testutil.ToFloat64(tracker.highCardinalityLabels.WithLabelValues("a", "request_id")))
+
+	// Cardinality is tracked per tenant.
+	require.Empty(t, observe("b", "3"))
+
+	// Values and high cardinality labels not seen within the window are forgotten.
+	now = now.Add(labelCardinalityWindow / 2)
+	require.Empty(t, observe("b", "4"))
+	now = now.Add(labelCardinalityWindow/2 + time.Second)
+	require.NoError(t, tracker.cleanup(context.Background()))
+	require.Empty(t, observe("b", "5"))
+	require.Equal(t, 0, testutil.CollectAndCount(tracker.highCardinalityLabels))
+
+	// Tenants without labels left are deleted.
+	require.NotContains(t, tracker.tenants, "a")
+	require.Contains(t, tracker.tenants, "b")
+	require.Empty(t, observe("a", "5"))
+}
+
+func TestLabelCardinalityTracker_ConcurrentCleanup(t *testing.T) {
+	now := time.Unix(0, 0)
+	tracker := newLabelCardinalityTracker(prometheus.NewRegistry())
+	tracker.now = func() time.Time { return now }
+	for _, tenant := range []string{"a", "b", "c"} {
+		tracker.observe(tenant, labels.FromStrings("app", "foo"), 10)
+	}
+	now = now.Add(2 * labelCardinalityWindow)
+
+	// Pushes keep observing labels while the expired ones are cleaned up.
+	var wg sync.WaitGroup
+	for _, tenant := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func(tenant string) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				tracker.observe(tenant, labels.FromStrings("app", strconv.Itoa(i)), 10)
+			}
+		}(tenant)
+	}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, tracker.cleanup(context.Background()))
+	}
+	wg.Wait()
+}
+
+func BenchmarkLabelCardinalityTracker(b *testing.B) {
+	tracker := newLabelCardinalityTracker(prometheus.NewRegistry())
+	ls := labels.FromStrings("app", "foo", "cluster", "eu-west", "namespace", "loki", "pod", "loki-0")
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			tracker.observe("tenant", ls, 100)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/validation"
 )
 
 // Limits is an interface for distributor limits/related configs
@@ -24,11 +25,17 @@ type Limits interface {
 	IncrementDuplicateTimestamps(userID string) bool
 	DiscoverServiceName(userID string) []string
 	DiscoverLogLevels(userID string) bool
+	IngestionRelabelConfigs(userID string) []*validation.IngestionRelabelConfig
+	MaxLabelValueCardinality(userID string) int
+	HighCardinalityLabelsPolicy(userID string) string
+	IngestionPriorityClass(userID string) string
+	IngestionPriorityStreams(userID string) []validation.IngestionPriorityStream
 
 	ShardStreams(userID string) shardstreams.Config
 	IngestionRateStrategy() string
 	IngestionRateBytes(userID string) float64
 	IngestionBurstSizeBytes(userID string) int
+	PerStreamRateLimit(userID string) validation.RateLimit
 	AllowStructuredMetadata(userID string) bool
 	MaxStructuredMetadataSize(userID string) int
 	MaxStructuredMetadataCount(userID string) int
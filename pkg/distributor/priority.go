@@ -0,0 +1,181 @@
+package distributor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/ingester/client"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// PriorityClass is a class of ingestion priority. The streams of a class are shed by the distributors
+// when one of the ingesters they are written to reports a load of at least shed_load.
+type PriorityClass struct {
+	Name     string  `yaml:"name" doc:"description=Name of the priority class, referenced by the ingestion_priority_class and ingestion_priority_streams limits."`
+	ShedLoad float64 `yaml:"shed_load" doc:"description=Load of the ingesters from which the streams of the class are shed, 1 when the ingesters are fully loaded. The lower the shed load, the lower the priority of the class."`
+}
+
+func validatePriorityClasses(classes []PriorityClass) error {
+	names := make(map[string]struct{}, len(classes))
+	for _, class := range classes {
+		if class.Name == "" {
+			return errors.New("the name of the priority classes must not be empty")
+		}
+		if _, ok := names[class.Name]; ok {
+			return fmt.Errorf("duplicate priority class %q", class.Name)
+		}
+		names[class.Name] = struct{}{}
+		if class.ShedLoad <= 0 {
+			return fmt.Errorf("the shed load of priority class %q must be greater than 0", class.Name)
+		}
+	}
+	return nil
+}
+
+// shedLoadFor returns the name and shed load of the priority class of a stream of a tenant, or a shed load of 0
+// when the stream is never shed. The class of the first ingestion priority stream matching the labels is used,
+// falling back to the ingestion priority class of the tenant.
+func (d *Distributor) shedLoadFor(tenantID string, lbs labels.Labels) (string, float64) {
+	if len(d.shedLoads) == 0 {
+		return "", 0
+	}
+	class := d.validator.Limits.IngestionPriorityClass(tenantID)
+	for _, rule := range d.validator.Limits.IngestionPriorityStreams(tenantID) {
+		if matchesAll(rule.Matchers, lbs) {
+			class = rule.Class
+			break
+		}
+	}
+	return class, d.shedLoads[class]
+}
+
+func matchesAll(matchers []*labels.Matcher, lbs labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbs.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadProbePeriod is how often the load of an ingester is requested while all of the streams written to it are shed.
+const loadProbePeriod = time.Second
+
+type ingesterLoad struct {
+	load      float64
+	updatedAt time.Time
+	probedAt  time.Time
+}
+
+// ingesterLoads keeps the last load reported by each ingester in its push responses.
+type ingesterLoads struct {
+	timeout time.Duration
+	gauge   *prometheus.GaugeVec
+
+	mtx   sync.RWMutex
+	loads map[string]ingesterLoad
+}
+
+func newIngesterLoads(timeout time.Duration, registerer prometheus.Registerer) *ingesterLoads {
+	return &ingesterLoads{
+		timeout: timeout,
+		gauge: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_ingester_load",
+			Help:      "The last load reported by the ingesters in their push responses, 1 when they are fully loaded.",
+		}, []string{"ingester"}),
+		loads: map[string]ingesterLoad{},
+	}
+}
+
+func (l *ingesterLoads) update(addr string, load float64) {
+	l.mtx.Lock()
+	l.loads[addr] = ingesterLoad{load: load, updatedAt: time.Now(), probedAt: l.loads[addr].probedAt}
+	l.mtx.Unlock()
+	l.gauge.WithLabelValues(addr).Set(load)
+}
+
+// max returns the highest load reported by the ingesters of a replication set.
+// Loads reported more than ingester_load_timeout ago are ignored, as the ingesters stopped reporting them.
+func (l *ingesterLoads) max(replicationSet ring.ReplicationSet) float64 {
+	now := time.Now()
+	highest := 0.0
+
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	for _, instance := range replicationSet.Instances {
+		load, ok := l.loads[instance.Addr]
+		if ok && now.Sub(load.updatedAt) <= l.timeout {
+			highest = math.Max(highest, load.load)
+		}
+	}
+	return highest
+}
+
+// probe returns whether the load of an ingester receiving no streams should be requested, as it is otherwise only
+// reported in the responses of the pushes to the ingester. It returns true at most once per probe period.
+func (l *ingesterLoads) probe(addr string) bool {
+	now := time.Now()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	load := l.loads[addr]
+	if now.Sub(load.updatedAt) < loadProbePeriod || now.Sub(load.probedAt) < loadProbePeriod {
+		return false
+	}
+	load.probedAt = now
+	l.loads[addr] = load
+	return true
+}
+
+// probeIngesterLoad requests the load of an ingester with an empty push, so that the ingesters all the streams
+// of which are shed keep reporting their load, and their streams stop being shed once they are no longer overloaded.
+func (d *Distributor) probeIngesterLoad(tenantID string, ingester ring.InstanceDesc) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.clientCfg.RemoteTimeout)
+	defer cancel()
+	ctx = user.InjectOrgID(ctx, tenantID)
+
+	c, err := d.pool.GetClientFor(ingester.Addr)
+	if err != nil {
+		level.Debug(d.logger).Log("msg", "failed to probe the load of the ingester", "ingester", ingester.Addr, "err", err)
+		return
+	}
+	resp, err := c.(logproto.PusherClient).Push(client.InjectRejectedStreams(ctx), &logproto.PushRequest{})
+	if err != nil {
+		level.Debug(d.logger).Log("msg", "failed to probe the load of the ingester", "ingester", ingester.Addr, "err", err)
+		return
+	}
+	d.ingesterLoads.update(ingester.Addr, resp.GetLoad())
+}
+
+// shedStream records the entries of a stream shed because of the load of its ingesters as discarded.
+func shedStream(rejected *rejectedStreams, index int, reqLabels, tenantID, class string, load float64, stream KeyedStream) {
+	bytes := 0
+	for _, e := range stream.Stream.Entries {
+		bytes += len(e.Line)
+	}
+	validation.DiscardedSamples.WithLabelValues(validation.IngestersOverloaded, tenantID).Add(float64(len(stream.Stream.Entries)))
+	validation.DiscardedBytes.WithLabelValues(validation.IngestersOverloaded, tenantID).Add(float64(bytes))
+	rejected.add(index, reqLabels, validation.IngestersOverloaded, fmt.Sprintf(validation.IngestersOverloadedErrorMsg, class, load), len(stream.Stream.Entries))
+}
+
+// streamPriority is the priority of a stream of a push request, kept along with the stream sent to the ingesters.
+type streamPriority struct {
+	index    int
+	labels   string
+	class    string
+	shedLoad float64
+}
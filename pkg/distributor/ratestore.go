@@ -367,6 +367,19 @@ func (s *rateStore) getClients(ctx context.Context) ([]ingesterClient, error) {
 	return clients, nil
 }
 
+// TenantRate returns the number of streams of a tenant with a known rate and
+// the sum of their rates.
+func (s *rateStore) TenantRate(tenant string) (int, int64) {
+	s.rateLock.RLock()
+	defer s.rateLock.RUnlock()
+
+	var total int64
+	for _, rate := range s.rates[tenant] {
+		total += rate.rate
+	}
+	return len(s.rates[tenant]), total
+}
+
 func (s *rateStore) RateFor(tenant string, streamHash uint64) (int64, float64) {
 	s.rateLock.RLock()
 	defer s.rateLock.RUnlock()
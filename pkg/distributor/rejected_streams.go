@@ -0,0 +1,31 @@
+package distributor
+
+import (
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+const relabelDroppedMessage = "stream dropped by the ingestion relabel configs"
+
+// rejectedStreams collects the rejected streams of a push request, for the push response.
+type rejectedStreams struct {
+	streams []logproto.RejectedStream
+}
+
+// add records entries of the stream at the given index of the request rejected for reason.
+// Consecutive rejections of the same stream for the same reason are merged, keeping the first message.
+func (r *rejectedStreams) add(index int, labels, reason, message string, entries int) {
+	if n := len(r.streams); n > 0 {
+		last := &r.streams[n-1]
+		if last.Index == int32(index) && last.Reason == reason {
+			last.RejectedEntries += int32(entries)
+			return
+		}
+	}
+	r.streams = append(r.streams, logproto.RejectedStream{
+		Index:           int32(index),
+		Labels:          labels,
+		Reason:          reason,
+		Message:         message,
+		RejectedEntries: int32(entries),
+	})
+}
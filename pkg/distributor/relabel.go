@@ -0,0 +1,36 @@
+package distributor
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// relabelStream applies the ingestion relabel configs of a tenant to the labels of a stream.
+// It returns the resulting labels, the labels moved to structured metadata and whether the stream is kept.
+func relabelStream(cfgs []*validation.IngestionRelabelConfig, ls labels.Labels) (labels.Labels, []logproto.LabelAdapter, bool) {
+	lb := labels.NewBuilder(ls)
+	var structuredMetadata []logproto.LabelAdapter
+
+	for _, cfg := range cfgs {
+		if cfg.StructuredMetadataRegex == nil {
+			if !relabel.ProcessBuilder(lb, cfg.Relabel) {
+				return nil, nil, false
+			}
+			continue
+		}
+
+		var moved []string
+		lb.Range(func(l labels.Label) {
+			if cfg.StructuredMetadataRegex.MatchString(l.Name) {
+				structuredMetadata = append(structuredMetadata, logproto.LabelAdapter{Name: l.Name, Value: l.Value})
+				moved = append(moved, l.Name)
+			}
+		})
+		lb.Del(moved...)
+	}
+
+	return lb.Labels(), structuredMetadata, true
+}
@@ -3,7 +3,6 @@ package distributor
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
@@ -50,10 +49,16 @@ type validationContext struct {
 	maxStructuredMetadataSize  int
 	maxStructuredMetadataCount int
 
+	ingestionRelabelConfigs     []*validation.IngestionRelabelConfig
+	ingestionRelabelConfigsHash uint64
+	maxLabelValueCardinality    int
+	highCardinalityLabelsPolicy string
+
 	userID string
 }
 
 func (v Validator) getValidationContextForTime(now time.Time, userID string) validationContext {
+	ingestionRelabelConfigs := v.IngestionRelabelConfigs(userID)
 	return validationContext{
 		userID:                       userID,
 		rejectOldSample:              v.RejectOldSamples(userID),
@@ -70,6 +75,10 @@ func (v Validator) getValidationContextForTime(now time.Time, userID string) val
 		allowStructuredMetadata:      v.AllowStructuredMetadata(userID),
 		maxStructuredMetadataSize:    v.MaxStructuredMetadataSize(userID),
 		maxStructuredMetadataCount:   v.MaxStructuredMetadataCount(userID),
+		ingestionRelabelConfigs:      ingestionRelabelConfigs,
+		ingestionRelabelConfigsHash:  validation.IngestionRelabelConfigsHash(ingestionRelabelConfigs),
+		maxLabelValueCardinality:     v.MaxLabelValueCardinality(userID),
+		highCardinalityLabelsPolicy:  v.HighCardinalityLabelsPolicy(userID),
 	}
 }
 
@@ -87,7 +96,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 		if v.usageTracker != nil {
 			v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.GreaterThanMaxSampleAge, labels, float64(len(entry.Line)))
 		}
-		return fmt.Errorf(validation.GreaterThanMaxSampleAgeErrorMsg, labels, formatedEntryTime, formatedRejectMaxAgeTime)
+		return validation.NewErrDiscarded(validation.GreaterThanMaxSampleAge, validation.GreaterThanMaxSampleAgeErrorMsg, labels, formatedEntryTime, formatedRejectMaxAgeTime)
 	}
 
 	if ts > vCtx.creationGracePeriod {
@@ -97,7 +106,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 		if v.usageTracker != nil {
 			v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.TooFarInFuture, labels, float64(len(entry.Line)))
 		}
-		return fmt.Errorf(validation.TooFarInFutureErrorMsg, labels, formatedEntryTime)
+		return validation.NewErrDiscarded(validation.TooFarInFuture, validation.TooFarInFutureErrorMsg, labels, formatedEntryTime)
 	}
 
 	if maxSize := vCtx.maxLineSize; maxSize != 0 && len(entry.Line) > maxSize {
@@ -110,7 +119,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 		if v.usageTracker != nil {
 			v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.LineTooLong, labels, float64(len(entry.Line)))
 		}
-		return fmt.Errorf(validation.LineTooLongErrorMsg, maxSize, labels, len(entry.Line))
+		return validation.NewErrDiscarded(validation.LineTooLong, validation.LineTooLongErrorMsg, maxSize, labels, len(entry.Line))
 	}
 
 	if len(entry.StructuredMetadata) > 0 {
@@ -120,7 +129,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 			if v.usageTracker != nil {
 				v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.DisallowedStructuredMetadata, labels, float64(len(entry.Line)))
 			}
-			return fmt.Errorf(validation.DisallowedStructuredMetadataErrorMsg, labels)
+			return validation.NewErrDiscarded(validation.DisallowedStructuredMetadata, validation.DisallowedStructuredMetadataErrorMsg, labels)
 		}
 
 		var structuredMetadataSizeBytes, structuredMetadataCount int
@@ -135,7 +144,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 			if v.usageTracker != nil {
 				v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.StructuredMetadataTooLarge, labels, float64(len(entry.Line)))
 			}
-			return fmt.Errorf(validation.StructuredMetadataTooLargeErrorMsg, labels, structuredMetadataSizeBytes, vCtx.maxStructuredMetadataSize)
+			return validation.NewErrDiscarded(validation.StructuredMetadataTooLarge, validation.StructuredMetadataTooLargeErrorMsg, labels, structuredMetadataSizeBytes, vCtx.maxStructuredMetadataSize)
 		}
 
 		if maxCount := vCtx.maxStructuredMetadataCount; maxCount != 0 && structuredMetadataCount > maxCount {
@@ -144,7 +153,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 			if v.usageTracker != nil {
 				v.usageTracker.DiscardedBytesAdd(ctx, vCtx.userID, validation.StructuredMetadataTooMany, labels, float64(len(entry.Line)))
 			}
-			return fmt.Errorf(validation.StructuredMetadataTooManyErrorMsg, labels, structuredMetadataCount, vCtx.maxStructuredMetadataCount)
+			return validation.NewErrDiscarded(validation.StructuredMetadataTooMany, validation.StructuredMetadataTooManyErrorMsg, labels, structuredMetadataCount, vCtx.maxStructuredMetadataCount)
 		}
 	}
 
@@ -155,7 +164,7 @@ func (v Validator) ValidateEntry(ctx context.Context, vCtx validationContext, la
 func (v Validator) ValidateLabels(ctx validationContext, ls labels.Labels, stream logproto.Stream) error {
 	if len(ls) == 0 {
 		validation.DiscardedSamples.WithLabelValues(validation.MissingLabels, ctx.userID).Inc()
-		return fmt.Errorf(validation.MissingLabelsErrorMsg)
+		return validation.NewErrDiscarded(validation.MissingLabels, validation.MissingLabelsErrorMsg)
 	}
 
 	// Skip validation for aggregated metric streams, as we create those for internal use
@@ -172,20 +181,20 @@ func (v Validator) ValidateLabels(ctx validationContext, ls labels.Labels, strea
 
 	if numLabelNames > ctx.maxLabelNamesPerSeries {
 		updateMetrics(validation.MaxLabelNamesPerSeries, ctx.userID, stream)
-		return fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, stream.Labels, numLabelNames, ctx.maxLabelNamesPerSeries)
+		return validation.NewErrDiscarded(validation.MaxLabelNamesPerSeries, validation.MaxLabelNamesPerSeriesErrorMsg, stream.Labels, numLabelNames, ctx.maxLabelNamesPerSeries)
 	}
 
 	lastLabelName := ""
 	for _, l := range ls {
 		if len(l.Name) > ctx.maxLabelNameLength {
 			updateMetrics(validation.LabelNameTooLong, ctx.userID, stream)
-			return fmt.Errorf(validation.LabelNameTooLongErrorMsg, stream.Labels, l.Name)
+			return validation.NewErrDiscarded(validation.LabelNameTooLong, validation.LabelNameTooLongErrorMsg, stream.Labels, l.Name)
 		} else if len(l.Value) > ctx.maxLabelValueLength {
 			updateMetrics(validation.LabelValueTooLong, ctx.userID, stream)
-			return fmt.Errorf(validation.LabelValueTooLongErrorMsg, stream.Labels, l.Value)
+			return validation.NewErrDiscarded(validation.LabelValueTooLong, validation.LabelValueTooLongErrorMsg, stream.Labels, l.Value)
 		} else if cmp := strings.Compare(lastLabelName, l.Name); cmp == 0 {
 			updateMetrics(validation.DuplicateLabelNames, ctx.userID, stream)
-			return fmt.Errorf(validation.DuplicateLabelNamesErrorMsg, stream.Labels, l.Name)
+			return validation.NewErrDiscarded(validation.DuplicateLabelNames, validation.DuplicateLabelNamesErrorMsg, stream.Labels, l.Name)
 		}
 		lastLabelName = l.Name
 	}
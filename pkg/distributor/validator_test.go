@@ -2,7 +2,6 @@ package distributor
 
 import (
 	"errors"
-	"fmt"
 	"testing"
 	"time"
 
@@ -62,7 +61,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime.Add(-time.Hour * 5), Line: "test"},
-			fmt.Errorf(validation.GreaterThanMaxSampleAgeErrorMsg,
+			validation.NewErrDiscarded(validation.GreaterThanMaxSampleAge, validation.GreaterThanMaxSampleAgeErrorMsg,
 				testStreamLabelsString,
 				testTime.Add(-time.Hour*5).Format(timeFormat),
 				testTime.Add(-1*time.Hour).Format(timeFormat), // same as RejectOldSamplesMaxAge
@@ -73,7 +72,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 			"test",
 			nil,
 			logproto.Entry{Timestamp: testTime.Add(time.Hour * 5), Line: "test"},
-			fmt.Errorf(validation.TooFarInFutureErrorMsg, testStreamLabelsString, testTime.Add(time.Hour*5).Format(timeFormat)),
+			validation.NewErrDiscarded(validation.TooFarInFuture, validation.TooFarInFutureErrorMsg, testStreamLabelsString, testTime.Add(time.Hour*5).Format(timeFormat)),
 		},
 		{
 			"line too long",
@@ -84,7 +83,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901"},
-			fmt.Errorf(validation.LineTooLongErrorMsg, 10, testStreamLabelsString, 11),
+			validation.NewErrDiscarded(validation.LineTooLong, validation.LineTooLongErrorMsg, 10, testStreamLabelsString, 11),
 		},
 		{
 			"disallowed structured metadata",
@@ -95,7 +94,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901", StructuredMetadata: push.LabelsAdapter{{Name: "foo", Value: "bar"}}},
-			fmt.Errorf(validation.DisallowedStructuredMetadataErrorMsg, testStreamLabelsString),
+			validation.NewErrDiscarded(validation.DisallowedStructuredMetadata, validation.DisallowedStructuredMetadataErrorMsg, testStreamLabelsString),
 		},
 		{
 			"structured metadata too big",
@@ -107,7 +106,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901", StructuredMetadata: push.LabelsAdapter{{Name: "foo", Value: "bar"}}},
-			fmt.Errorf(validation.StructuredMetadataTooLargeErrorMsg, testStreamLabelsString, 6, 4),
+			validation.NewErrDiscarded(validation.StructuredMetadataTooLarge, validation.StructuredMetadataTooLargeErrorMsg, testStreamLabelsString, 6, 4),
 		},
 		{
 			"structured metadata too many",
@@ -119,7 +118,7 @@ func TestValidator_ValidateEntry(t *testing.T) {
 				},
 			},
 			logproto.Entry{Timestamp: testTime, Line: "12345678901", StructuredMetadata: push.LabelsAdapter{{Name: "foo", Value: "bar"}, {Name: "too", Value: "many"}}},
-			fmt.Errorf(validation.StructuredMetadataTooManyErrorMsg, testStreamLabelsString, 2, 1),
+			validation.NewErrDiscarded(validation.StructuredMetadataTooMany, validation.StructuredMetadataTooManyErrorMsg, testStreamLabelsString, 2, 1),
 		},
 	}
 	for _, tt := range tests {
@@ -157,7 +156,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 			"test",
 			nil,
 			"{}",
-			fmt.Errorf(validation.MissingLabelsErrorMsg),
+			validation.NewErrDiscarded(validation.MissingLabels, validation.MissingLabelsErrorMsg),
 		},
 		{
 			"test too many labels",
@@ -166,7 +165,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				&validation.Limits{MaxLabelNamesPerSeries: 2},
 			},
 			"{foo=\"bar\",food=\"bars\",fed=\"bears\"}",
-			fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, "{foo=\"bar\",food=\"bars\",fed=\"bears\"}", 3, 2),
+			validation.NewErrDiscarded(validation.MaxLabelNamesPerSeries, validation.MaxLabelNamesPerSeriesErrorMsg, "{foo=\"bar\",food=\"bars\",fed=\"bears\"}", 3, 2),
 		},
 		{
 			"label name too long",
@@ -178,7 +177,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{fooooo=\"bar\"}",
-			fmt.Errorf(validation.LabelNameTooLongErrorMsg, "{fooooo=\"bar\"}", "fooooo"),
+			validation.NewErrDiscarded(validation.LabelNameTooLong, validation.LabelNameTooLongErrorMsg, "{fooooo=\"bar\"}", "fooooo"),
 		},
 		{
 			"label value too long",
@@ -191,7 +190,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{foo=\"barrrrrr\"}",
-			fmt.Errorf(validation.LabelValueTooLongErrorMsg, "{foo=\"barrrrrr\"}", "barrrrrr"),
+			validation.NewErrDiscarded(validation.LabelValueTooLong, validation.LabelValueTooLongErrorMsg, "{foo=\"barrrrrr\"}", "barrrrrr"),
 		},
 		{
 			"duplicate label",
@@ -204,7 +203,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{foo=\"bar\", foo=\"barf\"}",
-			fmt.Errorf(validation.DuplicateLabelNamesErrorMsg, "{foo=\"bar\", foo=\"barf\"}", "foo"),
+			validation.NewErrDiscarded(validation.DuplicateLabelNames, validation.DuplicateLabelNamesErrorMsg, "{foo=\"bar\", foo=\"barf\"}", "foo"),
 		},
 		{
 			"label value contains %",
@@ -217,7 +216,7 @@ func TestValidator_ValidateLabels(t *testing.T) {
 				},
 			},
 			"{foo=\"bar\", foo=\"barf%s\"}",
-			errors.New("stream '{foo=\"bar\", foo=\"barf%s\"}' has label value too long: 'barf%s'"), // Intentionally construct the string to make sure %s isn't substituted as (MISSING)
+			&validation.ErrDiscarded{Reason: validation.LabelValueTooLong, Err: errors.New("stream '{foo=\"bar\", foo=\"barf%s\"}' has label value too long: 'barf%s'")}, // Intentionally construct the string to make sure %s isn't substituted as (MISSING)
 		},
 	}
 	for _, tt := range tests {
@@ -5,6 +5,7 @@ package ingester
 
 import (
 	bytes "bytes"
+	context "context"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -12,6 +13,9 @@ import (
 	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	_ "github.com/grafana/loki/v3/pkg/logproto"
 	github_com_grafana_loki_v3_pkg_logproto "github.com/grafana/loki/v3/pkg/logproto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -234,48 +238,115 @@ func (m *Series) GetHighestTs() time.Time {
 	return time.Time{}
 }
 
+// TransferStreamsResponse is returned by an ingester once it recovered all the streams transferred to it.
+// The sending ingester compares the totals with the ones it sent to verify the hand-off.
+type TransferStreamsResponse struct {
+	Streams int64 `protobuf:"varint,1,opt,name=streams,proto3" json:"streams,omitempty"`
+	Chunks  int64 `protobuf:"varint,2,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	Bytes   int64 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+}
+
+func (m *TransferStreamsResponse) Reset()      { *m = TransferStreamsResponse{} }
+func (*TransferStreamsResponse) ProtoMessage() {}
+func (*TransferStreamsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00f4b7152db9bdb5, []int{2}
+}
+func (m *TransferStreamsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TransferStreamsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TransferStreamsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TransferStreamsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferStreamsResponse.Merge(m, src)
+}
+func (m *TransferStreamsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TransferStreamsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferStreamsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferStreamsResponse proto.InternalMessageInfo
+
+func (m *TransferStreamsResponse) GetStreams() int64 {
+	if m != nil {
+		return m.Streams
+	}
+	return 0
+}
+
+func (m *TransferStreamsResponse) GetChunks() int64 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
+func (m *TransferStreamsResponse) GetBytes() int64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Chunk)(nil), "loki_ingester.Chunk")
 	proto.RegisterType((*Series)(nil), "loki_ingester.Series")
+	proto.RegisterType((*TransferStreamsResponse)(nil), "loki_ingester.TransferStreamsResponse")
 }
 
 func init() { proto.RegisterFile("pkg/ingester/checkpoint.proto", fileDescriptor_00f4b7152db9bdb5) }
 
 var fileDescriptor_00f4b7152db9bdb5 = []byte{
-	// 521 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x31, 0x8f, 0xd3, 0x30,
-	0x14, 0x8e, 0xdb, 0x5c, 0xae, 0xe7, 0xc2, 0x62, 0x9d, 0x90, 0x29, 0xc2, 0xad, 0x6e, 0xea, 0x94,
-	0x48, 0xbd, 0x1b, 0x18, 0x10, 0xd2, 0xf5, 0x10, 0x12, 0x52, 0x07, 0x14, 0x8e, 0x85, 0x05, 0xb9,
-	0x89, 0xeb, 0x44, 0x4d, 0xe3, 0xc8, 0x76, 0x91, 0xba, 0xf1, 0x13, 0x6e, 0xe3, 0x2f, 0xf0, 0x53,
-	0x6e, 0xec, 0x78, 0x02, 0xe9, 0xa0, 0xe9, 0xc2, 0x78, 0x3f, 0x01, 0xd9, 0x49, 0x4a, 0xd9, 0xe8,
-	0xf6, 0xbe, 0xf7, 0xfc, 0xbd, 0x4f, 0xfe, 0xde, 0x07, 0x9f, 0x17, 0x73, 0x1e, 0xa4, 0x39, 0x67,
-	0x4a, 0x33, 0x19, 0x44, 0x09, 0x8b, 0xe6, 0x85, 0x48, 0x73, 0xed, 0x17, 0x52, 0x68, 0x81, 0x1e,
-	0x67, 0x62, 0x9e, 0x7e, 0x6a, 0xe6, 0xbd, 0x53, 0x2e, 0xb8, 0xb0, 0x93, 0xc0, 0x54, 0xd5, 0xa3,
-	0x5e, 0x9f, 0x0b, 0xc1, 0x33, 0x16, 0x58, 0x34, 0x5d, 0xce, 0x02, 0x9d, 0x2e, 0x98, 0xd2, 0x74,
-	0x51, 0xd4, 0x0f, 0x9e, 0x19, 0x91, 0x4c, 0xf0, 0x8a, 0xd9, 0x14, 0xd5, 0xf0, 0xec, 0x47, 0x0b,
-	0x1e, 0x5d, 0x25, 0xcb, 0x7c, 0x8e, 0x5e, 0x40, 0x77, 0x26, 0xc5, 0x02, 0x83, 0x01, 0x18, 0x76,
-	0x47, 0x3d, 0xbf, 0x5a, 0xeb, 0x37, 0x6b, 0xfd, 0xeb, 0x66, 0xed, 0xb8, 0x73, 0x7b, 0xdf, 0x77,
-	0x6e, 0x7e, 0xf6, 0x41, 0x68, 0x19, 0xe8, 0x02, 0xb6, 0xb4, 0xc0, 0xad, 0x03, 0x78, 0x2d, 0x2d,
-	0xd0, 0x18, 0x9e, 0xcc, 0xb2, 0xa5, 0x4a, 0x58, 0x7c, 0xa9, 0x71, 0xfb, 0x00, 0xf2, 0x5f, 0x1a,
-	0x7a, 0x03, 0xbb, 0x19, 0x55, 0xfa, 0x43, 0x11, 0x53, 0xcd, 0x62, 0xec, 0x1e, 0xb0, 0x65, 0x9f,
-	0x88, 0x9e, 0x40, 0x2f, 0xca, 0x84, 0x62, 0x31, 0x3e, 0x1a, 0x80, 0x61, 0x27, 0xac, 0x91, 0xe9,
-	0xab, 0x55, 0x1e, 0xb1, 0x18, 0x7b, 0x55, 0xbf, 0x42, 0x08, 0x41, 0x37, 0xa6, 0x9a, 0xe2, 0xe3,
-	0x01, 0x18, 0x3e, 0x0a, 0x6d, 0x6d, 0x7a, 0x09, 0xa3, 0x31, 0xee, 0x54, 0x3d, 0x53, 0x9f, 0x7d,
-	0x6d, 0x43, 0xef, 0x3d, 0x93, 0x29, 0x53, 0x66, 0xd5, 0x52, 0x31, 0xf9, 0xf6, 0xb5, 0x35, 0xf8,
-	0x24, 0xac, 0x11, 0x1a, 0xc0, 0xee, 0xcc, 0x5c, 0x58, 0x16, 0x32, 0xcd, 0xb5, 0x75, 0xd1, 0x0d,
-	0xf7, 0x5b, 0x48, 0x40, 0x2f, 0xa3, 0x53, 0x96, 0x29, 0xdc, 0x1e, 0xb4, 0x87, 0xdd, 0xd1, 0x53,
-	0x7f, 0x77, 0xc3, 0x09, 0xe3, 0x34, 0x5a, 0x4d, 0xcc, 0xf4, 0x1d, 0x4d, 0xe5, 0xf8, 0xa5, 0xf9,
-	0xde, 0xf7, 0xfb, 0xfe, 0x05, 0x4f, 0x75, 0xb2, 0x9c, 0xfa, 0x91, 0x58, 0x04, 0x5c, 0xd2, 0x19,
-	0xcd, 0x69, 0x60, 0xb2, 0x14, 0x7c, 0x3e, 0x0f, 0xf6, 0xd3, 0xe0, 0x5b, 0xea, 0x65, 0x4c, 0x0b,
-	0xcd, 0x64, 0x58, 0xcb, 0xa0, 0x11, 0xf4, 0x22, 0x13, 0x09, 0x85, 0x5d, 0x2b, 0x78, 0xea, 0xff,
-	0x93, 0x43, 0xdf, 0xe6, 0x65, 0xec, 0x1a, 0xad, 0xb0, 0x7e, 0x59, 0x67, 0xe0, 0xe8, 0xc0, 0x0c,
-	0xf4, 0x60, 0xc7, 0x9c, 0x61, 0x92, 0xe6, 0xcc, 0x3a, 0x7c, 0x12, 0xee, 0x30, 0xc2, 0xf0, 0x98,
-	0xe5, 0x5a, 0xae, 0xae, 0xb4, 0xb5, 0xb9, 0x1d, 0x36, 0xd0, 0x24, 0x27, 0x49, 0x79, 0xc2, 0x94,
-	0xbe, 0x56, 0xd6, 0xee, 0xff, 0x4e, 0xce, 0x8e, 0x36, 0x7e, 0xb5, 0xde, 0x10, 0xe7, 0x6e, 0x43,
-	0x9c, 0x87, 0x0d, 0x01, 0x5f, 0x4a, 0x02, 0xbe, 0x95, 0x04, 0xdc, 0x96, 0x04, 0xac, 0x4b, 0x02,
-	0x7e, 0x95, 0x04, 0xfc, 0x2e, 0x89, 0xf3, 0x50, 0x12, 0x70, 0xb3, 0x25, 0xce, 0x7a, 0x4b, 0x9c,
-	0xbb, 0x2d, 0x71, 0x3e, 0x76, 0x1a, 0x0f, 0xa6, 0x9e, 0x15, 0x3a, 0xff, 0x13, 0x00, 0x00, 0xff,
-	0xff, 0x4e, 0xf6, 0xfc, 0x95, 0xc2, 0x03, 0x00, 0x00,
+	// 603 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xf5, 0xc6, 0x89, 0x9b, 0x6e, 0x40, 0x48, 0xab, 0x02, 0x26, 0x88, 0x4d, 0x94, 0x03, 0xca,
+	0xc9, 0x96, 0xd2, 0x1e, 0x38, 0x20, 0xa4, 0xa6, 0x08, 0x81, 0xd4, 0x03, 0xda, 0x86, 0x0b, 0x97,
+	0x6a, 0x63, 0xaf, 0x3f, 0x14, 0xc7, 0x6b, 0xed, 0x6e, 0x90, 0x72, 0xe3, 0x27, 0xf4, 0xc6, 0x5f,
+	0xe0, 0xa7, 0xf4, 0xd8, 0x63, 0x05, 0x52, 0xa1, 0xee, 0x85, 0x63, 0x7f, 0x02, 0xda, 0xb5, 0x5d,
+	0x4a, 0x25, 0x24, 0x72, 0x9b, 0xf7, 0x66, 0xdf, 0x8c, 0xfd, 0xe6, 0xc1, 0x67, 0xc5, 0x22, 0xf6,
+	0xd3, 0x3c, 0x66, 0x52, 0x31, 0xe1, 0x07, 0x09, 0x0b, 0x16, 0x05, 0x4f, 0x73, 0xe5, 0x15, 0x82,
+	0x2b, 0x8e, 0xee, 0x67, 0x7c, 0x91, 0x1e, 0x37, 0xfd, 0xfe, 0x4e, 0xcc, 0x63, 0x6e, 0x3a, 0xbe,
+	0xae, 0xaa, 0x47, 0xfd, 0x41, 0xcc, 0x79, 0x9c, 0x31, 0xdf, 0xa0, 0xf9, 0x2a, 0xf2, 0x55, 0xba,
+	0x64, 0x52, 0xd1, 0x65, 0x51, 0x3f, 0x78, 0xaa, 0x97, 0x64, 0x3c, 0xae, 0x94, 0x4d, 0x51, 0x35,
+	0x47, 0xdf, 0x5b, 0xb0, 0x73, 0x90, 0xac, 0xf2, 0x05, 0x7a, 0x01, 0xdb, 0x91, 0xe0, 0x4b, 0x17,
+	0x0c, 0xc1, 0xb8, 0x37, 0xe9, 0x7b, 0xd5, 0x58, 0xaf, 0x19, 0xeb, 0xcd, 0x9a, 0xb1, 0xd3, 0xee,
+	0xe9, 0xc5, 0xc0, 0x3a, 0xf9, 0x31, 0x00, 0xc4, 0x28, 0xd0, 0x1e, 0x6c, 0x29, 0xee, 0xb6, 0x36,
+	0xd0, 0xb5, 0x14, 0x47, 0x53, 0xb8, 0x1d, 0x65, 0x2b, 0x99, 0xb0, 0x70, 0x5f, 0xb9, 0xf6, 0x06,
+	0xe2, 0x3f, 0x32, 0xf4, 0x06, 0xf6, 0x32, 0x2a, 0xd5, 0x87, 0x22, 0xa4, 0x8a, 0x85, 0x6e, 0x7b,
+	0x83, 0x29, 0xb7, 0x85, 0xe8, 0x11, 0x74, 0x82, 0x8c, 0x4b, 0x16, 0xba, 0x9d, 0x21, 0x18, 0x77,
+	0x49, 0x8d, 0x34, 0x2f, 0xd7, 0x79, 0xc0, 0x42, 0xd7, 0xa9, 0xf8, 0x0a, 0x21, 0x04, 0xdb, 0x21,
+	0x55, 0xd4, 0xdd, 0x1a, 0x82, 0xf1, 0x3d, 0x62, 0x6a, 0xcd, 0x25, 0x8c, 0x86, 0x6e, 0xb7, 0xe2,
+	0x74, 0x3d, 0xfa, 0x62, 0x43, 0xe7, 0x88, 0x89, 0x94, 0x49, 0x3d, 0x6a, 0x25, 0x99, 0x78, 0xf7,
+	0xda, 0x18, 0xbc, 0x4d, 0x6a, 0x84, 0x86, 0xb0, 0x17, 0xe9, 0x0b, 0x8b, 0x42, 0xa4, 0xb9, 0x32,
+	0x2e, 0xb6, 0xc9, 0x6d, 0x0a, 0x71, 0xe8, 0x64, 0x74, 0xce, 0x32, 0xe9, 0xda, 0x43, 0x7b, 0xdc,
+	0x9b, 0x3c, 0xf1, 0x6e, 0x6e, 0x78, 0xc8, 0x62, 0x1a, 0xac, 0x0f, 0x75, 0xf7, 0x3d, 0x4d, 0xc5,
+	0xf4, 0xa5, 0xfe, 0xbd, 0x6f, 0x17, 0x83, 0xbd, 0x38, 0x55, 0xc9, 0x6a, 0xee, 0x05, 0x7c, 0xe9,
+	0xc7, 0x82, 0x46, 0x34, 0xa7, 0xbe, 0xce, 0x92, 0xff, 0x69, 0xd7, 0xbf, 0x9d, 0x06, 0xcf, 0x48,
+	0xf7, 0x43, 0x5a, 0x28, 0x26, 0x48, 0xbd, 0x06, 0x4d, 0xa0, 0x13, 0xe8, 0x48, 0x48, 0xb7, 0x6d,
+	0x16, 0xee, 0x78, 0x7f, 0xe5, 0xd0, 0x33, 0x79, 0x99, 0xb6, 0xf5, 0x2e, 0x52, 0xbf, 0xac, 0x33,
+	0xd0, 0xd9, 0x30, 0x03, 0x7d, 0xd8, 0xd5, 0x67, 0x38, 0x4c, 0x73, 0x66, 0x1c, 0xde, 0x26, 0x37,
+	0x18, 0xb9, 0x70, 0x8b, 0xe5, 0x4a, 0xac, 0x0f, 0x94, 0xb1, 0xd9, 0x26, 0x0d, 0xd4, 0xc9, 0x49,
+	0xd2, 0x38, 0x61, 0x52, 0xcd, 0xa4, 0xb1, 0xfb, 0xbf, 0x93, 0x73, 0x23, 0x1b, 0x51, 0xf8, 0x78,
+	0x26, 0x68, 0x2e, 0x23, 0x26, 0x8e, 0x94, 0x60, 0x74, 0x29, 0x09, 0x93, 0x05, 0xcf, 0xa5, 0x59,
+	0x2c, 0x2b, 0xca, 0x9c, 0xca, 0x26, 0x0d, 0x34, 0x31, 0xa9, 0x8c, 0x69, 0x99, 0x46, 0xf3, 0xf3,
+	0x3b, 0xb0, 0x33, 0x5f, 0x2b, 0x26, 0x4d, 0x8c, 0x6d, 0x52, 0x81, 0xc9, 0x31, 0xdc, 0x7a, 0x4b,
+	0xf3, 0x90, 0x47, 0x11, 0x9a, 0xc1, 0x07, 0x77, 0xb6, 0xa1, 0x87, 0x77, 0x4c, 0xad, 0x62, 0xd2,
+	0x7f, 0x7e, 0x87, 0xfe, 0xc7, 0x47, 0x8e, 0xac, 0x31, 0x98, 0xbe, 0x3a, 0xbb, 0xc4, 0xd6, 0xf9,
+	0x25, 0xb6, 0xae, 0x2f, 0x31, 0xf8, 0x5c, 0x62, 0xf0, 0xb5, 0xc4, 0xe0, 0xb4, 0xc4, 0xe0, 0xac,
+	0xc4, 0xe0, 0x67, 0x89, 0xc1, 0xaf, 0x12, 0x5b, 0xd7, 0x25, 0x06, 0x27, 0x57, 0xd8, 0x3a, 0xbb,
+	0xc2, 0xd6, 0xf9, 0x15, 0xb6, 0x3e, 0x76, 0x9b, 0xd9, 0x73, 0xc7, 0x98, 0xb5, 0xfb, 0x3b, 0x00,
+	0x00, 0xff, 0xff, 0x55, 0x7b, 0xc4, 0xd9, 0x86, 0x04, 0x00, 0x00,
 }
 
 func (this *Chunk) Equal(that interface{}) bool {
@@ -378,6 +449,36 @@ func (this *Series) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *TransferStreamsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TransferStreamsResponse)
+	if !ok {
+		that2, ok := that.(TransferStreamsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Streams != that1.Streams {
+		return false
+	}
+	if this.Chunks != that1.Chunks {
+		return false
+	}
+	if this.Bytes != that1.Bytes {
+		return false
+	}
+	return true
+}
 func (this *Chunk) GoString() string {
 	if this == nil {
 		return "nil"
@@ -418,6 +519,18 @@ func (this *Series) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *TransferStreamsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&ingester.TransferStreamsResponse{")
+	s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	s = append(s, "Chunks: "+fmt.Sprintf("%#v", this.Chunks)+",\n")
+	s = append(s, "Bytes: "+fmt.Sprintf("%#v", this.Bytes)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringCheckpoint(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -426,6 +539,121 @@ func valueToGoStringCheckpoint(v interface{}, typ string) string {
 	pv := reflect.Indirect(rv).Interface()
 	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
 }
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// HandoffClient is the client API for Handoff service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HandoffClient interface {
+	TransferStreams(ctx context.Context, opts ...grpc.CallOption) (Handoff_TransferStreamsClient, error)
+}
+
+type handoffClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHandoffClient(cc *grpc.ClientConn) HandoffClient {
+	return &handoffClient{cc}
+}
+
+func (c *handoffClient) TransferStreams(ctx context.Context, opts ...grpc.CallOption) (Handoff_TransferStreamsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Handoff_serviceDesc.Streams[0], "/loki_ingester.Handoff/TransferStreams", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &handoffTransferStreamsClient{stream}
+	return x, nil
+}
+
+type Handoff_TransferStreamsClient interface {
+	Send(*Series) error
+	CloseAndRecv() (*TransferStreamsResponse, error)
+	grpc.ClientStream
+}
+
+type handoffTransferStreamsClient struct {
+	grpc.ClientStream
+}
+
+func (x *handoffTransferStreamsClient) Send(m *Series) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *handoffTransferStreamsClient) CloseAndRecv() (*TransferStreamsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(TransferStreamsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// HandoffServer is the server API for Handoff service.
+type HandoffServer interface {
+	TransferStreams(Handoff_TransferStreamsServer) error
+}
+
+// UnimplementedHandoffServer can be embedded to have forward compatible implementations.
+type UnimplementedHandoffServer struct {
+}
+
+func (*UnimplementedHandoffServer) TransferStreams(srv Handoff_TransferStreamsServer) error {
+	return status.Errorf(codes.Unimplemented, "method TransferStreams not implemented")
+}
+
+func RegisterHandoffServer(s *grpc.Server, srv HandoffServer) {
+	s.RegisterService(&_Handoff_serviceDesc, srv)
+}
+
+func _Handoff_TransferStreams_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(HandoffServer).TransferStreams(&handoffTransferStreamsServer{stream})
+}
+
+type Handoff_TransferStreamsServer interface {
+	SendAndClose(*TransferStreamsResponse) error
+	Recv() (*Series, error)
+	grpc.ServerStream
+}
+
+type handoffTransferStreamsServer struct {
+	grpc.ServerStream
+}
+
+func (x *handoffTransferStreamsServer) SendAndClose(m *TransferStreamsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *handoffTransferStreamsServer) Recv() (*Series, error) {
+	m := new(Series)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Handoff_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "loki_ingester.Handoff",
+	HandlerType: (*HandoffServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TransferStreams",
+			Handler:       _Handoff_TransferStreams_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/ingester/checkpoint.proto",
+}
+
 func (m *Chunk) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -606,6 +834,44 @@ func (m *Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *TransferStreamsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TransferStreamsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TransferStreamsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Bytes != 0 {
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Bytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Chunks != 0 {
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Chunks))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Streams != 0 {
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Streams))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintCheckpoint(dAtA []byte, offset int, v uint64) int {
 	offset -= sovCheckpoint(v)
 	base := offset
@@ -687,6 +953,24 @@ func (m *Series) Size() (n int) {
 	return n
 }
 
+func (m *TransferStreamsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Streams != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Streams))
+	}
+	if m.Chunks != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Chunks))
+	}
+	if m.Bytes != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Bytes))
+	}
+	return n
+}
+
 func sovCheckpoint(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -732,6 +1016,18 @@ func (this *Series) String() string {
 	}, "")
 	return s
 }
+func (this *TransferStreamsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TransferStreamsResponse{`,
+		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
+		`Chunks:` + fmt.Sprintf("%v", this.Chunks) + `,`,
+		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringCheckpoint(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1322,6 +1618,116 @@ func (m *Series) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *TransferStreamsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TransferStreamsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TransferStreamsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
+			}
+			m.Streams = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Streams |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+			}
+			m.Chunks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Chunks |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
+			}
+			m.Bytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipCheckpoint(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
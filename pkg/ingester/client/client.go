@@ -64,6 +64,21 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 
 // New returns a new ingester client.
 func New(cfg Config, addr string) (HealthAndIngesterClient, error) {
+	conn, err := Dial(cfg, addr)
+	if err != nil {
+		return nil, err
+	}
+	return ClosableHealthAndIngesterClient{
+		PusherClient:     logproto.NewPusherClient(conn),
+		QuerierClient:    logproto.NewQuerierClient(conn),
+		StreamDataClient: logproto.NewStreamDataClient(conn),
+		HealthClient:     grpc_health_v1.NewHealthClient(conn),
+		Closer:           conn,
+	}, nil
+}
+
+// Dial returns a new gRPC connection to an ingester, configured and instrumented like the ingester clients.
+func Dial(cfg Config, addr string) (*grpc.ClientConn, error) {
 	opts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(cfg.GRPCClientConfig.CallOptions()...),
 	}
@@ -76,17 +91,7 @@ func New(cfg Config, addr string) (HealthAndIngesterClient, error) {
 	opts = append(opts, dialOpts...)
 
 	// nolint:staticcheck // grpc.Dial() has been deprecated; we'll address it before upgrading to gRPC 2.
-	conn, err := grpc.Dial(addr, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return ClosableHealthAndIngesterClient{
-		PusherClient:     logproto.NewPusherClient(conn),
-		QuerierClient:    logproto.NewQuerierClient(conn),
-		StreamDataClient: logproto.NewStreamDataClient(conn),
-		HealthClient:     grpc_health_v1.NewHealthClient(conn),
-		Closer:           conn,
-	}, nil
+	return grpc.Dial(addr, opts...)
 }
 
 func instrumentation(cfg *Config) ([]grpc.UnaryClientInterceptor, []grpc.StreamClientInterceptor) {
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// rejectedStreamsMetadataKey is the gRPC metadata key set by the distributors on push requests
+// to get the streams rejected by the ingesters listed in the push responses, instead of failing
+// the whole push with the error of one of them. Ingesters not supporting it keep failing the push.
+const rejectedStreamsMetadataKey = "x-loki-push-rejected-streams"
+
+// InjectRejectedStreams returns a context asking the ingesters to list the streams they reject
+// in the push responses.
+func InjectRejectedStreams(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, rejectedStreamsMetadataKey, "true")
+}
+
+// ExtractRejectedStreams returns whether the push request of the context asks for the rejected
+// streams to be listed in the push response.
+func ExtractRejectedStreams(ctx context.Context) bool {
+	values := metadata.ValueFromIncomingContext(ctx, rejectedStreamsMetadataKey)
+	return len(values) > 0 && values[0] == "true"
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/backoff"
-	"github.com/grafana/dskit/ring"
 	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
@@ -105,7 +104,7 @@ func (f *flushReasonCounter) IncrementForReason(reason string) error {
 func (i *Ingester) InitFlushQueues() {
 	i.flushQueuesDone.Add(i.cfg.ConcurrentFlushes)
 	for j := 0; j < i.cfg.ConcurrentFlushes; j++ {
-		i.flushQueues[j] = util.NewPriorityQueue(i.metrics.flushQueueLength)
+		i.flushQueues[j] = util.NewPriorityQueue(i.flushQueueLength)
 		go i.flushLoop(j)
 	}
 }
@@ -117,13 +116,6 @@ func (i *Ingester) Flush() {
 	i.flush(true)
 }
 
-// TransferOut implements ring.FlushTransferer
-// Noop implementation because ingesters have a WAL now that does not require transferring chunks any more.
-// We return ErrTransferDisabled to indicate that we don't support transfers, and therefore we may flush on shutdown if configured to do so.
-func (i *Ingester) TransferOut(_ context.Context) error {
-	return ring.ErrTransferDisabled
-}
-
 func (i *Ingester) flush(mayRemoveStreams bool) {
 	i.sweepUsers(true, mayRemoveStreams)
 
@@ -389,6 +381,16 @@ func (i *Ingester) removeFlushedChunks(instance *instance, stream *stream, mayRe
 		stream.chunks = stream.chunks[1:]
 	}
 	i.metrics.memoryChunks.Sub(float64(prevNumChunks - len(stream.chunks)))
+	if len(stream.chunks) > 0 && len(stream.chunks) != prevNumChunks {
+		// The entries of the removed chunks are not looked up anymore.
+		from, _ := stream.chunks[0].chunk.Bounds()
+		for _, c := range stream.chunks[1:] {
+			if mint, _ := c.chunk.Bounds(); mint.Before(from) {
+				from = mint
+			}
+		}
+		instance.structuredMetadataIndexer.index.Prune(stream.fp, from)
+	}
 
 	// Signal how much data has been flushed to lessen any WAL replay pressure.
 	i.replayController.Sub(int64(subtracted))
@@ -0,0 +1,319 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/ring"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	tsdb_record "github.com/prometheus/prometheus/tsdb/record"
+
+	"github.com/grafana/loki/v3/pkg/chunkenc"
+	"github.com/grafana/loki/v3/pkg/ingester/client"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	lokiring "github.com/grafana/loki/v3/pkg/util/ring"
+)
+
+const (
+	transferSent     = "sent"
+	transferReceived = "received"
+)
+
+// transferClient transfers in-memory streams to another ingester.
+type transferClient interface {
+	HandoffClient
+	io.Closer
+}
+
+type closableTransferClient struct {
+	HandoffClient
+	io.Closer
+}
+
+func newTransferClient(cfg client.Config, addr string) (transferClient, error) {
+	// A transfer holds the streams of all the tenants, so it is not sent on behalf of a tenant.
+	cfg.Internal = true
+	conn, err := client.Dial(cfg, addr)
+	if err != nil {
+		return nil, err
+	}
+	return closableTransferClient{
+		HandoffClient: NewHandoffClient(conn),
+		Closer:        conn,
+	}, nil
+}
+
+// TransferOut implements ring.FlushTransferer
+// When transfer_on_shutdown is enabled, the in-memory streams are transferred to the ingesters taking over their
+// ownership in the ring, instead of being flushed. If the transfer fails, the Lifecycler flushes the chunks.
+// We return ErrTransferDisabled when the chunks would not be flushed on shutdown, for instance when they are
+// recovered from the WAL on restart, so the Lifecycler behaves as if transfers were not supported.
+func (i *Ingester) TransferOut(ctx context.Context) error {
+	if !i.cfg.TransferOnShutdown || !i.lifecycler.FlushOnShutdown() {
+		return ring.ErrTransferDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, i.cfg.TransferTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := i.transferOut(ctx); err != nil {
+		i.metrics.transferFailuresTotal.Inc()
+		return err
+	}
+	level.Info(i.logger).Log("msg", "transferred the in-memory streams", "duration", time.Since(start))
+	return nil
+}
+
+func (i *Ingester) transferOut(ctx context.Context) error {
+	if err := i.waitForLeaving(ctx); err != nil {
+		return err
+	}
+
+	transfers := map[string]*streamTransfer{}
+	defer func() {
+		for _, t := range transfers {
+			t.close()
+		}
+	}()
+
+	it := newStreamsIterator(i)
+	for it.Next() {
+		series := it.Stream()
+		targets, err := i.transferTargets(series)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			t, ok := transfers[target.Addr]
+			if !ok {
+				t, err = i.openTransfer(ctx, target.Addr)
+				if err != nil {
+					return err
+				}
+				transfers[target.Addr] = t
+			}
+			if err := t.send(series); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	// Streams already transferred to other ingesters are flushed as well if one of the transfers fails,
+	// their entries are deduplicated at query time.
+	for _, t := range transfers {
+		if err := t.finish(); err != nil {
+			return err
+		}
+		i.metrics.transferredStreamsTotal.WithLabelValues(transferSent).Add(float64(t.sent.Streams))
+		i.metrics.transferredChunksTotal.WithLabelValues(transferSent).Add(float64(t.sent.Chunks))
+		level.Info(i.logger).Log("msg", "transferred in-memory streams", "ingester", t.addr, "streams", t.sent.Streams, "chunks", t.sent.Chunks, "bytes", t.sent.Bytes)
+	}
+	return nil
+}
+
+// waitForLeaving waits until the ring sees this ingester as LEAVING, so the streams are written to the
+// ingesters taking over their ownership.
+func (i *Ingester) waitForLeaving(ctx context.Context) error {
+	b := backoff.New(ctx, backoff.Config{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+	})
+	for b.Ongoing() {
+		state, err := i.readRing.GetInstanceState(i.lifecycler.ID)
+		if err == nil && state == ring.LEAVING {
+			return nil
+		}
+		b.Wait()
+	}
+	return fmt.Errorf("waiting for the ring to see the ingester as LEAVING: %w", b.Err())
+}
+
+// transferTargets returns the ingesters a stream is transferred to: the ingesters it is written to now that this
+// ingester is leaving, which were not already replicas of the stream.
+func (i *Ingester) transferTargets(series *Series) ([]ring.InstanceDesc, error) {
+	lbs := logproto.FromLabelAdaptersToLabels(series.Labels).String()
+	token := lokiring.TokenFor(series.UserID, lbs)
+	writeSet, err := i.readRing.Get(token, ring.Write, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the ingesters taking over stream %s: %w", lbs, err)
+	}
+	// This ingester is still a replica of the streams for the reads while it is LEAVING.
+	replicas, err := i.readRing.Get(token, ring.Read, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the replicas of stream %s: %w", lbs, err)
+	}
+
+	var targets []ring.InstanceDesc
+	for _, instance := range writeSet.Instances {
+		if instance.Id != i.lifecycler.ID && !replicas.Includes(instance.Addr) {
+			targets = append(targets, instance)
+		}
+	}
+	return targets, nil
+}
+
+func (i *Ingester) openTransfer(ctx context.Context, addr string) (*streamTransfer, error) {
+	c, err := i.cfg.transferClientFactory(i.clientConfig, addr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to ingester %s: %w", addr, err)
+	}
+	stream, err := c.TransferStreams(ctx)
+	if err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("error transferring streams to ingester %s: %w", addr, err)
+	}
+	return &streamTransfer{
+		addr:   addr,
+		client: c,
+		stream: stream,
+	}, nil
+}
+
+// streamTransfer sends streams to an ingester, keeping the totals sent to verify the transfer.
+type streamTransfer struct {
+	addr   string
+	client transferClient
+	stream Handoff_TransferStreamsClient
+	sent   TransferStreamsResponse
+}
+
+func (t *streamTransfer) send(series *Series) error {
+	if err := t.stream.Send(series); err != nil {
+		if err == io.EOF {
+			// The ingester stopped receiving the streams, its error is returned when closing the transfer.
+			_, err = t.stream.CloseAndRecv()
+		}
+		return fmt.Errorf("error transferring streams to ingester %s: %w", t.addr, err)
+	}
+	t.sent.Streams++
+	t.sent.Chunks += int64(len(series.Chunks))
+	t.sent.Bytes += seriesBytes(series)
+	return nil
+}
+
+// finish waits for the ingester to add the streams sent, and verifies it received all of them.
+func (t *streamTransfer) finish() error {
+	received, err := t.stream.CloseAndRecv()
+	if err != nil {
+		return fmt.Errorf("error transferring streams to ingester %s: %w", t.addr, err)
+	}
+	if received.Streams != t.sent.Streams || received.Chunks != t.sent.Chunks || received.Bytes != t.sent.Bytes {
+		return fmt.Errorf("ingester %s received %d streams, %d chunks and %d bytes, expected %d streams, %d chunks and %d bytes",
+			t.addr, received.Streams, received.Chunks, received.Bytes, t.sent.Streams, t.sent.Chunks, t.sent.Bytes)
+	}
+	return nil
+}
+
+func (t *streamTransfer) close() {
+	_ = t.client.Close()
+}
+
+func seriesBytes(series *Series) int64 {
+	var n int64
+	for _, c := range series.Chunks {
+		n += int64(len(c.Data) + len(c.Head))
+	}
+	return n
+}
+
+// TransferStreams receives the in-memory streams of a leaving ingester. The streams are only added once all of them
+// are received and their chunks decoded, so an interrupted transfer, after which the leaving ingester flushes its
+// chunks, does not leave part of them in memory.
+// The entries received are written to the WAL before the transfer is acknowledged, as the leaving ingester does not
+// flush the chunks it transferred.
+func (i *Ingester) TransferStreams(stream Handoff_TransferStreamsServer) error {
+	if i.readonly {
+		return ErrReadOnly
+	}
+
+	var (
+		received []*Series
+		decoded  [][]chunkDesc
+		resp     TransferStreamsResponse
+	)
+	for {
+		series, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		chunks, err := fromWireChunks(&i.cfg, chunkenc.UnorderedWithStructuredMetadataHeadBlockFmt, series.Chunks)
+		if err != nil {
+			return fmt.Errorf("error decoding the chunks of stream %s: %w", logproto.FromLabelAdaptersToLabels(series.Labels), err)
+		}
+		received = append(received, series)
+		decoded = append(decoded, chunks)
+		resp.Streams++
+		resp.Chunks += int64(len(series.Chunks))
+		resp.Bytes += seriesBytes(series)
+	}
+
+	for n, series := range received {
+		inst, err := i.GetOrCreateInstance(series.UserID)
+		if err != nil {
+			return err
+		}
+		s, _, _, err := inst.recoverSeries(series)
+		if err != nil {
+			return fmt.Errorf("error adding stream %s: %w", logproto.FromLabelAdaptersToLabels(series.Labels), err)
+		}
+		i.metrics.memoryChunks.Add(float64(len(series.Chunks)))
+		if err := i.logTransferredStream(stream.Context(), series.UserID, s, decoded[n]); err != nil {
+			return fmt.Errorf("error writing stream %s to the WAL: %w", logproto.FromLabelAdaptersToLabels(series.Labels), err)
+		}
+	}
+	i.metrics.transferredStreamsTotal.WithLabelValues(transferReceived).Add(float64(resp.Streams))
+	i.metrics.transferredChunksTotal.WithLabelValues(transferReceived).Add(float64(resp.Chunks))
+	level.Info(i.logger).Log("msg", "received transferred in-memory streams", "streams", resp.Streams, "chunks", resp.Chunks, "bytes", resp.Bytes)
+
+	return stream.SendAndClose(&resp)
+}
+
+// logTransferredStream writes the entries of the chunks transferred to a stream to the WAL, so that they are replayed
+// if the ingester restarts before its next checkpoint.
+func (i *Ingester) logTransferredStream(ctx context.Context, userID string, s *stream, descs []chunkDesc) error {
+	record := recordPool.GetRecord()
+	record.UserID = userID
+	defer recordPool.PutRecord(record)
+
+	record.Series = append(record.Series, tsdb_record.RefSeries{
+		Ref:    chunks.HeadSeriesRef(s.fp),
+		Labels: s.labels,
+	})
+
+	var entries []logproto.Entry
+	pipeline := log.NewNoopPipeline().ForStream(s.labels)
+	for _, c := range descs {
+		from, through := c.chunk.Bounds()
+		it, err := c.chunk.Iterator(ctx, from, through.Add(time.Nanosecond), logproto.FORWARD, pipeline)
+		if err != nil {
+			return err
+		}
+		for it.Next() {
+			entries = append(entries, it.At())
+		}
+		err = it.Err()
+		it.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Like pushes, the entries advance the counter of the stream, so that they are not skipped by the replay.
+	s.chunkMtx.Lock()
+	s.entryCt += int64(len(entries))
+	record.AddEntries(uint64(s.fp), s.entryCt, entries...)
+	s.chunkMtx.Unlock()
+	return i.wal.Log(record)
+}
@@ -0,0 +1,238 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
+	"github.com/grafana/loki/v3/pkg/ingester/client"
+	"github.com/grafana/loki/v3/pkg/ingester/wal"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/runtime"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestTransferOut(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	leaving := ring.InstanceDesc{Id: "localhost", Addr: "localhost", State: ring.LEAVING}
+	replica := ring.InstanceDesc{Id: "replica", Addr: "replica", State: ring.ACTIVE}
+	owner := ring.InstanceDesc{Id: "owner", Addr: "owner", State: ring.ACTIVE}
+
+	newIngester := func(t *testing.T, cfg Config, readRing ring.ReadRing) *Ingester {
+		i, err := New(cfg, client.Config{}, &mockStore{chunks: map[string][]chunk.Chunk{}}, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, log.NewNopLogger(), nil, readRing, nil)
+		require.NoError(t, err)
+		return i
+	}
+
+	setup := func(t *testing.T, receiver *Ingester) (*Ingester, map[string]int) {
+		cfg := defaultIngesterTestConfig(t)
+		cfg.TransferOnShutdown = true
+		cfg.TransferTimeout = time.Second
+		transfers := map[string]int{}
+		cfg.transferClientFactory = func(_ client.Config, addr string) (transferClient, error) {
+			transfers[addr]++
+			return &loopbackTransferClient{receiver: receiver}, nil
+		}
+		readRing := &handoffRingMock{
+			readRingMock: mockReadRingWithOneActiveIngester(),
+			state:        ring.LEAVING,
+			writeSet:     ring.ReplicationSet{Instances: []ring.InstanceDesc{replica, owner}},
+			replicas:     ring.ReplicationSet{Instances: []ring.InstanceDesc{leaving, replica}},
+		}
+		sender := newIngester(t, cfg, readRing)
+
+		ctx := user.InjectOrgID(context.Background(), "test")
+		req := &logproto.PushRequest{Streams: []logproto.Stream{
+			{Labels: `{app="foo"}`},
+			{Labels: `{app="bar"}`},
+		}}
+		for i := 0; i < 10; i++ {
+			for s := range req.Streams {
+				req.Streams[s].Entries = append(req.Streams[s].Entries, logproto.Entry{
+					Timestamp: time.Unix(int64(i), 0),
+					Line:      fmt.Sprintf("line %d", i),
+				})
+			}
+		}
+		_, err := sender.Push(ctx, req)
+		require.NoError(t, err)
+
+		// Cut a block, so the transfer holds both the blocks and the head block of the chunks.
+		inst, err := sender.GetOrCreateInstance("test")
+		require.NoError(t, err)
+		_ = inst.forAllStreams(ctx, func(s *stream) error {
+			return s.chunks[0].chunk.Close()
+		})
+		_, err = sender.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+			{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(10, 0), Line: "line 10"}}},
+		}})
+		require.NoError(t, err)
+		return sender, transfers
+	}
+
+	t.Run("streams are transferred to their new owners", func(t *testing.T) {
+		receiver := newIngester(t, defaultIngesterTestConfig(t), mockReadRingWithOneActiveIngester())
+		receiverWAL := &recordingWAL{entries: map[string]int{}}
+		receiver.wal = receiverWAL
+		sender, transfers := setup(t, receiver)
+
+		require.NoError(t, sender.TransferOut(context.Background()))
+		// The replicas of the streams already hold them.
+		require.Equal(t, map[string]int{"owner": 1}, transfers)
+
+		ctx := user.InjectOrgID(context.Background(), "test")
+		result := mockQuerierServer{ctx: ctx}
+		require.NoError(t, receiver.Query(&logproto.QueryRequest{
+			Selector:  `{app=~"foo|bar"}`,
+			Limit:     100,
+			Start:     time.Unix(0, 0),
+			End:       time.Unix(100, 0),
+			Direction: logproto.FORWARD,
+		}, &result))
+		lines := map[string]int{}
+		for _, resp := range result.resps {
+			for _, s := range resp.Streams {
+				lines[s.Labels] += len(s.Entries)
+			}
+		}
+		require.Equal(t, map[string]int{`{app="foo"}`: 11, `{app="bar"}`: 10}, lines)
+		// The entries received are written to the WAL, as the sender does not flush them.
+		require.Equal(t, lines, receiverWAL.entries)
+	})
+
+	t.Run("a failed transfer is reported so the chunks are flushed", func(t *testing.T) {
+		receiver := newIngester(t, defaultIngesterTestConfig(t), mockReadRingWithOneActiveIngester())
+		receiver.readonly = true
+		sender, _ := setup(t, receiver)
+
+		err := sender.TransferOut(context.Background())
+		require.ErrorIs(t, err, ErrReadOnly)
+		require.False(t, errors.Is(err, ring.ErrTransferDisabled))
+	})
+
+	t.Run("transfers are disabled when the chunks are not flushed on shutdown", func(t *testing.T) {
+		receiver := newIngester(t, defaultIngesterTestConfig(t), mockReadRingWithOneActiveIngester())
+		sender, transfers := setup(t, receiver)
+		sender.lifecycler.SetFlushOnShutdown(false)
+
+		require.Equal(t, ring.ErrTransferDisabled, sender.TransferOut(context.Background()))
+		require.Empty(t, transfers)
+	})
+}
+
+// handoffRingMock returns the ingesters a stream is written to, and its replicas, once the sending ingester is LEAVING.
+type handoffRingMock struct {
+	*readRingMock
+	state              ring.InstanceState
+	writeSet, replicas ring.ReplicationSet
+}
+
+func (r *handoffRingMock) Get(_ uint32, op ring.Operation, _ []ring.InstanceDesc, _ []string, _ []string) (ring.ReplicationSet, error) {
+	if op == ring.Write {
+		return r.writeSet, nil
+	}
+	return r.replicas, nil
+}
+
+func (r *handoffRingMock) GetInstanceState(_ string) (ring.InstanceState, error) {
+	return r.state, nil
+}
+
+// recordingWAL counts the entries written to the WAL per stream.
+type recordingWAL struct {
+	noopWAL
+	entries map[string]int
+}
+
+func (w *recordingWAL) Log(record *wal.Record) error {
+	streams := map[chunks.HeadSeriesRef]string{}
+	for _, s := range record.Series {
+		streams[s.Ref] = s.Labels.String()
+	}
+	for _, e := range record.RefEntries {
+		w.entries[streams[e.Ref]] += len(e.Entries)
+	}
+	return nil
+}
+
+// loopbackTransferClient transfers the streams to an ingester in the same process.
+type loopbackTransferClient struct {
+	receiver *Ingester
+}
+
+func (c *loopbackTransferClient) TransferStreams(ctx context.Context, _ ...grpc.CallOption) (Handoff_TransferStreamsClient, error) {
+	return &loopbackTransferStream{ctx: ctx, receiver: c.receiver}, nil
+}
+
+func (c *loopbackTransferClient) Close() error { return nil }
+
+type loopbackTransferStream struct {
+	grpc.ClientStream
+
+	ctx      context.Context
+	receiver *Ingester
+	series   []*Series
+}
+
+func (s *loopbackTransferStream) Send(series *Series) error {
+	// The series are reused by the sender, like when they are marshalled by gRPC.
+	b, err := series.Marshal()
+	if err != nil {
+		return err
+	}
+	var cpy Series
+	if err := cpy.Unmarshal(b); err != nil {
+		return err
+	}
+	s.series = append(s.series, &cpy)
+	return nil
+}
+
+func (s *loopbackTransferStream) CloseAndRecv() (*TransferStreamsResponse, error) {
+	server := &loopbackTransferServer{ctx: s.ctx, series: s.series}
+	if err := s.receiver.TransferStreams(server); err != nil {
+		return nil, err
+	}
+	return server.resp, nil
+}
+
+type loopbackTransferServer struct {
+	grpc.ServerStream
+
+	ctx    context.Context
+	series []*Series
+	resp   *TransferStreamsResponse
+}
+
+func (s *loopbackTransferServer) Recv() (*Series, error) {
+	if len(s.series) == 0 {
+		return nil, io.EOF
+	}
+	series := s.series[0]
+	s.series = s.series[1:]
+	return series, nil
+}
+
+func (s *loopbackTransferServer) SendAndClose(resp *TransferStreamsResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func (s *loopbackTransferServer) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,150 @@
+package index
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// StructuredMetadataRef is a stream having entries with a structured metadata pair,
+// along with the time range of these entries.
+type StructuredMetadataRef struct {
+	Fingerprint model.Fingerprint
+	From        time.Time
+	Through     time.Time
+}
+
+// StructuredMetadataIndex implements an in-memory index from the structured metadata pairs
+// of the entries to the fingerprints of their streams.
+// It is sharded by fingerprint so that a stream only locks one shard on writes.
+type StructuredMetadataIndex struct {
+	totalShards uint32
+	shards      []*structuredMetadataShard
+}
+
+// Roughly
+// map[name/value pair] => map[fingerprint] => time range of the entries
+type structuredMetadataShard struct {
+	mtx   sync.RWMutex
+	pairs map[labels.Label]map[model.Fingerprint]*timeRange
+	// streams holds the pairs of every stream, to delete them along with the stream.
+	streams map[model.Fingerprint]map[labels.Label]struct{}
+}
+
+type timeRange struct {
+	from, through int64
+}
+
+func NewStructuredMetadataIndex(totalShards uint32) *StructuredMetadataIndex {
+	shards := make([]*structuredMetadataShard, totalShards)
+	for i := range shards {
+		shards[i] = &structuredMetadataShard{
+			pairs:   map[labels.Label]map[model.Fingerprint]*timeRange{},
+			streams: map[model.Fingerprint]map[labels.Label]struct{}{},
+		}
+	}
+	return &StructuredMetadataIndex{
+		totalShards: totalShards,
+		shards:      shards,
+	}
+}
+
+func (ii *StructuredMetadataIndex) shardFor(fp model.Fingerprint) *structuredMetadataShard {
+	return ii.shards[uint64(fp)%uint64(ii.totalShards)]
+}
+
+// Add indexes an entry of the stream with the given fingerprint having the given structured metadata pair.
+func (ii *StructuredMetadataIndex) Add(fp model.Fingerprint, name, value string, ts time.Time) {
+	shard := ii.shardFor(fp)
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	nanos := ts.UnixNano()
+	pair := labels.Label{Name: name, Value: value}
+	fps, ok := shard.pairs[pair]
+	if !ok {
+		// The pair may reference the memory of a request, it is copied before being retained.
+		pair = labels.Label{Name: copyString(name), Value: copyString(value)}
+		fps = map[model.Fingerprint]*timeRange{}
+		shard.pairs[pair] = fps
+	}
+	bounds, ok := fps[fp]
+	if !ok {
+		fps[fp] = &timeRange{from: nanos, through: nanos}
+		pairs, ok := shard.streams[fp]
+		if !ok {
+			pairs = map[labels.Label]struct{}{}
+			shard.streams[fp] = pairs
+		}
+		pairs[pair] = struct{}{}
+		return
+	}
+	if nanos < bounds.from {
+		bounds.from = nanos
+	}
+	if nanos > bounds.through {
+		bounds.through = nanos
+	}
+}
+
+// Lookup returns the streams having entries with the given structured metadata pair between from and through,
+// sorted by fingerprint.
+func (ii *StructuredMetadataIndex) Lookup(name, value string, from, through time.Time) []StructuredMetadataRef {
+	pair := labels.Label{Name: name, Value: value}
+	fromNanos, throughNanos := from.UnixNano(), through.UnixNano()
+
+	var refs []StructuredMetadataRef
+	for _, shard := range ii.shards {
+		shard.mtx.RLock()
+		for fp, bounds := range shard.pairs[pair] {
+			if bounds.through < fromNanos || bounds.from > throughNanos {
+				continue
+			}
+			refs = append(refs, StructuredMetadataRef{
+				Fingerprint: fp,
+				From:        time.Unix(0, bounds.from),
+				Through:     time.Unix(0, bounds.through),
+			})
+		}
+		shard.mtx.RUnlock()
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Fingerprint < refs[j].Fingerprint })
+	return refs
+}
+
+// Delete removes the pairs of the stream with the given fingerprint.
+func (ii *StructuredMetadataIndex) Delete(fp model.Fingerprint) {
+	ii.prune(fp, math.MaxInt64)
+}
+
+// Prune removes the pairs of the stream with the given fingerprint not having entries after before,
+// once the chunks holding these entries are not in memory anymore.
+func (ii *StructuredMetadataIndex) Prune(fp model.Fingerprint, before time.Time) {
+	ii.prune(fp, before.UnixNano())
+}
+
+func (ii *StructuredMetadataIndex) prune(fp model.Fingerprint, beforeNanos int64) {
+	shard := ii.shardFor(fp)
+	shard.mtx.Lock()
+	defer shard.mtx.Unlock()
+
+	pairs := shard.streams[fp]
+	for pair := range pairs {
+		fps := shard.pairs[pair]
+		if fps[fp].through >= beforeNanos {
+			continue
+		}
+		delete(fps, fp)
+		if len(fps) == 0 {
+			delete(shard.pairs, pair)
+		}
+		delete(pairs, pair)
+	}
+	if len(pairs) == 0 {
+		delete(shard.streams, fp)
+	}
+}
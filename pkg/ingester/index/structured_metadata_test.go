@@ -0,0 +1,50 @@
+package index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredMetadataIndex(t *testing.T) {
+	ii := NewStructuredMetadataIndex(4)
+	ts := func(s int64) time.Time { return time.Unix(s, 0) }
+
+	ii.Add(1, "trace_id", "abc", ts(10))
+	ii.Add(1, "trace_id", "abc", ts(20))
+	ii.Add(1, "trace_id", "def", ts(30))
+	ii.Add(2, "trace_id", "abc", ts(40))
+	ii.Add(3, "request_id", "abc", ts(50))
+
+	require.Equal(t, []StructuredMetadataRef{
+		{Fingerprint: 1, From: ts(10), Through: ts(20)},
+		{Fingerprint: 2, From: ts(40), Through: ts(40)},
+	}, ii.Lookup("trace_id", "abc", ts(0), ts(100)))
+	require.Equal(t, []StructuredMetadataRef{
+		{Fingerprint: 2, From: ts(40), Through: ts(40)},
+	}, ii.Lookup("trace_id", "abc", ts(25), ts(100)))
+	require.Empty(t, ii.Lookup("trace_id", "ghi", ts(0), ts(100)))
+
+	// The pairs of the entries older than the oldest chunk of the stream are pruned.
+	ii.Prune(1, ts(25))
+	require.Equal(t, []StructuredMetadataRef{
+		{Fingerprint: 2, From: ts(40), Through: ts(40)},
+	}, ii.Lookup("trace_id", "abc", ts(0), ts(100)))
+	require.Len(t, ii.Lookup("trace_id", "def", ts(0), ts(100)), 1)
+
+	ii.Delete(1)
+	ii.Delete(2)
+	require.Empty(t, ii.Lookup("trace_id", "abc", ts(0), ts(100)))
+	require.Empty(t, ii.Lookup("trace_id", "def", ts(0), ts(100)))
+	require.Equal(t, []StructuredMetadataRef{
+		{Fingerprint: 3, From: ts(50), Through: ts(50)},
+	}, ii.Lookup("request_id", "abc", ts(0), ts(100)))
+
+	for _, shard := range ii.shards {
+		for fp := range shard.streams {
+			require.Equal(t, model.Fingerprint(3), fp)
+		}
+	}
+}
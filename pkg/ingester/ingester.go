@@ -5,12 +5,14 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime/pprof"
+	"strconv"
 	"sync"
 	"time"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/wlog"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
@@ -44,6 +47,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/ingester/client"
 	"github.com/grafana/loki/v3/pkg/ingester/index"
 	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/kafka"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
@@ -58,6 +62,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/stores/index/seriesvolume"
 	index_stats "github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/v3/pkg/util"
+	"github.com/grafana/loki/v3/pkg/util/flagext"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 	"github.com/grafana/loki/v3/pkg/util/wal"
 )
@@ -105,6 +110,7 @@ type Config struct {
 
 	// For testing, you can override the address and ID of this ingester.
 	ingesterClientFactory func(cfg client.Config, addr string) (client.HealthAndIngesterClient, error)
+	transferClientFactory func(cfg client.Config, addr string) (transferClient, error)
 
 	QueryStore                  bool          `yaml:"-"`
 	QueryStoreMaxLookBackPeriod time.Duration `yaml:"query_store_max_look_back_period"`
@@ -124,13 +130,22 @@ type Config struct {
 
 	ShutdownMarkerPath string `yaml:"shutdown_marker_path"`
 
+	TransferOnShutdown bool          `yaml:"transfer_on_shutdown" category:"experimental"`
+	TransferTimeout    time.Duration `yaml:"transfer_timeout" category:"experimental"`
+
+	OverloadFlushQueueLength int              `yaml:"overload_flush_queue_length" category:"experimental"`
+	OverloadMemory           flagext.ByteSize `yaml:"overload_memory" category:"experimental"`
+
 	OwnedStreamsCheckInterval time.Duration `yaml:"owned_streams_check_interval" doc:"description=Interval at which the ingester ownedStreamService checks for changes in the ring to recalculate owned streams."`
+
+	KafkaIngestion KafkaIngestionConfig `yaml:"kafka_ingestion,omitempty" category:"experimental" doc:"description=Configures how the ingester consumes the Kafka partition written by the distributors."`
 }
 
 // RegisterFlags registers the flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.LifecyclerConfig.RegisterFlags(f, util_log.Logger)
 	cfg.WAL.RegisterFlags(f)
+	cfg.KafkaIngestion.RegisterFlags(f)
 
 	f.IntVar(&cfg.ConcurrentFlushes, "ingester.concurrent-flushes", 32, "How many flushes can happen concurrently from each stream.")
 	f.DurationVar(&cfg.FlushCheckPeriod, "ingester.flush-check-period", 30*time.Second, "How often should the ingester see if there are any blocks to flush. The first flush check is delayed by a random time up to 0.8x the flush check period. Additionally, there is +/- 1% jitter added to the interval.")
@@ -152,6 +167,10 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.IndexShards, "ingester.index-shards", index.DefaultIndexShards, "Shard factor used in the ingesters for the in process reverse index. This MUST be evenly divisible by ALL schema shard factors or Loki will not start.")
 	f.IntVar(&cfg.MaxDroppedStreams, "ingester.tailer.max-dropped-streams", 10, "Maximum number of dropped streams to keep in memory during tailing.")
 	f.StringVar(&cfg.ShutdownMarkerPath, "ingester.shutdown-marker-path", "", "Path where the shutdown marker file is stored. If not set and common.path_prefix is set then common.path_prefix will be used.")
+	f.BoolVar(&cfg.TransferOnShutdown, "ingester.transfer-on-shutdown", false, "Transfer the in-memory streams to their new owners in the ring, instead of flushing them, when the ingester leaves the ring and would flush its chunks on shutdown. The chunks are flushed if the transfer fails.")
+	f.DurationVar(&cfg.TransferTimeout, "ingester.transfer-timeout", time.Minute, "The maximum time to transfer the in-memory streams on shutdown, including the time for the ring to see the ingester as LEAVING.")
+	f.IntVar(&cfg.OverloadFlushQueueLength, "ingester.overload-flush-queue-length", 0, "Number of chunks waiting to be flushed at which the ingester is fully loaded. When set, the ingester reports its load to the distributors in the push responses: the highest of the ratio of the chunks waiting to be flushed to this number, and of the ratio of the heap in use to `ingester.overload-memory`. The distributors shed the pushes of the lower priority classes first when the ingesters are overloaded. 0 to disable.")
+	f.Var(&cfg.OverloadMemory, "ingester.overload-memory", "Heap in use at which the ingester is fully loaded. When set, the ingester reports its load to the distributors in the push responses, like with `ingester.overload-flush-queue-length`. A unit suffix (KB, MB, GB) may be applied. 0 to disable.")
 	f.DurationVar(&cfg.OwnedStreamsCheckInterval, "ingester.owned-streams-check-interval", 30*time.Second, "Interval at which the ingester ownedStreamService checks for changes in the ring to recalculate owned streams.")
 }
 
@@ -166,6 +185,10 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err = cfg.KafkaIngestion.Validate(cfg.WAL); err != nil {
+		return err
+	}
+
 	if cfg.FlushOpBackoff.MinBackoff > cfg.FlushOpBackoff.MaxBackoff {
 		return errors.New("invalid flush op min backoff: cannot be larger than max backoff")
 	}
@@ -178,6 +201,17 @@ func (cfg *Config) Validate() error {
 	if cfg.IndexShards <= 0 {
 		return fmt.Errorf("invalid ingester index shard factor: %d", cfg.IndexShards)
 	}
+	if cfg.OverloadFlushQueueLength < 0 {
+		return fmt.Errorf("invalid overload flush queue length: %d", cfg.OverloadFlushQueueLength)
+	}
+	if cfg.TransferOnShutdown {
+		if cfg.KafkaIngestion.Enabled {
+			return errors.New("the transfer on shutdown is not supported with the kafka ingestion")
+		}
+		if cfg.TransferTimeout <= 0 {
+			return fmt.Errorf("invalid transfer timeout: %s", cfg.TransferTimeout)
+		}
+	}
 
 	return nil
 }
@@ -202,11 +236,13 @@ type Interface interface {
 	logproto.PusherServer
 	logproto.QuerierServer
 	logproto.StreamDataServer
+	HandoffServer
 
 	CheckReady(ctx context.Context) error
 	FlushHandler(w http.ResponseWriter, _ *http.Request)
 	GetOrCreateInstance(instanceID string) (*instance, error)
 	ShutdownHandler(w http.ResponseWriter, r *http.Request)
+	WALReplayStatusHandler(w http.ResponseWriter, r *http.Request)
 	PrepareShutdown(w http.ResponseWriter, r *http.Request)
 }
 
@@ -237,8 +273,12 @@ type Ingester struct {
 
 	// One queue per flush thread.  Fingerprint is used to
 	// pick a queue.
-	flushQueues     []*util.PriorityQueue
-	flushQueuesDone sync.WaitGroup
+	flushQueues      []*util.PriorityQueue
+	flushQueuesDone  sync.WaitGroup
+	flushQueueLength *queueLengthGauge
+
+	// Only set when the load is reported to the distributors.
+	loadReporter *loadReporter
 
 	// Spread out calls to the chunk store over the flush period
 	flushRateLimiter *rate.Limiter
@@ -259,6 +299,12 @@ type Ingester struct {
 	metrics *ingesterMetrics
 
 	wal WAL
+	// walReplay is the progress of the WAL replay.
+	walReplay *walReplayProgress
+	// Only set by lazy WAL replays.
+	walReadThrough  *walReadThrough
+	walReplayCancel context.CancelFunc
+	walReplayDone   chan struct{}
 
 	chunkFilter      chunk.RequestChunkFilterer
 	extractorWrapper lokilog.SampleExtractorWrapper
@@ -273,13 +319,26 @@ type Ingester struct {
 	// recalculateOwnedStreams periodically checks the ring for changes and recalculates owned streams for each instance.
 	readRing                ring.ReadRing
 	recalculateOwnedStreams *recalculateOwnedStreams
+
+	// Only used when Kafka ingestion is enabled, in which case the ingester owns a single partition.
+	partitionID             int32
+	partitionRing           ring.PartitionRingReader
+	partitionRingLifecycler *ring.PartitionInstanceLifecycler
+	partitionReader         *kafka.PartitionReader
+
+	// streamOwner decides which streams the ingester pre-aggregates for the metric aggregation rules, and counts
+	// the entries not sent to the tailers of.
+	streamOwner *streamOwner
 }
 
 // New makes a new Ingester.
-func New(cfg Config, clientConfig client.Config, store Store, limits Limits, configs *runtime.TenantConfigs, registerer prometheus.Registerer, writeFailuresCfg writefailures.Cfg, metricsNamespace string, logger log.Logger, customStreamsTracker push.UsageTracker, readRing ring.ReadRing) (*Ingester, error) {
+func New(cfg Config, clientConfig client.Config, store Store, limits Limits, configs *runtime.TenantConfigs, registerer prometheus.Registerer, writeFailuresCfg writefailures.Cfg, metricsNamespace string, logger log.Logger, customStreamsTracker push.UsageTracker, readRing ring.ReadRing, partitionRing ring.PartitionRingReader) (*Ingester, error) {
 	if cfg.ingesterClientFactory == nil {
 		cfg.ingesterClientFactory = client.New
 	}
+	if cfg.transferClientFactory == nil {
+		cfg.transferClientFactory = newTransferClient
+	}
 	compressionStats.Set(cfg.ChunkEncoding)
 	targetSizeStats.Set(int64(cfg.TargetChunkSize))
 	walStats.Set("disabled")
@@ -298,6 +357,7 @@ func New(cfg Config, clientConfig client.Config, store Store, limits Limits, con
 		periodicConfigs:       store.GetSchemaConfigs(),
 		loopQuit:              make(chan struct{}),
 		flushQueues:           make([]*util.PriorityQueue, cfg.ConcurrentFlushes),
+		flushQueueLength:      newQueueLengthGauge(metrics.flushQueueLength),
 		flushRateLimiter:      rate.NewLimiter(rate.Inf, 1),
 		tailersQuit:           make(chan struct{}),
 		metrics:               metrics,
@@ -307,8 +367,13 @@ func New(cfg Config, clientConfig client.Config, store Store, limits Limits, con
 		writeLogManager:       writefailures.NewManager(logger, registerer, writeFailuresCfg, configs, "ingester"),
 		customStreamsTracker:  customStreamsTracker,
 		readRing:              readRing,
+		partitionRing:         partitionRing,
 	}
 	i.replayController = newReplayController(metrics, cfg.WAL, &replayFlusher{i})
+	if cfg.OverloadFlushQueueLength > 0 || cfg.OverloadMemory > 0 {
+		i.loadReporter = newLoadReporter(cfg, i.flushQueueLength, metrics.load)
+	}
+	i.walReplay = newWALReplayProgress(cfg.WAL)
 
 	if cfg.WAL.Enabled {
 		if err := os.MkdirAll(cfg.WAL.Dir, os.ModePerm); err != nil {
@@ -357,6 +422,18 @@ func New(cfg Config, clientConfig client.Config, store Store, limits Limits, con
 	}
 
 	i.recalculateOwnedStreams = newRecalculateOwnedStreams(i.getInstances, i.lifecycler.ID, i.readRing, cfg.OwnedStreamsCheckInterval, util_log.Logger)
+	i.streamOwner = &streamOwner{ring: i.readRing, ingesterID: i.lifecycler.ID}
+
+	if cfg.KafkaIngestion.Enabled {
+		if partitionRing == nil {
+			return nil, errors.New("partition ring is required for kafka ingestion")
+		}
+		if err := i.setupKafkaIngestion(registerer, metricsNamespace, logger); err != nil {
+			return nil, err
+		}
+		i.recalculateOwnedStreams.usePartitionRing(partitionRing, i.partitionID)
+		i.streamOwner.partition = strconv.Itoa(int(i.partitionID))
+	}
 
 	return i, nil
 }
@@ -444,91 +521,151 @@ func (i *Ingester) setupAutoForget() {
 	}()
 }
 
-func (i *Ingester) starting(ctx context.Context) error {
-	if i.cfg.WAL.Enabled {
-		start := time.Now()
+// replayWAL recovers the data of the WAL checkpoint and segments. Segments are read from first to last,
+// all of them if these are negative.
+func (i *Ingester) replayWAL(ctx context.Context, recoverer *ingesterRecoverer, first, last int) error {
+	start := time.Now()
 
-		// Ignore retain period during wal replay.
-		oldRetain := i.cfg.RetainPeriod
+	// Ignore retain period during wal replay. Lazy replays run while the ingester serves
+	// queries, so the retain period is kept for them.
+	lazy := i.cfg.WAL.LazyReplay
+	oldRetain := i.cfg.RetainPeriod
+	if !lazy {
 		i.cfg.RetainPeriod = 0
+	}
 
-		// Disable the in process stream limit checks while replaying the WAL.
-		// It is re-enabled in the recover's Close() method.
+	// Disable the in process stream limit checks while replaying the WAL.
+	// It is re-enabled in the recover's Close() method. The pushes received during
+	// lazy replays are still limited, the replayed entries are exempted by the streams.
+	if lazy {
+		i.limiter.DisableForLazyWALReplay()
+	} else {
 		i.limiter.DisableForWALReplay()
+	}
 
-		recoverer := newIngesterRecoverer(i)
-
-		i.metrics.walReplayActive.Set(1)
+	i.metrics.walReplayActive.Set(1)
+	i.walReplay.start(first, last)
 
-		endReplay := func() func() {
-			var once sync.Once
-			return func() {
-				once.Do(func() {
-					level.Info(i.logger).Log("msg", "closing recoverer")
-					recoverer.Close()
+	endReplay := func() func() {
+		var once sync.Once
+		return func() {
+			once.Do(func() {
+				level.Info(i.logger).Log("msg", "closing recoverer")
+				recoverer.Close()
 
-					elapsed := time.Since(start)
+				elapsed := time.Since(start)
 
-					i.metrics.walReplayActive.Set(0)
-					i.metrics.walReplayDuration.Set(elapsed.Seconds())
+				i.metrics.walReplayActive.Set(0)
+				i.metrics.walReplayDuration.Set(elapsed.Seconds())
+				if !lazy {
 					i.cfg.RetainPeriod = oldRetain
-					level.Info(i.logger).Log("msg", "WAL recovery finished", "time", elapsed.String())
-				})
-			}
-		}()
-		defer endReplay()
-
-		level.Info(i.logger).Log("msg", "recovering from checkpoint")
-		checkpointReader, checkpointCloser, err := newCheckpointReader(i.cfg.WAL.Dir, i.logger)
-		if err != nil {
-			return err
-		}
-		defer checkpointCloser.Close()
-
-		checkpointRecoveryErr := RecoverCheckpoint(checkpointReader, recoverer)
-		if checkpointRecoveryErr != nil {
-			i.metrics.walCorruptionsTotal.WithLabelValues(walTypeCheckpoint).Inc()
-			level.Error(i.logger).Log(
-				"msg",
-				`Recovered from checkpoint with errors. Some streams were likely not recovered due to WAL checkpoint file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.`,
-				"elapsed", time.Since(start).String(),
-			)
+				}
+				level.Info(i.logger).Log("msg", "WAL recovery finished", "time", elapsed.String())
+			})
 		}
-		level.Info(i.logger).Log(
-			"msg", "recovered WAL checkpoint recovery finished",
+	}()
+	defer endReplay()
+
+	level.Info(i.logger).Log("msg", "recovering from checkpoint")
+	checkpointReader, checkpointCloser, err := newCheckpointReader(i.cfg.WAL.Dir, i.logger)
+	if err != nil {
+		return err
+	}
+	defer checkpointCloser.Close()
+
+	checkpointRecoveryErr := RecoverCheckpoint(checkpointReader, recoverer)
+	if checkpointRecoveryErr != nil {
+		i.metrics.walCorruptionsTotal.WithLabelValues(walTypeCheckpoint).Inc()
+		level.Error(i.logger).Log(
+			"msg",
+			`Recovered from checkpoint with errors. Some streams were likely not recovered due to WAL checkpoint file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.`,
 			"elapsed", time.Since(start).String(),
-			"errors", checkpointRecoveryErr != nil,
 		)
+	}
+	level.Info(i.logger).Log(
+		"msg", "recovered WAL checkpoint recovery finished",
+		"elapsed", time.Since(start).String(),
+		"errors", checkpointRecoveryErr != nil,
+	)
 
-		level.Info(i.logger).Log("msg", "recovering from WAL")
-		segmentReader, segmentCloser, err := wal.NewWalReader(i.cfg.WAL.Dir, -1)
-		if err != nil {
-			return err
-		}
-		defer segmentCloser.Close()
+	i.walReplay.setPhase(walReplayPhaseSegments, checkpointRecoveryErr != nil)
 
-		segmentRecoveryErr := RecoverWAL(ctx, segmentReader, recoverer)
-		if segmentRecoveryErr != nil {
-			i.metrics.walCorruptionsTotal.WithLabelValues(walTypeSegment).Inc()
-			level.Error(i.logger).Log(
-				"msg",
-				"Recovered from WAL segments with errors. Some streams and/or entries were likely not recovered due to WAL segment file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.",
-				"elapsed", time.Since(start).String(),
-			)
-		}
-		level.Info(i.logger).Log(
-			"msg", "WAL segment recovery finished",
+	level.Info(i.logger).Log("msg", "recovering from WAL")
+	segmentReader, segmentCloser, err := i.newSegmentReader(first, last)
+	if err != nil {
+		return err
+	}
+
+	var segmentRecoveryErr error
+	if segmentReader != nil {
+		defer segmentCloser.Close()
+		segmentRecoveryErr = RecoverWAL(ctx, newSegmentProgressReader(ctx, segmentReader, i.walReplay), recoverer)
+	}
+	if segmentRecoveryErr != nil {
+		i.metrics.walCorruptionsTotal.WithLabelValues(walTypeSegment).Inc()
+		level.Error(i.logger).Log(
+			"msg",
+			"Recovered from WAL segments with errors. Some streams and/or entries were likely not recovered due to WAL segment file corruptions (or WAL file deletions while Loki is running). No administrator action is needed and data loss is only a possibility if more than (replication factor / 2 + 1) ingesters suffer from this.",
 			"elapsed", time.Since(start).String(),
-			"errors", segmentRecoveryErr != nil,
 		)
+	}
+	level.Info(i.logger).Log(
+		"msg", "WAL segment recovery finished",
+		"elapsed", time.Since(start).String(),
+		"errors", segmentRecoveryErr != nil,
+	)
 
-		endReplay()
+	endReplay()
+	if ctx.Err() == nil {
+		i.walReplay.setPhase(walReplayPhaseDone, checkpointRecoveryErr != nil || segmentRecoveryErr != nil)
+	}
+	return nil
+}
+
+// newSegmentReader opens the WAL segments from first to last, all of them if these are negative.
+// The returned reader is nil if there are no segments in the range.
+func (i *Ingester) newSegmentReader(first, last int) (*wlog.Reader, io.Closer, error) {
+	if first < 0 && last < 0 {
+		return wal.NewWalReader(i.cfg.WAL.Dir, -1)
+	}
+	if last < first {
+		return nil, nil, nil
+	}
+	segments, err := wlog.NewSegmentsRangeReader(wlog.SegmentRange{Dir: i.cfg.WAL.Dir, First: first, Last: last})
+	if err != nil {
+		return nil, nil, err
+	}
+	return wlog.NewReader(segments), segments, nil
+}
 
-		i.wal.Start()
+func (i *Ingester) starting(ctx context.Context) error {
+	if i.cfg.WAL.Enabled {
+		if i.cfg.WAL.LazyReplay {
+			if err := i.startLazyWALReplay(); err != nil {
+				return err
+			}
+		} else {
+			if err := i.replayWAL(ctx, newIngesterRecoverer(i), -1, -1); err != nil {
+				return err
+			}
+			i.wal.Start()
+		}
 	}
 
 	i.InitFlushQueues()
 
+	if i.cfg.KafkaIngestion.Enabled {
+		// Replaying the partition from the last committed offset replaces the WAL replay.
+		// The reader only reports running once it has caught up with the partition.
+		level.Info(i.logger).Log("msg", "replaying kafka partition", "partition", i.partitionID)
+		if err := services.StartAndAwaitRunning(ctx, i.partitionReader); err != nil {
+			return fmt.Errorf("failed to start kafka partition reader: %w", err)
+		}
+		if err := services.StartAndAwaitRunning(ctx, i.partitionRingLifecycler); err != nil {
+			return fmt.Errorf("failed to start partition ring lifecycler: %w", err)
+		}
+	}
+
 	// pass new context to lifecycler, so that it doesn't stop automatically when Ingester's service context is done
 	err := i.lifecycler.StartAsync(context.Background())
 	if err != nil {
@@ -592,14 +729,21 @@ func (i *Ingester) running(ctx context.Context) error {
 //
 // At this point, loop no longer runs, but flushers are still running.
 func (i *Ingester) stopping(_ error) error {
-	i.stopIncomingRequests()
 	var errs util.MultiError
+	if i.partitionReader != nil {
+		errs.Add(services.StopAndAwaitTerminated(context.Background(), i.partitionReader))
+	}
+	i.stopIncomingRequests()
+	i.stopLazyWALReplay()
 	errs.Add(i.wal.Stop())
 
 	if i.flushOnShutdownSwitch.Get() {
 		i.lifecycler.SetFlushOnShutdown(true)
 	}
 	errs.Add(services.StopAndAwaitTerminated(context.Background(), i.lifecycler))
+	if i.partitionRingLifecycler != nil {
+		errs.Add(services.StopAndAwaitTerminated(context.Background(), i.partitionRingLifecycler))
+	}
 
 	for _, flushQueue := range i.flushQueues {
 		flushQueue.Close()
@@ -675,7 +819,9 @@ func (i *Ingester) loop() {
 	for {
 		select {
 		case <-flushTicker.C:
-			i.sweepUsers(false, true)
+			// Streams being replayed from the WAL must not be removed.
+			i.sweepUsers(false, !i.walReplay.active())
+			i.writeMetricAggregations()
 
 		case <-i.loopQuit:
 			return
@@ -884,7 +1030,14 @@ func (i *Ingester) Push(ctx context.Context, req *logproto.PushRequest) (*logpro
 	if err != nil {
 		return &logproto.PushResponse{}, err
 	}
-	return &logproto.PushResponse{}, instance.Push(ctx, req)
+	if !client.ExtractRejectedStreams(ctx) {
+		return &logproto.PushResponse{Load: i.loadReporter.Load()}, instance.Push(ctx, req)
+	}
+
+	// The distributor asked for the rejected streams: report them in the response, and only fail
+	// the request for the errors which are not specific to a stream.
+	rejected, _, err := instance.pushStreams(ctx, req)
+	return &logproto.PushResponse{RejectedStreams: rejected, Load: i.loadReporter.Load()}, err
 }
 
 // GetStreamRates returns a response containing all streams and their current rate
@@ -923,6 +1076,8 @@ func (i *Ingester) GetOrCreateInstance(instanceID string) (*instance, error) { /
 		if err != nil {
 			return nil, err
 		}
+		inst.walReadThrough = i.walReadThrough
+		inst.metricAggregator.owner = i.streamOwner
 		i.instances[instanceID] = inst
 		activeTenantsStats.Set(int64(len(i.instances)))
 	}
@@ -1420,10 +1575,11 @@ func (i *Ingester) tail(req *logproto.TailRequest, queryServer logproto.Querier_
 		return fmt.Errorf("unsupported query expression: want (LogSelectorExpr), got (%T)", req.Plan.AST)
 	}
 
-	tailer, err := newTailer(instanceID, expr, queryServer, i.cfg.MaxDroppedStreams)
+	tailer, err := newTailer(instanceID, expr, queryServer, i.cfg.MaxDroppedStreams, req.Sample, i.limiter.limits.MaxTailEntriesPerSecond(instanceID))
 	if err != nil {
 		return err
 	}
+	tailer.owner = i.streamOwner
 
 	if err := instance.addNewTailer(queryServer.Context(), tailer); err != nil {
 		return err
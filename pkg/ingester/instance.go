@@ -128,6 +128,13 @@ type instance struct {
 	schemaconfig *config.SchemaConfig
 
 	customStreamsTracker push.UsageTracker
+
+	// walReadThrough is set while the WAL is replayed lazily.
+	walReadThrough *walReadThrough
+
+	metricAggregator *metricAggregator
+
+	structuredMetadataIndexer *structuredMetadataIndexer
 }
 
 func newInstance(
@@ -183,6 +190,10 @@ func newInstance(
 		schemaconfig:  &c,
 
 		customStreamsTracker: customStreamsTracker,
+
+		metricAggregator: newMetricAggregator(instanceID, limiter.limits, metrics),
+
+		structuredMetadataIndexer: newStructuredMetadataIndexer(instanceID, limiter.limits, uint32(cfg.IndexShards)),
 	}
 	i.mapper = NewFPMapper(i.getLabelsFromFingerprint)
 
@@ -224,34 +235,53 @@ func (i *instance) consumeChunk(ctx context.Context, ls labels.Labels, chunk *lo
 // happened to *the last stream in the request*. Ex: if three streams are part of the PushRequest
 // and all three failed, the returned error only describes what happened to the last processed stream.
 func (i *instance) Push(ctx context.Context, req *logproto.PushRequest) error {
+	_, rejectionErr, err := i.pushStreams(ctx, req)
+	if err != nil {
+		return err
+	}
+	return rejectionErr
+}
+
+// pushStreams appends the entries of the streams of a push request. It returns the streams whose
+// entries were rejected, keyed by their index in the request, along with the error of the last of
+// them, and the error of the last stream which could not be pushed for another reason.
+func (i *instance) pushStreams(ctx context.Context, req *logproto.PushRequest) ([]logproto.RejectedStream, error, error) {
 	record := recordPool.GetRecord()
 	record.UserID = i.instanceID
 	defer recordPool.PutRecord(record)
 	rateLimitWholeStream := i.limiter.limits.ShardStreams(i.instanceID).Enabled
 
-	var appendErr error
-	for _, reqStream := range req.Streams {
+	var (
+		rejected     []logproto.RejectedStream
+		rejectionErr error
+		appendErr    error
+	)
+	onErr := func(index int, reqStream logproto.Stream, err error) {
+		var rejection *errRejectedEntries
+		if !errors.As(err, &rejection) {
+			appendErr = err
+			return
+		}
+		rejectionErr = rejection.error
+		rejected = append(rejected, logproto.RejectedStream{
+			Index:           int32(index),
+			Labels:          reqStream.Labels,
+			Reason:          rejection.reason,
+			Message:         rejection.Error(),
+			RejectedEntries: int32(rejection.entries),
+		})
+	}
+	for idx, reqStream := range req.Streams {
 
-		s, _, err := i.streams.LoadOrStoreNew(reqStream.Labels,
-			func() (*stream, error) {
-				s, err := i.createStream(ctx, reqStream, record)
-				// Lock before adding to maps
-				if err == nil {
-					s.chunkMtx.Lock()
-				}
-				return s, err
-			},
-			func(s *stream) error {
-				s.chunkMtx.Lock()
-				return nil
-			},
-		)
+		s, err := i.loadOrCreateLockedStream(ctx, reqStream, record)
 		if err != nil {
-			appendErr = err
+			onErr(idx, reqStream, err)
 			continue
 		}
 
-		_, appendErr = s.Push(ctx, reqStream.Entries, record, 0, false, rateLimitWholeStream, i.customStreamsTracker)
+		if _, err := s.Push(ctx, reqStream.Entries, record, 0, false, rateLimitWholeStream, i.customStreamsTracker); err != nil {
+			onErr(idx, reqStream, err)
+		}
 		s.chunkMtx.Unlock()
 	}
 
@@ -266,12 +296,72 @@ func (i *instance) Push(ctx context.Context, req *logproto.PushRequest) error {
 					)
 				})
 			} else {
-				return err
+				return nil, nil, err
 			}
 		}
 	}
 
-	return appendErr
+	return rejected, rejectionErr, appendErr
+}
+
+// PushPartitionStream pushes a stream read from the Kafka partition owned by the ingester.
+// The offset of the record carrying the stream is kept on the chunks it is written to,
+// so that the record is only committed once these chunks have been flushed.
+func (i *instance) PushPartitionStream(ctx context.Context, reqStream logproto.Stream, offset int64) error {
+	record := recordPool.GetRecord()
+	record.UserID = i.instanceID
+	defer recordPool.PutRecord(record)
+	rateLimitWholeStream := i.limiter.limits.ShardStreams(i.instanceID).Enabled
+
+	s, err := i.loadOrCreateLockedStream(ctx, reqStream, record)
+	if err != nil {
+		return unwrapRejectedEntries(err)
+	}
+	defer s.chunkMtx.Unlock()
+
+	prevNumChunks := len(s.chunks)
+	_, err = s.Push(ctx, reqStream.Entries, record, 0, false, rateLimitWholeStream, i.customStreamsTracker)
+	s.setPartitionOffset(prevNumChunks, offset)
+	return unwrapRejectedEntries(err)
+}
+
+// oldestUnflushedPartitionOffset returns the lowest partition offset of the chunks not flushed yet.
+func (i *instance) oldestUnflushedPartitionOffset() (int64, bool) {
+	var (
+		oldest int64
+		found  bool
+	)
+	_ = i.streams.ForEach(func(s *stream) (bool, error) {
+		s.chunkMtx.RLock()
+		defer s.chunkMtx.RUnlock()
+		for _, c := range s.chunks {
+			if c.hasPartitionOffset && c.flushed.IsZero() && (!found || c.partitionOffset < oldest) {
+				oldest, found = c.partitionOffset, true
+			}
+		}
+		return true, nil
+	})
+	return oldest, found
+}
+
+// loadOrCreateLockedStream returns the stream for the given labels, creating it if needed.
+// The chunkMtx of the returned stream is locked.
+func (i *instance) loadOrCreateLockedStream(ctx context.Context, reqStream logproto.Stream, record *wal.Record) (*stream, error) {
+	s, _, err := i.streams.LoadOrStoreNew(reqStream.Labels,
+		func() (*stream, error) {
+			s, err := i.createStream(ctx, reqStream, record)
+			// Lock before adding to maps
+			if err == nil {
+				s.chunkMtx.Lock()
+			}
+			return s, err
+		},
+		func(s *stream) error {
+			s.chunkMtx.Lock()
+			return nil
+		},
+	)
+	return s, err
 }
 
 func (i *instance) createStream(ctx context.Context, pushReqStream logproto.Stream, record *wal.Record) (*stream, error) {
@@ -289,7 +379,11 @@ func (i *instance) createStream(ctx context.Context, pushReqStream logproto.Stre
 				"stream", pushReqStream.Labels,
 			)
 		}
-		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		return nil, &errRejectedEntries{
+			error:   httpgrpc.Errorf(http.StatusBadRequest, err.Error()),
+			reason:  validation.InvalidLabels,
+			entries: len(pushReqStream.Entries),
+		}
 	}
 
 	if record != nil {
@@ -309,7 +403,13 @@ func (i *instance) createStream(ctx context.Context, pushReqStream logproto.Stre
 		return nil, fmt.Errorf("failed to create stream: %w", err)
 	}
 
-	s := newStream(chunkfmt, headfmt, i.cfg, i.limiter, i.instanceID, fp, sortedLabels, i.limiter.UnorderedWrites(i.instanceID), i.streamRateCalculator, i.metrics, i.writeFailures, i.configs)
+	unorderedWrites := i.limiter.UnorderedWrites(i.instanceID)
+	if record == nil {
+		unorderedWrites = i.limiter.ReplayUnorderedWrites(i.instanceID)
+	}
+	s := newStream(chunkfmt, headfmt, i.cfg, i.limiter, i.instanceID, fp, sortedLabels, unorderedWrites, i.streamRateCalculator, i.metrics, i.writeFailures, i.configs)
+	s.metricAggregator = i.metricAggregator
+	s.structuredMetadataIndexer = i.structuredMetadataIndexer
 
 	// record will be nil when replaying the wal (we don't want to rewrite wal entries as we replay them).
 	if record != nil {
@@ -346,7 +446,11 @@ func (i *instance) onStreamCreationError(ctx context.Context, pushReqStream logp
 	if i.customStreamsTracker != nil {
 		i.customStreamsTracker.DiscardedBytesAdd(ctx, i.instanceID, validation.StreamLimit, labels, float64(bytes))
 	}
-	return nil, httpgrpc.Errorf(http.StatusTooManyRequests, validation.StreamLimitErrorMsg, labels, i.instanceID)
+	return nil, &errRejectedEntries{
+		error:   httpgrpc.Errorf(http.StatusTooManyRequests, validation.StreamLimitErrorMsg, labels, i.instanceID),
+		reason:  validation.StreamLimit,
+		entries: len(pushReqStream.Entries),
+	}
 }
 
 func (i *instance) onStreamCreated(s *stream) {
@@ -416,6 +520,7 @@ func (i *instance) getOrCreateStream(ctx context.Context, pushReqStream logproto
 func (i *instance) removeStream(s *stream) {
 	if i.streams.Delete(s) {
 		i.index.Delete(s.labels, s.fp)
+		i.structuredMetadataIndexer.index.Delete(s.fp)
 		i.streamsRemovedTotal.Inc()
 		memoryStreams.WithLabelValues(i.instanceID).Dec()
 		memoryStreamsLabelsBytes.Sub(float64(len(s.labels.String())))
@@ -456,18 +561,20 @@ func (i *instance) query(ctx context.Context, req logql.SelectLogParams) (iter.E
 		return nil, err
 	}
 
-	pipeline, err = deletion.SetupPipeline(req, pipeline)
+	pipeline, err = i.setupPipeline(ctx, req, pipeline)
 	if err != nil {
 		return nil, err
 	}
 
-	if i.pipelineWrapper != nil && httpreq.ExtractHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader) != "true" {
-		userID, err := tenant.TenantID(ctx)
+	// The lines surrounding the matching lines of an around stage are looked up
+	// in the unprocessed lines of the streams.
+	around, hasAround := syntax.AroundStage(expr)
+	streamsPipeline := pipeline
+	if hasAround {
+		streamsPipeline, err = i.setupPipeline(ctx, req, log.NewNoopPipeline())
 		if err != nil {
 			return nil, err
 		}
-
-		pipeline = i.pipelineWrapper.Wrap(ctx, pipeline, req.Plan.String(), userID)
 	}
 
 	stats := stats.FromContext(ctx)
@@ -484,7 +591,7 @@ func (i *instance) query(ctx context.Context, req logql.SelectLogParams) (iter.E
 		expr.Matchers(),
 		shard,
 		func(stream *stream) error {
-			iter, err := stream.Iterator(ctx, stats, req.Start, req.End, req.Direction, pipeline.ForStream(stream.labels))
+			iter, err := stream.Iterator(ctx, stats, req.Start, req.End, req.Direction, streamsPipeline.ForStream(stream.labels))
 			if err != nil {
 				return err
 			}
@@ -496,7 +603,39 @@ func (i *instance) query(ctx context.Context, req logql.SelectLogParams) (iter.E
 		return nil, err
 	}
 
-	return iter.NewSortEntryIterator(iters, req.Direction), nil
+	it := iter.NewSortEntryIterator(iters, req.Direction)
+	if i.walReadThrough.active() {
+		// Streams not replayed yet are read from the WAL, the entries already replayed are deduplicated.
+		walIt, err := i.walReadThrough.EntryIterator(ctx, i.instanceID, req, expr.Matchers(), shard, streamsPipeline)
+		if err != nil {
+			it.Close()
+			return nil, err
+		}
+		it = iter.NewMergeEntryIterator(ctx, []iter.EntryIterator{it, walIt}, req.Direction)
+	}
+	if hasAround {
+		it = iter.NewContextIterator(it, pipeline, req.Direction, around.Before, around.After)
+	}
+	return logql.NewResultStagesIterator(it, expr, true), nil
+}
+
+// setupPipeline applies the deletes of the request and the pipeline wrapper to
+// the pipeline of a log query.
+func (i *instance) setupPipeline(ctx context.Context, req logql.SelectLogParams, pipeline log.Pipeline) (log.Pipeline, error) {
+	pipeline, err := deletion.SetupPipeline(req, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.pipelineWrapper != nil && httpreq.ExtractHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader) != "true" {
+		userID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = i.pipelineWrapper.Wrap(ctx, pipeline, req.Plan.String(), userID)
+	}
+	return pipeline, nil
 }
 
 func (i *instance) QuerySample(ctx context.Context, req logql.SelectSampleParams) (iter.SampleIterator, error) {
@@ -559,7 +698,16 @@ func (i *instance) querySample(ctx context.Context, req logql.SelectSampleParams
 		return nil, err
 	}
 
-	return iter.NewSortSampleIterator(iters), nil
+	it := iter.NewSortSampleIterator(iters)
+	if i.walReadThrough.active() {
+		walIt, err := i.walReadThrough.SampleIterator(ctx, i.instanceID, req, selector.Matchers(), shard, extractor)
+		if err != nil {
+			it.Close()
+			return nil, err
+		}
+		it = iter.NewMergeSampleIterator(ctx, []iter.SampleIterator{it, walIt})
+	}
+	return it, nil
 }
 
 // Label returns the label names or values depending on the given request
@@ -1199,6 +1347,28 @@ func (i *instance) updateOwnedStreams(ingesterRing ring.ReadRing, ingesterID str
 	return err
 }
 
+// updateOwnedStreamsByPartition recalculates the owned streams when streams are assigned to
+// ingesters by the partition ring: a stream is owned if it hashes to the partition of the ingester.
+func (i *instance) updateOwnedStreamsByPartition(partitionRing *ring.PartitionRing, partitionID int32) error {
+	start := time.Now()
+	defer func() {
+		i.metrics.streamsOwnershipCheck.Observe(float64(time.Since(start).Milliseconds()))
+	}()
+	var err error
+	i.streams.WithLock(func() {
+		i.ownedStreamsSvc.resetStreamCounts()
+		err = i.streams.ForEach(func(s *stream) (bool, error) {
+			streamPartition, err := partitionRing.ActivePartitionForKey(lokiring.TokenFor(i.instanceID, s.labelsString))
+			if err != nil {
+				return false, fmt.Errorf("error getting partition for stream %s: %v", s.labelsString, err)
+			}
+			i.ownedStreamsSvc.trackStreamOwnership(s.fp, streamPartition == partitionID)
+			return true, nil
+		})
+	})
+	return err
+}
+
 func (i *instance) isOwnedStream(replicationSet ring.ReplicationSet, ingesterID string) bool {
 	for _, instanceDesc := range replicationSet.Instances {
 		if instanceDesc.Id == ingesterID {
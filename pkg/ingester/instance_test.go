@@ -556,7 +556,7 @@ func Benchmark_instance_addNewTailer(b *testing.B) {
 	inst, _ := newInstance(&Config{}, defaultPeriodConfigs, "test", limiter, loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, nil, nil, NewStreamRateCalculator(), nil, nil)
 	expr, err := syntax.ParseLogSelector(`{namespace="foo",pod="bar",instance=~"10.*"}`, true)
 	require.NoError(b, err)
-	t, err := newTailer("foo", expr, nil, 10)
+	t, err := newTailer("foo", expr, nil, 10, 0, 0)
 	require.NoError(b, err)
 	for i := 0; i < 10000; i++ {
 		require.NoError(b, inst.Push(ctx, &logproto.PushRequest{
@@ -995,6 +995,64 @@ func Test_QueryWithDelete(t *testing.T) {
 	require.Equal(t, logs, []string{`msg="dispatcher_7"`})
 }
 
+func Test_QueryWithAround(t *testing.T) {
+	instance := defaultInstance(t)
+
+	it, err := instance.Query(context.TODO(),
+		logql.SelectLogParams{
+			QueryRequest: &logproto.QueryRequest{
+				Selector:  `{job="3"} |= "_4" | around before=2 after=1`,
+				Limit:     uint32(10),
+				Start:     time.Unix(0, 0),
+				End:       time.Unix(0, 100000000),
+				Direction: logproto.FORWARD,
+				Plan: &plan.QueryPlan{
+					AST: syntax.MustParseExpr(`{job="3"} |= "_4" | around before=2 after=1`),
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	defer it.Close()
+
+	var logs []string
+	for it.Next() {
+		logs = append(logs, it.At().Line)
+	}
+	require.NoError(t, it.Err())
+
+	require.Equal(t, []string{`msg="worker_0"`, `msg="worker_2"`, `msg="worker_4"`, `msg="worker_6"`}, logs)
+}
+
+func Test_QueryWithLimitBy(t *testing.T) {
+	instance := defaultInstance(t)
+
+	it, err := instance.Query(context.TODO(),
+		logql.SelectLogParams{
+			QueryRequest: &logproto.QueryRequest{
+				Selector:  `{job="3"} | limit 2 by (log_stream)`,
+				Limit:     uint32(10),
+				Start:     time.Unix(0, 0),
+				End:       time.Unix(0, 100000000),
+				Direction: logproto.FORWARD,
+				Plan: &plan.QueryPlan{
+					AST: syntax.MustParseExpr(`{job="3"} | limit 2 by (log_stream)`),
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	defer it.Close()
+
+	var logs []string
+	for it.Next() {
+		logs = append(logs, it.At().Line)
+	}
+	require.NoError(t, it.Err())
+
+	require.Equal(t, []string{`msg="worker_0"`, `msg="dispatcher_1"`, `msg="worker_2"`, `msg="dispatcher_3"`}, logs)
+}
+
 func Test_QuerySampleWithDelete(t *testing.T) {
 	instance := defaultInstance(t)
 
@@ -1560,3 +1618,34 @@ func (m *mockUsageTracker) DiscardedBytesAdd(_ context.Context, _ string, _ stri
 // ReceivedBytesAdd implements push.UsageTracker.
 func (*mockUsageTracker) ReceivedBytesAdd(_ context.Context, _ string, _ time.Duration, _ labels.Labels, _ float64) {
 }
+
+func TestInstance_PushPartitionStream(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	inst, err := newInstance(defaultConfig(), defaultPeriodConfigs, "test", limiter, loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, nil, nil, NewStreamRateCalculator(), nil, nil)
+	require.NoError(t, err)
+
+	_, ok := inst.oldestUnflushedPartitionOffset()
+	require.False(t, ok)
+
+	tt := time.Now().Add(-5 * time.Minute)
+	ctx := user.InjectOrgID(context.Background(), "test")
+	require.NoError(t, inst.PushPartitionStream(ctx, logproto.Stream{Labels: `{app="foo"}`, Entries: entries(5, tt)}, 10))
+	require.NoError(t, inst.PushPartitionStream(ctx, logproto.Stream{Labels: `{app="bar"}`, Entries: entries(5, tt)}, 11))
+	require.NoError(t, inst.PushPartitionStream(ctx, logproto.Stream{Labels: `{app="foo"}`, Entries: entries(5, tt.Add(time.Minute))}, 12))
+
+	// The offset of the first record written to a chunk is kept until the chunk is flushed.
+	offset, ok := inst.oldestUnflushedPartitionOffset()
+	require.True(t, ok)
+	require.Equal(t, int64(10), offset)
+
+	s, ok := inst.streams.Load(`{app="foo"}`)
+	require.True(t, ok)
+	s.chunks[0].flushed = time.Now()
+
+	offset, ok = inst.oldestUnflushedPartitionOffset()
+	require.True(t, ok)
+	require.Equal(t, int64(11), offset)
+}
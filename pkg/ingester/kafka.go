@@ -0,0 +1,123 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/kv"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/loki/v3/pkg/kafka"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+const (
+	// PartitionRingKey is the key under which the partition ring is stored in the KV store.
+	PartitionRingKey = "ingester-partitions"
+	// PartitionRingName is the name of the partition ring.
+	PartitionRingName = "ingester-partitions"
+)
+
+// KafkaIngestionConfig configures how the ingester consumes its partition of the Kafka topic
+// written by the distributors.
+type KafkaIngestionConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	PartitionRingMinOwnersCount      int           `yaml:"partition_ring_min_owners_count"`
+	PartitionRingMinOwnersDuration   time.Duration `yaml:"partition_ring_min_owners_duration"`
+	PartitionRingDeleteInactiveAfter time.Duration `yaml:"partition_ring_delete_inactive_partition_after"`
+
+	KafkaConfig kafka.Config `yaml:"-"`
+
+	// For testing.
+	consumer kafka.Consumer `yaml:"-"`
+}
+
+func (cfg *KafkaIngestionConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ingester.kafka-ingestion.enabled", false, "Whether the ingester consumes its partition of the Kafka topic written by the distributors. Committed Kafka offsets replace the WAL, which must be disabled.")
+	f.IntVar(&cfg.PartitionRingMinOwnersCount, "ingester.kafka-ingestion.partition-ring-min-owners-count", 1, "Minimum number of owners to wait before a PENDING partition gets switched to ACTIVE.")
+	f.DurationVar(&cfg.PartitionRingMinOwnersDuration, "ingester.kafka-ingestion.partition-ring-min-owners-duration", 10*time.Second, "How long the minimum number of owners are enforced before a PENDING partition gets switched to ACTIVE.")
+	f.DurationVar(&cfg.PartitionRingDeleteInactiveAfter, "ingester.kafka-ingestion.partition-ring-delete-inactive-partition-after", 13*time.Hour, "How long to wait before an INACTIVE partition is eligible for deletion. The partition is deleted only if it has been in INACTIVE state for at least the configured duration and it has no owners registered. A value of 0 disables partitions deletion.")
+}
+
+func (cfg *KafkaIngestionConfig) Validate(walCfg WALConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if walCfg.Enabled {
+		return errors.New("the WAL must be disabled when Kafka ingestion is enabled, committed Kafka offsets are used to recover data instead")
+	}
+	if cfg.PartitionRingMinOwnersCount < 0 {
+		return fmt.Errorf("invalid partition ring min owners count: %d", cfg.PartitionRingMinOwnersCount)
+	}
+	return nil
+}
+
+// setupKafkaIngestion creates the partition lifecycler, which registers the ingester as owner of its
+// partition, and the reader consuming that partition.
+func (i *Ingester) setupKafkaIngestion(registerer prometheus.Registerer, metricsNamespace string, logger log.Logger) error {
+	cfg := i.cfg.KafkaIngestion
+
+	partitionID, err := kafka.IngesterPartitionID(i.cfg.LifecyclerConfig.ID)
+	if err != nil {
+		return fmt.Errorf("calculating ingester partition ID: %w", err)
+	}
+	i.partitionID = partitionID
+
+	kvClient, err := kv.NewClient(i.cfg.LifecyclerConfig.RingConfig.KVStore, ring.GetPartitionRingCodec(), kv.RegistererWithKVName(registerer, PartitionRingName+"-lifecycler"), logger)
+	if err != nil {
+		return fmt.Errorf("creating KV store for ingester partition ring: %w", err)
+	}
+	i.partitionRingLifecycler = ring.NewPartitionInstanceLifecycler(ring.PartitionInstanceLifecyclerConfig{
+		PartitionID:                          partitionID,
+		InstanceID:                           i.cfg.LifecyclerConfig.ID,
+		WaitOwnersCountOnPending:             cfg.PartitionRingMinOwnersCount,
+		WaitOwnersDurationOnPending:          cfg.PartitionRingMinOwnersDuration,
+		DeleteInactivePartitionAfterDuration: cfg.PartitionRingDeleteInactiveAfter,
+	}, PartitionRingName, PartitionRingKey, kvClient, logger, prometheus.WrapRegistererWithPrefix(metricsNamespace+"_", registerer))
+
+	consumer := cfg.consumer
+	if consumer == nil {
+		consumer, err = kafka.NewConsumer(cfg.KafkaConfig, cfg.KafkaConfig.GetConsumerGroup(i.cfg.LifecyclerConfig.ID, partitionID))
+		if err != nil {
+			return err
+		}
+	}
+	i.partitionReader = kafka.NewPartitionReader(cfg.KafkaConfig, partitionID, consumer, &partitionRecordHandler{i}, logger, registerer)
+
+	i.lifecyclerWatcher.WatchService(i.partitionRingLifecycler)
+	i.lifecyclerWatcher.WatchService(i.partitionReader)
+	return nil
+}
+
+// partitionRecordHandler pushes the streams read from the ingester partition to the tenant instances.
+type partitionRecordHandler struct {
+	ingester *Ingester
+}
+
+func (h *partitionRecordHandler) Handle(ctx context.Context, tenantID string, stream logproto.Stream, offset int64) error {
+	instance, err := h.ingester.GetOrCreateInstance(tenantID)
+	if err != nil {
+		return err
+	}
+	return instance.PushPartitionStream(user.InjectOrgID(ctx, tenantID), stream, offset)
+}
+
+func (h *partitionRecordHandler) OldestUnflushedOffset() (int64, bool) {
+	var (
+		oldest int64
+		found  bool
+	)
+	for _, instance := range h.ingester.getInstances() {
+		if offset, ok := instance.oldestUnflushedPartitionOffset(); ok && (!found || offset < oldest) {
+			oldest, found = offset, true
+		}
+	}
+	return oldest, found
+}
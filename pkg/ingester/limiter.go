@@ -31,6 +31,9 @@ type Limits interface {
 	MaxGlobalStreamsPerUser(userID string) int
 	PerStreamRateLimit(userID string) validation.RateLimit
 	ShardStreams(userID string) shardstreams.Config
+	MetricAggregationRules(userID string) []validation.MetricAggregationRule
+	IndexedStructuredMetadataKeys(userID string) []string
+	MaxTailEntriesPerSecond(userID string) int
 }
 
 // Limiter implements primitives to get the maximum number of streams
@@ -43,6 +46,8 @@ type Limiter struct {
 
 	mtx      sync.RWMutex
 	disabled bool
+	// replaying is set while the WAL is replayed lazily.
+	replaying bool
 }
 
 func (l *Limiter) DisableForWALReplay() {
@@ -52,10 +57,19 @@ func (l *Limiter) DisableForWALReplay() {
 	l.metrics.limiterEnabled.Set(0)
 }
 
+// DisableForLazyWALReplay keeps the limits enforced for the pushes received while the WAL is
+// replayed lazily, only the streams created by the replay accept out of order writes.
+func (l *Limiter) DisableForLazyWALReplay() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.replaying = true
+}
+
 func (l *Limiter) Enable() {
 	l.mtx.Lock()
 	defer l.mtx.Unlock()
 	l.disabled = false
+	l.replaying = false
 	l.metrics.limiterEnabled.Set(1)
 }
 
@@ -79,6 +93,17 @@ func (l *Limiter) UnorderedWrites(userID string) bool {
 	return l.limits.UnorderedWrites(userID)
 }
 
+// ReplayUnorderedWrites returns whether the streams created by a WAL replay accept out of order writes.
+func (l *Limiter) ReplayUnorderedWrites(userID string) bool {
+	l.mtx.RLock()
+	replaying := l.replaying
+	l.mtx.RUnlock()
+	if replaying {
+		return true
+	}
+	return l.UnorderedWrites(userID)
+}
+
 func (l *Limiter) GetStreamCountLimit(tenantID string) (calculatedLimit, localLimit, globalLimit, adjustedGlobalLimit int) {
 	// Start by setting the local limit either from override or default
 	localLimit = l.limits.MaxLocalStreamsPerUser(tenantID)
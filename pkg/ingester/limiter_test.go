@@ -206,6 +206,27 @@ func TestLimiter_minNonZero(t *testing.T) {
 	}
 }
 
+func TestLimiter_DisableForLazyWALReplay(t *testing.T) {
+	defaultLimits := defaultLimitsTestConfig()
+	defaultLimits.UnorderedWrites = false
+	limits, err := validation.NewOverrides(defaultLimits, nil)
+	require.NoError(t, err)
+
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+	limiter.DisableForLazyWALReplay()
+	// The pushes are still limited, only the streams created by the replay accept out of order writes.
+	require.Equal(t, limits.PerStreamRateLimit("test"), limiter.RateLimit("test"))
+	require.False(t, limiter.UnorderedWrites("test"))
+	require.True(t, limiter.ReplayUnorderedWrites("test"))
+
+	limiter.Enable()
+	require.False(t, limiter.ReplayUnorderedWrites("test"))
+
+	limiter.DisableForWALReplay()
+	require.Equal(t, validation.Unlimited, limiter.RateLimit("test"))
+	require.True(t, limiter.UnorderedWrites("test"))
+}
+
 type ringCountMock struct {
 	count int
 }
@@ -0,0 +1,88 @@
+package ingester
+
+import (
+	"runtime/metrics"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+)
+
+// loadUpdatePeriod is how often the load reported to the distributors is computed.
+const loadUpdatePeriod = time.Second
+
+// queueLengthGauge reports the length of the flush queues, and keeps it to compute the load of the ingester.
+type queueLengthGauge struct {
+	prometheus.Gauge
+	length atomic.Int64
+}
+
+func newQueueLengthGauge(gauge prometheus.Gauge) *queueLengthGauge {
+	return &queueLengthGauge{Gauge: gauge}
+}
+
+func (g *queueLengthGauge) Inc() {
+	g.length.Inc()
+	g.Gauge.Inc()
+}
+
+func (g *queueLengthGauge) Dec() {
+	g.length.Dec()
+	g.Gauge.Dec()
+}
+
+// loadReporter computes the load of the ingester reported to the distributors in the push responses.
+// The load is the highest of the ratio of the chunks waiting to be flushed to overload_flush_queue_length,
+// and of the ratio of the heap in use to overload_memory, each of them being ignored when not set: 1 when
+// the ingester is fully loaded.
+type loadReporter struct {
+	maxQueueLength int64
+	maxMemory      uint64
+	queueLength    *queueLengthGauge
+	heapInUse      func() uint64
+	gauge          prometheus.Gauge
+
+	updatedAt atomic.Int64
+	load      atomic.Float64
+}
+
+func newLoadReporter(cfg Config, queueLength *queueLengthGauge, gauge prometheus.Gauge) *loadReporter {
+	return &loadReporter{
+		maxQueueLength: int64(cfg.OverloadFlushQueueLength),
+		maxMemory:      uint64(cfg.OverloadMemory),
+		queueLength:    queueLength,
+		heapInUse:      heapInUse,
+		gauge:          gauge,
+	}
+}
+
+// Load returns the current load of the ingester, or 0 if the load is not reported.
+func (r *loadReporter) Load() float64 {
+	if r == nil {
+		return 0
+	}
+	// The load is only recomputed by one of the pushes once per update period.
+	now := time.Now().UnixNano()
+	last := r.updatedAt.Load()
+	if now-last >= int64(loadUpdatePeriod) && r.updatedAt.CompareAndSwap(last, now) {
+		load := 0.0
+		if r.maxQueueLength > 0 {
+			load = float64(r.queueLength.length.Load()) / float64(r.maxQueueLength)
+		}
+		if r.maxMemory > 0 {
+			load = max(load, float64(r.heapInUse())/float64(r.maxMemory))
+		}
+		r.load.Store(load)
+		r.gauge.Set(load)
+	}
+	return r.load.Load()
+}
+
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,45 @@
+package ingester
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadReporter(t *testing.T) {
+	cfg := Config{OverloadFlushQueueLength: 10, OverloadMemory: 1000}
+
+	queueLength := newQueueLengthGauge(prometheus.NewGauge(prometheus.GaugeOpts{Name: "queue_length"}))
+	r := newLoadReporter(cfg, queueLength, prometheus.NewGauge(prometheus.GaugeOpts{Name: "load"}))
+	heap := uint64(100)
+	r.heapInUse = func() uint64 { return heap }
+
+	for i := 0; i < 5; i++ {
+		queueLength.Inc()
+	}
+	require.Equal(t, 0.5, r.Load())
+
+	// The load is only recomputed once per update period.
+	heap = 900
+	require.Equal(t, 0.5, r.Load())
+
+	r.updatedAt.Store(0)
+	require.Equal(t, 0.9, r.Load())
+
+	// The memory is not part of the load when its threshold is not set.
+	cfg.OverloadMemory = 0
+	r = newLoadReporter(cfg, queueLength, prometheus.NewGauge(prometheus.GaugeOpts{Name: "load"}))
+	r.heapInUse = func() uint64 { return heap }
+	require.Equal(t, 0.5, r.Load())
+
+	// Nor is the flush queue when its length is not set.
+	cfg = Config{OverloadMemory: 1000}
+	r = newLoadReporter(cfg, queueLength, prometheus.NewGauge(prometheus.GaugeOpts{Name: "load"}))
+	r.heapInUse = func() uint64 { return heap }
+	require.Equal(t, 0.9, r.Load())
+
+	// The ingesters which do not report their load are never overloaded.
+	var disabled *loadReporter
+	require.Zero(t, disabled.Load())
+}
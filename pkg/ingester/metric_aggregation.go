@@ -0,0 +1,234 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// metricAggregator pre-aggregates the entries pushed to the streams of a tenant for its metric aggregation rules.
+// A bucket is written to its pre-aggregated metric stream one rule interval after its end, and the entries of the
+// buckets already written are not aggregated anymore.
+type metricAggregator struct {
+	tenant  string
+	limits  Limits
+	metrics *ingesterMetrics
+	// owner decides which streams are aggregated by the ingester, all of them if nil.
+	owner *streamOwner
+
+	mtx     sync.Mutex
+	buckets map[metricBucketKey]*metricBucket
+	// writtenAt is the time of the last write of the closed buckets.
+	writtenAt time.Time
+}
+
+type metricBucketKey struct {
+	labels string
+	end    int64
+}
+
+// metricBucket holds the number and the size of the entries of a pre-aggregated metric stream
+// in the interval ending at end.
+type metricBucket struct {
+	labels       labels.Labels
+	end          time.Time
+	interval     time.Duration
+	count, bytes uint64
+}
+
+func newMetricAggregator(tenant string, limits Limits, metrics *ingesterMetrics) *metricAggregator {
+	return &metricAggregator{
+		tenant:    tenant,
+		limits:    limits,
+		metrics:   metrics,
+		buckets:   map[metricBucketKey]*metricBucket{},
+		writtenAt: time.Now(),
+	}
+}
+
+// observe aggregates the entries stored in the stream with the given labels.
+func (a *metricAggregator) observe(streamLabels labels.Labels, labelsString string, entries []logproto.Entry) {
+	if len(entries) == 0 || streamLabels.Has(push.AggregatedMetricLabel) {
+		return
+	}
+	rules := a.limits.MetricAggregationRules(a.tenant)
+	matched := false
+	for r := range rules {
+		if matchesAll(rules[r].Matchers, streamLabels) {
+			matched = true
+			break
+		}
+	}
+	if !matched || !a.owner.owns(a.tenant, labelsString) {
+		return
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	var late int
+	for r := range rules {
+		rule := &rules[r]
+		if !matchesAll(rule.Matchers, streamLabels) {
+			continue
+		}
+		interval := time.Duration(rule.Interval)
+		// The labels of the pre-aggregated stream only depend on the entries if some are in the structured metadata.
+		streamOnly := true
+		for _, name := range rule.By {
+			if !streamLabels.Has(name) {
+				streamOnly = false
+				break
+			}
+		}
+		var lbls labels.Labels
+		var key string
+		if streamOnly {
+			lbls = metricAggregationLabels(rule, a.owner, streamLabels, nil)
+			key = lbls.String()
+		}
+
+		for e := range entries {
+			entry := &entries[e]
+			end := metricBucketEnd(entry.Timestamp, interval)
+			if !end.Add(interval).After(a.writtenAt) {
+				late++
+				continue
+			}
+			if !streamOnly {
+				lbls = metricAggregationLabels(rule, a.owner, streamLabels, entry.StructuredMetadata)
+				key = lbls.String()
+			}
+			bucketKey := metricBucketKey{labels: key, end: end.UnixNano()}
+			bucket, ok := a.buckets[bucketKey]
+			if !ok {
+				bucket = &metricBucket{labels: lbls, end: end, interval: interval}
+				a.buckets[bucketKey] = bucket
+			}
+			bucket.count++
+			bucket.bytes += uint64(len(entry.Line))
+		}
+	}
+	if late > 0 {
+		a.metrics.metricAggregationLateEntries.WithLabelValues(a.tenant).Add(float64(late))
+	}
+}
+
+// closedBuckets removes the buckets ended at least one interval before now and returns their samples,
+// as the entries of the pre-aggregated metric streams.
+func (a *metricAggregator) closedBuckets(now time.Time) []logproto.Stream {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.writtenAt = now
+
+	streams := map[string]*logproto.Stream{}
+	for key, bucket := range a.buckets {
+		if bucket.end.Add(bucket.interval).After(now) {
+			continue
+		}
+		delete(a.buckets, key)
+
+		stream, ok := streams[key.labels]
+		if !ok {
+			stream = &logproto.Stream{Labels: key.labels, Hash: bucket.labels.Hash()}
+			streams[key.labels] = stream
+		}
+		stream.Entries = append(stream.Entries, logproto.Entry{
+			Timestamp: bucket.end,
+			Line:      fmt.Sprintf("%s=%d %s=%d", validation.MetricAggregationCountField, bucket.count, validation.MetricAggregationBytesField, bucket.bytes),
+		})
+	}
+
+	result := make([]logproto.Stream, 0, len(streams))
+	for _, stream := range streams {
+		sort.Slice(stream.Entries, func(i, j int) bool {
+			return stream.Entries[i].Timestamp.Before(stream.Entries[j].Timestamp)
+		})
+		result = append(result, *stream)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Labels < result[j].Labels })
+	return result
+}
+
+// metricAggregationLabels returns the labels of the pre-aggregated metric stream of an entry for a rule.
+func metricAggregationLabels(rule *validation.MetricAggregationRule, owner *streamOwner, streamLabels labels.Labels, structuredMetadata []logproto.LabelAdapter) labels.Labels {
+	b := labels.NewScratchBuilder(len(rule.By) + 3)
+	b.Add(push.AggregatedMetricLabel, rule.Name)
+	if owner != nil {
+		b.Add(push.AggregatedMetricOwnerLabel, owner.name())
+		b.Add(push.AggregatedMetricReplicaLabel, owner.ingesterID)
+	}
+	for _, name := range rule.By {
+		value := streamLabels.Get(name)
+		if value == "" {
+			for _, l := range structuredMetadata {
+				if l.Name == name {
+					value = l.Value
+					break
+				}
+			}
+		}
+		if value != "" {
+			b.Add(name, value)
+		}
+	}
+	b.Sort()
+	return b.Labels()
+}
+
+// metricBucketEnd returns the end of the bucket of a timestamp, the buckets including their end.
+func metricBucketEnd(ts time.Time, interval time.Duration) time.Time {
+	end := ts.Truncate(interval)
+	if end.Before(ts) {
+		end = end.Add(interval)
+	}
+	return end
+}
+
+func matchesAll(matchers []*labels.Matcher, lbls labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbls.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeMetricAggregations pushes the closed buckets of the metric aggregation rules of the tenant
+// to the pre-aggregated metric streams.
+func (i *instance) writeMetricAggregations(now time.Time) {
+	streams := i.metricAggregator.closedBuckets(now)
+	if len(streams) == 0 {
+		return
+	}
+
+	var samples int
+	for _, stream := range streams {
+		samples += len(stream.Entries)
+	}
+	ctx := user.InjectOrgID(context.Background(), i.instanceID)
+	if err := i.Push(ctx, &logproto.PushRequest{Streams: streams}); err != nil {
+		level.Warn(util_log.Logger).Log("msg", "failed to write pre-aggregated metric samples", "tenant", i.instanceID, "err", err)
+		return
+	}
+	i.metrics.metricAggregationSamples.WithLabelValues(i.instanceID).Add(float64(samples))
+}
+
+// writeMetricAggregations writes the closed buckets of the metric aggregation rules of every tenant.
+func (i *Ingester) writeMetricAggregations() {
+	now := time.Now()
+	for _, inst := range i.getInstances() {
+		inst.writeMetricAggregations(now)
+	}
+}
@@ -0,0 +1,158 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	loki_runtime "github.com/grafana/loki/v3/pkg/runtime"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestMetricBucketEnd(t *testing.T) {
+	base := time.Unix(600, 0)
+	require.Equal(t, base, metricBucketEnd(base, time.Minute))
+	require.Equal(t, base.Add(time.Minute), metricBucketEnd(base.Add(time.Nanosecond), time.Minute))
+	require.Equal(t, base.Add(time.Minute), metricBucketEnd(base.Add(time.Minute), time.Minute))
+}
+
+func TestInstanceMetricAggregation(t *testing.T) {
+	limits := defaultLimitsTestConfig()
+	limits.MetricAggregationRules = []validation.MetricAggregationRule{
+		{Name: "levels", Selector: `{app="foo"}`, By: []string{"level"}, Interval: model.Duration(time.Minute)},
+	}
+	require.NoError(t, limits.Validate())
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	inst, err := newInstance(defaultConfig(), defaultPeriodConfigs, "test", NewLimiter(overrides, NilMetrics, &ringCountMock{count: 1}, 1), loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, nil, nil, NewStreamRateCalculator(), nil, nil)
+	require.NoError(t, err)
+
+	base := time.Now().Truncate(time.Minute).Add(-10 * time.Minute)
+	inst.metricAggregator.writtenAt = base
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	require.NoError(t, inst.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="foo", level="info"}`, Entries: []logproto.Entry{
+			{Timestamp: base.Add(time.Second), Line: "1234"},
+			{Timestamp: base.Add(2 * time.Second), Line: "12"},
+			{Timestamp: base.Add(61 * time.Second), Line: "1"},
+		}},
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{
+			{Timestamp: base.Add(time.Second), Line: "123", StructuredMetadata: []logproto.LabelAdapter{{Name: "level", Value: "warn"}}},
+		}},
+		{Labels: `{app="bar", level="info"}`, Entries: []logproto.Entry{
+			{Timestamp: base.Add(time.Second), Line: "not aggregated"},
+		}},
+	}}))
+
+	// The buckets are written one interval after their end.
+	inst.writeMetricAggregations(base.Add(time.Minute))
+	require.Empty(t, readMetricAggregations(ctx, t, inst, base))
+
+	inst.writeMetricAggregations(base.Add(2 * time.Minute))
+	require.Equal(t, map[string][]string{
+		`{__aggregated_metric__="levels", level="info"}`: {"count=2 bytes=6"},
+		`{__aggregated_metric__="levels", level="warn"}`: {"count=1 bytes=3"},
+	}, readMetricAggregations(ctx, t, inst, base))
+
+	// The entries of the buckets already written are not aggregated anymore.
+	require.NoError(t, inst.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="foo", level="info", pod="a"}`, Entries: []logproto.Entry{
+			{Timestamp: base.Add(3 * time.Second), Line: "late"},
+			{Timestamp: base.Add(62 * time.Second), Line: "22"},
+		}},
+	}}))
+	inst.writeMetricAggregations(base.Add(3 * time.Minute))
+	require.Equal(t, map[string][]string{
+		`{__aggregated_metric__="levels", level="info"}`: {"count=2 bytes=6", "count=2 bytes=3"},
+		`{__aggregated_metric__="levels", level="warn"}`: {"count=1 bytes=3"},
+	}, readMetricAggregations(ctx, t, inst, base))
+}
+
+func TestInstanceMetricAggregationOwner(t *testing.T) {
+	limits := defaultLimitsTestConfig()
+	limits.MetricAggregationRules = []validation.MetricAggregationRule{
+		{Name: "levels", Selector: `{app="foo"}`, By: []string{"level"}, Interval: model.Duration(time.Minute)},
+	}
+	require.NoError(t, limits.Validate())
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		owner    *streamOwner
+		expected map[string][]string
+	}{
+		{
+			name:  "first ingester of the replication set",
+			owner: &streamOwner{ring: newReadRingMock([]ring.InstanceDesc{{Id: "ingester-1"}, {Id: "ingester-2"}}, 0), ingesterID: "ingester-1"},
+			expected: map[string][]string{
+				`{__aggregated_metric__="levels", __aggregated_metric_owner__="ingester-1", __aggregated_metric_replica__="ingester-1", level="info"}`: {"count=1 bytes=4"},
+			},
+		},
+		{
+			name:     "other ingesters of the replication set",
+			owner:    &streamOwner{ring: newReadRingMock([]ring.InstanceDesc{{Id: "ingester-1"}, {Id: "ingester-2"}}, 0), ingesterID: "ingester-2"},
+			expected: map[string][]string{},
+		},
+		{
+			name:  "ingesters of a partition",
+			owner: &streamOwner{ingesterID: "ingester-2", partition: "3"},
+			expected: map[string][]string{
+				`{__aggregated_metric__="levels", __aggregated_metric_owner__="3", __aggregated_metric_replica__="ingester-2", level="info"}`: {"count=1 bytes=4"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			inst, err := newInstance(defaultConfig(), defaultPeriodConfigs, "test", NewLimiter(overrides, NilMetrics, &ringCountMock{count: 1}, 1), loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, nil, nil, NewStreamRateCalculator(), nil, nil)
+			require.NoError(t, err)
+			inst.metricAggregator.owner = tc.owner
+
+			base := time.Now().Truncate(time.Minute).Add(-10 * time.Minute)
+			inst.metricAggregator.writtenAt = base
+
+			ctx := user.InjectOrgID(context.Background(), "test")
+			require.NoError(t, inst.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+				{Labels: `{app="foo", level="info"}`, Entries: []logproto.Entry{
+					{Timestamp: base.Add(time.Second), Line: "1234"},
+				}},
+			}}))
+			inst.writeMetricAggregations(base.Add(2 * time.Minute))
+			require.Equal(t, tc.expected, readMetricAggregations(ctx, t, inst, base))
+		})
+	}
+}
+
+func readMetricAggregations(ctx context.Context, t *testing.T, inst *instance, from time.Time) map[string][]string {
+	it, err := inst.Query(ctx, logql.SelectLogParams{
+		QueryRequest: &logproto.QueryRequest{
+			Selector:  `{__aggregated_metric__="levels"}`,
+			Limit:     100,
+			Start:     from,
+			End:       from.Add(time.Hour),
+			Direction: logproto.FORWARD,
+			Plan: &plan.QueryPlan{
+				AST: syntax.MustParseExpr(`{__aggregated_metric__="levels"}`),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer it.Close()
+
+	result := map[string][]string{}
+	for it.Next() {
+		result[it.Labels()] = append(result[it.Labels()], it.At().Line)
+	}
+	require.NoError(t, it.Err())
+	return result
+}
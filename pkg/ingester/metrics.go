@@ -70,6 +70,15 @@ type ingesterMetrics struct {
 	flushQueueLength       prometheus.Gauge
 	duplicateLogBytesTotal *prometheus.CounterVec
 	streamsOwnershipCheck  prometheus.Histogram
+
+	metricAggregationSamples     *prometheus.CounterVec
+	metricAggregationLateEntries *prometheus.CounterVec
+
+	transferredStreamsTotal *prometheus.CounterVec
+	transferredChunksTotal  *prometheus.CounterVec
+	transferFailuresTotal   prometheus.Counter
+
+	load prometheus.Gauge
 }
 
 // setRecoveryBytesInUse bounds the bytes reports to >= 0.
@@ -328,5 +337,42 @@ func newIngesterMetrics(r prometheus.Registerer, metricsNamespace string) *inges
 			Name:      "duplicate_log_bytes_total",
 			Help:      "The total number of bytes that were discarded for duplicate log lines.",
 		}, []string{"tenant"}),
+
+		metricAggregationSamples: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Subsystem: "ingester",
+			Name:      "metric_aggregation_samples_total",
+			Help:      "The total number of pre-aggregated metric samples written to the streams of the metric aggregation rules.",
+		}, []string{"tenant"}),
+		metricAggregationLateEntries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Subsystem: "ingester",
+			Name:      "metric_aggregation_late_entries_total",
+			Help:      "The total number of entries not pre-aggregated because the bucket of their timestamp was already written.",
+		}, []string{"tenant"}),
+		transferredStreamsTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Subsystem: "ingester",
+			Name:      "transferred_streams_total",
+			Help:      "The total number of in-memory streams transferred between ingesters, sent or received.",
+		}, []string{"direction"}),
+		transferredChunksTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Subsystem: "ingester",
+			Name:      "transferred_chunks_total",
+			Help:      "The total number of in-memory chunks transferred between ingesters, sent or received.",
+		}, []string{"direction"}),
+		transferFailuresTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Subsystem: "ingester",
+			Name:      "transfer_failures_total",
+			Help:      "The total number of failed transfers of the in-memory streams on shutdown, after which the chunks are flushed.",
+		}),
+		load: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: constants.Loki,
+			Subsystem: "ingester",
+			Name:      "load",
+			Help:      "The load of the ingester reported to the distributors, 1 when it is fully loaded.",
+		}),
 	}
 }
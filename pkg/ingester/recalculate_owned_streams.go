@@ -2,6 +2,7 @@ package ingester
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/go-kit/log"
@@ -20,6 +21,11 @@ type recalculateOwnedStreams struct {
 	previousRing      ring.ReplicationSet
 	ingestersRing     ring.ReadRing
 	ticker            *time.Ticker
+
+	// When set, streams are owned by the partition of the ingester rather than by its ring tokens.
+	partitionRing            ring.PartitionRingReader
+	partitionID              int32
+	previousActivePartitions []int32
 }
 
 func newRecalculateOwnedStreams(instancesSupplier func() []*instance, ingesterID string, ring ring.ReadRing, ringPollInterval time.Duration, logger log.Logger) *recalculateOwnedStreams {
@@ -33,6 +39,13 @@ func newRecalculateOwnedStreams(instancesSupplier func() []*instance, ingesterID
 	return svc
 }
 
+// usePartitionRing makes the ownership of streams follow the partition ring, which is
+// how streams are assigned to ingesters when they are written through Kafka.
+func (s *recalculateOwnedStreams) usePartitionRing(partitionRing ring.PartitionRingReader, partitionID int32) {
+	s.partitionRing = partitionRing
+	s.partitionID = partitionID
+}
+
 func (s *recalculateOwnedStreams) iteration(_ context.Context) error {
 	s.recalculate()
 	return nil
@@ -61,7 +74,12 @@ func (s *recalculateOwnedStreams) recalculate() {
 		}
 
 		level.Info(s.logger).Log("msg", "updating streams ownership", "tenant", instance.instanceID)
-		err := instance.updateOwnedStreams(s.ingestersRing, s.ingesterID)
+		var err error
+		if s.partitionRing != nil {
+			err = instance.updateOwnedStreamsByPartition(s.partitionRing.PartitionRing(), s.partitionID)
+		} else {
+			err = instance.updateOwnedStreams(s.ingestersRing, s.ingesterID)
+		}
 		if err != nil {
 			level.Error(s.logger).Log("msg", "failed to re-evaluate streams ownership", "tenant", instance.instanceID, "err", err)
 		}
@@ -78,6 +96,15 @@ func (s *recalculateOwnedStreams) updateFixedLimitForAll() {
 }
 
 func (s *recalculateOwnedStreams) checkRingForChanges() (bool, error) {
+	if s.partitionRing != nil {
+		// Partition tokens are derived from the partition ID, so the assignment of streams
+		// only changes when the set of active partitions does.
+		active := s.partitionRing.PartitionRing().ActivePartitionIDs()
+		ringChanged := !slices.Equal(s.previousActivePartitions, active)
+		s.previousActivePartitions = active
+		return ringChanged, nil
+	}
+
 	rs, err := s.ingestersRing.GetAllHealthy(ring.WriteNoExtend)
 	if err != nil {
 		return false, err
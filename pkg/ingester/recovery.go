@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 	"github.com/prometheus/prometheus/tsdb/record"
 	"github.com/prometheus/prometheus/tsdb/wlog"
@@ -59,11 +60,21 @@ type Recoverer interface {
 }
 
 type ingesterRecoverer struct {
-	// basically map[userID]map[fingerprint]*stream
+	// basically map[userID]map[fingerprint]*recoveredStream
 	users  sync.Map
 	ing    *Ingester
 	logger log.Logger
 	done   chan struct{}
+	// lazy is set when the WAL is replayed while the ingester receives pushes.
+	lazy bool
+}
+
+// recoveredStream is a stream referenced by the WAL records.
+type recoveredStream struct {
+	*stream
+	// entryCt is the highest counter of the replayed entries of the stream. It is only used by lazy
+	// replays, as the counter of the stream is also incremented by the pushes received meanwhile.
+	entryCt int64
 }
 
 func newIngesterRecoverer(i *Ingester) *ingesterRecoverer {
@@ -72,6 +83,7 @@ func newIngesterRecoverer(i *Ingester) *ingesterRecoverer {
 		ing:    i,
 		done:   make(chan struct{}),
 		logger: i.logger,
+		lazy:   i.cfg.WAL.LazyReplay,
 	}
 }
 
@@ -86,21 +98,7 @@ func (r *ingesterRecoverer) Series(series *Series) error {
 			return err
 		}
 
-		// TODO(owen-d): create another fn to avoid unnecessary label type conversions.
-		stream, err := inst.getOrCreateStream(context.Background(), logproto.Stream{
-			Labels: logproto.FromLabelAdaptersToLabels(series.Labels).String(),
-		}, nil)
-
-		if err != nil {
-			return err
-		}
-
-		bytesAdded, entriesAdded, err := stream.setChunks(series.Chunks)
-		stream.lastLine.ts = series.To
-		stream.lastLine.content = series.LastLine
-		stream.entryCt = series.EntryCt
-		stream.highestTs = series.HighestTs
-
+		stream, bytesAdded, entriesAdded, err := inst.recoverSeries(series)
 		if err != nil {
 			return err
 		}
@@ -114,12 +112,47 @@ func (r *ingesterRecoverer) Series(series *Series) error {
 		// will use this original reference.
 		got, _ := r.users.LoadOrStore(series.UserID, &sync.Map{})
 		streamsMap := got.(*sync.Map)
-		streamsMap.Store(chunks.HeadSeriesRef(series.Fingerprint), stream)
+		streamsMap.Store(chunks.HeadSeriesRef(series.Fingerprint), &recoveredStream{stream: stream, entryCt: series.EntryCt})
 
 		return nil
 	})
 }
 
+// recoverSeries adds the chunks of a series, read from a checkpoint or transferred by another ingester,
+// to the stream with the labels of the series.
+func (i *instance) recoverSeries(series *Series) (*stream, int, int, error) {
+	// TODO(owen-d): create another fn to avoid unnecessary label type conversions.
+	stream, err := i.getOrCreateStream(context.Background(), logproto.Stream{
+		Labels: logproto.FromLabelAdaptersToLabels(series.Labels).String(),
+	}, nil)
+
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	bytesAdded, entriesAdded, err := stream.setChunks(series.Chunks)
+	stream.chunkMtx.Lock()
+	// The stream may already hold more recent entries pushed during a lazy replay, or, for the
+	// transferred streams, pushed since the sending ingester started leaving.
+	if !series.To.Before(stream.lastLine.ts) {
+		stream.lastLine.ts = series.To
+		stream.lastLine.content = series.LastLine
+	}
+	stream.entryCt = max(stream.entryCt, series.EntryCt)
+	if series.HighestTs.After(stream.highestTs) {
+		stream.highestTs = series.HighestTs
+	}
+	stream.chunkMtx.Unlock()
+
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if err := i.structuredMetadataIndexer.addChunks(context.Background(), stream); err != nil {
+		return nil, 0, 0, err
+	}
+	return stream, bytesAdded, entriesAdded, nil
+}
+
 // SetStream is responsible for setting the key path for userIDs -> fingerprints -> streams.
 // Internally, this uses nested sync.Maps due to their performance benefits for sets that only grow.
 // Using these also allows us to bypass the ingester -> instance -> stream hierarchy internally, which
@@ -152,10 +185,27 @@ func (r *ingesterRecoverer) SetStream(ctx context.Context, userID string, series
 	// path is set properly.
 	got, _ := r.users.LoadOrStore(userID, &sync.Map{})
 	streamsMap := got.(*sync.Map)
-	streamsMap.Store(series.Ref, stream)
+	if prev, ok := streamsMap.Load(series.Ref); ok && prev.(*recoveredStream).stream == stream {
+		// Keep the counter of the entries already replayed.
+		return nil
+	}
+	streamsMap.Store(series.Ref, &recoveredStream{stream: stream})
 	return nil
 }
 
+// streamLabels returns the labels of the stream referenced by the WAL records of a user.
+func (r *ingesterRecoverer) streamLabels(userID string, ref chunks.HeadSeriesRef) (labels.Labels, bool) {
+	out, ok := r.users.Load(userID)
+	if !ok {
+		return nil, false
+	}
+	s, ok := out.(*sync.Map).Load(ref)
+	if !ok {
+		return nil, false
+	}
+	return s.(*recoveredStream).labels, true
+}
+
 func (r *ingesterRecoverer) Push(userID string, entries wal.RefEntries) error {
 	return r.ing.replayController.WithBackPressure(func() error {
 		out, ok := r.users.Load(userID)
@@ -168,8 +218,13 @@ func (r *ingesterRecoverer) Push(userID string, entries wal.RefEntries) error {
 			return fmt.Errorf("stream (%d) not set during WAL replay for user (%s)", entries.Ref, userID)
 		}
 
+		rs := s.(*recoveredStream)
+		if r.lazy {
+			return r.pushLazy(rs, entries)
+		}
+
 		// ignore out of order errors here (it's possible for a checkpoint to already have data from the wal segments)
-		bytesAdded, err := s.(*stream).Push(context.Background(), entries.Entries, nil, entries.Counter, true, false, r.ing.customStreamsTracker)
+		bytesAdded, err := rs.Push(context.Background(), entries.Entries, nil, entries.Counter, true, false, r.ing.customStreamsTracker)
 		r.ing.replayController.Add(int64(bytesAdded))
 		if err != nil && err == ErrEntriesExist {
 			r.ing.metrics.duplicateEntriesTotal.Add(float64(len(entries.Entries)))
@@ -178,6 +233,29 @@ func (r *ingesterRecoverer) Push(userID string, entries wal.RefEntries) error {
 	})
 }
 
+// pushLazy replays entries while the stream may receive pushes. The replayed entries are
+// deduplicated with the counter of the recovered stream instead of the one of the stream.
+func (r *ingesterRecoverer) pushLazy(rs *recoveredStream, entries wal.RefEntries) error {
+	if entries.Counter <= rs.entryCt {
+		var byteCt int
+		for _, e := range entries.Entries {
+			byteCt += len(e.Line)
+		}
+		r.ing.metrics.walReplaySamplesDropped.WithLabelValues(duplicateReason).Add(float64(len(entries.Entries)))
+		r.ing.metrics.walReplayBytesDropped.WithLabelValues(duplicateReason).Add(float64(byteCt))
+		r.ing.metrics.duplicateEntriesTotal.Add(float64(len(entries.Entries)))
+		return nil
+	}
+	rs.entryCt = entries.Counter
+
+	rs.chunkMtx.Lock()
+	defer rs.chunkMtx.Unlock()
+	// ignore out of order errors here, as for regular replays.
+	bytesAdded, _ := rs.push(context.Background(), entries.Entries, nil, true, false, r.ing.customStreamsTracker)
+	r.ing.replayController.Add(int64(bytesAdded))
+	return nil
+}
+
 func (r *ingesterRecoverer) Close() {
 	// Ensure this is only run once.
 	select {
@@ -197,7 +275,10 @@ func (r *ingesterRecoverer) Close() {
 			defer s.chunkMtx.Unlock()
 
 			// reset all the incrementing stream counters after a successful WAL replay.
-			s.resetCounter()
+			// After a lazy replay, the counters were already used by the entries pushed meanwhile.
+			if !r.lazy {
+				s.resetCounter()
+			}
 
 			if len(s.chunks) == 0 {
 				inst.removeStream(s)
@@ -82,6 +82,11 @@ type stream struct {
 	chunkHeadBlockFormat chunkenc.HeadBlockFmt
 
 	configs *runtime.TenantConfigs
+
+	// metricAggregator pre-aggregates the entries pushed, if set.
+	metricAggregator *metricAggregator
+	// structuredMetadataIndexer indexes the structured metadata of the entries pushed, if set.
+	structuredMetadataIndexer *structuredMetadataIndexer
 }
 
 type chunkDesc struct {
@@ -92,6 +97,11 @@ type chunkDesc struct {
 	reason  string
 
 	lastUpdated time.Time
+
+	// partitionOffset is the offset of the oldest Kafka record written to the chunk,
+	// when the ingester consumes a partition instead of being pushed to.
+	partitionOffset    int64
+	hasPartitionOffset bool
 }
 
 type entryWithError struct {
@@ -161,11 +171,12 @@ func (s *stream) setChunks(chunks []Chunk) (bytesAdded, entriesAdded int, err er
 	if err != nil {
 		return 0, 0, err
 	}
-	s.chunks = chks
-	for _, c := range s.chunks {
+	for _, c := range chks {
 		entriesAdded += c.chunk.Size()
 		bytesAdded += c.chunk.UncompressedSize()
 	}
+	// Chunks created by pushes during a lazy WAL replay hold more recent data and stay last.
+	s.chunks = append(chks, s.chunks...)
 	return bytesAdded, entriesAdded, nil
 }
 
@@ -209,6 +220,19 @@ func (s *stream) Push(
 		return 0, ErrEntriesExist
 	}
 
+	return s.push(ctx, entries, record, isReplay, rateLimitWholeStream, usageTracker)
+}
+
+// push stores the entries in the chunks of the stream. chunkMtx must be held.
+// WAL replays skip the checks of the entries timestamps against the most recent entry.
+func (s *stream) push(
+	ctx context.Context,
+	entries []logproto.Entry,
+	record *wal.Record,
+	isReplay bool,
+	rateLimitWholeStream bool,
+	usageTracker push.UsageTracker,
+) (int, error) {
 	toStore, invalid := s.validateEntries(ctx, entries, isReplay, rateLimitWholeStream, usageTracker)
 	if rateLimitWholeStream && hasRateLimitErr(invalid) {
 		return 0, errorForFailedEntries(s, invalid, len(entries))
@@ -225,6 +249,13 @@ func (s *stream) Push(
 
 	bytesAdded, storedEntries, entriesWithErr := s.storeEntries(ctx, toStore, usageTracker)
 	s.recordAndSendToTailers(record, storedEntries)
+	if s.structuredMetadataIndexer != nil {
+		s.structuredMetadataIndexer.add(s.fp, storedEntries)
+	}
+	// The replayed entries were already aggregated before the restart.
+	if !isReplay && s.metricAggregator != nil {
+		s.metricAggregator.observe(s.labels, s.labelsString, storedEntries)
+	}
 
 	if len(s.chunks) != prevNumChunks {
 		s.metrics.memoryChunks.Add(float64(len(s.chunks) - prevNumChunks))
@@ -244,12 +275,18 @@ func errorForFailedEntries(s *stream, failedEntriesWithError []entryWithError, t
 	if !outOfOrder && !ok {
 		return lastEntryWithErr.e
 	}
-	var statusCode int
+	var (
+		statusCode int
+		reason     string
+	)
 	if outOfOrder {
-		statusCode = http.StatusBadRequest
+		statusCode, reason = http.StatusBadRequest, validation.OutOfOrder
+		if chunkenc.IsErrTooFarBehind(lastEntryWithErr.e) {
+			reason = validation.TooFarBehind
+		}
 	}
 	if ok {
-		statusCode = http.StatusTooManyRequests
+		statusCode, reason = http.StatusTooManyRequests, validation.StreamRateLimit
 	}
 	// Return a http status 4xx request response with all failed entries.
 	buf := bytes.Buffer{}
@@ -268,7 +305,32 @@ func errorForFailedEntries(s *stream, failedEntriesWithError []entryWithError, t
 
 	fmt.Fprintf(&buf, "user '%s', total ignored: %d out of %d for stream: %s", s.tenant, len(failedEntriesWithError), totalEntries, streamName)
 
-	return httpgrpc.Errorf(statusCode, buf.String())
+	return &errRejectedEntries{
+		error:   httpgrpc.Errorf(statusCode, buf.String()),
+		reason:  reason,
+		entries: len(failedEntriesWithError),
+	}
+}
+
+// errRejectedEntries is the error of a push whose entries were rejected by the limits or the
+// validations of the stream. It wraps the httpgrpc error returned to the client, along with the
+// reason and the number of entries rejected reported in the push responses.
+type errRejectedEntries struct {
+	error
+	reason  string
+	entries int
+}
+
+func (e *errRejectedEntries) Unwrap() error {
+	return e.error
+}
+
+// unwrapRejectedEntries returns the error returned to the client for the given push error.
+func unwrapRejectedEntries(err error) error {
+	if rejection, ok := err.(*errRejectedEntries); ok {
+		return rejection.error
+	}
+	return err
 }
 
 func hasRateLimitErr(errs []entryWithError) bool {
@@ -301,6 +363,8 @@ func (s *stream) recordAndSendToTailers(record *wal.Record, entries []logproto.E
 		stream := logproto.Stream{Labels: s.labelsString, Entries: entries}
 
 		closedTailers := []uint32{}
+		// The tailers of the same query share the result of its pipeline.
+		results := map[string]*tailResult{}
 
 		s.tailerMtx.RLock()
 		for _, tailer := range s.tailers {
@@ -308,7 +372,12 @@ func (s *stream) recordAndSendToTailers(record *wal.Record, entries []logproto.E
 				closedTailers = append(closedTailers, tailer.getID())
 				continue
 			}
-			tailer.send(stream, s.labels)
+			result, ok := results[tailer.query]
+			if !ok {
+				result = &tailResult{}
+				results[tailer.query] = result
+			}
+			tailer.send(stream, s.labels, result)
 		}
 		s.tailerMtx.RUnlock()
 
@@ -413,7 +482,8 @@ func (s *stream) validateEntries(ctx context.Context, entries []logproto.Entry,
 		totalBytes += lineBytes
 
 		now := time.Now()
-		if !rateLimitWholeStream && !s.limiter.AllowN(now, len(entries[i].Line)) {
+		// The replayed entries were already accepted, they are not rate limited again.
+		if !isReplay && !rateLimitWholeStream && !s.limiter.AllowN(now, len(entries[i].Line)) {
 			failedEntriesWithError = append(failedEntriesWithError, entryWithError{&entries[i], &validation.ErrStreamRateLimit{RateLimit: flagext.ByteSize(limit), Labels: s.labelsString, Bytes: flagext.ByteSize(lineBytes)}})
 			s.writeFailures.Log(s.tenant, failedEntriesWithError[len(failedEntriesWithError)-1].e)
 			rateLimitedSamples++
@@ -508,6 +578,19 @@ func (s *stream) cutChunk(ctx context.Context) *chunkDesc {
 	return &s.chunks[len(s.chunks)-1]
 }
 
+// setPartitionOffset records offset on the chunks written to by a push of a record
+// read from a Kafka partition. prevNumChunks is the number of chunks before the push.
+// Must hold chunkMtx
+func (s *stream) setPartitionOffset(prevNumChunks int, offset int64) {
+	for i := max(prevNumChunks-1, 0); i < len(s.chunks); i++ {
+		c := &s.chunks[i]
+		if !c.hasPartitionOffset && c.chunk.Size() > 0 {
+			c.partitionOffset = offset
+			c.hasPartitionOffset = true
+		}
+	}
+}
+
 // Returns true, if chunk should be cut before adding new entry. This is done to make ingesters
 // cut the chunk for this stream at the same moment, so that new chunk will contain exactly the same entries.
 func (s *stream) cutChunkForSynchronization(entryTimestamp, latestTs time.Time, c *chunkDesc, synchronizePeriod time.Duration, minUtilization float64) bool {
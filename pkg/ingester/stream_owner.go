@@ -0,0 +1,40 @@
+package ingester
+
+import (
+	"github.com/grafana/dskit/ring"
+
+	lokiring "github.com/grafana/loki/v3/pkg/util/ring"
+)
+
+// streamOwner decides which of the ingesters holding the replicas of a stream owns it, so that the entries of the
+// stream are only counted once across its replicas: by the metric aggregations, the sums of which would otherwise
+// count each entry once per replica, and by the tailers. With the ingesters ring, only the first ingester of the
+// replication set of a stream owns it. With Kafka, every ingester of the partition of a stream owns it, and the
+// queries of the pre-aggregated metrics keep the samples of one of the replicas of each partition.
+type streamOwner struct {
+	ring       ring.ReadRing
+	ingesterID string
+	// partition is set when the streams are read from a Kafka partition.
+	partition string
+}
+
+// owns returns whether the ingester owns the stream of the tenant with the given labels.
+func (o *streamOwner) owns(tenant, streamLabels string) bool {
+	if o == nil || o.partition != "" {
+		return true
+	}
+	var descs [5]ring.InstanceDesc
+	replicationSet, err := o.ring.Get(lokiring.TokenFor(tenant, streamLabels), ring.WriteNoExtend, descs[:0], nil, nil)
+	if err != nil || len(replicationSet.Instances) == 0 {
+		return false
+	}
+	return replicationSet.Instances[0].Id == o.ingesterID
+}
+
+// name returns the value of the owner label of the pre-aggregated metric streams written by the ingester.
+func (o *streamOwner) name() string {
+	if o.partition != "" {
+		return o.partition
+	}
+	return o.ingesterID
+}
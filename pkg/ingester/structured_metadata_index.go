@@ -0,0 +1,135 @@
+package ingester
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/ingester/index"
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/util/deletion"
+	server_util "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+// structuredMetadataIndexer indexes the entries pushed to the streams of a tenant
+// by the values of its indexed structured metadata keys.
+type structuredMetadataIndexer struct {
+	tenant string
+	limits Limits
+	index  *index.StructuredMetadataIndex
+}
+
+func newStructuredMetadataIndexer(tenant string, limits Limits, totalShards uint32) *structuredMetadataIndexer {
+	return &structuredMetadataIndexer{
+		tenant: tenant,
+		limits: limits,
+		index:  index.NewStructuredMetadataIndex(totalShards),
+	}
+}
+
+// add indexes the entries stored in the stream with the given fingerprint.
+func (x *structuredMetadataIndexer) add(fp model.Fingerprint, entries []logproto.Entry) {
+	keys := x.limits.IndexedStructuredMetadataKeys(x.tenant)
+	if len(keys) == 0 {
+		return
+	}
+	for e := range entries {
+		for _, l := range entries[e].StructuredMetadata {
+			if slices.Contains(keys, l.Name) {
+				x.index.Add(fp, l.Name, l.Value, entries[e].Timestamp)
+			}
+		}
+	}
+}
+
+// addChunks indexes the entries of the chunks of a stream recovered from a checkpoint.
+func (x *structuredMetadataIndexer) addChunks(ctx context.Context, s *stream) error {
+	if len(x.limits.IndexedStructuredMetadataKeys(x.tenant)) == 0 {
+		return nil
+	}
+	it, err := s.Iterator(ctx, nil, time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, log.NewNoopPipeline().ForStream(s.labels))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		x.add(s.fp, []logproto.Entry{it.At()})
+	}
+	return it.Err()
+}
+
+// Lookup returns the streams having entries with a value of an indexed structured metadata key, and these entries.
+func (i *instance) Lookup(ctx context.Context, req *logproto.LookupRequest) (*logproto.LookupResponse, error) {
+	resp, err := i.lookup(ctx, req)
+	err = server_util.ClientGrpcStatusAndError(err)
+	return resp, err
+}
+
+func (i *instance) lookup(ctx context.Context, req *logproto.LookupRequest) (*logproto.LookupResponse, error) {
+	matcher, err := labels.NewMatcher(labels.MatchEqual, req.Key, req.Value)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, err := deletion.SetupPipeline(logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{Deletes: req.Deletes}},
+		log.NewPipeline([]log.Stage{log.NewStringLabelFilter(matcher)}))
+	if err != nil {
+		return nil, err
+	}
+
+	statsCtx := stats.FromContext(ctx)
+	resp := &logproto.LookupResponse{}
+	var iters []iter.EntryIterator
+	for _, ref := range i.structuredMetadataIndexer.index.Lookup(req.Key, req.Value, req.Start, req.End) {
+		s, ok := i.streams.LoadByFP(ref.Fingerprint)
+		if !ok {
+			continue
+		}
+		it, err := s.Iterator(ctx, statsCtx, req.Start, req.End, req.Direction, pipeline.ForStream(s.labels))
+		if err != nil {
+			for _, it := range iters {
+				it.Close()
+			}
+			return nil, err
+		}
+		iters = append(iters, it)
+		resp.Refs = append(resp.Refs, logproto.LookupRef{
+			Labels:      s.labelsString,
+			Fingerprint: uint64(s.fp),
+			From:        ref.From,
+			Through:     ref.Through,
+		})
+	}
+
+	it := iter.NewSortEntryIterator(iters, req.Direction)
+	defer it.Close()
+	batch, _, err := iter.ReadBatch(it, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	resp.Streams = batch.Streams
+	return resp, nil
+}
+
+// Lookup returns the streams having entries with a value of an indexed structured metadata key
+// held by this ingester, and these entries.
+func (i *Ingester) Lookup(ctx context.Context, req *logproto.LookupRequest) (*logproto.LookupResponse, error) {
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := i.GetOrCreateInstance(userID)
+	if err != nil {
+		return nil, err
+	}
+	return instance.Lookup(ctx, req)
+}
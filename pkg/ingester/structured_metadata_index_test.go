@@ -0,0 +1,91 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	loki_runtime "github.com/grafana/loki/v3/pkg/runtime"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestInstanceLookup(t *testing.T) {
+	limits := defaultLimitsTestConfig()
+	limits.IndexedStructuredMetadataKeys = []string{"trace_id"}
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	inst, err := newInstance(defaultConfig(), defaultPeriodConfigs, "test", NewLimiter(overrides, NilMetrics, &ringCountMock{count: 1}, 1), loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, nil, nil, NewStreamRateCalculator(), nil, nil)
+	require.NoError(t, err)
+
+	base := time.Unix(1000, 0)
+	ctx := user.InjectOrgID(context.Background(), "test")
+	require.NoError(t, inst.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{
+			{Timestamp: base.Add(time.Second), Line: "1", StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "abc"}}},
+			{Timestamp: base.Add(2 * time.Second), Line: "2", StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "def"}}},
+			{Timestamp: base.Add(3 * time.Second), Line: "3", StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "abc"}}},
+		}},
+		{Labels: `{app="bar"}`, Entries: []logproto.Entry{
+			{Timestamp: base.Add(4 * time.Second), Line: "4", StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "abc"}}},
+			// Only the configured keys are indexed.
+			{Timestamp: base.Add(5 * time.Second), Line: "5", StructuredMetadata: []logproto.LabelAdapter{{Name: "request_id", Value: "abc"}}},
+		}},
+		{Labels: `{app="baz"}`, Entries: []logproto.Entry{
+			{Timestamp: base.Add(6 * time.Second), Line: "6"},
+		}},
+	}}))
+
+	lookup := func(key, value string, limit uint32) *logproto.LookupResponse {
+		resp, err := inst.Lookup(ctx, &logproto.LookupRequest{
+			Key:       key,
+			Value:     value,
+			Start:     base,
+			End:       base.Add(time.Minute),
+			Limit:     limit,
+			Direction: logproto.FORWARD,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := lookup("trace_id", "abc", 100)
+	require.Len(t, resp.Refs, 2)
+	refs := map[string]logproto.LookupRef{}
+	for _, ref := range resp.Refs {
+		refs[ref.Labels] = ref
+	}
+	require.Equal(t, base.Add(time.Second), refs[`{app="foo"}`].From)
+	require.Equal(t, base.Add(3*time.Second), refs[`{app="foo"}`].Through)
+	require.Equal(t, base.Add(4*time.Second), refs[`{app="bar"}`].From)
+
+	lines := map[string][]string{}
+	for _, stream := range resp.Streams {
+		for _, entry := range stream.Entries {
+			lines[stream.Labels] = append(lines[stream.Labels], entry.Line)
+		}
+	}
+	// The entries are labelled with their structured metadata, like the entries of the log queries.
+	require.Equal(t, map[string][]string{
+		`{app="foo", trace_id="abc"}`: {"1", "3"},
+		`{app="bar", trace_id="abc"}`: {"4"},
+	}, lines)
+
+	resp = lookup("trace_id", "abc", 1)
+	require.Len(t, resp.Streams, 1)
+	require.Equal(t, "1", resp.Streams[0].Entries[0].Line)
+
+	require.Empty(t, lookup("request_id", "abc", 100).Refs)
+
+	// The index entries of the streams removed are deleted.
+	s, ok := inst.streams.Load(`{app="foo"}`)
+	require.True(t, ok)
+	inst.removeStream(s)
+	resp = lookup("trace_id", "abc", 100)
+	require.Len(t, resp.Refs, 1)
+	require.Equal(t, `{app="bar"}`, resp.Refs[0].Labels)
+}
@@ -3,13 +3,16 @@ package ingester
 import (
 	"encoding/binary"
 	"hash/fnv"
+	"math"
 	"sync"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/prometheus/model/labels"
 	"go.uber.org/atomic"
 	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/log"
@@ -21,6 +24,10 @@ import (
 const (
 	bufferSizeForTailResponse = 5
 	bufferSizeForTailStream   = 100
+
+	// dropCountsPeriod is how often the counts of the entries not sent are sent to the querier when they change,
+	// even when no entries are sent.
+	dropCountsPeriod = time.Second
 )
 
 type TailServer interface {
@@ -31,15 +38,40 @@ type TailServer interface {
 type tailRequest struct {
 	stream logproto.Stream
 	lbs    labels.Labels
+	result *tailResult
+	owned  bool
+}
+
+// tailResult is the result of the pipeline of a query for a stream pushed. It is shared by the tailers of the same
+// query, so the pipeline is only evaluated once for all of them.
+type tailResult struct {
+	once    sync.Once
+	streams []*logproto.Stream
 }
 
 type tailer struct {
 	id          uint32
 	orgID       string
+	query       string
 	matchers    []*labels.Matcher
 	pipeline    syntax.Pipeline
 	pipelineMtx sync.Mutex
 
+	// sample is the fraction of the entries sent, and limiter limits the entries sent per second, when set.
+	sample  float64
+	limiter *rate.Limiter
+
+	// owner decides which of the replicas of a stream count its entries not sent, and send it when the tailer is
+	// rate limited: the replicas would otherwise each limit the stream independently. nil when the ingester
+	// owns all the streams.
+	owner *streamOwner
+
+	// Number of entries of the streams owned not sent since the tailer started, returned to the client with each
+	// response, and at least once per dropCountsPeriod when they change.
+	sampledEntries     atomic.Uint64
+	rateLimitedEntries atomic.Uint64
+	droppedEntries     atomic.Uint64
+
 	queue    chan tailRequest
 	sendChan chan *logproto.Stream
 
@@ -57,7 +89,7 @@ type tailer struct {
 	conn TailServer
 }
 
-func newTailer(orgID string, expr syntax.LogSelectorExpr, conn TailServer, maxDroppedStreams int) (*tailer, error) {
+func newTailer(orgID string, expr syntax.LogSelectorExpr, conn TailServer, maxDroppedStreams int, sample float64, maxEntriesPerSecond int) (*tailer, error) {
 	// Make sure we can build a pipeline. The stream processing code doesn't have a place to handle
 	// this error so make sure we handle it here.
 	pipeline, err := expr.Pipeline()
@@ -66,15 +98,27 @@ func newTailer(orgID string, expr syntax.LogSelectorExpr, conn TailServer, maxDr
 	}
 	matchers := expr.Matchers()
 
+	if sample <= 0 || sample >= 1 {
+		sample = 0
+	}
+	var limiter *rate.Limiter
+	if maxEntriesPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(maxEntriesPerSecond), maxEntriesPerSecond)
+	}
+
+	query := expr.String()
 	return &tailer{
 		orgID:             orgID,
+		query:             query,
 		matchers:          matchers,
+		sample:            sample,
+		limiter:           limiter,
 		sendChan:          make(chan *logproto.Stream, bufferSizeForTailResponse),
 		queue:             make(chan tailRequest, bufferSizeForTailStream),
 		conn:              conn,
 		droppedStreams:    make([]*logproto.DroppedStream, 0, maxDroppedStreams),
 		maxDroppedStreams: maxDroppedStreams,
-		id:                generateUniqueID(orgID, expr.String()),
+		id:                generateUniqueID(orgID, query),
 		closeChan:         make(chan struct{}),
 		closed:            atomic.Bool{},
 		pipeline:          pipeline,
@@ -89,6 +133,10 @@ func (t *tailer) loop() {
 	// Launch a go routine to receive streams sent with t.send
 	go t.receiveStreamsLoop()
 
+	dropCountsTicker := time.NewTicker(dropCountsPeriod)
+	defer dropCountsTicker.Stop()
+	var sentDropCounts tailDropCounts
+
 	for {
 		select {
 		case <-t.conn.Context().Done():
@@ -96,25 +144,53 @@ func (t *tailer) loop() {
 			return
 		case <-t.closeChan:
 			return
+		case <-dropCountsTicker.C:
+			// The counts are sent with an empty stream when they changed since the last response, as
+			// the entries of the streams might all be sampled out or rate limited.
+			if t.dropCounts() == sentDropCounts {
+				continue
+			}
+			stream = &logproto.Stream{}
 		case stream, ok = <-t.sendChan:
 			if !ok {
 				return
 			} else if stream == nil {
 				continue
 			}
+		}
 
-			// while sending new stream pop lined up dropped streams metadata for sending to querier
-			tailResponse := logproto.TailResponse{Stream: stream, DroppedStreams: t.popDroppedStreams()}
-			err = t.conn.Send(&tailResponse)
-			if err != nil {
-				// Don't log any error due to tail client closing the connection
-				if !util.IsConnCanceled(err) {
-					level.Error(util_log.WithContext(t.conn.Context(), util_log.Logger)).Log("msg", "Error writing to tail client", "err", err)
-				}
-				t.close()
-				return
+		// while sending new stream pop lined up dropped streams metadata for sending to querier
+		dropCounts := t.dropCounts()
+		tailResponse := logproto.TailResponse{
+			Stream:             stream,
+			DroppedStreams:     t.popDroppedStreams(),
+			SampledEntries:     dropCounts.sampled,
+			RateLimitedEntries: dropCounts.rateLimited,
+			DroppedEntries:     dropCounts.dropped,
+		}
+		err = t.conn.Send(&tailResponse)
+		if err != nil {
+			// Don't log any error due to tail client closing the connection
+			if !util.IsConnCanceled(err) {
+				level.Error(util_log.WithContext(t.conn.Context(), util_log.Logger)).Log("msg", "Error writing to tail client", "err", err)
 			}
+			t.close()
+			return
 		}
+		sentDropCounts = dropCounts
+	}
+}
+
+// tailDropCounts are the number of entries sampled out, rate limited and dropped since a tailer started.
+type tailDropCounts struct {
+	sampled, rateLimited, dropped uint64
+}
+
+func (t *tailer) dropCounts() tailDropCounts {
+	return tailDropCounts{
+		sampled:     t.sampledEntries.Load(),
+		rateLimited: t.rateLimitedEntries.Load(),
+		dropped:     t.droppedEntries.Load(),
 	}
 }
 
@@ -131,7 +207,13 @@ func (t *tailer) receiveStreamsLoop() {
 				return
 			}
 
-			streams := t.processStream(req.stream, req.lbs)
+			if req.result == nil {
+				req.result = &tailResult{}
+			}
+			req.result.once.Do(func() {
+				req.result.streams = t.processStream(req.stream, req.lbs)
+			})
+			streams := t.limitStreams(req.result.streams, req.owned)
 			if len(streams) == 0 {
 				continue
 			}
@@ -140,7 +222,7 @@ func (t *tailer) receiveStreamsLoop() {
 				select {
 				case t.sendChan <- s:
 				default:
-					t.dropStream(*s)
+					t.dropStream(*s, req.owned)
 				}
 			}
 		}
@@ -149,10 +231,13 @@ func (t *tailer) receiveStreamsLoop() {
 
 // send sends a stream to the tailer for processing and sending to the client.
 // It will drop the stream if the tailer is blocked or the queue is full.
-func (t *tailer) send(stream logproto.Stream, lbs labels.Labels) {
+// The result of the pipeline is shared with the other tailers the stream is sent to with the same result,
+// a nil result is only used by this tailer.
+func (t *tailer) send(stream logproto.Stream, lbs labels.Labels, result *tailResult) {
 	if t.isClosed() {
 		return
 	}
+	owned := t.owner.owns(t.orgID, stream.Labels)
 
 	// if we are already dropping streams due to blocked connection, drop new streams directly to save some effort
 	if blockedSince := t.blockedSince(); blockedSince != nil {
@@ -160,7 +245,7 @@ func (t *tailer) send(stream logproto.Stream, lbs labels.Labels) {
 			t.close()
 			return
 		}
-		t.dropStream(stream)
+		t.dropStream(stream, owned)
 		return
 	}
 
@@ -169,11 +254,13 @@ func (t *tailer) send(stream logproto.Stream, lbs labels.Labels) {
 	req := tailRequest{
 		stream: stream,
 		lbs:    lbs,
+		result: result,
+		owned:  owned,
 	}
 	select {
 	case t.queue <- req:
 	default:
-		t.dropStream(stream)
+		t.dropStream(stream, owned)
 	}
 }
 
@@ -219,6 +306,55 @@ func (t *tailer) processStream(stream logproto.Stream, lbs labels.Labels) []*log
 	return streamsResult
 }
 
+// limitStreams returns the entries of the streams sent to the client, once sampled and rate limited.
+// The streams are shared with the other tailers of the query, so they are copied when entries are removed.
+// The entries not sent are only counted for the streams owned, and the streams not owned are not sent when the
+// tailer is rate limited: their owner sends the entries within the limit.
+func (t *tailer) limitStreams(streams []*logproto.Stream, owned bool) []*logproto.Stream {
+	if t.sample == 0 && t.limiter == nil {
+		return streams
+	}
+	if t.limiter != nil && !owned {
+		return nil
+	}
+
+	result := make([]*logproto.Stream, 0, len(streams))
+	now := time.Now()
+	for _, stream := range streams {
+		entries := make([]logproto.Entry, 0, len(stream.Entries))
+		for _, e := range stream.Entries {
+			if t.sample > 0 && !sampled(stream.Labels, e, t.sample) {
+				if owned {
+					t.sampledEntries.Inc()
+				}
+				continue
+			}
+			if t.limiter != nil && !t.limiter.AllowN(now, 1) {
+				t.rateLimitedEntries.Inc()
+				continue
+			}
+			entries = append(entries, e)
+		}
+		if len(entries) > 0 {
+			result = append(result, &logproto.Stream{Labels: stream.Labels, Hash: stream.Hash, Entries: entries})
+		}
+	}
+	return result
+}
+
+// sampled returns whether an entry is part of the sample. The entries are sampled by hash, so the ingesters
+// holding the replicas of a stream send the same entries, which are deduplicated by the querier.
+func sampled(labels string, e logproto.Entry, sample float64) bool {
+	var ts [8]byte
+	binary.LittleEndian.PutUint64(ts[:], uint64(e.Timestamp.UnixNano()))
+
+	h := xxhash.New()
+	_, _ = h.WriteString(labels)
+	_, _ = h.Write(ts[:])
+	_, _ = h.WriteString(e.Line)
+	return float64(h.Sum64()) < sample*math.MaxUint64
+}
+
 // isMatching returns true if lbs matches all matchers.
 func isMatching(lbs labels.Labels, matchers []*labels.Matcher) bool {
 	for _, matcher := range matchers {
@@ -253,7 +389,9 @@ func (t *tailer) blockedSince() *time.Time {
 	return t.blockedAt
 }
 
-func (t *tailer) dropStream(stream logproto.Stream) {
+// dropStream drops a stream not sent because the querier is not receiving the responses. The entries dropped are
+// only counted when the stream is owned.
+func (t *tailer) dropStream(stream logproto.Stream, owned bool) {
 	if len(stream.Entries) == 0 {
 		return
 	}
@@ -271,6 +409,9 @@ func (t *tailer) dropStream(stream logproto.Stream) {
 		t.droppedStreams = nil
 	}
 
+	if owned {
+		t.droppedEntries.Add(uint64(len(stream.Entries)))
+	}
 	t.droppedStreams = append(t.droppedStreams, &logproto.DroppedStream{
 		From:   stream.Entries[0].Timestamp,
 		To:     stream.Entries[len(stream.Entries)-1].Timestamp,
@@ -23,7 +23,7 @@ func TestTailer_RoundTrip(t *testing.T) {
 	lbs := makeRandomLabels()
 	expr, err := syntax.ParseLogSelector(lbs.String(), true)
 	require.NoError(t, err)
-	tail, err := newTailer("org-id", expr, server, 10)
+	tail, err := newTailer("org-id", expr, server, 10, 0, 0)
 	require.NoError(t, err)
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -44,7 +44,7 @@ func TestTailer_RoundTrip(t *testing.T) {
 		tail.send(logproto.Stream{
 			Labels:  lbs.String(),
 			Entries: iterEntries,
-		}, lbs)
+		}, lbs, nil)
 
 		// sleep a bit to allow the tailer to process the stream without dropping
 		// This should take about 5 seconds to process all the streams
@@ -81,7 +81,7 @@ func TestTailer_sendRaceConditionOnSendWhileClosing(t *testing.T) {
 	for run := 0; run < runs; run++ {
 		expr, err := syntax.ParseLogSelector(stream.Labels, true)
 		require.NoError(t, err)
-		tailer, err := newTailer("org-id", expr, nil, 10)
+		tailer, err := newTailer("org-id", expr, nil, 10, 0, 0)
 		require.NoError(t, err)
 		require.NotNil(t, tailer)
 
@@ -91,7 +91,7 @@ func TestTailer_sendRaceConditionOnSendWhileClosing(t *testing.T) {
 		go assert.NotPanics(t, func() {
 			defer routines.Done()
 			time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
-			tailer.send(stream, labels.Labels{{Name: "type", Value: "test"}})
+			tailer.send(stream, labels.Labels{{Name: "type", Value: "test"}}, nil)
 		})
 
 		go assert.NotPanics(t, func() {
@@ -136,7 +136,7 @@ func Test_dropstream(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			expr, err := syntax.ParseLogSelector(`{app="foo"} |= "foo"`, true)
 			require.NoError(t, err)
-			tail, err := newTailer("foo", expr, &fakeTailServer{}, maxDroppedStreams)
+			tail, err := newTailer("foo", expr, &fakeTailServer{}, maxDroppedStreams, 0, 0)
 			require.NoError(t, err)
 
 			for i := 0; i < c.drop; i++ {
@@ -144,7 +144,7 @@ func Test_dropstream(t *testing.T) {
 					Entries: []logproto.Entry{
 						entry,
 					},
-				})
+				}, true)
 			}
 			assert.Equal(t, c.expected, len(tail.droppedStreams))
 		})
@@ -181,6 +181,9 @@ func cloneTailResponse(response logproto.TailResponse) logproto.TailResponse {
 		clone.DroppedStreams = make([]*logproto.DroppedStream, len(response.DroppedStreams))
 		copy(clone.DroppedStreams, response.DroppedStreams)
 	}
+	clone.SampledEntries = response.SampledEntries
+	clone.RateLimitedEntries = response.RateLimitedEntries
+	clone.DroppedEntries = response.DroppedEntries
 
 	return clone
 }
@@ -204,7 +207,7 @@ func (f *fakeTailServer) Reset() {
 func Test_TailerSendRace(t *testing.T) {
 	expr, err := syntax.ParseLogSelector(`{app="foo"} |= "foo"`, true)
 	require.NoError(t, err)
-	tail, err := newTailer("foo", expr, &fakeTailServer{}, 10)
+	tail, err := newTailer("foo", expr, &fakeTailServer{}, 10, 0, 0)
 	require.NoError(t, err)
 
 	var wg sync.WaitGroup
@@ -219,7 +222,7 @@ func Test_TailerSendRace(t *testing.T) {
 					{Timestamp: time.Unix(0, 2), Line: "2"},
 					{Timestamp: time.Unix(0, 3), Line: "3"},
 				},
-			}, lbs)
+			}, lbs, nil)
 			wg.Done()
 		}()
 	}
@@ -344,7 +347,7 @@ func Test_StructuredMetadata(t *testing.T) {
 			var server fakeTailServer
 			expr, err := syntax.ParseLogSelector(tc.query, true)
 			require.NoError(t, err)
-			tail, err := newTailer("foo", expr, &server, 10)
+			tail, err := newTailer("foo", expr, &server, 10, 0, 0)
 			require.NoError(t, err)
 
 			var wg sync.WaitGroup
@@ -354,7 +357,7 @@ func Test_StructuredMetadata(t *testing.T) {
 				wg.Done()
 			}()
 
-			tail.send(tc.sentStream, lbs)
+			tail.send(tc.sentStream, lbs, nil)
 
 			// Wait for the stream to be received by the server.
 			require.Eventually(t, func() bool {
@@ -370,11 +373,146 @@ func Test_StructuredMetadata(t *testing.T) {
 	}
 }
 
+func TestTailer_limitStreams(t *testing.T) {
+	expr, err := syntax.ParseLogSelector(`{app="foo"}`, true)
+	require.NoError(t, err)
+
+	stream := &logproto.Stream{Labels: `{app="foo"}`}
+	for i := 0; i < 1000; i++ {
+		stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Unix(0, int64(i)), Line: fmt.Sprintf("line %d", i)})
+	}
+	count := func(streams []*logproto.Stream) int {
+		n := 0
+		for _, s := range streams {
+			n += len(s.Entries)
+		}
+		return n
+	}
+
+	t.Run("sampling", func(t *testing.T) {
+		tail, err := newTailer("foo", expr, &fakeTailServer{}, 10, 0.1, 0)
+		require.NoError(t, err)
+		sent := count(tail.limitStreams([]*logproto.Stream{stream}, true))
+		require.InDelta(t, 100, sent, 50)
+		require.Equal(t, uint64(1000-sent), tail.sampledEntries.Load())
+		// The shared stream is not modified.
+		require.Len(t, stream.Entries, 1000)
+
+		// The replicas of the stream sample the same entries, and only its owner counts the entries sampled out.
+		replica, err := newTailer("foo", expr, &fakeTailServer{}, 10, 0.1, 0)
+		require.NoError(t, err)
+		require.Equal(t, tail.limitStreams([]*logproto.Stream{stream}, true), replica.limitStreams([]*logproto.Stream{stream}, false))
+		require.Zero(t, replica.sampledEntries.Load())
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		tail, err := newTailer("foo", expr, &fakeTailServer{}, 10, 0, 100)
+		require.NoError(t, err)
+		require.Equal(t, 100, count(tail.limitStreams([]*logproto.Stream{stream}, true)))
+		require.Equal(t, uint64(900), tail.rateLimitedEntries.Load())
+
+		// Only the owner of the stream sends it, so the entries it rate limits are not sent by the other replicas.
+		replica, err := newTailer("foo", expr, &fakeTailServer{}, 10, 0, 100)
+		require.NoError(t, err)
+		require.Empty(t, replica.limitStreams([]*logproto.Stream{stream}, false))
+		require.Zero(t, replica.rateLimitedEntries.Load())
+	})
+
+	t.Run("no limits", func(t *testing.T) {
+		tail, err := newTailer("foo", expr, &fakeTailServer{}, 10, 1, 0)
+		require.NoError(t, err)
+		require.Equal(t, []*logproto.Stream{stream}, tail.limitStreams([]*logproto.Stream{stream}, true))
+		require.Equal(t, []*logproto.Stream{stream}, tail.limitStreams([]*logproto.Stream{stream}, false))
+	})
+}
+
+func TestTailer_SendsDropCountsWithoutEntries(t *testing.T) {
+	lbs := labels.FromStrings("app", "foo")
+	expr, err := syntax.ParseLogSelector(`{app="foo"}`, true)
+	require.NoError(t, err)
+
+	var server fakeTailServer
+	tail, err := newTailer("foo", expr, &server, 10, 0, 1)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tail.loop()
+	}()
+
+	// The first entry is sent, the second one is rate limited.
+	tail.send(logproto.Stream{Labels: lbs.String(), Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "first"}}}, lbs, nil)
+	require.Eventually(t, func() bool {
+		return len(server.GetResponses()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	tail.send(logproto.Stream{Labels: lbs.String(), Entries: []logproto.Entry{{Timestamp: time.Unix(0, 2), Line: "second"}}}, lbs, nil)
+
+	// The rate limited entry is reported without waiting for another entry to be sent.
+	require.Eventually(t, func() bool {
+		return len(server.GetResponses()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	tail.close()
+	wg.Wait()
+
+	responses := server.GetResponses()
+	require.Len(t, responses, 2)
+	require.Zero(t, responses[0].RateLimitedEntries)
+	require.Equal(t, uint64(1), responses[1].RateLimitedEntries)
+	require.Empty(t, responses[1].Stream.Entries)
+}
+
+func TestTailer_SharedResult(t *testing.T) {
+	lbs := labels.FromStrings("app", "foo")
+	expr, err := syntax.ParseLogSelector(`{app="foo"} |= "error"`, true)
+	require.NoError(t, err)
+
+	var servers [2]fakeTailServer
+	var wg sync.WaitGroup
+	tailers := make([]*tailer, len(servers))
+	for i := range servers {
+		tailers[i], err = newTailer("foo", expr, &servers[i], 10, 0, 0)
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(tail *tailer) {
+			defer wg.Done()
+			tail.loop()
+		}(tailers[i])
+	}
+
+	result := &tailResult{}
+	stream := logproto.Stream{Labels: lbs.String(), Entries: []logproto.Entry{
+		{Timestamp: time.Unix(0, 1), Line: "error"},
+		{Timestamp: time.Unix(0, 2), Line: "info"},
+	}}
+	for _, tail := range tailers {
+		tail.send(stream, lbs, result)
+	}
+
+	for i := range servers {
+		require.Eventually(t, func() bool {
+			return len(servers[i].GetResponses()) > 0
+		}, 5*time.Second, 10*time.Millisecond)
+		responses := servers[i].GetResponses()
+		require.Len(t, responses, 1)
+		require.Equal(t, []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "error"}}, responses[0].Stream.Entries)
+	}
+	for _, tail := range tailers {
+		tail.close()
+	}
+	wg.Wait()
+
+	// The pipeline was evaluated once, the tailers sent the same stream.
+	require.Len(t, result.streams, 1)
+	require.Same(t, result.streams[0], servers[0].responses[0].Stream)
+	require.Same(t, result.streams[0], servers[1].responses[0].Stream)
+}
+
 func Benchmark_isClosed(t *testing.B) {
 	var server fakeTailServer
 	expr, err := syntax.ParseLogSelector(`{app="foo"}`, true)
 	require.NoError(t, err)
-	tail, err := newTailer("foo", expr, &server, 0)
+	tail, err := newTailer("foo", expr, &server, 0, 0, 0)
 	require.NoError(t, err)
 
 	require.Equal(t, false, tail.isClosed())
@@ -29,6 +29,7 @@ type WALConfig struct {
 	CheckpointDuration  time.Duration    `yaml:"checkpoint_duration"`
 	FlushOnShutdown     bool             `yaml:"flush_on_shutdown"`
 	ReplayMemoryCeiling flagext.ByteSize `yaml:"replay_memory_ceiling"`
+	LazyReplay          bool             `yaml:"lazy_replay"`
 }
 
 func (cfg *WALConfig) Validate() error {
@@ -48,6 +49,7 @@ func (cfg *WALConfig) RegisterFlags(f *flag.FlagSet) {
 	// Need to set default here
 	cfg.ReplayMemoryCeiling = flagext.ByteSize(defaultCeiling)
 	f.Var(&cfg.ReplayMemoryCeiling, "ingester.wal-replay-memory-ceiling", "Maximum memory size the WAL may use during replay. After hitting this, it will flush data to storage before continuing. A unit suffix (KB, MB, GB) may be applied.")
+	f.BoolVar(&cfg.LazyReplay, "ingester.wal-lazy-replay", false, "Experimental: Replay the WAL in the background instead of before the ingester becomes ready. Until the replay finishes, queries also read the data not replayed yet from the WAL, which is kept in memory until replayed, the replayed entries are exempted from the stream limits and the progress of the replay is shown on the /ingester/wal_replay page. If the ingester crashes during the replay, entries pushed during it may be skipped by the next replay.")
 }
 
 // WAL interface allows us to have a no-op WAL when the WAL is disabled.
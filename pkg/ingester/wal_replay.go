@@ -0,0 +1,476 @@
+package ingester
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+
+	"github.com/grafana/loki/v3/pkg/chunkenc"
+	"github.com/grafana/loki/v3/pkg/ingester/wal"
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/util"
+)
+
+const (
+	walReplayPhaseDisabled   = "disabled"
+	walReplayPhasePending    = "pending"
+	walReplayPhaseCheckpoint = "checkpoint"
+	walReplayPhaseSegments   = "segments"
+	walReplayPhaseDone       = "done"
+)
+
+// WALReplayStatus is the progress of the WAL replay of an ingester.
+type WALReplayStatus struct {
+	Lazy           bool      `json:"lazy"`
+	Phase          string    `json:"phase"`
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	FirstSegment   int       `json:"first_segment"`
+	LastSegment    int       `json:"last_segment"`
+	CurrentSegment int       `json:"current_segment"`
+	Errors         bool      `json:"errors"`
+}
+
+// walReplayProgress tracks the WAL replay for the status page and the read-through of lazy replays.
+type walReplayProgress struct {
+	mtx    sync.RWMutex
+	status WALReplayStatus
+}
+
+func newWALReplayProgress(cfg WALConfig) *walReplayProgress {
+	status := WALReplayStatus{Phase: walReplayPhaseDisabled, FirstSegment: -1, LastSegment: -1, CurrentSegment: -1}
+	if cfg.Enabled {
+		status.Lazy = cfg.LazyReplay
+		status.Phase = walReplayPhasePending
+	}
+	return &walReplayProgress{status: status}
+}
+
+func (p *walReplayProgress) start(firstSegment, lastSegment int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.status.StartedAt = time.Now()
+	p.status.Phase = walReplayPhaseCheckpoint
+	p.status.FirstSegment, p.status.LastSegment = firstSegment, lastSegment
+}
+
+func (p *walReplayProgress) setPhase(phase string, errors bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.status.Phase = phase
+	p.status.Errors = p.status.Errors || errors
+	if phase == walReplayPhaseDone {
+		p.status.FinishedAt = time.Now()
+	}
+}
+
+func (p *walReplayProgress) setSegment(segment int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.status.CurrentSegment = segment
+}
+
+func (p *walReplayProgress) get() WALReplayStatus {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.status
+}
+
+// active returns whether a WAL replay is in progress.
+func (p *walReplayProgress) active() bool {
+	phase := p.get().Phase
+	return phase == walReplayPhaseCheckpoint || phase == walReplayPhaseSegments
+}
+
+// segmentProgressReader reports the WAL segment being read to the replay progress.
+type segmentProgressReader struct {
+	*wlog.Reader
+	ctx      context.Context
+	progress *walReplayProgress
+	segment  int
+}
+
+func newSegmentProgressReader(ctx context.Context, r *wlog.Reader, progress *walReplayProgress) *segmentProgressReader {
+	return &segmentProgressReader{Reader: r, ctx: ctx, progress: progress, segment: -1}
+}
+
+func (r *segmentProgressReader) Next() bool {
+	// Stop reading when the ingester stops during a lazy replay.
+	if r.ctx.Err() != nil {
+		return false
+	}
+	if !r.Reader.Next() {
+		return false
+	}
+	if segment := r.Reader.Segment(); segment != r.segment {
+		r.segment = segment
+		r.progress.setSegment(segment)
+	}
+	return true
+}
+
+// startLazyWALReplay replays the WAL in the background. The segments created before the ingester started
+// are replayed, while the pushes are logged to the new segment. The WAL checkpoints only start once the
+// replay has finished, so that a checkpoint never misses data not replayed yet.
+func (i *Ingester) startLazyWALReplay() error {
+	first, last, err := wlog.Segments(i.cfg.WAL.Dir)
+	if err != nil {
+		return err
+	}
+	// The last segment was created by the WAL of this ingester.
+	last--
+
+	recoverer := newIngesterRecoverer(i)
+	i.walReadThrough = &walReadThrough{
+		cfg:          &i.cfg,
+		progress:     i.walReplay,
+		recoverer:    recoverer,
+		firstSegment: first,
+		lastSegment:  last,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.walReplayCancel = cancel
+	i.walReplayDone = make(chan struct{})
+	go func() {
+		defer close(i.walReplayDone)
+		if err := i.replayWAL(ctx, recoverer, first, last); err != nil {
+			level.Error(i.logger).Log("msg", "lazy WAL replay failed", "err", err)
+		}
+		if ctx.Err() != nil {
+			// The replay was interrupted, the remaining segments are replayed on the next start.
+			return
+		}
+		i.wal.Start()
+	}()
+	return nil
+}
+
+// stopLazyWALReplay interrupts a lazy WAL replay still in progress and waits for it to stop.
+func (i *Ingester) stopLazyWALReplay() {
+	if i.walReplayCancel == nil {
+		return
+	}
+	i.walReplayCancel()
+	<-i.walReplayDone
+}
+
+var walReplayStatusTemplate = template.Must(template.New("wal_replay").Parse(`<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="UTF-8">
+		<title>Ingester WAL Replay</title>
+	</head>
+	<body>
+		<h1>Ingester WAL Replay</h1>
+		<table>
+			<tr><td>Phase</td><td>{{ .Phase }}</td></tr>
+			<tr><td>Lazy</td><td>{{ .Lazy }}</td></tr>
+			{{ if not .StartedAt.IsZero }}<tr><td>Started at</td><td>{{ .StartedAt.Format "2006-01-02T15:04:05Z07:00" }}</td></tr>{{ end }}
+			{{ if not .FinishedAt.IsZero }}<tr><td>Finished at</td><td>{{ .FinishedAt.Format "2006-01-02T15:04:05Z07:00" }}</td></tr>{{ end }}
+			<tr><td>Segments</td><td>{{ .FirstSegment }} to {{ .LastSegment }}</td></tr>
+			<tr><td>Current segment</td><td>{{ .CurrentSegment }}</td></tr>
+			<tr><td>Errors</td><td>{{ .Errors }}</td></tr>
+		</table>
+	</body>
+</html>`))
+
+// WALReplayStatusHandler shows the progress of the WAL replay, as JSON if requested by the Accept header.
+func (i *Ingester) WALReplayStatusHandler(w http.ResponseWriter, r *http.Request) {
+	util.RenderHTTPResponse(w, i.walReplay.get(), walReplayStatusTemplate, r)
+}
+
+// walReadThrough serves the queries for the data of a lazy WAL replay not replayed yet,
+// by reading the WAL checkpoint and segments being replayed. The checkpoint and each segment
+// are only read once, their data is kept until the replay is past them.
+type walReadThrough struct {
+	cfg       *Config
+	progress  *walReplayProgress
+	recoverer *ingesterRecoverer
+
+	firstSegment, lastSegment int
+
+	mtx sync.Mutex
+	// cache holds the data read from the checkpoint, at walCheckpointKey, and from the segments.
+	cache map[int]*walCache
+	// refs holds the labels of the streams of the series records read from the segments, by tenant.
+	refs map[string]map[chunks.HeadSeriesRef]labels.Labels
+}
+
+// walCheckpointKey is the key of the checkpoint data in the cache of the read-through.
+const walCheckpointKey = -1
+
+// walCache is the data read from the WAL checkpoint or from a segment.
+type walCache struct {
+	once sync.Once
+	err  error
+	// series holds the WAL data of the streams by tenant and by reference.
+	series map[string]map[chunks.HeadSeriesRef]*walSeries
+}
+
+// walSeries is the WAL data of a stream.
+type walSeries struct {
+	// labels are nil for the series read from segments whose series record is in another segment.
+	labels labels.Labels
+	chunks []*chunkenc.MemChunk
+}
+
+// active returns whether queries need to read through the WAL.
+func (rt *walReadThrough) active() bool {
+	return rt != nil && rt.progress.active()
+}
+
+// EntryIterator returns an iterator over the WAL entries of the streams of a tenant matching a log query.
+func (rt *walReadThrough) EntryIterator(ctx context.Context, userID string, req logql.SelectLogParams, matchers []*labels.Matcher, shard *logql.Shard, pipeline log.Pipeline) (iter.EntryIterator, error) {
+	series, err := rt.read(userID, matchers, shard, req.Start, req.End)
+	if err != nil {
+		return nil, err
+	}
+	var iters []iter.EntryIterator
+	for _, s := range series {
+		streamPipeline := pipeline.ForStream(s.labels)
+		for _, c := range s.chunks {
+			it, err := c.Iterator(ctx, req.Start, req.End, req.Direction, streamPipeline)
+			if err != nil {
+				return nil, err
+			}
+			iters = append(iters, it)
+		}
+	}
+	// Entries of the checkpoint can also be in the segments.
+	return iter.NewMergeEntryIterator(ctx, iters, req.Direction), nil
+}
+
+// SampleIterator returns an iterator over the samples of the WAL entries of the streams of a tenant matching a metric query.
+func (rt *walReadThrough) SampleIterator(ctx context.Context, userID string, req logql.SelectSampleParams, matchers []*labels.Matcher, shard *logql.Shard, extractor log.SampleExtractor) (iter.SampleIterator, error) {
+	series, err := rt.read(userID, matchers, shard, req.Start, req.End)
+	if err != nil {
+		return nil, err
+	}
+	var iters []iter.SampleIterator
+	for _, s := range series {
+		streamExtractor := extractor.ForStream(s.labels)
+		for _, c := range s.chunks {
+			iters = append(iters, c.SampleIterator(ctx, req.Start, req.End, streamExtractor))
+		}
+	}
+	return iter.NewMergeSampleIterator(ctx, iters), nil
+}
+
+// read returns the WAL data of the streams of a tenant matching the matchers and the shard in the time range.
+// The checkpoint is skipped once replayed, and so are the segments before the one being replayed.
+func (rt *walReadThrough) read(userID string, matchers []*labels.Matcher, shard *logql.Shard, from, through time.Time) ([]*walSeries, error) {
+	matches := func(ls labels.Labels) bool {
+		for _, m := range matchers {
+			if !m.Matches(ls.Get(m.Name)) {
+				return false
+			}
+		}
+		return shard == nil || shard.Match(model.Fingerprint(ls.Hash()))
+	}
+
+	status := rt.progress.get()
+	// The entries of the segment before the one being read may still be replayed by the workers.
+	first := max(rt.firstSegment, status.CurrentSegment-1)
+	rt.evict(status.Phase == walReplayPhaseCheckpoint, first)
+
+	var caches []*walCache
+	if status.Phase == walReplayPhaseCheckpoint {
+		c, err := rt.load(walCheckpointKey, rt.readCheckpoint)
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, c)
+	}
+	for segment := max(first, 0); segment <= rt.lastSegment; segment++ {
+		c, err := rt.load(segment, func() (map[string]map[chunks.HeadSeriesRef]*walSeries, error) {
+			return rt.readSegment(segment)
+		})
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, c)
+	}
+
+	var series []*walSeries
+	for _, c := range caches {
+		for ref, s := range c.series[userID] {
+			ls := s.labels
+			if ls == nil {
+				var ok bool
+				if ls, ok = rt.seriesLabels(userID, ref); !ok {
+					continue
+				}
+			}
+			if !matches(ls) {
+				continue
+			}
+			ws := &walSeries{labels: ls}
+			for _, c := range s.chunks {
+				if mint, maxt := c.Bounds(); !through.Before(mint) && !maxt.Before(from) {
+					ws.chunks = append(ws.chunks, c)
+				}
+			}
+			if len(ws.chunks) > 0 {
+				series = append(series, ws)
+			}
+		}
+	}
+	return series, nil
+}
+
+// load returns the data read from the checkpoint or the segment with the given key, reading it on first use.
+func (rt *walReadThrough) load(key int, read func() (map[string]map[chunks.HeadSeriesRef]*walSeries, error)) (*walCache, error) {
+	rt.mtx.Lock()
+	if rt.cache == nil {
+		rt.cache = map[int]*walCache{}
+	}
+	c, ok := rt.cache[key]
+	if !ok {
+		c = &walCache{}
+		rt.cache[key] = c
+	}
+	rt.mtx.Unlock()
+
+	c.once.Do(func() {
+		c.series, c.err = read()
+	})
+	if c.err != nil {
+		// Read it again on the next query.
+		rt.mtx.Lock()
+		if rt.cache[key] == c {
+			delete(rt.cache, key)
+		}
+		rt.mtx.Unlock()
+		return nil, c.err
+	}
+	return c, nil
+}
+
+// evict drops the data of the checkpoint, unless it is still replayed, and of the segments before first.
+func (rt *walReadThrough) evict(checkpoint bool, first int) {
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+	for key := range rt.cache {
+		if key == walCheckpointKey && checkpoint {
+			continue
+		}
+		if key < first {
+			delete(rt.cache, key)
+		}
+	}
+}
+
+// seriesLabels returns the labels of a stream of the segments from its series record.
+func (rt *walReadThrough) seriesLabels(userID string, ref chunks.HeadSeriesRef) (labels.Labels, bool) {
+	rt.mtx.Lock()
+	ls, ok := rt.refs[userID][ref]
+	rt.mtx.Unlock()
+	if ok {
+		return ls, true
+	}
+	// The series record is in a segment already replayed.
+	return rt.recoverer.streamLabels(userID, ref)
+}
+
+func (rt *walReadThrough) readCheckpoint() (map[string]map[chunks.HeadSeriesRef]*walSeries, error) {
+	reader, closer, err := newCheckpointReader(rt.cfg.WAL.Dir, rt.recoverer.logger)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	series := map[string]map[chunks.HeadSeriesRef]*walSeries{}
+	for reader.Next() {
+		s := &Series{}
+		if err := decodeCheckpointRecord(reader.Record(), s); err != nil {
+			return nil, err
+		}
+		descs, err := fromWireChunks(rt.cfg, chunkenc.UnorderedWithStructuredMetadataHeadBlockFmt, s.Chunks)
+		if err != nil {
+			return nil, err
+		}
+		ws := &walSeries{labels: logproto.FromLabelAdaptersToLabels(s.Labels)}
+		for _, d := range descs {
+			ws.chunks = append(ws.chunks, d.chunk)
+		}
+		if series[s.UserID] == nil {
+			series[s.UserID] = map[chunks.HeadSeriesRef]*walSeries{}
+		}
+		series[s.UserID][chunks.HeadSeriesRef(s.Fingerprint)] = ws
+	}
+	return series, reader.Err()
+}
+
+func (rt *walReadThrough) readSegment(segment int) (map[string]map[chunks.HeadSeriesRef]*walSeries, error) {
+	segments, err := wlog.NewSegmentsRangeReader(wlog.SegmentRange{Dir: rt.cfg.WAL.Dir, First: segment, Last: segment})
+	if err != nil {
+		return nil, err
+	}
+	defer segments.Close()
+	reader := wlog.NewReader(segments)
+
+	series := map[string]map[chunks.HeadSeriesRef]*walSeries{}
+	rec := recordPool.GetRecord()
+	defer recordPool.PutRecord(rec)
+	for reader.Next() {
+		rec.Reset()
+		if err := wal.DecodeRecord(reader.Record(), rec); err != nil {
+			return nil, err
+		}
+		if len(rec.Series) > 0 {
+			rt.mtx.Lock()
+			if rt.refs == nil {
+				rt.refs = map[string]map[chunks.HeadSeriesRef]labels.Labels{}
+			}
+			if rt.refs[rec.UserID] == nil {
+				rt.refs[rec.UserID] = map[chunks.HeadSeriesRef]labels.Labels{}
+			}
+			for _, s := range rec.Series {
+				rt.refs[rec.UserID][s.Ref] = s.Labels
+			}
+			rt.mtx.Unlock()
+		}
+		for _, entries := range rec.RefEntries {
+			if series[rec.UserID] == nil {
+				series[rec.UserID] = map[chunks.HeadSeriesRef]*walSeries{}
+			}
+			ws, ok := series[rec.UserID][entries.Ref]
+			if !ok {
+				ws = &walSeries{chunks: []*chunkenc.MemChunk{
+					chunkenc.NewMemChunk(chunkenc.ChunkFormatV4, rt.cfg.parsedEncoding, chunkenc.UnorderedWithStructuredMetadataHeadBlockFmt, rt.cfg.BlockSize, rt.cfg.TargetChunkSize),
+				}}
+				series[rec.UserID][entries.Ref] = ws
+			}
+			for i := range entries.Entries {
+				if _, err := ws.chunks[0].Append(&entries.Entries[i]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	// Compress the entries of the head blocks, the chunks are only read from now on.
+	for _, userSeries := range series {
+		for _, ws := range userSeries {
+			if err := ws.chunks[0].Close(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return series, nil
+}
@@ -0,0 +1,217 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gokit_log "github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
+	"github.com/grafana/loki/v3/pkg/ingester/client"
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/runtime"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func pushTestStreams(ctx context.Context, t *testing.T, i *Ingester, start time.Time, steps int) {
+	req := logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{
+				Labels: `{foo="bar",bar="baz1"}`,
+			},
+			{
+				Labels: `{foo="bar",bar="baz2"}`,
+			},
+		},
+	}
+	for j := 0; j < steps; j++ {
+		for k := range req.Streams {
+			req.Streams[k].Entries = append(req.Streams[k].Entries, logproto.Entry{
+				Timestamp: start.Add(time.Duration(j) * time.Second),
+				Line:      fmt.Sprintf("line %d", j),
+			})
+		}
+	}
+	_, err := i.Push(ctx, &req)
+	require.NoError(t, err)
+}
+
+func waitWALReplayDone(t *testing.T, i *Ingester) {
+	require.Eventually(t, func() bool {
+		return i.walReplay.get().Phase == walReplayPhaseDone
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestIngesterLazyWALReplay(t *testing.T) {
+	walDir := t.TempDir()
+	ingesterConfig := defaultIngesterTestConfigWithWAL(t, walDir)
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	newStore := func() *mockStore {
+		return &mockStore{
+			chunks: map[string][]chunk.Chunk{},
+		}
+	}
+	readRingMock := mockReadRingWithOneActiveIngester()
+
+	i, err := New(ingesterConfig, client.Config{}, newStore(), limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokit_log.NewNopLogger(), nil, readRingMock, nil)
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	start := time.Now()
+	pushTestStreams(ctx, t, i, start, 10)
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	// restart the ingester with a lazy replay
+	ingesterConfig.WAL.LazyReplay = true
+	i, err = New(ingesterConfig, client.Config{}, newStore(), limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokit_log.NewNopLogger(), nil, readRingMock, nil)
+	require.NoError(t, err)
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+
+	// push more recent entries while the WAL is replayed
+	pushTestStreams(ctx, t, i, start.Add(10*time.Second), 10)
+	waitWALReplayDone(t, i)
+
+	status := i.walReplay.get()
+	require.True(t, status.Lazy)
+	require.False(t, status.Errors)
+	ensureIngesterData(ctx, t, start, start.Add(20*time.Second), i)
+
+	// the checkpoints start once the replay has finished
+	expectCheckpoint(t, walDir, true, ingesterConfig.WAL.CheckpointDuration*5)
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	// restart the ingester, recovering from checkpoint+wal segments
+	i, err = New(ingesterConfig, client.Config{}, newStore(), limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokit_log.NewNopLogger(), nil, readRingMock, nil)
+	require.NoError(t, err)
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+	waitWALReplayDone(t, i)
+
+	ensureIngesterData(ctx, t, start, start.Add(20*time.Second), i)
+}
+
+func TestWALReadThrough(t *testing.T) {
+	walDir := t.TempDir()
+	ingesterConfig := defaultIngesterTestConfigWithWAL(t, walDir)
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	store := &mockStore{
+		chunks: map[string][]chunk.Chunk{},
+	}
+
+	i, err := New(ingesterConfig, client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil, writefailures.Cfg{}, constants.Loki, gokit_log.NewNopLogger(), nil, mockReadRingWithOneActiveIngester(), nil)
+	require.NoError(t, err)
+	require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	start := time.Now()
+	pushTestStreams(ctx, t, i, start, 10)
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	first, last, err := wlog.Segments(walDir)
+	require.NoError(t, err)
+
+	ingesterConfig.WAL.LazyReplay = true
+	progress := newWALReplayProgress(ingesterConfig.WAL)
+	rt := &walReadThrough{
+		cfg:          &ingesterConfig,
+		progress:     progress,
+		recoverer:    newIngesterRecoverer(i),
+		firstSegment: first,
+		lastSegment:  last,
+	}
+	require.False(t, rt.active())
+	progress.start(first, last)
+	require.True(t, rt.active())
+
+	expr, err := syntax.ParseLogSelector(`{foo="bar", bar="baz1"}`, true)
+	require.NoError(t, err)
+	it, err := rt.EntryIterator(ctx, "test", logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{
+		Start:     start,
+		End:       start.Add(5 * time.Second),
+		Direction: logproto.FORWARD,
+	}}, expr.Matchers(), nil, log.NewNoopPipeline())
+	require.NoError(t, err)
+	streams, _, err := iter.ReadBatch(it, 100)
+	require.NoError(t, err)
+	require.NoError(t, it.Close())
+	require.Len(t, streams.Streams, 1)
+	require.Equal(t, `{bar="baz1", foo="bar"}`, streams.Streams[0].Labels)
+	require.Len(t, streams.Streams[0].Entries, 5)
+
+	// other tenants have no data
+	it, err = rt.EntryIterator(ctx, "other", logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{
+		Start:     start,
+		End:       start.Add(10 * time.Second),
+		Direction: logproto.FORWARD,
+	}}, expr.Matchers(), nil, log.NewNoopPipeline())
+	require.NoError(t, err)
+	require.False(t, it.Next())
+	require.NoError(t, it.Close())
+
+	// the checkpoint and the segments are only read once
+	require.Len(t, rt.cache, last-first+2)
+	cached := rt.cache[first]
+	_, err = rt.read("test", expr.Matchers(), nil, start, start.Add(10*time.Second))
+	require.NoError(t, err)
+	require.Same(t, cached, rt.cache[first])
+
+	// and dropped once replayed
+	progress.setPhase(walReplayPhaseSegments, false)
+	progress.setSegment(last + 1)
+	series, err := rt.read("test", expr.Matchers(), nil, start, start.Add(10*time.Second))
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	require.Len(t, rt.cache, 1)
+	require.Contains(t, rt.cache, last)
+
+	progress.setPhase(walReplayPhaseDone, false)
+	require.False(t, rt.active())
+}
+
+func TestWALReplayStatusHandler(t *testing.T) {
+	i := &Ingester{walReplay: newWALReplayProgress(WALConfig{Enabled: true, LazyReplay: true})}
+	i.walReplay.start(3, 7)
+	i.walReplay.setPhase(walReplayPhaseSegments, false)
+	i.walReplay.setSegment(5)
+
+	req := httptest.NewRequest(http.MethodGet, "/ingester/wal_replay", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	i.WALReplayStatusHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var status WALReplayStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	require.True(t, status.Lazy)
+	require.Equal(t, walReplayPhaseSegments, status.Phase)
+	require.Equal(t, 3, status.FirstSegment)
+	require.Equal(t, 7, status.LastSegment)
+	require.Equal(t, 5, status.CurrentSegment)
+
+	w = httptest.NewRecorder()
+	i.WALReplayStatusHandler(w, httptest.NewRequest(http.MethodGet, "/ingester/wal_replay", nil))
+	require.Contains(t, w.Body.String(), "<td>segments</td>")
+}
@@ -2,14 +2,19 @@ package iter
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"math"
 	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/model/labels"
+
 	"github.com/grafana/loki/v3/pkg/logqlmodel/metadata"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/util"
 	"github.com/grafana/loki/v3/pkg/util/loser"
@@ -508,6 +513,143 @@ func (i *timeRangedIterator) Next() bool {
 	return ok
 }
 
+type contextStream struct {
+	pipeline log.StreamPipeline
+	// leading holds the latest entries not returned yet, up to the number of
+	// entries to return before the next matching entry.
+	leading []entryWithLabels
+	// trailing is the number of entries still to return after the last
+	// matching entry.
+	trailing int
+}
+
+type contextIterator struct {
+	iter     EntryIterator
+	pipeline log.Pipeline
+
+	leading, trailing int
+	streams           map[uint64]*contextStream
+
+	pending []entryWithLabels
+	cur     entryWithLabels
+	err     error
+}
+
+// NewContextIterator returns an iterator over the entries of it matching the
+// pipeline, along with up to before and after entries of the same stream
+// surrounding each of them in time. it must iterate over unprocessed entries,
+// which are returned as they are when they surround a matching entry.
+func NewContextIterator(it EntryIterator, pipeline log.Pipeline, direction logproto.Direction, before, after int) EntryIterator {
+	leading, trailing := before, after
+	if direction == logproto.BACKWARD {
+		leading, trailing = after, before
+	}
+	return &contextIterator{
+		iter:     it,
+		pipeline: pipeline,
+		leading:  leading,
+		trailing: trailing,
+		streams:  map[uint64]*contextStream{},
+	}
+}
+
+func (i *contextIterator) Next() bool {
+	for len(i.pending) == 0 {
+		if !i.iter.Next() {
+			return false
+		}
+		if err := i.process(); err != nil {
+			i.err = err
+			return false
+		}
+	}
+	i.cur, i.pending = i.pending[0], i.pending[1:]
+	return true
+}
+
+func (i *contextIterator) process() error {
+	entry := i.iter.At()
+	s, err := i.stream(entry)
+	if err != nil {
+		return err
+	}
+
+	line, lbs, matches := s.pipeline.Process(entry.Timestamp.UnixNano(), []byte(entry.Line), logproto.FromLabelAdaptersToLabels(entry.StructuredMetadata)...)
+	switch {
+	case matches:
+		i.pending = append(i.pending, s.leading...)
+		s.leading = s.leading[:0]
+		i.pending = append(i.pending, entryWithLabels{
+			Entry: logproto.Entry{
+				Timestamp:          entry.Timestamp,
+				Line:               string(line),
+				StructuredMetadata: logproto.FromLabelsToLabelAdapters(lbs.StructuredMetadata()),
+				Parsed:             logproto.FromLabelsToLabelAdapters(lbs.Parsed()),
+			},
+			labels:     lbs.String(),
+			streamHash: i.iter.StreamHash(),
+		})
+		s.trailing = i.trailing
+	case s.trailing > 0:
+		i.pending = append(i.pending, i.unprocessed(entry))
+		s.trailing--
+	case i.leading > 0:
+		if len(s.leading) == i.leading {
+			copy(s.leading, s.leading[1:])
+			s.leading = s.leading[:len(s.leading)-1]
+		}
+		s.leading = append(s.leading, i.unprocessed(entry))
+	}
+	return nil
+}
+
+func (i *contextIterator) unprocessed(entry logproto.Entry) entryWithLabels {
+	return entryWithLabels{
+		Entry:      entry,
+		labels:     i.iter.Labels(),
+		streamHash: i.iter.StreamHash(),
+	}
+}
+
+// stream returns the state of the stream of the current entry.
+func (i *contextIterator) stream(entry logproto.Entry) (*contextStream, error) {
+	if s, ok := i.streams[i.iter.StreamHash()]; ok {
+		return s, nil
+	}
+
+	// The labels of unprocessed entries include their structured metadata.
+	lbs, err := syntax.ParseLabels(i.iter.Labels())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse series labels: %w", err)
+	}
+	builder := labels.NewBuilder(lbs)
+	for _, l := range entry.StructuredMetadata {
+		builder.Del(l.Name)
+	}
+
+	s := &contextStream{
+		pipeline: i.pipeline.ForStream(builder.Labels()),
+		leading:  make([]entryWithLabels, 0, i.leading),
+	}
+	i.streams[i.iter.StreamHash()] = s
+	return s, nil
+}
+
+func (i *contextIterator) At() logproto.Entry { return i.cur.Entry }
+
+func (i *contextIterator) Labels() string { return i.cur.labels }
+
+func (i *contextIterator) StreamHash() uint64 { return i.cur.streamHash }
+
+func (i *contextIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.iter.Err()
+}
+
+func (i *contextIterator) Close() error { return i.iter.Close() }
+
 type entryWithLabels struct {
 	logproto.Entry
 	labels     string
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"math/rand"
+	"slices"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 )
 
@@ -681,6 +683,82 @@ func (i *CloseTestingIterator) Close() error {
 	return nil
 }
 
+func TestContextIterator(t *testing.T) {
+	stream := func(lbs string, start int64, lines ...string) logproto.Stream {
+		ls, err := syntax.ParseLabels(lbs)
+		require.NoError(t, err)
+		s := logproto.Stream{Labels: lbs, Hash: ls.Hash()}
+		for i, line := range lines {
+			s.Entries = append(s.Entries, logproto.Entry{Timestamp: time.Unix(start+int64(i), 0), Line: line})
+		}
+		return s
+	}
+	streams := []logproto.Stream{
+		stream(`{app="foo"}`, 0, "a", "b", "error 1", "c", "d", "e", "f", "error 2", "g"),
+		stream(`{app="bar"}`, 3, "x", "error 3", "y"),
+	}
+
+	expr, err := syntax.ParseLogSelector(`{app=~"foo|bar"} |= "error"`, true)
+	require.NoError(t, err)
+	pipeline, err := expr.Pipeline()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name          string
+		direction     logproto.Direction
+		before, after int
+		expected      map[string][]string
+	}{
+		{
+			name:      "forward",
+			direction: logproto.FORWARD,
+			before:    1,
+			after:     2,
+			expected: map[string][]string{
+				`{app="foo"}`: {"b", "error 1", "c", "d", "f", "error 2", "g"},
+				`{app="bar"}`: {"x", "error 3", "y"},
+			},
+		},
+		{
+			name:      "backward",
+			direction: logproto.BACKWARD,
+			before:    1,
+			expected: map[string][]string{
+				`{app="foo"}`: {"error 2", "f", "error 1", "b"},
+				`{app="bar"}`: {"error 3", "x"},
+			},
+		},
+		{
+			name:      "no context",
+			direction: logproto.FORWARD,
+			expected: map[string][]string{
+				`{app="foo"}`: {"error 1", "error 2"},
+				`{app="bar"}`: {"error 3"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			input := make([]logproto.Stream, 0, len(streams))
+			for _, s := range streams {
+				if tc.direction == logproto.BACKWARD {
+					s.Entries = slices.Clone(s.Entries)
+					slices.Reverse(s.Entries)
+				}
+				input = append(input, s)
+			}
+
+			it := NewContextIterator(NewStreamsIterator(input, tc.direction), pipeline, tc.direction, tc.before, tc.after)
+			actual := map[string][]string{}
+			for it.Next() {
+				actual[it.Labels()] = append(actual[it.Labels()], it.At().Line)
+			}
+			require.NoError(t, it.Err())
+			require.NoError(t, it.Close())
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func TestNonOverlappingClose(t *testing.T) {
 	a, b := &CloseTestingIterator{}, &CloseTestingIterator{}
 	itr := NewNonOverlappingIterator([]EntryIterator{a, b})
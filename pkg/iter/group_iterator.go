@@ -0,0 +1,164 @@
+package iter
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// maxGroupKeysStreams bounds the number of parsed stream labels cached to
+// compute group keys. Streams labels include parsed labels, so a query can
+// return as many distinct streams as lines.
+const maxGroupKeysStreams = 4096
+
+// groupKeys computes the key of the group an entry belongs to from the values
+// of a set of labels, looked up in the parsed labels and structured metadata of
+// the entry first and in its stream labels otherwise. Without labels, each
+// stream is its own group.
+type groupKeys struct {
+	groups  []string
+	streams map[string]labels.Labels
+	buf     []byte
+}
+
+func newGroupKeys(groups []string) *groupKeys {
+	return &groupKeys{
+		groups:  groups,
+		streams: map[string]labels.Labels{},
+	}
+}
+
+// key returns the group key of an entry. The returned buffer is reused by
+// subsequent calls.
+func (g *groupKeys) key(streamLabels string, entry logproto.Entry) ([]byte, error) {
+	g.buf = g.buf[:0]
+	if len(g.groups) == 0 {
+		g.buf = append(g.buf, streamLabels...)
+		return g.buf, nil
+	}
+
+	lbls, ok := g.streams[streamLabels]
+	if !ok {
+		var err error
+		lbls, err = syntax.ParseLabels(streamLabels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse series labels to group entries: %w", err)
+		}
+		if len(g.streams) >= maxGroupKeysStreams {
+			clear(g.streams)
+		}
+		g.streams[streamLabels] = lbls
+	}
+
+	for i, name := range g.groups {
+		if i > 0 {
+			g.buf = append(g.buf, '\xff')
+		}
+		g.buf = append(g.buf, entryLabelValue(name, lbls, entry)...)
+	}
+	return g.buf, nil
+}
+
+func entryLabelValue(name string, streamLabels labels.Labels, entry logproto.Entry) string {
+	for _, l := range entry.Parsed {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	for _, l := range entry.StructuredMetadata {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return streamLabels.Get(name)
+}
+
+type limitByIterator struct {
+	EntryIterator
+	limit  int
+	keys   *groupKeys
+	counts map[string]int
+	err    error
+}
+
+// NewLimitByIterator returns an iterator over the first limit entries of each
+// group of entries sharing the same values for the given labels, or of each
+// stream if there are no labels.
+func NewLimitByIterator(it EntryIterator, limit int, groups []string) EntryIterator {
+	return &limitByIterator{
+		EntryIterator: it,
+		limit:         limit,
+		keys:          newGroupKeys(groups),
+		counts:        map[string]int{},
+	}
+}
+
+func (i *limitByIterator) Next() bool {
+	for i.EntryIterator.Next() {
+		key, err := i.keys.key(i.EntryIterator.Labels(), i.EntryIterator.At())
+		if err != nil {
+			i.err = err
+			return false
+		}
+		n := i.counts[string(key)]
+		if n >= i.limit {
+			continue
+		}
+		i.counts[string(key)] = n + 1
+		return true
+	}
+	return false
+}
+
+func (i *limitByIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.EntryIterator.Err()
+}
+
+type dedupIterator struct {
+	EntryIterator
+	keys *groupKeys
+	last map[string]string
+	err  error
+}
+
+// NewDedupIterator returns an iterator that skips entries whose line is
+// identical to the line of the previous entry of the same group of entries
+// sharing the same values for the given labels, or of the same stream if there
+// are no labels.
+func NewDedupIterator(it EntryIterator, groups []string) EntryIterator {
+	return &dedupIterator{
+		EntryIterator: it,
+		keys:          newGroupKeys(groups),
+		last:          map[string]string{},
+	}
+}
+
+func (i *dedupIterator) Next() bool {
+	for i.EntryIterator.Next() {
+		entry := i.EntryIterator.At()
+		key, err := i.keys.key(i.EntryIterator.Labels(), entry)
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if last, ok := i.last[string(key)]; ok && last == entry.Line {
+			continue
+		}
+		i.last[string(key)] = entry.Line
+		return true
+	}
+	return false
+}
+
+func (i *dedupIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.EntryIterator.Err()
+}
@@ -0,0 +1,124 @@
+package iter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+func groupIteratorStreams() []logproto.Stream {
+	return []logproto.Stream{
+		{
+			Labels: labels.FromStrings("app", "foo", "pod", "a").String(),
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(0, 1), Line: "start"},
+				{Timestamp: time.Unix(0, 3), Line: "retry"},
+				{Timestamp: time.Unix(0, 5), Line: "retry"},
+				{Timestamp: time.Unix(0, 7), Line: "done"},
+			},
+		},
+		{
+			Labels: labels.FromStrings("app", "foo", "pod", "b").String(),
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(0, 2), Line: "start"},
+				{Timestamp: time.Unix(0, 4), Line: "retry", Parsed: logproto.FromLabelsToLabelAdapters(labels.FromStrings("pod", "a"))},
+			},
+		},
+	}
+}
+
+func readGroupIterator(t *testing.T, it EntryIterator) []string {
+	t.Helper()
+	var res []string
+	for it.Next() {
+		lbls, err := syntax.ParseLabels(it.Labels())
+		require.NoError(t, err)
+		res = append(res, lbls.Get("pod")+":"+it.At().Line)
+	}
+	require.NoError(t, it.Err())
+	require.NoError(t, it.Close())
+	return res
+}
+
+func TestLimitByIterator(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		limit     int
+		groups    []string
+		direction logproto.Direction
+		expected  []string
+	}{
+		{
+			name:      "per stream",
+			limit:     2,
+			direction: logproto.FORWARD,
+			expected:  []string{"a:start", "b:start", "a:retry", "b:retry"},
+		},
+		{
+			name:      "per stream backward",
+			limit:     1,
+			direction: logproto.BACKWARD,
+			expected:  []string{"a:done", "b:retry"},
+		},
+		{
+			name:      "by stream label",
+			limit:     3,
+			groups:    []string{"app"},
+			direction: logproto.FORWARD,
+			expected:  []string{"a:start", "b:start", "a:retry"},
+		},
+		{
+			name:      "by parsed label",
+			limit:     2,
+			groups:    []string{"pod"},
+			direction: logproto.FORWARD,
+			expected:  []string{"a:start", "b:start", "a:retry"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			streams := groupIteratorStreams()
+			if tc.direction == logproto.BACKWARD {
+				for _, s := range streams {
+					for i, j := 0, len(s.Entries)-1; i < j; i, j = i+1, j-1 {
+						s.Entries[i], s.Entries[j] = s.Entries[j], s.Entries[i]
+					}
+				}
+			}
+			it := NewLimitByIterator(NewStreamsIterator(streams, tc.direction), tc.limit, tc.groups)
+			require.Equal(t, tc.expected, readGroupIterator(t, it))
+		})
+	}
+}
+
+func TestDedupIterator(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		groups   []string
+		expected []string
+	}{
+		{
+			name:     "per stream",
+			expected: []string{"a:start", "b:start", "a:retry", "b:retry", "a:done"},
+		},
+		{
+			name:     "by stream label",
+			groups:   []string{"app"},
+			expected: []string{"a:start", "a:retry", "a:done"},
+		},
+		{
+			name:     "by parsed label",
+			groups:   []string{"pod"},
+			expected: []string{"a:start", "b:start", "a:retry", "a:done"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			it := NewDedupIterator(NewStreamsIterator(groupIteratorStreams(), logproto.FORWARD), tc.groups)
+			require.Equal(t, tc.expected, readGroupIterator(t, it))
+		})
+	}
+}
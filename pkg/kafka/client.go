@@ -0,0 +1,266 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Shopify/sarama"
+)
+
+// OffsetOldest is returned by Consumer.CommittedOffset when no offset has been committed yet.
+// Passing it to Consumer.Fetch reads the partition from the oldest available record.
+const OffsetOldest = sarama.OffsetOldest
+
+// Producer writes records to the partitions of the configured topic.
+type Producer interface {
+	// Produce synchronously writes the records to Kafka. It returns once all records
+	// have been acknowledged, or with an error if any of them failed.
+	Produce(ctx context.Context, records []*Record) error
+	Close() error
+}
+
+// Consumer reads records from the partitions of the configured topic and keeps track
+// of the committed offsets of a consumer group.
+type Consumer interface {
+	// Fetch returns the records of the partition starting at offset. It blocks until at least
+	// one record is available or the context is done.
+	Fetch(ctx context.Context, partition int32, offset int64) ([]*Record, error)
+	// HighWatermark returns the offset of the next record that will be written to the partition.
+	HighWatermark(ctx context.Context, partition int32) (int64, error)
+	// CommittedOffset returns the offset of the next record to consume, as committed by the consumer group.
+	// It returns OffsetOldest when no offset has been committed yet.
+	CommittedOffset(ctx context.Context, partition int32) (int64, error)
+	// Commit stores offset as the next record to consume for the consumer group.
+	Commit(ctx context.Context, partition int32, offset int64) error
+	Close() error
+}
+
+func newSaramaConfig(cfg Config) *sarama.Config {
+	c := sarama.NewConfig()
+	c.Version = sarama.V2_1_0_0
+	if cfg.ClientID != "" {
+		c.ClientID = cfg.ClientID
+	}
+	c.Net.DialTimeout = cfg.DialTimeout
+	c.Metadata.Full = false
+
+	c.Producer.Partitioner = sarama.NewManualPartitioner
+	c.Producer.RequiredAcks = sarama.WaitForAll
+	c.Producer.Return.Successes = true
+	c.Producer.Return.Errors = true
+	c.Producer.Timeout = cfg.WriteTimeout
+	c.Producer.MaxMessageBytes = producerBatchMaxBytes
+
+	c.Consumer.Return.Errors = true
+	c.Consumer.MaxWaitTime = cfg.ConsumerFetchMaxWait
+	c.Consumer.Offsets.Initial = sarama.OffsetOldest
+	c.Consumer.Offsets.AutoCommit.Enable = false
+	return c
+}
+
+type saramaProducer struct {
+	topic    string
+	producer sarama.SyncProducer
+}
+
+// NewProducer returns a Producer backed by a Kafka cluster.
+func NewProducer(cfg Config) (Producer, error) {
+	producer, err := sarama.NewSyncProducer(cfg.Addresses(), newSaramaConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+	return &saramaProducer{topic: cfg.Topic, producer: producer}, nil
+}
+
+func (p *saramaProducer) Produce(ctx context.Context, records []*Record) error {
+	msgs := make([]*sarama.ProducerMessage, 0, len(records))
+	for _, r := range records {
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic:     p.topic,
+			Partition: r.Partition,
+			Key:       sarama.StringEncoder(r.TenantID),
+			Value:     sarama.ByteEncoder(r.Value),
+		})
+	}
+
+	// The sync producer doesn't support cancellation, the write timeout is enforced by the client config instead.
+	done := make(chan error, 1)
+	go func() {
+		done <- p.producer.SendMessages(msgs)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for i, msg := range msgs {
+		records[i].Offset = msg.Offset
+	}
+	return nil
+}
+
+func (p *saramaProducer) Close() error {
+	return p.producer.Close()
+}
+
+type saramaConsumer struct {
+	topic  string
+	client sarama.Client
+
+	mtx        sync.Mutex
+	consumer   sarama.Consumer
+	partitions map[int32]*partitionState
+	offsets    sarama.OffsetManager
+}
+
+type partitionState struct {
+	consumer   sarama.PartitionConsumer
+	nextOffset int64
+	offsets    sarama.PartitionOffsetManager
+}
+
+// NewConsumer returns a Consumer backed by a Kafka cluster, which commits offsets for the given consumer group.
+func NewConsumer(cfg Config, consumerGroup string) (Consumer, error) {
+	client, err := sarama.NewClient(cfg.Addresses(), newSaramaConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+	offsets, err := sarama.NewOffsetManagerFromClient(consumerGroup, client)
+	if err != nil {
+		_ = consumer.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create Kafka offset manager: %w", err)
+	}
+	return &saramaConsumer{
+		topic:      cfg.Topic,
+		client:     client,
+		consumer:   consumer,
+		offsets:    offsets,
+		partitions: make(map[int32]*partitionState),
+	}, nil
+}
+
+func (c *saramaConsumer) partition(partition int32) (*partitionState, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if p, ok := c.partitions[partition]; ok {
+		return p, nil
+	}
+	pom, err := c.offsets.ManagePartition(c.topic, partition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to manage offsets of partition %d: %w", partition, err)
+	}
+	p := &partitionState{offsets: pom}
+	c.partitions[partition] = p
+	return p, nil
+}
+
+func (c *saramaConsumer) Fetch(ctx context.Context, partition int32, offset int64) ([]*Record, error) {
+	p, err := c.partition(partition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restart consuming whenever the requested offset isn't the one following the last fetched record.
+	if p.consumer == nil || p.nextOffset != offset {
+		if p.consumer != nil {
+			_ = p.consumer.Close()
+			p.consumer = nil
+		}
+		pc, err := c.consumer.ConsumePartition(c.topic, partition, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to consume partition %d from offset %d: %w", partition, offset, err)
+		}
+		p.consumer = pc
+		p.nextOffset = offset
+	}
+
+	var records []*Record
+	add := func(msg *sarama.ConsumerMessage) {
+		records = append(records, &Record{
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+			TenantID:  string(msg.Key),
+			Value:     msg.Value,
+		})
+		p.nextOffset = msg.Offset + 1
+	}
+
+	select {
+	case msg := <-p.consumer.Messages():
+		add(msg)
+	case err := <-p.consumer.Errors():
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// Drain whatever is already buffered without waiting any further.
+	for {
+		select {
+		case msg := <-p.consumer.Messages():
+			add(msg)
+		default:
+			return records, nil
+		}
+	}
+}
+
+func (c *saramaConsumer) HighWatermark(_ context.Context, partition int32) (int64, error) {
+	return c.client.GetOffset(c.topic, partition, sarama.OffsetNewest)
+}
+
+func (c *saramaConsumer) CommittedOffset(_ context.Context, partition int32) (int64, error) {
+	p, err := c.partition(partition)
+	if err != nil {
+		return 0, err
+	}
+	offset, _ := p.offsets.NextOffset()
+	if offset < 0 {
+		return OffsetOldest, nil
+	}
+	return offset, nil
+}
+
+func (c *saramaConsumer) Commit(_ context.Context, partition int32, offset int64) error {
+	p, err := c.partition(partition)
+	if err != nil {
+		return err
+	}
+	p.offsets.MarkOffset(offset, "")
+	c.offsets.Commit()
+
+	select {
+	case err := <-p.offsets.Errors():
+		return err
+	default:
+		return nil
+	}
+}
+
+func (c *saramaConsumer) Close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var errs []error
+	for _, p := range c.partitions {
+		if p.consumer != nil {
+			errs = append(errs, p.consumer.Close())
+		}
+		errs = append(errs, p.offsets.Close())
+	}
+	errs = append(errs, c.offsets.Close(), c.consumer.Close(), c.client.Close())
+	return errors.Join(errs...)
+}
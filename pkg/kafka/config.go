@@ -0,0 +1,95 @@
+package kafka
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// producerBatchMaxBytes is the maximum size of a produce request accepted by Kafka by default (1MiB).
+	producerBatchMaxBytes = 1024 * 1024
+
+	// maxProducerRecordDataBytesLimit is the limit we set on the record payload size. It leaves some
+	// room for the record key and the headers which are added by the client.
+	maxProducerRecordDataBytesLimit = producerBatchMaxBytes - 16*1024
+	minProducerRecordDataBytesLimit = 128 * 1024
+)
+
+var (
+	ErrMissingKafkaAddress               = errors.New("the Kafka address has not been configured")
+	ErrMissingKafkaTopic                 = errors.New("the Kafka topic has not been configured")
+	ErrInvalidProducerMaxRecordSizeBytes = fmt.Errorf("the configured producer max record size bytes must be a value between %d and %d", minProducerRecordDataBytesLimit, maxProducerRecordDataBytesLimit)
+	ErrInvalidConsumerCommitInterval     = errors.New("the configured consumer commit interval must be greater than 0")
+)
+
+// Config holds the generic config for the Kafka backend.
+type Config struct {
+	Address      string        `yaml:"address"`
+	Topic        string        `yaml:"topic"`
+	ClientID     string        `yaml:"client_id"`
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+
+	ConsumerGroup          string        `yaml:"consumer_group"`
+	ConsumerCommitInterval time.Duration `yaml:"consumer_commit_interval"`
+	ConsumerFetchMaxWait   time.Duration `yaml:"consumer_fetch_max_wait"`
+
+	ProducerMaxRecordSizeBytes int `yaml:"producer_max_record_size_bytes"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.RegisterFlagsWithPrefix("kafka", f)
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Address, prefix+".address", "localhost:9092", "Comma separated list of the Kafka seed brokers used to bootstrap the client.")
+	f.StringVar(&cfg.Topic, prefix+".topic", "", "The Kafka topic name.")
+	f.StringVar(&cfg.ClientID, prefix+".client-id", "", "The Kafka client ID.")
+	f.DurationVar(&cfg.DialTimeout, prefix+".dial-timeout", 2*time.Second, "The maximum time allowed to open a connection to a Kafka broker.")
+	f.DurationVar(&cfg.WriteTimeout, prefix+".write-timeout", 10*time.Second, "How long to wait for an incoming write request to be successfully committed to the Kafka backend.")
+
+	f.StringVar(&cfg.ConsumerGroup, prefix+".consumer-group", "", "The consumer group used by the consumer to track the last consumed offset. The consumer group must be different for each ingester. If the configured consumer group contains the '<partition>' placeholder, it is replaced with the actual partition ID owned by the ingester. When empty (recommended), Loki uses the ingester instance ID to guarantee uniqueness.")
+	f.DurationVar(&cfg.ConsumerCommitInterval, prefix+".consumer-commit-interval", 5*time.Second, "How frequently a consumer should commit the offset of the oldest record whose data has not been flushed to storage yet. Records after the committed offset are replayed when the ingester restarts.")
+	f.DurationVar(&cfg.ConsumerFetchMaxWait, prefix+".consumer-fetch-max-wait", time.Second, "The maximum time a consumer waits for new records before checking whether the offset should be committed.")
+
+	f.IntVar(&cfg.ProducerMaxRecordSizeBytes, prefix+".producer-max-record-size-bytes", maxProducerRecordDataBytesLimit, "The maximum size of a Kafka record data that should be generated by the producer. An incoming write request larger than this size is split into multiple Kafka records. We strongly recommend to not change this setting unless for testing purposes.")
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Address == "" {
+		return ErrMissingKafkaAddress
+	}
+	if cfg.Topic == "" {
+		return ErrMissingKafkaTopic
+	}
+	if cfg.ProducerMaxRecordSizeBytes < minProducerRecordDataBytesLimit || cfg.ProducerMaxRecordSizeBytes > maxProducerRecordDataBytesLimit {
+		return ErrInvalidProducerMaxRecordSizeBytes
+	}
+	if cfg.ConsumerCommitInterval <= 0 {
+		return ErrInvalidConsumerCommitInterval
+	}
+	return nil
+}
+
+// Addresses returns the list of seed brokers configured in Address.
+func (cfg *Config) Addresses() []string {
+	var addrs []string
+	for _, addr := range strings.Split(cfg.Address, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// GetConsumerGroup returns the consumer group to use for the given instanceID and partitionID.
+func (cfg *Config) GetConsumerGroup(instanceID string, partitionID int32) string {
+	if cfg.ConsumerGroup == "" {
+		return instanceID
+	}
+
+	return strings.ReplaceAll(cfg.ConsumerGroup, "<partition>", fmt.Sprintf("%d", partitionID))
+}
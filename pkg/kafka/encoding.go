@@ -0,0 +1,122 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// Record is a single Kafka record. The key of the record is the tenant ID and the value
+// is a protobuf encoded logproto.Stream.
+type Record struct {
+	Partition int32
+	Offset    int64
+	TenantID  string
+	Value     []byte
+}
+
+// Encode converts a logproto.Stream into one or more Kafka records for the given partition.
+// Streams larger than maxSize are split across multiple records, so that the entries of a
+// single stream always keep their order within the partition.
+func Encode(partitionID int32, tenantID string, stream logproto.Stream, maxSize int) ([]*Record, error) {
+	if stream.Size() <= maxSize {
+		value, err := stream.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal stream: %w", err)
+		}
+		return []*Record{{Partition: partitionID, TenantID: tenantID, Value: value}}, nil
+	}
+
+	var (
+		records []*Record
+		batch   = logproto.Stream{Labels: stream.Labels, Hash: stream.Hash}
+		// The base size accounts for the labels and hash of every record.
+		baseSize    = batch.Size()
+		currentSize = baseSize
+	)
+
+	flush := func() error {
+		value, err := batch.Marshal()
+		if err != nil {
+			return fmt.Errorf("failed to marshal stream: %w", err)
+		}
+		records = append(records, &Record{Partition: partitionID, TenantID: tenantID, Value: value})
+		batch.Entries = batch.Entries[:0:0]
+		currentSize = baseSize
+		return nil
+	}
+
+	for _, entry := range stream.Entries {
+		// Every entry is prefixed by its tag and length in the encoded stream.
+		entrySize := entry.Size() + sovSize(entry.Size()) + 1
+		if baseSize+entrySize > maxSize {
+			return nil, fmt.Errorf("entry of stream %s is too large to fit in a single Kafka record: %d bytes, limit %d bytes", stream.Labels, entrySize, maxSize-baseSize)
+		}
+		if currentSize+entrySize > maxSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		batch.Entries = append(batch.Entries, entry)
+		currentSize += entrySize
+	}
+
+	if len(batch.Entries) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// maxDecoderCacheSize is the number of parsed label sets kept by a Decoder.
+const maxDecoderCacheSize = 10000
+
+// Decoder decodes Kafka records into logproto.Stream.
+// It caches parsed labels, since the same streams are usually written over and over again.
+// A Decoder is not safe for concurrent use.
+type Decoder struct {
+	cache map[string]labels.Labels
+}
+
+// NewDecoder returns a new Decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		cache: make(map[string]labels.Labels),
+	}
+}
+
+// Decode decodes the value of a record into a logproto.Stream and its parsed labels.
+func (d *Decoder) Decode(value []byte) (logproto.Stream, labels.Labels, error) {
+	var stream logproto.Stream
+	if err := stream.Unmarshal(value); err != nil {
+		return logproto.Stream{}, nil, fmt.Errorf("failed to unmarshal stream: %w", err)
+	}
+
+	ls, ok := d.cache[stream.Labels]
+	if !ok {
+		var err error
+		ls, err = syntax.ParseLabels(stream.Labels)
+		if err != nil {
+			return logproto.Stream{}, nil, fmt.Errorf("failed to parse labels %s: %w", stream.Labels, err)
+		}
+		if len(d.cache) >= maxDecoderCacheSize {
+			d.cache = make(map[string]labels.Labels)
+		}
+		d.cache[stream.Labels] = ls
+	}
+	return stream, ls, nil
+}
+
+// sovSize returns the number of bytes needed to varint encode x.
+func sovSize(x int) int {
+	n := 1
+	for x >= 1<<7 {
+		x >>= 7
+		n++
+	}
+	return n
+}
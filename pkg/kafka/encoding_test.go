@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	stream := logproto.Stream{
+		Labels: `{app="foo", env="prod"}`,
+		Entries: []logproto.Entry{
+			{Timestamp: time.Unix(1, 0).UTC(), Line: "line 1"},
+			{Timestamp: time.Unix(2, 0).UTC(), Line: "line 2"},
+		},
+	}
+
+	records, err := Encode(3, "tenant", stream, maxProducerRecordDataBytesLimit)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, int32(3), records[0].Partition)
+	require.Equal(t, "tenant", records[0].TenantID)
+
+	decoded, ls, err := NewDecoder().Decode(records[0].Value)
+	require.NoError(t, err)
+	require.Equal(t, stream.Labels, decoded.Labels)
+	require.Equal(t, stream.Entries, decoded.Entries)
+	require.Equal(t, "prod", ls.Get("env"))
+}
+
+func TestEncodeSplitsLargeStreams(t *testing.T) {
+	stream := logproto.Stream{Labels: `{app="foo"}`}
+	for i := 0; i < 100; i++ {
+		stream.Entries = append(stream.Entries, logproto.Entry{
+			Timestamp: time.Unix(int64(i), 0).UTC(),
+			Line:      strings.Repeat("a", 100),
+		})
+	}
+
+	const maxSize = 1000
+	records, err := Encode(0, "tenant", stream, maxSize)
+	require.NoError(t, err)
+	require.Greater(t, len(records), 1)
+
+	decoder := NewDecoder()
+	var entries []logproto.Entry
+	for _, r := range records {
+		require.LessOrEqual(t, len(r.Value), maxSize)
+		decoded, _, err := decoder.Decode(r.Value)
+		require.NoError(t, err)
+		require.Equal(t, stream.Labels, decoded.Labels)
+		entries = append(entries, decoded.Entries...)
+	}
+	require.Equal(t, stream.Entries, entries)
+}
+
+func TestEncodeEntryTooLarge(t *testing.T) {
+	stream := logproto.Stream{
+		Labels:  `{app="foo"}`,
+		Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0).UTC(), Line: strings.Repeat("a", 2000)}},
+	}
+	_, err := Encode(0, "tenant", stream, 1000)
+	require.Error(t, err)
+}
@@ -0,0 +1,135 @@
+// Package kafkatest provides an in-memory stand-in for a Kafka cluster, to test the
+// components producing and consuming records without a running Kafka.
+package kafkatest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/loki/v3/pkg/kafka"
+)
+
+// Cluster is an in-memory Kafka cluster with a single topic. It implements kafka.Producer,
+// while consumers for a consumer group are created with Consumer.
+type Cluster struct {
+	mtx        sync.Mutex
+	partitions map[int32][]*kafka.Record
+	committed  map[string]map[int32]int64
+	produceErr error
+	// produced is closed and replaced every time records are produced, to wake up fetches.
+	produced chan struct{}
+}
+
+// NewCluster returns an empty Cluster.
+func NewCluster() *Cluster {
+	return &Cluster{
+		partitions: make(map[int32][]*kafka.Record),
+		committed:  make(map[string]map[int32]int64),
+		produced:   make(chan struct{}),
+	}
+}
+
+// SetProduceError makes every following Produce call fail with err. A nil error restores the default behavior.
+func (c *Cluster) SetProduceError(err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.produceErr = err
+}
+
+// Produce appends the records to their partition and sets their offset.
+func (c *Cluster) Produce(_ context.Context, records []*kafka.Record) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.produceErr != nil {
+		return c.produceErr
+	}
+	for _, r := range records {
+		stored := *r
+		stored.Offset = int64(len(c.partitions[r.Partition]))
+		c.partitions[r.Partition] = append(c.partitions[r.Partition], &stored)
+		r.Offset = stored.Offset
+	}
+	close(c.produced)
+	c.produced = make(chan struct{})
+	return nil
+}
+
+// Close implements kafka.Producer.
+func (c *Cluster) Close() error {
+	return nil
+}
+
+// Records returns a copy of the records written to the partition.
+func (c *Cluster) Records(partition int32) []*kafka.Record {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return append([]*kafka.Record(nil), c.partitions[partition]...)
+}
+
+// CommittedOffset returns the offset committed by the consumer group for the partition,
+// or kafka.OffsetOldest when none has been committed.
+func (c *Cluster) CommittedOffset(group string, partition int32) int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if offset, ok := c.committed[group][partition]; ok {
+		return offset
+	}
+	return kafka.OffsetOldest
+}
+
+// Consumer returns a kafka.Consumer committing offsets for the given consumer group.
+func (c *Cluster) Consumer(group string) kafka.Consumer {
+	return &consumer{cluster: c, group: group}
+}
+
+type consumer struct {
+	cluster *Cluster
+	group   string
+}
+
+func (c *consumer) Fetch(ctx context.Context, partition int32, offset int64) ([]*kafka.Record, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	for {
+		c.cluster.mtx.Lock()
+		records := c.cluster.partitions[partition]
+		produced := c.cluster.produced
+		c.cluster.mtx.Unlock()
+
+		if offset < int64(len(records)) {
+			return append([]*kafka.Record(nil), records[offset:]...), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-produced:
+		}
+	}
+}
+
+func (c *consumer) HighWatermark(_ context.Context, partition int32) (int64, error) {
+	c.cluster.mtx.Lock()
+	defer c.cluster.mtx.Unlock()
+	return int64(len(c.cluster.partitions[partition])), nil
+}
+
+func (c *consumer) CommittedOffset(_ context.Context, partition int32) (int64, error) {
+	return c.cluster.CommittedOffset(c.group, partition), nil
+}
+
+func (c *consumer) Commit(_ context.Context, partition int32, offset int64) error {
+	c.cluster.mtx.Lock()
+	defer c.cluster.mtx.Unlock()
+	if c.cluster.committed[c.group] == nil {
+		c.cluster.committed[c.group] = make(map[int32]int64)
+	}
+	c.cluster.committed[c.group][partition] = offset
+	return nil
+}
+
+func (c *consumer) Close() error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package kafka
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var ingesterIDRegexp = regexp.MustCompile("-([0-9]+)$")
+
+// IngesterPartitionID returns the partition ID owned by the ingester with the given ID.
+// Ingesters are expected to be deployed as a StatefulSet, so the partition ID is the
+// sequence number at the end of the ID: "ingester-zone-a-3" owns partition 3.
+func IngesterPartitionID(ingesterID string) (int32, error) {
+	match := ingesterIDRegexp.FindStringSubmatch(ingesterID)
+	if len(match) == 0 {
+		return 0, fmt.Errorf("ingester ID %s doesn't match regular expression %q", ingesterID, ingesterIDRegexp.String())
+	}
+
+	// Parse the ingester sequence number.
+	ingesterSeq, err := strconv.ParseInt(match[1], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("no ingester sequence number in ingester ID %s", ingesterID)
+	}
+
+	return int32(ingesterSeq), nil
+}
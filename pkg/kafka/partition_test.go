@@ -0,0 +1,30 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngesterPartitionID(t *testing.T) {
+	for _, tc := range []struct {
+		ingesterID string
+		expected   int32
+		expectErr  bool
+	}{
+		{ingesterID: "ingester-0", expected: 0},
+		{ingesterID: "ingester-zone-a-12", expected: 12},
+		{ingesterID: "ingester", expectErr: true},
+		{ingesterID: "ingester-a", expectErr: true},
+	} {
+		t.Run(tc.ingesterID, func(t *testing.T) {
+			id, err := IngesterPartitionID(tc.ingesterID)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, id)
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+// RecordHandler receives the streams read from a partition.
+type RecordHandler interface {
+	// Handle pushes a stream read from the record at the given offset.
+	Handle(ctx context.Context, tenantID string, stream logproto.Stream, offset int64) error
+	// OldestUnflushedOffset returns the offset of the oldest record whose data has not been
+	// flushed to storage yet. It returns false if all the handled data has been flushed.
+	OldestUnflushedOffset() (int64, bool)
+}
+
+type readerMetrics struct {
+	recordsTotal        prometheus.Counter
+	recordFailuresTotal prometheus.Counter
+	fetchFailuresTotal  prometheus.Counter
+	commitFailuresTotal prometheus.Counter
+	lastCommittedOffset prometheus.Gauge
+	partitionLagRecords prometheus.Gauge
+	catchUpDuration     prometheus.Histogram
+}
+
+func newReaderMetrics(partitionID int32, reg prometheus.Registerer) *readerMetrics {
+	constLabels := prometheus.Labels{"partition": strconv.Itoa(int(partitionID))}
+	return &readerMetrics{
+		recordsTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Name:        "kafka_reader_records_total",
+			Help:        "Total number of records read from the partition.",
+			ConstLabels: constLabels,
+		}),
+		recordFailuresTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Name:        "kafka_reader_record_failures_total",
+			Help:        "Total number of records read from the partition that failed to be decoded or pushed.",
+			ConstLabels: constLabels,
+		}),
+		fetchFailuresTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Name:        "kafka_reader_fetch_failures_total",
+			Help:        "Total number of failed attempts to fetch records from the partition.",
+			ConstLabels: constLabels,
+		}),
+		commitFailuresTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Name:        "kafka_reader_commit_failures_total",
+			Help:        "Total number of failed attempts to commit the offset of the partition.",
+			ConstLabels: constLabels,
+		}),
+		lastCommittedOffset: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   constants.Loki,
+			Name:        "kafka_reader_last_committed_offset",
+			Help:        "The last offset successfully committed for the partition.",
+			ConstLabels: constLabels,
+		}),
+		partitionLagRecords: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   constants.Loki,
+			Name:        "kafka_reader_partition_lag_records",
+			Help:        "Number of records written to the partition which have not been read yet.",
+			ConstLabels: constLabels,
+		}),
+		catchUpDuration: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Namespace:   constants.Loki,
+			Name:        "kafka_reader_catch_up_duration_seconds",
+			Help:        "Time taken to replay the partition from the last committed offset at startup.",
+			Buckets:     prometheus.ExponentialBuckets(0.1, 2, 12),
+			ConstLabels: constLabels,
+		}),
+	}
+}
+
+// PartitionReader consumes a single partition and pushes its records to a RecordHandler.
+//
+// At startup it replays the partition from the last committed offset up to the high
+// watermark before becoming ready, which replaces the replay of a local WAL. While running,
+// it periodically commits the offset of the oldest record whose data has not been flushed
+// yet, so that no acknowledged write is lost if the ingester crashes.
+type PartitionReader struct {
+	services.Service
+
+	cfg         Config
+	partitionID int32
+	consumer    Consumer
+	handler     RecordHandler
+	decoder     *Decoder
+	logger      log.Logger
+	metrics     *readerMetrics
+
+	// nextOffset is the offset of the next record to read.
+	nextOffset int64
+	// committedOffset is the last offset committed for the partition.
+	committedOffset int64
+}
+
+// NewPartitionReader returns a PartitionReader for the given partition. The consumer is closed when the reader stops.
+func NewPartitionReader(cfg Config, partitionID int32, consumer Consumer, handler RecordHandler, logger log.Logger, reg prometheus.Registerer) *PartitionReader {
+	r := &PartitionReader{
+		cfg:             cfg,
+		partitionID:     partitionID,
+		consumer:        consumer,
+		handler:         handler,
+		decoder:         NewDecoder(),
+		logger:          log.With(logger, "component", "kafka-partition-reader", "partition", partitionID),
+		metrics:         newReaderMetrics(partitionID, reg),
+		nextOffset:      OffsetOldest,
+		committedOffset: OffsetOldest,
+	}
+	r.Service = services.NewBasicService(r.starting, r.running, r.stopping)
+	return r
+}
+
+func (r *PartitionReader) starting(ctx context.Context) error {
+	committed, err := r.consumer.CommittedOffset(ctx, r.partitionID)
+	if err != nil {
+		return fmt.Errorf("failed to read committed offset of partition %d: %w", r.partitionID, err)
+	}
+	r.nextOffset, r.committedOffset = committed, committed
+
+	highWatermark, err := r.consumer.HighWatermark(ctx, r.partitionID)
+	if err != nil {
+		return fmt.Errorf("failed to read high watermark of partition %d: %w", r.partitionID, err)
+	}
+
+	level.Info(r.logger).Log("msg", "replaying partition", "from_offset", committed, "to_offset", highWatermark)
+	start := time.Now()
+	for r.nextOffset < highWatermark {
+		n, err := r.poll(ctx)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Without a committed offset, the partition is read from the oldest record still retained,
+		// which might be none at all if every record up to the high watermark has been deleted.
+		if n == 0 && err == nil && r.nextOffset < 0 {
+			break
+		}
+	}
+	r.metrics.catchUpDuration.Observe(time.Since(start).Seconds())
+	r.updateLag(ctx)
+	level.Info(r.logger).Log("msg", "partition replayed", "next_offset", r.nextOffset, "duration", time.Since(start))
+	return nil
+}
+
+func (r *PartitionReader) running(ctx context.Context) error {
+	commitTicker := time.NewTicker(r.cfg.ConsumerCommitInterval)
+	defer commitTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-commitTicker.C:
+			r.commit(ctx)
+			r.updateLag(ctx)
+		default:
+		}
+
+		if _, err := r.poll(ctx); err != nil && ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (r *PartitionReader) stopping(_ error) error {
+	// Commit what is safe to commit with a fresh context, since the service context is already canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.WriteTimeout)
+	defer cancel()
+	r.commit(ctx)
+	return r.consumer.Close()
+}
+
+// poll fetches and handles the next batch of records and returns how many were read.
+// It waits at most ConsumerFetchMaxWait for new records.
+func (r *PartitionReader) poll(ctx context.Context) (int, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, r.cfg.ConsumerFetchMaxWait)
+	defer cancel()
+
+	records, err := r.consumer.Fetch(fetchCtx, r.partitionID, r.nextOffset)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return 0, nil
+		}
+		if ctx.Err() == nil {
+			r.metrics.fetchFailuresTotal.Inc()
+			level.Warn(r.logger).Log("msg", "failed to fetch records", "offset", r.nextOffset, "err", err)
+			r.wait(ctx)
+		}
+		return 0, err
+	}
+
+	for _, record := range records {
+		r.handle(ctx, record)
+		r.nextOffset = record.Offset + 1
+	}
+	return len(records), nil
+}
+
+func (r *PartitionReader) handle(ctx context.Context, record *Record) {
+	r.metrics.recordsTotal.Inc()
+
+	stream, _, err := r.decoder.Decode(record.Value)
+	if err != nil {
+		r.metrics.recordFailuresTotal.Inc()
+		level.Error(r.logger).Log("msg", "failed to decode record", "offset", record.Offset, "err", err)
+		return
+	}
+	if err := r.handler.Handle(ctx, record.TenantID, stream, record.Offset); err != nil {
+		r.metrics.recordFailuresTotal.Inc()
+		level.Warn(r.logger).Log("msg", "failed to push record", "offset", record.Offset, "tenant", record.TenantID, "err", err)
+	}
+}
+
+// commit stores the offset of the oldest record whose data is still only held in memory.
+// When all data has been flushed, the offset of the next record to read is committed instead.
+func (r *PartitionReader) commit(ctx context.Context) {
+	offset := r.nextOffset
+	if unflushed, ok := r.handler.OldestUnflushedOffset(); ok && unflushed < offset {
+		offset = unflushed
+	}
+	if offset < 0 || offset <= r.committedOffset {
+		return
+	}
+
+	if err := r.consumer.Commit(ctx, r.partitionID, offset); err != nil {
+		r.metrics.commitFailuresTotal.Inc()
+		level.Warn(r.logger).Log("msg", "failed to commit offset", "offset", offset, "err", err)
+		return
+	}
+	r.committedOffset = offset
+	r.metrics.lastCommittedOffset.Set(float64(offset))
+}
+
+func (r *PartitionReader) updateLag(ctx context.Context) {
+	highWatermark, err := r.consumer.HighWatermark(ctx, r.partitionID)
+	if err != nil {
+		level.Warn(r.logger).Log("msg", "failed to read high watermark", "err", err)
+		return
+	}
+	lag := highWatermark
+	if r.nextOffset > 0 {
+		lag -= r.nextOffset
+	}
+	r.metrics.partitionLagRecords.Set(float64(max(lag, 0)))
+}
+
+// wait backs off after a failed fetch, so that an unavailable cluster doesn't cause a busy loop.
+func (r *PartitionReader) wait(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(r.cfg.ConsumerFetchMaxWait):
+	}
+}
@@ -0,0 +1,109 @@
+package kafka_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/kafka"
+	"github.com/grafana/loki/v3/pkg/kafka/kafkatest"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+type mockHandler struct {
+	mtx       sync.Mutex
+	streams   []logproto.Stream
+	unflushed int64
+	hasData   bool
+}
+
+func (m *mockHandler) Handle(_ context.Context, _ string, stream logproto.Stream, offset int64) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.streams = append(m.streams, stream)
+	if !m.hasData {
+		m.unflushed, m.hasData = offset, true
+	}
+	return nil
+}
+
+func (m *mockHandler) OldestUnflushedOffset() (int64, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.unflushed, m.hasData
+}
+
+func (m *mockHandler) flush() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.hasData = false
+}
+
+func (m *mockHandler) received() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return len(m.streams)
+}
+
+func testConfig() kafka.Config {
+	return kafka.Config{
+		Topic:                  "logs",
+		WriteTimeout:           time.Second,
+		ConsumerCommitInterval: 10 * time.Millisecond,
+		ConsumerFetchMaxWait:   10 * time.Millisecond,
+	}
+}
+
+func produce(t *testing.T, cluster *kafkatest.Cluster, partition int32, lines ...string) {
+	for _, line := range lines {
+		records, err := kafka.Encode(partition, "tenant", logproto.Stream{
+			Labels:  `{app="foo"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Now(), Line: line}},
+		}, 1<<20)
+		require.NoError(t, err)
+		require.NoError(t, cluster.Produce(context.Background(), records))
+	}
+}
+
+func TestPartitionReader_ReplaysFromCommittedOffset(t *testing.T) {
+	cluster := kafkatest.NewCluster()
+	produce(t, cluster, 1, "a", "b", "c", "d")
+	require.NoError(t, cluster.Consumer("ingester-1").Commit(context.Background(), 1, 2))
+
+	handler := &mockHandler{}
+	reader := kafka.NewPartitionReader(testConfig(), 1, cluster.Consumer("ingester-1"), handler, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), reader))
+	defer services.StopAndAwaitTerminated(context.Background(), reader) //nolint:errcheck
+
+	// Records before the committed offset are not replayed, and the reader is only running once caught up.
+	require.Equal(t, 2, handler.received())
+	require.Equal(t, "c", handler.streams[0].Entries[0].Line)
+	require.Equal(t, "d", handler.streams[1].Entries[0].Line)
+
+	produce(t, cluster, 1, "e")
+	require.Eventually(t, func() bool { return handler.received() == 3 }, time.Second, 10*time.Millisecond)
+}
+
+func TestPartitionReader_CommitsOldestUnflushedOffset(t *testing.T) {
+	cluster := kafkatest.NewCluster()
+	handler := &mockHandler{}
+	reader := kafka.NewPartitionReader(testConfig(), 0, cluster.Consumer("ingester-0"), handler, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), reader))
+	defer services.StopAndAwaitTerminated(context.Background(), reader) //nolint:errcheck
+
+	produce(t, cluster, 0, "a", "b", "c")
+	require.Eventually(t, func() bool { return handler.received() == 3 }, time.Second, 10*time.Millisecond)
+
+	// The first record has not been flushed yet, so it must be replayed after a restart.
+	require.Eventually(t, func() bool { return cluster.CommittedOffset("ingester-0", 0) == 0 }, time.Second, 10*time.Millisecond)
+
+	// Once everything is flushed, the reader commits the offset of the next record to read.
+	handler.flush()
+	require.Eventually(t, func() bool { return cluster.CommittedOffset("ingester-0", 0) == 3 }, time.Second, 10*time.Millisecond)
+}
@@ -225,6 +225,10 @@ func (l *limiter) MaxQuerySeries(_ context.Context, _ string) int {
 	return l.n
 }
 
+func (l *limiter) MaxStatsEntriesPerQuery(_ context.Context, _ string) int {
+	return 0
+}
+
 func (l *limiter) MaxQueryRange(_ context.Context, _ string) time.Duration {
 	return 0 * time.Second
 }
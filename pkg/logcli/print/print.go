@@ -3,6 +3,7 @@ package print
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"sort"
@@ -53,6 +54,8 @@ func (r *QueryResultPrinter) PrintResult(value loghttp.ResultValue, out output.L
 		printMatrix(value.(loghttp.Matrix))
 	case loghttp.ResultTypeVector:
 		printVector(value.(loghttp.Vector))
+	case loghttp.ResultTypeTable:
+		printTable(os.Stdout, value.(loghttp.Table))
 	default:
 		log.Fatalf("Unable to print unsupported type: %v", value.Type())
 	}
@@ -195,6 +198,18 @@ func printScalar(scalar loghttp.Scalar) {
 	fmt.Print(string(bytes))
 }
 
+// printTable writes the table as tab-aligned columns with a header row.
+func printTable(w io.Writer, table loghttp.Table) {
+	writer := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(table.Columns, "\t"))
+	for _, row := range table.Rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	if err := writer.Flush(); err != nil {
+		log.Fatalf("Error writing table: %v", err)
+	}
+}
+
 type kvLogger struct {
 	*tabwriter.Writer
 }
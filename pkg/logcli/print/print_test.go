@@ -1,6 +1,7 @@
 package print
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 
@@ -141,6 +142,21 @@ func Test_subtract(t *testing.T) {
 	}
 }
 
+func Test_printTable(t *testing.T) {
+	var buf bytes.Buffer
+	printTable(&buf, loghttp.Table{
+		Columns: []string{"status", "route", "count()"},
+		Rows: [][]string{
+			{"200", "/api/v1/push", "1234"},
+			{"500", "/", "5"},
+		},
+	})
+	require.Equal(t, `status  route         count()
+200     /api/v1/push  1234
+500     /             5
+`, buf.String())
+}
+
 func mustParseLabels(t *testing.T, s string) loghttp.LabelSet {
 	t.Helper()
 	l, err := marshal.NewLabelSet(s)
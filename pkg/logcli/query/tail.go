@@ -126,6 +126,9 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 				log.Println(d.Timestamp, d.Labels)
 			}
 		}
+		if d := tailResponse.DropCounts; d != nil {
+			log.Printf("Server did not send %d entries sampled out, %d entries exceeding the rate limit and %d entries dropped due to slow client since the start of the tail\n", d.Sampled, d.RateLimited, d.Dropped)
+		}
 	}
 }
 
@@ -12,8 +12,16 @@ type DroppedEntry struct {
 	Labels    string
 }
 
+// TailDropCounts are the number of entries of a tail call not sent by the ingesters, by reason
+type TailDropCounts struct {
+	Sampled     uint64 `json:"sampled"`
+	RateLimited uint64 `json:"rate_limited"`
+	Dropped     uint64 `json:"dropped"`
+}
+
 // TailResponse represents the http json response to a tail query
 type TailResponse struct {
 	Streams        []logproto.Stream `json:"streams"`
 	DroppedEntries []DroppedEntry    `json:"dropped_entries"`
+	DropCounts     *TailDropCounts   `json:"drop_counts,omitempty"`
 }
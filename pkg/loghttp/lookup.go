@@ -0,0 +1,66 @@
+package loghttp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// LookupResponse represents the http json response to a lookup query.
+type LookupResponse struct {
+	Status   string             `json:"status"`
+	Data     LookupResponseData `json:"data"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+// LookupResponseData holds the streams having entries with the value looked up and these entries.
+type LookupResponseData struct {
+	Refs    []LookupRef `json:"refs"`
+	Streams Streams     `json:"streams"`
+}
+
+// LookupRef is a stream having entries with the value looked up, between from and through.
+type LookupRef struct {
+	Stream      LabelSet  `json:"stream"`
+	Fingerprint string    `json:"fingerprint"`
+	From        time.Time `json:"from"`
+	Through     time.Time `json:"through"`
+}
+
+// ParseLookupQuery parses a LookupRequest request from an http request.
+func ParseLookupQuery(r *http.Request) (*logproto.LookupRequest, error) {
+	var err error
+	result := &logproto.LookupRequest{
+		Key:   r.Form.Get("key"),
+		Value: r.Form.Get("value"),
+	}
+	if !model.LabelName(result.Key).IsValid() {
+		return nil, errors.Errorf("invalid key %q", result.Key)
+	}
+	if result.Value == "" {
+		return nil, errors.New("value must not be empty")
+	}
+
+	result.Start, result.End, err = bounds(r)
+	if err != nil {
+		return nil, err
+	}
+	if result.End.Before(result.Start) {
+		return nil, errEndBeforeStart
+	}
+
+	result.Limit, err = limit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Direction, err = direction(r)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package loghttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestParseLookupQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    *logproto.LookupRequest
+		wantErr bool
+	}{
+		{
+			name: "should correctly parse valid params",
+			path: "/loki/api/v1/lookup?key=trace_id&value=abc&start=100000000000&end=3600000000000&limit=10&direction=forward",
+			want: &logproto.LookupRequest{
+				Key:       "trace_id",
+				Value:     "abc",
+				Start:     time.Unix(100, 0),
+				End:       time.Unix(3600, 0),
+				Limit:     10,
+				Direction: logproto.FORWARD,
+			},
+		},
+		{
+			name: "should default the limit and the direction",
+			path: "/loki/api/v1/lookup?key=trace_id&value=abc&start=100000000000&end=3600000000000",
+			want: &logproto.LookupRequest{
+				Key:       "trace_id",
+				Value:     "abc",
+				Start:     time.Unix(100, 0),
+				End:       time.Unix(3600, 0),
+				Limit:     100,
+				Direction: logproto.BACKWARD,
+			},
+		},
+		{
+			name:    "should reject an invalid key",
+			path:    "/loki/api/v1/lookup?key=trace-id&value=abc",
+			wantErr: true,
+		},
+		{
+			name:    "should reject an empty value",
+			path:    "/loki/api/v1/lookup?key=trace_id",
+			wantErr: true,
+		},
+		{
+			name:    "should reject an end before the start",
+			path:    "/loki/api/v1/lookup?key=trace_id&value=abc&start=3600000000000&end=100000000000",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			require.NoError(t, err)
+			err = req.ParseForm()
+			require.NoError(t, err)
+
+			got, err := ParseLookupQuery(req)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equalf(t, tt.want, got, "Incorrect response from input path: %s", tt.path)
+		})
+	}
+}
@@ -142,6 +142,21 @@ func tailDelay(r *http.Request) (uint32, error) {
 	return uint32(l), nil
 }
 
+func tailSample(r *http.Request) (float64, error) {
+	value := r.Form.Get("sample")
+	if value == "" {
+		return 0, nil
+	}
+	sample, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if sample <= 0 || sample > 1 {
+		return 0, errors.New("sample must be greater than 0 and at most 1")
+	}
+	return sample, nil
+}
+
 // parseInt parses an int from a string
 // if the value is empty it returns a default value passed as second parameter
 func parseInt(value string, def int) (int, error) {
@@ -51,6 +51,12 @@ func ParseOTLPRequest(userID string, r *http.Request, tenantsRetention TenantsRe
 	return req, stats, nil
 }
 
+// OTLPLogsToPushRequest converts OTLP logs to a Loki push request, attributes are
+// mapped to stream labels and structured metadata according to otlpConfig.
+func OTLPLogsToPushRequest(ctx context.Context, ld plog.Logs, otlpConfig OTLPConfig) *logproto.PushRequest {
+	return otlpToLokiPushRequest(ctx, ld, "", nil, otlpConfig, nil, newPushStats())
+}
+
 func extractLogs(r *http.Request, pushStats *Stats) (plog.Logs, error) {
 	pushStats.ContentEncoding = r.Header.Get(contentEnc)
 	// bodySize should always reflect the compressed size of the request body
@@ -151,7 +157,10 @@ func otlpToLokiPushRequest(ctx context.Context, ld plog.Logs, userID string, ten
 		}
 
 		resourceAttributesAsStructuredMetadataSize := labelsSize(resourceAttributesAsStructuredMetadata)
-		retentionPeriodForUser := tenantsRetention.RetentionPeriodFor(userID, lbs)
+		var retentionPeriodForUser time.Duration
+		if tenantsRetention != nil {
+			retentionPeriodForUser = tenantsRetention.RetentionPeriodFor(userID, lbs)
+		}
 
 		stats.StructuredMetadataBytes[retentionPeriodForUser] += int64(resourceAttributesAsStructuredMetadataSize)
 		if tracker != nil {
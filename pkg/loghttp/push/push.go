@@ -62,6 +62,10 @@ const (
 	LabelServiceName      = "service_name"
 	ServiceUnknown        = "unknown_service"
 	AggregatedMetricLabel = "__aggregated_metric__"
+	// AggregatedMetricOwnerLabel identifies the ingester, or the partition, whose streams a pre-aggregated metric stream aggregates.
+	AggregatedMetricOwnerLabel = "__aggregated_metric_owner__"
+	// AggregatedMetricReplicaLabel identifies the ingester writing a pre-aggregated metric stream.
+	AggregatedMetricReplicaLabel = "__aggregated_metric_replica__"
 )
 
 type TenantsRetention interface {
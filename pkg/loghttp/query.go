@@ -214,6 +214,7 @@ const (
 	ResultTypeScalar = "scalar"
 	ResultTypeVector = "vector"
 	ResultTypeMatrix = "matrix"
+	ResultTypeTable  = "table"
 )
 
 // ResultValue interface mimics the promql.Value interface
@@ -240,6 +241,9 @@ func (Vector) Type() ResultType { return ResultTypeVector }
 // Type implements the promql.Value interface
 func (Matrix) Type() ResultType { return ResultTypeMatrix }
 
+// Type implements the promql.Value interface
+func (Table) Type() ResultType { return ResultTypeTable }
+
 // Streams is a slice of Stream
 type Streams []Stream
 
@@ -354,6 +358,12 @@ func (q *QueryResponseData) UnmarshalJSON(data []byte) error {
 					return err
 				}
 				q.Result = v
+			case ResultTypeTable:
+				var t Table
+				if err = json.Unmarshal(value, &t); err != nil {
+					return err
+				}
+				q.Result = t
 			default:
 				return fmt.Errorf("unknown type: %s", q.ResultType)
 			}
@@ -388,6 +398,13 @@ type Vector []model.Sample
 // Matrix is a slice of SampleStreams
 type Matrix []model.SampleStream
 
+// Table is the result of a log query with a stats stage. Each row holds a
+// value per column.
+type Table struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
 // InstantQuery defines a log instant query.
 type InstantQuery struct {
 	Query     string
@@ -279,6 +279,17 @@ func Test_QueryResponseUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			Status: "ok",
+			Data: QueryResponseData{
+				ResultType: "table",
+				Result: Table{
+					Columns: []string{"status", "count()"},
+					Rows:    [][]string{{"200", "12"}, {"500", "3"}},
+				},
+				Statistics: stats.Result{},
+			},
+		},
 	} {
 		tt := tt
 		t.Run("", func(t *testing.T) {
@@ -23,6 +23,15 @@ const (
 type TailResponse struct {
 	Streams        []Stream        `json:"streams,omitempty"`
 	DroppedStreams []DroppedStream `json:"dropped_entries,omitempty"`
+	DropCounts     *TailDropCounts `json:"drop_counts,omitempty"`
+}
+
+// TailDropCounts are the number of entries of a tail call not sent by the ingesters since it started:
+// the entries sampled out, the entries exceeding the rate limit, and the entries dropped for slow clients.
+type TailDropCounts struct {
+	Sampled     uint64 `json:"sampled"`
+	RateLimited uint64 `json:"rate_limited"`
+	Dropped     uint64 `json:"dropped"`
 }
 
 // DroppedStream represents a dropped stream in tail call
@@ -74,6 +83,17 @@ func ParseTailQuery(r *http.Request) (*logproto.TailRequest, error) {
 	if err != nil {
 		return nil, err
 	}
+	if expr, ok := parsed.(syntax.LogSelectorExpr); ok {
+		if _, _, ok := syntax.SplitStatsStage(expr); ok {
+			return nil, fmt.Errorf("%s stage is not supported when tailing", syntax.OpStats)
+		}
+		if _, ok := syntax.AroundStage(expr); ok {
+			return nil, fmt.Errorf("%s stage is not supported when tailing", syntax.OpAround)
+		}
+		if len(syntax.ResultStages(expr)) > 0 {
+			return nil, fmt.Errorf("%s and %s stages are not supported when tailing", syntax.OpLimit, syntax.OpDedup)
+		}
+	}
 	req := logproto.TailRequest{
 		Query: qs,
 		Plan: &plan.QueryPlan{
@@ -102,5 +122,9 @@ func ParseTailQuery(r *http.Request) (*logproto.TailRequest, error) {
 	if req.DelayFor > maxDelayForInTailing {
 		return nil, fmt.Errorf("delay_for can't be greater than %d", maxDelayForInTailing)
 	}
+	req.Sample, err = tailSample(r)
+	if err != nil {
+		return nil, err
+	}
 	return &req, nil
 }
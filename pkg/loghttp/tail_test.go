@@ -32,12 +32,33 @@ func TestParseTailQuery(t *testing.T) {
 			&http.Request{
 				URL: mustParseURL(`?query={foo="bar"}&time=2016-06-10T21:42:24.760738998Z&limit=100&delay_for=20`),
 			}, nil, true},
+		{"stats stage",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"} | logfmt | stats count()&start=2017-06-10T21:42:24.760738998Z`),
+			}, nil, true},
+		{"around stage",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"} |= "error" | around 3&start=2017-06-10T21:42:24.760738998Z`),
+			}, nil, true},
+		{"limit stage",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"} | limit 3&start=2017-06-10T21:42:24.760738998Z`),
+			}, nil, true},
+		{"dedup stage",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"} | dedup by (pod)&start=2017-06-10T21:42:24.760738998Z`),
+			}, nil, true},
+		{"bad sample",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&sample=2`),
+			}, nil, true},
 		{"good",
 			&http.Request{
-				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&limit=1000&delay_for=5`),
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&limit=1000&delay_for=5&sample=0.01`),
 			}, &logproto.TailRequest{
 				Query:    `{foo="bar"}`,
 				DelayFor: 5,
+				Sample:   0.01,
 				Start:    time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
 				Limit:    1000,
 				Plan: &plan.QueryPlan{
@@ -13,6 +13,7 @@ type Stream = push.Stream
 type LabelAdapter = push.LabelAdapter
 type PushRequest = push.PushRequest
 type PushResponse = push.PushResponse
+type RejectedStream = push.RejectedStream
 type PusherClient = push.PusherClient
 type PusherServer = push.PusherServer
 
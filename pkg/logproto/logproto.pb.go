@@ -994,6 +994,8 @@ type TailRequest struct {
 	Limit    uint32                                                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 	Start    time.Time                                              `protobuf:"bytes,5,opt,name=start,proto3,stdtime" json:"start"`
 	Plan     *github_com_grafana_loki_v3_pkg_querier_plan.QueryPlan `protobuf:"bytes,6,opt,name=plan,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/plan.QueryPlan" json:"plan,omitempty"`
+	// sample is the fraction of the entries sent by the ingesters, all of them when 0.
+	Sample float64 `protobuf:"fixed64,7,opt,name=sample,proto3" json:"sample,omitempty"`
 }
 
 func (m *TailRequest) Reset()      { *m = TailRequest{} }
@@ -1057,9 +1059,23 @@ func (m *TailRequest) GetStart() time.Time {
 	return time.Time{}
 }
 
+func (m *TailRequest) GetSample() float64 {
+	if m != nil {
+		return m.Sample
+	}
+	return 0
+}
+
 type TailResponse struct {
 	Stream         *github_com_grafana_loki_pkg_push.Stream `protobuf:"bytes,1,opt,name=stream,proto3,customtype=github.com/grafana/loki/pkg/push.Stream" json:"stream,omitempty"`
 	DroppedStreams []*DroppedStream                         `protobuf:"bytes,2,rep,name=droppedStreams,proto3" json:"droppedStreams,omitempty"`
+	// sampledEntries, rateLimitedEntries and droppedEntries are the number of entries of the streams owned by
+	// the ingester its tailer did not send since the tail started, because they were sampled out, exceeded the
+	// rate limit, or were dropped while the querier was not receiving the responses. The stream is empty in the
+	// responses only sending these counts.
+	SampledEntries     uint64 `protobuf:"varint,3,opt,name=sampledEntries,proto3" json:"sampledEntries,omitempty"`
+	RateLimitedEntries uint64 `protobuf:"varint,4,opt,name=rateLimitedEntries,proto3" json:"rateLimitedEntries,omitempty"`
+	DroppedEntries     uint64 `protobuf:"varint,5,opt,name=droppedEntries,proto3" json:"droppedEntries,omitempty"`
 }
 
 func (m *TailResponse) Reset()      { *m = TailResponse{} }
@@ -1101,6 +1117,27 @@ func (m *TailResponse) GetDroppedStreams() []*DroppedStream {
 	return nil
 }
 
+func (m *TailResponse) GetSampledEntries() uint64 {
+	if m != nil {
+		return m.SampledEntries
+	}
+	return 0
+}
+
+func (m *TailResponse) GetRateLimitedEntries() uint64 {
+	if m != nil {
+		return m.RateLimitedEntries
+	}
+	return 0
+}
+
+func (m *TailResponse) GetDroppedEntries() uint64 {
+	if m != nil {
+		return m.DroppedEntries
+	}
+	return 0
+}
+
 type SeriesRequest struct {
 	Start  time.Time `protobuf:"bytes,1,opt,name=start,proto3,stdtime" json:"start"`
 	End    time.Time `protobuf:"bytes,2,opt,name=end,proto3,stdtime" json:"end"`
@@ -3058,6 +3095,212 @@ func (m *DetectedLabel) GetSketch() []byte {
 	return nil
 }
 
+// LookupRequest looks up the streams and the entries having a value of an
+// indexed structured metadata key.
+type LookupRequest struct {
+	Key       string    `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value     string    `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Start     time.Time `protobuf:"bytes,3,opt,name=start,proto3,stdtime" json:"start"`
+	End       time.Time `protobuf:"bytes,4,opt,name=end,proto3,stdtime" json:"end"`
+	Limit     uint32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Direction Direction `protobuf:"varint,6,opt,name=direction,proto3,enum=logproto.Direction" json:"direction,omitempty"`
+	Deletes   []*Delete `protobuf:"bytes,7,rep,name=deletes,proto3" json:"deletes,omitempty"`
+}
+
+func (m *LookupRequest) Reset()      { *m = LookupRequest{} }
+func (*LookupRequest) ProtoMessage() {}
+func (*LookupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{54}
+}
+func (m *LookupRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LookupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LookupRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LookupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupRequest.Merge(m, src)
+}
+func (m *LookupRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *LookupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LookupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LookupRequest proto.InternalMessageInfo
+
+func (m *LookupRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *LookupRequest) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *LookupRequest) GetStart() time.Time {
+	if m != nil {
+		return m.Start
+	}
+	return time.Time{}
+}
+
+func (m *LookupRequest) GetEnd() time.Time {
+	if m != nil {
+		return m.End
+	}
+	return time.Time{}
+}
+
+func (m *LookupRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *LookupRequest) GetDirection() Direction {
+	if m != nil {
+		return m.Direction
+	}
+	return FORWARD
+}
+
+func (m *LookupRequest) GetDeletes() []*Delete {
+	if m != nil {
+		return m.Deletes
+	}
+	return nil
+}
+
+type LookupResponse struct {
+	Refs    []LookupRef                               `protobuf:"bytes,1,rep,name=refs,proto3" json:"refs"`
+	Streams []github_com_grafana_loki_pkg_push.Stream `protobuf:"bytes,2,rep,name=streams,proto3,customtype=github.com/grafana/loki/pkg/push.Stream" json:"streams,omitempty"`
+}
+
+func (m *LookupResponse) Reset()      { *m = LookupResponse{} }
+func (*LookupResponse) ProtoMessage() {}
+func (*LookupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{55}
+}
+func (m *LookupResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LookupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LookupResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LookupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupResponse.Merge(m, src)
+}
+func (m *LookupResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *LookupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LookupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LookupResponse proto.InternalMessageInfo
+
+func (m *LookupResponse) GetRefs() []LookupRef {
+	if m != nil {
+		return m.Refs
+	}
+	return nil
+}
+
+// LookupRef is a stream having entries with the value looked up, between from
+// and through.
+type LookupRef struct {
+	Labels      string    `protobuf:"bytes,1,opt,name=labels,proto3" json:"labels,omitempty"`
+	Fingerprint uint64    `protobuf:"varint,2,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	From        time.Time `protobuf:"bytes,3,opt,name=from,proto3,stdtime" json:"from"`
+	Through     time.Time `protobuf:"bytes,4,opt,name=through,proto3,stdtime" json:"through"`
+}
+
+func (m *LookupRef) Reset()      { *m = LookupRef{} }
+func (*LookupRef) ProtoMessage() {}
+func (*LookupRef) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{56}
+}
+func (m *LookupRef) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LookupRef) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LookupRef.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LookupRef) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupRef.Merge(m, src)
+}
+func (m *LookupRef) XXX_Size() int {
+	return m.Size()
+}
+func (m *LookupRef) XXX_DiscardUnknown() {
+	xxx_messageInfo_LookupRef.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LookupRef proto.InternalMessageInfo
+
+func (m *LookupRef) GetLabels() string {
+	if m != nil {
+		return m.Labels
+	}
+	return ""
+}
+
+func (m *LookupRef) GetFingerprint() uint64 {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return 0
+}
+
+func (m *LookupRef) GetFrom() time.Time {
+	if m != nil {
+		return m.From
+	}
+	return time.Time{}
+}
+
+func (m *LookupRef) GetThrough() time.Time {
+	if m != nil {
+		return m.Through
+	}
+	return time.Time{}
+}
+
 func init() {
 	proto.RegisterEnum("logproto.Direction", Direction_name, Direction_value)
 	proto.RegisterType((*LabelToValuesResponse)(nil), "logproto.LabelToValuesResponse")
@@ -3116,180 +3359,193 @@ func init() {
 	proto.RegisterType((*DetectedLabelsRequest)(nil), "logproto.DetectedLabelsRequest")
 	proto.RegisterType((*DetectedLabelsResponse)(nil), "logproto.DetectedLabelsResponse")
 	proto.RegisterType((*DetectedLabel)(nil), "logproto.DetectedLabel")
+	proto.RegisterType((*LookupRequest)(nil), "logproto.LookupRequest")
+	proto.RegisterType((*LookupResponse)(nil), "logproto.LookupResponse")
+	proto.RegisterType((*LookupRef)(nil), "logproto.LookupRef")
 }
 
 func init() { proto.RegisterFile("pkg/logproto/logproto.proto", fileDescriptor_c28a5f14f1f4c79a) }
 
 var fileDescriptor_c28a5f14f1f4c79a = []byte{
-	// 2673 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x1a, 0x4d, 0x8c, 0x5b, 0x47,
-	0xd9, 0xcf, 0x7e, 0xfe, 0xfb, 0xec, 0xdd, 0x6c, 0x66, 0x9d, 0xc4, 0xda, 0xa4, 0x7e, 0xdb, 0x11,
-	0xb4, 0xa1, 0x49, 0xd7, 0x4d, 0x4a, 0x4b, 0x9a, 0x52, 0x4a, 0xbc, 0xdb, 0xa4, 0x49, 0xb7, 0x69,
-	0x3a, 0x9b, 0xa6, 0x05, 0x51, 0x55, 0x2f, 0xf6, 0xac, 0xf7, 0x29, 0xf6, 0x7b, 0xce, 0x7b, 0xe3,
-	0xa6, 0x7b, 0x43, 0xe2, 0x8c, 0xa8, 0xc4, 0x01, 0xb8, 0x20, 0x21, 0x21, 0x81, 0x40, 0xbd, 0x20,
-	0x4e, 0x08, 0xc1, 0x85, 0x43, 0xb9, 0xf5, 0x58, 0xf5, 0x60, 0xe8, 0xf6, 0x82, 0x56, 0x42, 0xaa,
-	0x84, 0xc4, 0xa1, 0x27, 0x34, 0x7f, 0xef, 0xcd, 0x7b, 0x6b, 0x93, 0x3a, 0x04, 0xb5, 0xb9, 0xd8,
-	0xf3, 0x7d, 0xf3, 0xcd, 0x37, 0xf3, 0xfd, 0xcc, 0xf7, 0x7d, 0xf3, 0xd9, 0x70, 0x7c, 0x74, 0xab,
-	0xdf, 0x1e, 0x04, 0xfd, 0x51, 0x18, 0xb0, 0x20, 0x1e, 0xac, 0x89, 0x4f, 0x54, 0xd1, 0xf0, 0x4a,
-	0xa3, 0x1f, 0xf4, 0x03, 0x49, 0xc3, 0x47, 0x72, 0x7e, 0xc5, 0xe9, 0x07, 0x41, 0x7f, 0x40, 0xdb,
-	0x02, 0xba, 0x39, 0xde, 0x6e, 0x33, 0x6f, 0x48, 0x23, 0xe6, 0x0e, 0x47, 0x8a, 0x60, 0x55, 0x71,
-	0xbf, 0x3d, 0x18, 0x06, 0x3d, 0x3a, 0x68, 0x47, 0xcc, 0x65, 0x91, 0xfc, 0x54, 0x14, 0xcb, 0x9c,
-	0x62, 0x34, 0x8e, 0x76, 0xc4, 0x87, 0x44, 0xe2, 0xdf, 0x5b, 0x70, 0x64, 0xd3, 0xbd, 0x49, 0x07,
-	0xd7, 0x83, 0x1b, 0xee, 0x60, 0x4c, 0x23, 0x42, 0xa3, 0x51, 0xe0, 0x47, 0x14, 0xad, 0x43, 0x69,
-	0xc0, 0x27, 0xa2, 0xa6, 0xb5, 0x5a, 0x38, 0x59, 0x3b, 0x7b, 0x6a, 0x2d, 0x3e, 0xf2, 0xd4, 0x05,
-	0x12, 0x1b, 0xbd, 0xe0, 0xb3, 0x70, 0x97, 0xa8, 0xa5, 0x2b, 0x37, 0xa0, 0x66, 0xa0, 0xd1, 0x12,
-	0x14, 0x6e, 0xd1, 0xdd, 0xa6, 0xb5, 0x6a, 0x9d, 0xac, 0x12, 0x3e, 0x44, 0x67, 0xa0, 0xf8, 0x36,
-	0x67, 0xd3, 0xcc, 0xaf, 0x5a, 0x27, 0x6b, 0x67, 0x8f, 0x27, 0x9b, 0xbc, 0xe6, 0x7b, 0xb7, 0xc7,
-	0x54, 0xac, 0x56, 0x1b, 0x49, 0xca, 0xf3, 0xf9, 0x73, 0x16, 0x3e, 0x05, 0x87, 0x0f, 0xcc, 0xa3,
-	0xa3, 0x50, 0x12, 0x14, 0xf2, 0xc4, 0x55, 0xa2, 0x20, 0xdc, 0x00, 0xb4, 0xc5, 0x42, 0xea, 0x0e,
-	0x89, 0xcb, 0xf8, 0x79, 0x6f, 0x8f, 0x69, 0xc4, 0xf0, 0xcb, 0xb0, 0x9c, 0xc2, 0x2a, 0xb1, 0x9f,
-	0x86, 0x5a, 0x94, 0xa0, 0x95, 0xec, 0x8d, 0xe4, 0x58, 0xc9, 0x1a, 0x62, 0x12, 0xe2, 0x9f, 0x5b,
-	0x00, 0xc9, 0x1c, 0x6a, 0x01, 0xc8, 0xd9, 0x17, 0xdd, 0x68, 0x47, 0x08, 0x6c, 0x13, 0x03, 0x83,
-	0x4e, 0xc3, 0xe1, 0x04, 0xba, 0x1a, 0x6c, 0xed, 0xb8, 0x61, 0x4f, 0xe8, 0xc0, 0x26, 0x07, 0x27,
-	0x10, 0x02, 0x3b, 0x74, 0x19, 0x6d, 0x16, 0x56, 0xad, 0x93, 0x05, 0x22, 0xc6, 0x5c, 0x5a, 0x46,
-	0x7d, 0xd7, 0x67, 0x4d, 0x5b, 0xa8, 0x53, 0x41, 0x1c, 0xcf, 0xed, 0x4b, 0xa3, 0x66, 0x71, 0xd5,
-	0x3a, 0xb9, 0x40, 0x14, 0x84, 0xff, 0x5d, 0x80, 0xfa, 0xab, 0x63, 0x1a, 0xee, 0x2a, 0x05, 0xa0,
-	0x16, 0x54, 0x22, 0x3a, 0xa0, 0x5d, 0x16, 0x84, 0xd2, 0x22, 0x9d, 0x7c, 0xd3, 0x22, 0x31, 0x0e,
-	0x35, 0xa0, 0x38, 0xf0, 0x86, 0x1e, 0x13, 0xc7, 0x5a, 0x20, 0x12, 0x40, 0xe7, 0xa1, 0x18, 0x31,
-	0x37, 0x64, 0xe2, 0x2c, 0xb5, 0xb3, 0x2b, 0x6b, 0xd2, 0x31, 0xd7, 0xb4, 0x63, 0xae, 0x5d, 0xd7,
-	0x8e, 0xd9, 0xa9, 0xbc, 0x3f, 0x71, 0x72, 0xef, 0xfe, 0xcd, 0xb1, 0x88, 0x5c, 0x82, 0x9e, 0x86,
-	0x02, 0xf5, 0x7b, 0xe2, 0xbc, 0x9f, 0x77, 0x25, 0x5f, 0x80, 0xce, 0x40, 0xb5, 0xe7, 0x85, 0xb4,
-	0xcb, 0xbc, 0xc0, 0x17, 0x52, 0x2d, 0x9e, 0x5d, 0x4e, 0x2c, 0xb2, 0xa1, 0xa7, 0x48, 0x42, 0x85,
-	0x4e, 0x43, 0x29, 0xe2, 0xaa, 0x8b, 0x9a, 0x65, 0xee, 0x0b, 0x9d, 0xc6, 0xfe, 0xc4, 0x59, 0x92,
-	0x98, 0xd3, 0xc1, 0xd0, 0x63, 0x74, 0x38, 0x62, 0xbb, 0x44, 0xd1, 0xa0, 0xc7, 0xa0, 0xdc, 0xa3,
-	0x03, 0xca, 0x0d, 0x5e, 0x11, 0x06, 0x5f, 0x32, 0xd8, 0x8b, 0x09, 0xa2, 0x09, 0xd0, 0x9b, 0x60,
-	0x8f, 0x06, 0xae, 0xdf, 0xac, 0x0a, 0x29, 0x16, 0x13, 0xc2, 0x6b, 0x03, 0xd7, 0xef, 0x3c, 0xf3,
-	0xd1, 0xc4, 0x79, 0xaa, 0xef, 0xb1, 0x9d, 0xf1, 0xcd, 0xb5, 0x6e, 0x30, 0x6c, 0xf7, 0x43, 0x77,
-	0xdb, 0xf5, 0xdd, 0xf6, 0x20, 0xb8, 0xe5, 0xb5, 0xdf, 0x7e, 0xb2, 0xcd, 0xef, 0xe0, 0xed, 0x31,
-	0x0d, 0x3d, 0x1a, 0xb6, 0x39, 0x9b, 0x35, 0x61, 0x12, 0xbe, 0x94, 0x08, 0xb6, 0xe8, 0x0a, 0xf7,
-	0xbf, 0x20, 0xa4, 0xeb, 0x3b, 0x63, 0xff, 0x56, 0xd4, 0x04, 0xb1, 0xcb, 0xb1, 0x64, 0x17, 0x81,
-	0x27, 0x74, 0xfb, 0x52, 0x18, 0x8c, 0x47, 0x9d, 0x43, 0xfb, 0x13, 0xc7, 0xa4, 0x27, 0x26, 0x70,
-	0xc5, 0xae, 0x94, 0x96, 0xca, 0xf8, 0xbd, 0x02, 0xa0, 0x2d, 0x77, 0x38, 0x1a, 0xd0, 0xb9, 0xcc,
-	0x1f, 0x1b, 0x3a, 0x7f, 0xcf, 0x86, 0x2e, 0xcc, 0x6b, 0xe8, 0xc4, 0x6a, 0xf6, 0x7c, 0x56, 0x2b,
-	0x7e, 0x5e, 0xab, 0x95, 0xbe, 0xf4, 0x56, 0xc3, 0x4d, 0xb0, 0x39, 0x67, 0x1e, 0x2c, 0x43, 0xf7,
-	0x8e, 0xb0, 0x4d, 0x9d, 0xf0, 0x21, 0xde, 0x84, 0x92, 0x94, 0x0b, 0xad, 0x64, 0x8d, 0x97, 0xbe,
-	0xb7, 0x89, 0xe1, 0x0a, 0xda, 0x24, 0x4b, 0x89, 0x49, 0x0a, 0x42, 0xd9, 0xf8, 0x8f, 0x16, 0x2c,
-	0x28, 0x8f, 0x50, 0xb1, 0xef, 0x26, 0x94, 0x65, 0xec, 0xd1, 0x71, 0xef, 0x58, 0x36, 0xee, 0x5d,
-	0xe8, 0xb9, 0x23, 0x46, 0xc3, 0x4e, 0xfb, 0xfd, 0x89, 0x63, 0x7d, 0x34, 0x71, 0x1e, 0x9d, 0xa5,
-	0x34, 0x9d, 0x6b, 0x74, 0xbc, 0xd4, 0x8c, 0xd1, 0x29, 0x71, 0x3a, 0x16, 0x29, 0xb7, 0x3a, 0xb4,
-	0x26, 0x53, 0xd4, 0x65, 0xbf, 0x4f, 0x23, 0xce, 0xd9, 0xe6, 0x1e, 0x41, 0x24, 0x0d, 0x17, 0xf3,
-	0x8e, 0x1b, 0xfa, 0x9e, 0xdf, 0x8f, 0x9a, 0x05, 0x11, 0xd3, 0x63, 0x18, 0xff, 0xd4, 0x82, 0xe5,
-	0x94, 0x5b, 0x2b, 0x21, 0xce, 0x41, 0x29, 0xe2, 0x96, 0xd2, 0x32, 0x18, 0x4e, 0xb1, 0x25, 0xf0,
-	0x9d, 0x45, 0x75, 0xf8, 0x92, 0x84, 0x89, 0xa2, 0xbf, 0x7f, 0x47, 0xfb, 0x8b, 0x05, 0x75, 0x91,
-	0x98, 0xf4, 0x5d, 0x43, 0x60, 0xfb, 0xee, 0x90, 0x2a, 0x53, 0x89, 0xb1, 0x91, 0xad, 0xf8, 0x76,
-	0x15, 0x9d, 0xad, 0xe6, 0x0d, 0xb0, 0xd6, 0x3d, 0x07, 0x58, 0x2b, 0xb9, 0x77, 0x0d, 0x28, 0x72,
-	0xf7, 0xde, 0x15, 0xc1, 0xb5, 0x4a, 0x24, 0x80, 0x1f, 0x85, 0x05, 0x25, 0x85, 0x52, 0xed, 0xac,
-	0x04, 0x3b, 0x84, 0x92, 0xb4, 0x04, 0xfa, 0x0a, 0x54, 0xe3, 0xc2, 0x44, 0x48, 0x5b, 0xe8, 0x94,
-	0xf6, 0x27, 0x4e, 0x9e, 0x45, 0x24, 0x99, 0x40, 0x8e, 0x99, 0xf4, 0xad, 0x4e, 0x75, 0x7f, 0xe2,
-	0x48, 0x84, 0x4a, 0xf1, 0xe8, 0x04, 0xd8, 0x3b, 0x3c, 0x6f, 0x72, 0x15, 0xd8, 0x9d, 0xca, 0xfe,
-	0xc4, 0x11, 0x30, 0x11, 0x9f, 0xf8, 0x12, 0xd4, 0x37, 0x69, 0xdf, 0xed, 0xee, 0xaa, 0x4d, 0x1b,
-	0x9a, 0x1d, 0xdf, 0xd0, 0xd2, 0x3c, 0x1e, 0x86, 0x7a, 0xbc, 0xe3, 0x5b, 0xc3, 0x48, 0xdd, 0x86,
-	0x5a, 0x8c, 0x7b, 0x39, 0xc2, 0x3f, 0xb3, 0x40, 0xf9, 0x00, 0xc2, 0x46, 0xb5, 0xc3, 0x63, 0x21,
-	0xec, 0x4f, 0x1c, 0x85, 0xd1, 0xc5, 0x0c, 0x7a, 0x16, 0xca, 0x91, 0xd8, 0x91, 0x33, 0xcb, 0xba,
-	0x96, 0x98, 0xe8, 0x1c, 0xe2, 0x2e, 0xb2, 0x3f, 0x71, 0x34, 0x21, 0xd1, 0x03, 0xb4, 0x96, 0x2a,
-	0x08, 0xa4, 0x60, 0x8b, 0xfb, 0x13, 0xc7, 0xc0, 0x9a, 0x05, 0x02, 0xfe, 0xcc, 0x82, 0xda, 0x75,
-	0xd7, 0x8b, 0x5d, 0xa8, 0xa9, 0x4d, 0x94, 0xc4, 0x6a, 0x89, 0xe0, 0x9e, 0xd8, 0xa3, 0x03, 0x77,
-	0xf7, 0x62, 0x10, 0x0a, 0xbe, 0x0b, 0x24, 0x86, 0x93, 0x1c, 0x6e, 0x4f, 0xcd, 0xe1, 0xc5, 0xf9,
-	0x43, 0xfb, 0xff, 0x37, 0x90, 0x5e, 0xb1, 0x2b, 0xf9, 0xa5, 0x02, 0x7e, 0xcf, 0x82, 0xba, 0x14,
-	0x5e, 0x79, 0xde, 0xf7, 0xa0, 0x24, 0x75, 0x23, 0xc4, 0xff, 0x2f, 0x81, 0xe9, 0xd4, 0x3c, 0x41,
-	0x49, 0xf1, 0x44, 0xcf, 0xc3, 0x62, 0x2f, 0x0c, 0x46, 0x23, 0xda, 0xdb, 0x52, 0xe1, 0x2f, 0x9f,
-	0x0d, 0x7f, 0x1b, 0xe6, 0x3c, 0xc9, 0x90, 0xe3, 0xbf, 0x5a, 0xb0, 0xa0, 0x82, 0x89, 0x32, 0x57,
-	0xac, 0x62, 0xeb, 0x9e, 0xb3, 0x67, 0x7e, 0xde, 0xec, 0x79, 0x14, 0x4a, 0x7d, 0x9e, 0x5f, 0x74,
-	0x40, 0x52, 0xd0, 0x7c, 0x59, 0x15, 0x5f, 0x81, 0x45, 0x2d, 0xca, 0x8c, 0x88, 0xba, 0x92, 0x8d,
-	0xa8, 0x97, 0x7b, 0xd4, 0x67, 0xde, 0xb6, 0x17, 0xc7, 0x48, 0x45, 0x8f, 0x7f, 0x64, 0xc1, 0x52,
-	0x96, 0x04, 0x6d, 0x64, 0x1e, 0x16, 0x8f, 0xcc, 0x66, 0x67, 0xbe, 0x29, 0x34, 0x6b, 0xf5, 0xb2,
-	0x78, 0xea, 0x6e, 0x2f, 0x8b, 0x86, 0x19, 0x64, 0xaa, 0x2a, 0x2a, 0xe0, 0x9f, 0x58, 0xb0, 0x90,
-	0xb2, 0x25, 0x3a, 0x07, 0xf6, 0x76, 0x18, 0x0c, 0xe7, 0x32, 0x94, 0x58, 0x81, 0xbe, 0x0e, 0x79,
-	0x16, 0xcc, 0x65, 0xa6, 0x3c, 0x0b, 0xb8, 0x95, 0x94, 0xf8, 0x05, 0x59, 0xb7, 0x4b, 0x08, 0x3f,
-	0x05, 0x55, 0x21, 0xd0, 0x35, 0xd7, 0x0b, 0xa7, 0x26, 0x8c, 0xe9, 0x02, 0x3d, 0x0b, 0x87, 0x64,
-	0x30, 0x9c, 0xbe, 0xb8, 0x3e, 0x6d, 0x71, 0x5d, 0x2f, 0x3e, 0x0e, 0x45, 0x51, 0x74, 0xf0, 0x25,
-	0x3d, 0x97, 0xb9, 0x7a, 0x09, 0x1f, 0xe3, 0x23, 0xb0, 0xcc, 0xef, 0x20, 0x0d, 0xa3, 0xf5, 0x60,
-	0xec, 0x33, 0xfd, 0x6e, 0x3a, 0x0d, 0x8d, 0x34, 0x5a, 0x79, 0x49, 0x03, 0x8a, 0x5d, 0x8e, 0x10,
-	0x3c, 0x16, 0x88, 0x04, 0xf0, 0x2f, 0x2d, 0x40, 0x97, 0x28, 0x13, 0xbb, 0x5c, 0xde, 0x88, 0xaf,
-	0xc7, 0x0a, 0x54, 0x86, 0x2e, 0xeb, 0xee, 0xd0, 0x30, 0xd2, 0xf5, 0x8b, 0x86, 0xbf, 0x88, 0xc2,
-	0x13, 0x9f, 0x81, 0xe5, 0xd4, 0x29, 0x95, 0x4c, 0x2b, 0x50, 0xe9, 0x2a, 0x9c, 0x4a, 0x79, 0x31,
-	0x8c, 0x7f, 0x97, 0x87, 0x8a, 0x2e, 0xeb, 0xd0, 0x19, 0xa8, 0x6d, 0x7b, 0x7e, 0x9f, 0x86, 0xa3,
-	0xd0, 0x53, 0x2a, 0xb0, 0x65, 0x99, 0x67, 0xa0, 0x89, 0x09, 0xa0, 0xc7, 0xa1, 0x3c, 0x8e, 0x68,
-	0xf8, 0x96, 0x27, 0x6f, 0x7a, 0xb5, 0xd3, 0xd8, 0x9b, 0x38, 0xa5, 0xd7, 0x22, 0x1a, 0x5e, 0xde,
-	0xe0, 0xc9, 0x67, 0x2c, 0x46, 0x44, 0x7e, 0xf7, 0xd0, 0x4b, 0xca, 0x4d, 0x45, 0x01, 0xd7, 0xf9,
-	0x06, 0x3f, 0x7e, 0x26, 0xd4, 0x8d, 0xc2, 0x60, 0x48, 0xd9, 0x0e, 0x1d, 0x47, 0xed, 0x6e, 0x30,
-	0x1c, 0x06, 0x7e, 0x5b, 0x74, 0x02, 0x84, 0xd0, 0x3c, 0x83, 0xf2, 0xe5, 0xca, 0x73, 0xaf, 0x43,
-	0x99, 0xed, 0x84, 0xc1, 0xb8, 0xbf, 0x23, 0x12, 0x43, 0xa1, 0x73, 0x7e, 0x7e, 0x7e, 0x9a, 0x03,
-	0xd1, 0x03, 0xf4, 0x30, 0xd7, 0x16, 0xed, 0xde, 0x8a, 0xc6, 0x43, 0xf9, 0xf6, 0xec, 0x14, 0xf7,
-	0x27, 0x8e, 0xf5, 0x38, 0x89, 0xd1, 0xf8, 0x02, 0x2c, 0xa4, 0x4a, 0x61, 0xf4, 0x04, 0xd8, 0x21,
-	0xdd, 0xd6, 0xa1, 0x00, 0x1d, 0xac, 0x98, 0x65, 0xf6, 0xe7, 0x34, 0x44, 0x7c, 0xe2, 0x1f, 0xe6,
-	0xc1, 0x31, 0x5e, 0xfd, 0x17, 0x83, 0xf0, 0x65, 0xca, 0x42, 0xaf, 0x7b, 0xd5, 0x1d, 0x52, 0xed,
-	0x5e, 0x0e, 0xd4, 0x86, 0x02, 0xf9, 0x96, 0x71, 0x8b, 0x60, 0x18, 0xd3, 0xa1, 0x87, 0x00, 0xc4,
-	0xb5, 0x93, 0xf3, 0xf2, 0x42, 0x55, 0x05, 0x46, 0x4c, 0xaf, 0xa7, 0x94, 0xdd, 0x9e, 0x53, 0x39,
-	0x4a, 0xc9, 0x97, 0xb3, 0x4a, 0x9e, 0x9b, 0x4f, 0xac, 0x59, 0xf3, 0xba, 0x14, 0xd3, 0xd7, 0x05,
-	0xff, 0xd3, 0x82, 0xd6, 0xa6, 0x3e, 0xf9, 0x3d, 0xaa, 0x43, 0xcb, 0x9b, 0xbf, 0x4f, 0xf2, 0x16,
-	0xee, 0xa3, 0xbc, 0x76, 0x46, 0xde, 0x16, 0xc0, 0xa6, 0xe7, 0xd3, 0x8b, 0xde, 0x80, 0xd1, 0x70,
-	0xca, 0x23, 0xe9, 0xc7, 0x85, 0x24, 0xe2, 0x10, 0xba, 0xad, 0x75, 0xb0, 0x6e, 0x84, 0xf9, 0xfb,
-	0x21, 0x62, 0xfe, 0x3e, 0x8a, 0x58, 0xc8, 0x44, 0x40, 0x1f, 0xca, 0xdb, 0x42, 0x3c, 0x99, 0xb1,
-	0x53, 0xfd, 0xa7, 0x44, 0xf6, 0xce, 0xb7, 0xd4, 0xe6, 0x4f, 0xdf, 0xa5, 0xe0, 0x12, 0x5d, 0xc1,
-	0x76, 0xb4, 0xeb, 0x33, 0xf7, 0x1d, 0x63, 0x3d, 0xd1, 0x9b, 0x20, 0x57, 0xd5, 0x74, 0xc5, 0xa9,
-	0x35, 0xdd, 0x73, 0x6a, 0x9b, 0xff, 0xa5, 0xae, 0xc3, 0xcf, 0x25, 0x01, 0x56, 0x18, 0x45, 0x05,
-	0xd8, 0x47, 0xee, 0x76, 0xfd, 0xd5, 0xa5, 0xff, 0x93, 0x05, 0x4b, 0x97, 0x28, 0x4b, 0xd7, 0x58,
-	0x0f, 0x90, 0x49, 0xf1, 0x8b, 0x70, 0xd8, 0x38, 0xbf, 0x92, 0xfe, 0xc9, 0x4c, 0x61, 0x75, 0x24,
-	0x91, 0xff, 0xb2, 0xdf, 0xa3, 0xef, 0xa8, 0xf7, 0x6a, 0xba, 0xa6, 0xba, 0x06, 0x35, 0x63, 0x12,
-	0x5d, 0xc8, 0x54, 0x53, 0xcb, 0x99, 0x36, 0x2d, 0xaf, 0x08, 0x3a, 0x0d, 0x25, 0x93, 0x7c, 0x95,
-	0xaa, 0x5a, 0x39, 0xae, 0x3c, 0xb6, 0x00, 0x09, 0x73, 0x09, 0xb6, 0x66, 0xee, 0x13, 0xd8, 0x97,
-	0xe2, 0xb2, 0x2a, 0x86, 0xd1, 0xc3, 0x60, 0x87, 0xc1, 0x1d, 0x5d, 0x26, 0x2f, 0x24, 0x5b, 0x92,
-	0xe0, 0x0e, 0x11, 0x53, 0xf8, 0x59, 0x28, 0x90, 0xe0, 0x0e, 0x6a, 0x01, 0x84, 0xae, 0xdf, 0xa7,
-	0x37, 0xe2, 0x07, 0x5a, 0x9d, 0x18, 0x98, 0x19, 0x75, 0xc9, 0x3a, 0x1c, 0x36, 0x4f, 0x24, 0xcd,
-	0xbd, 0x06, 0xe5, 0x57, 0xc7, 0xa6, 0xba, 0x1a, 0x19, 0x75, 0xc9, 0x3e, 0x80, 0x26, 0xe2, 0x3e,
-	0x03, 0x09, 0x1e, 0x9d, 0x80, 0x2a, 0x73, 0x6f, 0x0e, 0xe8, 0xd5, 0x24, 0x04, 0x26, 0x08, 0x3e,
-	0xcb, 0xdf, 0x96, 0x37, 0x8c, 0x02, 0x2b, 0x41, 0xa0, 0xc7, 0x60, 0x29, 0x39, 0xf3, 0xb5, 0x90,
-	0x6e, 0x7b, 0xef, 0x08, 0x0b, 0xd7, 0xc9, 0x01, 0x3c, 0x3a, 0x09, 0x87, 0x12, 0xdc, 0x96, 0x28,
-	0x64, 0x6c, 0x41, 0x9a, 0x45, 0x73, 0xdd, 0x08, 0x71, 0x5f, 0xb8, 0x3d, 0x76, 0x07, 0xe2, 0xf2,
-	0xd5, 0x89, 0x81, 0xc1, 0x7f, 0xb6, 0xe0, 0xb0, 0x34, 0x35, 0x73, 0xd9, 0x03, 0xe9, 0xf5, 0xbf,
-	0xb2, 0x00, 0x99, 0x12, 0x28, 0xd7, 0xfa, 0xaa, 0xd9, 0x67, 0xe2, 0x95, 0x52, 0x4d, 0x3c, 0x99,
-	0x25, 0x2a, 0x69, 0x15, 0x61, 0x28, 0x75, 0x65, 0x3f, 0x4d, 0x34, 0xc6, 0xe5, 0x9b, 0x5c, 0x62,
-	0x88, 0xfa, 0x46, 0x0e, 0x14, 0x6f, 0xee, 0x32, 0x1a, 0xa9, 0x17, 0xb5, 0x68, 0x25, 0x08, 0x04,
-	0x91, 0x5f, 0x7c, 0x2f, 0xea, 0x33, 0xe1, 0x35, 0x76, 0xb2, 0x97, 0x42, 0x11, 0x3d, 0xc0, 0xbf,
-	0xcd, 0xc3, 0xc2, 0x8d, 0x60, 0x30, 0x4e, 0x92, 0xe6, 0x83, 0x94, 0x30, 0x52, 0xcf, 0xfc, 0xa2,
-	0x7e, 0xe6, 0x23, 0xb0, 0x23, 0x46, 0x47, 0xc2, 0xb3, 0x0a, 0x44, 0x8c, 0x11, 0x86, 0x3a, 0x73,
-	0xc3, 0x3e, 0x65, 0xf2, 0xf1, 0xd4, 0x2c, 0x89, 0xaa, 0x36, 0x85, 0x43, 0xab, 0x50, 0x73, 0xfb,
-	0xfd, 0x90, 0xf6, 0x5d, 0x46, 0x3b, 0xbb, 0xcd, 0xb2, 0xd8, 0xcc, 0x44, 0xe1, 0x37, 0x60, 0x51,
-	0x2b, 0x4b, 0x99, 0xf4, 0x09, 0x28, 0xbf, 0x2d, 0x30, 0x53, 0xda, 0x6e, 0x92, 0x54, 0x85, 0x31,
-	0x4d, 0x96, 0xfe, 0x79, 0x41, 0x9f, 0x19, 0x5f, 0x81, 0x92, 0x24, 0x47, 0x27, 0xcc, 0x27, 0x90,
-	0xac, 0x02, 0x39, 0xac, 0xde, 0x33, 0x18, 0x4a, 0x92, 0x91, 0x32, 0xbc, 0xf0, 0x0d, 0x89, 0x21,
-	0xea, 0x1b, 0xff, 0xcb, 0x82, 0x23, 0x1b, 0x94, 0xd1, 0x2e, 0xa3, 0xbd, 0x8b, 0x1e, 0x1d, 0xf4,
-	0xbe, 0xd0, 0xd7, 0x79, 0xdc, 0x63, 0x2b, 0x18, 0x3d, 0x36, 0x1e, 0x77, 0x06, 0x9e, 0x4f, 0x37,
-	0x8d, 0x26, 0x4d, 0x82, 0xe0, 0x11, 0x62, 0x9b, 0x1f, 0x5c, 0x4e, 0xcb, 0xdf, 0x73, 0x0c, 0x4c,
-	0x6c, 0xe1, 0x52, 0x62, 0x61, 0xfc, 0x03, 0x0b, 0x8e, 0x66, 0xa5, 0x56, 0x46, 0x6a, 0x43, 0x49,
-	0x2c, 0x9e, 0xd2, 0xde, 0x4d, 0xad, 0x20, 0x8a, 0x0c, 0x9d, 0x4b, 0xed, 0x2f, 0x7e, 0x07, 0xea,
-	0x34, 0xf7, 0x27, 0x4e, 0x23, 0xc1, 0x1a, 0x1d, 0x04, 0x83, 0x16, 0xff, 0x81, 0xbf, 0xb3, 0x4d,
-	0x9e, 0xc2, 0xde, 0xdc, 0xbf, 0x54, 0xec, 0x95, 0x00, 0xfa, 0x1a, 0xd8, 0x6c, 0x77, 0xa4, 0x42,
-	0x6e, 0xe7, 0xc8, 0x67, 0x13, 0xe7, 0x70, 0x6a, 0xd9, 0xf5, 0xdd, 0x11, 0x25, 0x82, 0x84, 0xbb,
-	0x65, 0xd7, 0x0d, 0x7b, 0x9e, 0xef, 0x0e, 0x3c, 0x26, 0xd5, 0x68, 0x13, 0x13, 0x85, 0x9a, 0x50,
-	0x1e, 0xb9, 0x61, 0xa4, 0xeb, 0xa6, 0x2a, 0xd1, 0xa0, 0x68, 0x81, 0xdc, 0xa2, 0xac, 0xbb, 0x23,
-	0xc3, 0xac, 0x6a, 0x81, 0x08, 0x4c, 0xaa, 0x05, 0x22, 0x30, 0xf8, 0x17, 0x86, 0xe3, 0xc8, 0x3b,
-	0xf1, 0xa5, 0x73, 0x1c, 0xfc, 0x9d, 0xc4, 0xca, 0xfa, 0x88, 0xca, 0xca, 0xcf, 0xc3, 0x62, 0x2f,
-	0x35, 0x33, 0xdb, 0xda, 0xb2, 0xbd, 0x9b, 0x21, 0xc7, 0xe3, 0xc4, 0x74, 0x02, 0x33, 0xc3, 0x74,
-	0x19, 0x7b, 0xe4, 0x0f, 0xda, 0x23, 0xd1, 0x7a, 0xe1, 0xee, 0x5a, 0x7f, 0xec, 0x11, 0xa8, 0xc6,
-	0x3f, 0xe5, 0xa1, 0x1a, 0x94, 0x2f, 0xbe, 0x42, 0x5e, 0xbf, 0x40, 0x36, 0x96, 0x72, 0xa8, 0x0e,
-	0x95, 0xce, 0x85, 0xf5, 0x97, 0x04, 0x64, 0x9d, 0xfd, 0x4d, 0x49, 0x17, 0x02, 0x21, 0xfa, 0x26,
-	0x14, 0x65, 0x76, 0x3f, 0x9a, 0x08, 0x67, 0xfe, 0xca, 0xb5, 0x72, 0xec, 0x00, 0x5e, 0x6a, 0x09,
-	0xe7, 0x9e, 0xb0, 0xd0, 0x55, 0xa8, 0x09, 0xa4, 0xea, 0x23, 0x9f, 0xc8, 0xb6, 0x73, 0x53, 0x9c,
-	0x1e, 0x9a, 0x31, 0x6b, 0xf0, 0x3b, 0x0f, 0x45, 0xa9, 0xb0, 0xa3, 0x99, 0x22, 0x6c, 0xca, 0x69,
-	0x52, 0x9d, 0x75, 0x9c, 0x43, 0xcf, 0x80, 0x7d, 0xdd, 0xf5, 0x06, 0xc8, 0xa8, 0x01, 0x8d, 0xf6,
-	0xef, 0xca, 0xd1, 0x2c, 0xda, 0xd8, 0xf6, 0xb9, 0xb8, 0x8b, 0x7d, 0x2c, 0xdb, 0x4a, 0xd3, 0xcb,
-	0x9b, 0x07, 0x27, 0xe2, 0x9d, 0x5f, 0x91, 0xbd, 0x56, 0xdd, 0xd0, 0x41, 0x0f, 0xa5, 0xb7, 0xca,
-	0xf4, 0x7f, 0x56, 0x5a, 0xb3, 0xa6, 0x63, 0x86, 0x9b, 0x50, 0x33, 0x9a, 0x29, 0xa6, 0x5a, 0x0f,
-	0x76, 0x82, 0x4c, 0xb5, 0x4e, 0xe9, 0xc0, 0xe0, 0x1c, 0xba, 0x04, 0x15, 0x5e, 0x39, 0x8b, 0x1f,
-	0x5d, 0x8e, 0x67, 0x0b, 0x64, 0xa3, 0x30, 0x5a, 0x39, 0x31, 0x7d, 0x32, 0x66, 0xf4, 0x6d, 0xa8,
-	0x5e, 0xa2, 0x4c, 0x65, 0x97, 0x63, 0xd9, 0xf4, 0x34, 0x45, 0x53, 0xe9, 0x14, 0x87, 0x73, 0xe8,
-	0x0d, 0x51, 0xc4, 0xa7, 0x83, 0x2b, 0x72, 0x66, 0x04, 0xd1, 0xf8, 0x5c, 0xab, 0xb3, 0x09, 0x62,
-	0xce, 0xaf, 0xa7, 0x38, 0xab, 0x3c, 0xec, 0xcc, 0xb8, 0xb0, 0x31, 0x67, 0xe7, 0x2e, 0x7f, 0xc9,
-	0xc0, 0xb9, 0xb3, 0x6f, 0xea, 0x7f, 0x25, 0x6c, 0xb8, 0xcc, 0x45, 0xaf, 0xc0, 0xa2, 0xd0, 0x65,
-	0xfc, 0xb7, 0x85, 0x94, 0xcf, 0x1f, 0xf8, 0x8f, 0x44, 0xca, 0xe7, 0x0f, 0xfe, 0x57, 0x02, 0xe7,
-	0x3a, 0x6f, 0x7e, 0xf0, 0x71, 0x2b, 0xf7, 0xe1, 0xc7, 0xad, 0xdc, 0xa7, 0x1f, 0xb7, 0xac, 0xef,
-	0xef, 0xb5, 0xac, 0x5f, 0xef, 0xb5, 0xac, 0xf7, 0xf7, 0x5a, 0xd6, 0x07, 0x7b, 0x2d, 0xeb, 0xef,
-	0x7b, 0x2d, 0xeb, 0x1f, 0x7b, 0xad, 0xdc, 0xa7, 0x7b, 0x2d, 0xeb, 0xdd, 0x4f, 0x5a, 0xb9, 0x0f,
-	0x3e, 0x69, 0xe5, 0x3e, 0xfc, 0xa4, 0x95, 0xfb, 0xee, 0xa3, 0x77, 0x7f, 0xb0, 0xca, 0xb0, 0x58,
-	0x12, 0x5f, 0x4f, 0xfe, 0x27, 0x00, 0x00, 0xff, 0xff, 0xb0, 0x55, 0xf6, 0x50, 0x3b, 0x23, 0x00,
-	0x00,
+	// 2841 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x1a, 0x4b, 0x8c, 0x1c, 0x47,
+	0x75, 0x7a, 0xfe, 0xf3, 0x66, 0x76, 0xbd, 0xae, 0x1d, 0xaf, 0x47, 0x6b, 0x67, 0x7a, 0x53, 0x02,
+	0xc7, 0xc4, 0xce, 0x4e, 0xec, 0x90, 0xe0, 0x38, 0x24, 0xc1, 0xb3, 0x1b, 0x3b, 0x76, 0x36, 0x8e,
+	0x53, 0xeb, 0x38, 0x01, 0x11, 0x45, 0xed, 0x99, 0xda, 0xd9, 0xd6, 0xce, 0x74, 0x8f, 0xbb, 0x6b,
+	0xe2, 0xcc, 0x0d, 0x89, 0x33, 0x22, 0x82, 0x03, 0x70, 0x41, 0x20, 0x90, 0x40, 0x42, 0xb9, 0x20,
+	0x4e, 0x08, 0xc1, 0x85, 0x43, 0xb8, 0xe5, 0x18, 0xe5, 0x30, 0x90, 0xcd, 0x05, 0x56, 0x42, 0x8a,
+	0x84, 0xc4, 0x81, 0x13, 0xaa, 0x5f, 0x77, 0x75, 0xef, 0x2c, 0xf6, 0x18, 0x47, 0x89, 0x2f, 0x33,
+	0x55, 0xaf, 0x5e, 0xbd, 0xaa, 0xf7, 0xa9, 0x57, 0xef, 0xbd, 0x6a, 0x38, 0x36, 0xdc, 0xe9, 0xb5,
+	0xfa, 0x7e, 0x6f, 0x18, 0xf8, 0xcc, 0x8f, 0x1a, 0xab, 0xe2, 0x17, 0x95, 0x75, 0x7f, 0xb9, 0xde,
+	0xf3, 0x7b, 0xbe, 0xc4, 0xe1, 0x2d, 0x39, 0xbe, 0x6c, 0xf7, 0x7c, 0xbf, 0xd7, 0xa7, 0x2d, 0xd1,
+	0xbb, 0x39, 0xda, 0x6a, 0x31, 0x77, 0x40, 0x43, 0xe6, 0x0c, 0x86, 0x0a, 0x61, 0x45, 0x51, 0xbf,
+	0xd5, 0x1f, 0xf8, 0x5d, 0xda, 0x6f, 0x85, 0xcc, 0x61, 0xa1, 0xfc, 0x55, 0x18, 0x8b, 0x1c, 0x63,
+	0x38, 0x0a, 0xb7, 0xc5, 0x8f, 0x04, 0xe2, 0xdf, 0x59, 0x70, 0x64, 0xc3, 0xb9, 0x49, 0xfb, 0xd7,
+	0xfd, 0x1b, 0x4e, 0x7f, 0x44, 0x43, 0x42, 0xc3, 0xa1, 0xef, 0x85, 0x14, 0xad, 0x41, 0xb1, 0xcf,
+	0x07, 0xc2, 0x86, 0xb5, 0x92, 0x3b, 0x59, 0x3d, 0x7b, 0x6a, 0x35, 0xda, 0xf2, 0xd4, 0x09, 0x12,
+	0x1a, 0xbe, 0xe0, 0xb1, 0x60, 0x4c, 0xd4, 0xd4, 0xe5, 0x1b, 0x50, 0x35, 0xc0, 0x68, 0x01, 0x72,
+	0x3b, 0x74, 0xdc, 0xb0, 0x56, 0xac, 0x93, 0x15, 0xc2, 0x9b, 0xe8, 0x0c, 0x14, 0xde, 0xe6, 0x64,
+	0x1a, 0xd9, 0x15, 0xeb, 0x64, 0xf5, 0xec, 0xb1, 0x78, 0x91, 0xd7, 0x3c, 0xf7, 0xd6, 0x88, 0x8a,
+	0xd9, 0x6a, 0x21, 0x89, 0x79, 0x3e, 0x7b, 0xce, 0xc2, 0xa7, 0xe0, 0xf0, 0xbe, 0x71, 0xb4, 0x04,
+	0x45, 0x81, 0x21, 0x77, 0x5c, 0x21, 0xaa, 0x87, 0xeb, 0x80, 0x36, 0x59, 0x40, 0x9d, 0x01, 0x71,
+	0x18, 0xdf, 0xef, 0xad, 0x11, 0x0d, 0x19, 0x7e, 0x19, 0x16, 0x13, 0x50, 0xc5, 0xf6, 0x53, 0x50,
+	0x0d, 0x63, 0xb0, 0xe2, 0xbd, 0x1e, 0x6f, 0x2b, 0x9e, 0x43, 0x4c, 0x44, 0xfc, 0x53, 0x0b, 0x20,
+	0x1e, 0x43, 0x4d, 0x00, 0x39, 0xfa, 0xa2, 0x13, 0x6e, 0x0b, 0x86, 0xf3, 0xc4, 0x80, 0xa0, 0xd3,
+	0x70, 0x38, 0xee, 0x5d, 0xf5, 0x37, 0xb7, 0x9d, 0xa0, 0x2b, 0x64, 0x90, 0x27, 0xfb, 0x07, 0x10,
+	0x82, 0x7c, 0xe0, 0x30, 0xda, 0xc8, 0xad, 0x58, 0x27, 0x73, 0x44, 0xb4, 0x39, 0xb7, 0x8c, 0x7a,
+	0x8e, 0xc7, 0x1a, 0x79, 0x21, 0x4e, 0xd5, 0xe3, 0x70, 0xae, 0x5f, 0x1a, 0x36, 0x0a, 0x2b, 0xd6,
+	0xc9, 0x39, 0xa2, 0x7a, 0xf8, 0xdf, 0x39, 0xa8, 0xbd, 0x3a, 0xa2, 0xc1, 0x58, 0x09, 0x00, 0x35,
+	0xa1, 0x1c, 0xd2, 0x3e, 0xed, 0x30, 0x3f, 0x90, 0x1a, 0x69, 0x67, 0x1b, 0x16, 0x89, 0x60, 0xa8,
+	0x0e, 0x85, 0xbe, 0x3b, 0x70, 0x99, 0xd8, 0xd6, 0x1c, 0x91, 0x1d, 0x74, 0x1e, 0x0a, 0x21, 0x73,
+	0x02, 0x26, 0xf6, 0x52, 0x3d, 0xbb, 0xbc, 0x2a, 0x0d, 0x73, 0x55, 0x1b, 0xe6, 0xea, 0x75, 0x6d,
+	0x98, 0xed, 0xf2, 0xfb, 0x13, 0x3b, 0xf3, 0xee, 0x5f, 0x6d, 0x8b, 0xc8, 0x29, 0xe8, 0x29, 0xc8,
+	0x51, 0xaf, 0x2b, 0xf6, 0x7b, 0xb7, 0x33, 0xf9, 0x04, 0x74, 0x06, 0x2a, 0x5d, 0x37, 0xa0, 0x1d,
+	0xe6, 0xfa, 0x9e, 0xe0, 0x6a, 0xfe, 0xec, 0x62, 0xac, 0x91, 0x75, 0x3d, 0x44, 0x62, 0x2c, 0x74,
+	0x1a, 0x8a, 0x21, 0x17, 0x5d, 0xd8, 0x28, 0x71, 0x5b, 0x68, 0xd7, 0xf7, 0x26, 0xf6, 0x82, 0x84,
+	0x9c, 0xf6, 0x07, 0x2e, 0xa3, 0x83, 0x21, 0x1b, 0x13, 0x85, 0x83, 0x1e, 0x85, 0x52, 0x97, 0xf6,
+	0x29, 0x57, 0x78, 0x59, 0x28, 0x7c, 0xc1, 0x20, 0x2f, 0x06, 0x88, 0x46, 0x40, 0x6f, 0x42, 0x7e,
+	0xd8, 0x77, 0xbc, 0x46, 0x45, 0x70, 0x31, 0x1f, 0x23, 0x5e, 0xeb, 0x3b, 0x5e, 0xfb, 0xe9, 0x8f,
+	0x26, 0xf6, 0x93, 0x3d, 0x97, 0x6d, 0x8f, 0x6e, 0xae, 0x76, 0xfc, 0x41, 0xab, 0x17, 0x38, 0x5b,
+	0x8e, 0xe7, 0xb4, 0xfa, 0xfe, 0x8e, 0xdb, 0x7a, 0xfb, 0x89, 0x16, 0x3f, 0x83, 0xb7, 0x46, 0x34,
+	0x70, 0x69, 0xd0, 0xe2, 0x64, 0x56, 0x85, 0x4a, 0xf8, 0x54, 0x22, 0xc8, 0xa2, 0x2b, 0xdc, 0xfe,
+	0xfc, 0x80, 0xae, 0x6d, 0x8f, 0xbc, 0x9d, 0xb0, 0x01, 0x62, 0x95, 0xa3, 0xf1, 0x2a, 0x02, 0x4e,
+	0xe8, 0xd6, 0xa5, 0xc0, 0x1f, 0x0d, 0xdb, 0x87, 0xf6, 0x26, 0xb6, 0x89, 0x4f, 0xcc, 0xce, 0x95,
+	0x7c, 0xb9, 0xb8, 0x50, 0xc2, 0xef, 0xe5, 0x00, 0x6d, 0x3a, 0x83, 0x61, 0x9f, 0xce, 0xa4, 0xfe,
+	0x48, 0xd1, 0xd9, 0x7b, 0x56, 0x74, 0x6e, 0x56, 0x45, 0xc7, 0x5a, 0xcb, 0xcf, 0xa6, 0xb5, 0xc2,
+	0xdd, 0x6a, 0xad, 0xf8, 0x85, 0xd7, 0x1a, 0x6e, 0x40, 0x9e, 0x53, 0xe6, 0xce, 0x32, 0x70, 0x6e,
+	0x0b, 0xdd, 0xd4, 0x08, 0x6f, 0xe2, 0x0d, 0x28, 0x4a, 0xbe, 0xd0, 0x72, 0x5a, 0x79, 0xc9, 0x73,
+	0x1b, 0x2b, 0x2e, 0xa7, 0x55, 0xb2, 0x10, 0xab, 0x24, 0x27, 0x84, 0x8d, 0xff, 0x60, 0xc1, 0x9c,
+	0xb2, 0x08, 0xe5, 0xfb, 0x6e, 0x42, 0x49, 0xfa, 0x1e, 0xed, 0xf7, 0x8e, 0xa6, 0xfd, 0xde, 0x85,
+	0xae, 0x33, 0x64, 0x34, 0x68, 0xb7, 0xde, 0x9f, 0xd8, 0xd6, 0x47, 0x13, 0xfb, 0x91, 0x83, 0x84,
+	0xa6, 0xef, 0x1a, 0xed, 0x2f, 0x35, 0x61, 0x74, 0x4a, 0xec, 0x8e, 0x85, 0xca, 0xac, 0x0e, 0xad,
+	0xca, 0x2b, 0xea, 0xb2, 0xd7, 0xa3, 0x21, 0xa7, 0x9c, 0xe7, 0x16, 0x41, 0x24, 0x0e, 0x67, 0xf3,
+	0xb6, 0x13, 0x78, 0xae, 0xd7, 0x0b, 0x1b, 0x39, 0xe1, 0xd3, 0xa3, 0x3e, 0xfe, 0xb1, 0x05, 0x8b,
+	0x09, 0xb3, 0x56, 0x4c, 0x9c, 0x83, 0x62, 0xc8, 0x35, 0xa5, 0x79, 0x30, 0x8c, 0x62, 0x53, 0xc0,
+	0xdb, 0xf3, 0x6a, 0xf3, 0x45, 0xd9, 0x27, 0x0a, 0xff, 0xfe, 0x6d, 0xed, 0xcf, 0x16, 0xd4, 0xc4,
+	0xc5, 0xa4, 0xcf, 0x1a, 0x82, 0xbc, 0xe7, 0x0c, 0xa8, 0x52, 0x95, 0x68, 0x1b, 0xb7, 0x15, 0x5f,
+	0xae, 0xac, 0x6f, 0xab, 0x59, 0x1d, 0xac, 0x75, 0xcf, 0x0e, 0xd6, 0x8a, 0xcf, 0x5d, 0x1d, 0x0a,
+	0xdc, 0xbc, 0xc7, 0xc2, 0xb9, 0x56, 0x88, 0xec, 0xe0, 0x47, 0x60, 0x4e, 0x71, 0xa1, 0x44, 0x7b,
+	0xd0, 0x05, 0x3b, 0x80, 0xa2, 0xd4, 0x04, 0xfa, 0x12, 0x54, 0xa2, 0xc0, 0x44, 0x70, 0x9b, 0x6b,
+	0x17, 0xf7, 0x26, 0x76, 0x96, 0x85, 0x24, 0x1e, 0x40, 0xb6, 0x79, 0xe9, 0x5b, 0xed, 0xca, 0xde,
+	0xc4, 0x96, 0x00, 0x75, 0xc5, 0xa3, 0xe3, 0x90, 0xdf, 0xe6, 0xf7, 0x26, 0x17, 0x41, 0xbe, 0x5d,
+	0xde, 0x9b, 0xd8, 0xa2, 0x4f, 0xc4, 0x2f, 0xbe, 0x04, 0xb5, 0x0d, 0xda, 0x73, 0x3a, 0x63, 0xb5,
+	0x68, 0x5d, 0x93, 0xe3, 0x0b, 0x5a, 0x9a, 0xc6, 0xc3, 0x50, 0x8b, 0x56, 0x7c, 0x6b, 0x10, 0xaa,
+	0xd3, 0x50, 0x8d, 0x60, 0x2f, 0x87, 0xf8, 0x27, 0x16, 0x28, 0x1b, 0x40, 0xd8, 0x88, 0x76, 0xb8,
+	0x2f, 0x84, 0xbd, 0x89, 0xad, 0x20, 0x3a, 0x98, 0x41, 0xcf, 0x40, 0x29, 0x14, 0x2b, 0x72, 0x62,
+	0x69, 0xd3, 0x12, 0x03, 0xed, 0x43, 0xdc, 0x44, 0xf6, 0x26, 0xb6, 0x46, 0x24, 0xba, 0x81, 0x56,
+	0x13, 0x01, 0x81, 0x64, 0x6c, 0x7e, 0x6f, 0x62, 0x1b, 0x50, 0x33, 0x40, 0xc0, 0x3f, 0xc8, 0x42,
+	0xf5, 0xba, 0xe3, 0x46, 0x26, 0xd4, 0xd0, 0x2a, 0x8a, 0x7d, 0xb5, 0x04, 0x70, 0x4b, 0xec, 0xd2,
+	0xbe, 0x33, 0xbe, 0xe8, 0x07, 0x82, 0xee, 0x1c, 0x89, 0xfa, 0xf1, 0x1d, 0x9e, 0x9f, 0x7a, 0x87,
+	0x17, 0x66, 0x77, 0xed, 0x9f, 0xb1, 0x23, 0x5d, 0x82, 0xa2, 0x94, 0x58, 0xa3, 0x24, 0x94, 0xa9,
+	0x7a, 0x57, 0xf2, 0xe5, 0xec, 0x42, 0x0e, 0xbf, 0x97, 0x85, 0x9a, 0x14, 0x8a, 0xb2, 0xc8, 0x6f,
+	0x43, 0x51, 0xca, 0x4c, 0x88, 0xe5, 0x7f, 0x38, 0xac, 0x53, 0xb3, 0x38, 0x2b, 0x45, 0x13, 0x3d,
+	0x0f, 0xf3, 0xdd, 0xc0, 0x1f, 0x0e, 0x69, 0x77, 0x53, 0xb9, 0xc5, 0x6c, 0xda, 0x2d, 0xae, 0x9b,
+	0xe3, 0x24, 0x85, 0x8e, 0x4e, 0xc0, 0xbc, 0xdc, 0x7f, 0x97, 0xc7, 0xbf, 0xdc, 0x27, 0x09, 0xc5,
+	0x93, 0x14, 0x14, 0xad, 0x02, 0xe2, 0x31, 0xdd, 0x06, 0xd7, 0x4e, 0x8c, 0x9b, 0x17, 0xb8, 0x53,
+	0x46, 0x38, 0x5d, 0xb5, 0x92, 0xc6, 0x2d, 0x48, 0xba, 0x49, 0x28, 0xfe, 0x8b, 0x05, 0x73, 0xca,
+	0xc9, 0x29, 0x33, 0x8a, 0x54, 0x6f, 0xdd, 0xf3, 0xad, 0x9e, 0x9d, 0xf5, 0x56, 0x5f, 0x82, 0x62,
+	0x8f, 0xdf, 0x7b, 0xda, 0x51, 0xaa, 0xde, 0x6c, 0xb7, 0x3d, 0xbe, 0x02, 0xf3, 0x9a, 0x95, 0x03,
+	0x3c, 0xfd, 0x72, 0xda, 0xd3, 0x5f, 0xee, 0x52, 0x8f, 0xb9, 0x5b, 0x6e, 0xe4, 0xbb, 0x15, 0x3e,
+	0xfe, 0xbe, 0x05, 0x0b, 0x69, 0x14, 0xb4, 0x9e, 0x4a, 0x78, 0x4e, 0x1c, 0x4c, 0xce, 0xcc, 0x75,
+	0x34, 0x69, 0x95, 0xf1, 0x3c, 0x79, 0xa7, 0x8c, 0xa7, 0x6e, 0x3a, 0xbf, 0x8a, 0xf2, 0x56, 0xf8,
+	0x47, 0x16, 0xcc, 0x25, 0x6c, 0x09, 0x9d, 0x83, 0xfc, 0x56, 0xe0, 0x0f, 0x66, 0x52, 0x94, 0x98,
+	0x81, 0xbe, 0x0a, 0x59, 0xe6, 0xcf, 0xa4, 0xa6, 0x2c, 0xf3, 0xb9, 0x96, 0x14, 0xfb, 0x39, 0x99,
+	0x4f, 0xc8, 0x1e, 0x7e, 0x12, 0x2a, 0x82, 0xa1, 0x6b, 0x8e, 0x1b, 0x4c, 0xbd, 0xc8, 0xa6, 0x33,
+	0xf4, 0x0c, 0x1c, 0x92, 0x4e, 0x7a, 0xfa, 0xe4, 0xda, 0xb4, 0xc9, 0x35, 0x3d, 0xf9, 0x18, 0x14,
+	0x44, 0x30, 0xc4, 0xa7, 0x74, 0x1d, 0xe6, 0xe8, 0x29, 0xbc, 0x8d, 0x8f, 0xc0, 0x22, 0xf7, 0x01,
+	0x34, 0x08, 0xd7, 0xfc, 0x91, 0xc7, 0x74, 0x3e, 0x77, 0x1a, 0xea, 0x49, 0xb0, 0xb2, 0x92, 0x3a,
+	0x14, 0x3a, 0x1c, 0x20, 0x68, 0xcc, 0x11, 0xd9, 0xc1, 0xbf, 0xb4, 0x00, 0x5d, 0xa2, 0x4c, 0xac,
+	0x72, 0x79, 0x3d, 0x3a, 0x1e, 0xcb, 0x50, 0x1e, 0x38, 0xac, 0xb3, 0x4d, 0x83, 0x50, 0xc7, 0x55,
+	0xba, 0xff, 0x79, 0x04, 0xc4, 0xf8, 0x0c, 0x2c, 0x26, 0x76, 0xa9, 0x78, 0x5a, 0x86, 0x72, 0x47,
+	0xc1, 0xd4, 0x55, 0x1c, 0xf5, 0xf1, 0x6f, 0xb3, 0x50, 0xd6, 0xe1, 0x26, 0x3a, 0x03, 0xd5, 0x2d,
+	0xd7, 0xeb, 0xd1, 0x60, 0x18, 0xb8, 0x4a, 0x04, 0x79, 0x19, 0x7e, 0x1a, 0x60, 0x62, 0x76, 0xd0,
+	0x63, 0x50, 0x1a, 0x85, 0x34, 0x78, 0xcb, 0x95, 0x27, 0xbd, 0xd2, 0xae, 0xef, 0x4e, 0xec, 0xe2,
+	0x6b, 0x21, 0x0d, 0x2e, 0xaf, 0xf3, 0x4b, 0x71, 0x24, 0x5a, 0x44, 0xfe, 0x77, 0xd1, 0x4b, 0xca,
+	0x4c, 0x45, 0x60, 0xd9, 0xfe, 0x1a, 0xdf, 0x7e, 0xca, 0xd5, 0x0e, 0x03, 0x7f, 0x40, 0xd9, 0x36,
+	0x1d, 0x85, 0xad, 0x8e, 0x3f, 0x18, 0xf8, 0x5e, 0x4b, 0x54, 0x28, 0x04, 0xd3, 0xfc, 0x66, 0xe7,
+	0xd3, 0x95, 0xe5, 0x5e, 0x87, 0x12, 0xdb, 0x0e, 0xfc, 0x51, 0x6f, 0x5b, 0x38, 0xbf, 0x5c, 0xfb,
+	0xfc, 0xec, 0xf4, 0x34, 0x05, 0xa2, 0x1b, 0xe8, 0x61, 0x2e, 0x2d, 0xda, 0xd9, 0x09, 0x47, 0x03,
+	0x99, 0x13, 0xb7, 0x0b, 0x7b, 0x13, 0xdb, 0x7a, 0x8c, 0x44, 0x60, 0x7c, 0x01, 0xe6, 0x12, 0x21,
+	0x3a, 0x7a, 0x1c, 0xf2, 0x01, 0xdd, 0xd2, 0xae, 0x00, 0xed, 0x8f, 0xe4, 0x65, 0x54, 0xc2, 0x71,
+	0x88, 0xf8, 0xc5, 0xdf, 0xcb, 0x82, 0x6d, 0x54, 0x23, 0x2e, 0xfa, 0xc1, 0xcb, 0x94, 0x05, 0x6e,
+	0xe7, 0xaa, 0x33, 0xa0, 0xda, 0xbc, 0x6c, 0xa8, 0x0e, 0x04, 0xf0, 0x2d, 0xe3, 0x14, 0xc1, 0x20,
+	0xc2, 0x43, 0x0f, 0x01, 0x88, 0x63, 0x27, 0xc7, 0xe5, 0x81, 0xaa, 0x08, 0x88, 0x18, 0x5e, 0x4b,
+	0x08, 0xbb, 0x35, 0xa3, 0x70, 0x94, 0x90, 0x2f, 0xa7, 0x85, 0x3c, 0x33, 0x9d, 0x48, 0xb2, 0xe6,
+	0x71, 0x29, 0x24, 0x8f, 0x0b, 0xfe, 0xa7, 0x05, 0xcd, 0x0d, 0xbd, 0xf3, 0x7b, 0x14, 0x87, 0xe6,
+	0x37, 0x7b, 0x9f, 0xf8, 0xcd, 0xdd, 0x47, 0x7e, 0xf3, 0x29, 0x7e, 0x9b, 0x00, 0x1b, 0xae, 0x47,
+	0x2f, 0xba, 0x7d, 0x46, 0x83, 0x29, 0xc9, 0xdb, 0x0f, 0x73, 0xb1, 0xc7, 0x21, 0x74, 0x4b, 0xcb,
+	0x60, 0xcd, 0x70, 0xf3, 0xf7, 0x83, 0xc5, 0xec, 0x7d, 0x64, 0x31, 0x97, 0xf2, 0x80, 0x1e, 0x94,
+	0xb6, 0x04, 0x7b, 0xf2, 0xc6, 0x4e, 0xd4, 0xc5, 0x62, 0xde, 0xdb, 0xcf, 0xa9, 0xc5, 0x9f, 0xba,
+	0x43, 0x20, 0x28, 0xaa, 0x95, 0xad, 0x70, 0xec, 0x31, 0xe7, 0x1d, 0x63, 0x3e, 0xd1, 0x8b, 0x20,
+	0x47, 0xc5, 0x9a, 0x85, 0xa9, 0xb1, 0xe6, 0xb3, 0x6a, 0x99, 0xff, 0x27, 0xde, 0xc4, 0xcf, 0xc6,
+	0x0e, 0x56, 0x28, 0x45, 0x39, 0xd8, 0x13, 0x77, 0x3a, 0xfe, 0xea, 0xd0, 0xff, 0xd1, 0x82, 0x85,
+	0x4b, 0x94, 0x25, 0x63, 0xac, 0x07, 0x48, 0xa5, 0xf8, 0x45, 0x38, 0x6c, 0xec, 0x5f, 0x71, 0xff,
+	0x44, 0x2a, 0xb0, 0x3a, 0x12, 0xf3, 0x7f, 0xd9, 0xeb, 0xd2, 0x77, 0x54, 0x1e, 0x9d, 0x8c, 0xa9,
+	0xae, 0x41, 0xd5, 0x18, 0x44, 0x17, 0x52, 0xd1, 0xd4, 0x62, 0xaa, 0x7c, 0xcc, 0x23, 0x82, 0x76,
+	0x5d, 0xf1, 0x24, 0xb3, 0x65, 0x15, 0xab, 0x47, 0x91, 0xc7, 0x26, 0x20, 0xa1, 0x2e, 0x41, 0xd6,
+	0xbc, 0xfb, 0x04, 0xf4, 0xa5, 0x28, 0xac, 0x8a, 0xfa, 0xe8, 0x61, 0xc8, 0x07, 0xfe, 0x6d, 0x1d,
+	0xa6, 0xcf, 0xc5, 0x4b, 0x12, 0xff, 0x36, 0x11, 0x43, 0xf8, 0x19, 0xc8, 0x11, 0xff, 0x36, 0x6a,
+	0x02, 0x04, 0x8e, 0xd7, 0xa3, 0x37, 0xa2, 0xc4, 0xb1, 0x46, 0x0c, 0xc8, 0x01, 0x71, 0xc9, 0x1a,
+	0x1c, 0x36, 0x77, 0x24, 0xd5, 0xbd, 0x0a, 0xa5, 0x57, 0x47, 0xa6, 0xb8, 0xea, 0x29, 0x71, 0xc9,
+	0xfa, 0x84, 0x46, 0xe2, 0x36, 0x03, 0x31, 0x1c, 0x1d, 0x87, 0x0a, 0x73, 0x6e, 0xf6, 0xe9, 0xd5,
+	0xd8, 0x05, 0xc6, 0x00, 0x3e, 0xca, 0x73, 0xde, 0x1b, 0x46, 0x80, 0x15, 0x03, 0xd0, 0xa3, 0xb0,
+	0x10, 0xef, 0xf9, 0x5a, 0x40, 0xb7, 0xdc, 0x77, 0x84, 0x86, 0x6b, 0x64, 0x1f, 0x1c, 0x9d, 0x84,
+	0x43, 0x31, 0x6c, 0x53, 0x04, 0x32, 0x79, 0x81, 0x9a, 0x06, 0x73, 0xd9, 0x08, 0x76, 0x5f, 0xb8,
+	0x35, 0x72, 0xfa, 0xe2, 0xf0, 0xd5, 0x88, 0x01, 0xc1, 0x7f, 0xb2, 0xe0, 0xb0, 0x54, 0x35, 0x73,
+	0xd8, 0x03, 0x69, 0xf5, 0xbf, 0xb2, 0x00, 0x99, 0x1c, 0x28, 0xd3, 0xfa, 0xb2, 0x59, 0xff, 0xe2,
+	0x91, 0x52, 0x55, 0xa4, 0xf2, 0x12, 0x14, 0x97, 0xb0, 0x30, 0x14, 0x3b, 0xb2, 0xce, 0x27, 0x0a,
+	0xf6, 0xb2, 0x56, 0x20, 0x21, 0x44, 0xfd, 0x23, 0x1b, 0x0a, 0x37, 0xc7, 0x4c, 0x27, 0x7c, 0xb2,
+	0xc4, 0x21, 0x00, 0x44, 0xfe, 0xf1, 0xb5, 0xa8, 0x99, 0xe7, 0xc9, 0xb5, 0x14, 0x88, 0xe8, 0x06,
+	0xfe, 0x4d, 0x16, 0xe6, 0x6e, 0xf8, 0xfd, 0x51, 0x7c, 0x69, 0x3e, 0x48, 0x17, 0x46, 0xa2, 0xfc,
+	0x50, 0xd0, 0xe5, 0x07, 0x04, 0xf9, 0x90, 0xd1, 0xa1, 0xb0, 0xac, 0x1c, 0x11, 0x6d, 0x84, 0xa1,
+	0xc6, 0x9c, 0xa0, 0x47, 0x99, 0x4c, 0x9e, 0x1a, 0x45, 0x11, 0xd5, 0x26, 0x60, 0x68, 0x05, 0xaa,
+	0x4e, 0xaf, 0x17, 0xd0, 0x9e, 0xc3, 0x68, 0x7b, 0x2c, 0x0a, 0x04, 0x15, 0x62, 0x82, 0xf0, 0x1b,
+	0x30, 0xaf, 0x85, 0xa5, 0x54, 0xfa, 0x38, 0x94, 0xde, 0x16, 0x90, 0x29, 0xe5, 0x40, 0x89, 0xaa,
+	0xdc, 0x98, 0x46, 0x4b, 0x3e, 0x7b, 0xe8, 0x3d, 0xe3, 0x2b, 0x50, 0x94, 0xe8, 0xe8, 0xb8, 0x99,
+	0x02, 0xc9, 0x28, 0x90, 0xf7, 0x55, 0x3e, 0x83, 0xa1, 0x28, 0x09, 0x29, 0xc5, 0x0b, 0xdb, 0x90,
+	0x10, 0xa2, 0xfe, 0xf1, 0xbf, 0x2c, 0x38, 0xb2, 0x4e, 0x19, 0xed, 0x30, 0xda, 0xbd, 0xe8, 0xd2,
+	0x7e, 0xf7, 0x73, 0xcd, 0xce, 0xa3, 0xda, 0x5f, 0xce, 0xa8, 0xfd, 0x71, 0xbf, 0xd3, 0x77, 0x3d,
+	0x59, 0x77, 0x50, 0xc5, 0xa3, 0x18, 0xc0, 0x3d, 0xc4, 0x16, 0xdf, 0xb8, 0x1c, 0x96, 0xef, 0x4c,
+	0x06, 0x24, 0xd2, 0x70, 0x31, 0xd6, 0x30, 0xfe, 0xae, 0x05, 0x4b, 0x69, 0xae, 0x95, 0x92, 0x5a,
+	0x50, 0x14, 0x93, 0xa7, 0x94, 0x9d, 0x13, 0x33, 0x88, 0x42, 0x43, 0xe7, 0x12, 0xeb, 0x8b, 0xf7,
+	0xa9, 0x76, 0x63, 0x6f, 0x62, 0xd7, 0x63, 0xa8, 0x51, 0x41, 0x30, 0x70, 0xf1, 0xef, 0x79, 0x9e,
+	0x6d, 0xd2, 0x14, 0xfa, 0xe6, 0xf6, 0xa5, 0x7c, 0xaf, 0xec, 0xa0, 0xaf, 0x40, 0x9e, 0x8d, 0x87,
+	0xca, 0xe5, 0xb6, 0x8f, 0xfc, 0x67, 0x62, 0x1f, 0x4e, 0x4c, 0xbb, 0x3e, 0x1e, 0x52, 0x22, 0x50,
+	0xb8, 0x59, 0x76, 0x9c, 0xa0, 0xeb, 0x7a, 0x4e, 0xdf, 0x65, 0x63, 0x55, 0xe1, 0x31, 0x41, 0xa8,
+	0x01, 0xa5, 0xa1, 0x13, 0x84, 0x3a, 0x6e, 0xaa, 0x10, 0xdd, 0x15, 0x25, 0x90, 0x1d, 0xca, 0x3a,
+	0xdb, 0xd2, 0xcd, 0xaa, 0x12, 0x88, 0x80, 0x24, 0x4a, 0x20, 0x02, 0x82, 0x7f, 0x6e, 0x18, 0x8e,
+	0x3c, 0x13, 0x5f, 0x38, 0xc3, 0xc1, 0xdf, 0x8c, 0xb5, 0xac, 0xb7, 0xa8, 0xb4, 0xfc, 0x3c, 0xcc,
+	0x77, 0x13, 0x23, 0x07, 0x6b, 0x5b, 0x96, 0x9d, 0x53, 0xe8, 0x78, 0x14, 0xab, 0x4e, 0x40, 0x0e,
+	0x50, 0x5d, 0x4a, 0x1f, 0xd9, 0xfd, 0xfa, 0x88, 0xa5, 0x9e, 0xbb, 0x0b, 0xa9, 0xff, 0x2c, 0x0b,
+	0x73, 0x1b, 0xbe, 0xbf, 0x33, 0x1a, 0x6a, 0x69, 0xdf, 0x65, 0x51, 0xe7, 0x73, 0x79, 0x2b, 0x8d,
+	0xdc, 0x57, 0xc1, 0xac, 0xf8, 0x26, 0x5e, 0x50, 0x8b, 0x77, 0xf5, 0x82, 0x6a, 0xbc, 0xae, 0x95,
+	0xee, 0xf0, 0xba, 0x86, 0x7f, 0x61, 0xc1, 0xbc, 0x16, 0x91, 0xd2, 0xf6, 0x63, 0x89, 0x08, 0xda,
+	0x8c, 0xfe, 0x14, 0xde, 0x96, 0x72, 0xbc, 0x02, 0xcd, 0x7c, 0x7a, 0xca, 0x7e, 0x46, 0x4f, 0x4f,
+	0x3c, 0x6e, 0xa9, 0x44, 0xab, 0x1b, 0xf5, 0x2e, 0xcb, 0xac, 0x77, 0x71, 0xf3, 0x31, 0x4b, 0x26,
+	0xca, 0x7c, 0xcc, 0x0a, 0xc9, 0x39, 0x23, 0x0b, 0x9f, 0xad, 0x32, 0xf7, 0x5c, 0x32, 0xf5, 0xbe,
+	0xdb, 0xc9, 0x7a, 0xd2, 0xa3, 0x27, 0xa0, 0x12, 0xe9, 0x0a, 0x55, 0xa1, 0x74, 0xf1, 0x15, 0xf2,
+	0xfa, 0x05, 0xb2, 0xbe, 0x90, 0x41, 0x35, 0x28, 0xb7, 0x2f, 0xac, 0xbd, 0x24, 0x7a, 0xd6, 0xd9,
+	0x7f, 0x14, 0x75, 0x4c, 0x1a, 0xa0, 0xaf, 0x43, 0x41, 0x06, 0x9a, 0x4b, 0xb1, 0x44, 0xcd, 0x87,
+	0xe0, 0xe5, 0xa3, 0xfb, 0xe0, 0x52, 0x85, 0x38, 0xf3, 0xb8, 0x85, 0xae, 0x42, 0x55, 0x00, 0xd5,
+	0x53, 0xcb, 0xf1, 0xf4, 0x8b, 0x47, 0x82, 0xd2, 0x43, 0x07, 0x8c, 0x1a, 0xf4, 0xce, 0x43, 0x41,
+	0x9e, 0xdd, 0xa5, 0x54, 0x3e, 0x30, 0x65, 0x37, 0x89, 0xc7, 0x27, 0x9c, 0x41, 0x4f, 0x43, 0xfe,
+	0xba, 0xe3, 0xf6, 0x91, 0x91, 0x8e, 0x18, 0x2f, 0x24, 0xcb, 0x4b, 0x69, 0xb0, 0xb1, 0xec, 0xb3,
+	0xd1, 0x43, 0xcf, 0xd1, 0x74, 0x55, 0x57, 0x4f, 0x6f, 0xec, 0x1f, 0x88, 0x56, 0x7e, 0x45, 0x3e,
+	0x3b, 0xe8, 0xda, 0x22, 0x7a, 0x28, 0xb9, 0x54, 0xaa, 0x14, 0xb9, 0xdc, 0x3c, 0x68, 0x38, 0x22,
+	0xb8, 0x01, 0x55, 0xa3, 0xae, 0x67, 0x8a, 0x75, 0x7f, 0x51, 0xd2, 0x14, 0xeb, 0x94, 0x62, 0x20,
+	0xce, 0xa0, 0x4b, 0x50, 0xe6, 0x49, 0x9c, 0x78, 0x97, 0x3c, 0x96, 0xce, 0xd5, 0x8c, 0x18, 0x7d,
+	0xf9, 0xf8, 0xf4, 0xc1, 0x88, 0xd0, 0x37, 0xa0, 0x72, 0x89, 0x32, 0x15, 0xe8, 0x1c, 0x4d, 0x47,
+	0x4a, 0x53, 0x24, 0x95, 0x8c, 0xb6, 0x70, 0x06, 0xbd, 0x21, 0xf2, 0xc9, 0xe4, 0x3d, 0x8f, 0xec,
+	0x03, 0xee, 0xf3, 0x68, 0x5f, 0x2b, 0x07, 0x23, 0x44, 0x94, 0x5f, 0x4f, 0x50, 0x56, 0x21, 0xa1,
+	0x7d, 0xc0, 0xdd, 0x11, 0x51, 0xb6, 0xef, 0xf0, 0xd5, 0x12, 0xce, 0x70, 0xdb, 0x90, 0x5e, 0xc1,
+	0xe4, 0x38, 0xe1, 0xf0, 0x4d, 0x8e, 0x93, 0x6e, 0x0e, 0x67, 0xce, 0xbe, 0xa9, 0xbf, 0xfb, 0x59,
+	0x77, 0x98, 0x83, 0x5e, 0x81, 0x79, 0xa1, 0x8a, 0xe8, 0xc3, 0xa0, 0xc4, 0x91, 0xd9, 0xf7, 0x15,
+	0x52, 0xe2, 0xc8, 0xec, 0xff, 0x1a, 0x09, 0x67, 0xda, 0x6f, 0x7e, 0xf0, 0x71, 0x33, 0xf3, 0xe1,
+	0xc7, 0xcd, 0xcc, 0xa7, 0x1f, 0x37, 0xad, 0xef, 0xec, 0x36, 0xad, 0x5f, 0xef, 0x36, 0xad, 0xf7,
+	0x77, 0x9b, 0xd6, 0x07, 0xbb, 0x4d, 0xeb, 0x6f, 0xbb, 0x4d, 0xeb, 0xef, 0xbb, 0xcd, 0xcc, 0xa7,
+	0xbb, 0x4d, 0xeb, 0xdd, 0x4f, 0x9a, 0x99, 0x0f, 0x3e, 0x69, 0x66, 0x3e, 0xfc, 0xa4, 0x99, 0xf9,
+	0xd6, 0x23, 0x77, 0x2e, 0xbd, 0x48, 0x8f, 0x53, 0x14, 0x7f, 0x4f, 0xfc, 0x37, 0x00, 0x00, 0xff,
+	0xff, 0x6d, 0x9e, 0xec, 0xdd, 0x9d, 0x26, 0x00, 0x00,
 }
 
 func (x Direction) String() string {
@@ -3898,6 +4154,9 @@ func (this *TailRequest) Equal(that interface{}) bool {
 	} else if !this.Plan.Equal(*that1.Plan) {
 		return false
 	}
+	if this.Sample != that1.Sample {
+		return false
+	}
 	return true
 }
 func (this *TailResponse) Equal(that interface{}) bool {
@@ -3934,6 +4193,15 @@ func (this *TailResponse) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.SampledEntries != that1.SampledEntries {
+		return false
+	}
+	if this.RateLimitedEntries != that1.RateLimitedEntries {
+		return false
+	}
+	if this.DroppedEntries != that1.DroppedEntries {
+		return false
+	}
 	return true
 }
 func (this *SeriesRequest) Equal(that interface{}) bool {
@@ -5086,83 +5354,200 @@ func (this *DetectedLabel) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *LabelToValuesResponse) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *LookupRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&logproto.LabelToValuesResponse{")
-	keysForLabels := make([]string, 0, len(this.Labels))
-	for k, _ := range this.Labels {
-		keysForLabels = append(keysForLabels, k)
+
+	that1, ok := that.(*LookupRequest)
+	if !ok {
+		that2, ok := that.(LookupRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForLabels)
-	mapStringForLabels := "map[string]*UniqueLabelValues{"
-	for _, k := range keysForLabels {
-		mapStringForLabels += fmt.Sprintf("%#v: %#v,", k, this.Labels[k])
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	mapStringForLabels += "}"
-	if this.Labels != nil {
-		s = append(s, "Labels: "+mapStringForLabels+",\n")
+	if this.Key != that1.Key {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *UniqueLabelValues) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Value != that1.Value {
+		return false
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&logproto.UniqueLabelValues{")
-	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *StreamRatesRequest) GoString() string {
-	if this == nil {
-		return "nil"
+	if !this.Start.Equal(that1.Start) {
+		return false
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&logproto.StreamRatesRequest{")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *StreamRatesResponse) GoString() string {
-	if this == nil {
-		return "nil"
+	if !this.End.Equal(that1.End) {
+		return false
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&logproto.StreamRatesResponse{")
-	if this.StreamRates != nil {
-		s = append(s, "StreamRates: "+fmt.Sprintf("%#v", this.StreamRates)+",\n")
+	if this.Limit != that1.Limit {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *StreamRate) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Direction != that1.Direction {
+		return false
 	}
-	s := make([]string, 0, 9)
-	s = append(s, "&logproto.StreamRate{")
-	s = append(s, "StreamHash: "+fmt.Sprintf("%#v", this.StreamHash)+",\n")
-	s = append(s, "StreamHashNoShard: "+fmt.Sprintf("%#v", this.StreamHashNoShard)+",\n")
-	s = append(s, "Rate: "+fmt.Sprintf("%#v", this.Rate)+",\n")
-	s = append(s, "Tenant: "+fmt.Sprintf("%#v", this.Tenant)+",\n")
-	s = append(s, "Pushes: "+fmt.Sprintf("%#v", this.Pushes)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *QueryRequest) GoString() string {
-	if this == nil {
-		return "nil"
+	if len(this.Deletes) != len(that1.Deletes) {
+		return false
 	}
-	s := make([]string, 0, 13)
-	s = append(s, "&logproto.QueryRequest{")
-	s = append(s, "Selector: "+fmt.Sprintf("%#v", this.Selector)+",\n")
-	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
-	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
-	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	for i := range this.Deletes {
+		if !this.Deletes[i].Equal(that1.Deletes[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *LookupResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LookupResponse)
+	if !ok {
+		that2, ok := that.(LookupResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Refs) != len(that1.Refs) {
+		return false
+	}
+	for i := range this.Refs {
+		if !this.Refs[i].Equal(&that1.Refs[i]) {
+			return false
+		}
+	}
+	if len(this.Streams) != len(that1.Streams) {
+		return false
+	}
+	for i := range this.Streams {
+		if !this.Streams[i].Equal(that1.Streams[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *LookupRef) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LookupRef)
+	if !ok {
+		that2, ok := that.(LookupRef)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Labels != that1.Labels {
+		return false
+	}
+	if this.Fingerprint != that1.Fingerprint {
+		return false
+	}
+	if !this.From.Equal(that1.From) {
+		return false
+	}
+	if !this.Through.Equal(that1.Through) {
+		return false
+	}
+	return true
+}
+func (this *LabelToValuesResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.LabelToValuesResponse{")
+	keysForLabels := make([]string, 0, len(this.Labels))
+	for k, _ := range this.Labels {
+		keysForLabels = append(keysForLabels, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForLabels)
+	mapStringForLabels := "map[string]*UniqueLabelValues{"
+	for _, k := range keysForLabels {
+		mapStringForLabels += fmt.Sprintf("%#v: %#v,", k, this.Labels[k])
+	}
+	mapStringForLabels += "}"
+	if this.Labels != nil {
+		s = append(s, "Labels: "+mapStringForLabels+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *UniqueLabelValues) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.UniqueLabelValues{")
+	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamRatesRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&logproto.StreamRatesRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamRatesResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.StreamRatesResponse{")
+	if this.StreamRates != nil {
+		s = append(s, "StreamRates: "+fmt.Sprintf("%#v", this.StreamRates)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamRate) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&logproto.StreamRate{")
+	s = append(s, "StreamHash: "+fmt.Sprintf("%#v", this.StreamHash)+",\n")
+	s = append(s, "StreamHashNoShard: "+fmt.Sprintf("%#v", this.StreamHashNoShard)+",\n")
+	s = append(s, "Rate: "+fmt.Sprintf("%#v", this.Rate)+",\n")
+	s = append(s, "Tenant: "+fmt.Sprintf("%#v", this.Tenant)+",\n")
+	s = append(s, "Pushes: "+fmt.Sprintf("%#v", this.Pushes)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *QueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 13)
+	s = append(s, "&logproto.QueryRequest{")
+	s = append(s, "Selector: "+fmt.Sprintf("%#v", this.Selector)+",\n")
+	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
 	s = append(s, "Direction: "+fmt.Sprintf("%#v", this.Direction)+",\n")
 	s = append(s, "Shards: "+fmt.Sprintf("%#v", this.Shards)+",\n")
 	if this.Deletes != nil {
@@ -5310,13 +5695,14 @@ func (this *TailRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 9)
+	s := make([]string, 0, 10)
 	s = append(s, "&logproto.TailRequest{")
 	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
 	s = append(s, "DelayFor: "+fmt.Sprintf("%#v", this.DelayFor)+",\n")
 	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
 	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
 	s = append(s, "Plan: "+fmt.Sprintf("%#v", this.Plan)+",\n")
+	s = append(s, "Sample: "+fmt.Sprintf("%#v", this.Sample)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -5324,12 +5710,15 @@ func (this *TailResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 9)
 	s = append(s, "&logproto.TailResponse{")
 	s = append(s, "Stream: "+fmt.Sprintf("%#v", this.Stream)+",\n")
 	if this.DroppedStreams != nil {
 		s = append(s, "DroppedStreams: "+fmt.Sprintf("%#v", this.DroppedStreams)+",\n")
 	}
+	s = append(s, "SampledEntries: "+fmt.Sprintf("%#v", this.SampledEntries)+",\n")
+	s = append(s, "RateLimitedEntries: "+fmt.Sprintf("%#v", this.RateLimitedEntries)+",\n")
+	s = append(s, "DroppedEntries: "+fmt.Sprintf("%#v", this.DroppedEntries)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -5798,6 +6187,54 @@ func (this *DetectedLabel) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *LookupRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&logproto.LookupRequest{")
+	s = append(s, "Key: "+fmt.Sprintf("%#v", this.Key)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
+	s = append(s, "Direction: "+fmt.Sprintf("%#v", this.Direction)+",\n")
+	if this.Deletes != nil {
+		s = append(s, "Deletes: "+fmt.Sprintf("%#v", this.Deletes)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LookupResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&logproto.LookupResponse{")
+	if this.Refs != nil {
+		vs := make([]LookupRef, len(this.Refs))
+		for i := range vs {
+			vs[i] = this.Refs[i]
+		}
+		s = append(s, "Refs: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LookupRef) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&logproto.LookupRef{")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	s = append(s, "Fingerprint: "+fmt.Sprintf("%#v", this.Fingerprint)+",\n")
+	s = append(s, "From: "+fmt.Sprintf("%#v", this.From)+",\n")
+	s = append(s, "Through: "+fmt.Sprintf("%#v", this.Through)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringLogproto(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -5834,6 +6271,7 @@ type QuerierClient interface {
 	GetVolume(ctx context.Context, in *VolumeRequest, opts ...grpc.CallOption) (*VolumeResponse, error)
 	GetDetectedFields(ctx context.Context, in *DetectedFieldsRequest, opts ...grpc.CallOption) (*DetectedFieldsResponse, error)
 	GetDetectedLabels(ctx context.Context, in *DetectedLabelsRequest, opts ...grpc.CallOption) (*LabelToValuesResponse, error)
+	Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error)
 }
 
 type querierClient struct {
@@ -6012,6 +6450,15 @@ func (c *querierClient) GetDetectedLabels(ctx context.Context, in *DetectedLabel
 	return out, nil
 }
 
+func (c *querierClient) Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error) {
+	out := new(LookupResponse)
+	err := c.cc.Invoke(ctx, "/logproto.Querier/Lookup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QuerierServer is the server API for Querier service.
 type QuerierServer interface {
 	Query(*QueryRequest, Querier_QueryServer) error
@@ -6029,6 +6476,7 @@ type QuerierServer interface {
 	GetVolume(context.Context, *VolumeRequest) (*VolumeResponse, error)
 	GetDetectedFields(context.Context, *DetectedFieldsRequest) (*DetectedFieldsResponse, error)
 	GetDetectedLabels(context.Context, *DetectedLabelsRequest) (*LabelToValuesResponse, error)
+	Lookup(context.Context, *LookupRequest) (*LookupResponse, error)
 }
 
 // UnimplementedQuerierServer can be embedded to have forward compatible implementations.
@@ -6068,6 +6516,9 @@ func (*UnimplementedQuerierServer) GetDetectedFields(ctx context.Context, req *D
 func (*UnimplementedQuerierServer) GetDetectedLabels(ctx context.Context, req *DetectedLabelsRequest) (*LabelToValuesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDetectedLabels not implemented")
 }
+func (*UnimplementedQuerierServer) Lookup(ctx context.Context, req *LookupRequest) (*LookupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lookup not implemented")
+}
 
 func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
 	s.RegisterService(&_Querier_serviceDesc, srv)
@@ -6280,6 +6731,24 @@ func _Querier_GetDetectedLabels_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Querier_Lookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuerierServer).Lookup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logproto.Querier/Lookup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).Lookup(ctx, req.(*LookupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Querier_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "logproto.Querier",
 	HandlerType: (*QuerierServer)(nil),
@@ -6316,6 +6785,10 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetDetectedLabels",
 			Handler:    _Querier_GetDetectedLabels_Handler,
 		},
+		{
+			MethodName: "Lookup",
+			Handler:    _Querier_Lookup_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -7219,6 +7692,12 @@ func (m *TailRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Sample != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sample))))
+		i--
+		dAtA[i] = 0x39
+	}
 	if m.Plan != nil {
 		{
 			size := m.Plan.Size()
@@ -7279,6 +7758,21 @@ func (m *TailResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.DroppedEntries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.DroppedEntries))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.RateLimitedEntries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.RateLimitedEntries))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.SampledEntries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.SampledEntries))
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.DroppedStreams) > 0 {
 		for iNdEx := len(m.DroppedStreams) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -8876,29 +9370,208 @@ func (m *DetectedLabel) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintLogproto(dAtA []byte, offset int, v uint64) int {
-	offset -= sovLogproto(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *LookupRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *LabelToValuesResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *LookupRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LookupRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		for k, v := range m.Labels {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
+	if len(m.Deletes) > 0 {
+		for iNdEx := len(m.Deletes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Deletes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.Direction != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Direction))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Limit != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x28
+	}
+	n28, err28 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
+	if err28 != nil {
+		return 0, err28
+	}
+	i -= n28
+	i = encodeVarintLogproto(dAtA, i, uint64(n28))
+	i--
+	dAtA[i] = 0x22
+	n29, err29 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err29 != nil {
+		return 0, err29
+	}
+	i -= n29
+	i = encodeVarintLogproto(dAtA, i, uint64(n29))
+	i--
+	dAtA[i] = 0x1a
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Key)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LookupResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LookupResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LookupResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Streams) > 0 {
+		for iNdEx := len(m.Streams) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Streams[iNdEx].Size()
+				i -= size
+				if _, err := m.Streams[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Refs) > 0 {
+		for iNdEx := len(m.Refs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Refs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LookupRef) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LookupRef) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LookupRef) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	n30, err30 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Through, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Through):])
+	if err30 != nil {
+		return 0, err30
+	}
+	i -= n30
+	i = encodeVarintLogproto(dAtA, i, uint64(n30))
+	i--
+	dAtA[i] = 0x22
+	n31, err31 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.From, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.From):])
+	if err31 != nil {
+		return 0, err31
+	}
+	i -= n31
+	i = encodeVarintLogproto(dAtA, i, uint64(n31))
+	i--
+	dAtA[i] = 0x1a
+	if m.Fingerprint != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Fingerprint))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Labels) > 0 {
+		i -= len(m.Labels)
+		copy(dAtA[i:], m.Labels)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintLogproto(dAtA []byte, offset int, v uint64) int {
+	offset -= sovLogproto(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *LabelToValuesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
 				l = v.Size()
 				l += 1 + sovLogproto(uint64(l))
 			}
@@ -9251,6 +9924,9 @@ func (m *TailRequest) Size() (n int) {
 		l = m.Plan.Size()
 		n += 1 + l + sovLogproto(uint64(l))
 	}
+	if m.Sample != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -9270,6 +9946,15 @@ func (m *TailResponse) Size() (n int) {
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
+	if m.SampledEntries != 0 {
+		n += 1 + sovLogproto(uint64(m.SampledEntries))
+	}
+	if m.RateLimitedEntries != 0 {
+		n += 1 + sovLogproto(uint64(m.RateLimitedEntries))
+	}
+	if m.DroppedEntries != 0 {
+		n += 1 + sovLogproto(uint64(m.DroppedEntries))
+	}
 	return n
 }
 
@@ -9971,6 +10656,80 @@ func (m *DetectedLabel) Size() (n int) {
 	return n
 }
 
+func (m *LookupRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovLogproto(uint64(l))
+	if m.Limit != 0 {
+		n += 1 + sovLogproto(uint64(m.Limit))
+	}
+	if m.Direction != 0 {
+		n += 1 + sovLogproto(uint64(m.Direction))
+	}
+	if len(m.Deletes) > 0 {
+		for _, e := range m.Deletes {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LookupResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Refs) > 0 {
+		for _, e := range m.Refs {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	if len(m.Streams) > 0 {
+		for _, e := range m.Streams {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LookupRef) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Labels)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.Fingerprint != 0 {
+		n += 1 + sovLogproto(uint64(m.Fingerprint))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.From)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Through)
+	n += 1 + l + sovLogproto(uint64(l))
+	return n
+}
+
 func sovLogproto(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -10209,6 +10968,7 @@ func (this *TailRequest) String() string {
 		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
 		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
 		`Plan:` + fmt.Sprintf("%v", this.Plan) + `,`,
+		`Sample:` + fmt.Sprintf("%v", this.Sample) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -10225,6 +10985,9 @@ func (this *TailResponse) String() string {
 	s := strings.Join([]string{`&TailResponse{`,
 		`Stream:` + fmt.Sprintf("%v", this.Stream) + `,`,
 		`DroppedStreams:` + repeatedStringForDroppedStreams + `,`,
+		`SampledEntries:` + fmt.Sprintf("%v", this.SampledEntries) + `,`,
+		`RateLimitedEntries:` + fmt.Sprintf("%v", this.RateLimitedEntries) + `,`,
+		`DroppedEntries:` + fmt.Sprintf("%v", this.DroppedEntries) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -10708,18 +11471,68 @@ func (this *DetectedLabel) String() string {
 	}, "")
 	return s
 }
-func valueToStringLogproto(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
+func (this *LookupRequest) String() string {
+	if this == nil {
 		return "nil"
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	repeatedStringForDeletes := "[]*Delete{"
+	for _, f := range this.Deletes {
+		repeatedStringForDeletes += strings.Replace(f.String(), "Delete", "Delete", 1) + ","
+	}
+	repeatedStringForDeletes += "}"
+	s := strings.Join([]string{`&LookupRequest{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`Direction:` + fmt.Sprintf("%v", this.Direction) + `,`,
+		`Deletes:` + repeatedStringForDeletes + `,`,
+		`}`,
+	}, "")
+	return s
 }
-func (m *LabelToValuesResponse) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
+func (this *LookupResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRefs := "[]LookupRef{"
+	for _, f := range this.Refs {
+		repeatedStringForRefs += strings.Replace(strings.Replace(f.String(), "LookupRef", "LookupRef", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRefs += "}"
+	s := strings.Join([]string{`&LookupResponse{`,
+		`Refs:` + repeatedStringForRefs + `,`,
+		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LookupRef) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LookupRef{`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`Fingerprint:` + fmt.Sprintf("%v", this.Fingerprint) + `,`,
+		`From:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.From), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Through:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Through), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringLogproto(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *LabelToValuesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
 		preIndex := iNdEx
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -13199,6 +14012,17 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sample", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sample = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -13322,6 +14146,63 @@ func (m *TailResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SampledEntries", wireType)
+			}
+			m.SampledEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SampledEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RateLimitedEntries", wireType)
+			}
+			m.RateLimitedEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RateLimitedEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DroppedEntries", wireType)
+			}
+			m.DroppedEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DroppedEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -17895,6 +18776,552 @@ func (m *DetectedLabelsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *LookupRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LookupRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LookupRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Start, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.End, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Direction", wireType)
+			}
+			m.Direction = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Direction |= Direction(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deletes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Deletes = append(m.Deletes, &Delete{})
+			if err := m.Deletes[len(m.Deletes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LookupResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LookupResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LookupResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Refs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Refs = append(m.Refs, LookupRef{})
+			if err := m.Refs[len(m.Refs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Streams = append(m.Streams, github_com_grafana_loki_pkg_push.Stream{})
+			if err := m.Streams[len(m.Streams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LookupRef) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LookupRef: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LookupRef: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fingerprint", wireType)
+			}
+			m.Fingerprint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Fingerprint |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.From, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Through, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *DetectedLabel) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
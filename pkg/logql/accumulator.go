@@ -10,6 +10,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/sketch"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/metadata"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
@@ -71,6 +72,62 @@ func (a *QuantileSketchAccumulator) Result() []logqlmodel.Result {
 	return []logqlmodel.Result{{Data: a.matrix}}
 }
 
+type TopKSketchAccumulator struct {
+	k      int
+	matrix sketch.TopKMatrix
+}
+
+// newTopKSketchAccumulator returns an accumulator for sharded approx_topk
+// queries that merges the sketches of the shards as they come in.
+func newTopKSketchAccumulator(k int) *TopKSketchAccumulator {
+	return &TopKSketchAccumulator{k: k}
+}
+
+func (a *TopKSketchAccumulator) Accumulate(_ context.Context, res logqlmodel.Result, _ int) error {
+	data, ok := res.Data.(sketch.TopKMatrix)
+	if !ok {
+		return fmt.Errorf("unexpected matrix type: got (%T), want (sketch.TopKMatrix)", res.Data)
+	}
+
+	var err error
+	a.matrix, err = mergeTopKSketchMatrix(a.matrix, data, a.k)
+	return err
+}
+
+func (a *TopKSketchAccumulator) Result() []logqlmodel.Result {
+	return []logqlmodel.Result{{Data: a.matrix}}
+}
+
+type CountDistinctSketchAccumulator struct {
+	matrix CountDistinctSketchMatrix
+}
+
+// newCountDistinctSketchAccumulator returns an accumulator for sharded
+// approx_count_distinct queries that merges the sketches of the shards as
+// they come in.
+func newCountDistinctSketchAccumulator() *CountDistinctSketchAccumulator {
+	return &CountDistinctSketchAccumulator{}
+}
+
+func (a *CountDistinctSketchAccumulator) Accumulate(_ context.Context, res logqlmodel.Result, _ int) error {
+	data, ok := res.Data.(CountDistinctSketchMatrix)
+	if !ok {
+		return fmt.Errorf("unexpected matrix type: got (%T), want (CountDistinctSketchMatrix)", res.Data)
+	}
+	if a.matrix == nil {
+		a.matrix = data
+		return nil
+	}
+
+	var err error
+	a.matrix, err = a.matrix.Merge(data)
+	return err
+}
+
+func (a *CountDistinctSketchAccumulator) Result() []logqlmodel.Result {
+	return []logqlmodel.Result{{Data: a.matrix}}
+}
+
 // heap impl for keeping only the top n results across m streams
 // importantly, AccumulatedStreams is _bounded_, so it will only
 // store the top `limit` results across all streams.
@@ -0,0 +1,349 @@
+package logql
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+const (
+	CountDistinctSketchMatrixType = "CountDistinctSketchMatrix"
+
+	// DefaultCountDistinctPrecision is the default precision of the
+	// HyperLogLog sketches of approx_count_distinct. It uses 2^14 registers
+	// and has a standard error of about 0.8%.
+	DefaultCountDistinctPrecision = 14
+)
+
+func newCountDistinctSketch(precision uint8) *hyperloglog.Sketch {
+	if precision == 16 {
+		return hyperloglog.New16()
+	}
+	return hyperloglog.New14()
+}
+
+// CountDistinctSketchSample is the HyperLogLog sketch of the distinct label
+// values of a single group.
+type CountDistinctSketchSample struct {
+	T      int64
+	F      *hyperloglog.Sketch
+	Metric labels.Labels
+}
+
+func (s CountDistinctSketchSample) ToProto() (*logproto.CountDistinctSketchSample, error) {
+	hll, err := s.F.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	metric := make([]*logproto.LabelPair, len(s.Metric))
+	for i, m := range s.Metric {
+		metric[i] = &logproto.LabelPair{Name: m.Name, Value: m.Value}
+	}
+
+	return &logproto.CountDistinctSketchSample{
+		Hyperloglog: hll,
+		TimestampMs: s.T,
+		Metric:      metric,
+	}, nil
+}
+
+func countDistinctSketchSampleFromProto(proto *logproto.CountDistinctSketchSample) (CountDistinctSketchSample, error) {
+	hll := hyperloglog.New()
+	if err := hll.UnmarshalBinary(proto.Hyperloglog); err != nil {
+		return CountDistinctSketchSample{}, err
+	}
+	out := CountDistinctSketchSample{
+		T:      proto.TimestampMs,
+		F:      hll,
+		Metric: make(labels.Labels, len(proto.Metric)),
+	}
+
+	for i, p := range proto.Metric {
+		out.Metric[i] = labels.Label{Name: p.Name, Value: p.Value}
+	}
+
+	return out, nil
+}
+
+type CountDistinctSketchVector []CountDistinctSketchSample
+
+var _ StepResult = CountDistinctSketchVector{}
+
+func (CountDistinctSketchVector) SampleVector() promql.Vector {
+	return promql.Vector{}
+}
+
+func (CountDistinctSketchVector) QuantileSketchVec() ProbabilisticQuantileVector {
+	return ProbabilisticQuantileVector{}
+}
+
+// Merge merges the sketches of right into the sketches of the same group in v.
+func (v CountDistinctSketchVector) Merge(right CountDistinctSketchVector) (CountDistinctSketchVector, error) {
+	groups := streamHashPool.Get().(map[uint64]int)
+	defer func() {
+		clear(groups)
+		streamHashPool.Put(groups)
+	}()
+	for i, sample := range v {
+		groups[sample.Metric.Hash()] = i
+	}
+
+	for _, sample := range right {
+		i, ok := groups[sample.Metric.Hash()]
+		if !ok {
+			v = append(v, sample)
+			continue
+		}
+
+		if err := v[i].F.Merge(sample.F); err != nil {
+			return v, err
+		}
+	}
+
+	return v, nil
+}
+
+func (v CountDistinctSketchVector) ToProto() (*logproto.CountDistinctSketchVector, error) {
+	samples := make([]*logproto.CountDistinctSketchSample, len(v))
+	var err error
+	for i, sample := range v {
+		samples[i], err = sample.ToProto()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &logproto.CountDistinctSketchVector{Samples: samples}, nil
+}
+
+func CountDistinctSketchVectorFromProto(proto *logproto.CountDistinctSketchVector) (CountDistinctSketchVector, error) {
+	out := make(CountDistinctSketchVector, len(proto.Samples))
+	var err error
+	for i, sample := range proto.Samples {
+		out[i], err = countDistinctSketchSampleFromProto(sample)
+		if err != nil {
+			return CountDistinctSketchVector{}, err
+		}
+	}
+	return out, nil
+}
+
+// CountDistinctSketchMatrix holds a vector of sketches for each step.
+type CountDistinctSketchMatrix []CountDistinctSketchVector
+
+func (CountDistinctSketchMatrix) String() string {
+	return "CountDistinctSketchMatrix()"
+}
+
+func (CountDistinctSketchMatrix) Type() promql_parser.ValueType { return CountDistinctSketchMatrixType }
+
+func (m CountDistinctSketchMatrix) Merge(right CountDistinctSketchMatrix) (CountDistinctSketchMatrix, error) {
+	if len(m) != len(right) {
+		return nil, fmt.Errorf("failed to merge count distinct sketch matrix: lengths differ %d!=%d", len(m), len(right))
+	}
+	var err error
+	for i, vec := range m {
+		m[i], err = vec.Merge(right[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge count distinct sketch matrix: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+func (m CountDistinctSketchMatrix) ToProto() (*logproto.CountDistinctSketchMatrix, error) {
+	values := make([]*logproto.CountDistinctSketchVector, len(m))
+	var err error
+	for i, vec := range m {
+		values[i], err = vec.ToProto()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &logproto.CountDistinctSketchMatrix{Values: values}, nil
+}
+
+func CountDistinctSketchMatrixFromProto(proto *logproto.CountDistinctSketchMatrix) (CountDistinctSketchMatrix, error) {
+	out := make(CountDistinctSketchMatrix, len(proto.Values))
+	var err error
+	for i, v := range proto.Values {
+		out[i], err = CountDistinctSketchVectorFromProto(v)
+		if err != nil {
+			return CountDistinctSketchMatrix{}, err
+		}
+	}
+	return out, nil
+}
+
+// CountDistinctSketchStepEvaluator inserts the values of a label of the
+// samples of its inner evaluator into a HyperLogLog sketch per group.
+type CountDistinctSketchStepEvaluator struct {
+	inner     StepEvaluator
+	expr      *syntax.VectorAggregationExpr
+	precision uint8
+	without   []string
+	lb        *labels.Builder
+	buf       []byte
+}
+
+func newCountDistinctSketchStepEvaluator(inner StepEvaluator, expr *syntax.VectorAggregationExpr, precision uint8) *CountDistinctSketchStepEvaluator {
+	sort.Strings(expr.Grouping.Groups)
+	return &CountDistinctSketchStepEvaluator{
+		inner:     inner,
+		expr:      expr,
+		precision: precision,
+		// the counted label is always dropped from the groups.
+		without: append(append([]string{}, expr.Grouping.Groups...), expr.Label, labels.MetricName),
+		lb:      labels.NewBuilder(nil),
+		buf:     make([]byte, 0, 1024),
+	}
+}
+
+func (e *CountDistinctSketchStepEvaluator) Next() (bool, int64, StepResult) {
+	ok, ts, r := e.inner.Next()
+	if !ok {
+		return false, 0, CountDistinctSketchVector{}
+	}
+
+	groups := map[uint64]int{}
+	vec := CountDistinctSketchVector{}
+	for _, s := range r.SampleVector() {
+		value := s.Metric.Get(e.expr.Label)
+		if value == "" {
+			continue
+		}
+
+		var groupingKey uint64
+		if e.expr.Grouping.Without {
+			groupingKey, e.buf = s.Metric.HashWithoutLabels(e.buf, e.without...)
+		} else {
+			groupingKey, e.buf = s.Metric.HashForLabels(e.buf, e.expr.Grouping.Groups...)
+		}
+		i, ok := groups[groupingKey]
+		if !ok {
+			var m labels.Labels
+			if e.expr.Grouping.Without {
+				e.lb.Reset(s.Metric)
+				e.lb.Del(e.without...)
+				m = e.lb.Labels()
+			} else {
+				e.lb.Reset(nil)
+				for _, n := range e.expr.Grouping.Groups {
+					e.lb.Set(n, s.Metric.Get(n))
+				}
+				m = e.lb.Labels()
+			}
+			i = len(vec)
+			groups[groupingKey] = i
+			vec = append(vec, CountDistinctSketchSample{
+				T:      ts,
+				F:      newCountDistinctSketch(e.precision),
+				Metric: m,
+			})
+		}
+		vec[i].F.Insert([]byte(value))
+	}
+	return true, ts, vec
+}
+
+func (e *CountDistinctSketchStepEvaluator) Close() error { return e.inner.Close() }
+
+func (e *CountDistinctSketchStepEvaluator) Error() error { return e.inner.Error() }
+
+// MergeCountDistinctSketchVector joins the results from stepEvaluator into a CountDistinctSketchMatrix.
+func MergeCountDistinctSketchVector(next bool, r StepResult, stepEvaluator StepEvaluator) (promql_parser.Value, error) {
+	var result CountDistinctSketchMatrix
+	for next {
+		vec, ok := r.(CountDistinctSketchVector)
+		if !ok {
+			return nil, fmt.Errorf("unexpected step result type: got (%T), want (CountDistinctSketchVector)", r)
+		}
+		result = append(result, vec)
+		next, _, r = stepEvaluator.Next()
+	}
+	return result, stepEvaluator.Error()
+}
+
+// CountDistinctSketchMatrixStepEvaluator steps through a matrix of merged
+// count distinct sketches.
+type CountDistinctSketchMatrixStepEvaluator struct {
+	end, ts time.Time
+	step    time.Duration
+	m       CountDistinctSketchMatrix
+}
+
+func NewCountDistinctSketchMatrixStepEvaluator(m CountDistinctSketchMatrix, params Params) *CountDistinctSketchMatrixStepEvaluator {
+	return &CountDistinctSketchMatrixStepEvaluator{
+		end:  params.End(),
+		ts:   params.Start().Add(-params.Step()), // will be corrected on first Next() call
+		step: params.Step(),
+		m:    m,
+	}
+}
+
+func (e *CountDistinctSketchMatrixStepEvaluator) Next() (bool, int64, StepResult) {
+	e.ts = e.ts.Add(e.step)
+	if e.ts.After(e.end) || len(e.m) == 0 {
+		return false, 0, CountDistinctSketchVector{}
+	}
+
+	vec := e.m[0]
+	e.m = e.m[1:]
+
+	return true, e.ts.UnixMilli(), vec
+}
+
+func (*CountDistinctSketchMatrixStepEvaluator) Close() error { return nil }
+
+func (*CountDistinctSketchMatrixStepEvaluator) Error() error { return nil }
+
+// CountDistinctEstimateStepEvaluator evaluates count distinct sketches into
+// their estimated cardinality.
+type CountDistinctEstimateStepEvaluator struct {
+	inner StepEvaluator
+	err   error
+}
+
+func NewCountDistinctEstimateStepEvaluator(inner StepEvaluator) *CountDistinctEstimateStepEvaluator {
+	return &CountDistinctEstimateStepEvaluator{inner: inner}
+}
+
+func (e *CountDistinctEstimateStepEvaluator) Next() (bool, int64, StepResult) {
+	ok, ts, r := e.inner.Next()
+	if !ok {
+		return false, 0, SampleVector{}
+	}
+	sketches, ok := r.(CountDistinctSketchVector)
+	if !ok {
+		e.err = fmt.Errorf("unexpected step result type: got (%T), want (CountDistinctSketchVector)", r)
+		return false, 0, SampleVector{}
+	}
+
+	vec := make(promql.Vector, len(sketches))
+	for i, s := range sketches {
+		vec[i] = promql.Sample{
+			T:      ts,
+			F:      float64(s.F.Estimate()),
+			Metric: s.Metric,
+		}
+	}
+	return true, ts, SampleVector(vec)
+}
+
+func (e *CountDistinctEstimateStepEvaluator) Close() error { return e.inner.Close() }
+
+func (e *CountDistinctEstimateStepEvaluator) Error() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.inner.Error()
+}
@@ -0,0 +1,68 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+func TestCountDistinctSketchAccumulator(t *testing.T) {
+	expr := syntax.MustParseExpr(`approx_count_distinct by (app) (user, count_over_time({app="foo"}[1m]))`).(*syntax.VectorAggregationExpr)
+	shard := func(users map[string][]int) logqlmodel.Result {
+		vec := promql.Vector{}
+		for app, ids := range users {
+			for _, id := range ids {
+				vec = append(vec, promql.Sample{T: 1000, F: 1, Metric: labels.FromStrings("app", app, "user", fmt.Sprintf("u%d", id))})
+			}
+		}
+		ev := newCountDistinctSketchStepEvaluator(NewVectorStepEvaluator(time.Unix(1, 0), vec), expr, DefaultCountDistinctPrecision)
+		next, _, r := ev.Next()
+		data, err := MergeCountDistinctSketchVector(next, r, ev)
+		require.NoError(t, err)
+
+		// shard results are sent to the frontend as protobuf.
+		proto, err := data.(CountDistinctSketchMatrix).ToProto()
+		require.NoError(t, err)
+		matrix, err := CountDistinctSketchMatrixFromProto(proto)
+		require.NoError(t, err)
+		return logqlmodel.Result{Data: matrix}
+	}
+
+	acc := newCountDistinctSketchAccumulator()
+	require.NoError(t, acc.Accumulate(context.Background(), shard(map[string][]int{"a": {1, 2, 3}, "b": {1}}), 0))
+	require.NoError(t, acc.Accumulate(context.Background(), shard(map[string][]int{"a": {3, 4}}), 1))
+	require.NoError(t, acc.Accumulate(context.Background(), shard(map[string][]int{"b": {1, 2}}), 2))
+
+	results := acc.Result()
+	require.Len(t, results, 1)
+
+	params, err := NewLiteralParams(expr.String(), time.Unix(1, 0), time.Unix(1, 0), 0, 0, 0, 0, nil, nil)
+	require.NoError(t, err)
+	ev := NewCountDistinctEstimateStepEvaluator(NewCountDistinctSketchMatrixStepEvaluator(results[0].Data.(CountDistinctSketchMatrix), params))
+	ok, ts, r := ev.Next()
+	require.True(t, ok)
+	require.Equal(t, int64(1000), ts)
+	require.ElementsMatch(t, promql.Vector{
+		{T: 1000, F: 4, Metric: labels.FromStrings("app", "a")},
+		{T: 1000, F: 2, Metric: labels.FromStrings("app", "b")},
+	}, r.SampleVector())
+
+	ok, _, _ = ev.Next()
+	require.False(t, ok)
+	require.NoError(t, ev.Error())
+}
+
+func TestCountDistinctSketchPrecisionMismatch(t *testing.T) {
+	left := CountDistinctSketchVector{{T: 1000, F: newCountDistinctSketch(14), Metric: labels.EmptyLabels()}}
+	right := CountDistinctSketchVector{{T: 1000, F: newCountDistinctSketch(16), Metric: labels.EmptyLabels()}}
+	_, err := CountDistinctSketchMatrix{left}.Merge(CountDistinctSketchMatrix{right})
+	require.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package logql
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/prometheus/prometheus/promql"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/v3/pkg/logql/sketch"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// approxTopKCardinality is the expected cardinality the count-min sketches of
+// approx_topk are sized for. All shards must use the same size for their
+// sketches to be mergeable.
+const approxTopKCardinality = 10000
+
+func newApproxTopK(k int) (*sketch.Topk, error) {
+	return sketch.NewCMSTopkForCardinality(nil, k, approxTopKCardinality)
+}
+
+// TopKSketchVector is the count-min sketch of the series of a single step.
+type TopKSketchVector struct {
+	T    int64
+	Topk *sketch.Topk
+}
+
+var _ StepResult = TopKSketchVector{}
+
+func (TopKSketchVector) SampleVector() promql.Vector {
+	return promql.Vector{}
+}
+
+func (TopKSketchVector) QuantileSketchVec() ProbabilisticQuantileVector {
+	return ProbabilisticQuantileVector{}
+}
+
+// TopKSketchStepEvaluator observes the samples of its inner evaluator into a
+// topk sketch for each step. Samples are counted by value, so the inner
+// expression is expected to produce integer counts.
+type TopKSketchStepEvaluator struct {
+	inner StepEvaluator
+	k     int
+	err   error
+}
+
+func newTopKSketchStepEvaluator(inner StepEvaluator, k int) *TopKSketchStepEvaluator {
+	return &TopKSketchStepEvaluator{
+		inner: inner,
+		k:     k,
+	}
+}
+
+func (e *TopKSketchStepEvaluator) Next() (bool, int64, StepResult) {
+	ok, ts, r := e.inner.Next()
+	if !ok {
+		return false, 0, TopKSketchVector{}
+	}
+
+	topk, err := newApproxTopK(e.k)
+	if err != nil {
+		e.err = err
+		return false, 0, TopKSketchVector{}
+	}
+	for _, s := range r.SampleVector() {
+		if math.IsNaN(s.F) || s.F < 1 {
+			continue
+		}
+		topk.Add(s.Metric.String(), uint32(math.Min(math.Round(s.F), math.MaxUint32)))
+	}
+	return true, ts, TopKSketchVector{T: ts, Topk: topk}
+}
+
+func (e *TopKSketchStepEvaluator) Close() error { return e.inner.Close() }
+
+func (e *TopKSketchStepEvaluator) Error() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.inner.Error()
+}
+
+// MergeTopKSketchVector joins the results from stepEvaluator into a sketch.TopKMatrix.
+func MergeTopKSketchVector(next bool, r StepResult, stepEvaluator StepEvaluator) (promql_parser.Value, error) {
+	var result sketch.TopKMatrix
+	for next {
+		vec, ok := r.(TopKSketchVector)
+		if !ok {
+			return nil, fmt.Errorf("unexpected step result type: got (%T), want (TopKSketchVector)", r)
+		}
+		result = append(result, sketch.NewTopKVector(vec.Topk, uint64(vec.T)))
+		next, _, r = stepEvaluator.Next()
+	}
+	return result, stepEvaluator.Error()
+}
+
+// TopKSketchMatrixStepEvaluator steps through a matrix of merged topk
+// sketches and returns the k most frequent series of each step.
+type TopKSketchMatrixStepEvaluator struct {
+	m   sketch.TopKMatrix
+	err error
+}
+
+func NewTopKSketchMatrixStepEvaluator(m sketch.TopKMatrix) *TopKSketchMatrixStepEvaluator {
+	return &TopKSketchMatrixStepEvaluator{m: m}
+}
+
+func (e *TopKSketchMatrixStepEvaluator) Next() (bool, int64, StepResult) {
+	if len(e.m) == 0 {
+		return false, 0, SampleVector{}
+	}
+	v := e.m[0]
+	e.m = e.m[1:]
+
+	ts := int64(v.Timestamp())
+	topk := v.Topk().Topk()
+	vec := make(promql.Vector, 0, len(topk))
+	for _, el := range topk {
+		metric, err := syntax.ParseLabels(el.Event)
+		if err != nil {
+			e.err = fmt.Errorf("cannot parse topk sketch series %s: %w", el.Event, err)
+			return false, 0, SampleVector{}
+		}
+		vec = append(vec, promql.Sample{
+			T:      ts,
+			F:      float64(el.Count),
+			Metric: metric,
+		})
+	}
+	return true, ts, SampleVector(vec)
+}
+
+func (*TopKSketchMatrixStepEvaluator) Close() error { return nil }
+
+func (e *TopKSketchMatrixStepEvaluator) Error() error { return e.err }
+
+// mergeTopKSketchMatrix merges the per shard sketches of right into left. The
+// sketches of left must have been created for k.
+func mergeTopKSketchMatrix(left, right sketch.TopKMatrix, k int) (sketch.TopKMatrix, error) {
+	// sketches decoded from shard responses don't know about k, merge them
+	// into new ones that do.
+	sized := make(sketch.TopKMatrix, 0, len(right))
+	for _, v := range right {
+		topk, err := newApproxTopK(k)
+		if err != nil {
+			return nil, err
+		}
+		if err := topk.Merge(v.Topk()); err != nil {
+			return nil, err
+		}
+		sized = append(sized, sketch.NewTopKVector(topk, v.Timestamp()))
+	}
+	if left == nil {
+		return sized, nil
+	}
+	return left.Merge(sized)
+}
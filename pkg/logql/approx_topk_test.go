@@ -0,0 +1,55 @@
+package logql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/sketch"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+func TestTopKSketchAccumulator(t *testing.T) {
+	shard := func(counts map[string]float64) logqlmodel.Result {
+		vec := promql.Vector{}
+		for user, count := range counts {
+			vec = append(vec, promql.Sample{T: 1000, F: count, Metric: labels.FromStrings("user", user)})
+		}
+		ev := newTopKSketchStepEvaluator(NewVectorStepEvaluator(time.Unix(1, 0), vec), 2)
+		next, _, r := ev.Next()
+		data, err := MergeTopKSketchVector(next, r, ev)
+		require.NoError(t, err)
+
+		// shard results are sent to the frontend as protobuf.
+		proto, err := data.(sketch.TopKMatrix).ToProto()
+		require.NoError(t, err)
+		matrix, err := sketch.TopKMatrixFromProto(proto)
+		require.NoError(t, err)
+		return logqlmodel.Result{Data: matrix}
+	}
+
+	acc := newTopKSketchAccumulator(2)
+	require.NoError(t, acc.Accumulate(context.Background(), shard(map[string]float64{"a": 10, "b": 3}), 0))
+	require.NoError(t, acc.Accumulate(context.Background(), shard(map[string]float64{"c": 8}), 1))
+	require.NoError(t, acc.Accumulate(context.Background(), shard(map[string]float64{"b": 9, "c": 0.2}), 2))
+
+	results := acc.Result()
+	require.Len(t, results, 1)
+
+	ev := NewTopKSketchMatrixStepEvaluator(results[0].Data.(sketch.TopKMatrix))
+	ok, ts, r := ev.Next()
+	require.True(t, ok)
+	require.Equal(t, int64(1000), ts)
+	require.Equal(t, promql.Vector{
+		{T: 1000, F: 12, Metric: labels.FromStrings("user", "b")},
+		{T: 1000, F: 10, Metric: labels.FromStrings("user", "a")},
+	}, r.SampleVector())
+
+	ok, _, _ = ev.Next()
+	require.False(t, ok)
+	require.NoError(t, ev.Error())
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/iter"
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/sketch"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/metadata"
@@ -301,6 +302,67 @@ func (e *QuantileSketchMergeExpr) Walk(f syntax.WalkFn) {
 	}
 }
 
+// TopKSketchEvalExpr merges the topk sketches of its downstreams and evaluates
+// them to the k most frequent series.
+type TopKSketchEvalExpr struct {
+	syntax.SampleExpr
+	k           int
+	downstreams []DownstreamSampleExpr
+}
+
+func (e TopKSketchEvalExpr) String() string {
+	var sb strings.Builder
+	for i, d := range e.downstreams {
+		if i >= defaultMaxDepth {
+			break
+		}
+
+		if i > 0 {
+			sb.WriteString(" ++ ")
+		}
+
+		sb.WriteString(d.String())
+	}
+	return fmt.Sprintf("topkSketchEval<%d, %s>", e.k, sb.String())
+}
+
+func (e *TopKSketchEvalExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	for _, d := range e.downstreams {
+		d.Walk(f)
+	}
+}
+
+// CountDistinctSketchEvalExpr merges the count distinct sketches of its
+// downstreams and evaluates them to their estimated cardinality.
+type CountDistinctSketchEvalExpr struct {
+	syntax.SampleExpr
+	downstreams []DownstreamSampleExpr
+}
+
+func (e CountDistinctSketchEvalExpr) String() string {
+	var sb strings.Builder
+	for i, d := range e.downstreams {
+		if i >= defaultMaxDepth {
+			break
+		}
+
+		if i > 0 {
+			sb.WriteString(" ++ ")
+		}
+
+		sb.WriteString(d.String())
+	}
+	return fmt.Sprintf("countDistinctSketchEval<%s>", sb.String())
+}
+
+func (e *CountDistinctSketchEvalExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	for _, d := range e.downstreams {
+		d.Walk(f)
+	}
+}
+
 type MergeFirstOverTimeExpr struct {
 	syntax.SampleExpr
 	downstreams []DownstreamSampleExpr
@@ -435,7 +497,7 @@ func (errorQuerier) SelectSamples(_ context.Context, _ SelectSampleParams) (iter
 func NewDownstreamEvaluator(downstreamer Downstreamer) *DownstreamEvaluator {
 	return &DownstreamEvaluator{
 		Downstreamer:     downstreamer,
-		defaultEvaluator: NewDefaultEvaluator(&errorQuerier{}, 0),
+		defaultEvaluator: NewDefaultEvaluator(&errorQuerier{}, 0, DefaultCountDistinctPrecision),
 	}
 }
 
@@ -527,6 +589,59 @@ func (ev *DownstreamEvaluator) NewStepEvaluator(
 		}
 		inner := NewQuantileSketchMatrixStepEvaluator(matrix, params)
 		return NewQuantileSketchVectorStepEvaluator(inner, *e.quantile), nil
+	case *TopKSketchEvalExpr:
+		queries := make([]DownstreamQuery, len(e.downstreams))
+		for i, d := range e.downstreams {
+			queries[i] = DownstreamQuery{
+				Params: ParamsWithExpressionOverride{
+					Params:             ParamOverridesFromShard(params, d.shard),
+					ExpressionOverride: d.SampleExpr,
+				},
+			}
+		}
+
+		acc := newTopKSketchAccumulator(e.k)
+		results, err := ev.Downstream(ctx, queries, acc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) != 1 {
+			return nil, fmt.Errorf("unexpected results length for sharded approx_topk: got (%d), want (1)", len(results))
+		}
+
+		matrix, ok := results[0].Data.(sketch.TopKMatrix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected matrix type: got (%T), want (sketch.TopKMatrix)", results[0].Data)
+		}
+		return NewTopKSketchMatrixStepEvaluator(matrix), nil
+	case *CountDistinctSketchEvalExpr:
+		queries := make([]DownstreamQuery, len(e.downstreams))
+		for i, d := range e.downstreams {
+			queries[i] = DownstreamQuery{
+				Params: ParamsWithExpressionOverride{
+					Params:             ParamOverridesFromShard(params, d.shard),
+					ExpressionOverride: d.SampleExpr,
+				},
+			}
+		}
+
+		acc := newCountDistinctSketchAccumulator()
+		results, err := ev.Downstream(ctx, queries, acc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) != 1 {
+			return nil, fmt.Errorf("unexpected results length for sharded approx_count_distinct: got (%d), want (1)", len(results))
+		}
+
+		matrix, ok := results[0].Data.(CountDistinctSketchMatrix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected matrix type: got (%T), want (CountDistinctSketchMatrix)", results[0].Data)
+		}
+		inner := NewCountDistinctSketchMatrixStepEvaluator(matrix, params)
+		return NewCountDistinctEstimateStepEvaluator(inner), nil
 	case *MergeFirstOverTimeExpr:
 		queries := make([]DownstreamQuery, len(e.downstreams))
 
@@ -640,7 +755,13 @@ func (ev *DownstreamEvaluator) NewIterator(
 			cur = cur.next
 		}
 
-		acc := NewStreamAccumulator(params)
+		// The stream accumulator only keeps the first lines of all shards, which
+		// could all belong to the same groups of a limit stage. All the results
+		// are kept instead so that the limit is applied to their merged entries.
+		var acc Accumulator = NewStreamAccumulator(params)
+		if len(syntax.ResultStages(e.DownstreamLogSelectorExpr.LogSelectorExpr)) > 0 {
+			acc = NewBufferedAccumulator(len(queries))
+		}
 		results, err := ev.Downstream(ctx, queries, acc)
 		if err != nil {
 			return nil, err
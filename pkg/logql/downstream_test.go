@@ -2,6 +2,7 @@ package logql
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"testing"
 	"time"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
 )
 
@@ -43,6 +45,7 @@ func TestMappingEquivalence(t *testing.T) {
 		{`1 + 1`, false, nil},
 		{`{a="1"}`, false, nil},
 		{`{a="1"} |= "number: 10"`, false, nil},
+		{`{a=~".+"} | limit 2 by (a)`, false, nil},
 		{`rate({a=~".+"}[1s])`, false, nil},
 		{`sum by (a) (rate({a=~".+"}[1s]))`, false, nil},
 		{`sum(rate({a=~".+"}[1s]))`, false, nil},
@@ -55,6 +58,11 @@ func TestMappingEquivalence(t *testing.T) {
 		{`max(count(rate({a=~".+"}[1s])))`, false, nil},
 		{`max(sum by (cluster) (rate({a=~".+"}[1s]))) / count(rate({a=~".+"}[1s]))`, false, nil},
 		{`sum(rate({a=~".+"} |= "foo" != "foo"[1s]) or vector(1))`, false, nil},
+		{`max_over_time(sum by (a) (rate({a=~".+"}[1s]))[5s:2s])`, false, nil},
+		{`sum by (a) (rate({a=~".+"}[1s] @ 10)) / sum by (a) (rate({a=~".+"}[1s]))`, false, nil},
+		{`avg(rate({a=~".+"}[2s] @ end() offset 1s)) by (a)`, true, nil},
+		{`max_over_time(sum by (a) (rate({a=~".+"}[1s]))[5s:2s] @ start())`, false, nil},
+		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s] @ 10) by (a) + 1`, false, []string{ShardQuantileOverTime}},
 		{`avg_over_time({a=~".+"} | logfmt | unwrap value [1s])`, false, nil},
 		{`avg_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, true, nil},
 		{`avg_over_time({a=~".+"} | logfmt | unwrap value [1s]) without (stream)`, true, nil},
@@ -270,6 +278,177 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 	}
 }
 
+func TestApproxTopKSharding(t *testing.T) {
+	var (
+		shards  = 3
+		streams []logproto.Stream
+	)
+	// user i logs 3*(i+1) lines spread over pods on different shards.
+	for i := 0; i < 20; i++ {
+		for pod := 0; pod < 3; pod++ {
+			stream := logproto.Stream{Labels: fmt.Sprintf(`{app="foo", pod="%d", user="u%d"}`, pod, i)}
+			for j := 0; j <= i; j++ {
+				stream.Entries = append(stream.Entries, logproto.Entry{
+					Timestamp: time.Unix(int64(j), 0),
+					Line:      fmt.Sprintf("line=%d", j),
+				})
+			}
+			streams = append(streams, stream)
+		}
+	}
+
+	q := NewMockQuerier(shards, streams)
+	opts := EngineOpts{}
+	regular := NewEngine(opts, q, NoLimits, log.NewNopLogger())
+	sharded := NewDownstreamEngine(opts, MockDownstreamer{regular}, NoLimits, log.NewNopLogger())
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	for _, tc := range []struct {
+		name       string
+		start, end time.Time
+		step       time.Duration
+	}{
+		{"instant", time.Unix(60, 0), time.Unix(60, 0), 0},
+		{"range", time.Unix(20, 0), time.Unix(60, 0), 10 * time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exact, err := NewLiteralParams(`topk(5, sum by (user) (count_over_time({app="foo"}[1m])))`, tc.start, tc.end, tc.step, 0, logproto.FORWARD, 100, nil, nil)
+			require.NoError(t, err)
+			expected, err := regular.Query(exact).Exec(ctx)
+			require.NoError(t, err)
+
+			params, err := NewLiteralParams(`approx_topk(5, sum by (user) (count_over_time({app="foo"}[1m])))`, tc.start, tc.end, tc.step, 0, logproto.FORWARD, 100, nil, nil)
+			require.NoError(t, err)
+
+			mapper := NewShardMapper(NewPowerOfTwoStrategy(ConstantShards(shards)), nilShardMetrics, []string{ShardApproxTopK})
+			noop, _, mapped, err := mapper.Parse(params.GetExpression())
+			require.NoError(t, err)
+			require.False(t, noop)
+			require.IsType(t, &TopKSketchEvalExpr{}, mapped)
+
+			res, err := sharded.Query(ctx, ParamsWithExpressionOverride{Params: params, ExpressionOverride: mapped}).Exec(ctx)
+			require.NoError(t, err)
+
+			// with few series the sketch is exact.
+			switch data := expected.Data.(type) {
+			case promql.Vector:
+				require.ElementsMatch(t, data, res.Data)
+			case promql.Matrix:
+				require.ElementsMatch(t, data, res.Data)
+			}
+		})
+	}
+}
+
+func TestLimitBySharding(t *testing.T) {
+	var (
+		shards  = 3
+		streams []logproto.Stream
+	)
+	// app "chatty" logs from pods on different shards before app "quiet" logs
+	// anything, filling the limit of the query on its own.
+	for pod := 0; pod < 6; pod++ {
+		stream := logproto.Stream{Labels: fmt.Sprintf(`{app="chatty", pod="%d"}`, pod)}
+		for j := 0; j < 10; j++ {
+			stream.Entries = append(stream.Entries, logproto.Entry{
+				Timestamp: time.Unix(int64(j), int64(pod)),
+				Line:      fmt.Sprintf("line=%d", j),
+			})
+		}
+		streams = append(streams, stream)
+	}
+	streams = append(streams, logproto.Stream{
+		Labels:  `{app="quiet", pod="0"}`,
+		Entries: []logproto.Entry{{Timestamp: time.Unix(30, 0), Line: "line=0"}},
+	})
+
+	q := NewMockQuerier(shards, streams)
+	opts := EngineOpts{}
+	regular := NewEngine(opts, q, NoLimits, log.NewNopLogger())
+	sharded := NewDownstreamEngine(opts, MockDownstreamer{regular}, NoLimits, log.NewNopLogger())
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	params, err := NewLiteralParams(`{app=~".+"} | limit 5 by (app)`, time.Unix(0, 0), time.Unix(60, 0), time.Second, 0, logproto.FORWARD, 10, nil, nil)
+	require.NoError(t, err)
+
+	expected, err := regular.Query(params).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), expected.Data.(logqlmodel.Streams).Lines())
+
+	mapper := NewShardMapper(NewPowerOfTwoStrategy(ConstantShards(shards)), nilShardMetrics, nil)
+	noop, _, mapped, err := mapper.Parse(params.GetExpression())
+	require.NoError(t, err)
+	require.False(t, noop)
+
+	res, err := sharded.Query(ctx, ParamsWithExpressionOverride{Params: params, ExpressionOverride: mapped}).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected.Data, res.Data)
+}
+
+func TestApproxCountDistinctSharding(t *testing.T) {
+	var (
+		shards  = 3
+		streams []logproto.Stream
+	)
+	// app i is used by the users 0 to 10*i, each logging from pods on
+	// different shards from time i on.
+	for app := 0; app < 3; app++ {
+		for u := 0; u <= 10*app; u++ {
+			for pod := 0; pod < 2; pod++ {
+				stream := logproto.Stream{Labels: fmt.Sprintf(`{app="%d", pod="%d"}`, app, pod)}
+				for ts := app*10 + u%10; ts < 60; ts += 10 {
+					stream.Entries = append(stream.Entries, logproto.Entry{
+						Timestamp: time.Unix(int64(ts), 0),
+						Line:      fmt.Sprintf(`user=u%d`, u),
+					})
+				}
+				streams = append(streams, stream)
+			}
+		}
+	}
+
+	q := NewMockQuerier(shards, streams)
+	opts := EngineOpts{}
+	regular := NewEngine(opts, q, NoLimits, log.NewNopLogger())
+	sharded := NewDownstreamEngine(opts, MockDownstreamer{regular}, NoLimits, log.NewNopLogger())
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	for _, tc := range []struct {
+		name       string
+		start, end time.Time
+		step       time.Duration
+	}{
+		{"instant", time.Unix(60, 0), time.Unix(60, 0), 0},
+		{"range", time.Unix(0, 0), time.Unix(60, 0), 10 * time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exact, err := NewLiteralParams(`count by (app) (count by (app, user) (count_over_time({app=~".+"} | logfmt [1m])))`, tc.start, tc.end, tc.step, 0, logproto.FORWARD, 100, nil, nil)
+			require.NoError(t, err)
+			expected, err := regular.Query(exact).Exec(ctx)
+			require.NoError(t, err)
+			require.NotEmpty(t, expected.Data)
+
+			params, err := NewLiteralParams(`approx_count_distinct by (app) (user, count_over_time({app=~".+"} | logfmt [1m]))`, tc.start, tc.end, tc.step, 0, logproto.FORWARD, 100, nil, nil)
+			require.NoError(t, err)
+
+			// with few values the sketches are exact.
+			res, err := regular.Query(params).Exec(ctx)
+			require.NoError(t, err)
+			require.Equal(t, expected.Data, res.Data)
+
+			mapper := NewShardMapper(NewPowerOfTwoStrategy(ConstantShards(shards)), nilShardMetrics, []string{ShardApproxCountDistinct})
+			noop, _, mapped, err := mapper.Parse(params.GetExpression())
+			require.NoError(t, err)
+			require.False(t, noop)
+			require.IsType(t, &CountDistinctSketchEvalExpr{}, mapped)
+
+			res, err = sharded.Query(ctx, ParamsWithExpressionOverride{Params: params, ExpressionOverride: mapped}).Exec(ctx)
+			require.NoError(t, err)
+			require.Equal(t, expected.Data, res.Data)
+		})
+	}
+}
+
 func TestShardCounter(t *testing.T) {
 	var (
 		shards   = 3
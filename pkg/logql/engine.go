@@ -5,6 +5,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/iter"
 	"github.com/grafana/loki/v3/pkg/logproto"
+	logql_log "github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
@@ -146,18 +148,35 @@ type EngineOpts struct {
 
 	// LogExecutingQuery will control if we log the query when Exec is called.
 	LogExecutingQuery bool `yaml:"-"`
+
+	// CountDistinctPrecision is the precision of the HyperLogLog sketches
+	// used by approx_count_distinct.
+	CountDistinctPrecision uint `yaml:"count_distinct_precision"`
 }
 
 func (opts *EngineOpts) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.DurationVar(&opts.MaxLookBackPeriod, prefix+".engine.max-lookback-period", 30*time.Second, "The maximum amount of time to look back for log lines. Used only for instant log queries.")
+	f.UintVar(&opts.CountDistinctPrecision, prefix+".engine.count-distinct-precision", DefaultCountDistinctPrecision, "The precision of the HyperLogLog sketches used by approx_count_distinct. Supported values are 14 and 16. A higher precision is more accurate but uses 4 times the memory. All queriers must use the same precision for sharded queries.")
 	// Log executing query by default
 	opts.LogExecutingQuery = true
 }
 
+func (opts *EngineOpts) Validate() error {
+	switch opts.CountDistinctPrecision {
+	case 14, 16:
+		return nil
+	default:
+		return fmt.Errorf("invalid count distinct precision %d: must be 14 or 16", opts.CountDistinctPrecision)
+	}
+}
+
 func (opts *EngineOpts) applyDefault() {
 	if opts.MaxLookBackPeriod == 0 {
 		opts.MaxLookBackPeriod = 30 * time.Second
 	}
+	if opts.CountDistinctPrecision == 0 {
+		opts.CountDistinctPrecision = DefaultCountDistinctPrecision
+	}
 }
 
 // Engine is the LogQL engine.
@@ -176,7 +195,7 @@ func NewEngine(opts EngineOpts, q Querier, l Limits, logger log.Logger) *Engine
 	}
 	return &Engine{
 		logger:           logger,
-		evaluatorFactory: NewDefaultEvaluator(q, opts.MaxLookBackPeriod),
+		evaluatorFactory: NewDefaultEvaluator(q, opts.MaxLookBackPeriod, uint8(opts.CountDistinctPrecision)),
 		limits:           l,
 		opts:             opts,
 	}
@@ -217,6 +236,8 @@ func (q *query) resultLength(res promql_parser.Value) int {
 		return r.TotalSamples()
 	case logqlmodel.Streams:
 		return int(r.Lines())
+	case logqlmodel.Table:
+		return len(r.Rows)
 	case ProbabilisticQuantileMatrix:
 		return len(r)
 	default:
@@ -295,10 +316,15 @@ func (q *query) Eval(ctx context.Context) (promql_parser.Value, error) {
 		return value, err
 
 	case syntax.LogSelectorExpr:
+		if stats, selector, ok := syntax.SplitStatsStage(e); ok {
+			return q.evalStats(ctx, stats, selector)
+		}
+
 		itr, err := q.evaluator.NewIterator(ctx, e, q.params)
 		if err != nil {
 			return nil, err
 		}
+		itr = NewResultStagesIterator(itr, e, false)
 
 		encodingFlags := httpreq.ExtractEncodingFlagsFromCtx(ctx)
 		if encodingFlags.Has(httpreq.FlagCategorizeLabels) {
@@ -313,6 +339,56 @@ func (q *query) Eval(ctx context.Context) (promql_parser.Value, error) {
 	}
 }
 
+// statsParams removes the limit from the selection of the log lines of a
+// stats query: its limit applies to the rows of the table.
+type statsParams struct {
+	Params
+}
+
+func (statsParams) Limit() uint32 { return math.MaxInt32 }
+
+// evalStats aggregates the log lines selected by the query into a table.
+// Stats queries are neither split nor sharded, the number of log lines they
+// aggregate is bounded by the max_stats_entries_per_query limit instead.
+func (q *query) evalStats(ctx context.Context, stats *syntax.StatsExpr, selector syntax.LogSelectorExpr) (promql_parser.Value, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	maxEntriesCapture := func(id string) int { return q.limits.MaxStatsEntriesPerQuery(ctx, id) }
+	maxEntries := validation.SmallestPositiveIntPerTenant(tenantIDs, maxEntriesCapture)
+
+	itr, err := q.evaluator.NewIterator(ctx, selector, statsParams{q.params})
+	if err != nil {
+		return nil, err
+	}
+	defer util.LogErrorWithContext(ctx, "closing iterator", itr.Close)
+
+	var (
+		aggregator = logql_log.NewStatsAggregator(stats.Aggregations, stats.Groups)
+		streamLbs  string
+		lbs        labels.Labels
+		entries    int
+	)
+	for itr.Next() {
+		entries++
+		if maxEntries > 0 && entries > maxEntries {
+			return nil, logqlmodel.NewStatsEntriesLimitError(maxEntries)
+		}
+		if itr.Labels() != streamLbs || lbs == nil {
+			streamLbs = itr.Labels()
+			if lbs, err = syntax.ParseLabels(streamLbs); err != nil {
+				return nil, err
+			}
+		}
+		aggregator.Process(lbs)
+	}
+	if err := itr.Err(); err != nil {
+		return nil, err
+	}
+	return aggregator.Table(int(q.params.Limit())), nil
+}
+
 func (q *query) checkBlocked(ctx context.Context, tenants []string) bool {
 	blocker := newQueryBlocker(ctx, q)
 
@@ -354,6 +430,10 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 		return nil, err
 	}
 
+	// @ start() and @ end() refer to the bounds of the whole query, resolve them
+	// before subqueries evaluate their inner query with different bounds.
+	syntax.ResolveAtModifiers(expr, q.params.Start(), q.params.End())
+
 	stepEvaluator, err := q.evaluator.NewStepEvaluator(ctx, q.evaluator, expr, q.params)
 	if err != nil {
 		return nil, err
@@ -377,6 +457,10 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 			return q.JoinSampleVector(next, vec, stepEvaluator, maxSeries, mfl)
 		case ProbabilisticQuantileVector:
 			return MergeQuantileSketchVector(next, vec, stepEvaluator, q.params)
+		case TopKSketchVector:
+			return MergeTopKSketchVector(next, vec, stepEvaluator)
+		case CountDistinctSketchVector:
+			return MergeCountDistinctSketchVector(next, vec, stepEvaluator)
 		default:
 			return nil, fmt.Errorf("unsupported result type: %T", r)
 		}
@@ -475,6 +559,11 @@ func (q *query) checkIntervalLimit(expr syntax.SampleExpr, limit time.Duration)
 				return
 			}
 			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Left.Interval), model.Duration(limit))
+		case *syntax.SubqueryExpr:
+			if e.Range <= limit {
+				return
+			}
+			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Range), model.Duration(limit))
 		}
 	})
 	return err
@@ -585,6 +674,50 @@ func readStreams(i iter.EntryIterator, size uint32, dir logproto.Direction, inte
 	return result, i.Err()
 }
 
+// NewResultStagesIterator applies the limit and dedup stages ending a log query
+// to the entries of the iterator, which must be sorted in the direction of the
+// query. If pushdown is true, only the limit stages preceding any dedup stage
+// are applied: unlike deduplication, the first entries of each group are
+// always part of the first entries of that group of any subset of the streams,
+// so they can be selected before the entries of other sources are merged.
+func NewResultStagesIterator(it iter.EntryIterator, expr syntax.LogSelectorExpr, pushdown bool) iter.EntryIterator {
+	for _, s := range syntax.ResultStages(unwrapLogSelectorExpr(expr)) {
+		switch e := s.(type) {
+		case *syntax.LimitByExpr:
+			it = iter.NewLimitByIterator(it, e.Limit, e.Groups)
+		case *syntax.DedupExpr:
+			if pushdown {
+				return it
+			}
+			it = iter.NewDedupIterator(it, e.Groups)
+		}
+	}
+	return it
+}
+
+// ApplyResultStages applies the limit and dedup stages ending a log query to
+// streams merged from the results of multiple queries.
+func ApplyResultStages(streams logqlmodel.Streams, expr syntax.LogSelectorExpr, direction logproto.Direction) (logqlmodel.Streams, error) {
+	if len(syntax.ResultStages(unwrapLogSelectorExpr(expr))) == 0 {
+		return streams, nil
+	}
+	it := NewResultStagesIterator(iter.NewStreamsIterator(streams, direction), expr, false)
+	defer util.LogError("closing iterator", it.Close)
+	return readStreams(it, math.MaxUint32, direction, 0)
+}
+
+// unwrapLogSelectorExpr returns the log query of downstream expressions.
+func unwrapLogSelectorExpr(expr syntax.LogSelectorExpr) syntax.LogSelectorExpr {
+	switch e := expr.(type) {
+	case DownstreamLogSelectorExpr:
+		return e.LogSelectorExpr
+	case *ConcatLogSelectorExpr:
+		return e.DownstreamLogSelectorExpr.LogSelectorExpr
+	default:
+		return expr
+	}
+}
+
 type groupedAggregation struct {
 	labels      labels.Labels
 	value       float64
@@ -194,6 +194,54 @@ func TestEngine_InstantQuery(t *testing.T) {
 			},
 			promql.Vector{promql.Sample{T: 60 * 1000, F: 1, Metric: labels.FromStrings("app", "foo")}},
 		},
+		{
+			// subquery evaluated at 90 and 120 with an offset of 30s: counts are 3 and 1.
+			`sum_over_time(count_over_time({app="foo"}[30s])[1m:30s] offset 30s)`, time.Unix(150, 0), logproto.FORWARD, 10,
+			[][]logproto.Series{
+				{
+					{
+						Labels: `{app="foo"}`,
+						Samples: []logproto.Sample{
+							{Timestamp: time.Unix(10, 0).UnixNano(), Hash: 1, Value: 1.},
+							{Timestamp: time.Unix(20, 0).UnixNano(), Hash: 2, Value: 1.},
+							{Timestamp: time.Unix(40, 0).UnixNano(), Hash: 3, Value: 1.},
+							{Timestamp: time.Unix(70, 0).UnixNano(), Hash: 4, Value: 1.},
+							{Timestamp: time.Unix(80, 0).UnixNano(), Hash: 5, Value: 1.},
+							{Timestamp: time.Unix(90, 0).UnixNano(), Hash: 6, Value: 1.},
+							{Timestamp: time.Unix(100, 0).UnixNano(), Hash: 7, Value: 1.},
+						},
+					},
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(60, 0), End: time.Unix(120, 0), Selector: `count_over_time({app="foo"}[30s])`}},
+			},
+			promql.Vector{promql.Sample{T: 150 * 1000, F: 4, Metric: labels.FromStrings("app", "foo")}},
+		},
+		{
+			// subquery pinned at 150 is evaluated at 90 and 120 with an offset of 30s.
+			`sum_over_time(count_over_time({app="foo"}[30s])[1m:30s] @ 150 offset 30s)`, time.Unix(300, 0), logproto.FORWARD, 10,
+			[][]logproto.Series{
+				{
+					{
+						Labels: `{app="foo"}`,
+						Samples: []logproto.Sample{
+							{Timestamp: time.Unix(10, 0).UnixNano(), Hash: 1, Value: 1.},
+							{Timestamp: time.Unix(20, 0).UnixNano(), Hash: 2, Value: 1.},
+							{Timestamp: time.Unix(40, 0).UnixNano(), Hash: 3, Value: 1.},
+							{Timestamp: time.Unix(70, 0).UnixNano(), Hash: 4, Value: 1.},
+							{Timestamp: time.Unix(80, 0).UnixNano(), Hash: 5, Value: 1.},
+							{Timestamp: time.Unix(90, 0).UnixNano(), Hash: 6, Value: 1.},
+							{Timestamp: time.Unix(100, 0).UnixNano(), Hash: 7, Value: 1.},
+						},
+					},
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(60, 0), End: time.Unix(120, 0), Selector: `count_over_time({app="foo"}[30s])`}},
+			},
+			promql.Vector{promql.Sample{T: 300 * 1000, F: 4, Metric: labels.FromStrings("app", "foo")}},
+		},
 		{
 			`rate({app="foo"}[30s])`, time.Unix(60, 0), logproto.FORWARD, 10,
 			[][]logproto.Series{
@@ -248,6 +296,26 @@ func TestEngine_InstantQuery(t *testing.T) {
 			},
 			promql.Vector{promql.Sample{T: 90 * 1000, F: 6, Metric: labels.FromStrings("app", "foo")}},
 		},
+		{
+			`count_over_time({app="foo"} |~".+bar" [1m] @ 60)`, time.Unix(300, 0), logproto.BACKWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(10, identity), `{app="foo"}`)}, // 10 , 20 , 30 .. 60 = 6 total
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `count_over_time({app="foo"}|~".+bar"[1m] @ 60)`}},
+			},
+			promql.Vector{promql.Sample{T: 300 * 1000, F: 6, Metric: labels.FromStrings("app", "foo")}},
+		},
+		{
+			`count_over_time({app="foo"} |~".+bar" [1m] @ 90 offset 30s)`, time.Unix(300, 0), logproto.BACKWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(10, identity), `{app="foo"}`)}, // 10 , 20 , 30 .. 60 = 6 total
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `count_over_time({app="foo"}|~".+bar"[1m] @ 90 offset 30s)`}},
+			},
+			promql.Vector{promql.Sample{T: 300 * 1000, F: 6, Metric: labels.FromStrings("app", "foo")}},
+		},
 		{
 			`count_over_time(({app="foo"} |~".+bar")[5m])`, time.Unix(5*60, 0), logproto.BACKWARD, 10,
 			[][]logproto.Series{
@@ -438,6 +506,42 @@ func TestEngine_InstantQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			`label_join(sum(count_over_time({app=~"foo|bar"} |~".+bar" [1m])) by (namespace,app), "new", "-", "namespace", "app")`,
+			time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo", namespace="a"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `sum by (namespace,app) (count_over_time({app=~"foo|bar"} |~".+bar" [1m])) `}},
+			},
+			promql.Vector{
+				promql.Sample{
+					T: 60 * 1000, F: 6,
+					Metric: labels.FromStrings("app", "foo",
+						"namespace", "a",
+						"new", "a-foo",
+					),
+				},
+			},
+		},
+		{
+			`histogram_quantile(0.125, sum by (le) (count_over_time({app="foo"}[1m])))`,
+			time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(20, identity), `{app="foo", le="1"}`),
+					newSeries(testSize, factor(5, identity), `{app="foo", le="2"}`),
+					newSeries(testSize, identity, `{app="foo", le="+Inf"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `sum by (le) (count_over_time({app="foo"}[1m]))`}},
+			},
+			promql.Vector{promql.Sample{T: 60 * 1000, F: 1.5, Metric: labels.EmptyLabels()}},
+		},
 		{
 			`count(count_over_time({app=~"foo|bar"} |~".+bar" [1m])) without (app)`, time.Unix(60, 0), logproto.FORWARD, 100,
 			[][]logproto.Series{
@@ -644,6 +748,28 @@ func TestEngine_InstantQuery(t *testing.T) {
 				Metric: labels.EmptyLabels(),
 			}},
 		},
+		{
+			// 1970-01-03 is a Saturday
+			`hour() * 10 + day_of_week()`,
+			time.Unix(2*24*3600+14*3600, 0), logproto.FORWARD, 100,
+			nil,
+			nil,
+			promql.Vector{promql.Sample{
+				T: (2*24*3600 + 14*3600) * 1000, F: 146,
+				Metric: labels.Labels(nil),
+			}},
+		},
+		{
+			`clamp_max(ceil(rate({app="foo"}[1m]) * 1.5), 1.5)`,
+			time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app="foo"}[1m])`}},
+			},
+			promql.Vector{promql.Sample{T: 60 * 1000, F: 1.5, Metric: labels.FromStrings("app", "foo")}},
+		},
 		{
 			// single comparison
 			`1 == 1`,
@@ -1003,6 +1129,42 @@ func TestEngine_RangeQuery(t *testing.T) {
 			},
 			logqlmodel.Streams([]logproto.Stream{newStream(10, identity, `{app="foo"}`)}),
 		},
+		{
+			`{app="foo"} | logfmt | stats count(), avg(duration) by (status)`, time.Unix(0, 0), time.Unix(30, 0), time.Second, 0, logproto.BACKWARD, 10,
+			[][]logproto.Stream{
+				{
+					newStream(5, identity, `{app="foo", duration="2", status="200"}`),
+					newStream(3, identity, `{app="foo", duration="4", status="500"}`),
+					newStream(2, identity, `{app="foo", duration="6", status="200"}`),
+				},
+			},
+			[]SelectLogParams{
+				{&logproto.QueryRequest{Direction: logproto.BACKWARD, Start: time.Unix(0, 0), End: time.Unix(30, 0), Limit: math.MaxInt32, Selector: `{app="foo"} | logfmt`}},
+			},
+			logqlmodel.Table{
+				Columns: []string{"status", "count()", "avg(duration)"},
+				Rows: [][]string{
+					{"200", "7", "3.142857142857143"},
+					{"500", "3", "4"},
+				},
+			},
+		},
+		{
+			`{app="foo"} | stats count() by (status)`, time.Unix(0, 0), time.Unix(30, 0), time.Second, 0, logproto.FORWARD, 1,
+			[][]logproto.Stream{
+				{
+					newStream(5, identity, `{app="foo", status="200"}`),
+					newStream(3, identity, `{app="foo", status="500"}`),
+				},
+			},
+			[]SelectLogParams{
+				{&logproto.QueryRequest{Direction: logproto.FORWARD, Start: time.Unix(0, 0), End: time.Unix(30, 0), Limit: math.MaxInt32, Selector: `{app="foo"}`}},
+			},
+			logqlmodel.Table{
+				Columns: []string{"status", "count()"},
+				Rows:    [][]string{{"200", "5"}},
+			},
+		},
 		{
 			`{app="food"}`, time.Unix(0, 0), time.Unix(30, 0), 0, 2 * time.Second, logproto.FORWARD, 10,
 			[][]logproto.Stream{
@@ -1073,6 +1235,39 @@ func TestEngine_RangeQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			`count_over_time({app="foo"} |~".+bar" [1m] @ start())`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.BACKWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(10, identity), `{app="foo"}`)}, // 10 , 20 , 30 .. 60 = 6 total
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `count_over_time({app="foo"}|~".+bar"[1m] @ 60)`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 6}, {T: 90 * 1000, F: 6}, {T: 120 * 1000, F: 6}},
+				},
+			},
+		},
+		{
+			`sum(count_over_time({app="foo"} |~".+bar" [1m] @ end())) / sum(count_over_time({app="foo"} |~".+bar" [30s]))`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.BACKWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(10, identity), `{app="foo"}`)},
+				{newSeries(testSize, factor(10, identity), `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(60, 0), End: time.Unix(120, 0), Selector: `sum(count_over_time({app="foo"}|~".+bar"[1m] @ 120))`}},
+				{&logproto.SampleQueryRequest{Start: time.Unix(30, 0), End: time.Unix(120, 0), Selector: `sum(count_over_time({app="foo"}|~".+bar"[30s]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.EmptyLabels(),
+					// 6 lines in the pinned minute against 3 lines in each 30s range.
+					Floats: []promql.FPoint{{T: 60 * 1000, F: 2}, {T: 90 * 1000, F: 2}, {T: 120 * 1000, F: 2}},
+				},
+			},
+		},
 		{
 			`count_over_time({app="foo"} |~".+bar" [1m])`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.BACKWARD, 10,
 			[][]logproto.Series{
@@ -2215,6 +2410,39 @@ func TestEngine_RangeQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			// subquery: inner counts at 30, 60, 90 and 120 are 2, 1, 3 and 1.
+			`max_over_time(count_over_time({app="foo"}[30s])[1m:30s])`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 10,
+			[][]logproto.Series{
+				{
+					{
+						Labels: `{app="foo"}`,
+						Samples: []logproto.Sample{
+							{Timestamp: time.Unix(10, 0).UnixNano(), Hash: 1, Value: 1.},
+							{Timestamp: time.Unix(20, 0).UnixNano(), Hash: 2, Value: 1.},
+							{Timestamp: time.Unix(40, 0).UnixNano(), Hash: 3, Value: 1.},
+							{Timestamp: time.Unix(70, 0).UnixNano(), Hash: 4, Value: 1.},
+							{Timestamp: time.Unix(80, 0).UnixNano(), Hash: 5, Value: 1.},
+							{Timestamp: time.Unix(90, 0).UnixNano(), Hash: 6, Value: 1.},
+							{Timestamp: time.Unix(100, 0).UnixNano(), Hash: 7, Value: 1.},
+						},
+					},
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(120, 0), Selector: `count_over_time({app="foo"}[30s])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{
+						{T: 60000, F: 2},
+						{T: 90000, F: 3},
+						{T: 120000, F: 3},
+					},
+				},
+			},
+		},
 		{
 			// tests combining two streams + unwrap
 			`sum(rate({job="foo"} | logfmt | bar > 0 | unwrap bazz [30s]))`, time.Unix(60, 0), time.Unix(120, 0), 30 * time.Second, 0, logproto.FORWARD, 10,
@@ -2470,6 +2698,29 @@ func TestEngine_MaxSeries(t *testing.T) {
 	}
 }
 
+func TestEngine_MaxStatsEntries(t *testing.T) {
+	for _, test := range []struct {
+		maxEntries     int
+		expectLimitErr bool
+	}{
+		{0, false},
+		{10000000, false},
+		{1000, true},
+	} {
+		t.Run(fmt.Sprint(test.maxEntries), func(t *testing.T) {
+			eng := NewEngine(EngineOpts{}, getLocalQuerier(100000), &fakeLimits{maxSeries: 1, maxStats: test.maxEntries}, log.NewNopLogger())
+			params, err := NewLiteralParams(`{app="foo"} | stats count()`, time.Unix(0, 0), time.Unix(100000, 0), 0, 0, logproto.FORWARD, 1000, nil, nil)
+			require.NoError(t, err)
+			_, err = eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+			if test.expectLimitErr {
+				require.ErrorIs(t, err, logqlmodel.ErrLimit)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestEngine_MaxRangeInterval(t *testing.T) {
 	eng := NewEngine(EngineOpts{}, getLocalQuerier(100000), &fakeLimits{rangeLimit: 24 * time.Hour, maxSeries: 100000}, log.NewNopLogger())
 
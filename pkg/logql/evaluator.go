@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -282,15 +283,17 @@ func EvaluatorUnsupportedType(expr syntax.Expr, ev EvaluatorFactory) error {
 }
 
 type DefaultEvaluator struct {
-	maxLookBackPeriod time.Duration
-	querier           Querier
+	maxLookBackPeriod      time.Duration
+	countDistinctPrecision uint8
+	querier                Querier
 }
 
 // NewDefaultEvaluator constructs a DefaultEvaluator
-func NewDefaultEvaluator(querier Querier, maxLookBackPeriod time.Duration) *DefaultEvaluator {
+func NewDefaultEvaluator(querier Querier, maxLookBackPeriod time.Duration, countDistinctPrecision uint8) *DefaultEvaluator {
 	return &DefaultEvaluator{
-		querier:           querier,
-		maxLookBackPeriod: maxLookBackPeriod,
+		querier:                querier,
+		maxLookBackPeriod:      maxLookBackPeriod,
+		countDistinctPrecision: countDistinctPrecision,
 	}
 }
 
@@ -325,44 +328,88 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 ) (StepEvaluator, error) {
 	switch e := expr.(type) {
 	case *syntax.VectorAggregationExpr:
+		switch e.Operation {
+		case syntax.OpTypeApproxTopK:
+			// without sharding approx_topk is evaluated exactly.
+			cpy := *e
+			cpy.Operation = syntax.OpTypeTopK
+			return newVectorAggEvaluator(ctx, nextEvFactory, &cpy, q)
+		case syntax.OpTypeTopKSketch:
+			inner, err := nextEvFactory.NewStepEvaluator(ctx, nextEvFactory, e.Left, q)
+			if err != nil {
+				return nil, err
+			}
+			return newTopKSketchStepEvaluator(inner, e.Params), nil
+		case syntax.OpTypeApproxCountDistinct, syntax.OpTypeCountDistinctSketch:
+			inner, err := nextEvFactory.NewStepEvaluator(ctx, nextEvFactory, e.Left, q)
+			if err != nil {
+				return nil, err
+			}
+			sketches := newCountDistinctSketchStepEvaluator(inner, e, ev.countDistinctPrecision)
+			if e.Operation == syntax.OpTypeCountDistinctSketch {
+				return sketches, nil
+			}
+			return NewCountDistinctEstimateStepEvaluator(sketches), nil
+		}
 		if rangExpr, ok := e.Left.(*syntax.RangeAggregationExpr); ok && e.Operation == syntax.OpTypeSum {
 			// if range expression is wrapped with a vector expression
 			// we should send the vector expression for allowing reducing labels at the source.
-			nextEvFactory = SampleEvaluatorFunc(func(ctx context.Context, _ SampleEvaluatorFactory, _ syntax.SampleExpr, _ Params) (StepEvaluator, error) {
-				it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
-					&logproto.SampleQueryRequest{
-						// extend startTs backwards by step
-						Start: q.Start().Add(-rangExpr.Left.Interval).Add(-rangExpr.Left.Offset),
-						// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
-						End: q.End().Add(-rangExpr.Left.Offset).Add(time.Nanosecond),
-						// intentionally send the vector for reducing labels.
-						Selector: e.String(),
-						Shards:   q.Shards(),
-						Plan: &plan.QueryPlan{
-							AST: expr,
-						},
-						StoreChunks: q.GetStoreChunks(),
-					},
-				})
-				if err != nil {
-					return nil, err
-				}
-				return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), rangExpr, q, rangExpr.Left.Offset)
+			nextEvFactory = SampleEvaluatorFunc(func(ctx context.Context, _ SampleEvaluatorFactory, _ syntax.SampleExpr, q Params) (StepEvaluator, error) {
+				// intentionally send the vector for reducing labels.
+				return ev.newRangeAggStepEvaluator(ctx, rangExpr, e, q)
 			})
 		}
 		return newVectorAggEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.RangeAggregationExpr:
+		return ev.newRangeAggStepEvaluator(ctx, e, e, q)
+	case *syntax.BinOpExpr:
+		return newBinOpStepEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.LabelReplaceExpr:
+		return newLabelReplaceEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.LabelJoinExpr:
+		return newLabelJoinEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.HistogramQuantileExpr:
+		return newHistogramQuantileEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.SubqueryExpr:
+		if e.At != nil {
+			return newPinnedStepEvaluator(q, e.At, func(pinned Params) (StepEvaluator, error) {
+				return newSubqueryEvaluator(ctx, nextEvFactory, e, pinned)
+			})
+		}
+		return newSubqueryEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.FunctionExpr:
+		return newFunctionEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.VectorExpr:
+		val, err := e.Value()
+		if err != nil {
+			return nil, err
+		}
+		return newVectorIterator(val, q.Step().Milliseconds(), q.Start().UnixMilli(), q.End().UnixMilli()), nil
+	default:
+		return nil, EvaluatorUnsupportedType(e, ev)
+	}
+}
+
+// newRangeAggStepEvaluator selects the samples of the range aggregation rangExpr
+// and evaluates it. The selector expression is sent to the querier, it is either
+// the range aggregation itself or a vector aggregation wrapping it.
+func (ev *DefaultEvaluator) newRangeAggStepEvaluator(
+	ctx context.Context,
+	rangExpr *syntax.RangeAggregationExpr,
+	selector syntax.SampleExpr,
+	q Params,
+) (StepEvaluator, error) {
+	newEvaluator := func(q Params) (StepEvaluator, error) {
 		it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
 			&logproto.SampleQueryRequest{
 				// extend startTs backwards by step
-				Start: q.Start().Add(-e.Left.Interval).Add(-e.Left.Offset),
+				Start: q.Start().Add(-rangExpr.Left.Interval).Add(-rangExpr.Left.Offset),
 				// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
-				End: q.End().Add(-e.Left.Offset).Add(time.Nanosecond),
-				// intentionally send the vector for reducing labels.
-				Selector: e.String(),
+				End:      q.End().Add(-rangExpr.Left.Offset).Add(time.Nanosecond),
+				Selector: selector.String(),
 				Shards:   q.Shards(),
 				Plan: &plan.QueryPlan{
-					AST: expr,
+					AST: selector,
 				},
 				StoreChunks: q.GetStoreChunks(),
 			},
@@ -370,20 +417,12 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 		if err != nil {
 			return nil, err
 		}
-		return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), e, q, e.Left.Offset)
-	case *syntax.BinOpExpr:
-		return newBinOpStepEvaluator(ctx, nextEvFactory, e, q)
-	case *syntax.LabelReplaceExpr:
-		return newLabelReplaceEvaluator(ctx, nextEvFactory, e, q)
-	case *syntax.VectorExpr:
-		val, err := e.Value()
-		if err != nil {
-			return nil, err
-		}
-		return newVectorIterator(val, q.Step().Milliseconds(), q.Start().UnixMilli(), q.End().UnixMilli()), nil
-	default:
-		return nil, EvaluatorUnsupportedType(e, ev)
+		return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), rangExpr, q, rangExpr.Left.Offset)
+	}
+	if rangExpr.Left.At != nil {
+		return newPinnedStepEvaluator(q, rangExpr.Left.At, newEvaluator)
 	}
+	return newEvaluator(q)
 }
 
 func newVectorAggEvaluator(
@@ -1298,6 +1337,179 @@ func (e *LabelReplaceEvaluator) Error() error {
 	return e.nextEvaluator.Error()
 }
 
+// newLabelJoinEvaluator
+func newLabelJoinEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.LabelJoinExpr,
+	q Params,
+) (*LabelJoinEvaluator, error) {
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelJoinEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+	}, nil
+}
+
+type LabelJoinEvaluator struct {
+	nextEvaluator StepEvaluator
+	labelCache    map[uint64]labels.Labels
+	expr          *syntax.LabelJoinExpr
+	sb            strings.Builder
+}
+
+func (e *LabelJoinEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	if e.labelCache == nil {
+		e.labelCache = make(map[uint64]labels.Labels, len(vec))
+	}
+	for i, s := range vec {
+		hash := s.Metric.Hash()
+		if labels, ok := e.labelCache[hash]; ok {
+			vec[i].Metric = labels
+			continue
+		}
+		e.sb.Reset()
+		for j, src := range e.expr.Src {
+			if j > 0 {
+				e.sb.WriteString(e.expr.Separator)
+			}
+			e.sb.WriteString(s.Metric.Get(src))
+		}
+
+		lb := labels.NewBuilder(s.Metric).Del(e.expr.Dst)
+		if e.sb.Len() > 0 {
+			lb.Set(e.expr.Dst, e.sb.String())
+		}
+		outLbs := lb.Labels()
+		e.labelCache[hash] = outLbs
+		vec[i].Metric = outLbs
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *LabelJoinEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *LabelJoinEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+// newFunctionEvaluator
+func newFunctionEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.FunctionExpr,
+	q Params,
+) (*FunctionEvaluator, error) {
+	fn, err := sampleFunction(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextEvaluator StepEvaluator
+	if expr.Left == nil {
+		// functions without argument are applied to the evaluation time.
+		nextEvaluator = newVectorIterator(0, q.Step().Milliseconds(), q.Start().UnixMilli(), q.End().UnixMilli())
+	} else {
+		nextEvaluator, err = evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &FunctionEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		fn:            fn,
+	}, nil
+}
+
+type FunctionEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.FunctionExpr
+	fn            func(float64) float64
+}
+
+func (e *FunctionEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	useTime := e.expr.Left == nil || e.expr.Function == syntax.OpFuncTimestamp
+	for i := range vec {
+		if useTime {
+			vec[i].F = float64(vec[i].T) / 1e3
+		}
+		vec[i].F = e.fn(vec[i].F)
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *FunctionEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *FunctionEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+// sampleFunction returns the function applied to every sample value.
+func sampleFunction(expr *syntax.FunctionExpr) (func(float64) float64, error) {
+	switch expr.Function {
+	case syntax.OpFuncAbs:
+		return math.Abs, nil
+	case syntax.OpFuncCeil:
+		return math.Ceil, nil
+	case syntax.OpFuncFloor:
+		return math.Floor, nil
+	case syntax.OpFuncRound:
+		toNearest := 1.
+		if len(expr.Args) > 0 {
+			toNearest = expr.Args[0]
+		}
+		// Rounding to the inverse avoids floating point errors, e.g. 0.3/0.1.
+		toNearestInverse := 1.0 / toNearest
+		return func(v float64) float64 {
+			return math.Floor(v*toNearestInverse+0.5) / toNearestInverse
+		}, nil
+	case syntax.OpFuncClampMin:
+		lower := expr.Args[0]
+		return func(v float64) float64 { return math.Max(lower, v) }, nil
+	case syntax.OpFuncClampMax:
+		upper := expr.Args[0]
+		return func(v float64) float64 { return math.Min(upper, v) }, nil
+	case syntax.OpFuncLn:
+		return math.Log, nil
+	case syntax.OpFuncExp:
+		return math.Exp, nil
+	case syntax.OpFuncSqrt:
+		return math.Sqrt, nil
+	case syntax.OpFuncTimestamp:
+		return func(v float64) float64 { return v }, nil
+	case syntax.OpFuncHour:
+		return func(v float64) float64 {
+			return float64(time.Unix(int64(v), 0).UTC().Hour())
+		}, nil
+	case syntax.OpFuncDayOfWeek:
+		return func(v float64) float64 {
+			return float64(time.Unix(int64(v), 0).UTC().Weekday())
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported function %s", expr.Function)
+	}
+}
+
 // This is to replace missing timeseries during absent_over_time aggregation.
 func absentLabels(expr syntax.SampleExpr) (labels.Labels, error) {
 	m := labels.Labels{}
@@ -1,5 +1,7 @@
 package logql
 
+import "time"
+
 // MaxChildrenDisplay defines the maximum number of children that should be
 // shown by explain.
 const MaxChildrenDisplay = 3
@@ -14,6 +16,31 @@ func (e *LabelReplaceEvaluator) Explain(parent Node) {
 	e.nextEvaluator.Explain(b)
 }
 
+func (e *LabelJoinEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s LabelJoin", e.expr.Dst)
+	e.nextEvaluator.Explain(b)
+}
+
+func (e *HistogramQuantileEvaluator) Explain(parent Node) {
+	b := parent.Childf("[%v] HistogramQuantile", e.expr.Quantile)
+	e.nextEvaluator.Explain(b)
+}
+
+func (e *SubqueryEvaluator) Explain(parent Node) {
+	b := parent.Childf("[%s, %s] Subquery", e.expr.Operation, e.expr.Range)
+	e.nextEvaluator.Explain(b)
+}
+
+func (e *PinnedStepEvaluator) Explain(parent Node) {
+	b := parent.Childf("[%s] Pinned", e.at.UTC().Format(time.RFC3339Nano))
+	e.inner.Explain(b)
+}
+
+func (e *FunctionEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s Function", e.expr.Function)
+	e.nextEvaluator.Explain(b)
+}
+
 func (e *VectorAggEvaluator) Explain(parent Node) {
 	b := parent.Childf("[%s, %s] VectorAgg", e.expr.Operation, e.expr.Grouping)
 	e.nextEvaluator.Explain(b)
@@ -63,6 +90,29 @@ func (e *QuantileSketchVectorStepEvaluator) Explain(parent Node) {
 	e.inner.Explain(b)
 }
 
+func (e *TopKSketchStepEvaluator) Explain(parent Node) {
+	b := parent.Childf("TopKSketch k=%d", e.k)
+	e.inner.Explain(b)
+}
+
+func (*TopKSketchMatrixStepEvaluator) Explain(parent Node) {
+	parent.Child("TopKSketchMatrix")
+}
+
+func (e *CountDistinctSketchStepEvaluator) Explain(parent Node) {
+	b := parent.Childf("CountDistinctSketch %s", e.expr.Label)
+	e.inner.Explain(b)
+}
+
+func (*CountDistinctSketchMatrixStepEvaluator) Explain(parent Node) {
+	parent.Child("CountDistinctSketchMatrix")
+}
+
+func (e *CountDistinctEstimateStepEvaluator) Explain(parent Node) {
+	b := parent.Child("CountDistinctEstimate")
+	e.inner.Explain(b)
+}
+
 func (e *mergeOverTimeStepEvaluator) Explain(parent Node) {
 	parent.Child("MergeFirstOverTime")
 }
@@ -25,7 +25,7 @@ func TestExplain(t *testing.T) {
 
 	ctx := user.InjectOrgID(context.Background(), "fake")
 
-	defaultEv := NewDefaultEvaluator(querier, 30*time.Second)
+	defaultEv := NewDefaultEvaluator(querier, 30*time.Second, DefaultCountDistinctPrecision)
 	downEv := &DownstreamEvaluator{Downstreamer: MockDownstreamer{regular}, defaultEvaluator: defaultEv}
 
 	strategy := NewPowerOfTwoStrategy(ConstantShards(4))
@@ -0,0 +1,216 @@
+package logql
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// smallDeltaTolerance is the relative tolerance under which the counts of
+// consecutive buckets are considered equal. Bucket counters summed over time
+// or across series accumulate floating point errors.
+const smallDeltaTolerance = 1e-12
+
+type histogramBucket struct {
+	upperBound float64
+	count      float64
+}
+
+type histogramBuckets []histogramBucket
+
+func (b histogramBuckets) Len() int           { return len(b) }
+func (b histogramBuckets) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b histogramBuckets) Less(i, j int) bool { return b[i].upperBound < b[j].upperBound }
+
+type histogram struct {
+	labels  labels.Labels
+	buckets histogramBuckets
+}
+
+func newHistogramQuantileEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.HistogramQuantileExpr,
+	q Params,
+) (*HistogramQuantileEvaluator, error) {
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistogramQuantileEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		buf:           make([]byte, 0, 1024),
+	}, nil
+}
+
+// HistogramQuantileEvaluator computes the quantile of the histograms formed by
+// the samples sharing the same labels but `le` at every step.
+type HistogramQuantileEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.HistogramQuantileExpr
+	buf           []byte
+}
+
+func (e *HistogramQuantileEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+
+	var (
+		hash       uint64
+		byLabels   = make(map[uint64]*histogram)
+		histograms []*histogram
+	)
+	for _, s := range vec {
+		upperBound, err := strconv.ParseFloat(s.Metric.Get(model.BucketLabel), 64)
+		if err != nil {
+			// Samples without a valid bucket upper bound are ignored.
+			continue
+		}
+		hash, e.buf = s.Metric.HashWithoutLabels(e.buf, model.BucketLabel)
+		h, ok := byLabels[hash]
+		if !ok {
+			h = &histogram{
+				labels: labels.NewBuilder(s.Metric).Del(model.BucketLabel).Labels(),
+			}
+			byLabels[hash] = h
+			histograms = append(histograms, h)
+		}
+		h.buckets = append(h.buckets, histogramBucket{upperBound: upperBound, count: s.F})
+	}
+
+	vec = vec[:0]
+	for _, h := range histograms {
+		vec = append(vec, promql.Sample{
+			Metric: h.labels,
+			T:      ts,
+			F:      bucketQuantile(e.expr.Quantile, h.buckets),
+		})
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *HistogramQuantileEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *HistogramQuantileEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+// bucketQuantile calculates the quantile q of a histogram from its cumulative
+// buckets, assuming a linear distribution within each bucket, like PromQL.
+//
+// The histogram must have a bucket with an upper bound of +Inf, otherwise NaN
+// is returned. Buckets with the same upper bound are merged and the counts are
+// forced to be monotonic, as bucket counters of different series may be
+// scraped, or here aggregated from log lines, at slightly different times.
+// If q < 0, -Inf is returned, if q > 1, +Inf is returned.
+func bucketQuantile(q float64, buckets histogramBuckets) float64 {
+	if math.IsNaN(q) {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	sort.Sort(buckets)
+	if !math.IsInf(buckets[len(buckets)-1].upperBound, +1) {
+		return math.NaN()
+	}
+
+	buckets = coalesceBuckets(buckets)
+	ensureMonotonic(buckets)
+
+	if len(buckets) < 2 {
+		return math.NaN()
+	}
+	observations := buckets[len(buckets)-1].count
+	if observations == 0 {
+		return math.NaN()
+	}
+	rank := q * observations
+	b := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].count >= rank })
+
+	if b == len(buckets)-1 {
+		return buckets[len(buckets)-2].upperBound
+	}
+	if b == 0 && buckets[0].upperBound <= 0 {
+		return buckets[0].upperBound
+	}
+	var (
+		bucketStart float64
+		bucketEnd   = buckets[b].upperBound
+		count       = buckets[b].count
+	)
+	if b > 0 {
+		bucketStart = buckets[b-1].upperBound
+		count -= buckets[b-1].count
+		rank -= buckets[b-1].count
+	}
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count)
+}
+
+// coalesceBuckets merges buckets with the same upper bound, e.g. `le="1"` and
+// `le="1.0"`. The buckets must be sorted.
+func coalesceBuckets(buckets histogramBuckets) histogramBuckets {
+	last := buckets[0]
+	i := 0
+	for _, b := range buckets[1:] {
+		if b.upperBound == last.upperBound {
+			last.count += b.count
+		} else {
+			buckets[i] = last
+			last = b
+			i++
+		}
+	}
+	buckets[i] = last
+	return buckets[:i+1]
+}
+
+// ensureMonotonic raises the count of every bucket to at least the count of
+// the previous bucket, and ignores differences smaller than
+// smallDeltaTolerance between consecutive buckets.
+func ensureMonotonic(buckets histogramBuckets) {
+	prev := buckets[0].count
+	for i := 1; i < len(buckets); i++ {
+		curr := buckets[i].count
+		switch {
+		case curr == prev:
+		case almostEqual(prev, curr, smallDeltaTolerance), curr < prev:
+			buckets[i].count = prev
+		default:
+			prev = curr
+		}
+	}
+}
+
+var minNormal = math.Float64frombits(0x0010000000000000) // The smallest positive normal value of type float64.
+
+// almostEqual returns true if a and b differ by less than their sum
+// multiplied by epsilon.
+func almostEqual(a, b, epsilon float64) bool {
+	if a == b {
+		return true
+	}
+	absSum := math.Abs(a) + math.Abs(b)
+	diff := math.Abs(a - b)
+	if a == 0 || b == 0 || absSum < minNormal {
+		return diff < epsilon*minNormal
+	}
+	return diff/math.Min(absSum, math.MaxFloat64) < epsilon
+}
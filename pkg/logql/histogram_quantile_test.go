@@ -0,0 +1,100 @@
+package logql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_bucketQuantile(t *testing.T) {
+	inf := math.Inf(+1)
+	for _, tc := range []struct {
+		name     string
+		q        float64
+		buckets  histogramBuckets
+		expected float64
+	}{
+		{
+			name:     "interpolated within a bucket",
+			q:        0.5,
+			buckets:  histogramBuckets{{1, 10}, {2, 30}, {inf, 40}},
+			expected: 1.5,
+		},
+		{
+			name:     "unsorted buckets",
+			q:        0.5,
+			buckets:  histogramBuckets{{inf, 40}, {2, 30}, {1, 10}},
+			expected: 1.5,
+		},
+		{
+			name:     "first bucket",
+			q:        0.1,
+			buckets:  histogramBuckets{{1, 10}, {2, 30}, {inf, 40}},
+			expected: 0.4,
+		},
+		{
+			name:     "highest bucket",
+			q:        0.9,
+			buckets:  histogramBuckets{{1, 10}, {2, 30}, {inf, 40}},
+			expected: 2,
+		},
+		{
+			name:     "first bucket with negative upper bound",
+			q:        0.1,
+			buckets:  histogramBuckets{{-1, 10}, {2, 30}, {inf, 40}},
+			expected: -1,
+		},
+		{
+			name:     "non monotonic buckets",
+			q:        0.6,
+			buckets:  histogramBuckets{{1, 20}, {2, 10}, {4, 30}, {inf, 40}},
+			expected: 2.8,
+		},
+		{
+			name:     "buckets with the same upper bound",
+			q:        0.5,
+			buckets:  histogramBuckets{{1, 5}, {1, 5}, {2, 30}, {inf, 40}},
+			expected: 1.5,
+		},
+		{
+			name:     "no +Inf bucket",
+			q:        0.5,
+			buckets:  histogramBuckets{{1, 10}, {2, 30}},
+			expected: math.NaN(),
+		},
+		{
+			name:     "only +Inf bucket",
+			q:        0.5,
+			buckets:  histogramBuckets{{inf, 40}},
+			expected: math.NaN(),
+		},
+		{
+			name:     "no observations",
+			q:        0.5,
+			buckets:  histogramBuckets{{1, 0}, {inf, 0}},
+			expected: math.NaN(),
+		},
+		{
+			name:     "negative quantile",
+			q:        -1,
+			buckets:  histogramBuckets{{1, 10}, {inf, 40}},
+			expected: math.Inf(-1),
+		},
+		{
+			name:     "quantile above 1",
+			q:        2,
+			buckets:  histogramBuckets{{1, 10}, {inf, 40}},
+			expected: math.Inf(+1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := bucketQuantile(tc.q, tc.buckets)
+			if math.IsNaN(tc.expected) {
+				require.True(t, math.IsNaN(actual), "expected NaN, got %v", actual)
+				return
+			}
+			require.InDelta(t, tc.expected, actual, 1e-9)
+		})
+	}
+}
@@ -15,6 +15,7 @@ var (
 // Limits allow the engine to fetch limits for a given users.
 type Limits interface {
 	MaxQuerySeries(context.Context, string) int
+	MaxStatsEntriesPerQuery(context.Context, string) int
 	MaxQueryRange(ctx context.Context, userID string) time.Duration
 	QueryTimeout(context.Context, string) time.Duration
 	BlockedQueries(context.Context, string) []*validation.BlockedQuery
@@ -22,6 +23,7 @@ type Limits interface {
 
 type fakeLimits struct {
 	maxSeries      int
+	maxStats       int
 	timeout        time.Duration
 	blockedQueries []*validation.BlockedQuery
 	rangeLimit     time.Duration
@@ -32,6 +34,10 @@ func (f fakeLimits) MaxQuerySeries(_ context.Context, _ string) int {
 	return f.maxSeries
 }
 
+func (f fakeLimits) MaxStatsEntriesPerQuery(_ context.Context, _ string) int {
+	return f.maxStats
+}
+
 func (f fakeLimits) MaxQueryRange(_ context.Context, _ string) time.Duration {
 	return f.rangeLimit
 }
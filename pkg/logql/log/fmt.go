@@ -2,8 +2,12 @@ package log
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -11,7 +15,9 @@ import (
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/grafana/jsonparser"
 	"github.com/grafana/regexp"
+	jsoniter "github.com/json-iterator/go"
 
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
@@ -70,6 +76,15 @@ var (
 		"unixToTime":       unixToTime,
 		"alignLeft":        alignLeft,
 		"alignRight":       alignRight,
+		"substr":           substring,
+		"trunc":            trunc,
+		"toJson":           toJSON,
+		"fromJsonKey":      fromJSONKey,
+		"hexenc":           hexEncode,
+		"hexdec":           hexDecode,
+		"sha256":           sha256Sum,
+		"fnv":              fnvSum,
+		"splitIndex":       splitIndex,
 	}
 
 	// sprig template functions
@@ -79,8 +94,6 @@ var (
 		"lower",
 		"upper",
 		"title",
-		"trunc",
-		"substr",
 		"contains",
 		"hasPrefix",
 		"hasSuffix",
@@ -111,6 +124,8 @@ var (
 		"floor",
 		"round",
 		"fromJson",
+		"splitList",
+		"join",
 		"date",
 		"toDate",
 		"now",
@@ -179,6 +194,125 @@ func toDateInZone(fmt, zone, str string) time.Time {
 	return t
 }
 
+// toJSON encodes v as JSON. Label sets are encoded with sorted keys without
+// going through reflection. It returns an empty string if v cannot be encoded.
+func toJSON(v interface{}) string {
+	stream := jsoniter.ConfigFastest.BorrowStream(nil)
+	defer jsoniter.ConfigFastest.ReturnStream(stream)
+
+	if m, ok := v.(map[string]string); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		stream.WriteObjectStart()
+		for i, k := range keys {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(k)
+			stream.WriteString(m[k])
+		}
+		stream.WriteObjectEnd()
+	} else {
+		stream.WriteVal(v)
+	}
+	if stream.Error != nil {
+		return ""
+	}
+	return string(stream.Buffer())
+}
+
+// fromJSONKey returns the value found at the given dot separated path of a
+// JSON document without decoding the whole document. Array elements are
+// accessed with the [n] syntax, e.g. `items.[0].name`. Strings are returned
+// unquoted, other values as their raw JSON. It returns an empty string if the
+// path does not exist or the document is invalid.
+func fromJSONKey(path, s string) string {
+	var keys []string
+	if path != "" {
+		keys = strings.Split(path, ".")
+	}
+	value, dataType, _, err := jsonparser.Get(unsafeGetBytes(s), keys...)
+	if err != nil {
+		return ""
+	}
+	switch dataType {
+	case jsonparser.String:
+		str, err := jsonparser.ParseString(value)
+		if err != nil {
+			return ""
+		}
+		return str
+	case jsonparser.Null:
+		return ""
+	default:
+		return string(value)
+	}
+}
+
+func hexEncode(s string) string {
+	return hex.EncodeToString(unsafeGetBytes(s))
+}
+
+func hexDecode(s string) (string, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return unsafeGetString(b), nil
+}
+
+func sha256Sum(s string) string {
+	sum := sha256.Sum256(unsafeGetBytes(s))
+	return hex.EncodeToString(sum[:])
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// fnvSum returns the hex encoded 64-bit FNV-1a hash of s. The hash is computed
+// inline to avoid allocating a hash.Hash64 per call.
+func fnvSum(s string) string {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], h)
+	return hex.EncodeToString(b[:])
+}
+
+// splitIndex returns the i-th element of s split by sep, without allocating
+// the intermediate slice. A negative index counts from the end. It returns an
+// empty string if the index is out of range.
+func splitIndex(sep string, i int, s string) string {
+	if sep == "" {
+		return ""
+	}
+	if i < 0 {
+		i += strings.Count(s, sep) + 1
+		if i < 0 {
+			return ""
+		}
+	}
+	for ; i > 0; i-- {
+		idx := strings.Index(s, sep)
+		if idx < 0 {
+			return ""
+		}
+		s = s[idx+len(sep):]
+	}
+	if idx := strings.Index(s, sep); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
 func init() {
 	sprigFuncMap := sprig.GenericFuncMap()
 	for _, v := range templateFunctions {
@@ -461,6 +461,60 @@ func Test_lineFormatter_Format(t *testing.T) {
 			labels.FromStrings("foo", "aSdtIGEgc3RyaW5nLCBlbmNvZGUgbWUh"),
 			[]byte("1"),
 		},
+		{
+			"toJson",
+			newMustLineFormatter("{{ toJson . }}"),
+			labels.FromStrings("foo", `say "hi"`, "bar", "blop"),
+			1656353124120000000,
+			[]byte(`{"bar":"blop","foo":"say \"hi\""}`),
+			labels.FromStrings("foo", `say "hi"`, "bar", "blop"),
+			[]byte("1"),
+		},
+		{
+			"fromJsonKey",
+			newMustLineFormatter(`{{ fromJsonKey "user.name" .foo }} {{ fromJsonKey "roles.[1]" .foo }} {{ fromJsonKey "user.id" .foo }} {{ fromJsonKey "missing" .foo }}`),
+			labels.FromStrings("foo", `{"user":{"name":"jane","id":42},"roles":["admin","viewer"]}`),
+			1656353124120000000,
+			[]byte("jane viewer 42 "),
+			labels.FromStrings("foo", `{"user":{"name":"jane","id":42},"roles":["admin","viewer"]}`),
+			[]byte("1"),
+		},
+		{
+			"hex",
+			newMustLineFormatter("{{ .foo | hexenc }} {{ .foo | hexenc | hexdec }}"),
+			labels.FromStrings("foo", "loki"),
+			1656353124120000000,
+			[]byte("6c6f6b69 loki"),
+			labels.FromStrings("foo", "loki"),
+			[]byte("1"),
+		},
+		{
+			"hashes",
+			newMustLineFormatter("{{ .foo | sha256 }} {{ .foo | fnv }}"),
+			labels.FromStrings("foo", "loki"),
+			1656353124120000000,
+			[]byte("982945308d3682d16636fd628c314e293499e99c00120acd9b693f5ab16e1648 cdf9d3ad70dfeb64"),
+			labels.FromStrings("foo", "loki"),
+			[]byte("1"),
+		},
+		{
+			"split and join",
+			newMustLineFormatter(`{{ .foo | splitIndex "/" 1 }} {{ .foo | splitIndex "/" -1 }} {{ .foo | splitList "/" | join "-" }}`),
+			labels.FromStrings("foo", "api/v1/push"),
+			1656353124120000000,
+			[]byte("v1 push api-v1-push"),
+			labels.FromStrings("foo", "api/v1/push"),
+			[]byte("1"),
+		},
+		{
+			"default and float arithmetic",
+			newMustLineFormatter(`{{ .missing | default "none" }} {{ addf .foo 1.25 }} {{ divf .foo 2 }}`),
+			labels.FromStrings("foo", "2.5"),
+			1656353124120000000,
+			[]byte("none 3.75 1.25"),
+			labels.FromStrings("foo", "2.5"),
+			[]byte("1"),
+		},
 		{
 			"alignLeft",
 			newMustLineFormatter("{{ alignLeft 4 .foo }}"),
@@ -665,6 +719,12 @@ func Test_labelsFormatter_Format(t *testing.T) {
 				"bar", "i'm a string, encode me!",
 			),
 		},
+		{
+			"pseudonymize",
+			mustNewLabelsFormatter([]LabelFmt{NewTemplateLabelFmt("user", "{{ .user | fnv }}")}),
+			labels.FromStrings("user", "jane"),
+			labels.FromStrings("user", "987395de5a6c6651"),
+		},
 		{
 			"unixToTime days",
 			mustNewLabelsFormatter([]LabelFmt{NewTemplateLabelFmt("foo", `{{ .bar | unixToTime }}`)}),
@@ -836,6 +896,66 @@ func Test_AlignRight(t *testing.T) {
 	}
 }
 
+func Test_splitIndex(t *testing.T) {
+	tests := []struct {
+		sep  string
+		i    int
+		s    string
+		want string
+	}{
+		{",", 0, "a,b,c", "a"},
+		{",", 2, "a,b,c", "c"},
+		{",", 3, "a,b,c", ""},
+		{",", -1, "a,b,c", "c"},
+		{",", -3, "a,b,c", "a"},
+		{",", -4, "a,b,c", ""},
+		{"::", 1, "a::b::c", "b"},
+		{",", 0, "abc", "abc"},
+		{",", 1, "a,,c", ""},
+		{"", 0, "abc", ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s%d%s", tt.sep, tt.i, tt.s), func(t *testing.T) {
+			require.Equal(t, tt.want, splitIndex(tt.sep, tt.i, tt.s))
+		})
+	}
+}
+
+func Test_fromJSONKey(t *testing.T) {
+	doc := `{"a":{"b":"c\nd","n":null,"list":[1,{"x":true}]}}`
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"a.b", "c\nd"},
+		{"a.n", ""},
+		{"a.list.[0]", "1"},
+		{"a.list.[1].x", "true"},
+		{"a.list.[2]", ""},
+		{"a.missing", ""},
+		{"a.list", `[1,{"x":true}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			require.Equal(t, tt.want, fromJSONKey(tt.path, doc))
+		})
+	}
+	require.Equal(t, "", fromJSONKey("a", "not json"))
+}
+
+func Test_toJSON(t *testing.T) {
+	require.Equal(t, `{}`, toJSON(map[string]string{}))
+	require.Equal(t, `{"a":"1","b":"\u0001"}`, toJSON(map[string]string{"b": "\x01", "a": "1"}))
+	require.Equal(t, `[1,2]`, toJSON([]int{1, 2}))
+	require.Equal(t, `"a"`, toJSON("a"))
+	require.Equal(t, "", toJSON(func() {}))
+}
+
+func Test_hexDecode(t *testing.T) {
+	_, err := hexDecode("zz")
+	require.Error(t, err)
+}
+
 func Test_substring(t *testing.T) {
 	tests := []struct {
 		start int
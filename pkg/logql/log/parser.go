@@ -2,8 +2,10 @@ package log
 
 import (
 	"bytes"
+	"encoding/csv"
 	"errors"
 	"fmt"
+	"strconv"
 	"unicode/utf8"
 
 	"github.com/grafana/jsonparser"
@@ -11,6 +13,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logql/log/jsonexpr"
 	"github.com/grafana/loki/v3/pkg/logql/log/logfmt"
 	"github.com/grafana/loki/v3/pkg/logql/log/pattern"
+	"github.com/grafana/loki/v3/pkg/logql/log/xmlexpr"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 
 	"github.com/grafana/regexp"
@@ -32,6 +35,8 @@ var (
 	_ Stage = &JSONParser{}
 	_ Stage = &RegexpParser{}
 	_ Stage = &LogfmtParser{}
+	_ Stage = &XMLParser{}
+	_ Stage = &CSVParser{}
 
 	trueBytes = []byte("true")
 
@@ -757,3 +762,427 @@ func (u *UnpackParser) unpack(entry []byte, lbs *LabelsBuilder) ([]byte, error)
 	}
 	return entry, nil
 }
+
+type XMLParser struct {
+	prefixBuffer []byte // buffer used to build label keys
+	lbs          *LabelsBuilder
+	extracted    map[string]struct{}
+
+	keys        internedStringSet
+	parserHints ParserHint
+}
+
+// NewXMLParser creates a log stage that can parse a xml log line and add its elements and attributes as labels.
+// Nested elements are flattened by joining their names with an underscore, the root element name is omitted.
+// When an element or attribute is repeated, the first value is used.
+func NewXMLParser() *XMLParser {
+	return &XMLParser{
+		prefixBuffer: make([]byte, 0, 1024),
+		extracted:    map[string]struct{}{},
+		keys:         internedStringSet{},
+	}
+}
+
+func (x *XMLParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	doc, err := xmlexpr.Parse(line)
+	if err != nil {
+		addErrLabel(errXML, err, lbs)
+		return line, true
+	}
+
+	// reset the state.
+	x.prefixBuffer = x.prefixBuffer[:0]
+	x.lbs = lbs
+	x.parserHints = parserHints
+	for k := range x.extracted {
+		delete(x.extracted, k)
+	}
+
+	root := doc.Children[0]
+	if len(root.Children) == 0 && len(root.Attrs) == 0 {
+		x.prefixBuffer = appendSanitized(x.prefixBuffer, []byte(root.Name))
+		if err := x.set(root.Text()); err != nil {
+			return line, !errors.Is(err, errLabelDoesNotMatch)
+		}
+		return line, true
+	}
+
+	if err := x.parseNode(root); err != nil {
+		if errors.Is(err, errLabelDoesNotMatch) {
+			// one of the label matchers does not match. The whole line can be thrown away
+			return line, false
+		}
+	}
+	return line, true
+}
+
+// parseNode extracts the attributes and children of a node, the prefix buffer
+// holds the label key of the node.
+func (x *XMLParser) parseNode(n *xmlexpr.Node) error {
+	for _, attr := range n.Attrs {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		if err := x.parseChild(attr.Name.Local, func() error {
+			return x.set(attr.Value)
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range n.Children {
+		if err := x.parseChild(c.Name, func() error {
+			if len(c.Children) == 0 {
+				if err := x.set(c.Text()); err != nil {
+					return err
+				}
+			}
+			return x.parseNode(c)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseChild appends name to the prefix buffer and calls fn if labels starting
+// with the resulting prefix should be extracted.
+func (x *XMLParser) parseChild(name string, fn func() error) error {
+	prefixLen := len(x.prefixBuffer)
+	if prefixLen != 0 {
+		x.prefixBuffer = append(x.prefixBuffer, byte(jsonSpacer))
+	}
+	x.prefixBuffer = appendSanitized(x.prefixBuffer, []byte(name))
+
+	var err error
+	if x.parserHints.ShouldExtractPrefix(unsafeGetString(x.prefixBuffer)) {
+		err = fn()
+	}
+	// rollback the prefix as we exit the current node.
+	x.prefixBuffer = x.prefixBuffer[:prefixLen]
+	return err
+}
+
+func (x *XMLParser) set(value string) error {
+	key, ok := x.keys.Get(x.prefixBuffer, func() (string, bool) {
+		field := string(x.prefixBuffer)
+		if x.lbs.BaseHas(field) {
+			field = field + duplicateSuffix
+		}
+		if !x.parserHints.ShouldExtract(field) {
+			return "", false
+		}
+		return field, true
+	})
+	if !ok {
+		return nil
+	}
+	if _, ok := x.extracted[key]; ok {
+		return nil
+	}
+	x.extracted[key] = struct{}{}
+
+	x.lbs.Set(ParsedLabel, key, value)
+	if !x.parserHints.ShouldContinueParsingLine(key, x.lbs) {
+		return errLabelDoesNotMatch
+	}
+	if x.parserHints.AllRequiredExtracted() {
+		return errFoundAllLabels
+	}
+	return nil
+}
+
+func (x *XMLParser) RequiredLabelNames() []string { return []string{} }
+
+type XMLExpressionParser struct {
+	ids   []string
+	exprs []*xmlexpr.Expr
+	keys  internedStringSet
+}
+
+// NewXMLExpressionParser creates a log stage extracting labels from a xml log line using XPath expressions.
+func NewXMLExpressionParser(expressions []LabelExtractionExpr) (*XMLExpressionParser, error) {
+	var ids []string
+	var exprs []*xmlexpr.Expr
+	for _, exp := range expressions {
+		expr, err := xmlexpr.Compile(exp.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse expression [%s]: %w", exp.Expression, err)
+		}
+
+		if !model.LabelName(exp.Identifier).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
+		}
+
+		ids = append(ids, exp.Identifier)
+		exprs = append(exprs, expr)
+	}
+
+	return &XMLExpressionParser{
+		ids:   ids,
+		exprs: exprs,
+		keys:  internedStringSet{},
+	}, nil
+}
+
+func (x *XMLExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if len(line) == 0 || lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+
+	doc, err := xmlexpr.Parse(line)
+	if err != nil {
+		addErrLabel(errXML, err, lbs)
+		return line, true
+	}
+
+	for i, identifier := range x.ids {
+		key, _ := x.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+			if lbs.BaseHas(identifier) {
+				identifier = identifier + duplicateSuffix
+			}
+			return identifier, true
+		})
+		// Ensure there's a label for every expression, even when nothing matches.
+		value, _ := x.exprs[i].Eval(doc)
+		lbs.Set(ParsedLabel, key, value)
+	}
+
+	return line, true
+}
+
+func (x *XMLExpressionParser) RequiredLabelNames() []string { return []string{} }
+
+type CSVParser struct {
+	reader *bytes.Reader
+	header []string
+	names  []string
+	keys   internedStringSet
+}
+
+// NewCSVParser creates a log stage that can parse a csv log line and add its columns as labels.
+// Without header the columns are named column_1, column_2 and so on. With a header the columns
+// are named after it and the lines matching the header are filtered out.
+func NewCSVParser(header string) (*CSVParser, error) {
+	columns, err := parseCSVHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(columns))
+	for i, column := range columns {
+		name := sanitizeLabelKey(column, true)
+		if name == "" {
+			name = "column_" + strconv.Itoa(i+1)
+		}
+		names = append(names, name)
+	}
+	return &CSVParser{
+		reader: bytes.NewReader(nil),
+		header: columns,
+		names:  names,
+		keys:   internedStringSet{},
+	}, nil
+}
+
+func (c *CSVParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() && c.header == nil {
+		return line, true
+	}
+
+	record, err := readCSVRecord(c.reader, line)
+	if err != nil {
+		addErrLabel(errCSV, err, lbs)
+		return line, true
+	}
+	if isCSVHeader(record, c.header) {
+		return line, false
+	}
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	for i, value := range record {
+		for len(c.names) <= i {
+			c.names = append(c.names, "column_"+strconv.Itoa(len(c.names)+1))
+		}
+		key, ok := c.keys.Get(unsafeGetBytes(c.names[i]), func() (string, bool) {
+			field := c.names[i]
+			if lbs.BaseHas(field) {
+				field = field + duplicateSuffix
+			}
+			if !parserHints.ShouldExtract(field) {
+				return "", false
+			}
+			return field, true
+		})
+		if !ok {
+			continue
+		}
+		lbs.Set(ParsedLabel, key, value)
+		if !parserHints.ShouldContinueParsingLine(key, lbs) {
+			return line, false
+		}
+		if parserHints.AllRequiredExtracted() {
+			break
+		}
+	}
+	return line, true
+}
+
+func (c *CSVParser) RequiredLabelNames() []string { return []string{} }
+
+type CSVExpressionParser struct {
+	ids     []string
+	columns []int
+	header  []string
+	reader  *bytes.Reader
+	keys    internedStringSet
+}
+
+// NewCSVExpressionParser creates a log stage extracting the given columns of a csv log line.
+// The expression of each label is the 1-based index of the column to extract; a label without
+// expression extracts the column matching its position in the list. With a header, columns can
+// also be referred to by name, a label without expression extracts the column of the same name,
+// and the lines matching the header are filtered out.
+func NewCSVExpressionParser(header string, expressions []LabelExtractionExpr) (*CSVExpressionParser, error) {
+	if len(expressions) == 0 {
+		return nil, fmt.Errorf("no csv expression provided")
+	}
+	headerColumns, err := parseCSVHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	var columns []int
+	for i, exp := range expressions {
+		column := i + 1
+		if exp.Expression != exp.Identifier || headerColumns != nil {
+			n, err := csvColumn(exp.Expression, headerColumns)
+			if err != nil {
+				return nil, err
+			}
+			column = n
+		}
+
+		if !model.LabelName(exp.Identifier).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
+		}
+
+		ids = append(ids, exp.Identifier)
+		columns = append(columns, column-1)
+	}
+
+	return &CSVExpressionParser{
+		ids:     ids,
+		columns: columns,
+		header:  headerColumns,
+		reader:  bytes.NewReader(nil),
+		keys:    internedStringSet{},
+	}, nil
+}
+
+// csvColumn returns the 1-based index of the column referred to by an expression, either
+// its index or its name in the header.
+func csvColumn(expression string, header []string) (int, error) {
+	if n, err := strconv.Atoi(expression); err == nil {
+		if n < 1 {
+			return 0, fmt.Errorf("cannot parse expression [%s]: column must be a positive integer", expression)
+		}
+		return n, nil
+	}
+	if header == nil {
+		return 0, fmt.Errorf("cannot parse expression [%s]: column must be a positive integer", expression)
+	}
+	for i, name := range header {
+		if name == expression {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("cannot parse expression [%s]: column not found in header", expression)
+}
+
+func (c *CSVExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if len(line) == 0 || (lbs.ParserLabelHints().NoLabels() && c.header == nil) {
+		return line, true
+	}
+
+	record, err := readCSVRecord(c.reader, line)
+	if err != nil {
+		addErrLabel(errCSV, err, lbs)
+		return line, true
+	}
+	if isCSVHeader(record, c.header) {
+		return line, false
+	}
+	if lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+
+	for i, identifier := range c.ids {
+		key, _ := c.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+			if lbs.BaseHas(identifier) {
+				identifier = identifier + duplicateSuffix
+			}
+			return identifier, true
+		})
+		// Ensure there's a label for every expression, even when the column is missing.
+		var value string
+		if c.columns[i] < len(record) {
+			value = record[c.columns[i]]
+		}
+		lbs.Set(ParsedLabel, key, value)
+	}
+
+	return line, true
+}
+
+func (c *CSVExpressionParser) RequiredLabelNames() []string { return []string{} }
+
+// parseCSVHeader returns the column names of a csv header, or nil without header.
+func parseCSVHeader(header string) ([]string, error) {
+	if header == "" {
+		return nil, nil
+	}
+	columns, err := readCSVRecord(bytes.NewReader(nil), []byte(header))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse header [%s]: %w", header, err)
+	}
+	return columns, nil
+}
+
+// isCSVHeader returns whether a record is the header line.
+func isCSVHeader(record, header []string) bool {
+	if header == nil || len(record) != len(header) {
+		return false
+	}
+	for i := range record {
+		if record[i] != header[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readCSVRecord reads a single record from a csv line, records can have any
+// number of fields.
+func readCSVRecord(reader *bytes.Reader, line []byte) ([]string, error) {
+	reader.Reset(line)
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	record, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range record {
+		if !utf8.ValidString(v) {
+			record[i] = ""
+		}
+	}
+	return record, nil
+}
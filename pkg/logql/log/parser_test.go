@@ -1219,6 +1219,338 @@ func TestXExpressionParserFailures(t *testing.T) {
 	}
 }
 
+func Test_xmlParser_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  []byte
+		lbs   labels.Labels
+		want  labels.Labels
+		hints ParserHint
+	}{
+		{
+			"nested elements and attributes",
+			[]byte(`<event id="42"><level>error</level><user name="bob"><ip>10.0.0.1</ip></user></event>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("id", "42",
+				"level", "error",
+				"user_name", "bob",
+				"user_ip", "10.0.0.1",
+			),
+			NoParserHints(),
+		},
+		{
+			"repeated elements keep the first value",
+			[]byte(`<event><tag>a</tag><tag>b</tag></event>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("tag", "a"),
+			NoParserHints(),
+		},
+		{
+			"namespaces are ignored",
+			[]byte(`<e:event xmlns:e="urn:event" xmlns="urn:default"><e:level>info</e:level></e:event>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("level", "info"),
+			NoParserHints(),
+		},
+		{
+			"leaf root element",
+			[]byte(`<msg>hello world</msg>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("msg", "hello world"),
+			NoParserHints(),
+		},
+		{
+			"sanitized names",
+			[]byte(`<event><http-status>500</http-status><trace.id>abc</trace.id></event>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("http_status", "500", "trace_id", "abc"),
+			NoParserHints(),
+		},
+		{
+			"duplicate extraction",
+			[]byte(`<event><app>foo</app><level>info</level></event>`),
+			labels.FromStrings("app", "bar"),
+			labels.FromStrings("app", "bar",
+				"app_extracted", "foo",
+				"level", "info",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]byte(`<event><app>foo</app><level>info</level><user><ip>10.0.0.1</ip></user></event>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("user_ip", "10.0.0.1"),
+			NewParserHint([]string{"user_ip"}, nil, false, true, "", nil),
+		},
+		{
+			"invalid xml",
+			[]byte(`<event><level>info</event>`),
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, "XML syntax error on line 1: element <level> closed by </event>",
+			),
+			NoParserHints(),
+		},
+	}
+	for _, tt := range tests {
+		x := NewXMLParser()
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParser(t *testing.T) {
+	testLine := []byte(`<Event><System><EventID>4625</EventID><Provider Name="Security"/></System><EventData><Data Name="TargetUserName">bob</Data><Data Name="IpAddress">10.0.0.1</Data></EventData></Event>`)
+
+	tests := []struct {
+		name        string
+		line        []byte
+		expressions []LabelExtractionExpr
+		lbs         labels.Labels
+		want        labels.Labels
+	}{
+		{
+			"multiple expressions",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("id", "/Event/System/EventID"),
+				NewLabelExtractionExpr("provider", "//Provider/@Name"),
+				NewLabelExtractionExpr("ip", "//Data[@Name='IpAddress']"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("id", "4625",
+				"provider", "Security",
+				"ip", "10.0.0.1",
+			),
+		},
+		{
+			"missing value",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "//Data[@Name='SubjectUserName']"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("user", ""),
+		},
+		{
+			"duplicate extraction",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("id", "//EventID"),
+			},
+			labels.FromStrings("id", "1"),
+			labels.FromStrings("id", "1", "id_extracted", "4625"),
+		},
+		{
+			"invalid xml",
+			[]byte(`{"id":1}`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("id", "//EventID"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings(logqlmodel.ErrorLabel, errXML,
+				logqlmodel.ErrorDetailsLabel, "no root element",
+			),
+		},
+	}
+	for _, tt := range tests {
+		x, err := NewXMLExpressionParser(tt.expressions)
+		require.NoError(t, err)
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, NoParserHints(), false, false).ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+
+	_, err := NewXMLExpressionParser([]LabelExtractionExpr{NewLabelExtractionExpr("id", "/Event[")})
+	require.Error(t, err)
+}
+
+func Test_csvParser_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  []byte
+		lbs   labels.Labels
+		want  labels.Labels
+		hints ParserHint
+	}{
+		{
+			"columns",
+			[]byte(`2024-01-01T00:00:00Z,error,"disk full, retrying"`),
+			labels.EmptyLabels(),
+			labels.FromStrings("column_1", "2024-01-01T00:00:00Z",
+				"column_2", "error",
+				"column_3", "disk full, retrying",
+			),
+			NoParserHints(),
+		},
+		{
+			"escaped quotes and empty columns",
+			[]byte(`a,,"say ""hi"""`),
+			labels.EmptyLabels(),
+			labels.FromStrings("column_1", "a",
+				"column_2", "",
+				"column_3", `say "hi"`,
+			),
+			NoParserHints(),
+		},
+		{
+			"duplicate extraction",
+			[]byte(`a,b`),
+			labels.FromStrings("column_1", "x"),
+			labels.FromStrings("column_1", "x",
+				"column_1_extracted", "a",
+				"column_2", "b",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]byte(`a,b,c`),
+			labels.EmptyLabels(),
+			labels.FromStrings("column_2", "b"),
+			NewParserHint([]string{"column_2"}, nil, false, true, "", nil),
+		},
+		{
+			"invalid csv",
+			[]byte(`a,"b`),
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				logqlmodel.ErrorLabel, errCSV,
+				logqlmodel.ErrorDetailsLabel, `parse error on line 1, column 5: extraneous or missing " in quoted-field`,
+			),
+			NoParserHints(),
+		},
+	}
+	for _, tt := range tests {
+		c, err := NewCSVParser("")
+		require.NoError(t, err)
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = c.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestCSVExpressionParser(t *testing.T) {
+	testLine := []byte(`2024-01-01T00:00:00Z,error,api,"disk full, retrying"`)
+
+	tests := []struct {
+		name        string
+		expressions []LabelExtractionExpr
+		lbs         labels.Labels
+		want        labels.Labels
+	}{
+		{
+			"positional names",
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("ts", "ts"),
+				NewLabelExtractionExpr("level", "level"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("ts", "2024-01-01T00:00:00Z", "level", "error"),
+		},
+		{
+			"column index",
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("level", "level"),
+				NewLabelExtractionExpr("msg", "4"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("level", "2024-01-01T00:00:00Z", "msg", "disk full, retrying"),
+		},
+		{
+			"missing column",
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("extra", "5"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("extra", ""),
+		},
+		{
+			"duplicate extraction",
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("service", "3"),
+			},
+			labels.FromStrings("service", "web"),
+			labels.FromStrings("service", "web", "service_extracted", "api"),
+		},
+	}
+	for _, tt := range tests {
+		c, err := NewCSVExpressionParser("", tt.expressions)
+		require.NoError(t, err)
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, NoParserHints(), false, false).ForLabels(tt.lbs, tt.lbs.Hash())
+			b.Reset()
+			_, _ = c.Process(0, testLine, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+
+	for _, exp := range []LabelExtractionExpr{
+		NewLabelExtractionExpr("msg", "0"),
+		NewLabelExtractionExpr("msg", "message"),
+		NewLabelExtractionExpr("1msg", "1"),
+	} {
+		_, err := NewCSVExpressionParser("", []LabelExtractionExpr{exp})
+		require.Error(t, err, exp)
+	}
+}
+
+func TestCSVParser_Header(t *testing.T) {
+	header := `method,"request path",status`
+
+	p, err := NewCSVParser(header)
+	require.NoError(t, err)
+	b := NewBaseLabelsBuilder().ForLabels(labels.EmptyLabels(), 0)
+
+	// The header line is filtered out.
+	b.Reset()
+	_, ok := p.Process(0, []byte(`method,request path,status`), b)
+	require.False(t, ok)
+
+	// Columns are named after the header, extra columns by their position.
+	b.Reset()
+	_, ok = p.Process(0, []byte(`GET,/api,200,12ms`), b)
+	require.True(t, ok)
+	require.Equal(t, labels.FromStrings("method", "GET",
+		"request_path", "/api",
+		"status", "200",
+		"column_4", "12ms",
+	), b.LabelsResult().Labels())
+
+	e, err := NewCSVExpressionParser(header, []LabelExtractionExpr{
+		NewLabelExtractionExpr("status", "status"),
+		NewLabelExtractionExpr("path", "request path"),
+		NewLabelExtractionExpr("first", "1"),
+	})
+	require.NoError(t, err)
+
+	b.Reset()
+	_, ok = e.Process(0, []byte(header), b)
+	require.False(t, ok)
+
+	b.Reset()
+	_, ok = e.Process(0, []byte(`GET,/api,200`), b)
+	require.True(t, ok)
+	require.Equal(t, labels.FromStrings("status", "200", "path", "/api", "first", "GET"), b.LabelsResult().Labels())
+
+	_, err = NewCSVParser(`"method`)
+	require.Error(t, err)
+	_, err = NewCSVExpressionParser(header, []LabelExtractionExpr{NewLabelExtractionExpr("duration", "duration")})
+	require.Error(t, err)
+}
+
 func Test_unpackParser_Parse(t *testing.T) {
 	tests := []struct {
 		name string
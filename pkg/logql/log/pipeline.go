@@ -2,11 +2,18 @@ package log
 
 import (
 	"context"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"unsafe"
 
+	"github.com/DataDog/sketches-go/ddsketch"
 	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
 
 // NoopStage is a stage that doesn't process a log line.
@@ -381,3 +388,265 @@ func unsafeGetBytes(s string) []byte {
 func unsafeGetString(buf []byte) string {
 	return *((*string)(unsafe.Pointer(&buf)))
 }
+
+// Operations of a stats stage aggregation.
+const (
+	StatsCount      = "count"
+	StatsSum        = "sum"
+	StatsAvg        = "avg"
+	StatsMin        = "min"
+	StatsMax        = "max"
+	StatsDistinct   = "distinct"
+	StatsPercentile = "percentile"
+)
+
+// percentileRelativeAccuracy is the relative accuracy of the sketches used by
+// percentile aggregations.
+const percentileRelativeAccuracy = 0.01
+
+// StatsAggregation is a single aggregation of a stats stage, e.g. `avg(duration)`.
+type StatsAggregation struct {
+	Op string
+	// Field is the label aggregated. It is empty for `count()`.
+	Field string
+	// Param is the quantile of a percentile aggregation.
+	Param float64
+}
+
+// String returns the aggregation as written in the stats stage, which is also
+// the name of its column.
+func (a StatsAggregation) String() string {
+	if a.Op == StatsPercentile {
+		return a.Op + "(" + strconv.FormatFloat(a.Param, 'f', -1, 64) + ", " + a.Field + ")"
+	}
+	return a.Op + "(" + a.Field + ")"
+}
+
+// StatsAggregator aggregates log lines into a table with one row per distinct
+// combination of the values of the grouping labels.
+// Values that can't be parsed as floats are ignored by numeric aggregations.
+type StatsAggregator struct {
+	aggregations []StatsAggregation
+	groups       []string
+
+	rows map[string]*statsRow
+	buf  []byte
+}
+
+// NewStatsAggregator creates a StatsAggregator computing the aggregations of
+// log lines grouped by the values of the groups labels.
+func NewStatsAggregator(aggregations []StatsAggregation, groups []string) *StatsAggregator {
+	return &StatsAggregator{
+		aggregations: aggregations,
+		groups:       groups,
+		rows:         map[string]*statsRow{},
+	}
+}
+
+// Process adds a log line with the given labels to its row.
+func (s *StatsAggregator) Process(lbs labels.Labels) {
+	s.buf = s.buf[:0]
+	for _, g := range s.groups {
+		s.buf = append(s.buf, lbs.Get(g)...)
+		s.buf = append(s.buf, '\xff')
+	}
+
+	row, ok := s.rows[string(s.buf)]
+	if !ok {
+		row = s.newRow(lbs)
+		s.rows[string(s.buf)] = row
+	}
+
+	for i, a := range s.aggregations {
+		if a.Field == "" {
+			row.values[i].add("")
+			continue
+		}
+		if v := lbs.Get(a.Field); v != "" {
+			row.values[i].add(v)
+		}
+	}
+}
+
+func (s *StatsAggregator) newRow(lbs labels.Labels) *statsRow {
+	row := &statsRow{
+		groups: make([]string, len(s.groups)),
+		values: make([]statsValue, len(s.aggregations)),
+	}
+	for i, g := range s.groups {
+		row.groups[i] = lbs.Get(g)
+	}
+	for i, a := range s.aggregations {
+		row.values[i] = newStatsValue(a)
+	}
+	return row
+}
+
+// Len returns the number of rows.
+func (s *StatsAggregator) Len() int {
+	return len(s.rows)
+}
+
+// Table returns the rows sorted by the values of the grouping labels, truncated
+// to limit rows if limit is greater than zero.
+func (s *StatsAggregator) Table(limit int) logqlmodel.Table {
+	rows := make([]*statsRow, 0, len(s.rows))
+	for _, row := range s.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		for k := range rows[i].groups {
+			if rows[i].groups[k] != rows[j].groups[k] {
+				return rows[i].groups[k] < rows[j].groups[k]
+			}
+		}
+		return false
+	})
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	table := logqlmodel.Table{
+		Columns: make([]string, 0, len(s.groups)+len(s.aggregations)),
+		Rows:    make([][]string, 0, len(rows)),
+	}
+	table.Columns = append(table.Columns, s.groups...)
+	for _, a := range s.aggregations {
+		table.Columns = append(table.Columns, a.String())
+	}
+	for _, row := range rows {
+		values := make([]string, 0, len(table.Columns))
+		values = append(values, row.groups...)
+		for _, v := range row.values {
+			values = append(values, strconv.FormatFloat(v.value(), 'f', -1, 64))
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table
+}
+
+type statsRow struct {
+	groups []string
+	values []statsValue
+}
+
+// statsValue is the state of an aggregation for a single row.
+type statsValue interface {
+	add(v string)
+	value() float64
+}
+
+func newStatsValue(a StatsAggregation) statsValue {
+	switch a.Op {
+	case StatsCount:
+		return &countValue{}
+	case StatsSum:
+		return &sumValue{}
+	case StatsAvg:
+		return &avgValue{}
+	case StatsMin:
+		return &minMaxValue{less: func(a, b float64) bool { return a < b }}
+	case StatsMax:
+		return &minMaxValue{less: func(a, b float64) bool { return a > b }}
+	case StatsDistinct:
+		return &distinctValue{values: map[string]struct{}{}}
+	case StatsPercentile:
+		sketch, _ := ddsketch.NewDefaultDDSketch(percentileRelativeAccuracy)
+		return &percentileValue{quantile: a.Param, sketch: sketch}
+	default:
+		// The parser only accepts the operations above.
+		panic("unknown stats operation " + a.Op)
+	}
+}
+
+type countValue struct {
+	n float64
+}
+
+func (c *countValue) add(string)     { c.n++ }
+func (c *countValue) value() float64 { return c.n }
+
+type sumValue struct {
+	sum float64
+}
+
+func (s *sumValue) add(v string) {
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		s.sum += f
+	}
+}
+
+func (s *sumValue) value() float64 { return s.sum }
+
+type avgValue struct {
+	sum, n float64
+}
+
+func (a *avgValue) add(v string) {
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		a.sum += f
+		a.n++
+	}
+}
+
+func (a *avgValue) value() float64 {
+	if a.n == 0 {
+		return math.NaN()
+	}
+	return a.sum / a.n
+}
+
+type minMaxValue struct {
+	less func(a, b float64) bool
+	v    float64
+	set  bool
+}
+
+func (m *minMaxValue) add(v string) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return
+	}
+	if !m.set || m.less(f, m.v) {
+		m.v, m.set = f, true
+	}
+}
+
+func (m *minMaxValue) value() float64 {
+	if !m.set {
+		return math.NaN()
+	}
+	return m.v
+}
+
+type distinctValue struct {
+	values map[string]struct{}
+}
+
+func (d *distinctValue) add(v string) {
+	if _, ok := d.values[v]; !ok {
+		d.values[strings.Clone(v)] = struct{}{}
+	}
+}
+
+func (d *distinctValue) value() float64 { return float64(len(d.values)) }
+
+type percentileValue struct {
+	quantile float64
+	sketch   *ddsketch.DDSketch
+}
+
+func (p *percentileValue) add(v string) {
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		_ = p.sketch.Add(f)
+	}
+}
+
+func (p *percentileValue) value() float64 {
+	v, err := p.sketch.GetValueAtQuantile(p.quantile)
+	if err != nil {
+		// the sketch is empty
+		return math.NaN()
+	}
+	return v
+}
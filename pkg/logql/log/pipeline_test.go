@@ -1,6 +1,7 @@
 package log
 
 import (
+	"strconv"
 	"testing"
 	"time"
 
@@ -734,3 +735,87 @@ func BenchmarkLogfmtExpressionParser(b *testing.B) {
 
 	logfmtBenchmark(b, parser)
 }
+
+func TestStatsAggregator(t *testing.T) {
+	lines := []labels.Labels{
+		labels.FromStrings("status", "200", "route", "/api", "duration", "0.5", "user", "alice"),
+		labels.FromStrings("status", "200", "route", "/api", "duration", "1.5", "user", "bob"),
+		labels.FromStrings("status", "200", "route", "/api", "duration", "foo", "user", "alice"),
+		labels.FromStrings("status", "500", "route", "/api", "duration", "3"),
+		labels.FromStrings("status", "200", "route", "/health"),
+	}
+
+	for _, tc := range []struct {
+		name         string
+		aggregations []StatsAggregation
+		groups       []string
+		limit        int
+		expected     logqlmodel.Table
+	}{
+		{
+			name:         "count without grouping",
+			aggregations: []StatsAggregation{{Op: StatsCount}},
+			expected: logqlmodel.Table{
+				Columns: []string{"count()"},
+				Rows:    [][]string{{"5"}},
+			},
+		},
+		{
+			name: "all aggregations by route",
+			aggregations: []StatsAggregation{
+				{Op: StatsCount, Field: "duration"},
+				{Op: StatsSum, Field: "duration"},
+				{Op: StatsAvg, Field: "duration"},
+				{Op: StatsMin, Field: "duration"},
+				{Op: StatsMax, Field: "duration"},
+				{Op: StatsDistinct, Field: "user"},
+			},
+			groups: []string{"route"},
+			expected: logqlmodel.Table{
+				Columns: []string{"route", "count(duration)", "sum(duration)", "avg(duration)", "min(duration)", "max(duration)", "distinct(user)"},
+				Rows: [][]string{
+					{"/api", "4", "5", "1.6666666666666667", "0.5", "3", "2"},
+					{"/health", "0", "0", "NaN", "NaN", "NaN", "0"},
+				},
+			},
+		},
+		{
+			name:         "sorted by groups and limited",
+			aggregations: []StatsAggregation{{Op: StatsCount}},
+			groups:       []string{"status", "route"},
+			limit:        2,
+			expected: logqlmodel.Table{
+				Columns: []string{"status", "route", "count()"},
+				Rows: [][]string{
+					{"200", "/api", "3"},
+					{"200", "/health", "1"},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewStatsAggregator(tc.aggregations, tc.groups)
+			for _, lbs := range lines {
+				s.Process(lbs)
+			}
+			require.Equal(t, tc.expected, s.Table(tc.limit))
+		})
+	}
+}
+
+func TestStatsAggregator_Percentile(t *testing.T) {
+	s := NewStatsAggregator([]StatsAggregation{{Op: StatsPercentile, Field: "latency", Param: 0.9}}, nil)
+	for i := 1; i <= 100; i++ {
+		s.Process(labels.FromStrings("latency", strconv.Itoa(i)))
+	}
+	table := s.Table(0)
+	require.Equal(t, []string{"percentile(0.9, latency)"}, table.Columns)
+	require.Len(t, table.Rows, 1)
+	p90, err := strconv.ParseFloat(table.Rows[0][0], 64)
+	require.NoError(t, err)
+	require.InEpsilon(t, 90, p90, percentileRelativeAccuracy)
+
+	empty := NewStatsAggregator([]StatsAggregation{{Op: StatsPercentile, Field: "latency", Param: 0.9}}, nil)
+	empty.Process(labels.FromStrings("app", "foo"))
+	require.Equal(t, [][]string{{"NaN"}}, empty.Table(0).Rows)
+}
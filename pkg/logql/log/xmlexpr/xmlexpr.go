@@ -0,0 +1,369 @@
+// Package xmlexpr implements the subset of XPath used to extract values from
+// XML log lines.
+//
+// Supported expressions are location paths made of:
+//   - absolute (`/event/level`) and descendant (`//level`) steps,
+//   - the `*` wildcard,
+//   - positional (`[2]`), attribute (`[@name]`, `[@name='user']`) and child
+//     text (`[key='user']`) predicates,
+//   - a final attribute (`/@id`) or `text()` step.
+//
+// Names are matched against the local name of elements and attributes,
+// namespaces are ignored.
+package xmlexpr
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Node is an element of a parsed XML document.
+type Node struct {
+	Name     string
+	Attrs    []xml.Attr
+	Children []*Node
+
+	// text is the character data directly within the node, childAt holds
+	// the offset in text at which every child starts.
+	text    []byte
+	childAt []int
+}
+
+// Attr returns the value of the attribute with the given local name.
+func (n *Node) Attr(name string) (string, bool) {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the character data of the node and all of its descendants,
+// with leading and trailing white space removed.
+func (n *Node) Text() string {
+	if len(n.Children) == 0 {
+		return string(bytes.TrimSpace(n.text))
+	}
+	var buf bytes.Buffer
+	n.appendText(&buf)
+	return string(bytes.TrimSpace(buf.Bytes()))
+}
+
+func (n *Node) appendText(buf *bytes.Buffer) {
+	prev := 0
+	for i, c := range n.Children {
+		buf.Write(n.text[prev:n.childAt[i]])
+		prev = n.childAt[i]
+		c.appendText(buf)
+	}
+	buf.Write(n.text[prev:])
+}
+
+// Parse parses an XML document and returns a document node whose only child
+// is the root element.
+func Parse(data []byte) (*Node, error) {
+	doc := &Node{}
+	stack := []*Node{doc}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &Node{Name: t.Name.Local, Attrs: t.Attr}
+			parent.Children = append(parent.Children, n)
+			parent.childAt = append(parent.childAt, len(parent.text))
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.text = append(parent.text, t...)
+		}
+	}
+
+	if len(doc.Children) == 0 {
+		return nil, errors.New("no root element")
+	}
+	return doc, nil
+}
+
+// Expr is a compiled expression.
+type Expr struct {
+	steps []step
+	// attr or text select the attribute or the character data of the
+	// matched element rather than its text content.
+	attr string
+	text bool
+}
+
+type step struct {
+	descendant bool
+	name       string
+	predicates []predicate
+}
+
+type predicate struct {
+	// position is the 1-based position among the matched siblings, zero if
+	// the predicate is not positional.
+	position int
+	// attr or child is the name of the attribute or child element to test.
+	attr, child string
+	// value is compared with the attribute or child text if hasValue is true.
+	value    string
+	hasValue bool
+}
+
+// Compile parses an expression.
+func Compile(expr string) (*Expr, error) {
+	p := &exprParser{input: strings.TrimSpace(expr)}
+	e, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid xml expression %q: %w", expr, err)
+	}
+	return e, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be parsed.
+func MustCompile(expr string) *Expr {
+	e, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Eval returns the value selected by the expression in the document returned
+// by Parse. The value of the first matching node is returned.
+func (e *Expr) Eval(doc *Node) (string, bool) {
+	nodes := []*Node{doc}
+	for _, s := range e.steps {
+		nodes = s.eval(nodes)
+		if len(nodes) == 0 {
+			return "", false
+		}
+	}
+
+	for _, n := range nodes {
+		switch {
+		case e.attr != "":
+			if v, ok := n.Attr(e.attr); ok {
+				return v, true
+			}
+		case e.text:
+			return string(bytes.TrimSpace(n.text)), true
+		default:
+			return n.Text(), true
+		}
+	}
+	return "", false
+}
+
+func (s step) eval(context []*Node) []*Node {
+	if s.descendant {
+		var all []*Node
+		for _, n := range context {
+			all = appendDescendantsOrSelf(all, n)
+		}
+		context = all
+	}
+
+	var res []*Node
+	for _, n := range context {
+		position := 0
+		for _, c := range n.Children {
+			if s.name != "*" && c.Name != s.name {
+				continue
+			}
+			position++
+			if s.matches(c, position) {
+				res = append(res, c)
+			}
+		}
+	}
+	return res
+}
+
+func (s step) matches(n *Node, position int) bool {
+	for _, p := range s.predicates {
+		switch {
+		case p.position > 0:
+			if p.position != position {
+				return false
+			}
+		case p.attr != "":
+			v, ok := n.Attr(p.attr)
+			if !ok || (p.hasValue && v != p.value) {
+				return false
+			}
+		case p.child != "":
+			if !hasChild(n, p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func hasChild(n *Node, p predicate) bool {
+	for _, c := range n.Children {
+		if c.Name == p.child && (!p.hasValue || c.Text() == p.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendDescendantsOrSelf(to []*Node, n *Node) []*Node {
+	to = append(to, n)
+	for _, c := range n.Children {
+		to = appendDescendantsOrSelf(to, c)
+	}
+	return to
+}
+
+type exprParser struct {
+	input string
+	pos   int
+}
+
+func (p *exprParser) parse() (*Expr, error) {
+	if p.input == "" {
+		return nil, errors.New("empty expression")
+	}
+
+	e := &Expr{}
+	for p.pos < len(p.input) {
+		descendant := false
+		switch {
+		case strings.HasPrefix(p.input[p.pos:], "//"):
+			descendant = true
+			p.pos += 2
+		case p.input[p.pos] == '/':
+			p.pos++
+		case len(e.steps) > 0:
+			return nil, fmt.Errorf("unexpected character %q at position %d", p.input[p.pos], p.pos)
+		}
+
+		if p.pos == len(p.input) {
+			return nil, errors.New("expression must not end with a separator")
+		}
+
+		if p.input[p.pos] == '@' {
+			p.pos++
+			e.attr = localName(p.name())
+			if e.attr == "" {
+				return nil, fmt.Errorf("missing attribute name at position %d", p.pos)
+			}
+			if descendant {
+				e.steps = append(e.steps, step{descendant: true, name: "*"})
+			}
+			break
+		}
+		if strings.HasPrefix(p.input[p.pos:], "text()") {
+			p.pos += len("text()")
+			if descendant {
+				e.steps = append(e.steps, step{descendant: true, name: "*"})
+			}
+			e.text = true
+			break
+		}
+
+		s := step{descendant: descendant, name: localName(p.name())}
+		if s.name == "" {
+			return nil, fmt.Errorf("missing element name at position %d", p.pos)
+		}
+		for p.pos < len(p.input) && p.input[p.pos] == '[' {
+			pred, err := p.predicate()
+			if err != nil {
+				return nil, err
+			}
+			s.predicates = append(s.predicates, pred)
+		}
+		e.steps = append(e.steps, s)
+	}
+
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("unexpected %q after attribute or text() step", p.input[p.pos:])
+	}
+	if len(e.steps) == 0 {
+		return nil, errors.New("expression must select an element")
+	}
+	return e, nil
+}
+
+func (p *exprParser) predicate() (predicate, error) {
+	end := strings.IndexByte(p.input[p.pos:], ']')
+	if end < 0 {
+		return predicate{}, fmt.Errorf("unclosed predicate at position %d", p.pos)
+	}
+	body := strings.TrimSpace(p.input[p.pos+1 : p.pos+end])
+	p.pos += end + 1
+
+	if n, err := strconv.Atoi(body); err == nil {
+		if n < 1 {
+			return predicate{}, fmt.Errorf("invalid position %d", n)
+		}
+		return predicate{position: n}, nil
+	}
+
+	var pred predicate
+	name := body
+	if i := strings.IndexByte(body, '='); i >= 0 {
+		name = strings.TrimSpace(body[:i])
+		v, err := unquote(strings.TrimSpace(body[i+1:]))
+		if err != nil {
+			return predicate{}, err
+		}
+		pred.value, pred.hasValue = v, true
+	}
+	if strings.HasPrefix(name, "@") {
+		pred.attr = localName(name[1:])
+	} else {
+		pred.child = localName(name)
+	}
+	if pred.attr == "" && pred.child == "" {
+		return predicate{}, fmt.Errorf("invalid predicate [%s]", body)
+	}
+	return pred, nil
+}
+
+func (p *exprParser) name() string {
+	start := p.pos
+	for p.pos < len(p.input) && isNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func isNameChar(c byte) bool {
+	return c == '*' || c == '_' || c == '-' || c == '.' || c == ':' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func localName(name string) string {
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("predicate value %s must be quoted", s)
+	}
+	return s[1 : len(s)-1], nil
+}
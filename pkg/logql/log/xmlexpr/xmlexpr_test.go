@@ -0,0 +1,91 @@
+package xmlexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const windowsEvent = `<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Microsoft-Windows-Security-Auditing" Guid="{54849625}"/>
+    <EventID>4625</EventID>
+    <Level>0</Level>
+  </System>
+  <EventData>
+    <Data Name="TargetUserName">bob</Data>
+    <Data Name="IpAddress">10.0.0.1</Data>
+  </EventData>
+</Event>`
+
+func TestEval(t *testing.T) {
+	doc, err := Parse([]byte(windowsEvent))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		expr     string
+		expected string
+		ok       bool
+	}{
+		{"/Event/System/EventID", "4625", true},
+		{"Event/System/Level", "0", true},
+		{"//EventID", "4625", true},
+		{"/Event//Level", "0", true},
+		{"/Event/System/Provider/@Name", "Microsoft-Windows-Security-Auditing", true},
+		{"//Provider/@Guid", "{54849625}", true},
+		{"//@Guid", "{54849625}", true},
+		{"//Data[@Name='IpAddress']", "10.0.0.1", true},
+		{`//Data[@Name="TargetUserName"]/text()`, "bob", true},
+		{"/Event/EventData/Data[2]", "10.0.0.1", true},
+		{"/Event/EventData/*[1]", "bob", true},
+		{"/Event/System[EventID='4625']/Level", "0", true},
+		{"/Event/System[Level]/EventID", "4625", true},
+		{"/ev:Event/ev:System/ev:EventID", "4625", true},
+		{"/Event/EventData", "bob\n    10.0.0.1", true},
+		{"/Event/System[EventID='1']/Level", "", false},
+		{"//Data[@Name='Missing']", "", false},
+		{"/Event/EventData/Data[3]", "", false},
+		{"/Event/System/Provider/@Missing", "", false},
+		{"/System", "", false},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			e, err := Compile(tc.expr)
+			require.NoError(t, err)
+			v, ok := e.Eval(doc)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, v)
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"/",
+		"/Event/",
+		"/Event/@",
+		"/Event/@Name/Level",
+		"/Event/text()/Level",
+		"/Event[",
+		"/Event[0]",
+		"/Event[@Name=bob]",
+		"@Name",
+		"/Event Level",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Compile(expr)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"not xml",
+		"<event><level>info</event>",
+	} {
+		_, err := Parse([]byte(data))
+		require.Error(t, err, data)
+	}
+}
@@ -851,3 +851,70 @@ func (a *OneOverTime) agg(_ promql.FPoint) {
 func (a *OneOverTime) at() float64 {
 	return 1.0
 }
+
+// pinnedParams are the parameters of a range or subquery pinned by an @
+// modifier, it is evaluated as an instant query at the pinned time.
+type pinnedParams struct {
+	Params
+	at time.Time
+}
+
+func (p pinnedParams) Start() time.Time    { return p.at }
+func (p pinnedParams) End() time.Time      { return p.at }
+func (p pinnedParams) Step() time.Duration { return 0 }
+
+// PinnedStepEvaluator evaluates a range or subquery pinned by an @ modifier
+// once at the pinned time and returns the result at every step of the query.
+type PinnedStepEvaluator struct {
+	inner  StepEvaluator
+	at     time.Time
+	vec    promql.Vector
+	loaded bool
+
+	currentMs, stepMs, endMs int64
+}
+
+// newPinnedStepEvaluator builds the evaluator of the pinned expression with
+// newEvaluator given the parameters of an instant query at the pinned time.
+func newPinnedStepEvaluator(q Params, at *syntax.AtModifier, newEvaluator func(Params) (StepEvaluator, error)) (*PinnedStepEvaluator, error) {
+	pinned := at.Time(q.Start(), q.End())
+	inner, err := newEvaluator(pinnedParams{Params: q, at: pinned})
+	if err != nil {
+		return nil, err
+	}
+	stepMs := q.Step().Milliseconds()
+	if stepMs == 0 {
+		stepMs = 1
+	}
+	return &PinnedStepEvaluator{
+		inner:     inner,
+		at:        pinned,
+		stepMs:    stepMs,
+		endMs:     q.End().UnixMilli(),
+		currentMs: q.Start().UnixMilli() - stepMs,
+	}, nil
+}
+
+func (e *PinnedStepEvaluator) Next() (bool, int64, StepResult) {
+	e.currentMs += e.stepMs
+	if e.currentMs > e.endMs {
+		return false, 0, SampleVector{}
+	}
+	if !e.loaded {
+		e.loaded = true
+		if ok, _, r := e.inner.Next(); ok {
+			e.vec = r.SampleVector()
+		}
+	}
+
+	vec := make(promql.Vector, len(e.vec))
+	for i, s := range e.vec {
+		s.T = e.currentMs
+		vec[i] = s
+	}
+	return true, e.currentMs, SampleVector(vec)
+}
+
+func (e *PinnedStepEvaluator) Close() error { return e.inner.Close() }
+
+func (e *PinnedStepEvaluator) Error() error { return e.inner.Error() }
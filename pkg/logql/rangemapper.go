@@ -175,6 +175,34 @@ func (m RangeMapper) Map(expr syntax.SampleExpr, vectorAggrPushdown *syntax.Vect
 		}
 		e.Left = lhsMapped
 		return e, nil
+	case *syntax.LabelJoinExpr:
+		lhsMapped, err := m.Map(e.Left, vectorAggrPushdown, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.HistogramQuantileExpr:
+		lhsMapped, err := m.Map(e.Left, vectorAggrPushdown, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.FunctionExpr:
+		if e.Left == nil {
+			return e, nil
+		}
+		lhsMapped, err := m.Map(e.Left, vectorAggrPushdown, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.SubqueryExpr:
+		// The inner query is evaluated at the subquery step which is not
+		// aligned with the split ranges, so subqueries are never split.
+		return e, nil
 	case *syntax.LiteralExpr:
 		return e, nil
 	case *syntax.VectorExpr:
@@ -201,7 +229,7 @@ func getRangeInterval(expr syntax.SampleExpr) time.Duration {
 }
 
 // hasLabelExtractionStage returns true if an expression contains a stage for label extraction,
-// such as `| json` or `| logfmt`, that would result in an exploding amount of series in downstream queries.
+// such as `| json`, `| logfmt` or `| xml`, that would result in an exploding amount of series in downstream queries.
 func hasLabelExtractionStage(expr syntax.SampleExpr) bool {
 	found := false
 	expr.Walk(func(e syntax.Expr) {
@@ -211,7 +239,8 @@ func hasLabelExtractionStage(expr syntax.SampleExpr) bool {
 		case *syntax.LabelParserExpr:
 			// It will **not** return true for `regexp`, `unpack` and `pattern`, since these label extraction
 			// stages can control how many labels, and therefore the resulting amount of series, are extracted.
-			if concrete.Op == syntax.OpParserTypeJSON {
+			switch concrete.Op {
+			case syntax.OpParserTypeJSON, syntax.OpParserTypeXML, syntax.OpParserTypeCSV:
 				found = true
 			}
 		}
@@ -470,6 +499,7 @@ func (m RangeMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 		Left:      lhsMapped,
 		Grouping:  expr.Grouping,
 		Params:    expr.Params,
+		Label:     expr.Label,
 		Operation: expr.Operation,
 	}, nil
 }
@@ -570,6 +600,12 @@ func isSplittableByRange(expr syntax.SampleExpr) bool {
 		return isSplittableByRange(e.SampleExpr) || literalLHS && isSplittableByRange(e.RHS) || literalRHS
 	case *syntax.LabelReplaceExpr:
 		return isSplittableByRange(e.Left)
+	case *syntax.LabelJoinExpr:
+		return isSplittableByRange(e.Left)
+	case *syntax.HistogramQuantileExpr:
+		return isSplittableByRange(e.Left)
+	case *syntax.FunctionExpr:
+		return e.Left != nil && isSplittableByRange(e.Left)
 	case *syntax.VectorExpr:
 		return false
 	default:
@@ -41,6 +41,15 @@ func Test_SplitRangeInterval(t *testing.T) {
 			)`,
 			2,
 		},
+		// Should keep the @ modifier on every split range
+		{
+			`count_over_time({app="foo"}[4s] @ 100 offset 1s)`,
+			`sum without () (
+				downstream<count_over_time({app="foo"}[2s] @ 100 offset 3s), shard=<nil>>
+				++ downstream<count_over_time({app="foo"}[2s] @ 100 offset 1s), shard=<nil>>
+			)`,
+			2,
+		},
 		{
 			`sum_over_time({app="foo"} | unwrap bar [3s] offset 1s)`,
 			`sum without () (
@@ -1844,6 +1853,12 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`sum(avg_over_time({app="foo"} | unwrap bar[3m]))`,
 		},
 
+		// subqueries are never split
+		{
+			`max_over_time(sum(rate({app="foo"}[3m]))[1h:5m])`,
+			`max_over_time(sum(rate({app="foo"}[3m]))[1h:5m])`,
+		},
+
 		// should be noop if range interval is lower or equal to split interval (1m)
 		{
 			`bytes_over_time({app="foo"}[1m])`,
@@ -13,23 +13,29 @@ import (
 )
 
 const (
-	ShardLastOverTime     = "last_over_time"
-	ShardFirstOverTime    = "first_over_time"
-	ShardQuantileOverTime = "quantile_over_time"
+	ShardLastOverTime        = "last_over_time"
+	ShardFirstOverTime       = "first_over_time"
+	ShardQuantileOverTime    = "quantile_over_time"
+	ShardApproxTopK          = "approx_topk"
+	ShardApproxCountDistinct = "approx_count_distinct"
 )
 
 type ShardMapper struct {
-	shards                   ShardingStrategy
-	metrics                  *MapperMetrics
-	quantileOverTimeSharding bool
-	lastOverTimeSharding     bool
-	firstOverTimeSharding    bool
+	shards                      ShardingStrategy
+	metrics                     *MapperMetrics
+	quantileOverTimeSharding    bool
+	lastOverTimeSharding        bool
+	firstOverTimeSharding       bool
+	approxTopKSharding          bool
+	approxCountDistinctSharding bool
 }
 
 func NewShardMapper(strategy ShardingStrategy, metrics *MapperMetrics, shardAggregation []string) ShardMapper {
 	quantileOverTimeSharding := false
 	lastOverTimeSharding := false
 	firstOverTimeSharding := false
+	approxTopKSharding := false
+	approxCountDistinctSharding := false
 	for _, a := range shardAggregation {
 		switch a {
 		case ShardQuantileOverTime:
@@ -38,14 +44,20 @@ func NewShardMapper(strategy ShardingStrategy, metrics *MapperMetrics, shardAggr
 			lastOverTimeSharding = true
 		case ShardFirstOverTime:
 			firstOverTimeSharding = true
+		case ShardApproxTopK:
+			approxTopKSharding = true
+		case ShardApproxCountDistinct:
+			approxCountDistinctSharding = true
 		}
 	}
 	return ShardMapper{
-		shards:                   strategy,
-		metrics:                  metrics,
-		quantileOverTimeSharding: quantileOverTimeSharding,
-		firstOverTimeSharding:    firstOverTimeSharding,
-		lastOverTimeSharding:     lastOverTimeSharding,
+		shards:                      strategy,
+		metrics:                     metrics,
+		quantileOverTimeSharding:    quantileOverTimeSharding,
+		firstOverTimeSharding:       firstOverTimeSharding,
+		lastOverTimeSharding:        lastOverTimeSharding,
+		approxTopKSharding:          approxTopKSharding,
+		approxCountDistinctSharding: approxCountDistinctSharding,
 	}
 }
 
@@ -92,8 +104,16 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder, topLevel bool)
 		return m.mapVectorAggregationExpr(e, r, topLevel)
 	case *syntax.LabelReplaceExpr:
 		return m.mapLabelReplaceExpr(e, r, topLevel)
+	case *syntax.LabelJoinExpr:
+		return m.mapLabelJoinExpr(e, r, topLevel)
+	case *syntax.HistogramQuantileExpr:
+		return m.mapHistogramQuantileExpr(e, r, topLevel)
 	case *syntax.RangeAggregationExpr:
 		return m.mapRangeAggregationExpr(e, r, topLevel)
+	case *syntax.SubqueryExpr:
+		return m.mapSubqueryExpr(e, r, topLevel)
+	case *syntax.FunctionExpr:
+		return m.mapFunctionExpr(e, r, topLevel)
 	case *syntax.BinOpExpr:
 		return m.mapBinOpExpr(e, r, topLevel)
 	default:
@@ -154,6 +174,10 @@ func (m ShardMapper) mapBinOpExpr(e *syntax.BinOpExpr, r *downstreamRecorder, to
 }
 
 func (m ShardMapper) mapLogSelectorExpr(expr syntax.LogSelectorExpr, r *downstreamRecorder) (syntax.LogSelectorExpr, uint64, error) {
+	if !expr.Shardable(true) {
+		return noOp(expr, m.shards.Resolver())
+	}
+
 	var head *ConcatLogSelectorExpr
 	shards, maxBytesPerShard, err := m.shards.Shards(expr)
 	if err != nil {
@@ -232,6 +256,13 @@ func (m ShardMapper) wrappedShardedVectorAggr(expr *syntax.VectorAggregationExpr
 // technically, std{dev,var} are also parallelizable if there is no cross-shard merging
 // in descendent nodes in the AST. This optimization is currently avoided for simplicity.
 func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	if expr.Operation == syntax.OpTypeApproxTopK && m.approxTopKSharding && isSketchableCount(expr.Left) {
+		return m.mapApproxTopKExpr(expr, r)
+	}
+	if expr.Operation == syntax.OpTypeApproxCountDistinct && m.approxCountDistinctSharding && isCountDistinctSketchable(expr.Left) {
+		return m.mapApproxCountDistinctExpr(expr, r)
+	}
+
 	if expr.Shardable(topLevel) {
 
 		switch expr.Operation {
@@ -318,11 +349,114 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 		Left:      sampleExpr,
 		Grouping:  expr.Grouping,
 		Params:    expr.Params,
+		Label:     expr.Label,
 		Operation: expr.Operation,
 	}, bytesPerShard, nil
 
 }
 
+// mapApproxTopKExpr sends a topk sketch of the counts of each shard downstream.
+// The sketches are merged on the frontend:
+// approx_topk(k, x) ->
+// topk_sketch_eval(k, __topk_sketch__(k, x, shard=1) ++ __topk_sketch__(k, x, shard=2)...)
+func (m ShardMapper) mapApproxTopKExpr(expr *syntax.VectorAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	shards, bytesPerShard, err := m.shards.Shards(expr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(shards) == 0 {
+		return noOp(expr, m.shards.Resolver())
+	}
+
+	sketchExpr := &syntax.VectorAggregationExpr{
+		Left:      expr.Left,
+		Grouping:  &syntax.Grouping{},
+		Params:    expr.Params,
+		Operation: syntax.OpTypeTopKSketch,
+	}
+	downstreams := make([]DownstreamSampleExpr, 0, len(shards))
+	for i := len(shards) - 1; i >= 0; i-- {
+		downstreams = append(downstreams, DownstreamSampleExpr{
+			shard:      &shards[i],
+			SampleExpr: sketchExpr,
+		})
+	}
+	r.Add(len(shards), MetricsKey)
+
+	return &TopKSketchEvalExpr{
+		k:           expr.Params,
+		downstreams: downstreams,
+	}, bytesPerShard, nil
+}
+
+// isSketchableCount returns whether the expression is a sum of counts that can
+// be added up across shards in a topk sketch.
+func isSketchableCount(expr syntax.SampleExpr) bool {
+	vec, ok := expr.(*syntax.VectorAggregationExpr)
+	if !ok || vec.Operation != syntax.OpTypeSum || !vec.Shardable(true) {
+		return false
+	}
+	rng, ok := vec.Left.(*syntax.RangeAggregationExpr)
+	if !ok {
+		return false
+	}
+	switch rng.Operation {
+	case syntax.OpRangeTypeCount, syntax.OpRangeTypeBytes:
+		return true
+	default:
+		return false
+	}
+}
+
+// mapApproxCountDistinctExpr sends a HyperLogLog sketch of the label values of
+// each shard downstream. The sketches are merged on the frontend:
+// approx_count_distinct(l, x) ->
+// count_distinct_sketch_eval(__count_distinct_sketch__(l, x, shard=1) ++ __count_distinct_sketch__(l, x, shard=2)...)
+func (m ShardMapper) mapApproxCountDistinctExpr(expr *syntax.VectorAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	shards, bytesPerShard, err := m.shards.Shards(expr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(shards) == 0 {
+		return noOp(expr, m.shards.Resolver())
+	}
+
+	sketchExpr := &syntax.VectorAggregationExpr{
+		Left:      expr.Left,
+		Grouping:  expr.Grouping,
+		Label:     expr.Label,
+		Operation: syntax.OpTypeCountDistinctSketch,
+	}
+	downstreams := make([]DownstreamSampleExpr, 0, len(shards))
+	for i := len(shards) - 1; i >= 0; i-- {
+		downstreams = append(downstreams, DownstreamSampleExpr{
+			shard:      &shards[i],
+			SampleExpr: sketchExpr,
+		})
+	}
+	r.Add(len(shards), MetricsKey)
+
+	return &CountDistinctSketchEvalExpr{
+		downstreams: downstreams,
+	}, bytesPerShard, nil
+}
+
+// isCountDistinctSketchable returns whether the series of the expression are
+// the union of the series of its shards. Only the labels of the series are
+// counted, so their values don't need to be merged across shards.
+func isCountDistinctSketchable(expr syntax.SampleExpr) bool {
+	switch e := expr.(type) {
+	case *syntax.RangeAggregationExpr:
+		return e.Shardable(false)
+	case *syntax.VectorAggregationExpr:
+		switch e.Operation {
+		case syntax.OpTypeSum, syntax.OpTypeAvg, syntax.OpTypeCount, syntax.OpTypeMax, syntax.OpTypeMin:
+			return isCountDistinctSketchable(e.Left)
+		}
+	}
+	return false
+}
+
 func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
 	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
 	if err != nil {
@@ -333,6 +467,58 @@ func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downs
 	return &cpy, bytesPerShard, nil
 }
 
+func (m ShardMapper) mapLabelJoinExpr(expr *syntax.LabelJoinExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapHistogramQuantileExpr shards the aggregation of the bucket counters, the
+// quantile is computed from the merged buckets on the frontend.
+func (m ShardMapper) mapHistogramQuantileExpr(expr *syntax.HistogramQuantileExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapFunctionExpr shards the argument of a function, the function itself is
+// applied to the merged results on the frontend.
+func (m ShardMapper) mapFunctionExpr(expr *syntax.FunctionExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	if expr.Left == nil {
+		return expr, 0, nil
+	}
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapSubqueryExpr shards the inner query of a subquery. The range aggregation
+// over the inner results is then evaluated on the frontend.
+func (m ShardMapper) mapSubqueryExpr(expr *syntax.SubqueryExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	if isNoOp(expr.Left, subMapped) {
+		return noOp(expr, m.shards.Resolver())
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
 // These functions require a different merge strategy than the default
 // concatenation.
 // This is because the same label sets may exist on multiple shards when label-reducing parsing is applied or when
@@ -452,7 +638,9 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 		}, bytesPerShard, nil
 
 	case syntax.OpRangeTypeQuantile:
-		if !m.quantileOverTimeSharding {
+		// sketches and timestamped samples of pinned ranges can't be
+		// repeated at every step, pinned ranges are not sharded with them.
+		if !m.quantileOverTimeSharding || expr.Left.At != nil {
 			return noOp(expr, m.shards.Resolver())
 		}
 
@@ -499,7 +687,7 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 		}, bytesPerShard, nil
 
 	case syntax.OpRangeTypeFirst:
-		if !m.firstOverTimeSharding {
+		if !m.firstOverTimeSharding || expr.Left.At != nil {
 			return noOp(expr, m.shards.Resolver())
 		}
 
@@ -530,7 +718,7 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			downstreams: downstreams,
 		}, bytesPerShard, nil
 	case syntax.OpRangeTypeLast:
-		if !m.lastOverTimeSharding {
+		if !m.lastOverTimeSharding || expr.Left.At != nil {
 			return noOp(expr, m.shards.Resolver())
 		}
 
@@ -579,9 +767,12 @@ func isNoOp(left syntax.Expr, right syntax.Expr) bool {
 }
 
 func isLiteralOrVector(e syntax.Expr) bool {
-	switch e.(type) {
+	switch e := e.(type) {
 	case *syntax.VectorExpr, *syntax.LiteralExpr:
 		return true
+	case *syntax.FunctionExpr:
+		// functions of the evaluation time, e.g. hour()
+		return e.Left == nil
 	default:
 		return false
 	}
@@ -145,6 +145,15 @@ func TestMappingStrings(t *testing.T) {
 			out: `downstream<{foo="bar"} |="foo" |~"bar" | json | (latency>=10s or (foo<5,bar="t")) | line_format "b{{.blip}}", shard=0_of_2>
 					++downstream<{foo="bar"} |="foo" |~"bar" | json | (latency>=10s or (foo<5, bar="t")) | line_format "b{{.blip}}", shard=1_of_2>`,
 		},
+		{
+			in: `{foo="bar"} | json | limit 10 by (pod)`,
+			out: `downstream<{foo="bar"} | json | limit 10 by (pod), shard=0_of_2>
+					++ downstream<{foo="bar"} | json | limit 10 by (pod), shard=1_of_2>`,
+		},
+		{
+			in:  `{foo="bar"} | dedup`,
+			out: `{foo="bar"} | dedup`,
+		},
 		{
 			in: `sum(rate({foo="bar"}[1m]))`,
 			out: `sum(
@@ -152,6 +161,31 @@ func TestMappingStrings(t *testing.T) {
 				++ downstream<sum(rate({foo="bar"}[1m])), shard=1_of_2>
 			)`,
 		},
+		{
+			in: `max_over_time(sum(rate({foo="bar"}[1m]))[1h:5m])`,
+			out: `max_over_time(sum(
+				downstream<sum(rate({foo="bar"}[1m])), shard=0_of_2>
+				++ downstream<sum(rate({foo="bar"}[1m])), shard=1_of_2>
+			)[1h:5m])`,
+		},
+		{
+			in: `sum(rate({foo="bar"}[1m] @ 1609746000)) / sum(rate({foo="bar"}[1m]))`,
+			out: `(sum(
+				downstream<sum(rate({foo="bar"}[1m] @ 1609746000)), shard=0_of_2>
+				++ downstream<sum(rate({foo="bar"}[1m] @ 1609746000)), shard=1_of_2>
+			) / sum(
+				downstream<sum(rate({foo="bar"}[1m])), shard=0_of_2>
+				++ downstream<sum(rate({foo="bar"}[1m])), shard=1_of_2>
+			))`,
+		},
+		{
+			in:  `quantile_over_time(0.99, {foo="bar"} | unwrap bytes [1m] @ 1609746000) by (app)`,
+			out: `quantile_over_time(0.99, {foo="bar"} | unwrap bytes [1m] @ 1609746000) by (app)`,
+		},
+		{
+			in:  `max_over_time(stddev_over_time({foo="bar"} | unwrap bytes [1m])[1h:])`,
+			out: `max_over_time(stddev_over_time({foo="bar"} | unwrap bytes [1m])[1h:])`,
+		},
 		{
 			in: `max(count(rate({foo="bar"}[5m]))) / 2`,
 			out: `(max(
@@ -312,6 +346,44 @@ func TestMappingStrings(t *testing.T) {
 					)
 				)`,
 		},
+		{
+			in: `clamp_min(abs(sum(rate({foo="bar"}[5m]))), 1)`,
+			out: `clamp_min(
+					abs(
+						sum(
+							downstream<sum(rate({foo="bar"}[5m])), shard=0_of_2>
+							++downstream<sum(rate({foo="bar"}[5m])), shard=1_of_2>
+						)
+					),
+					1
+				)`,
+		},
+		{
+			in:  `hour() >= 9`,
+			out: `(hour() >= 9)`,
+		},
+		{
+			in: `histogram_quantile(0.99, sum by (le) (rate({foo="bar"}[5m])))`,
+			out: `histogram_quantile(
+					0.99,
+					sum by (le) (
+						downstream<sum by (le) (rate({foo="bar"}[5m])), shard=0_of_2>
+						++downstream<sum by (le) (rate({foo="bar"}[5m])), shard=1_of_2>
+					)
+				)`,
+		},
+		{
+			in: `label_join(sum by (a) (rate({foo="bar"}[5m])), "b", "-", "a")`,
+			out: `label_join(
+					sum by (a) (
+						downstream<sum by (a) (rate({foo="bar"}[5m])), shard=0_of_2>
+						++downstream<sum by (a) (rate({foo="bar"}[5m])), shard=1_of_2>
+					),
+					"b",
+					"-",
+					"a"
+				)`,
+		},
 		{
 			in: `sum(count_over_time({foo="bar"} | logfmt | label_format bar=baz | bar="buz" [5m])) by (bar)`,
 			out: `sum by (bar) (
@@ -524,6 +596,87 @@ func TestMappingStrings_NoProbabilisticSharding(t *testing.T) {
 	}
 }
 
+func TestMappingStrings_ApproxTopK(t *testing.T) {
+	m := NewShardMapper(NewPowerOfTwoStrategy(ConstantShards(2)), nilShardMetrics, []string{ShardApproxTopK})
+	for _, tc := range []struct {
+		in  string
+		out string
+	}{
+		{
+			in: `approx_topk(10, sum by (user_id) (count_over_time({app="foo"}[1m])))`,
+			out: `topkSketchEval<10,
+				downstream<__topk_sketch__(10,sum by (user_id)(count_over_time({app="foo"}[1m]))),shard=1_of_2>
+				++ downstream<__topk_sketch__(10,sum by (user_id)(count_over_time({app="foo"}[1m]))),shard=0_of_2>
+			>`,
+		},
+		{
+			// only sums of counts can be merged in a sketch, anything else is
+			// evaluated like topk.
+			in: `approx_topk(10, sum by (user_id) (rate({app="foo"}[1m])))`,
+			out: `approx_topk(10,
+				sum by (user_id) (
+					downstream<sum by (user_id)(rate({app="foo"}[1m])),shard=0_of_2>
+					++ downstream<sum by (user_id)(rate({app="foo"}[1m])),shard=1_of_2>
+				)
+			)`,
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			ast, err := syntax.ParseExpr(tc.in)
+			require.Nil(t, err)
+
+			mapped, _, err := m.Map(ast, nilShardMetrics.downstreamRecorder(), true)
+			require.Nil(t, err)
+
+			require.Equal(t, removeWhiteSpace(tc.out), removeWhiteSpace(mapped.String()))
+		})
+	}
+}
+
+func TestMappingStrings_ApproxCountDistinct(t *testing.T) {
+	m := NewShardMapper(NewPowerOfTwoStrategy(ConstantShards(2)), nilShardMetrics, []string{ShardApproxCountDistinct})
+	for _, tc := range []struct {
+		in  string
+		out string
+	}{
+		{
+			in: `approx_count_distinct by (app) (user, count_over_time({app="foo"} | json [1m]))`,
+			out: `countDistinctSketchEval<
+				downstream<__count_distinct_sketch__ by (app)(user,count_over_time({app="foo"} | json[1m])),shard=1_of_2>
+				++ downstream<__count_distinct_sketch__ by (app)(user,count_over_time({app="foo"} | json[1m])),shard=0_of_2>
+			>`,
+		},
+		{
+			// the values of the inner series don't matter, only their labels.
+			in: `approx_count_distinct(user, sum by (user) (rate({app="foo"}[1m])))`,
+			out: `countDistinctSketchEval<
+				downstream<__count_distinct_sketch__(user,sum by (user)(rate({app="foo"}[1m]))),shard=1_of_2>
+				++ downstream<__count_distinct_sketch__(user,sum by (user)(rate({app="foo"}[1m]))),shard=0_of_2>
+			>`,
+		},
+		{
+			// topk depends on the values of all shards.
+			in: `approx_count_distinct(user, topk(10, count_over_time({app="foo"}[1m])))`,
+			out: `approx_count_distinct(user,
+				topk(10,
+					downstream<count_over_time({app="foo"}[1m]),shard=0_of_2>
+					++ downstream<count_over_time({app="foo"}[1m]),shard=1_of_2>
+				)
+			)`,
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			ast, err := syntax.ParseExpr(tc.in)
+			require.Nil(t, err)
+
+			mapped, _, err := m.Map(ast, nilShardMetrics.downstreamRecorder(), true)
+			require.Nil(t, err)
+
+			require.Equal(t, removeWhiteSpace(tc.out), removeWhiteSpace(mapped.String()))
+		})
+	}
+}
+
 func TestMapping(t *testing.T) {
 	strategy := NewPowerOfTwoStrategy(ConstantShards(2))
 	m := NewShardMapper(strategy, nilShardMetrics, []string{})
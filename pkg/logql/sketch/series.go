@@ -13,6 +13,16 @@ type TopKVector struct {
 	ts   uint64
 }
 
+// NewTopKVector returns the topk sketch of a single step at timestamp ts in
+// milliseconds.
+func NewTopKVector(topk *Topk, ts uint64) TopKVector {
+	return TopKVector{topk: topk, ts: ts}
+}
+
+func (v TopKVector) Topk() *Topk { return v.topk }
+
+func (v TopKVector) Timestamp() uint64 { return v.ts }
+
 // TopkMatrix is `promql.Value` and `parser.Value`
 type TopKMatrix []TopKVector
 
@@ -52,3 +62,29 @@ func TopKMatrixFromProto(proto *logproto.TopKMatrix) (TopKMatrix, error) {
 
 	return values, nil
 }
+
+// Merge merges the sketches of right into the sketches of s with the same
+// timestamp. Both matrices must be sorted by timestamp.
+func (s TopKMatrix) Merge(right TopKMatrix) (TopKMatrix, error) {
+	result := make(TopKMatrix, 0, max(len(s), len(right)))
+	i, j := 0, 0
+	for i < len(s) && j < len(right) {
+		switch {
+		case s[i].ts < right[j].ts:
+			result = append(result, s[i])
+			i++
+		case s[i].ts > right[j].ts:
+			result = append(result, right[j])
+			j++
+		default:
+			if err := s[i].topk.Merge(right[j].topk); err != nil {
+				return nil, err
+			}
+			result = append(result, s[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s[i:]...)
+	return append(result, right[j:]...), nil
+}
@@ -81,3 +81,34 @@ func TestTopKMatrixProtoMerge(t *testing.T) {
 	require.Equal(t, oCardinality, dCardinality)
 	require.Equal(t, uint64(100), deserialized[0].ts)
 }
+
+func TestTopKMatrixMerge(t *testing.T) {
+	newTopk := func(counts map[string]uint32) *Topk {
+		topk, err := newCMSTopK(3, 2048, 5)
+		require.NoError(t, err)
+		for event, count := range counts {
+			topk.Add(event, count)
+		}
+		return topk
+	}
+
+	left := TopKMatrix{
+		NewTopKVector(newTopk(map[string]uint32{"a": 10, "b": 5}), 100),
+		NewTopKVector(newTopk(map[string]uint32{"a": 1}), 200),
+	}
+	right := TopKMatrix{
+		NewTopKVector(newTopk(map[string]uint32{"b": 10, "c": 1}), 100),
+		NewTopKVector(newTopk(map[string]uint32{"d": 7}), 300),
+	}
+
+	merged, err := left.Merge(right)
+	require.NoError(t, err)
+	require.Len(t, merged, 3)
+
+	require.Equal(t, uint64(100), merged[0].Timestamp())
+	require.Equal(t, TopKResult{{Event: "b", Count: 15}, {Event: "a", Count: 10}, {Event: "c", Count: 1}}, merged[0].Topk().Topk())
+	require.Equal(t, uint64(200), merged[1].Timestamp())
+	require.Equal(t, TopKResult{{Event: "a", Count: 1}}, merged[1].Topk().Topk())
+	require.Equal(t, uint64(300), merged[2].Timestamp())
+	require.Equal(t, TopKResult{{Event: "d", Count: 7}}, merged[2].Topk().Topk())
+}
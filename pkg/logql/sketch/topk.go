@@ -237,7 +237,12 @@ func unsafeGetBytes(s string) []byte {
 // for each node in the heap and rebalance the heap, and then if the event we're observing has an estimate that is still
 // greater than the minimum heap element count, we should put this event into the heap and remove the other one.
 func (t *Topk) Observe(event string) {
-	estimate, h1, h2 := t.sketch.ConservativeIncrement(event)
+	t.Add(event, 1)
+}
+
+// Add observes count occurrences of the given event at once, see Observe.
+func (t *Topk) Add(event string, count uint32) {
+	estimate, h1, h2 := t.sketch.ConservativeAdd(event, count)
 	t.hll.Insert(unsafeGetBytes(event))
 
 	if t.InTopk(h1, h2) {
@@ -320,10 +325,13 @@ func (t *Topk) Merge(from *Topk) error {
 
 	all = removeDuplicates(all)
 	sort.Sort(all)
+	if len(all) > t.max {
+		all = all[:t.max]
+	}
 	temp := &MinHeap{}
 	var h1, h2 uint32
 	// TODO: merging should also potentially replace it's bloomfilter? or 0 everything in the bloomfilter
-	for _, e := range all[:t.max] {
+	for _, e := range all {
 		h1, h2 = hashn(e.Event)
 		t.heapPush(temp, e.Event, uint32(e.Count), h1, h2)
 	}
@@ -0,0 +1,209 @@
+package logql
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// defaultSubqueryStep is the resolution of a subquery without an explicit
+// step when the query has no step either, e.g. an instant query.
+const defaultSubqueryStep = time.Minute
+
+// subqueryParams are the parameters of the inner query of a subquery.
+type subqueryParams struct {
+	Params
+	expr        *syntax.SubqueryExpr
+	start, end  time.Time
+	step        time.Duration
+	queryString string
+}
+
+func newSubqueryParams(outer Params, expr *syntax.SubqueryExpr) subqueryParams {
+	step := SubqueryStep(expr, outer.Step())
+	start, end := SubqueryBounds(expr, outer.Start(), outer.End(), step)
+	return subqueryParams{
+		Params:      outer,
+		expr:        expr,
+		start:       start,
+		end:         end,
+		step:        step,
+		queryString: expr.Left.String(),
+	}
+}
+
+func (p subqueryParams) QueryString() string        { return p.queryString }
+func (p subqueryParams) GetExpression() syntax.Expr { return p.expr.Left }
+func (p subqueryParams) Start() time.Time           { return p.start }
+func (p subqueryParams) End() time.Time             { return p.end }
+func (p subqueryParams) Step() time.Duration        { return p.step }
+
+// SubqueryStep returns the resolution at which the inner query of a subquery
+// is evaluated given the step of the outer query.
+func SubqueryStep(expr *syntax.SubqueryExpr, step time.Duration) time.Duration {
+	if expr.Step > 0 {
+		return expr.Step
+	}
+	if step > 0 {
+		return step
+	}
+	return defaultSubqueryStep
+}
+
+// SubqueryBounds returns the start and end of the inner query of a subquery
+// given the bounds of the outer query. The start is aligned to a multiple of
+// the step so that the inner query evaluates at the same timestamps regardless
+// of the outer query bounds, which keeps split and cached results consistent.
+func SubqueryBounds(expr *syntax.SubqueryExpr, start, end time.Time, step time.Duration) (time.Time, time.Time) {
+	from := start.Add(-expr.Offset - expr.Range).UnixNano()
+	// the lower bound of a range is not inclusive, start with the first step after it.
+	aligned := from - from%int64(step)
+	if aligned <= from {
+		aligned += int64(step)
+	}
+	return time.Unix(0, aligned), end.Add(-expr.Offset)
+}
+
+// SubqueryEvaluator applies a range aggregation over the results of its
+// inner query evaluated at the subquery step.
+type SubqueryEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.SubqueryExpr
+	agg           BatchRangeVectorAggregator
+
+	// current, end, step, selRange and offset are in nanoseconds.
+	current, end, step, selRange, offset int64
+
+	window map[uint64]*promql.Series
+	at     []promql.Sample
+
+	// peeked holds the last result of the inner evaluator not yet consumed.
+	peeked     bool
+	peekedTs   int64
+	peekedVec  promql.Vector
+	nextExists bool
+}
+
+func newSubqueryEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.SubqueryExpr,
+	q Params,
+) (*SubqueryEvaluator, error) {
+	agg, err := aggregator(&syntax.RangeAggregationExpr{
+		Left:      &syntax.LogRange{Interval: expr.Range},
+		Operation: expr.Operation,
+		Params:    expr.Params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, newSubqueryParams(q, expr))
+	if err != nil {
+		return nil, err
+	}
+
+	step := q.Step().Nanoseconds()
+	// forces at least one step.
+	if step == 0 {
+		step = 1
+	}
+	return &SubqueryEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		agg:           agg,
+		current:       q.Start().UnixNano() - step, // first call to Next will set it to start
+		end:           q.End().UnixNano(),
+		step:          step,
+		selRange:      expr.Range.Nanoseconds(),
+		offset:        expr.Offset.Nanoseconds(),
+		window:        map[uint64]*promql.Series{},
+		nextExists:    true,
+	}, nil
+}
+
+func (e *SubqueryEvaluator) Next() (bool, int64, StepResult) {
+	e.current += e.step
+	if e.current > e.end {
+		return false, 0, SampleVector{}
+	}
+	// inner timestamps are in milliseconds.
+	rangeEnd := (e.current - e.offset) / 1e+6
+	rangeStart := rangeEnd - e.selRange/1e+6
+
+	e.popBack(rangeStart)
+	e.load(rangeStart, rangeEnd)
+
+	e.at = e.at[:0]
+	ts := e.current / 1e+6
+	for _, series := range e.window {
+		e.at = append(e.at, promql.Sample{
+			F:      e.agg(series.Floats),
+			T:      ts,
+			Metric: series.Metric,
+		})
+	}
+	return true, ts, SampleVector(e.at)
+}
+
+// popBack removes the points out of the current window.
+func (e *SubqueryEvaluator) popBack(start int64) {
+	for fp, series := range e.window {
+		i := 0
+		for i < len(series.Floats) && series.Floats[i].T <= start {
+			i++
+		}
+		series.Floats = series.Floats[i:]
+		if len(series.Floats) == 0 {
+			delete(e.window, fp)
+			putSeries(series)
+		}
+	}
+}
+
+// load consumes the inner results up to the end of the current window.
+func (e *SubqueryEvaluator) load(start, end int64) {
+	for e.peek() && e.peekedTs <= end {
+		if e.peekedTs > start {
+			for _, s := range e.peekedVec {
+				fp := s.Metric.Hash()
+				series, ok := e.window[fp]
+				if !ok {
+					series = getSeries()
+					series.Metric = s.Metric
+					e.window[fp] = series
+				}
+				series.Floats = append(series.Floats, promql.FPoint{T: e.peekedTs, F: s.F})
+			}
+		}
+		e.peeked = false
+	}
+}
+
+func (e *SubqueryEvaluator) peek() bool {
+	if e.peeked {
+		return true
+	}
+	if !e.nextExists {
+		return false
+	}
+	var r StepResult
+	e.nextExists, e.peekedTs, r = e.nextEvaluator.Next()
+	if !e.nextExists {
+		return false
+	}
+	e.peekedVec = r.SampleVector()
+	e.peeked = true
+	return true
+}
+
+func (e *SubqueryEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *SubqueryEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
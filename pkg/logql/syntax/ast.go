@@ -646,6 +646,12 @@ func newLabelParserExpr(op, param string) *LabelParserExpr {
 			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid pattern parser: %s", err.Error()), 0, 0))
 		}
 	}
+	if op == OpParserTypeCSV {
+		_, err := log.NewCSVParser(param)
+		if err != nil {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: %s", err.Error()), 0, 0))
+		}
+	}
 
 	return &LabelParserExpr{
 		Op:    op,
@@ -671,6 +677,10 @@ func (e *LabelParserExpr) Stage() (log.Stage, error) {
 		return log.NewUnpackParser(), nil
 	case OpParserTypePattern:
 		return log.NewPatternParser(e.Param)
+	case OpParserTypeXML:
+		return log.NewXMLParser(), nil
+	case OpParserTypeCSV:
+		return log.NewCSVParser(e.Param)
 	default:
 		return nil, fmt.Errorf("unknown parser operator: %s", e.Op)
 	}
@@ -843,6 +853,248 @@ func (e *KeepLabelsExpr) Walk(f WalkFn) { f(e) }
 
 func (e *KeepLabelsExpr) Accept(v RootVisitor) { v.VisitKeepLabel(e) }
 
+// StatsExpr is the `| stats` stage of a log query, which aggregates the
+// selected log lines into a table. It can only be the last stage of a log
+// query and is evaluated by the engine instead of the log pipeline.
+type StatsExpr struct {
+	Aggregations []log.StatsAggregation
+	Groups       []string
+	implicit
+}
+
+func newStatsExpr(aggregations []log.StatsAggregation, groups []string) *StatsExpr {
+	return &StatsExpr{Aggregations: aggregations, Groups: groups}
+}
+
+func mustNewPercentileAggregation(quantile, field string) log.StatsAggregation {
+	q, err := strconv.ParseFloat(quantile, 64)
+	if err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid percentile: %s", err), 0, 0))
+	}
+	if q < 0 || q > 1 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid percentile %s, must be between 0 and 1", quantile), 0, 0))
+	}
+	return log.StatsAggregation{Op: log.StatsPercentile, Field: field, Param: q}
+}
+
+func (*StatsExpr) isStageExpr() {}
+
+func (e *StatsExpr) Shardable(_ bool) bool { return false }
+
+// Stage returns a noop stage: the engine aggregates the lines returned by the
+// rest of the pipeline.
+func (e *StatsExpr) Stage() (log.Stage, error) {
+	return log.NoopStage, nil
+}
+
+func (e *StatsExpr) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpStats))
+	for i, a := range e.Aggregations {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(a.String())
+	}
+	if len(e.Groups) > 0 {
+		sb.WriteString(Grouping{Groups: e.Groups}.String())
+	}
+	return sb.String()
+}
+
+func (e *StatsExpr) Walk(f WalkFn) { f(e) }
+
+func (e *StatsExpr) Accept(v RootVisitor) { v.VisitStats(e) }
+
+// SplitStatsStage returns the stats stage of a log query and the log query
+// without it. ok is false if the log query doesn't end with a stats stage.
+func SplitStatsStage(expr LogSelectorExpr) (stats *StatsExpr, selector LogSelectorExpr, ok bool) {
+	p, isPipeline := expr.(*PipelineExpr)
+	if !isPipeline || len(p.MultiStages) == 0 {
+		return nil, expr, false
+	}
+	last := len(p.MultiStages) - 1
+	if stats, ok = p.MultiStages[last].(*StatsExpr); !ok {
+		return nil, expr, false
+	}
+	if last == 0 {
+		return stats, p.Left, true
+	}
+	return stats, newPipelineExpr(p.Left, p.MultiStages[:last:last]), true
+}
+
+// maxAroundLines is the maximum number of lines an around stage can return
+// before or after each matching line.
+const maxAroundLines = 100
+
+// AroundExpr is the `| around` stage of a log query, which returns up to Before
+// and After lines of the same stream surrounding each line matched by the rest
+// of the pipeline. It can only be the last stage of a log query and is
+// evaluated by the ingesters and the store instead of the log pipeline.
+type AroundExpr struct {
+	Before int
+	After  int
+	implicit
+}
+
+// mustNewAroundExpr creates an around stage from pairs of `before` or `after`
+// parameter names and number of lines.
+func mustNewAroundExpr(params ...string) *AroundExpr {
+	e := &AroundExpr{}
+	for i := 0; i < len(params); i += 2 {
+		n, err := strconv.Atoi(params[i+1])
+		if err != nil || n < 0 || n > maxAroundLines {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid number of lines %s, must be between 0 and %d", params[i+1], maxAroundLines), 0, 0))
+		}
+		switch params[i] {
+		case OpAroundBefore:
+			e.Before = n
+		case OpAroundAfter:
+			e.After = n
+		default:
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid around parameter %s, must be %s or %s", params[i], OpAroundBefore, OpAroundAfter), 0, 0))
+		}
+	}
+	return e
+}
+
+func (*AroundExpr) isStageExpr() {}
+
+func (e *AroundExpr) Shardable(_ bool) bool { return true }
+
+// Stage returns a noop stage: the surrounding lines are added by an entry
+// iterator over the lines of the streams before they go through the pipeline.
+func (e *AroundExpr) Stage() (log.Stage, error) {
+	return log.NoopStage, nil
+}
+
+func (e *AroundExpr) String() string {
+	if e.Before == e.After {
+		return fmt.Sprintf("%s %s %d", OpPipe, OpAround, e.Before)
+	}
+	return fmt.Sprintf("%s %s %s=%d %s=%d", OpPipe, OpAround, OpAroundBefore, e.Before, OpAroundAfter, e.After)
+}
+
+func (e *AroundExpr) Walk(f WalkFn) { f(e) }
+
+func (e *AroundExpr) Accept(v RootVisitor) { v.VisitAround(e) }
+
+// AroundStage returns the around stage of a log query. ok is false if the log
+// query doesn't end with an around stage.
+func AroundStage(expr LogSelectorExpr) (around *AroundExpr, ok bool) {
+	p, isPipeline := expr.(*PipelineExpr)
+	if !isPipeline || len(p.MultiStages) == 0 {
+		return nil, false
+	}
+	around, ok = p.MultiStages[len(p.MultiStages)-1].(*AroundExpr)
+	return around, ok
+}
+
+// LimitByExpr is the `| limit` stage of a log query, which returns at most
+// Limit lines per group of lines sharing the same values for the Groups labels,
+// or per stream if there are no Groups.
+type LimitByExpr struct {
+	Limit  int
+	Groups []string
+	implicit
+}
+
+func mustNewLimitByExpr(limit string, groups []string) *LimitByExpr {
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid limit %s, must be a positive integer", limit), 0, 0))
+	}
+	return &LimitByExpr{Limit: n, Groups: groups}
+}
+
+func (*LimitByExpr) isStageExpr() {}
+
+// Shardable returns true: the first lines of each group are always part of
+// the first lines of that group returned by each shard.
+func (e *LimitByExpr) Shardable(_ bool) bool { return true }
+
+// Stage returns a noop stage: the limit is applied by an entry iterator over
+// the lines returned by the pipeline.
+func (e *LimitByExpr) Stage() (log.Stage, error) {
+	return log.NoopStage, nil
+}
+
+func (e *LimitByExpr) String() string {
+	s := fmt.Sprintf("%s %s %d", OpPipe, OpLimit, e.Limit)
+	if len(e.Groups) > 0 {
+		s += Grouping{Groups: e.Groups}.String()
+	}
+	return s
+}
+
+func (e *LimitByExpr) Walk(f WalkFn) { f(e) }
+
+func (e *LimitByExpr) Accept(v RootVisitor) { v.VisitLimitBy(e) }
+
+// DedupExpr is the `| dedup` stage of a log query, which drops lines identical
+// to the previous line of the same group of lines sharing the same values for
+// the Groups labels, or of the same stream if there are no Groups.
+type DedupExpr struct {
+	Groups []string
+	implicit
+}
+
+func newDedupExpr(groups []string) *DedupExpr {
+	return &DedupExpr{Groups: groups}
+}
+
+func (*DedupExpr) isStageExpr() {}
+
+// Shardable returns false: lines of a group can come from streams of different
+// shards, so whether two lines are consecutive is only known once the shards
+// are merged.
+func (e *DedupExpr) Shardable(_ bool) bool { return false }
+
+// Stage returns a noop stage: duplicated lines are dropped by an entry
+// iterator over the lines returned by the pipeline.
+func (e *DedupExpr) Stage() (log.Stage, error) {
+	return log.NoopStage, nil
+}
+
+func (e *DedupExpr) String() string {
+	s := fmt.Sprintf("%s %s", OpPipe, OpDedup)
+	if len(e.Groups) > 0 {
+		s += Grouping{Groups: e.Groups}.String()
+	}
+	return s
+}
+
+func (e *DedupExpr) Walk(f WalkFn) { f(e) }
+
+func (e *DedupExpr) Accept(v RootVisitor) { v.VisitDedup(e) }
+
+// ResultStages returns the limit and dedup stages ending a log query, in the
+// order they must be applied to the lines returned by the pipeline.
+func ResultStages(expr LogSelectorExpr) []StageExpr {
+	p, ok := expr.(*PipelineExpr)
+	if !ok {
+		return nil
+	}
+	i := len(p.MultiStages)
+	for i > 0 && isResultStage(p.MultiStages[i-1]) {
+		i--
+	}
+	if i == len(p.MultiStages) {
+		return nil
+	}
+	return p.MultiStages[i:]
+}
+
+func isResultStage(s StageExpr) bool {
+	switch s.(type) {
+	case *LimitByExpr, *DedupExpr:
+		return true
+	default:
+		return false
+	}
+}
+
 func (*LineFmtExpr) isStageExpr() {}
 
 func (e *LineFmtExpr) Shardable(_ bool) bool { return true }
@@ -1011,6 +1263,88 @@ func (l *LogfmtExpressionParser) String() string {
 	return sb.String()
 }
 
+type XMLExpressionParser struct {
+	Expressions []log.LabelExtractionExpr
+
+	implicit
+}
+
+func newXMLExpressionParser(expressions []log.LabelExtractionExpr) *XMLExpressionParser {
+	if _, err := log.NewXMLExpressionParser(expressions); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid xml parser: %s", err.Error()), 0, 0))
+	}
+	return &XMLExpressionParser{
+		Expressions: expressions,
+	}
+}
+
+func (*XMLExpressionParser) isStageExpr() {}
+
+func (x *XMLExpressionParser) Shardable(_ bool) bool { return true }
+
+func (x *XMLExpressionParser) Walk(f WalkFn) { f(x) }
+
+func (x *XMLExpressionParser) Accept(v RootVisitor) { v.VisitXMLExpressionParser(x) }
+
+func (x *XMLExpressionParser) Stage() (log.Stage, error) {
+	return log.NewXMLExpressionParser(x.Expressions)
+}
+
+func (x *XMLExpressionParser) String() string {
+	return labelExtractionString(OpParserTypeXML, x.Expressions)
+}
+
+type CSVExpressionParser struct {
+	Header      string
+	Expressions []log.LabelExtractionExpr
+
+	implicit
+}
+
+func newCSVExpressionParser(header string, expressions []log.LabelExtractionExpr) *CSVExpressionParser {
+	if _, err := log.NewCSVExpressionParser(header, expressions); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: %s", err.Error()), 0, 0))
+	}
+	return &CSVExpressionParser{
+		Header:      header,
+		Expressions: expressions,
+	}
+}
+
+func (*CSVExpressionParser) isStageExpr() {}
+
+func (c *CSVExpressionParser) Shardable(_ bool) bool { return true }
+
+func (c *CSVExpressionParser) Walk(f WalkFn) { f(c) }
+
+func (c *CSVExpressionParser) Accept(v RootVisitor) { v.VisitCSVExpressionParser(c) }
+
+func (c *CSVExpressionParser) Stage() (log.Stage, error) {
+	return log.NewCSVExpressionParser(c.Header, c.Expressions)
+}
+
+func (c *CSVExpressionParser) String() string {
+	if c.Header == "" {
+		return labelExtractionString(OpParserTypeCSV, c.Expressions)
+	}
+	return labelExtractionString(OpParserTypeCSV+" "+strconv.Quote(c.Header), c.Expressions)
+}
+
+func labelExtractionString(op string, expressions []log.LabelExtractionExpr) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, op))
+	for i, exp := range expressions {
+		sb.WriteString(exp.Identifier)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(exp.Expression))
+
+		if i+1 != len(expressions) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
 func mustNewMatcher(t labels.MatchType, n, v string) *labels.Matcher {
 	m, err := labels.NewMatcher(t, n, v)
 	if err != nil {
@@ -1076,6 +1410,9 @@ type LogRange struct {
 	Left     LogSelectorExpr
 	Interval time.Duration
 	Offset   time.Duration
+	// At pins the end of the range to a fixed time instead of the time of
+	// each step, nil if the range has no @ modifier.
+	At *AtModifier
 
 	Unwrap *UnwrapExpr
 
@@ -1090,6 +1427,9 @@ func (r LogRange) String() string {
 		sb.WriteString(r.Unwrap.String())
 	}
 	sb.WriteString(fmt.Sprintf("[%v]", model.Duration(r.Interval)))
+	if r.At != nil {
+		sb.WriteString(r.At.String())
+	}
 	if r.Offset != 0 {
 		offsetExpr := OffsetExpr{Offset: r.Offset}
 		sb.WriteString(offsetExpr.String())
@@ -1121,24 +1461,30 @@ func (r *LogRange) WithoutUnwrap() (*LogRange, error) {
 		Left:     left,
 		Interval: r.Interval,
 		Offset:   r.Offset,
+		At:       r.At.clone(),
 	}, nil
 }
 
 func newLogRange(left LogSelectorExpr, interval time.Duration, u *UnwrapExpr, o *OffsetExpr) *LogRange {
 	var offset time.Duration
+	var at *AtModifier
 	if o != nil {
 		offset = o.Offset
+		at = o.At
 	}
 	return &LogRange{
 		Left:     left,
 		Interval: interval,
 		Unwrap:   u,
 		Offset:   offset,
+		At:       at,
 	}
 }
 
+// OffsetExpr holds the modifiers of a range, the offset and the optional @ modifier.
 type OffsetExpr struct {
 	Offset time.Duration
+	At     *AtModifier
 }
 
 func (o *OffsetExpr) String() string {
@@ -1147,25 +1493,106 @@ func (o *OffsetExpr) String() string {
 	return sb.String()
 }
 
-func newOffsetExpr(offset time.Duration) *OffsetExpr {
+func newOffsetExpr(offset time.Duration, at *AtModifier) *OffsetExpr {
 	return &OffsetExpr{
 		Offset: offset,
+		At:     at,
+	}
+}
+
+// AtModifier pins the evaluation time of a range or subquery to a timestamp
+// or to the start or end of the query, e.g. `@ 1609746000`, `@ start()` or `@ end()`.
+type AtModifier struct {
+	Timestamp time.Time
+	// StartOrEnd is OpStart or OpEnd when the modifier refers to the bounds
+	// of the query rather than to a timestamp.
+	StartOrEnd string
+}
+
+func (a *AtModifier) String() string {
+	if a.StartOrEnd != "" {
+		return fmt.Sprintf(" %s %s()", OpAt, a.StartOrEnd)
+	}
+	return fmt.Sprintf(" %s %s", OpAt, strconv.FormatFloat(float64(a.Timestamp.UnixMilli())/1e3, 'f', -1, 64))
+}
+
+// Time returns the time the modifier pins to for a query between start and end.
+func (a *AtModifier) Time(start, end time.Time) time.Time {
+	switch a.StartOrEnd {
+	case OpStart:
+		return start
+	case OpEnd:
+		return end
+	default:
+		return a.Timestamp
+	}
+}
+
+func (a *AtModifier) clone() *AtModifier {
+	if a == nil {
+		return nil
 	}
+	cpy := *a
+	return &cpy
+}
+
+func mustNewAtModifier(s string) *AtModifier {
+	seconds := mustNewFloat(s)
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid @ modifier timestamp: %s", s), 0, 0))
+	}
+	return &AtModifier{Timestamp: time.UnixMilli(int64(math.Round(seconds * 1e3)))}
+}
+
+// HasAtModifier returns true if a range or subquery of expr is pinned by an @ modifier.
+func HasAtModifier(expr Expr) bool {
+	var found bool
+	expr.Walk(func(e Expr) {
+		switch concrete := e.(type) {
+		case *LogRange:
+			found = found || concrete.At != nil
+		case *SubqueryExpr:
+			found = found || concrete.At != nil
+		}
+	})
+	return found
+}
+
+// ResolveAtModifiers replaces the `@ start()` and `@ end()` modifiers of expr
+// with the given start and end timestamps. The expression is modified in place.
+func ResolveAtModifiers(expr Expr, start, end time.Time) {
+	resolve := func(a *AtModifier) {
+		if a == nil || a.StartOrEnd == "" {
+			return
+		}
+		a.Timestamp = a.Time(start, end)
+		a.StartOrEnd = ""
+	}
+	expr.Walk(func(e Expr) {
+		switch concrete := e.(type) {
+		case *LogRange:
+			resolve(concrete.At)
+		case *SubqueryExpr:
+			resolve(concrete.At)
+		}
+	})
 }
 
 const (
 	// vector ops
-	OpTypeSum      = "sum"
-	OpTypeAvg      = "avg"
-	OpTypeMax      = "max"
-	OpTypeMin      = "min"
-	OpTypeCount    = "count"
-	OpTypeStddev   = "stddev"
-	OpTypeStdvar   = "stdvar"
-	OpTypeBottomK  = "bottomk"
-	OpTypeTopK     = "topk"
-	OpTypeSort     = "sort"
-	OpTypeSortDesc = "sort_desc"
+	OpTypeSum                 = "sum"
+	OpTypeAvg                 = "avg"
+	OpTypeMax                 = "max"
+	OpTypeMin                 = "min"
+	OpTypeCount               = "count"
+	OpTypeStddev              = "stddev"
+	OpTypeStdvar              = "stdvar"
+	OpTypeBottomK             = "bottomk"
+	OpTypeTopK                = "topk"
+	OpTypeApproxTopK          = "approx_topk"
+	OpTypeApproxCountDistinct = "approx_count_distinct"
+	OpTypeSort                = "sort"
+	OpTypeSortDesc            = "sort_desc"
 
 	// range vector ops
 	OpRangeTypeCount       = "count_over_time"
@@ -1214,6 +1641,8 @@ const (
 	OpParserTypeRegexp  = "regexp"
 	OpParserTypeUnpack  = "unpack"
 	OpParserTypePattern = "pattern"
+	OpParserTypeXML     = "xml"
+	OpParserTypeCSV     = "csv"
 
 	OpFmtLine    = "line_format"
 	OpFmtLabel   = "label_format"
@@ -1222,6 +1651,9 @@ const (
 	OpPipe   = "|"
 	OpUnwrap = "unwrap"
 	OpOffset = "offset"
+	OpAt     = "@"
+	OpStart  = "start"
+	OpEnd    = "end"
 
 	OpOn       = "on"
 	OpIgnoring = "ignoring"
@@ -1235,6 +1667,25 @@ const (
 	OpConvDurationSeconds = "duration_seconds"
 
 	OpLabelReplace = "label_replace"
+	OpLabelJoin    = "label_join"
+
+	OpHistogramQuantile = "histogram_quantile"
+
+	// math functions
+	OpFuncAbs      = "abs"
+	OpFuncCeil     = "ceil"
+	OpFuncFloor    = "floor"
+	OpFuncRound    = "round"
+	OpFuncClampMin = "clamp_min"
+	OpFuncClampMax = "clamp_max"
+	OpFuncLn       = "ln"
+	OpFuncExp      = "exp"
+	OpFuncSqrt     = "sqrt"
+
+	// time functions
+	OpFuncTimestamp = "timestamp"
+	OpFuncHour      = "hour"
+	OpFuncDayOfWeek = "day_of_week"
 
 	// function filters
 	OpFilterIP = "ip"
@@ -1245,6 +1696,18 @@ const (
 	// keep labels
 	OpKeep = "keep"
 
+	// stats stage
+	OpStats = "stats"
+
+	// around stage
+	OpAround       = "around"
+	OpAroundBefore = "before"
+	OpAroundAfter  = "after"
+
+	// result stages
+	OpLimit = "limit"
+	OpDedup = "dedup"
+
 	// parser flags
 	OpStrict    = "--strict"
 	OpKeepEmpty = "--keep-empty"
@@ -1255,6 +1718,8 @@ const (
 	OpRangeTypeQuantileSketch     = "__quantile_sketch_over_time__"
 	OpRangeTypeFirstWithTimestamp = "__first_over_time_ts__"
 	OpRangeTypeLastWithTimestamp  = "__last_over_time_ts__"
+	OpTypeTopKSketch              = "__topk_sketch__"
+	OpTypeCountDistinctSketch     = "__count_distinct_sketch__"
 )
 
 func IsComparisonOperator(op string) bool {
@@ -1349,6 +1814,7 @@ func (e *RangeAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
 				Matchers: xs,
 				Interval: e.Left.Interval,
 				Offset:   e.Left.Offset,
+				At:       e.Left.At,
 			},
 		}, nil
 	}
@@ -1474,6 +1940,7 @@ type VectorAggregationExpr struct {
 
 	Grouping  *Grouping `json:"grouping,omitempty"`
 	Params    int       `json:"params"`
+	Label     string    `json:"label,omitempty"`
 	Operation string    `json:"operation"`
 	err       error
 	implicit
@@ -1481,9 +1948,10 @@ type VectorAggregationExpr struct {
 
 func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Grouping, params *string) SampleExpr {
 	var p int
+	var label string
 	var err error
 	switch operation {
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK:
 		if params == nil {
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
 		}
@@ -1495,6 +1963,15 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter (must be greater than 0) %s(%s", operation, *params), 0, 0)}
 		}
 
+	case OpTypeApproxCountDistinct:
+		if params == nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("label required for operation %s", operation), 0, 0)}
+		}
+		if !model.LabelName(*params).IsValid() {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid label %s(%s,", operation, *params), 0, 0)}
+		}
+		label = *params
+
 	default:
 		if params != nil {
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("unsupported parameter for operation %s(%s,", operation, *params), 0, 0)}
@@ -1503,11 +1980,16 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 	if gr == nil {
 		gr = &Grouping{}
 	}
+	// the sketch of approx_topk is shared by all series, it can't be grouped.
+	if operation == OpTypeApproxTopK && !gr.Singleton() {
+		return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("grouping not allowed for %s aggregation", operation), 0, 0)}
+	}
 	return &VectorAggregationExpr{
 		Left:      left,
 		Operation: operation,
 		Grouping:  gr,
 		Params:    p,
+		Label:     label,
 	}
 }
 
@@ -1559,8 +2041,10 @@ func (e *VectorAggregationExpr) String() string {
 	var params []string
 	switch e.Operation {
 	// bottomK and topk can have first parameter as 0
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK, OpTypeTopKSketch:
 		params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
+	case OpTypeApproxCountDistinct, OpTypeCountDistinctSketch:
+		params = []string{e.Label, e.Left.String()}
 	default:
 		if e.Params != 0 {
 			params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
@@ -2200,6 +2684,436 @@ func (e *LabelReplaceExpr) String() string {
 	return sb.String()
 }
 
+// LabelJoinExpr sets a label to the values of a list of source labels joined
+// by a separator, e.g. label_join(..., "dst", ",", "src1", "src2").
+type LabelJoinExpr struct {
+	Left      SampleExpr
+	Dst       string
+	Separator string
+	Src       []string
+	err       error
+
+	implicit
+}
+
+func mustNewLabelJoinExpr(left SampleExpr, dst, separator string, src []string) *LabelJoinExpr {
+	if !model.LabelName(dst).IsValid() {
+		return &LabelJoinExpr{
+			err: logqlmodel.NewParseError(fmt.Sprintf("invalid destination label name in label_join: %s", dst), 0, 0),
+		}
+	}
+	for _, name := range src {
+		if !model.LabelName(name).IsValid() {
+			return &LabelJoinExpr{
+				err: logqlmodel.NewParseError(fmt.Sprintf("invalid source label name in label_join: %s", name), 0, 0),
+			}
+		}
+	}
+	return &LabelJoinExpr{
+		Left:      left,
+		Dst:       dst,
+		Separator: separator,
+		Src:       src,
+	}
+}
+
+func (e *LabelJoinExpr) isSampleExpr() {}
+
+func (e *LabelJoinExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *LabelJoinExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *LabelJoinExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+func (e *LabelJoinExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *LabelJoinExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *LabelJoinExpr) Accept(v RootVisitor) { v.VisitLabelJoin(e) }
+
+func (e *LabelJoinExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(OpLabelJoin)
+	sb.WriteString("(")
+	sb.WriteString(e.Left.String())
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Dst))
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Separator))
+	for _, src := range e.Src {
+		sb.WriteString(",")
+		sb.WriteString(strconv.Quote(src))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// HistogramQuantileExpr computes the φ-quantile of the histograms made of the
+// bucket counters of a vector, identified by their upper bound in the `le`
+// label, e.g. histogram_quantile(0.99, sum by (le) (...)).
+type HistogramQuantileExpr struct {
+	Left     SampleExpr
+	Quantile float64
+	err      error
+
+	implicit
+}
+
+func newHistogramQuantileExpr(left SampleExpr, quantile *LiteralExpr) *HistogramQuantileExpr {
+	v, err := quantile.Value()
+	if err != nil {
+		return &HistogramQuantileExpr{err: err}
+	}
+	return &HistogramQuantileExpr{
+		Left:     left,
+		Quantile: v,
+	}
+}
+
+func (e *HistogramQuantileExpr) isSampleExpr() {}
+
+func (e *HistogramQuantileExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *HistogramQuantileExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *HistogramQuantileExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+func (e *HistogramQuantileExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *HistogramQuantileExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *HistogramQuantileExpr) Accept(v RootVisitor) { v.VisitHistogramQuantile(e) }
+
+func (e *HistogramQuantileExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(OpHistogramQuantile)
+	sb.WriteString("(")
+	sb.WriteString(strconv.FormatFloat(e.Quantile, 'f', -1, 64))
+	sb.WriteString(",")
+	sb.WriteString(e.Left.String())
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// FunctionExpr applies a function to the value of every sample of a vector,
+// e.g. abs(...), clamp_min(..., 0) or hour(...).
+type FunctionExpr struct {
+	// Left is nil for time functions called without argument, e.g. hour(),
+	// which are applied to the evaluation time.
+	Left     SampleExpr
+	Function string
+	Args     []float64
+	err      error
+
+	implicit
+}
+
+func newFunctionExpr(function string, left SampleExpr, arg *LiteralExpr) *FunctionExpr {
+	var args []float64
+	if arg != nil {
+		v, err := arg.Value()
+		if err != nil {
+			return &FunctionExpr{err: err}
+		}
+		args = append(args, v)
+	}
+
+	e := &FunctionExpr{
+		Left:     left,
+		Function: function,
+		Args:     args,
+	}
+	if err := e.validate(); err != nil {
+		return &FunctionExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+func (e *FunctionExpr) validate() error {
+	var minArgs, maxArgs int
+	var optionalLeft bool
+	switch e.Function {
+	case OpFuncAbs, OpFuncCeil, OpFuncFloor, OpFuncLn, OpFuncExp, OpFuncSqrt, OpFuncTimestamp:
+	case OpFuncRound:
+		maxArgs = 1
+	case OpFuncClampMin, OpFuncClampMax:
+		minArgs, maxArgs = 1, 1
+	case OpFuncHour, OpFuncDayOfWeek:
+		// Without a vector argument these use the evaluation time.
+		optionalLeft = true
+	default:
+		return fmt.Errorf("unknown function %s", e.Function)
+	}
+	if e.Left == nil && !optionalLeft {
+		return fmt.Errorf("function %s requires a vector argument", e.Function)
+	}
+	if len(e.Args) < minArgs || len(e.Args) > maxArgs {
+		return fmt.Errorf("invalid number of arguments for function %s", e.Function)
+	}
+	return nil
+}
+
+func (e *FunctionExpr) isSampleExpr() {}
+
+func (e *FunctionExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.Left == nil {
+		return &VectorExpr{}, nil
+	}
+	return e.Left.Selector()
+}
+
+func (e *FunctionExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.Left == nil {
+		return nil, nil
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *FunctionExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.Left == nil {
+		return nil, nil
+	}
+	return e.Left.Extractor()
+}
+
+func (e *FunctionExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *FunctionExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *FunctionExpr) Accept(v RootVisitor) { v.VisitFunction(e) }
+
+// impls Stringer
+func (e *FunctionExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Function)
+	sb.WriteString("(")
+	if e.Left != nil {
+		sb.WriteString(e.Left.String())
+	}
+	for _, arg := range e.Args {
+		sb.WriteString(",")
+		sb.WriteString(strconv.FormatFloat(arg, 'f', -1, 64))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// SubqueryExpr is a range aggregation over the results of a metric query
+// evaluated at a fixed resolution, e.g. max_over_time(rate({app="foo"}[1m])[1h:5m]).
+type SubqueryExpr struct {
+	Left      SampleExpr
+	Operation string
+	Params    *float64
+	Range     time.Duration
+	// Step is the resolution at which Left is evaluated, zero means the step
+	// of the query is used.
+	Step   time.Duration
+	Offset time.Duration
+	// At pins the evaluation time of the subquery, nil if the subquery has
+	// no @ modifier.
+	At  *AtModifier
+	err error
+
+	implicit
+}
+
+// SubqueryRange holds the range and the optional step of a subquery, e.g. [1h:5m].
+type SubqueryRange struct {
+	Range time.Duration
+	Step  time.Duration
+}
+
+func newSubqueryExpr(left SampleExpr, operation string, rng SubqueryRange, offset *OffsetExpr, stringParams *string) SampleExpr {
+	switch operation {
+	case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeCount, OpRangeTypeMax, OpRangeTypeMin,
+		OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeFirst,
+		OpRangeTypeLast, OpRangeTypeRateCounter:
+	default:
+		return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid aggregation %s over subquery", operation), 0, 0)}
+	}
+
+	var params *float64
+	if stringParams != nil {
+		if operation != OpRangeTypeQuantile {
+			return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)}
+		}
+		var err error
+		params = new(float64)
+		*params, err = strconv.ParseFloat(*stringParams, 64)
+		if err != nil {
+			return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)}
+		}
+	} else if operation == OpRangeTypeQuantile {
+		return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+	}
+
+	if rng.Range <= 0 {
+		return &SubqueryExpr{err: logqlmodel.NewParseError("subquery range must be positive", 0, 0)}
+	}
+	if rng.Step < 0 {
+		return &SubqueryExpr{err: logqlmodel.NewParseError("subquery step must be positive", 0, 0)}
+	}
+
+	e := &SubqueryExpr{
+		Left:      left,
+		Operation: operation,
+		Params:    params,
+		Range:     rng.Range,
+		Step:      rng.Step,
+	}
+	if offset != nil {
+		e.Offset = offset.Offset
+		e.At = offset.At
+	}
+	return e
+}
+
+func (e *SubqueryExpr) isSampleExpr() {}
+
+func (e *SubqueryExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+// MatcherGroups returns the matcher groups of the inner query, their interval
+// is widened by the range and offset of the subquery.
+func (e *SubqueryExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	groups, err := e.Left.MatcherGroups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		groups[i].Interval += e.Range
+		groups[i].Offset += e.Offset
+		if groups[i].At == nil {
+			groups[i].At = e.At
+		}
+	}
+	return groups, nil
+}
+
+func (e *SubqueryExpr) Extractor() (SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractor()
+}
+
+// Shardable returns false, the inner query may still be sharded on its own.
+func (e *SubqueryExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *SubqueryExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
+func (e *SubqueryExpr) Accept(v RootVisitor) { v.VisitSubquery(e) }
+
+// impls Stringer
+func (e *SubqueryExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	if e.Params != nil {
+		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+		sb.WriteString(",")
+	}
+	sb.WriteString(e.Left.String())
+	sb.WriteString(e.rangeString())
+	sb.WriteString(")")
+	return sb.String()
+}
+
+func (e *SubqueryExpr) rangeString() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[%v:", model.Duration(e.Range)))
+	if e.Step != 0 {
+		sb.WriteString(model.Duration(e.Step).String())
+	}
+	sb.WriteString("]")
+	if e.At != nil {
+		sb.WriteString(e.At.String())
+	}
+	if e.Offset != 0 {
+		offsetExpr := OffsetExpr{Offset: e.Offset}
+		sb.WriteString(offsetExpr.String())
+	}
+	return sb.String()
+}
+
 // shardableOps lists the operations which may be sharded, but are not
 // guaranteed to be. See the `Shardable()` implementations
 // on the respective expr types for more details.
@@ -2249,6 +3163,9 @@ var shardableOps = map[string]bool{
 type MatcherRange struct {
 	Matchers         []*labels.Matcher
 	Interval, Offset time.Duration
+	// At is the @ modifier pinning the range, nil if the range follows the
+	// steps of the query.
+	At *AtModifier
 }
 
 func MatcherGroups(expr Expr) ([]MatcherRange, error) {
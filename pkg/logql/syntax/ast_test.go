@@ -50,6 +50,10 @@ func Test_logSelectorExpr_String(t *testing.T) {
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)"`, true},
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)" | ( ( foo<5.01 , bar>20ms ) or foo="bar" ) | line_format "blip{{.boop}}bap" | label_format foo=bar,bar="blip{{.blop}}"`, true},
 		{`{foo="bar"} | logfmt | counter>-1 | counter>=-1 | counter<-1 | counter<=-1 | counter!=-1 | counter==-1`, true},
+		{`{foo="bar"} |= "baz" | xml | level="error"`, true},
+		{`{foo="bar"} |= "baz" | xml id="/Event/System/EventID",ip="//Data[@Name='IpAddress']" | id="4625"`, true},
+		{`{foo="bar"} |= "baz" | csv | column_2="error"`, true},
+		{`{foo="bar"} |= "baz" | csv ts="ts",level="level",msg="4" | level="error"`, true},
 	}
 
 	for _, tt := range tests {
@@ -262,6 +266,31 @@ func TestMatcherGroups(t *testing.T) {
 				},
 			},
 		},
+		{
+			query: `count_over_time({job="foo"}[5m] @ 1609746000 offset 10m)`,
+			exp: []MatcherRange{
+				{
+					Interval: 5 * time.Minute,
+					Offset:   10 * time.Minute,
+					At:       &AtModifier{Timestamp: time.UnixMilli(1609746000000)},
+					Matchers: []*labels.Matcher{
+						labels.MustNewMatcher(labels.MatchEqual, "job", "foo"),
+					},
+				},
+			},
+		},
+		{
+			query: `max_over_time(count_over_time({job="foo"}[5m])[1h:] @ end())`,
+			exp: []MatcherRange{
+				{
+					Interval: time.Hour + 5*time.Minute,
+					At:       &AtModifier{StartOrEnd: OpEnd},
+					Matchers: []*labels.Matcher{
+						labels.MustNewMatcher(labels.MatchEqual, "job", "foo"),
+					},
+				},
+			},
+		},
 	} {
 		t.Run(fmt.Sprint(i), func(t *testing.T) {
 			expr, err := ParseExpr(tc.query)
@@ -273,6 +302,97 @@ func TestMatcherGroups(t *testing.T) {
 	}
 }
 
+func TestSplitStatsStage(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		stats    string
+		selector string
+	}{
+		{
+			in:       `{app="foo"} | json | stats count() by (status)`,
+			stats:    `| stats count() by (status)`,
+			selector: `{app="foo"} | json`,
+		},
+		{
+			in:       `{app="foo"} | stats avg(duration)`,
+			stats:    `| stats avg(duration)`,
+			selector: `{app="foo"}`,
+		},
+		{
+			in:       `{app="foo"} | json`,
+			selector: `{app="foo"} | json`,
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			expr, err := ParseLogSelector(tc.in, true)
+			require.NoError(t, err)
+
+			stats, selector, ok := SplitStatsStage(expr)
+			require.Equal(t, tc.stats != "", ok)
+			if ok {
+				require.Equal(t, tc.stats, stats.String())
+			}
+			require.Equal(t, tc.selector, selector.String())
+			// the original expression is left untouched
+			require.Equal(t, expr.String(), MustParseExpr(tc.in).String())
+		})
+	}
+}
+
+func TestAroundStage(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		around *AroundExpr
+	}{
+		{`{app="foo"} |= "error" | around 3`, &AroundExpr{Before: 3, After: 3}},
+		{`{app="foo"} | json | around before=1`, &AroundExpr{Before: 1}},
+		{`{app="foo"} |= "error"`, nil},
+		{`{app="foo"}`, nil},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			expr, err := ParseLogSelector(tc.in, true)
+			require.NoError(t, err)
+
+			around, ok := AroundStage(expr)
+			require.Equal(t, tc.around != nil, ok)
+			require.Equal(t, tc.around, around)
+		})
+	}
+}
+
+func TestResultStages(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		stages []StageExpr
+	}{
+		{`{app="foo"} | limit 3 by (pod)`, []StageExpr{&LimitByExpr{Limit: 3, Groups: []string{"pod"}}}},
+		{`{app="foo"} | json | dedup | limit 3`, []StageExpr{&DedupExpr{}, &LimitByExpr{Limit: 3}}},
+		{`{app="foo"} |= "error"`, nil},
+		{`{app="foo"}`, nil},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			expr, err := ParseLogSelector(tc.in, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.stages, ResultStages(expr))
+		})
+	}
+}
+
+func TestResolveAtModifiers(t *testing.T) {
+	start, end := time.Unix(100, 0), time.Unix(200, 0)
+
+	expr, err := ParseExpr(`rate({app="foo"}[1m] @ start()) / max_over_time(rate({app="foo"}[1m] @ 50)[1h:] @ end())`)
+	require.NoError(t, err)
+	require.True(t, HasAtModifier(expr))
+
+	ResolveAtModifiers(expr, start, end)
+	require.Equal(t, `(rate({app="foo"}[1m] @ 100) / max_over_time(rate({app="foo"}[1m] @ 50)[1h:] @ 200))`, expr.String())
+
+	expr, err = ParseExpr(`rate({app="foo"}[1m] offset 1h)`)
+	require.NoError(t, err)
+	require.False(t, HasAtModifier(expr))
+}
+
 func Test_NilFilterDoesntPanic(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []string{
@@ -671,6 +791,34 @@ func TestStringer(t *testing.T) {
 			in:  `0 > count_over_time({foo="bar"}[1m])`,
 			out: `(0 > count_over_time({foo="bar"}[1m]))`,
 		},
+		{
+			in:  `{app="foo"} | json | STATS count(), percentile(0.990, latency) by (status,route)`,
+			out: `{app="foo"} | json | stats count(), percentile(0.99, latency) by (status,route)`,
+		},
+		{
+			in:  `{app="foo"} |= "error" | around before=3 after=3`,
+			out: `{app="foo"} |= "error" | around 3`,
+		},
+		{
+			in:  `{app="foo"} |= "error" | around after=5`,
+			out: `{app="foo"} |= "error" | around before=0 after=5`,
+		},
+		{
+			in:  `{app="foo"} | logfmt | dedup | LIMIT 10 BY (pod, namespace)`,
+			out: `{app="foo"} | logfmt | dedup | limit 10 by (pod,namespace)`,
+		},
+		{
+			in:  `{app="foo"} | dedup by (app) | limit 5`,
+			out: `{app="foo"} | dedup by (app) | limit 5`,
+		},
+		{
+			in:  `rate({app="foo"}[1m] offset 1h @ 1609746000.000)`,
+			out: `rate({app="foo"}[1m] @ 1609746000 offset 1h0m0s)`,
+		},
+		{
+			in:  `max_over_time(rate({app="foo"}[1m])[1h:] @ end())`,
+			out: `max_over_time(rate({app="foo"}[1m])[1h:] @ end())`,
+		},
 		{
 			in:  `{app="foo"} |= "foo" or "bar"`,
 			out: `{app="foo"} |= "foo" or "bar"`,
@@ -867,6 +1015,10 @@ func Test_parserExpr_Parser(t *testing.T) {
 	}{
 		{"json", OpParserTypeJSON, "", log.NewJSONParser(), false, false},
 		{"unpack", OpParserTypeUnpack, "", log.NewUnpackParser(), false, false},
+		{"xml", OpParserTypeXML, "", log.NewXMLParser(), false, false},
+		{"csv", OpParserTypeCSV, "", mustNewCSVParser(""), false, false},
+		{"csv header", OpParserTypeCSV, "method,path", mustNewCSVParser("method,path"), false, false},
+		{"csv header err", OpParserTypeCSV, `"method`, nil, true, true},
 		{"pattern", OpParserTypePattern, "<foo> bar <buzz>", mustNewPatternParser("<foo> bar <buzz>"), false, false},
 		{"pattern err", OpParserTypePattern, "bar", nil, true, true},
 		{"regexp", OpParserTypeRegexp, "(?P<foo>foo)", mustNewRegexParser("(?P<foo>foo)"), false, false},
@@ -907,6 +1059,9 @@ func Test_parserExpr_String(t *testing.T) {
 		{"valid pattern", OpParserTypePattern, "buzz", `| pattern "buzz"`},
 		{"empty pattern", OpParserTypePattern, "", `| pattern ""`},
 		{"valid json", OpParserTypeJSON, "", `| json`},
+		{"valid xml", OpParserTypeXML, "", `| xml`},
+		{"valid csv", OpParserTypeCSV, "", `| csv`},
+		{"csv header", OpParserTypeCSV, "method,path", `| csv "method,path"`},
 	}
 
 	for _, tt := range tests {
@@ -936,6 +1091,14 @@ func mustNewPatternParser(p string) log.Stage {
 	return r
 }
 
+func mustNewCSVParser(header string) log.Stage {
+	r, err := log.NewCSVParser(header)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
 func Test_canInjectVectorGrouping(t *testing.T) {
 	tests := []struct {
 		vecOp   string
@@ -54,6 +54,7 @@ func (v *cloneVisitor) VisitVectorAggregation(e *VectorAggregationExpr) {
 	copied := &VectorAggregationExpr{
 		Left:      MustClone[SampleExpr](e.Left),
 		Params:    e.Params,
+		Label:     e.Label,
 		Operation: e.Operation,
 	}
 
@@ -87,6 +88,91 @@ func (v *cloneVisitor) VisitLabelReplace(e *LabelReplaceExpr) {
 	v.cloned = mustNewLabelReplaceExpr(left, e.Dst, e.Replacement, e.Src, e.Regex)
 }
 
+func (v *cloneVisitor) VisitLabelJoin(e *LabelJoinExpr) {
+	copied := &LabelJoinExpr{
+		Left:      MustClone[SampleExpr](e.Left),
+		Dst:       e.Dst,
+		Separator: e.Separator,
+	}
+	if e.Src != nil {
+		copied.Src = make([]string, len(e.Src))
+		copy(copied.Src, e.Src)
+	}
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitHistogramQuantile(e *HistogramQuantileExpr) {
+	v.cloned = &HistogramQuantileExpr{
+		Left:     MustClone[SampleExpr](e.Left),
+		Quantile: e.Quantile,
+	}
+}
+
+func (v *cloneVisitor) VisitAround(e *AroundExpr) {
+	v.cloned = &AroundExpr{Before: e.Before, After: e.After}
+}
+
+func (v *cloneVisitor) VisitDedup(e *DedupExpr) {
+	copied := &DedupExpr{}
+	if e.Groups != nil {
+		copied.Groups = make([]string, len(e.Groups))
+		copy(copied.Groups, e.Groups)
+	}
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitLimitBy(e *LimitByExpr) {
+	copied := &LimitByExpr{Limit: e.Limit}
+	if e.Groups != nil {
+		copied.Groups = make([]string, len(e.Groups))
+		copy(copied.Groups, e.Groups)
+	}
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitStats(e *StatsExpr) {
+	copied := &StatsExpr{
+		Aggregations: make([]log.StatsAggregation, len(e.Aggregations)),
+		Groups:       make([]string, len(e.Groups)),
+	}
+	copy(copied.Aggregations, e.Aggregations)
+	copy(copied.Groups, e.Groups)
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitSubquery(e *SubqueryExpr) {
+	copied := &SubqueryExpr{
+		Left:      MustClone[SampleExpr](e.Left),
+		Operation: e.Operation,
+		Range:     e.Range,
+		Step:      e.Step,
+		Offset:    e.Offset,
+		At:        e.At.clone(),
+	}
+
+	if e.Params != nil {
+		tmp := *e.Params
+		copied.Params = &tmp
+	}
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitFunction(e *FunctionExpr) {
+	copied := &FunctionExpr{
+		Function: e.Function,
+	}
+	if e.Left != nil {
+		copied.Left = MustClone[SampleExpr](e.Left)
+	}
+	if e.Args != nil {
+		copied.Args = make([]float64, len(e.Args))
+		copy(copied.Args, e.Args)
+	}
+	v.cloned = copied
+}
+
 func (v *cloneVisitor) VisitLiteral(e *LiteralExpr) {
 	v.cloned = &LiteralExpr{Val: e.Val}
 }
@@ -100,6 +186,7 @@ func (v *cloneVisitor) VisitLogRange(e *LogRange) {
 		Left:     MustClone[LogSelectorExpr](e.Left),
 		Interval: e.Interval,
 		Offset:   e.Offset,
+		At:       e.At.clone(),
 	}
 	if e.Unwrap != nil {
 		copied.Unwrap = &UnwrapExpr{
@@ -159,6 +246,16 @@ func (v *cloneVisitor) VisitDropLabels(e *DropLabelsExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitCSVExpressionParser(e *CSVExpressionParser) {
+	copied := &CSVExpressionParser{
+		Header:      e.Header,
+		Expressions: make([]log.LabelExtractionExpr, len(e.Expressions)),
+	}
+	copy(copied.Expressions, e.Expressions)
+
+	v.cloned = copied
+}
+
 func (v *cloneVisitor) VisitJSONExpressionParser(e *JSONExpressionParser) {
 	copied := &JSONExpressionParser{
 		Expressions: make([]log.LabelExtractionExpr, len(e.Expressions)),
@@ -300,3 +397,12 @@ func (v *cloneVisitor) VisitLogfmtParser(e *LogfmtParserExpr) {
 		KeepEmpty: e.KeepEmpty,
 	}
 }
+
+func (v *cloneVisitor) VisitXMLExpressionParser(e *XMLExpressionParser) {
+	copied := &XMLExpressionParser{
+		Expressions: make([]log.LabelExtractionExpr, len(e.Expressions)),
+	}
+	copy(copied.Expressions, e.Expressions)
+
+	v.cloned = copied
+}
@@ -38,6 +38,15 @@ func TestClone(t *testing.T) {
 		"keep label": {
 			query: `{app="foo"} |= "bar" | json | keep latency, status_code="200"`,
 		},
+		"stats": {
+			query: `{app="foo"} |= "bar" | json | stats count(), avg(latency) by (status_code)`,
+		},
+		"around": {
+			query: `{app="foo"} |= "bar" | around before=2 after=5`,
+		},
+		"limit and dedup": {
+			query: `{app="foo"} |= "bar" | dedup by (app) | limit 10 by (pod)`,
+		},
 		"regexp": {
 			query: `{env="prod", app=~"loki.*"} |~ ".*foo.*"`,
 		},
@@ -50,6 +59,12 @@ func TestClone(t *testing.T) {
 		"label replace": {
 			query: `label_replace(vector(0.000000),"foo","bar","","")`,
 		},
+		"label join": {
+			query: `label_join(rate({app="api"}[1m]),"dst","-","app","pod")`,
+		},
+		"histogram quantile": {
+			query: `histogram_quantile(0.99,sum by (le)(sum_over_time({app="api"} | unwrap count[1m])))`,
+		},
 		"filters with bytes": {
 			query: `{app="foo"} |= "bar" | json | ( status_code <500 or ( status_code>200 , size>=2.5KiB ) )`,
 		},
@@ -30,14 +30,15 @@ import (
   FilterOp                string
   BinOpExpr               SampleExpr
   LabelReplaceExpr        SampleExpr
+  FunctionExpr            SampleExpr
+  FunctionOp              string
   binOp                   string
   bytes                   uint64
   str                     string
   duration                time.Duration
+  subqueryRange           SubqueryRange
   LiteralExpr             *LiteralExpr
   BinOpModifier           *BinOpOptions
-  BoolModifier            *BinOpOptions
-  OnOrIgnoringModifier    *BinOpOptions
   LabelParser             *LabelParserExpr
   LogfmtParser            *LogfmtParserExpr
   LineFilters             *LineFilterExpr
@@ -61,6 +62,8 @@ import (
   LabelExtractionExpressionList []log.LabelExtractionExpr
   JSONExpressionParser          *JSONExpressionParser
   LogfmtExpressionParser        *LogfmtExpressionParser
+  XMLExpressionParser           *XMLExpressionParser
+  CSVExpressionParser           *CSVExpressionParser
 
   UnwrapExpr              *UnwrapExpr
   DecolorizeExpr          *DecolorizeExpr
@@ -71,6 +74,8 @@ import (
   KeepLabel               log.KeepLabel
   KeepLabels              []log.KeepLabel
   KeepLabelsExpr          *KeepLabelsExpr
+  StatsAggregation        log.StatsAggregation
+  StatsAggregations       []log.StatsAggregation
 }
 
 %start root
@@ -96,9 +101,14 @@ import (
 %type <BinOpExpr>             binOpExpr
 %type <LiteralExpr>           literalExpr
 %type <LabelReplaceExpr>      labelReplaceExpr
+%type <FunctionExpr>          functionExpr
+%type <FunctionExpr>          labelJoinExpr
+%type <FunctionExpr>          histogramQuantileExpr
+%type <Labels>                stringList
+%type <FunctionOp>            functionOp
 %type <BinOpModifier>         binOpModifier
-%type <BoolModifier>          boolModifier
-%type <OnOrIgnoringModifier>  onOrIgnoringModifier
+%type <BinOpModifier>         boolModifier
+%type <BinOpModifier>         onOrIgnoringModifier
 %type <LabelParser>           labelParser
 %type <LogfmtParser>          logfmtParser
 %type <PipelineExpr>          pipelineExpr
@@ -126,28 +136,43 @@ import (
 %type <LabelExtractionExpressionList>    labelExtractionExpressionList
 %type <LogfmtExpressionParser>           logfmtExpressionParser
 %type <JSONExpressionParser>             jsonExpressionParser
+%type <XMLExpressionParser>              xmlExpressionParser
+%type <CSVExpressionParser>              csvExpressionParser
 %type <UnwrapExpr>            unwrapExpr
 %type <UnitFilter>            unitFilter
 %type <IPLabelFilter>         ipLabelFilter
 %type <OffsetExpr>            offsetExpr
+%type <OffsetExpr>            atModifier
+%type <PipelineStage>         statsExpr
+%type <StatsAggregation>      statsAggregation
+%type <StatsAggregations>     statsAggregations
+%type <PipelineStage>         aroundExpr
+%type <PipelineStage>         limitByExpr
+%type <PipelineStage>         dedupExpr
 
 %token <bytes> BYTES
 %token <str>      IDENTIFIER STRING NUMBER PARSER_FLAG
 %token <duration> DURATION RANGE
+%token <subqueryRange> SUBQUERY_RANGE
 %token <val>      MATCHERS LABELS EQ RE NRE NPA OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT PIPE_PATTERN
                   OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE RATE_COUNTER SUM SORT SORT_DESC AVG MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK
                   BYTES_OVER_TIME BYTES_RATE BOOL JSON REGEXP LOGFMT PIPE LINE_FMT LABEL_FMT UNWRAP AVG_OVER_TIME SUM_OVER_TIME MIN_OVER_TIME
                   MAX_OVER_TIME STDVAR_OVER_TIME STDDEV_OVER_TIME QUANTILE_OVER_TIME BYTES_CONV DURATION_CONV DURATION_SECONDS_CONV
                   FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
-                  DECOLORIZE DROP KEEP
+                  DECOLORIZE DROP KEEP XML CSV ABS CEIL FLOOR ROUND CLAMP_MIN CLAMP_MAX LN EXP SQRT TIMESTAMP HOUR DAY_OF_WEEK APPROX_TOPK APPROX_COUNT_DISTINCT AT START END STATS DISTINCT PERCENTILE AROUND LIMIT DEDUP LABEL_JOIN HISTOGRAM_QUANTILE
 
 // Operators are listed with increasing precedence.
+// LOWEST is only used with %prec on rules that must not be reduced while the
+// next token can still extend them, e.g. a comma after the labels of a parser
+// or the range of a selector in a subquery.
+%nonassoc LOWEST
 %left <binOp> OR
 %left <binOp> AND UNLESS
 %left <binOp> CMP_EQ NEQ LT LTE GT GTE
 %left <binOp> ADD SUB
 %left <binOp> MUL DIV MOD
 %right <binOp> POW
+%right COMMA CLOSE_PARENTHESIS
 
 %%
 
@@ -164,13 +189,16 @@ metricExpr:
     | binOpExpr                                     { $$ = $1 }
     | literalExpr                                   { $$ = $1 }
     | labelReplaceExpr                              { $$ = $1 }
+    | functionExpr                                  { $$ = $1 }
+    | labelJoinExpr                                 { $$ = $1 }
+    | histogramQuantileExpr                         { $$ = $1 }
     | vectorExpr                                    { $$ = $1 }
     | OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS { $$ = $2 }
     ;
 
 logExpr:
-      selector                                    { $$ = newMatcherExpr($1)}
-    | selector pipelineExpr                       { $$ = newPipelineExpr(newMatcherExpr($1), $2)}
+      selector %prec LOWEST                       { $$ = newMatcherExpr($1)}
+    | selector pipelineExpr %prec LOWEST          { $$ = newPipelineExpr(newMatcherExpr($1), $2)}
     | OPEN_PARENTHESIS logExpr CLOSE_PARENTHESIS  { $$ = $2 }
     ;
 
@@ -220,6 +248,10 @@ rangeAggregationExpr:
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS           { $$ = newRangeAggregationExpr($5, $1, nil, &$3) }
     | rangeOp OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS grouping               { $$ = newRangeAggregationExpr($3, $1, $5, nil) }
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS grouping  { $$ = newRangeAggregationExpr($5, $1, $7, &$3) }
+    | rangeOp OPEN_PARENTHESIS metricExpr SUBQUERY_RANGE CLOSE_PARENTHESIS                           { $$ = newSubqueryExpr($3, $1, $4, nil, nil) }
+    | rangeOp OPEN_PARENTHESIS metricExpr SUBQUERY_RANGE offsetExpr CLOSE_PARENTHESIS                { $$ = newSubqueryExpr($3, $1, $4, $5, nil) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA metricExpr SUBQUERY_RANGE CLOSE_PARENTHESIS              { $$ = newSubqueryExpr($5, $1, $6, nil, &$3) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA metricExpr SUBQUERY_RANGE offsetExpr CLOSE_PARENTHESIS   { $$ = newSubqueryExpr($5, $1, $6, $7, &$3) }
     ;
 
 vectorAggregationExpr:
@@ -231,6 +263,10 @@ vectorAggregationExpr:
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS                 { $$ = mustNewVectorAggregationExpr($5, $1, nil, &$3) }
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS grouping        { $$ = mustNewVectorAggregationExpr($5, $1, $7, &$3) }
     | vectorOp grouping OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS        { $$ = mustNewVectorAggregationExpr($6, $1, $2, &$4) }
+    // Aggregations over the values of a label.
+    | APPROX_COUNT_DISTINCT OPEN_PARENTHESIS IDENTIFIER COMMA metricExpr CLOSE_PARENTHESIS          { $$ = mustNewVectorAggregationExpr($5, OpTypeApproxCountDistinct, nil, &$3) }
+    | APPROX_COUNT_DISTINCT OPEN_PARENTHESIS IDENTIFIER COMMA metricExpr CLOSE_PARENTHESIS grouping { $$ = mustNewVectorAggregationExpr($5, OpTypeApproxCountDistinct, $7, &$3) }
+    | APPROX_COUNT_DISTINCT grouping OPEN_PARENTHESIS IDENTIFIER COMMA metricExpr CLOSE_PARENTHESIS { $$ = mustNewVectorAggregationExpr($6, OpTypeApproxCountDistinct, $2, &$4) }
     ;
 
 labelReplaceExpr:
@@ -238,6 +274,41 @@ labelReplaceExpr:
       { $$ = mustNewLabelReplaceExpr($3, $5, $7, $9, $11)}
     ;
 
+labelJoinExpr:
+      LABEL_JOIN OPEN_PARENTHESIS metricExpr COMMA STRING COMMA STRING CLOSE_PARENTHESIS                  { $$ = mustNewLabelJoinExpr($3, $5, $7, nil) }
+    | LABEL_JOIN OPEN_PARENTHESIS metricExpr COMMA STRING COMMA STRING COMMA stringList CLOSE_PARENTHESIS { $$ = mustNewLabelJoinExpr($3, $5, $7, $9) }
+    ;
+
+stringList:
+      STRING                    { $$ = []string{$1} }
+    | stringList COMMA STRING   { $$ = append($1, $3) }
+    ;
+
+histogramQuantileExpr:
+    HISTOGRAM_QUANTILE OPEN_PARENTHESIS literalExpr COMMA metricExpr CLOSE_PARENTHESIS { $$ = newHistogramQuantileExpr($5, $3) }
+    ;
+
+functionExpr:
+      functionOp OPEN_PARENTHESIS CLOSE_PARENTHESIS                               { $$ = newFunctionExpr($1, nil, nil) }
+    | functionOp OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS                    { $$ = newFunctionExpr($1, $3, nil) }
+    | functionOp OPEN_PARENTHESIS metricExpr COMMA literalExpr CLOSE_PARENTHESIS  { $$ = newFunctionExpr($1, $3, $5) }
+    ;
+
+functionOp:
+      ABS           { $$ = OpFuncAbs }
+    | CEIL          { $$ = OpFuncCeil }
+    | FLOOR         { $$ = OpFuncFloor }
+    | ROUND         { $$ = OpFuncRound }
+    | CLAMP_MIN     { $$ = OpFuncClampMin }
+    | CLAMP_MAX     { $$ = OpFuncClampMax }
+    | LN            { $$ = OpFuncLn }
+    | EXP           { $$ = OpFuncExp }
+    | SQRT          { $$ = OpFuncSqrt }
+    | TIMESTAMP     { $$ = OpFuncTimestamp }
+    | HOUR          { $$ = OpFuncHour }
+    | DAY_OF_WEEK   { $$ = OpFuncDayOfWeek }
+    ;
+
 filter:
       PIPE_MATCH                       { $$ = log.LineMatchRegexp }
     | PIPE_EXACT                       { $$ = log.LineMatchEqual }
@@ -276,12 +347,18 @@ pipelineStage:
   | PIPE labelParser             { $$ = $2 }
   | PIPE jsonExpressionParser    { $$ = $2 }
   | PIPE logfmtExpressionParser  { $$ = $2 }
+  | PIPE xmlExpressionParser     { $$ = $2 }
+  | PIPE csvExpressionParser     { $$ = $2 }
   | PIPE labelFilter             { $$ = &LabelFilterExpr{LabelFilterer: $2 }}
   | PIPE lineFormatExpr          { $$ = $2 }
   | PIPE decolorizeExpr          { $$ = $2 }
   | PIPE labelFormatExpr         { $$ = $2 }
   | PIPE dropLabelsExpr          { $$ = $2 }
   | PIPE keepLabelsExpr          { $$ = $2 }
+  | PIPE statsExpr               { $$ = $2 }
+  | PIPE aroundExpr              { $$ = $2 }
+  | PIPE limitByExpr             { $$ = $2 }
+  | PIPE dedupExpr               { $$ = $2 }
   ;
 
 filterOp:
@@ -321,14 +398,25 @@ labelParser:
   | REGEXP STRING       { $$ = newLabelParserExpr(OpParserTypeRegexp, $2) }
   | UNPACK              { $$ = newLabelParserExpr(OpParserTypeUnpack, "") }
   | PATTERN STRING      { $$ = newLabelParserExpr(OpParserTypePattern, $2) }
+  | XML                 { $$ = newLabelParserExpr(OpParserTypeXML, "") }
+  | CSV                 { $$ = newLabelParserExpr(OpParserTypeCSV, "") }
+  | CSV STRING          { $$ = newLabelParserExpr(OpParserTypeCSV, $2) }
   ;
 
 jsonExpressionParser:
-    JSON labelExtractionExpressionList { $$ = newJSONExpressionParser($2) }
+    JSON labelExtractionExpressionList %prec LOWEST { $$ = newJSONExpressionParser($2) }
 
 logfmtExpressionParser:
-    LOGFMT parserFlags labelExtractionExpressionList  { $$ = newLogfmtExpressionParser($3, $2)}
-  | LOGFMT labelExtractionExpressionList              { $$ = newLogfmtExpressionParser($2, nil)}
+    LOGFMT parserFlags labelExtractionExpressionList %prec LOWEST { $$ = newLogfmtExpressionParser($3, $2)}
+  | LOGFMT labelExtractionExpressionList %prec LOWEST             { $$ = newLogfmtExpressionParser($2, nil)}
+  ;
+
+xmlExpressionParser:
+    XML labelExtractionExpressionList %prec LOWEST { $$ = newXMLExpressionParser($2) }
+
+csvExpressionParser:
+    CSV labelExtractionExpressionList %prec LOWEST        { $$ = newCSVExpressionParser("", $2) }
+  | CSV STRING labelExtractionExpressionList %prec LOWEST { $$ = newCSVExpressionParser($2, $3) }
   ;
 
 lineFormatExpr: LINE_FMT STRING { $$ = newLineFmtExpr($2) };
@@ -357,7 +445,7 @@ labelFilter:
     | OPEN_PARENTHESIS labelFilter CLOSE_PARENTHESIS { $$ = $2 }
     | labelFilter labelFilter                        { $$ = log.NewAndLabelFilter($1, $2 ) }
     | labelFilter AND labelFilter                    { $$ = log.NewAndLabelFilter($1, $3 ) }
-    | labelFilter COMMA labelFilter                  { $$ = log.NewAndLabelFilter($1, $3 ) }
+    | labelFilter COMMA labelFilter %prec LOWEST     { $$ = log.NewAndLabelFilter($1, $3 ) }
     | labelFilter OR labelFilter                     { $$ = log.NewOrLabelFilter($1, $3 ) }
     ;
 
@@ -543,6 +631,7 @@ vectorOp:
       | STDVAR  { $$ = OpTypeStdvar }
       | BOTTOMK { $$ = OpTypeBottomK }
       | TOPK    { $$ = OpTypeTopK }
+      | APPROX_TOPK { $$ = OpTypeApproxTopK }
       | SORT    { $$ = OpTypeSort }
       | SORT_DESC    { $$ = OpTypeSortDesc }
       ;
@@ -566,7 +655,54 @@ rangeOp:
     ;
 
 offsetExpr:
-    OFFSET DURATION { $$ = newOffsetExpr( $2 ) }
+    OFFSET DURATION                 { $$ = newOffsetExpr( $2, nil ) }
+  | atModifier                      { $$ = $1 }
+  | atModifier OFFSET DURATION      { $$ = newOffsetExpr( $3, $1.At ) }
+  | OFFSET DURATION atModifier      { $$ = newOffsetExpr( $2, $3.At ) }
+  ;
+
+atModifier:
+    AT NUMBER                                       { $$ = newOffsetExpr( 0, mustNewAtModifier( $2 ) ) }
+  | AT START OPEN_PARENTHESIS CLOSE_PARENTHESIS     { $$ = newOffsetExpr( 0, &AtModifier{ StartOrEnd: OpStart } ) }
+  | AT END OPEN_PARENTHESIS CLOSE_PARENTHESIS       { $$ = newOffsetExpr( 0, &AtModifier{ StartOrEnd: OpEnd } ) }
+  ;
+
+statsExpr:
+      STATS statsAggregations %prec LOWEST                                   { $$ = newStatsExpr($2, nil) }
+    | STATS statsAggregations BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS   { $$ = newStatsExpr($2, $5) }
+    ;
+
+statsAggregations:
+      statsAggregation                             { $$ = []log.StatsAggregation{ $1 } }
+    | statsAggregations COMMA statsAggregation     { $$ = append($1, $3) }
+    ;
+
+statsAggregation:
+      COUNT OPEN_PARENTHESIS CLOSE_PARENTHESIS                                  { $$ = log.StatsAggregation{ Op: log.StatsCount } }
+    | COUNT OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS                       { $$ = log.StatsAggregation{ Op: log.StatsCount, Field: $3 } }
+    | SUM OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS                         { $$ = log.StatsAggregation{ Op: log.StatsSum, Field: $3 } }
+    | AVG OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS                         { $$ = log.StatsAggregation{ Op: log.StatsAvg, Field: $3 } }
+    | MIN OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS                         { $$ = log.StatsAggregation{ Op: log.StatsMin, Field: $3 } }
+    | MAX OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS                         { $$ = log.StatsAggregation{ Op: log.StatsMax, Field: $3 } }
+    | DISTINCT OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS                    { $$ = log.StatsAggregation{ Op: log.StatsDistinct, Field: $3 } }
+    | PERCENTILE OPEN_PARENTHESIS NUMBER COMMA IDENTIFIER CLOSE_PARENTHESIS     { $$ = mustNewPercentileAggregation($3, $5) }
+    ;
+
+aroundExpr:
+      AROUND NUMBER                                          { $$ = mustNewAroundExpr(OpAroundBefore, $2, OpAroundAfter, $2) }
+    | AROUND IDENTIFIER EQ NUMBER                            { $$ = mustNewAroundExpr($2, $4) }
+    | AROUND IDENTIFIER EQ NUMBER IDENTIFIER EQ NUMBER       { $$ = mustNewAroundExpr($2, $4, $5, $7) }
+    ;
+
+limitByExpr:
+      LIMIT NUMBER                                           { $$ = mustNewLimitByExpr($2, nil) }
+    | LIMIT NUMBER BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS { $$ = mustNewLimitByExpr($2, $5) }
+    ;
+
+dedupExpr:
+      DEDUP                                                  { $$ = newDedupExpr(nil) }
+    | DEDUP BY OPEN_PARENTHESIS labels CLOSE_PARENTHESIS     { $$ = newDedupExpr($4) }
+    ;
 
 labels:
       IDENTIFIER                 { $$ = []string{ $1 } }
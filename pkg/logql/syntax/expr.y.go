@@ -36,14 +36,15 @@ type exprSymType struct {
 	FilterOp              string
 	BinOpExpr             SampleExpr
 	LabelReplaceExpr      SampleExpr
+	FunctionExpr          SampleExpr
+	FunctionOp            string
 	binOp                 string
 	bytes                 uint64
 	str                   string
 	duration              time.Duration
+	subqueryRange         SubqueryRange
 	LiteralExpr           *LiteralExpr
 	BinOpModifier         *BinOpOptions
-	BoolModifier          *BinOpOptions
-	OnOrIgnoringModifier  *BinOpOptions
 	LabelParser           *LabelParserExpr
 	LogfmtParser          *LogfmtParserExpr
 	LineFilters           *LineFilterExpr
@@ -67,16 +68,20 @@ type exprSymType struct {
 	LabelExtractionExpressionList []log.LabelExtractionExpr
 	JSONExpressionParser          *JSONExpressionParser
 	LogfmtExpressionParser        *LogfmtExpressionParser
-
-	UnwrapExpr     *UnwrapExpr
-	DecolorizeExpr *DecolorizeExpr
-	OffsetExpr     *OffsetExpr
-	DropLabel      log.DropLabel
-	DropLabels     []log.DropLabel
-	DropLabelsExpr *DropLabelsExpr
-	KeepLabel      log.KeepLabel
-	KeepLabels     []log.KeepLabel
-	KeepLabelsExpr *KeepLabelsExpr
+	XMLExpressionParser           *XMLExpressionParser
+	CSVExpressionParser           *CSVExpressionParser
+
+	UnwrapExpr        *UnwrapExpr
+	DecolorizeExpr    *DecolorizeExpr
+	OffsetExpr        *OffsetExpr
+	DropLabel         log.DropLabel
+	DropLabels        []log.DropLabel
+	DropLabelsExpr    *DropLabelsExpr
+	KeepLabel         log.KeepLabel
+	KeepLabels        []log.KeepLabel
+	KeepLabelsExpr    *KeepLabelsExpr
+	StatsAggregation  log.StatsAggregation
+	StatsAggregations []log.StatsAggregation
 }
 
 const BYTES = 57346
@@ -86,90 +91,119 @@ const NUMBER = 57349
 const PARSER_FLAG = 57350
 const DURATION = 57351
 const RANGE = 57352
-const MATCHERS = 57353
-const LABELS = 57354
-const EQ = 57355
-const RE = 57356
-const NRE = 57357
-const NPA = 57358
-const OPEN_BRACE = 57359
-const CLOSE_BRACE = 57360
-const OPEN_BRACKET = 57361
-const CLOSE_BRACKET = 57362
-const COMMA = 57363
-const DOT = 57364
-const PIPE_MATCH = 57365
-const PIPE_EXACT = 57366
-const PIPE_PATTERN = 57367
-const OPEN_PARENTHESIS = 57368
-const CLOSE_PARENTHESIS = 57369
-const BY = 57370
-const WITHOUT = 57371
-const COUNT_OVER_TIME = 57372
-const RATE = 57373
-const RATE_COUNTER = 57374
-const SUM = 57375
-const SORT = 57376
-const SORT_DESC = 57377
-const AVG = 57378
-const MAX = 57379
-const MIN = 57380
-const COUNT = 57381
-const STDDEV = 57382
-const STDVAR = 57383
-const BOTTOMK = 57384
-const TOPK = 57385
-const BYTES_OVER_TIME = 57386
-const BYTES_RATE = 57387
-const BOOL = 57388
-const JSON = 57389
-const REGEXP = 57390
-const LOGFMT = 57391
-const PIPE = 57392
-const LINE_FMT = 57393
-const LABEL_FMT = 57394
-const UNWRAP = 57395
-const AVG_OVER_TIME = 57396
-const SUM_OVER_TIME = 57397
-const MIN_OVER_TIME = 57398
-const MAX_OVER_TIME = 57399
-const STDVAR_OVER_TIME = 57400
-const STDDEV_OVER_TIME = 57401
-const QUANTILE_OVER_TIME = 57402
-const BYTES_CONV = 57403
-const DURATION_CONV = 57404
-const DURATION_SECONDS_CONV = 57405
-const FIRST_OVER_TIME = 57406
-const LAST_OVER_TIME = 57407
-const ABSENT_OVER_TIME = 57408
-const VECTOR = 57409
-const LABEL_REPLACE = 57410
-const UNPACK = 57411
-const OFFSET = 57412
-const PATTERN = 57413
-const IP = 57414
-const ON = 57415
-const IGNORING = 57416
-const GROUP_LEFT = 57417
-const GROUP_RIGHT = 57418
-const DECOLORIZE = 57419
-const DROP = 57420
-const KEEP = 57421
-const OR = 57422
-const AND = 57423
-const UNLESS = 57424
-const CMP_EQ = 57425
-const NEQ = 57426
-const LT = 57427
-const LTE = 57428
-const GT = 57429
-const GTE = 57430
-const ADD = 57431
-const SUB = 57432
-const MUL = 57433
-const DIV = 57434
-const MOD = 57435
-const POW = 57436
+const SUBQUERY_RANGE = 57353
+const MATCHERS = 57354
+const LABELS = 57355
+const EQ = 57356
+const RE = 57357
+const NRE = 57358
+const NPA = 57359
+const OPEN_BRACE = 57360
+const CLOSE_BRACE = 57361
+const OPEN_BRACKET = 57362
+const CLOSE_BRACKET = 57363
+const COMMA = 57364
+const DOT = 57365
+const PIPE_MATCH = 57366
+const PIPE_EXACT = 57367
+const PIPE_PATTERN = 57368
+const OPEN_PARENTHESIS = 57369
+const CLOSE_PARENTHESIS = 57370
+const BY = 57371
+const WITHOUT = 57372
+const COUNT_OVER_TIME = 57373
+const RATE = 57374
+const RATE_COUNTER = 57375
+const SUM = 57376
+const SORT = 57377
+const SORT_DESC = 57378
+const AVG = 57379
+const MAX = 57380
+const MIN = 57381
+const COUNT = 57382
+const STDDEV = 57383
+const STDVAR = 57384
+const BOTTOMK = 57385
+const TOPK = 57386
+const BYTES_OVER_TIME = 57387
+const BYTES_RATE = 57388
+const BOOL = 57389
+const JSON = 57390
+const REGEXP = 57391
+const LOGFMT = 57392
+const PIPE = 57393
+const LINE_FMT = 57394
+const LABEL_FMT = 57395
+const UNWRAP = 57396
+const AVG_OVER_TIME = 57397
+const SUM_OVER_TIME = 57398
+const MIN_OVER_TIME = 57399
+const MAX_OVER_TIME = 57400
+const STDVAR_OVER_TIME = 57401
+const STDDEV_OVER_TIME = 57402
+const QUANTILE_OVER_TIME = 57403
+const BYTES_CONV = 57404
+const DURATION_CONV = 57405
+const DURATION_SECONDS_CONV = 57406
+const FIRST_OVER_TIME = 57407
+const LAST_OVER_TIME = 57408
+const ABSENT_OVER_TIME = 57409
+const VECTOR = 57410
+const LABEL_REPLACE = 57411
+const UNPACK = 57412
+const OFFSET = 57413
+const PATTERN = 57414
+const IP = 57415
+const ON = 57416
+const IGNORING = 57417
+const GROUP_LEFT = 57418
+const GROUP_RIGHT = 57419
+const DECOLORIZE = 57420
+const DROP = 57421
+const KEEP = 57422
+const XML = 57423
+const CSV = 57424
+const ABS = 57425
+const CEIL = 57426
+const FLOOR = 57427
+const ROUND = 57428
+const CLAMP_MIN = 57429
+const CLAMP_MAX = 57430
+const LN = 57431
+const EXP = 57432
+const SQRT = 57433
+const TIMESTAMP = 57434
+const HOUR = 57435
+const DAY_OF_WEEK = 57436
+const APPROX_TOPK = 57437
+const APPROX_COUNT_DISTINCT = 57438
+const AT = 57439
+const START = 57440
+const END = 57441
+const STATS = 57442
+const DISTINCT = 57443
+const PERCENTILE = 57444
+const AROUND = 57445
+const LIMIT = 57446
+const DEDUP = 57447
+const LABEL_JOIN = 57448
+const HISTOGRAM_QUANTILE = 57449
+const LOWEST = 57450
+const OR = 57451
+const AND = 57452
+const UNLESS = 57453
+const CMP_EQ = 57454
+const NEQ = 57455
+const LT = 57456
+const LTE = 57457
+const GT = 57458
+const GTE = 57459
+const ADD = 57460
+const SUB = 57461
+const MUL = 57462
+const DIV = 57463
+const MOD = 57464
+const POW = 57465
 
 var exprToknames = [...]string{
 	"$end",
@@ -182,6 +216,7 @@ var exprToknames = [...]string{
 	"PARSER_FLAG",
 	"DURATION",
 	"RANGE",
+	"SUBQUERY_RANGE",
 	"MATCHERS",
 	"LABELS",
 	"EQ",
@@ -251,6 +286,34 @@ var exprToknames = [...]string{
 	"DECOLORIZE",
 	"DROP",
 	"KEEP",
+	"XML",
+	"CSV",
+	"ABS",
+	"CEIL",
+	"FLOOR",
+	"ROUND",
+	"CLAMP_MIN",
+	"CLAMP_MAX",
+	"LN",
+	"EXP",
+	"SQRT",
+	"TIMESTAMP",
+	"HOUR",
+	"DAY_OF_WEEK",
+	"APPROX_TOPK",
+	"APPROX_COUNT_DISTINCT",
+	"AT",
+	"START",
+	"END",
+	"STATS",
+	"DISTINCT",
+	"PERCENTILE",
+	"AROUND",
+	"LIMIT",
+	"DEDUP",
+	"LABEL_JOIN",
+	"HISTOGRAM_QUANTILE",
+	"LOWEST",
 	"OR",
 	"AND",
 	"UNLESS",
@@ -274,7 +337,7 @@ const exprEofCode = 1
 const exprErrCode = 2
 const exprInitialStackSize = 16
 
-//line expr.y:582
+//line expr.y:718
 
 //line yacctab:1
 var exprExca = [...]int8{
@@ -285,260 +348,366 @@ var exprExca = [...]int8{
 
 const exprPrivate = 57344
 
-const exprLast = 639
+const exprLast = 1090
 
 var exprAct = [...]int16{
-	289, 228, 84, 4, 214, 64, 182, 126, 204, 189,
-	75, 200, 197, 63, 237, 5, 152, 187, 77, 2,
-	56, 80, 48, 49, 50, 57, 58, 61, 62, 59,
-	60, 51, 52, 53, 54, 55, 56, 283, 10, 49,
-	50, 57, 58, 61, 62, 59, 60, 51, 52, 53,
-	54, 55, 56, 57, 58, 61, 62, 59, 60, 51,
-	52, 53, 54, 55, 56, 53, 54, 55, 56, 109,
-	217, 139, 215, 115, 51, 52, 53, 54, 55, 56,
-	266, 140, 221, 16, 216, 265, 292, 156, 136, 166,
-	167, 297, 262, 161, 220, 16, 294, 261, 154, 148,
-	150, 151, 366, 281, 184, 339, 16, 67, 280, 130,
-	163, 207, 150, 151, 168, 169, 170, 171, 172, 173,
-	174, 175, 176, 177, 178, 179, 180, 181, 278, 164,
-	165, 16, 366, 277, 239, 94, 293, 194, 142, 386,
-	191, 275, 202, 206, 16, 294, 274, 142, 264, 340,
-	85, 86, 292, 141, 272, 219, 316, 16, 381, 271,
-	260, 306, 235, 185, 183, 17, 18, 356, 229, 224,
-	149, 231, 232, 110, 306, 240, 294, 17, 18, 136,
-	355, 213, 208, 211, 212, 209, 210, 374, 17, 18,
-	248, 249, 250, 269, 331, 184, 16, 306, 268, 373,
-	130, 255, 306, 354, 252, 342, 343, 344, 353, 136,
-	239, 72, 74, 17, 18, 371, 363, 339, 306, 69,
-	70, 71, 285, 384, 308, 184, 17, 18, 287, 290,
-	130, 296, 314, 299, 369, 109, 302, 115, 303, 17,
-	18, 291, 154, 288, 359, 300, 263, 267, 270, 273,
-	276, 279, 282, 349, 185, 183, 330, 294, 293, 310,
-	312, 315, 317, 318, 72, 74, 202, 206, 325, 320,
-	324, 295, 69, 70, 71, 346, 72, 74, 17, 18,
-	73, 306, 239, 239, 69, 70, 71, 307, 328, 136,
-	239, 332, 304, 334, 336, 224, 338, 109, 294, 230,
-	243, 337, 348, 333, 313, 311, 109, 239, 295, 350,
-	130, 230, 241, 72, 74, 83, 224, 85, 86, 292,
-	301, 69, 70, 71, 233, 347, 144, 13, 136, 238,
-	143, 327, 153, 73, 360, 361, 155, 326, 284, 109,
-	362, 225, 13, 247, 184, 73, 364, 365, 230, 130,
-	246, 155, 370, 245, 244, 218, 160, 159, 158, 227,
-	16, 90, 89, 82, 72, 74, 376, 380, 377, 378,
-	13, 352, 69, 70, 71, 253, 298, 305, 259, 6,
-	382, 258, 73, 21, 22, 23, 36, 45, 46, 37,
-	39, 40, 38, 41, 42, 43, 44, 24, 25, 230,
-	256, 242, 234, 226, 183, 257, 254, 26, 27, 28,
-	29, 30, 31, 32, 81, 379, 368, 33, 34, 35,
-	47, 19, 236, 227, 367, 162, 345, 79, 72, 74,
-	335, 190, 13, 73, 251, 88, 69, 70, 71, 322,
-	323, 6, 17, 18, 87, 21, 22, 23, 36, 45,
-	46, 37, 39, 40, 38, 41, 42, 43, 44, 24,
-	25, 190, 375, 230, 188, 385, 383, 372, 358, 26,
-	27, 28, 29, 30, 31, 32, 146, 357, 3, 33,
-	34, 35, 47, 19, 157, 76, 329, 319, 309, 286,
-	72, 74, 145, 321, 13, 147, 198, 73, 69, 70,
-	71, 223, 222, 6, 17, 18, 136, 21, 22, 23,
-	36, 45, 46, 37, 39, 40, 38, 41, 42, 43,
-	44, 24, 25, 221, 220, 230, 195, 130, 193, 192,
-	351, 26, 27, 28, 29, 30, 31, 32, 205, 136,
-	201, 33, 34, 35, 47, 19, 190, 81, 122, 123,
-	121, 198, 131, 133, 297, 72, 74, 127, 128, 73,
-	130, 113, 114, 69, 70, 71, 17, 18, 196, 118,
-	124, 203, 125, 120, 199, 91, 119, 117, 132, 134,
-	135, 122, 123, 121, 116, 131, 133, 186, 65, 137,
-	66, 129, 138, 111, 112, 93, 92, 11, 9, 20,
-	12, 15, 8, 124, 341, 125, 14, 7, 78, 68,
-	1, 132, 134, 135, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 73, 95, 96, 97, 98, 99,
-	100, 101, 102, 103, 104, 105, 106, 107, 108,
+	371, 373, 290, 104, 84, 298, 274, 159, 226, 255,
+	251, 244, 4, 247, 83, 231, 233, 76, 5, 95,
+	3, 97, 2, 108, 100, 363, 277, 96, 71, 72,
+	73, 74, 75, 76, 189, 73, 74, 75, 76, 176,
+	374, 10, 68, 69, 70, 77, 78, 81, 82, 79,
+	80, 71, 72, 73, 74, 75, 76, 69, 70, 77,
+	78, 81, 82, 79, 80, 71, 72, 73, 74, 75,
+	76, 77, 78, 81, 82, 79, 80, 71, 72, 73,
+	74, 75, 76, 267, 187, 188, 361, 439, 134, 20,
+	275, 360, 486, 372, 87, 142, 346, 438, 281, 20,
+	276, 345, 342, 382, 280, 20, 381, 341, 499, 185,
+	187, 188, 499, 536, 173, 191, 194, 358, 20, 374,
+	20, 192, 357, 177, 201, 203, 204, 370, 210, 211,
+	119, 228, 372, 338, 355, 372, 163, 20, 207, 354,
+	208, 209, 212, 213, 214, 215, 216, 217, 218, 219,
+	220, 221, 222, 223, 224, 225, 205, 179, 374, 352,
+	266, 374, 20, 526, 351, 344, 105, 106, 524, 235,
+	372, 340, 241, 238, 240, 249, 253, 173, 440, 441,
+	135, 273, 268, 271, 272, 269, 270, 329, 349, 178,
+	179, 20, 279, 348, 328, 515, 374, 533, 443, 163,
+	21, 22, 380, 532, 301, 514, 95, 288, 186, 296,
+	21, 22, 292, 513, 96, 512, 21, 22, 229, 227,
+	153, 154, 152, 173, 164, 166, 382, 523, 293, 21,
+	22, 21, 22, 522, 511, 391, 315, 316, 317, 381,
+	228, 510, 155, 381, 156, 163, 319, 419, 21, 22,
+	165, 167, 168, 157, 158, 322, 92, 94, 504, 92,
+	94, 284, 284, 173, 89, 90, 91, 89, 90, 91,
+	418, 284, 169, 21, 22, 170, 171, 172, 502, 107,
+	228, 105, 106, 443, 365, 163, 323, 434, 386, 367,
+	377, 376, 378, 134, 291, 385, 484, 285, 387, 369,
+	142, 496, 21, 22, 379, 192, 477, 383, 388, 343,
+	347, 350, 353, 356, 359, 362, 394, 400, 402, 405,
+	407, 368, 399, 300, 381, 476, 257, 391, 227, 258,
+	260, 259, 256, 507, 249, 253, 410, 408, 415, 417,
+	414, 391, 391, 449, 375, 428, 406, 481, 470, 397,
+	92, 94, 103, 93, 105, 106, 93, 289, 89, 90,
+	91, 475, 454, 92, 94, 474, 494, 229, 227, 380,
+	431, 89, 90, 91, 473, 384, 442, 391, 472, 466,
+	444, 447, 446, 469, 134, 291, 455, 457, 134, 391,
+	448, 391, 445, 261, 262, 468, 300, 467, 291, 375,
+	451, 452, 453, 459, 173, 92, 94, 300, 462, 300,
+	381, 300, 391, 89, 90, 91, 464, 391, 393, 404,
+	300, 228, 471, 392, 461, 307, 163, 173, 458, 436,
+	403, 306, 401, 480, 302, 433, 487, 389, 485, 488,
+	291, 310, 294, 299, 492, 181, 180, 93, 493, 163,
+	134, 491, 490, 430, 429, 20, 427, 497, 416, 498,
+	93, 364, 501, 339, 336, 503, 16, 335, 334, 333,
+	332, 331, 330, 314, 313, 6, 202, 312, 311, 28,
+	29, 30, 43, 53, 54, 44, 46, 47, 45, 48,
+	49, 50, 51, 31, 32, 278, 517, 200, 198, 197,
+	519, 520, 93, 33, 34, 35, 36, 37, 38, 39,
+	196, 115, 114, 40, 41, 42, 67, 23, 113, 527,
+	112, 111, 102, 531, 521, 478, 465, 463, 320, 395,
+	183, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 52, 19, 20, 289, 182, 390, 327,
+	184, 326, 92, 94, 25, 26, 16, 324, 509, 309,
+	89, 90, 91, 308, 305, 6, 21, 22, 303, 28,
+	29, 30, 43, 53, 54, 44, 46, 47, 45, 48,
+	49, 50, 51, 31, 32, 295, 286, 291, 337, 325,
+	321, 435, 287, 33, 34, 35, 36, 37, 38, 39,
+	101, 92, 94, 40, 41, 42, 67, 23, 518, 89,
+	90, 91, 500, 495, 99, 456, 489, 437, 264, 525,
+	263, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 52, 19, 20, 86, 234, 234, 516,
+	318, 232, 426, 425, 25, 26, 16, 412, 413, 93,
+	265, 234, 239, 508, 206, 193, 21, 22, 110, 28,
+	29, 30, 43, 53, 54, 44, 46, 47, 45, 48,
+	49, 50, 51, 31, 32, 109, 535, 534, 530, 528,
+	506, 505, 483, 33, 34, 35, 36, 37, 38, 39,
+	482, 432, 409, 40, 41, 42, 67, 23, 93, 411,
+	398, 396, 245, 151, 366, 283, 282, 281, 280, 242,
+	237, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 52, 19, 297, 236, 300, 479, 460,
+	424, 423, 422, 421, 25, 26, 16, 420, 252, 248,
+	234, 304, 101, 245, 199, 6, 21, 22, 150, 28,
+	29, 30, 43, 53, 54, 44, 46, 47, 45, 48,
+	49, 50, 51, 31, 32, 149, 254, 148, 160, 161,
+	141, 140, 138, 33, 34, 35, 36, 37, 38, 39,
+	139, 243, 145, 40, 41, 42, 67, 23, 250, 147,
+	246, 146, 144, 143, 230, 85, 174, 162, 175, 136,
+	137, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 52, 19, 195, 118, 117, 24, 529,
+	14, 13, 12, 11, 25, 26, 16, 9, 27, 15,
+	18, 8, 450, 17, 7, 6, 21, 22, 98, 28,
+	29, 30, 43, 53, 54, 44, 46, 47, 45, 48,
+	49, 50, 51, 31, 32, 88, 1, 0, 0, 0,
+	0, 0, 0, 33, 34, 35, 36, 37, 38, 39,
+	0, 0, 0, 40, 41, 42, 67, 23, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 52, 19, 190, 0, 0, 0, 0,
+	0, 0, 0, 0, 25, 26, 16, 0, 0, 0,
+	0, 0, 0, 0, 0, 193, 21, 22, 0, 28,
+	29, 30, 43, 53, 54, 44, 46, 47, 45, 48,
+	49, 50, 51, 31, 32, 0, 0, 0, 0, 0,
+	0, 0, 0, 33, 34, 35, 36, 37, 38, 39,
+	92, 94, 0, 40, 41, 42, 67, 23, 89, 90,
+	91, 0, 0, 0, 0, 173, 0, 0, 0, 0,
+	0, 55, 56, 57, 58, 59, 60, 61, 62, 63,
+	64, 65, 66, 52, 19, 291, 0, 163, 0, 0,
+	0, 0, 0, 0, 25, 26, 116, 0, 0, 0,
+	0, 0, 0, 0, 0, 372, 21, 22, 153, 154,
+	152, 0, 164, 166, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	155, 374, 156, 0, 0, 0, 0, 0, 165, 167,
+	168, 157, 158, 0, 0, 0, 0, 93, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	169, 0, 0, 170, 171, 172, 120, 121, 122, 123,
+	124, 125, 126, 127, 128, 129, 130, 131, 132, 133,
 }
 
 var exprPact = [...]int16{
-	353, -1000, -58, -1000, -1000, 540, 353, -1000, -1000, -1000,
-	-1000, -1000, -1000, 409, 337, 289, -1000, 437, 428, 336,
-	335, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	538, -1000, -67, -1000, -1000, 585, 538, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 595, 495, 325, 252,
+	-1000, 668, 651, 494, 493, 491, 485, 484, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 89, 89,
-	89, 89, 89, 89, 89, 89, 89, 89, 89, 89,
-	89, 89, 89, 540, -1000, 196, 534, -9, 75, -1000,
-	-1000, -1000, -1000, -1000, -1000, 303, 299, -58, 474, -1000,
-	-1000, 86, 325, 477, 332, 331, 330, -1000, -1000, 353,
-	418, 353, 56, 14, -1000, 353, 353, 353, 353, 353,
-	353, 353, 353, 353, 353, 353, 353, 353, 353, -1000,
-	-1000, -1000, -1000, -1000, -1000, 83, -1000, -1000, -1000, -1000,
-	-1000, 456, 541, 523, -1000, 522, -1000, -1000, -1000, -1000,
-	284, 520, -1000, 546, 535, 533, 98, -1000, -1000, 66,
-	-10, 329, -1000, -1000, -1000, -1000, -1000, 542, 518, 517,
-	496, 495, 314, 382, 413, 310, 297, 381, 415, 302,
-	285, 380, 273, -42, 328, 327, 324, 317, -30, -30,
-	-26, -26, -74, -74, -74, -74, -15, -15, -15, -15,
-	-15, -15, 83, 284, 284, 284, 426, 354, -1000, -1000,
-	393, 354, -1000, -1000, 174, -1000, 379, -1000, 392, 360,
-	-1000, 86, -1000, 357, -1000, 86, -1000, 88, 76, 189,
-	150, 137, 124, 99, -1000, -43, 312, 66, 483, -1000,
-	-1000, -1000, -1000, -1000, -1000, 122, 310, 249, 126, 261,
-	501, 349, 293, 122, 353, 265, 356, 260, -1000, -1000,
-	197, -1000, 482, -1000, 278, 277, 205, 129, 204, 83,
-	323, -1000, 354, 541, 481, -1000, 491, 434, 535, 533,
-	311, -1000, -1000, -1000, 305, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, 66, 480, -1000, 229, -1000, 167, 475,
-	46, 475, 421, 16, 284, 16, 95, 144, 416, 248,
-	298, -1000, -1000, 226, -1000, 353, 525, -1000, -1000, 350,
-	181, -1000, 176, -1000, -1000, 153, -1000, 140, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, 471, 462, -1000, 217,
-	-1000, 122, 46, 475, 46, -1000, -1000, 83, -1000, 16,
-	-1000, 190, -1000, -1000, -1000, 82, 414, 406, 207, 122,
-	188, -1000, 461, -1000, -1000, -1000, -1000, 172, 160, -1000,
-	-1000, 46, -1000, 457, 52, 46, 38, 16, 16, 405,
-	-1000, -1000, 346, -1000, -1000, 131, 46, -1000, -1000, 16,
-	460, -1000, -1000, 202, 459, 112, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 83, 83,
+	83, 83, 83, 83, 83, 83, 83, 83, 83, 83,
+	83, 83, 83, 585, -1000, 240, 970, -70, 117, -1000,
+	-1000, -1000, -1000, -1000, -1000, 418, 417, -67, 528, -1000,
+	-1000, 95, 898, 808, 483, 472, 471, 739, 470, -1000,
+	-1000, 538, 448, 538, 111, 647, 538, 66, 52, -1000,
+	538, 538, 538, 538, 538, 538, 538, 538, 538, 538,
+	538, 538, 538, 538, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, 109, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, 633, 735, 720, -1000, 704, 735, 646, -1000,
+	-1000, -1000, -1000, 422, 703, -1000, 738, 734, 733, 292,
+	613, 643, 131, 69, -1000, -1000, 84, -83, 468, -1000,
+	-1000, -1000, -1000, -1000, 737, 702, 701, 700, 699, 269,
+	564, 581, 536, 628, 414, 563, 718, 415, 406, 546,
+	736, 542, -1000, 403, 541, 537, 413, -53, 451, 450,
+	447, 446, -41, -41, -85, -85, -106, -106, -106, -106,
+	-90, -90, -90, -90, -90, -90, 109, 422, 422, 422,
+	632, 506, -1000, -1000, 576, 506, -1000, -1000, 506, 735,
+	506, 258, -1000, 535, -1000, 575, 529, -1000, 95, -1000,
+	527, -1000, 95, -1000, 165, -1000, 445, 444, 443, 442,
+	441, 440, 437, -1000, 574, 104, 436, 98, 92, 184,
+	155, 130, 113, 82, -1000, -84, 434, 84, 698, -1000,
+	-1000, -1000, -1000, -1000, -1000, 137, 628, 99, 389, 944,
+	192, 172, 347, 260, 137, 538, 409, 526, 395, -1000,
+	-1000, 390, -1000, 538, 507, 695, -1000, 111, 694, 538,
+	-1000, 404, 402, 391, 318, 399, 109, 218, -1000, 506,
+	735, 686, 506, -1000, 697, 642, 734, 733, 431, 292,
+	242, 732, 728, 727, 726, 725, 636, 635, 429, 722,
+	427, -1000, -1000, -1000, 426, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, 84, 685, -1000, 407, -1000, 259, 580,
+	-1000, 401, 608, 26, 80, 22, 188, 243, 55, 243,
+	22, 422, 338, 334, 605, 359, -1000, -1000, 400, -1000,
+	538, 724, -1000, -1000, 396, 538, 505, 388, 504, 351,
+	369, -1000, 367, -1000, -1000, 355, -1000, 320, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 722, -1000, -1000, 350,
+	346, 337, 333, 297, 278, 503, 723, 722, 319, 684,
+	676, -1000, 268, -1000, 137, 64, -1000, -57, 607, -1000,
+	425, 424, -1000, 22, 55, 243, 55, -1000, 109, -1000,
+	339, -1000, -1000, -1000, 603, 273, 61, 602, 137, 250,
+	-1000, 137, 230, 675, -1000, 674, -1000, -1000, -1000, -1000,
+	-1000, 305, -1000, -1000, -1000, -1000, -1000, -1000, 648, 544,
+	213, -1000, 206, 187, -1000, -1000, -1000, 185, -1000, -1000,
+	177, 167, -1000, 55, 634, 22, 598, 57, 55, 49,
+	22, -1000, -1000, -1000, -1000, 502, 205, -1000, 140, 612,
+	-1000, -1000, -1000, -1000, -1000, -1000, 135, -1000, 22, 55,
+	-1000, 673, -1000, 672, -1000, -1000, -1000, -1000, 501, 175,
+	-1000, 671, -1000, 670, 85, -1000, -1000,
 }
 
 var exprPgo = [...]int16{
-	0, 610, 18, 609, 2, 14, 478, 3, 16, 7,
-	608, 607, 606, 604, 15, 602, 601, 600, 599, 84,
-	598, 38, 597, 575, 596, 595, 594, 593, 13, 5,
-	592, 591, 589, 6, 588, 107, 4, 587, 584, 577,
-	576, 574, 11, 573, 571, 8, 569, 12, 568, 9,
-	17, 562, 561, 1, 558, 557, 0,
+	0, 856, 21, 855, 3, 5, 20, 12, 34, 7,
+	838, 834, 833, 832, 18, 831, 830, 829, 828, 100,
+	827, 41, 823, 822, 821, 820, 819, 818, 1006, 817,
+	816, 800, 799, 14, 4, 798, 797, 796, 8, 795,
+	94, 6, 794, 793, 792, 791, 790, 13, 789, 788,
+	10, 782, 11, 781, 16, 15, 780, 772, 771, 770,
+	2, 769, 768, 0, 1, 767, 9, 766, 765, 748,
+	703,
 }
 
 var exprR1 = [...]int8{
 	0, 1, 2, 2, 7, 7, 7, 7, 7, 7,
-	7, 6, 6, 6, 8, 8, 8, 8, 8, 8,
+	7, 7, 7, 7, 6, 6, 6, 8, 8, 8,
 	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
 	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
-	53, 53, 53, 13, 13, 13, 11, 11, 11, 11,
-	15, 15, 15, 15, 15, 15, 22, 3, 3, 3,
+	8, 8, 8, 60, 60, 60, 13, 13, 13, 11,
+	11, 11, 11, 11, 11, 11, 11, 15, 15, 15,
+	15, 15, 15, 15, 15, 15, 22, 24, 24, 26,
+	26, 25, 23, 23, 23, 27, 27, 27, 27, 27,
+	27, 27, 27, 27, 27, 27, 27, 3, 3, 3,
 	3, 3, 3, 14, 14, 14, 10, 10, 9, 9,
-	9, 9, 28, 28, 29, 29, 29, 29, 29, 29,
-	29, 29, 29, 29, 29, 19, 36, 36, 36, 35,
-	35, 35, 34, 34, 34, 37, 37, 27, 27, 26,
-	26, 26, 26, 52, 51, 51, 38, 39, 47, 47,
-	48, 48, 48, 46, 33, 33, 33, 33, 33, 33,
-	33, 33, 33, 49, 49, 50, 50, 55, 55, 54,
-	54, 32, 32, 32, 32, 32, 32, 32, 30, 30,
-	30, 30, 30, 30, 30, 31, 31, 31, 31, 31,
-	31, 31, 42, 42, 41, 41, 40, 45, 45, 44,
-	44, 43, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 20, 20, 20, 20, 20, 24, 24, 25,
-	25, 25, 25, 23, 23, 23, 23, 23, 23, 23,
-	23, 21, 21, 21, 17, 18, 16, 16, 16, 16,
-	16, 16, 16, 16, 16, 16, 16, 12, 12, 12,
+	9, 9, 33, 33, 34, 34, 34, 34, 34, 34,
+	34, 34, 34, 34, 34, 34, 34, 34, 34, 34,
+	34, 19, 41, 41, 41, 40, 40, 40, 39, 39,
+	39, 42, 42, 32, 32, 31, 31, 31, 31, 31,
+	31, 31, 57, 56, 56, 58, 59, 59, 43, 44,
+	52, 52, 53, 53, 53, 51, 38, 38, 38, 38,
+	38, 38, 38, 38, 38, 54, 54, 55, 55, 62,
+	62, 61, 61, 37, 37, 37, 37, 37, 37, 37,
+	35, 35, 35, 35, 35, 35, 35, 36, 36, 36,
+	36, 36, 36, 36, 47, 47, 46, 46, 45, 50,
+	50, 49, 49, 48, 20, 20, 20, 20, 20, 20,
+	20, 20, 20, 20, 20, 20, 20, 20, 20, 29,
+	29, 30, 30, 30, 30, 28, 28, 28, 28, 28,
+	28, 28, 28, 21, 21, 21, 17, 18, 16, 16,
+	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
 	12, 12, 12, 12, 12, 12, 12, 12, 12, 12,
-	12, 12, 56, 5, 5, 4, 4, 4, 4,
+	12, 12, 12, 12, 12, 63, 63, 63, 63, 64,
+	64, 64, 65, 65, 67, 67, 66, 66, 66, 66,
+	66, 66, 66, 66, 68, 68, 68, 69, 69, 70,
+	70, 5, 5, 4, 4, 4, 4,
 }
 
 var exprR2 = [...]int8{
 	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	3, 1, 2, 3, 2, 3, 4, 5, 3, 4,
-	5, 6, 3, 4, 5, 6, 3, 4, 5, 6,
-	4, 5, 6, 7, 3, 4, 4, 5, 3, 2,
-	3, 6, 3, 1, 1, 1, 4, 6, 5, 7,
-	4, 5, 5, 6, 7, 7, 12, 1, 1, 1,
+	1, 1, 1, 3, 1, 2, 3, 2, 3, 4,
+	5, 3, 4, 5, 6, 3, 4, 5, 6, 3,
+	4, 5, 6, 4, 5, 6, 7, 3, 4, 4,
+	5, 3, 2, 3, 6, 3, 1, 1, 1, 4,
+	6, 5, 7, 5, 6, 7, 8, 4, 5, 5,
+	6, 7, 7, 6, 7, 7, 12, 8, 10, 1,
+	3, 6, 3, 4, 6, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 3, 3, 2, 1, 3, 3, 3,
 	3, 3, 1, 2, 1, 2, 2, 2, 2, 2,
-	2, 2, 2, 2, 2, 1, 1, 4, 3, 2,
-	5, 4, 1, 3, 2, 1, 2, 1, 2, 1,
-	2, 1, 2, 2, 3, 2, 2, 1, 3, 3,
-	1, 3, 3, 2, 1, 1, 1, 1, 3, 2,
-	3, 3, 3, 3, 1, 1, 3, 6, 6, 1,
-	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+	2, 1, 1, 4, 3, 2, 5, 4, 1, 3,
+	2, 1, 2, 1, 2, 1, 2, 1, 2, 1,
+	1, 2, 2, 3, 2, 2, 2, 3, 2, 1,
+	3, 3, 1, 3, 3, 2, 1, 1, 1, 1,
+	3, 2, 3, 3, 3, 3, 1, 1, 3, 6,
+	6, 1, 1, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 1, 1, 1, 3, 2, 1, 1, 1,
-	3, 2, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 4, 4, 4, 4, 0, 1, 5,
-	4, 5, 4, 1, 1, 2, 4, 5, 2, 4,
-	5, 1, 2, 2, 4, 1, 1, 1, 1, 1,
+	3, 3, 3, 3, 1, 1, 1, 3, 2, 1,
+	1, 1, 3, 2, 4, 4, 4, 4, 4, 4,
+	4, 4, 4, 4, 4, 4, 4, 4, 4, 0,
+	1, 5, 4, 5, 4, 1, 1, 2, 4, 5,
+	2, 4, 5, 1, 2, 2, 4, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 2, 1, 3, 4, 4, 3, 3,
+	1, 1, 1, 1, 1, 2, 1, 3, 3, 2,
+	4, 4, 2, 6, 1, 3, 3, 4, 4, 4,
+	4, 4, 4, 6, 2, 4, 7, 2, 6, 1,
+	5, 1, 3, 4, 4, 3, 3,
 }
 
 var exprChk = [...]int16{
-	-1000, -1, -2, -6, -7, -14, 26, -11, -15, -20,
-	-21, -22, -17, 17, -12, -16, 7, 89, 90, 68,
-	-18, 30, 31, 32, 44, 45, 54, 55, 56, 57,
-	58, 59, 60, 64, 65, 66, 33, 36, 39, 37,
-	38, 40, 41, 42, 43, 34, 35, 67, 80, 81,
-	82, 89, 90, 91, 92, 93, 94, 83, 84, 87,
-	88, 85, 86, -28, -29, -34, 50, -35, -3, 23,
-	24, 25, 15, 84, 16, -7, -6, -2, -10, 18,
-	-9, 5, 26, 26, -4, 28, 29, 7, 7, 26,
-	26, -23, -24, -25, 46, -23, -23, -23, -23, -23,
-	-23, -23, -23, -23, -23, -23, -23, -23, -23, -29,
-	-35, -27, -26, -52, -51, -33, -38, -39, -46, -40,
-	-43, 49, 47, 48, 69, 71, -9, -55, -54, -31,
-	26, 51, 77, 52, 78, 79, 5, -32, -30, 80,
-	6, -19, 72, 27, 27, 18, 2, 21, 13, 84,
-	14, 15, -8, 7, -14, 26, -7, 7, 26, 26,
-	26, -7, 7, -2, 73, 74, 75, 76, -2, -2,
-	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-2, -2, -33, 81, 21, 80, -37, -50, 8, -49,
-	5, -50, 6, 6, -33, 6, -48, -47, 5, -41,
-	-42, 5, -9, -44, -45, 5, -9, 13, 84, 87,
-	88, 85, 86, 83, -36, 6, -19, 80, 26, -9,
-	6, 6, 6, 6, 2, 27, 21, 10, -53, -28,
-	50, -14, -8, 27, 21, -7, 7, -5, 27, 5,
-	-5, 27, 21, 27, 26, 26, 26, 26, -33, -33,
-	-33, 8, -50, 21, 13, 27, 21, 13, 21, 21,
-	72, 9, 4, -21, 72, 9, 4, -21, 9, 4,
+	-1000, -1, -2, -6, -7, -14, 27, -11, -15, -20,
+	-21, -22, -23, -24, -25, -17, 18, -12, -16, 96,
+	7, 118, 119, 69, -27, 106, 107, -18, 31, 32,
+	33, 45, 46, 55, 56, 57, 58, 59, 60, 61,
+	65, 66, 67, 34, 37, 40, 38, 39, 41, 42,
+	43, 44, 95, 35, 36, 83, 84, 85, 86, 87,
+	88, 89, 90, 91, 92, 93, 94, 68, 109, 110,
+	111, 118, 119, 120, 121, 122, 123, 112, 113, 116,
+	117, 114, 115, -33, -34, -39, 51, -40, -3, 24,
+	25, 26, 16, 113, 17, -7, -6, -2, -10, 19,
+	-9, 5, 27, 27, -4, 29, 30, 27, -4, 7,
+	7, 27, 27, 27, 27, 27, -28, -29, -30, 47,
+	-28, -28, -28, -28, -28, -28, -28, -28, -28, -28,
+	-28, -28, -28, -28, -34, -40, -32, -31, -57, -56,
+	-58, -59, -38, -43, -44, -51, -45, -48, -65, -68,
+	-69, -70, 50, 48, 49, 70, 72, 81, 82, -9,
+	-62, -61, -36, 27, 52, 78, 53, 79, 80, 100,
+	103, 104, 105, 5, -37, -35, 109, 6, -19, 73,
+	28, 28, 19, 2, 22, 14, 113, 15, 16, -8,
+	7, -7, -14, 27, -7, 7, 27, 27, 27, 5,
+	27, -7, 28, -7, -7, -21, 7, -2, 74, 75,
+	76, 77, -2, -2, -2, -2, -2, -2, -2, -2,
+	-2, -2, -2, -2, -2, -2, -38, 110, 22, 109,
+	-42, -55, 8, -54, 5, -55, 6, 6, -55, 6,
+	-55, -38, 6, -53, -52, 5, -46, -47, 5, -9,
+	-49, -50, 5, -9, -67, -66, 40, 34, 37, 39,
+	38, 101, 102, 7, 5, 7, 29, 14, 113, 116,
+	117, 114, 115, 112, -41, 6, -19, 109, 27, -9,
+	6, 6, 6, 6, 2, 28, 22, 11, -33, 10,
+	-60, 51, -14, -8, 28, 22, -7, 7, -5, 28,
+	5, -5, 28, 22, 5, 22, 28, 22, 22, 22,
+	28, 27, 27, 27, 27, -38, -38, -38, 8, -55,
+	22, 14, -55, 28, 22, 14, 22, 22, 29, 22,
+	27, 27, 27, 27, 27, 27, 27, 14, 29, 27,
+	73, 9, 4, -21, 73, 9, 4, -21, 9, 4,
 	-21, 9, 4, -21, 9, 4, -21, 9, 4, -21,
-	9, 4, -21, 80, 26, -36, 6, -4, -8, -56,
-	-53, -28, 70, 10, 50, 10, -53, 53, 27, -53,
-	-28, 27, -4, -7, 27, 21, 21, 27, 27, 6,
-	-5, 27, -5, 27, 27, -5, 27, -5, -49, 6,
-	-47, 2, 5, 6, -42, -45, 26, 26, -36, 6,
-	27, 27, -53, -28, -53, 9, -56, -33, -56, 10,
-	5, -13, 61, 62, 63, 10, 27, 27, -53, 27,
-	-7, 5, 21, 27, 27, 27, 27, 6, 6, 27,
-	-4, -53, -56, 26, -56, -53, 50, 10, 10, 27,
-	-4, 27, 6, 27, 27, 5, -53, -56, -56, 10,
-	21, 27, -56, 6, 21, 6, 27,
+	9, 4, -21, 109, 27, -41, 6, -4, -8, -7,
+	28, -63, 71, -64, 97, 10, -60, -63, -60, -33,
+	10, 51, 54, -33, 28, -60, 28, -4, -7, 28,
+	22, 22, 28, 28, -7, 22, 6, -21, 6, -7,
+	-5, 28, -5, 28, 28, -5, 28, -5, -54, 6,
+	-52, 2, 5, 6, -47, -50, 27, -66, 28, 5,
+	5, 5, 5, 5, 5, 7, 7, 27, -5, 27,
+	27, -41, 6, 28, 28, 11, 28, 9, 71, 7,
+	98, 99, -63, 10, -60, -33, -60, -63, -38, 5,
+	-13, 62, 63, 64, 28, -60, 10, 28, 28, -7,
+	5, 28, -7, 22, 28, 22, 28, 28, 28, 28,
+	28, -5, 28, 28, 28, 28, 28, 28, 22, 5,
+	-5, 28, 6, 6, 28, -4, 28, -63, -64, 9,
+	27, 27, -63, -60, 27, 10, 28, -63, -60, 51,
+	10, -4, 28, -4, 28, 6, 6, 28, 5, 14,
+	28, 28, 28, 28, 28, 28, 5, -63, 10, -60,
+	-63, 22, 28, 22, 28, 7, 28, -63, 6, -26,
+	6, 22, 28, 22, 6, 6, 28,
 }
 
 var exprDef = [...]int16{
-	0, -2, 1, 2, 3, 11, 0, 4, 5, 6,
-	7, 8, 9, 0, 0, 0, 191, 0, 0, 0,
-	0, 207, 208, 209, 210, 211, 212, 213, 214, 215,
-	216, 217, 218, 219, 220, 221, 196, 197, 198, 199,
-	200, 201, 202, 203, 204, 205, 206, 195, 177, 177,
-	177, 177, 177, 177, 177, 177, 177, 177, 177, 177,
-	177, 177, 177, 12, 72, 74, 0, 92, 0, 57,
-	58, 59, 60, 61, 62, 3, 2, 0, 0, 65,
-	66, 0, 0, 0, 0, 0, 0, 192, 193, 0,
-	0, 0, 183, 184, 178, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 73,
-	94, 75, 76, 77, 78, 79, 80, 81, 82, 83,
-	84, 97, 99, 0, 101, 0, 114, 115, 116, 117,
-	0, 0, 107, 0, 0, 0, 0, 129, 130, 0,
-	89, 0, 85, 10, 13, 63, 64, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 3, 191, 0, 0,
-	0, 3, 0, 162, 0, 0, 185, 188, 163, 164,
-	165, 166, 167, 168, 169, 170, 171, 172, 173, 174,
-	175, 176, 119, 0, 0, 0, 98, 105, 95, 125,
-	124, 103, 100, 102, 0, 106, 113, 110, 0, 156,
-	154, 152, 153, 161, 159, 157, 158, 0, 0, 0,
-	0, 0, 0, 0, 93, 86, 0, 0, 0, 67,
-	68, 69, 70, 71, 39, 46, 0, 14, 0, 0,
-	0, 0, 0, 50, 0, 3, 191, 0, 227, 223,
-	0, 228, 0, 194, 0, 0, 0, 0, 120, 121,
-	122, 96, 104, 0, 0, 118, 0, 0, 0, 0,
-	0, 136, 143, 150, 0, 135, 142, 149, 131, 138,
-	145, 132, 139, 146, 133, 140, 147, 134, 141, 148,
-	137, 144, 151, 0, 0, 91, 0, 48, 0, 15,
-	18, 34, 0, 22, 0, 26, 0, 0, 0, 0,
-	0, 38, 52, 3, 51, 0, 0, 225, 226, 0,
-	0, 180, 0, 182, 186, 0, 189, 0, 126, 123,
-	111, 112, 108, 109, 155, 160, 0, 0, 88, 0,
-	90, 47, 19, 35, 36, 222, 23, 42, 27, 30,
-	40, 0, 43, 44, 45, 16, 0, 0, 0, 53,
-	3, 224, 0, 179, 181, 187, 190, 0, 0, 87,
-	49, 37, 31, 0, 17, 20, 0, 24, 28, 0,
-	54, 55, 0, 127, 128, 0, 21, 25, 29, 32,
-	0, 41, 33, 0, 0, 0, 56,
+	0, -2, 1, 2, 3, 14, 0, 4, 5, 6,
+	7, 8, 9, 10, 11, 12, 0, 0, 0, 0,
+	233, 0, 0, 0, 0, 0, 0, 0, 250, 251,
+	252, 253, 254, 255, 256, 257, 258, 259, 260, 261,
+	262, 263, 264, 238, 239, 240, 241, 242, 243, 244,
+	245, 246, 247, 248, 249, 75, 76, 77, 78, 79,
+	80, 81, 82, 83, 84, 85, 86, 237, 219, 219,
+	219, 219, 219, 219, 219, 219, 219, 219, 219, 219,
+	219, 219, 219, 15, 102, 104, 0, 128, 0, 87,
+	88, 89, 90, 91, 92, 3, 2, 0, 0, 95,
+	96, 0, 0, 0, 0, 0, 0, 0, 0, 234,
+	235, 0, 0, 0, 0, 0, 0, 225, 226, 220,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 103, 130, 105, 106, 107, 108,
+	109, 110, 111, 112, 113, 114, 115, 116, 117, 118,
+	119, 120, 133, 135, 0, 137, 0, 139, 140, 156,
+	157, 158, 159, 0, 0, 149, 0, 0, 0, 0,
+	0, 0, 289, 0, 171, 172, 0, 125, 0, 121,
+	13, 16, 93, 94, 0, 0, 0, 0, 0, 0,
+	233, 3, 14, 0, 3, 233, 0, 0, 0, 0,
+	0, 3, 72, 3, 3, 0, 0, 204, 0, 0,
+	227, 230, 205, 206, 207, 208, 209, 210, 211, 212,
+	213, 214, 215, 216, 217, 218, 161, 0, 0, 0,
+	134, 144, 131, 167, 166, 142, 136, 138, 145, 141,
+	146, 0, 148, 155, 152, 0, 198, 196, 194, 195,
+	203, 201, 199, 200, 272, 274, 0, 0, 0, 0,
+	0, 0, 0, 284, 0, 287, 0, 0, 0, 0,
+	0, 0, 0, 0, 129, 122, 0, 0, 0, 97,
+	98, 99, 100, 101, 42, 49, 0, 0, 15, 17,
+	0, 0, 14, 0, 57, 0, 3, 233, 0, 295,
+	291, 0, 296, 0, 0, 0, 73, 0, 0, 0,
+	236, 0, 0, 0, 0, 162, 163, 164, 132, 143,
+	0, 0, 147, 160, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 178, 185, 192, 0, 177, 184, 191, 173, 180,
+	187, 174, 181, 188, 175, 182, 189, 176, 183, 190,
+	179, 186, 193, 0, 0, 127, 0, 51, 0, 3,
+	53, 0, 0, 266, 0, 29, 0, 18, 21, 37,
+	25, 0, 0, 15, 0, 0, 41, 59, 3, 58,
+	0, 0, 293, 294, 3, 0, 0, 0, 0, 3,
+	0, 222, 0, 224, 228, 0, 231, 0, 168, 165,
+	153, 154, 150, 151, 197, 202, 0, 275, 276, 0,
+	0, 0, 0, 0, 0, 0, 285, 0, 0, 0,
+	0, 124, 0, 126, 50, 0, 54, 265, 0, 269,
+	0, 0, 30, 33, 22, 38, 39, 26, 45, 43,
+	0, 46, 47, 48, 0, 0, 19, 0, 60, 3,
+	292, 63, 3, 0, 74, 0, 71, 221, 223, 229,
+	232, 0, 277, 278, 279, 280, 281, 282, 0, 0,
+	0, 290, 0, 0, 123, 52, 55, 0, 268, 267,
+	0, 0, 34, 40, 0, 31, 0, 20, 23, 0,
+	27, 61, 62, 64, 65, 0, 0, 273, 0, 0,
+	288, 169, 170, 56, 270, 271, 0, 32, 35, 24,
+	28, 0, 67, 0, 283, 286, 44, 36, 0, 0,
+	69, 0, 68, 0, 0, 70, 66,
 }
 
 var exprTok1 = [...]int8{
@@ -555,7 +724,10 @@ var exprTok2 = [...]int8{
 	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
-	92, 93, 94,
+	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
+	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
+	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
+	122, 123,
 }
 
 var exprTok3 = [...]int8{
@@ -901,1368 +1073,1776 @@ exprdefault:
 
 	case 1:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:154
+//line expr.y:179
 		{
 			exprlex.(*parser).expr = exprDollar[1].Expr
 		}
 	case 2:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:157
+//line expr.y:182
 		{
 			exprVAL.Expr = exprDollar[1].LogExpr
 		}
 	case 3:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:158
+//line expr.y:183
 		{
 			exprVAL.Expr = exprDollar[1].MetricExpr
 		}
 	case 4:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:162
+//line expr.y:187
 		{
 			exprVAL.MetricExpr = exprDollar[1].RangeAggregationExpr
 		}
 	case 5:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:163
+//line expr.y:188
 		{
 			exprVAL.MetricExpr = exprDollar[1].VectorAggregationExpr
 		}
 	case 6:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:164
+//line expr.y:189
 		{
 			exprVAL.MetricExpr = exprDollar[1].BinOpExpr
 		}
 	case 7:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:165
+//line expr.y:190
 		{
 			exprVAL.MetricExpr = exprDollar[1].LiteralExpr
 		}
 	case 8:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:166
+//line expr.y:191
 		{
 			exprVAL.MetricExpr = exprDollar[1].LabelReplaceExpr
 		}
 	case 9:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:167
+//line expr.y:192
 		{
-			exprVAL.MetricExpr = exprDollar[1].VectorExpr
+			exprVAL.MetricExpr = exprDollar[1].FunctionExpr
 		}
 	case 10:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:193
+		{
+			exprVAL.MetricExpr = exprDollar[1].FunctionExpr
+		}
+	case 11:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:194
+		{
+			exprVAL.MetricExpr = exprDollar[1].FunctionExpr
+		}
+	case 12:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:195
+		{
+			exprVAL.MetricExpr = exprDollar[1].VectorExpr
+		}
+	case 13:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:168
+//line expr.y:196
 		{
 			exprVAL.MetricExpr = exprDollar[2].MetricExpr
 		}
-	case 11:
+	case 14:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:172
+//line expr.y:200
 		{
 			exprVAL.LogExpr = newMatcherExpr(exprDollar[1].Selector)
 		}
-	case 12:
+	case 15:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:173
+//line expr.y:201
 		{
 			exprVAL.LogExpr = newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr)
 		}
-	case 13:
+	case 16:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:174
+//line expr.y:202
 		{
 			exprVAL.LogExpr = exprDollar[2].LogExpr
 		}
-	case 14:
+	case 17:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:178
+//line expr.y:206
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, nil)
 		}
-	case 15:
+	case 18:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:179
+//line expr.y:207
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 16:
+	case 19:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:180
+//line expr.y:208
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, nil)
 		}
-	case 17:
+	case 20:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:181
+//line expr.y:209
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, exprDollar[5].OffsetExpr)
 		}
-	case 18:
+	case 21:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:182
+//line expr.y:210
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 19:
+	case 22:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:183
+//line expr.y:211
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[4].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 20:
+	case 23:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:184
+//line expr.y:212
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[5].UnwrapExpr, nil)
 		}
-	case 21:
+	case 24:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:185
+//line expr.y:213
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[6].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 22:
+	case 25:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:186
+//line expr.y:214
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, nil)
 		}
-	case 23:
+	case 26:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:187
+//line expr.y:215
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, exprDollar[4].OffsetExpr)
 		}
-	case 24:
+	case 27:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:188
+//line expr.y:216
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 25:
+	case 28:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:189
+//line expr.y:217
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, exprDollar[6].OffsetExpr)
 		}
-	case 26:
+	case 29:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:190
+//line expr.y:218
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, nil)
 		}
-	case 27:
+	case 30:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:191
+//line expr.y:219
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, exprDollar[4].OffsetExpr)
 		}
-	case 28:
+	case 31:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:192
+//line expr.y:220
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, nil)
 		}
-	case 29:
+	case 32:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:193
+//line expr.y:221
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, exprDollar[6].OffsetExpr)
 		}
-	case 30:
+	case 33:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:194
+//line expr.y:222
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, nil)
 		}
-	case 31:
+	case 34:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:195
+//line expr.y:223
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
-	case 32:
+	case 35:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:196
+//line expr.y:224
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 33:
+	case 36:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:197
+//line expr.y:225
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, exprDollar[7].OffsetExpr)
 		}
-	case 34:
+	case 37:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:198
+//line expr.y:226
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, nil, nil)
 		}
-	case 35:
+	case 38:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:199
+//line expr.y:227
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
-	case 36:
+	case 39:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:200
+//line expr.y:228
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, exprDollar[4].UnwrapExpr, nil)
 		}
-	case 37:
+	case 40:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:201
+//line expr.y:229
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, exprDollar[5].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
-	case 38:
+	case 41:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:202
+//line expr.y:230
 		{
 			exprVAL.LogRangeExpr = exprDollar[2].LogRangeExpr
 		}
-	case 40:
+	case 43:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:207
+//line expr.y:235
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[3].str, "")
 		}
-	case 41:
+	case 44:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:208
+//line expr.y:236
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[5].str, exprDollar[3].ConvOp)
 		}
-	case 42:
+	case 45:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:209
+//line expr.y:237
 		{
 			exprVAL.UnwrapExpr = exprDollar[1].UnwrapExpr.addPostFilter(exprDollar[3].LabelFilter)
 		}
-	case 43:
+	case 46:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:213
+//line expr.y:241
 		{
 			exprVAL.ConvOp = OpConvBytes
 		}
-	case 44:
+	case 47:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:214
+//line expr.y:242
 		{
 			exprVAL.ConvOp = OpConvDuration
 		}
-	case 45:
+	case 48:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:215
+//line expr.y:243
 		{
 			exprVAL.ConvOp = OpConvDurationSeconds
 		}
-	case 46:
+	case 49:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:219
+//line expr.y:247
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, nil, nil)
 		}
-	case 47:
+	case 50:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:220
+//line expr.y:248
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, nil, &exprDollar[3].str)
 		}
-	case 48:
+	case 51:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:221
+//line expr.y:249
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[5].Grouping, nil)
 		}
-	case 49:
+	case 52:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:222
+//line expr.y:250
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 50:
-		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:227
-		{
-			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
-		}
-	case 51:
-		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:228
-		{
-			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[4].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, nil)
-		}
-	case 52:
-		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:229
-		{
-			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, exprDollar[5].Grouping, nil)
-		}
 	case 53:
-		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:231
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:251
 		{
-			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, nil, &exprDollar[3].str)
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[3].MetricExpr, exprDollar[1].RangeOp, exprDollar[4].subqueryRange, nil, nil)
 		}
 	case 54:
-		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:232
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:252
 		{
-			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, exprDollar[7].Grouping, &exprDollar[3].str)
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[3].MetricExpr, exprDollar[1].RangeOp, exprDollar[4].subqueryRange, exprDollar[5].OffsetExpr, nil)
 		}
 	case 55:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
-//line expr.y:233
+//line expr.y:253
 		{
-			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, &exprDollar[4].str)
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[5].MetricExpr, exprDollar[1].RangeOp, exprDollar[6].subqueryRange, nil, &exprDollar[3].str)
 		}
 	case 56:
-		exprDollar = exprS[exprpt-12 : exprpt+1]
-//line expr.y:238
+		exprDollar = exprS[exprpt-8 : exprpt+1]
+//line expr.y:254
 		{
-			exprVAL.LabelReplaceExpr = mustNewLabelReplaceExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].str, exprDollar[11].str)
+			exprVAL.RangeAggregationExpr = newSubqueryExpr(exprDollar[5].MetricExpr, exprDollar[1].RangeOp, exprDollar[6].subqueryRange, exprDollar[7].OffsetExpr, &exprDollar[3].str)
 		}
 	case 57:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:242
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:259
 		{
-			exprVAL.Filter = log.LineMatchRegexp
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
 		}
 	case 58:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:243
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:260
 		{
-			exprVAL.Filter = log.LineMatchEqual
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[4].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, nil)
 		}
 	case 59:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:244
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:261
 		{
-			exprVAL.Filter = log.LineMatchPattern
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, exprDollar[5].Grouping, nil)
 		}
 	case 60:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:245
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:263
 		{
-			exprVAL.Filter = log.LineMatchNotRegexp
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, nil, &exprDollar[3].str)
 		}
 	case 61:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:246
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:264
 		{
-			exprVAL.Filter = log.LineMatchNotEqual
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
 	case 62:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:247
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:265
 		{
-			exprVAL.Filter = log.LineMatchNotPattern
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, &exprDollar[4].str)
 		}
 	case 63:
-		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:251
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:267
 		{
-			exprVAL.Selector = exprDollar[2].Matchers
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, OpTypeApproxCountDistinct, nil, &exprDollar[3].str)
 		}
 	case 64:
-		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:252
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:268
 		{
-			exprVAL.Selector = exprDollar[2].Matchers
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, OpTypeApproxCountDistinct, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
 	case 65:
-		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:253
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:269
 		{
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, OpTypeApproxCountDistinct, exprDollar[2].Grouping, &exprDollar[4].str)
 		}
 	case 66:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:257
+		exprDollar = exprS[exprpt-12 : exprpt+1]
+//line expr.y:274
 		{
-			exprVAL.Matchers = []*labels.Matcher{exprDollar[1].Matcher}
+			exprVAL.LabelReplaceExpr = mustNewLabelReplaceExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].str, exprDollar[11].str)
 		}
 	case 67:
-		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:258
+		exprDollar = exprS[exprpt-8 : exprpt+1]
+//line expr.y:278
 		{
-			exprVAL.Matchers = append(exprDollar[1].Matchers, exprDollar[3].Matcher)
+			exprVAL.FunctionExpr = mustNewLabelJoinExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, nil)
 		}
 	case 68:
-		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:262
+		exprDollar = exprS[exprpt-10 : exprpt+1]
+//line expr.y:279
 		{
-			exprVAL.Matcher = mustNewMatcher(labels.MatchEqual, exprDollar[1].str, exprDollar[3].str)
+			exprVAL.FunctionExpr = mustNewLabelJoinExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].Labels)
 		}
 	case 69:
-		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:263
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:283
 		{
-			exprVAL.Matcher = mustNewMatcher(labels.MatchNotEqual, exprDollar[1].str, exprDollar[3].str)
+			exprVAL.Labels = []string{exprDollar[1].str}
 		}
 	case 70:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:264
+//line expr.y:284
 		{
-			exprVAL.Matcher = mustNewMatcher(labels.MatchRegexp, exprDollar[1].str, exprDollar[3].str)
+			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
 		}
 	case 71:
-		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:265
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:288
 		{
-			exprVAL.Matcher = mustNewMatcher(labels.MatchNotRegexp, exprDollar[1].str, exprDollar[3].str)
+			exprVAL.FunctionExpr = newHistogramQuantileExpr(exprDollar[5].MetricExpr, exprDollar[3].LiteralExpr)
 		}
 	case 72:
-		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:269
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:292
 		{
-			exprVAL.PipelineExpr = MultiStageExpr{exprDollar[1].PipelineStage}
+			exprVAL.FunctionExpr = newFunctionExpr(exprDollar[1].FunctionOp, nil, nil)
 		}
 	case 73:
-		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:270
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:293
 		{
-			exprVAL.PipelineExpr = append(exprDollar[1].PipelineExpr, exprDollar[2].PipelineStage)
+			exprVAL.FunctionExpr = newFunctionExpr(exprDollar[1].FunctionOp, exprDollar[3].MetricExpr, nil)
 		}
 	case 74:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:294
+		{
+			exprVAL.FunctionExpr = newFunctionExpr(exprDollar[1].FunctionOp, exprDollar[3].MetricExpr, exprDollar[5].LiteralExpr)
+		}
+	case 75:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:274
+//line expr.y:298
+		{
+			exprVAL.FunctionOp = OpFuncAbs
+		}
+	case 76:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:299
+		{
+			exprVAL.FunctionOp = OpFuncCeil
+		}
+	case 77:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:300
+		{
+			exprVAL.FunctionOp = OpFuncFloor
+		}
+	case 78:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:301
+		{
+			exprVAL.FunctionOp = OpFuncRound
+		}
+	case 79:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:302
+		{
+			exprVAL.FunctionOp = OpFuncClampMin
+		}
+	case 80:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:303
+		{
+			exprVAL.FunctionOp = OpFuncClampMax
+		}
+	case 81:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:304
+		{
+			exprVAL.FunctionOp = OpFuncLn
+		}
+	case 82:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:305
+		{
+			exprVAL.FunctionOp = OpFuncExp
+		}
+	case 83:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:306
+		{
+			exprVAL.FunctionOp = OpFuncSqrt
+		}
+	case 84:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:307
+		{
+			exprVAL.FunctionOp = OpFuncTimestamp
+		}
+	case 85:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:308
+		{
+			exprVAL.FunctionOp = OpFuncHour
+		}
+	case 86:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:309
+		{
+			exprVAL.FunctionOp = OpFuncDayOfWeek
+		}
+	case 87:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:313
+		{
+			exprVAL.Filter = log.LineMatchRegexp
+		}
+	case 88:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:314
+		{
+			exprVAL.Filter = log.LineMatchEqual
+		}
+	case 89:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:315
+		{
+			exprVAL.Filter = log.LineMatchPattern
+		}
+	case 90:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:316
+		{
+			exprVAL.Filter = log.LineMatchNotRegexp
+		}
+	case 91:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:317
+		{
+			exprVAL.Filter = log.LineMatchNotEqual
+		}
+	case 92:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:318
+		{
+			exprVAL.Filter = log.LineMatchNotPattern
+		}
+	case 93:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:322
+		{
+			exprVAL.Selector = exprDollar[2].Matchers
+		}
+	case 94:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:323
+		{
+			exprVAL.Selector = exprDollar[2].Matchers
+		}
+	case 95:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:324
+		{
+		}
+	case 96:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:328
+		{
+			exprVAL.Matchers = []*labels.Matcher{exprDollar[1].Matcher}
+		}
+	case 97:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:329
+		{
+			exprVAL.Matchers = append(exprDollar[1].Matchers, exprDollar[3].Matcher)
+		}
+	case 98:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:333
+		{
+			exprVAL.Matcher = mustNewMatcher(labels.MatchEqual, exprDollar[1].str, exprDollar[3].str)
+		}
+	case 99:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:334
+		{
+			exprVAL.Matcher = mustNewMatcher(labels.MatchNotEqual, exprDollar[1].str, exprDollar[3].str)
+		}
+	case 100:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:335
+		{
+			exprVAL.Matcher = mustNewMatcher(labels.MatchRegexp, exprDollar[1].str, exprDollar[3].str)
+		}
+	case 101:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:336
+		{
+			exprVAL.Matcher = mustNewMatcher(labels.MatchNotRegexp, exprDollar[1].str, exprDollar[3].str)
+		}
+	case 102:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:340
+		{
+			exprVAL.PipelineExpr = MultiStageExpr{exprDollar[1].PipelineStage}
+		}
+	case 103:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:341
+		{
+			exprVAL.PipelineExpr = append(exprDollar[1].PipelineExpr, exprDollar[2].PipelineStage)
+		}
+	case 104:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:345
 		{
 			exprVAL.PipelineStage = exprDollar[1].LineFilters
 		}
-	case 75:
+	case 105:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:275
+//line expr.y:346
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtParser
 		}
-	case 76:
+	case 106:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:276
+//line expr.y:347
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelParser
 		}
-	case 77:
+	case 107:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:277
+//line expr.y:348
 		{
 			exprVAL.PipelineStage = exprDollar[2].JSONExpressionParser
 		}
-	case 78:
+	case 108:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:278
+//line expr.y:349
 		{
 			exprVAL.PipelineStage = exprDollar[2].LogfmtExpressionParser
 		}
-	case 79:
+	case 109:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:279
+//line expr.y:350
+		{
+			exprVAL.PipelineStage = exprDollar[2].XMLExpressionParser
+		}
+	case 110:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:351
+		{
+			exprVAL.PipelineStage = exprDollar[2].CSVExpressionParser
+		}
+	case 111:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:352
 		{
 			exprVAL.PipelineStage = &LabelFilterExpr{LabelFilterer: exprDollar[2].LabelFilter}
 		}
-	case 80:
+	case 112:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:280
+//line expr.y:353
 		{
 			exprVAL.PipelineStage = exprDollar[2].LineFormatExpr
 		}
-	case 81:
+	case 113:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:281
+//line expr.y:354
 		{
 			exprVAL.PipelineStage = exprDollar[2].DecolorizeExpr
 		}
-	case 82:
+	case 114:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:282
+//line expr.y:355
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelFormatExpr
 		}
-	case 83:
+	case 115:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:283
+//line expr.y:356
 		{
 			exprVAL.PipelineStage = exprDollar[2].DropLabelsExpr
 		}
-	case 84:
+	case 116:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:284
+//line expr.y:357
 		{
 			exprVAL.PipelineStage = exprDollar[2].KeepLabelsExpr
 		}
-	case 85:
+	case 117:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:358
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 118:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:359
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 119:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:360
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 120:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:361
+		{
+			exprVAL.PipelineStage = exprDollar[2].PipelineStage
+		}
+	case 121:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:288
+//line expr.y:365
 		{
 			exprVAL.FilterOp = OpFilterIP
 		}
-	case 86:
+	case 122:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:292
+//line expr.y:369
 		{
 			exprVAL.OrFilter = newLineFilterExpr(log.LineMatchEqual, "", exprDollar[1].str)
 		}
-	case 87:
+	case 123:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:293
+//line expr.y:370
 		{
 			exprVAL.OrFilter = newLineFilterExpr(log.LineMatchEqual, exprDollar[1].FilterOp, exprDollar[3].str)
 		}
-	case 88:
+	case 124:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:294
+//line expr.y:371
 		{
 			exprVAL.OrFilter = newOrLineFilter(newLineFilterExpr(log.LineMatchEqual, "", exprDollar[1].str), exprDollar[3].OrFilter)
 		}
-	case 89:
+	case 125:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:298
+//line expr.y:375
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str)
 		}
-	case 90:
+	case 126:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:299
+//line expr.y:376
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, exprDollar[2].FilterOp, exprDollar[4].str)
 		}
-	case 91:
+	case 127:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:300
+//line expr.y:377
 		{
 			exprVAL.LineFilter = newOrLineFilter(newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str), exprDollar[4].OrFilter)
 		}
-	case 92:
+	case 128:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:304
+//line expr.y:381
 		{
 			exprVAL.LineFilters = exprDollar[1].LineFilter
 		}
-	case 93:
+	case 129:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:305
+//line expr.y:382
 		{
 			exprVAL.LineFilters = newOrLineFilter(exprDollar[1].LineFilter, exprDollar[3].OrFilter)
 		}
-	case 94:
+	case 130:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:306
+//line expr.y:383
 		{
 			exprVAL.LineFilters = newNestedLineFilterExpr(exprDollar[1].LineFilters, exprDollar[2].LineFilter)
 		}
-	case 95:
+	case 131:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:310
+//line expr.y:387
 		{
 			exprVAL.ParserFlags = []string{exprDollar[1].str}
 		}
-	case 96:
+	case 132:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:311
+//line expr.y:388
 		{
 			exprVAL.ParserFlags = append(exprDollar[1].ParserFlags, exprDollar[2].str)
 		}
-	case 97:
+	case 133:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:315
+//line expr.y:392
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(nil)
 		}
-	case 98:
+	case 134:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:316
+//line expr.y:393
 		{
 			exprVAL.LogfmtParser = newLogfmtParserExpr(exprDollar[2].ParserFlags)
 		}
-	case 99:
+	case 135:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:320
+//line expr.y:397
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeJSON, "")
 		}
-	case 100:
+	case 136:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:321
+//line expr.y:398
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeRegexp, exprDollar[2].str)
 		}
-	case 101:
+	case 137:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:322
+//line expr.y:399
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeUnpack, "")
 		}
-	case 102:
+	case 138:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:323
+//line expr.y:400
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypePattern, exprDollar[2].str)
 		}
-	case 103:
+	case 139:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:401
+		{
+			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeXML, "")
+		}
+	case 140:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:402
+		{
+			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeCSV, "")
+		}
+	case 141:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:327
+//line expr.y:403
+		{
+			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeCSV, exprDollar[2].str)
+		}
+	case 142:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:407
 		{
 			exprVAL.JSONExpressionParser = newJSONExpressionParser(exprDollar[2].LabelExtractionExpressionList)
 		}
-	case 104:
+	case 143:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:330
+//line expr.y:410
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[3].LabelExtractionExpressionList, exprDollar[2].ParserFlags)
 		}
-	case 105:
+	case 144:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:331
+//line expr.y:411
 		{
 			exprVAL.LogfmtExpressionParser = newLogfmtExpressionParser(exprDollar[2].LabelExtractionExpressionList, nil)
 		}
-	case 106:
+	case 145:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:334
+//line expr.y:415
+		{
+			exprVAL.XMLExpressionParser = newXMLExpressionParser(exprDollar[2].LabelExtractionExpressionList)
+		}
+	case 146:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:418
+		{
+			exprVAL.CSVExpressionParser = newCSVExpressionParser("", exprDollar[2].LabelExtractionExpressionList)
+		}
+	case 147:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:419
+		{
+			exprVAL.CSVExpressionParser = newCSVExpressionParser(exprDollar[2].str, exprDollar[3].LabelExtractionExpressionList)
+		}
+	case 148:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:422
 		{
 			exprVAL.LineFormatExpr = newLineFmtExpr(exprDollar[2].str)
 		}
-	case 107:
+	case 149:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:336
+//line expr.y:424
 		{
 			exprVAL.DecolorizeExpr = newDecolorizeExpr()
 		}
-	case 108:
+	case 150:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:339
+//line expr.y:427
 		{
 			exprVAL.LabelFormat = log.NewRenameLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 109:
+	case 151:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:340
+//line expr.y:428
 		{
 			exprVAL.LabelFormat = log.NewTemplateLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 110:
+	case 152:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:344
+//line expr.y:432
 		{
 			exprVAL.LabelsFormat = []log.LabelFmt{exprDollar[1].LabelFormat}
 		}
-	case 111:
+	case 153:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:345
+//line expr.y:433
 		{
 			exprVAL.LabelsFormat = append(exprDollar[1].LabelsFormat, exprDollar[3].LabelFormat)
 		}
-	case 113:
+	case 155:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:350
+//line expr.y:438
 		{
 			exprVAL.LabelFormatExpr = newLabelFmtExpr(exprDollar[2].LabelsFormat)
 		}
-	case 114:
+	case 156:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:353
+//line expr.y:441
 		{
 			exprVAL.LabelFilter = log.NewStringLabelFilter(exprDollar[1].Matcher)
 		}
-	case 115:
+	case 157:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:354
+//line expr.y:442
 		{
 			exprVAL.LabelFilter = exprDollar[1].IPLabelFilter
 		}
-	case 116:
+	case 158:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:355
+//line expr.y:443
 		{
 			exprVAL.LabelFilter = exprDollar[1].UnitFilter
 		}
-	case 117:
+	case 159:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:356
+//line expr.y:444
 		{
 			exprVAL.LabelFilter = exprDollar[1].NumberFilter
 		}
-	case 118:
+	case 160:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:357
+//line expr.y:445
 		{
 			exprVAL.LabelFilter = exprDollar[2].LabelFilter
 		}
-	case 119:
+	case 161:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:358
+//line expr.y:446
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[2].LabelFilter)
 		}
-	case 120:
+	case 162:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:359
+//line expr.y:447
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 121:
+	case 163:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:360
+//line expr.y:448
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 122:
+	case 164:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:361
+//line expr.y:449
 		{
 			exprVAL.LabelFilter = log.NewOrLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 123:
+	case 165:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:365
+//line expr.y:453
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 124:
+	case 166:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:366
+//line expr.y:454
 		{
 			exprVAL.LabelExtractionExpression = log.NewLabelExtractionExpr(exprDollar[1].str, exprDollar[1].str)
 		}
-	case 125:
+	case 167:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:369
+//line expr.y:457
 		{
 			exprVAL.LabelExtractionExpressionList = []log.LabelExtractionExpr{exprDollar[1].LabelExtractionExpression}
 		}
-	case 126:
+	case 168:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:370
+//line expr.y:458
 		{
 			exprVAL.LabelExtractionExpressionList = append(exprDollar[1].LabelExtractionExpressionList, exprDollar[3].LabelExtractionExpression)
 		}
-	case 127:
+	case 169:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:374
+//line expr.y:462
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterEqual)
 		}
-	case 128:
+	case 170:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
-//line expr.y:375
+//line expr.y:463
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterNotEqual)
 		}
-	case 129:
+	case 171:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:379
+//line expr.y:467
 		{
 			exprVAL.UnitFilter = exprDollar[1].DurationFilter
 		}
-	case 130:
+	case 172:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:380
+//line expr.y:468
 		{
 			exprVAL.UnitFilter = exprDollar[1].BytesFilter
 		}
-	case 131:
+	case 173:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:383
+//line expr.y:471
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 132:
+	case 174:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:384
+//line expr.y:472
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 133:
+	case 175:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:385
+//line expr.y:473
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 134:
+	case 176:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:386
+//line expr.y:474
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 135:
+	case 177:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:387
+//line expr.y:475
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 136:
+	case 178:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:388
+//line expr.y:476
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 137:
+	case 179:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:389
+//line expr.y:477
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 138:
+	case 180:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:393
+//line expr.y:481
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 139:
+	case 181:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:394
+//line expr.y:482
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 140:
+	case 182:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:395
+//line expr.y:483
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 141:
+	case 183:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:396
+//line expr.y:484
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 142:
+	case 184:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:397
+//line expr.y:485
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 143:
+	case 185:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:398
+//line expr.y:486
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 144:
+	case 186:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:399
+//line expr.y:487
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 145:
+	case 187:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:403
+//line expr.y:491
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 146:
+	case 188:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:404
+//line expr.y:492
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 147:
+	case 189:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:405
+//line expr.y:493
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 148:
+	case 190:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:406
+//line expr.y:494
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 149:
+	case 191:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:407
+//line expr.y:495
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 150:
+	case 192:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:408
+//line expr.y:496
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 151:
+	case 193:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:409
+//line expr.y:497
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].LiteralExpr.Val)
 		}
-	case 152:
+	case 194:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:413
+//line expr.y:501
 		{
 			exprVAL.DropLabel = log.NewDropLabel(nil, exprDollar[1].str)
 		}
-	case 153:
+	case 195:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:414
+//line expr.y:502
 		{
 			exprVAL.DropLabel = log.NewDropLabel(exprDollar[1].Matcher, "")
 		}
-	case 154:
+	case 196:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:417
+//line expr.y:505
 		{
 			exprVAL.DropLabels = []log.DropLabel{exprDollar[1].DropLabel}
 		}
-	case 155:
+	case 197:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:418
+//line expr.y:506
 		{
 			exprVAL.DropLabels = append(exprDollar[1].DropLabels, exprDollar[3].DropLabel)
 		}
-	case 156:
+	case 198:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:421
+//line expr.y:509
 		{
 			exprVAL.DropLabelsExpr = newDropLabelsExpr(exprDollar[2].DropLabels)
 		}
-	case 157:
+	case 199:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:424
+//line expr.y:512
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(nil, exprDollar[1].str)
 		}
-	case 158:
+	case 200:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:425
+//line expr.y:513
 		{
 			exprVAL.KeepLabel = log.NewKeepLabel(exprDollar[1].Matcher, "")
 		}
-	case 159:
+	case 201:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:428
+//line expr.y:516
 		{
 			exprVAL.KeepLabels = []log.KeepLabel{exprDollar[1].KeepLabel}
 		}
-	case 160:
+	case 202:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:429
+//line expr.y:517
 		{
 			exprVAL.KeepLabels = append(exprDollar[1].KeepLabels, exprDollar[3].KeepLabel)
 		}
-	case 161:
+	case 203:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:432
+//line expr.y:520
 		{
 			exprVAL.KeepLabelsExpr = newKeepLabelsExpr(exprDollar[2].KeepLabels)
 		}
-	case 162:
+	case 204:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:436
+//line expr.y:524
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("or", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 163:
+	case 205:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:437
+//line expr.y:525
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("and", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 164:
+	case 206:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:438
+//line expr.y:526
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("unless", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 165:
+	case 207:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:439
+//line expr.y:527
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("+", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 166:
+	case 208:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:440
+//line expr.y:528
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("-", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 167:
+	case 209:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:441
+//line expr.y:529
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("*", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 168:
+	case 210:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:442
+//line expr.y:530
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("/", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 169:
+	case 211:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:443
+//line expr.y:531
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("%", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 170:
+	case 212:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:444
+//line expr.y:532
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("^", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 171:
+	case 213:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:445
+//line expr.y:533
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("==", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 172:
+	case 214:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:446
+//line expr.y:534
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("!=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 173:
+	case 215:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:447
+//line expr.y:535
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 174:
+	case 216:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:448
+//line expr.y:536
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 175:
+	case 217:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:449
+//line expr.y:537
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 176:
+	case 218:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:450
+//line expr.y:538
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 177:
+	case 219:
 		exprDollar = exprS[exprpt-0 : exprpt+1]
-//line expr.y:454
+//line expr.y:542
 		{
-			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}
+			exprVAL.BinOpModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}
 		}
-	case 178:
+	case 220:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:458
+//line expr.y:546
 		{
-			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}, ReturnBool: true}
+			exprVAL.BinOpModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}, ReturnBool: true}
 		}
-	case 179:
+	case 221:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:465
+//line expr.y:553
 		{
-			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
-			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
-			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
+			exprVAL.BinOpModifier.VectorMatching.On = true
+			exprVAL.BinOpModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 180:
+	case 222:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:471
+//line expr.y:559
 		{
-			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
-			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
+			exprVAL.BinOpModifier.VectorMatching.On = true
 		}
-	case 181:
+	case 223:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:476
+//line expr.y:564
 		{
-			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
-			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
+			exprVAL.BinOpModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 182:
+	case 224:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:481
+//line expr.y:569
 		{
-			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 		}
-	case 183:
+	case 225:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:487
+//line expr.y:575
 		{
-			exprVAL.BinOpModifier = exprDollar[1].BoolModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 		}
-	case 184:
+	case 226:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:488
+//line expr.y:576
 		{
-			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 		}
-	case 185:
+	case 227:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:490
+//line expr.y:578
 		{
-			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 186:
+	case 228:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:495
+//line expr.y:583
 		{
-			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 187:
+	case 229:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:500
+//line expr.y:588
 		{
-			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 188:
+	case 230:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:506
+//line expr.y:594
 		{
-			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 189:
+	case 231:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:511
+//line expr.y:599
 		{
-			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 190:
+	case 232:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
-//line expr.y:516
+//line expr.y:604
 		{
-			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
+			exprVAL.BinOpModifier = exprDollar[1].BinOpModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 191:
+	case 233:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:524
+//line expr.y:612
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[1].str, false)
 		}
-	case 192:
+	case 234:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:525
+//line expr.y:613
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, false)
 		}
-	case 193:
+	case 235:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:526
+//line expr.y:614
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, true)
 		}
-	case 194:
+	case 236:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:530
+//line expr.y:618
 		{
 			exprVAL.VectorExpr = NewVectorExpr(exprDollar[3].str)
 		}
-	case 195:
+	case 237:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:533
+//line expr.y:621
 		{
 			exprVAL.Vector = OpTypeVector
 		}
-	case 196:
+	case 238:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:537
+//line expr.y:625
 		{
 			exprVAL.VectorOp = OpTypeSum
 		}
-	case 197:
+	case 239:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:538
+//line expr.y:626
 		{
 			exprVAL.VectorOp = OpTypeAvg
 		}
-	case 198:
+	case 240:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:539
+//line expr.y:627
 		{
 			exprVAL.VectorOp = OpTypeCount
 		}
-	case 199:
+	case 241:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:540
+//line expr.y:628
 		{
 			exprVAL.VectorOp = OpTypeMax
 		}
-	case 200:
+	case 242:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:541
+//line expr.y:629
 		{
 			exprVAL.VectorOp = OpTypeMin
 		}
-	case 201:
+	case 243:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:542
+//line expr.y:630
 		{
 			exprVAL.VectorOp = OpTypeStddev
 		}
-	case 202:
+	case 244:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:543
+//line expr.y:631
 		{
 			exprVAL.VectorOp = OpTypeStdvar
 		}
-	case 203:
+	case 245:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:544
+//line expr.y:632
 		{
 			exprVAL.VectorOp = OpTypeBottomK
 		}
-	case 204:
+	case 246:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:545
+//line expr.y:633
 		{
 			exprVAL.VectorOp = OpTypeTopK
 		}
-	case 205:
+	case 247:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:546
+//line expr.y:634
+		{
+			exprVAL.VectorOp = OpTypeApproxTopK
+		}
+	case 248:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:635
 		{
 			exprVAL.VectorOp = OpTypeSort
 		}
-	case 206:
+	case 249:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:547
+//line expr.y:636
 		{
 			exprVAL.VectorOp = OpTypeSortDesc
 		}
-	case 207:
+	case 250:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:551
+//line expr.y:640
 		{
 			exprVAL.RangeOp = OpRangeTypeCount
 		}
-	case 208:
+	case 251:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:552
+//line expr.y:641
 		{
 			exprVAL.RangeOp = OpRangeTypeRate
 		}
-	case 209:
+	case 252:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:553
+//line expr.y:642
 		{
 			exprVAL.RangeOp = OpRangeTypeRateCounter
 		}
-	case 210:
+	case 253:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:554
+//line expr.y:643
 		{
 			exprVAL.RangeOp = OpRangeTypeBytes
 		}
-	case 211:
+	case 254:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:555
+//line expr.y:644
 		{
 			exprVAL.RangeOp = OpRangeTypeBytesRate
 		}
-	case 212:
+	case 255:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:556
+//line expr.y:645
 		{
 			exprVAL.RangeOp = OpRangeTypeAvg
 		}
-	case 213:
+	case 256:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:557
+//line expr.y:646
 		{
 			exprVAL.RangeOp = OpRangeTypeSum
 		}
-	case 214:
+	case 257:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:558
+//line expr.y:647
 		{
 			exprVAL.RangeOp = OpRangeTypeMin
 		}
-	case 215:
+	case 258:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:559
+//line expr.y:648
 		{
 			exprVAL.RangeOp = OpRangeTypeMax
 		}
-	case 216:
+	case 259:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:560
+//line expr.y:649
 		{
 			exprVAL.RangeOp = OpRangeTypeStdvar
 		}
-	case 217:
+	case 260:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:561
+//line expr.y:650
 		{
 			exprVAL.RangeOp = OpRangeTypeStddev
 		}
-	case 218:
+	case 261:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:562
+//line expr.y:651
 		{
 			exprVAL.RangeOp = OpRangeTypeQuantile
 		}
-	case 219:
+	case 262:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:563
+//line expr.y:652
 		{
 			exprVAL.RangeOp = OpRangeTypeFirst
 		}
-	case 220:
+	case 263:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:564
+//line expr.y:653
 		{
 			exprVAL.RangeOp = OpRangeTypeLast
 		}
-	case 221:
+	case 264:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:565
+//line expr.y:654
 		{
 			exprVAL.RangeOp = OpRangeTypeAbsent
 		}
-	case 222:
+	case 265:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
-//line expr.y:569
+//line expr.y:658
 		{
-			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[2].duration)
+			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[2].duration, nil)
 		}
-	case 223:
+	case 266:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:659
+		{
+			exprVAL.OffsetExpr = exprDollar[1].OffsetExpr
+		}
+	case 267:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:660
+		{
+			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[3].duration, exprDollar[1].OffsetExpr.At)
+		}
+	case 268:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:661
+		{
+			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[2].duration, exprDollar[3].OffsetExpr.At)
+		}
+	case 269:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:665
+		{
+			exprVAL.OffsetExpr = newOffsetExpr(0, mustNewAtModifier(exprDollar[2].str))
+		}
+	case 270:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:666
+		{
+			exprVAL.OffsetExpr = newOffsetExpr(0, &AtModifier{StartOrEnd: OpStart})
+		}
+	case 271:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:667
+		{
+			exprVAL.OffsetExpr = newOffsetExpr(0, &AtModifier{StartOrEnd: OpEnd})
+		}
+	case 272:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:671
+		{
+			exprVAL.PipelineStage = newStatsExpr(exprDollar[2].StatsAggregations, nil)
+		}
+	case 273:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:672
+		{
+			exprVAL.PipelineStage = newStatsExpr(exprDollar[2].StatsAggregations, exprDollar[5].Labels)
+		}
+	case 274:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:676
+		{
+			exprVAL.StatsAggregations = []log.StatsAggregation{exprDollar[1].StatsAggregation}
+		}
+	case 275:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:677
+		{
+			exprVAL.StatsAggregations = append(exprDollar[1].StatsAggregations, exprDollar[3].StatsAggregation)
+		}
+	case 276:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:681
+		{
+			exprVAL.StatsAggregation = log.StatsAggregation{Op: log.StatsCount}
+		}
+	case 277:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:682
+		{
+			exprVAL.StatsAggregation = log.StatsAggregation{Op: log.StatsCount, Field: exprDollar[3].str}
+		}
+	case 278:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:683
+		{
+			exprVAL.StatsAggregation = log.StatsAggregation{Op: log.StatsSum, Field: exprDollar[3].str}
+		}
+	case 279:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:684
+		{
+			exprVAL.StatsAggregation = log.StatsAggregation{Op: log.StatsAvg, Field: exprDollar[3].str}
+		}
+	case 280:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:685
+		{
+			exprVAL.StatsAggregation = log.StatsAggregation{Op: log.StatsMin, Field: exprDollar[3].str}
+		}
+	case 281:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:686
+		{
+			exprVAL.StatsAggregation = log.StatsAggregation{Op: log.StatsMax, Field: exprDollar[3].str}
+		}
+	case 282:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:687
+		{
+			exprVAL.StatsAggregation = log.StatsAggregation{Op: log.StatsDistinct, Field: exprDollar[3].str}
+		}
+	case 283:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:688
+		{
+			exprVAL.StatsAggregation = mustNewPercentileAggregation(exprDollar[3].str, exprDollar[5].str)
+		}
+	case 284:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:692
+		{
+			exprVAL.PipelineStage = mustNewAroundExpr(OpAroundBefore, exprDollar[2].str, OpAroundAfter, exprDollar[2].str)
+		}
+	case 285:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:693
+		{
+			exprVAL.PipelineStage = mustNewAroundExpr(exprDollar[2].str, exprDollar[4].str)
+		}
+	case 286:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:694
+		{
+			exprVAL.PipelineStage = mustNewAroundExpr(exprDollar[2].str, exprDollar[4].str, exprDollar[5].str, exprDollar[7].str)
+		}
+	case 287:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:698
+		{
+			exprVAL.PipelineStage = mustNewLimitByExpr(exprDollar[2].str, nil)
+		}
+	case 288:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:699
+		{
+			exprVAL.PipelineStage = mustNewLimitByExpr(exprDollar[2].str, exprDollar[5].Labels)
+		}
+	case 289:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:703
+		{
+			exprVAL.PipelineStage = newDedupExpr(nil)
+		}
+	case 290:
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:704
+		{
+			exprVAL.PipelineStage = newDedupExpr(exprDollar[4].Labels)
+		}
+	case 291:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
-//line expr.y:572
+//line expr.y:708
 		{
 			exprVAL.Labels = []string{exprDollar[1].str}
 		}
-	case 224:
+	case 292:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:573
+//line expr.y:709
 		{
 			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
 		}
-	case 225:
+	case 293:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:577
+//line expr.y:713
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: exprDollar[3].Labels}
 		}
-	case 226:
+	case 294:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
-//line expr.y:578
+//line expr.y:714
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: exprDollar[3].Labels}
 		}
-	case 227:
+	case 295:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:579
+//line expr.y:715
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: nil}
 		}
-	case 228:
+	case 296:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
-//line expr.y:580
+//line expr.y:716
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: nil}
 		}
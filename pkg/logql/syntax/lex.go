@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/util/strutil"
 
+	"github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
 
@@ -87,6 +88,24 @@ var parserFlags = map[string]struct{}{
 	OpKeepEmpty: {},
 }
 
+// stageTokens are tokens that are only keywords right after a pipe, so that
+// they can still be used as label names everywhere else.
+var stageTokens = map[string]int{
+	// parsers
+	OpParserTypeXML: XML,
+	OpParserTypeCSV: CSV,
+
+	// stats stage
+	OpStats: STATS,
+
+	// around stage
+	OpAround: AROUND,
+
+	// result stages
+	OpLimit: LIMIT,
+	OpDedup: DEDUP,
+}
+
 // functionTokens are tokens that needs to be suffixes with parenthesis
 var functionTokens = map[string]int{
 	// range vec ops
@@ -108,18 +127,36 @@ var functionTokens = map[string]int{
 	OpTypeVector:           VECTOR,
 
 	// vec ops
-	OpTypeSum:      SUM,
-	OpTypeAvg:      AVG,
-	OpTypeMax:      MAX,
-	OpTypeMin:      MIN,
-	OpTypeCount:    COUNT,
-	OpTypeStddev:   STDDEV,
-	OpTypeStdvar:   STDVAR,
-	OpTypeBottomK:  BOTTOMK,
-	OpTypeTopK:     TOPK,
-	OpTypeSort:     SORT,
-	OpTypeSortDesc: SORT_DESC,
-	OpLabelReplace: LABEL_REPLACE,
+	OpTypeSum:                 SUM,
+	OpTypeAvg:                 AVG,
+	OpTypeMax:                 MAX,
+	OpTypeMin:                 MIN,
+	OpTypeCount:               COUNT,
+	OpTypeStddev:              STDDEV,
+	OpTypeStdvar:              STDVAR,
+	OpTypeBottomK:             BOTTOMK,
+	OpTypeTopK:                TOPK,
+	OpTypeApproxTopK:          APPROX_TOPK,
+	OpTypeApproxCountDistinct: APPROX_COUNT_DISTINCT,
+	OpTypeSort:                SORT,
+	OpTypeSortDesc:            SORT_DESC,
+	OpLabelReplace:            LABEL_REPLACE,
+	OpLabelJoin:               LABEL_JOIN,
+	OpHistogramQuantile:       HISTOGRAM_QUANTILE,
+
+	// functions
+	OpFuncAbs:       ABS,
+	OpFuncCeil:      CEIL,
+	OpFuncFloor:     FLOOR,
+	OpFuncRound:     ROUND,
+	OpFuncClampMin:  CLAMP_MIN,
+	OpFuncClampMax:  CLAMP_MAX,
+	OpFuncLn:        LN,
+	OpFuncExp:       EXP,
+	OpFuncSqrt:      SQRT,
+	OpFuncTimestamp: TIMESTAMP,
+	OpFuncHour:      HOUR,
+	OpFuncDayOfWeek: DAY_OF_WEEK,
 
 	// conversion Op
 	OpConvBytes:           BYTES_CONV,
@@ -128,15 +165,29 @@ var functionTokens = map[string]int{
 
 	// filterOp
 	OpFilterIP: IP,
+
+	// @ modifier
+	OpStart: START,
+	OpEnd:   END,
+
+	// stats aggregations
+	log.StatsDistinct:   DISTINCT,
+	log.StatsPercentile: PERCENTILE,
 }
 
 type lexer struct {
 	Scanner
 	errs    []logqlmodel.ParseError
 	builder strings.Builder
+	prev    int // last token returned by Lex
 }
 
 func (l *lexer) Lex(lval *exprSymType) int {
+	l.prev = l.lex(lval)
+	return l.prev
+}
+
+func (l *lexer) lex(lval *exprSymType) int {
 	r := l.Scan()
 
 	switch r {
@@ -146,11 +197,14 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		for next := l.Peek(); !(next == '\n' || next == scanner.EOF); next = l.Next() {
 		}
 
-		return l.Lex(lval)
+		return l.lex(lval)
 
 	case scanner.EOF:
 		return 0
 
+	case '@':
+		return AT
+
 	case scanner.Int, scanner.Float:
 		numberText := l.TokenText()
 
@@ -202,6 +256,9 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		l.builder.Reset()
 		for r := l.Next(); r != scanner.EOF; r = l.Next() {
 			if r == ']' {
+				if rng, step, ok := strings.Cut(l.builder.String(), ":"); ok {
+					return l.subqueryRange(lval, rng, step)
+				}
 				i, err := model.ParseDuration(l.builder.String())
 				if err != nil {
 					l.Error(err.Error())
@@ -247,6 +304,10 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		return tok
 	}
 
+	if tok, ok := stageTokens[tokenTextLower]; ok && l.prev == PIPE && !isLabelFilter(l.Scanner) {
+		return tok
+	}
+
 	lval.str = tokenText
 	return IDENTIFIER
 }
@@ -255,6 +316,26 @@ func (l *lexer) Error(msg string) {
 	l.errs = append(l.errs, logqlmodel.NewParseError(msg, l.Line, l.Column))
 }
 
+// subqueryRange returns a SUBQUERY_RANGE token from the range and optional
+// step of a subquery, e.g. [1h:5m] or [1h:].
+func (l *lexer) subqueryRange(lval *exprSymType, rng, step string) int {
+	r, err := model.ParseDuration(strings.TrimSpace(rng))
+	if err != nil {
+		l.Error(err.Error())
+		return 0
+	}
+	lval.subqueryRange = SubqueryRange{Range: time.Duration(r)}
+	if step = strings.TrimSpace(step); step != "" {
+		s, err := model.ParseDuration(step)
+		if err != nil {
+			l.Error(err.Error())
+			return 0
+		}
+		lval.subqueryRange.Step = time.Duration(s)
+	}
+	return SUBQUERY_RANGE
+}
+
 // tryScanFlag scans for a parser flag and returns it on success
 // it advances the scanner only if a valid flag is found
 func tryScanFlag(l *Scanner) (string, bool) {
@@ -405,6 +486,17 @@ func isFunction(sc Scanner) bool {
 	return false
 }
 
+// isLabelFilter reports whether the next token compares a label, as in
+// `| csv = "1"`, in which case a stage keyword is a label name.
+func isLabelFilter(sc Scanner) bool {
+	sc = trimSpace(sc)
+	switch sc.Peek() {
+	case '=', '!', '<', '>':
+		return true
+	}
+	return false
+}
+
 func trimSpace(l Scanner) Scanner {
 	for n := l.Peek(); n != scanner.EOF; n = l.Peek() {
 		if unicode.IsSpace(n) {
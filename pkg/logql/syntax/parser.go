@@ -195,6 +195,29 @@ func validateSampleExpr(expr SampleExpr) error {
 			return e.err
 		}
 		return nil
+	case *SubqueryExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *FunctionExpr:
+		if e.err != nil {
+			return e.err
+		}
+		if e.Left == nil {
+			return nil
+		}
+		return validateSampleExpr(e.Left)
+	case *LabelJoinExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *HistogramQuantileExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
 	case *VectorAggregationExpr:
 		if e.err != nil {
 			return e.err
@@ -210,6 +233,9 @@ func validateSampleExpr(expr SampleExpr) error {
 		if err != nil {
 			return err
 		}
+		if op, ok := lastOnlyStage(selector); ok {
+			return logqlmodel.NewParseError(fmt.Sprintf("%s stage is only allowed in log queries", op), 0, 0)
+		}
 		return validateLogSelectorExpression(selector)
 	}
 }
@@ -218,11 +244,83 @@ func validateLogSelectorExpression(expr LogSelectorExpr) error {
 	switch e := expr.(type) {
 	case *VectorExpr:
 		return nil
+	case *PipelineExpr:
+		if err := validateLastOnlyStages(e.MultiStages); err != nil {
+			return err
+		}
+		if err := validateFormatStages(e.MultiStages); err != nil {
+			return err
+		}
+		return validateMatchers(e.Matchers())
 	default:
 		return validateMatchers(e.Matchers())
 	}
 }
 
+// validateLastOnlyStages ensures that stats and around stages are the last
+// stage of a pipeline, and that limit and dedup stages are only followed by
+// other limit and dedup stages.
+func validateLastOnlyStages(stages MultiStageExpr) error {
+	for i, s := range stages {
+		op, ok := lastOnlyStageOp(s)
+		if !ok || i == len(stages)-1 {
+			continue
+		}
+		if !isResultStage(s) {
+			return logqlmodel.NewParseError(fmt.Sprintf("%s stage must be the last stage of a log query", op), 0, 0)
+		}
+		if !isResultStage(stages[i+1]) {
+			return logqlmodel.NewParseError(fmt.Sprintf("%s stage can only be followed by %s or %s stages", op, OpLimit, OpDedup), 0, 0)
+		}
+	}
+	return nil
+}
+
+// validateFormatStages compiles the templates of line_format and label_format
+// stages so that unknown functions and malformed templates are reported as
+// parse errors instead of failing once the query is executed.
+func validateFormatStages(stages MultiStageExpr) error {
+	for _, s := range stages {
+		switch s.(type) {
+		case *LineFmtExpr, *LabelFmtExpr:
+			if _, err := s.Stage(); err != nil {
+				return logqlmodel.NewParseError(err.Error(), 0, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// lastOnlyStage returns the operation of the first stats, around, limit or
+// dedup stage of a log query, if any.
+func lastOnlyStage(expr LogSelectorExpr) (string, bool) {
+	p, ok := expr.(*PipelineExpr)
+	if !ok {
+		return "", false
+	}
+	for _, s := range p.MultiStages {
+		if op, ok := lastOnlyStageOp(s); ok {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func lastOnlyStageOp(s StageExpr) (string, bool) {
+	switch s.(type) {
+	case *StatsExpr:
+		return OpStats, true
+	case *AroundExpr:
+		return OpAround, true
+	case *LimitByExpr:
+		return OpLimit, true
+	case *DedupExpr:
+		return OpDedup, true
+	default:
+		return "", false
+	}
+}
+
 // validateSortGrouping prevent by|without groupings on sort operations.
 // This will keep compatibility with promql and allowing sort by (foo) doesn't make much sense anyway when sort orders by value instead of labels.
 func validateSortGrouping(grouping *Grouping) error {
@@ -384,6 +384,456 @@ var ParseTestCases = []struct {
 		in:  `min({ foo = "bar" }[5m])`,
 		err: logqlmodel.NewParseError("syntax error: unexpected RANGE", 0, 20),
 	},
+	{
+		in: `max_over_time(rate({app="api"} |= "error" [1m])[1h:1m])`,
+		exp: &SubqueryExpr{
+			Operation: OpRangeTypeMax,
+			Left: &RangeAggregationExpr{
+				Operation: OpRangeTypeRate,
+				Left: &LogRange{
+					Left: newPipelineExpr(
+						newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+						MultiStageExpr{newLineFilterExpr(log.LineMatchEqual, "", "error")},
+					),
+					Interval: time.Minute,
+				},
+			},
+			Range: time.Hour,
+			Step:  time.Minute,
+		},
+	},
+	{
+		in: `quantile_over_time(0.99, sum by (app) (rate({app="api"}[1m]))[1h:] offset 1d)`,
+		exp: newSubqueryExpr(
+			mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					&LogRange{
+						Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+						Interval: time.Minute,
+					},
+					OpRangeTypeRate, nil, nil,
+				),
+				OpTypeSum, &Grouping{Groups: []string{"app"}}, nil,
+			),
+			OpRangeTypeQuantile, SubqueryRange{Range: time.Hour}, newOffsetExpr(24*time.Hour, nil), NewStringLabelFilter("0.99"),
+		),
+	},
+	{
+		in: `count_over_time({app="api"}[5m] @ 1609746000)`,
+		exp: newRangeAggregationExpr(
+			&LogRange{
+				Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+				Interval: 5 * time.Minute,
+				At:       &AtModifier{Timestamp: time.UnixMilli(1609746000000)},
+			},
+			OpRangeTypeCount, nil, nil,
+		),
+	},
+	{
+		in: `rate({app="api"} |= "error" [5m] @ 1609746000.5 offset 1h)`,
+		exp: newRangeAggregationExpr(
+			&LogRange{
+				Left: newPipelineExpr(
+					newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+					MultiStageExpr{newLineFilterExpr(log.LineMatchEqual, "", "error")},
+				),
+				Interval: 5 * time.Minute,
+				Offset:   time.Hour,
+				At:       &AtModifier{Timestamp: time.UnixMilli(1609746000500)},
+			},
+			OpRangeTypeRate, nil, nil,
+		),
+	},
+	{
+		in: `rate({app="api"}[5m] offset 1h @ start())`,
+		exp: newRangeAggregationExpr(
+			&LogRange{
+				Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+				Interval: 5 * time.Minute,
+				Offset:   time.Hour,
+				At:       &AtModifier{StartOrEnd: OpStart},
+			},
+			OpRangeTypeRate, nil, nil,
+		),
+	},
+	{
+		in: `sum by (end) (rate({end="api"}[5m] @ end()))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "end", "api")}),
+					Interval: 5 * time.Minute,
+					At:       &AtModifier{StartOrEnd: OpEnd},
+				},
+				OpRangeTypeRate, nil, nil,
+			),
+			OpTypeSum, &Grouping{Groups: []string{"end"}}, nil,
+		),
+	},
+	{
+		in: `max_over_time(rate({app="api"}[1m])[1h:] @ 1609746000)`,
+		exp: newSubqueryExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+					Interval: time.Minute,
+				},
+				OpRangeTypeRate, nil, nil,
+			),
+			OpRangeTypeMax, SubqueryRange{Range: time.Hour}, newOffsetExpr(0, &AtModifier{Timestamp: time.UnixMilli(1609746000000)}), nil,
+		),
+	},
+	{
+		in: `sum by (at) (rate({at="api"} | logfmt | at > 0 [5m] @ 1609746000))`, // @ does not reserve at
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left: newPipelineExpr(
+						newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "at", "api")}),
+						MultiStageExpr{
+							newLogfmtParserExpr(nil),
+							newLabelFilterExpr(log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "at", 0)),
+						},
+					),
+					Interval: 5 * time.Minute,
+					At:       &AtModifier{Timestamp: time.UnixMilli(1609746000000)},
+				},
+				OpRangeTypeRate, nil, nil,
+			),
+			OpTypeSum, &Grouping{Groups: []string{"at"}}, nil,
+		),
+	},
+	{
+		in: `count_over_time(({app="api"})[5m] @ 1609746000)`,
+		exp: newRangeAggregationExpr(
+			&LogRange{
+				Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+				Interval: 5 * time.Minute,
+				At:       &AtModifier{Timestamp: time.UnixMilli(1609746000000)},
+			},
+			OpRangeTypeCount, nil, nil,
+		),
+	},
+	{
+		in:  `rate({app="api"}[5m] @ foo())`,
+		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or START or END", 1, 24),
+	},
+	{
+		in: `{stats="a"} | json | stats = "ok" | stats count() by (stats)`, // stats is only a keyword right after a pipe
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "stats", "a")}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "stats", "ok"))),
+				newStatsExpr([]log.StatsAggregation{{Op: log.StatsCount}}, []string{"stats"}),
+			},
+		},
+	},
+	{
+		in: `{app="api"} | json | stats count() by (status, route)`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				newStatsExpr([]log.StatsAggregation{{Op: log.StatsCount}}, []string{"status", "route"}),
+			},
+		},
+	},
+	{
+		in: `{app="api"} |= "error" | logfmt | stats count(duration), sum(bytes), avg(duration), min(duration), max(duration), distinct(user), percentile(0.99, duration)`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newLineFilterExpr(log.LineMatchEqual, "", "error"),
+				newLogfmtParserExpr(nil),
+				newStatsExpr([]log.StatsAggregation{
+					{Op: log.StatsCount, Field: "duration"},
+					{Op: log.StatsSum, Field: "bytes"},
+					{Op: log.StatsAvg, Field: "duration"},
+					{Op: log.StatsMin, Field: "duration"},
+					{Op: log.StatsMax, Field: "duration"},
+					{Op: log.StatsDistinct, Field: "user"},
+					{Op: log.StatsPercentile, Field: "duration", Param: 0.99},
+				}, nil),
+			},
+		},
+	},
+	{
+		in: `{app="api"} | json distinct="user" | stats distinct(distinct) by (distinct)`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newJSONExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("distinct", "user"),
+				}),
+				newStatsExpr([]log.StatsAggregation{{Op: log.StatsDistinct, Field: "distinct"}}, []string{"distinct"}),
+			},
+		},
+	},
+	{
+		in:  `{app="api"} | stats percentile(1.5, duration)`,
+		err: logqlmodel.NewParseError("invalid percentile 1.5, must be between 0 and 1", 0, 0),
+	},
+	{
+		in:  `{app="api"} | stats count() | json`,
+		err: logqlmodel.NewParseError("stats stage must be the last stage of a log query", 0, 0),
+	},
+	{
+		in:  `count_over_time({app="api"} | stats count() [5m])`,
+		err: logqlmodel.NewParseError("stats stage is only allowed in log queries", 0, 0),
+	},
+	{
+		in:  `{app="api"} | stats count() without (status)`,
+		err: logqlmodel.NewParseError("syntax error: unexpected without", 1, 29),
+	},
+	{
+		in: `{app="api"} |= "error" | around 3`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newLineFilterExpr(log.LineMatchEqual, "", "error"),
+				&AroundExpr{Before: 3, After: 3},
+			},
+		},
+	},
+	{
+		in: `{app="api"} | json | level="error" | around after=10 before=2`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "level", "error"))},
+				&AroundExpr{Before: 2, After: 10},
+			},
+		},
+	},
+	{
+		in: `{app="api"} |= "error" | around after=5`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newLineFilterExpr(log.LineMatchEqual, "", "error"),
+				&AroundExpr{After: 5},
+			},
+		},
+	},
+	{
+		in:  `{app="api"} |= "error" | around 101`,
+		err: logqlmodel.NewParseError("invalid number of lines 101, must be between 0 and 100", 0, 0),
+	},
+	{
+		in:  `{app="api"} |= "error" | around lines=3`,
+		err: logqlmodel.NewParseError("invalid around parameter lines, must be before or after", 0, 0),
+	},
+	{
+		in:  `{app="api"} |= "error" | around 3 | json`,
+		err: logqlmodel.NewParseError("around stage must be the last stage of a log query", 0, 0),
+	},
+	{
+		in: `{around="1"} | logfmt | around > 0 | around 2`, // around is only a keyword right after a pipe
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "around", "1")}),
+			MultiStages: MultiStageExpr{
+				newLogfmtParserExpr(nil),
+				newLabelFilterExpr(log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "around", 0)),
+				&AroundExpr{Before: 2, After: 2},
+			},
+		},
+	},
+	{
+		in:  `count_over_time({app="api"} |= "error" | around 3 [5m])`,
+		err: logqlmodel.NewParseError("around stage is only allowed in log queries", 0, 0),
+	},
+	{
+		in: `{app="api"} | json | limit 10 by (pod, namespace)`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&LimitByExpr{Limit: 10, Groups: []string{"pod", "namespace"}},
+			},
+		},
+	},
+	{
+		in: `{app="api"} |= "error" | dedup | limit 5`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				newLineFilterExpr(log.LineMatchEqual, "", "error"),
+				&DedupExpr{},
+				&LimitByExpr{Limit: 5},
+			},
+		},
+	},
+	{
+		in: `{app="api"} | dedup by (app)`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+			MultiStages: MultiStageExpr{
+				&DedupExpr{Groups: []string{"app"}},
+			},
+		},
+	},
+	{
+		in: `{dedup="a"} | json limit="x" | logfmt | limit > 10 | label_format limit=foo | dedup by (limit) | limit 5`, // limit and dedup are only keywords right after a pipe
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "dedup", "a")}),
+			MultiStages: MultiStageExpr{
+				newJSONExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("limit", `x`),
+				}),
+				newLogfmtParserExpr(nil),
+				newLabelFilterExpr(log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "limit", 10)),
+				newLabelFmtExpr([]log.LabelFmt{log.NewRenameLabelFmt("limit", "foo")}),
+				&DedupExpr{Groups: []string{"limit"}},
+				&LimitByExpr{Limit: 5},
+			},
+		},
+	},
+	{
+		in:  `{app="api"} | limit 0`,
+		err: logqlmodel.NewParseError("invalid limit 0, must be a positive integer", 0, 0),
+	},
+	{
+		in:  `{app="api"} | limit 10 | json`,
+		err: logqlmodel.NewParseError("limit stage can only be followed by limit or dedup stages", 0, 0),
+	},
+	{
+		in:  `{app="api"} | around 3 | dedup`,
+		err: logqlmodel.NewParseError("around stage must be the last stage of a log query", 0, 0),
+	},
+	{
+		in:  `count_over_time({app="api"} | dedup [5m])`,
+		err: logqlmodel.NewParseError("dedup stage is only allowed in log queries", 0, 0),
+	},
+	{
+		in: `clamp_min(abs(sum(rate({app="api"}[1m]))), -1)`,
+		exp: &FunctionExpr{
+			Function: OpFuncClampMin,
+			Args:     []float64{-1},
+			Left: &FunctionExpr{
+				Function: OpFuncAbs,
+				Left: mustNewVectorAggregationExpr(
+					newRangeAggregationExpr(
+						&LogRange{
+							Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+							Interval: time.Minute,
+						},
+						OpRangeTypeRate, nil, nil,
+					),
+					OpTypeSum, &Grouping{}, nil,
+				),
+			},
+		},
+	},
+	{
+		in: `label_join(rate({app="api"}[1m]), "dst", "-", "app", "pod")`,
+		exp: mustNewLabelJoinExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+					Interval: time.Minute,
+				},
+				OpRangeTypeRate, nil, nil,
+			),
+			"dst", "-", []string{"app", "pod"},
+		),
+	},
+	{
+		in:  `label_join(vector(0), "dst", ",")`,
+		exp: mustNewLabelJoinExpr(&VectorExpr{Val: 0}, "dst", ",", nil),
+	},
+	{
+		in:  `label_join(vector(0), "1dst", ",", "src")`,
+		err: logqlmodel.NewParseError("invalid destination label name in label_join: 1dst", 0, 0),
+	},
+	{
+		in:  `label_join(vector(0), "dst", ",", "src-1")`,
+		err: logqlmodel.NewParseError("invalid source label name in label_join: src-1", 0, 0),
+	},
+	{
+		in: `histogram_quantile(0.99, sum by (le) (sum_over_time({app="api"} | unwrap count [1m])))`,
+		exp: &HistogramQuantileExpr{
+			Quantile: 0.99,
+			Left: mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					&LogRange{
+						Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "api")}),
+						Interval: time.Minute,
+						Unwrap:   &UnwrapExpr{Identifier: "count"},
+					},
+					OpRangeTypeSum, nil, nil,
+				),
+				OpTypeSum, &Grouping{Groups: []string{"le"}}, nil,
+			),
+		},
+	},
+	{
+		in:  `histogram_quantile(sum by (le) (rate({app="api"}[1m])))`,
+		err: logqlmodel.NewParseError("syntax error: unexpected SUM, expecting NUMBER or + or -", 1, 20),
+	},
+	{
+		in:  `hour() >= 9`,
+		exp: mustNewBinOpExpr(OpTypeGTE, &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}, &FunctionExpr{Function: OpFuncHour}, mustNewLiteralExpr("9", false)),
+	},
+	{
+		in:  `abs()`,
+		err: logqlmodel.NewParseError("function abs requires a vector argument", 0, 0),
+	},
+	{
+		in:  `hour(vector(1), 5)`,
+		err: logqlmodel.NewParseError("invalid number of arguments for function hour", 0, 0),
+	},
+	{
+		in:  `day_of_week(vector(1), 2)`,
+		err: logqlmodel.NewParseError("invalid number of arguments for function day_of_week", 0, 0),
+	},
+	{
+		in:  `{round="1", hour="5"}`, // function names are only keywords before a parenthesis
+		exp: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "round", "1"), mustNewMatcher(labels.MatchEqual, "hour", "5")}),
+	},
+	{
+		in: `{app="x"} | json | timestamp != "" | logfmt | exp > 1 | label_format day_of_week=hour`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "x")}),
+			MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchNotEqual, "timestamp", ""))),
+				newLogfmtParserExpr(nil),
+				newLabelFilterExpr(log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "exp", 1)),
+				newLabelFmtExpr([]log.LabelFmt{log.NewRenameLabelFmt("day_of_week", "hour")}),
+			},
+		),
+	},
+	{
+		in: `sum by (hour) (count_over_time({app="x"}[1m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				&LogRange{
+					Left:     newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "x")}),
+					Interval: time.Minute,
+				},
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeSum, &Grouping{Groups: []string{"hour"}}, nil,
+		),
+	},
+	{
+		in:  `clamp_max(rate({app="api"}[1m]))`,
+		err: logqlmodel.NewParseError("invalid number of arguments for function clamp_max", 0, 0),
+	},
+	{
+		in:  `rate(rate({app="api"}[1m])[1h:1m])`,
+		err: logqlmodel.NewParseError("invalid aggregation rate over subquery", 0, 0),
+	},
+	{
+		in:  `quantile_over_time(rate({app="api"}[1m])[1h:1m])`,
+		err: logqlmodel.NewParseError("parameter required for operation quantile_over_time", 0, 0),
+	},
+	{
+		in:  `max_over_time(rate({app="api"}[1m])[1h:1x])`,
+		err: logqlmodel.NewParseError(`unknown unit "x" in duration "1x"`, 0, 36),
+	},
 	// line filter for ip-matcher
 	{
 		in: `{foo="bar"} |= "baz" |= ip("123.123.123.123")`,
@@ -638,6 +1088,42 @@ var ParseTestCases = []struct {
 		in:  `topk(count_over_time({ foo = "bar" }[5h]))`,
 		err: logqlmodel.NewParseError("parameter required for operation topk", 0, 0),
 	},
+	{
+		in: `approx_topk(10, sum by (user_id) (count_over_time({ foo = "bar" }[5h])))`,
+		exp: mustNewVectorAggregationExpr(mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRange{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+				Interval: 5 * time.Hour,
+			},
+			Operation: "count_over_time",
+		}, "sum", &Grouping{Groups: []string{"user_id"}}, nil), OpTypeApproxTopK, nil, NewStringLabelFilter("10")),
+	},
+	{
+		in:  `approx_topk(count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("parameter required for operation approx_topk", 0, 0),
+	},
+	{
+		in:  `approx_topk(10, count_over_time({ foo = "bar" }[5h])) by (foo)`,
+		err: logqlmodel.NewParseError("grouping not allowed for approx_topk aggregation", 0, 0),
+	},
+	{
+		in: `approx_count_distinct by (app) (user_id, count_over_time({ foo = "bar" }[5h]))`,
+		exp: mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRange{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+				Interval: 5 * time.Hour,
+			},
+			Operation: "count_over_time",
+		}, OpTypeApproxCountDistinct, &Grouping{Groups: []string{"app"}}, NewStringLabelFilter("user_id")),
+	},
+	{
+		in:  `approx_count_distinct(count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("syntax error: unexpected COUNT_OVER_TIME, expecting IDENTIFIER", 1, 23),
+	},
+	{
+		in:  `approx_count_distinct(10, count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("syntax error: unexpected NUMBER, expecting IDENTIFIER", 1, 23),
+	},
 	{
 		in:  `bottomk(he,count_over_time({ foo = "bar" }[5h]))`,
 		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER", 1, 9),
@@ -1528,6 +2014,24 @@ var ParseTestCases = []struct {
 			},
 		},
 	},
+	{
+		in: `{app="foo"} | line_format "{{ toJson . }}" | label_format user="{{ .user | fnv }}"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLineFmtExpr("{{ toJson . }}"),
+				newLabelFmtExpr([]log.LabelFmt{log.NewTemplateLabelFmt("user", "{{ .user | fnv }}")}),
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | line_format "{{ .foo | unknown }}"`,
+		err: logqlmodel.NewParseError(`invalid line template: template: line:1: function "unknown" not defined`, 0, 0),
+	},
+	{
+		in:  `sum(count_over_time({app="foo"} | label_format bar="{{ .foo " [5m]))`,
+		err: logqlmodel.NewParseError(`invalid template for label 'bar': template: label:1: unclosed action`, 0, 0),
+	},
 	{
 		in: `{app="foo"} |= "bar" | line_format "blip{{ .foo }}blop"`,
 		exp: &PipelineExpr{
@@ -1753,7 +2257,7 @@ var ParseTestCases = []struct {
 			},
 				5*time.Minute,
 				newUnwrapExpr("foo", OpConvBytes),
-				newOffsetExpr(5*time.Minute)),
+				newOffsetExpr(5*time.Minute, nil)),
 			OpRangeTypeSum, nil, nil,
 		),
 	},
@@ -1867,7 +2371,7 @@ var ParseTestCases = []struct {
 				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				5*time.Minute,
 				newUnwrapExpr("bar", ""),
-				newOffsetExpr(5*time.Minute)),
+				newOffsetExpr(5*time.Minute, nil)),
 			OpRangeTypeMax, &Grouping{Without: true, Groups: []string{"foo", "bar"}}, nil,
 		),
 	},
@@ -1878,7 +2382,7 @@ var ParseTestCases = []struct {
 				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				5*time.Minute,
 				newUnwrapExpr("bar", ""),
-				newOffsetExpr(-5*time.Minute)),
+				newOffsetExpr(-5*time.Minute, nil)),
 			OpRangeTypeMax, &Grouping{Without: true, Groups: []string{"foo", "bar"}}, nil,
 		),
 	},
@@ -2076,7 +2580,7 @@ var ParseTestCases = []struct {
 				},
 					5*time.Minute,
 					newUnwrapExpr("foo", ""),
-					newOffsetExpr(5*time.Minute)),
+					newOffsetExpr(5*time.Minute, nil)),
 				OpRangeTypeQuantile, &Grouping{Without: false, Groups: []string{"namespace", "instance"}}, NewStringLabelFilter("0.99998"),
 			),
 			OpTypeSum,
@@ -2889,7 +3393,7 @@ var ParseTestCases = []struct {
 	},
 	{
 		in:  `quantile_over_time(foo,{namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms| unwrap latency [5m])`,
-		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or { or (", 1, 20),
+		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER", 1, 20),
 	},
 	{
 		in:  `vector(abc)`,
@@ -3024,6 +3528,111 @@ var ParseTestCases = []struct {
 			},
 		},
 	},
+	{
+		in: `{app="foo"} | xml`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeXML, ""),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | xml id="/Event/System/EventID", ip="//Data[@Name='IpAddress']"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newXMLExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("id", `/Event/System/EventID`),
+					log.NewLabelExtractionExpr("ip", `//Data[@Name='IpAddress']`),
+				}),
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | xml id="/Event["`,
+		err: logqlmodel.NewParseError(`invalid xml parser: cannot parse expression [/Event[]: invalid xml expression "/Event[": unclosed predicate at position 6`, 0, 0),
+	},
+	{
+		in: `{app="foo"} | csv`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeCSV, ""),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | csv ts, level, msg="4"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newCSVExpressionParser("", []log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("ts", `ts`),
+					log.NewLabelExtractionExpr("level", `level`),
+					log.NewLabelExtractionExpr("msg", `4`),
+				}),
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | csv msg="message"`,
+		err: logqlmodel.NewParseError(`invalid csv parser: cannot parse expression [message]: column must be a positive integer`, 0, 0),
+	},
+	{
+		in: `{app="foo"} | csv "method,path,status"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeCSV, "method,path,status"),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | csv "method,path,status" status, url="path", code="3"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newCSVExpressionParser("method,path,status", []log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("status", `status`),
+					log.NewLabelExtractionExpr("url", `path`),
+					log.NewLabelExtractionExpr("code", `3`),
+				}),
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | csv "method,path" msg="message"`,
+		err: logqlmodel.NewParseError(`invalid csv parser: cannot parse expression [message]: column not found in header`, 0, 0),
+	},
+	{
+		in:  `{xml="a", csv="b"}`, // parser names are only keywords right after a pipe
+		exp: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "xml", "a"), mustNewMatcher(labels.MatchEqual, "csv", "b")}),
+	},
+	{
+		in: `{app="foo"} | json xml="data" | logfmt | csv = "1"`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+			MultiStageExpr{
+				newJSONExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("xml", `data`),
+				}),
+				newLogfmtParserExpr(nil),
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "csv", "1"))),
+			},
+		),
+	},
+	{
+		in: `{app="foo"} | logfmt | xml != "" | label_format csv=xml`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+			MultiStageExpr{
+				newLogfmtParserExpr(nil),
+				newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchNotEqual, "xml", ""))),
+				newLabelFmtExpr([]log.LabelFmt{log.NewRenameLabelFmt("csv", "xml")}),
+			},
+		),
+	},
 	{
 		in: `count_over_time({ foo ="bar" } | json layer7_something_specific="layer7_something_specific" [12m])`,
 		exp: &RangeAggregationExpr{
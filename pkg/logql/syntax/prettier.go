@@ -121,6 +121,26 @@ func (e *KeepLabelsExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | stats count(), avg(duration) by (status)
+func (e *StatsExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | around before=2 after=5
+func (e *AroundExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | limit 10 by (pod)
+func (e *LimitByExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | dedup by (app)
+func (e *DedupExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: | level!="error"
 func (e *LabelFilterExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -151,6 +171,16 @@ func (e *LogfmtExpressionParser) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | xml label="expression", another="expression"
+func (e *XMLExpressionParser) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | csv "header" label="expression", another="expression"
+func (e *CSVExpressionParser) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: sum_over_time({foo="bar"} | logfmt | unwrap bytes_processed [5m])
 func (e *UnwrapExpr) Pretty(level int) string {
 	s := Indent(level)
@@ -180,6 +210,10 @@ func (e *LogRange) Pretty(level int) string {
 	// TODO: this will put [1m] on the same line, not in new line as people used to now.
 	s = fmt.Sprintf("%s [%s]", s, model.Duration(e.Interval))
 
+	if e.At != nil {
+		s += e.At.String()
+	}
+
 	if e.Offset != 0 {
 		oe := OffsetExpr{Offset: e.Offset}
 		s += oe.Pretty(level)
@@ -226,6 +260,27 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: max_over_time(rate({foo="bar"}[5m])[1h:1m])
+func (e *SubqueryExpr) Pretty(level int) string {
+	s := Indent(level)
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation + "(\n"
+
+	if e.Params != nil {
+		s = fmt.Sprintf("%s%s%s,", s, Indent(level+1), fmt.Sprint(*e.Params))
+		s += "\n"
+	}
+
+	s += e.Left.Pretty(level+1) + e.rangeString()
+
+	s += "\n" + Indent(level) + ")"
+
+	return s
+}
+
 // e.g:
 // sum(count_over_time({foo="bar"}[5m])) by (container)
 // topk(10, count_over_time({foo="bar"}[5m])) by (container)
@@ -248,9 +303,12 @@ func (e *VectorAggregationExpr) Pretty(level int) string {
 	left := e.Left.Pretty(level + 1)
 	switch e.Operation {
 	// e.Params default value (0) can mean a legit param for topk and bottomk
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK:
 		params = []string{fmt.Sprintf("%s%d", Indent(level+1), e.Params), left}
 
+	case OpTypeApproxCountDistinct:
+		params = []string{Indent(level+1) + e.Label, left}
+
 	default:
 		if e.Params != 0 {
 			params = []string{fmt.Sprintf("%s%d", Indent(level+1), e.Params), left}
@@ -338,6 +396,86 @@ func (e *LabelReplaceExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: label_join(rate({job="api-server"}[5m]), "foo", ",", "job", "service")
+func (e *LabelJoinExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += OpLabelJoin
+
+	s += "(\n"
+
+	params := []string{
+		e.Left.Pretty(level + 1),
+		Indent(level+1) + strconv.Quote(e.Dst),
+		Indent(level+1) + strconv.Quote(e.Separator),
+	}
+	for _, src := range e.Src {
+		params = append(params, Indent(level+1)+strconv.Quote(src))
+	}
+
+	for i, v := range params {
+		s += v
+		// LogQL doesn't allow `,` at the end of last argument.
+		if i < len(params)-1 {
+			s += ","
+		}
+		s += "\n"
+	}
+
+	s += Indent(level) + ")"
+
+	return s
+}
+
+// e.g: histogram_quantile(0.99, sum by (le) (sum_over_time({job="api-server"} | unwrap bucket [5m])))
+func (e *HistogramQuantileExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += OpHistogramQuantile + "(\n"
+	s += Indent(level+1) + strconv.FormatFloat(e.Quantile, 'f', -1, 64) + ",\n"
+	s += e.Left.Pretty(level+1) + "\n"
+	s += Indent(level) + ")"
+
+	return s
+}
+
+// e.g: clamp_min(sum(rate({job="api-server"}[5m])), 0)
+func (e *FunctionExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if e.Left == nil || !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Function + "(\n"
+
+	params := []string{e.Left.Pretty(level + 1)}
+	for _, arg := range e.Args {
+		params = append(params, Indent(level+1)+strconv.FormatFloat(arg, 'f', -1, 64))
+	}
+
+	for i, v := range params {
+		s += v
+		// LogQL doesn't allow `,` at the end of last argument.
+		if i < len(params)-1 {
+			s += ","
+		}
+		s += "\n"
+	}
+
+	s += Indent(level) + ")"
+
+	return s
+}
+
 // e.g: vector(5)
 func (e *VectorExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
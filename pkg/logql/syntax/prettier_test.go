@@ -49,6 +49,28 @@ func TestFormat(t *testing.T) {
 			exp: `{job="loki", instance="localhost"}
   | logfmt
   | label_format dst="{{.src}}"`,
+		},
+		{
+			name: "pipeline_stats",
+			in:   `{job="loki", instance="localhost"}|logfmt|stats count(),max(duration) by (level)`,
+			exp: `{job="loki", instance="localhost"}
+  | logfmt
+  | stats count(), max(duration) by (level)`,
+		},
+		{
+			name: "pipeline_around",
+			in:   `{job="loki", instance="localhost"}|="error"|around before=2 after=5`,
+			exp: `{job="loki", instance="localhost"}
+  |= "error"
+  | around before=2 after=5`,
+		},
+		{
+			name: "pipeline_limit_dedup",
+			in:   `{job="loki", instance="localhost"}|="error"|dedup|limit 10 by (pod)`,
+			exp: `{job="loki", instance="localhost"}
+  |= "error"
+  | dedup
+  | limit 10 by (pod)`,
 		},
 		{
 			name: "aggregation",
@@ -64,6 +86,14 @@ func TestFormat(t *testing.T) {
 			exp: `count_over_time(
   {job="loki", instance="localhost"}
     |= "error" [5m] offset 20m
+)`,
+		},
+		{
+			name: "aggregation_with_at_modifier",
+			in:   `count_over_time({job="loki", instance="localhost"}|= "error"[5m] @ 1609746000 offset 20m)`,
+			exp: `count_over_time(
+  {job="loki", instance="localhost"}
+    |= "error" [5m] @ 1609746000 offset 20m
 )`,
 		},
 		{
@@ -132,6 +162,28 @@ func TestFormat_VectorAggregation(t *testing.T) {
   count_over_time(
     {foo="bar", namespace="loki", instance="localhost"} [5m]
   )
+)`,
+		},
+		{
+			name: "approx_topk",
+			in:   `approx_topk(5, sum by (user_id) (count_over_time({foo="bar"}[5m])))`,
+			exp: `approx_topk(
+  5,
+  sum by (user_id)(
+    count_over_time(
+      {foo="bar"} [5m]
+    )
+  )
+)`,
+		},
+		{
+			name: "approx_count_distinct",
+			in:   `approx_count_distinct by (app) (user_id, count_over_time({foo="bar", namespace="loki", instance="localhost"}[5m]))`,
+			exp: `approx_count_distinct by (app)(
+  user_id,
+  count_over_time(
+    {foo="bar", namespace="loki", instance="localhost"} [5m]
+  )
 )`,
 		},
 	}
@@ -200,6 +252,69 @@ func TestFormat_LabelReplace(t *testing.T) {
 	}
 }
 
+func TestFormat_Function(t *testing.T) {
+	MaxCharsPerLine = 20
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "clamp_min",
+			in:   `clamp_min(rate({job="api-server",service="a:c"}|= "err" [5m]), 0.5)`,
+			exp: `clamp_min(
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  ),
+  0.5
+)`,
+		},
+		{
+			name: "hour",
+			in:   `hour()`,
+			exp:  `hour()`,
+		},
+		{
+			name: "label_join",
+			in:   `label_join(rate({job="api-server",service="a:c"}|= "err" [5m]), "foo", ",", "job", "service")`,
+			exp: `label_join(
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  ),
+  "foo",
+  ",",
+  "job",
+  "service"
+)`,
+		},
+		{
+			name: "histogram_quantile",
+			in:   `histogram_quantile(0.99, sum by (le) (rate({job="api-server",service="a:c"}|= "err" [5m])))`,
+			exp: `histogram_quantile(
+  0.99,
+  sum by (le)(
+    rate(
+      {job="api-server", service="a:c"}
+        |= "err" [5m]
+    )
+  )
+)`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, err := ParseExpr(c.in)
+			require.NoError(t, err)
+			got := Prettify(expr)
+			assert.Equal(t, c.exp, got)
+		})
+	}
+}
+
 func TestFormat_BinOp(t *testing.T) {
 	MaxCharsPerLine = 20
 
@@ -35,10 +35,13 @@ const (
 	Binary              = "binary"
 	Bytes               = "bytes"
 	And                 = "and"
+	At                  = "at"
 	Card                = "cardinality"
 	Dst                 = "dst"
 	Duration            = "duration"
+	Function            = "function"
 	Groups              = "groups"
+	HistogramQuantile   = "histogram_quantile"
 	GroupingField       = "grouping"
 	Include             = "include"
 	Identifier          = "identifier"
@@ -46,6 +49,7 @@ const (
 	IntervalNanos       = "interval_nanos"
 	IPField             = "ip"
 	Label               = "label"
+	LabelJoin           = "label_join"
 	LabelReplace        = "label_replace"
 	LHS                 = "lhs"
 	Literal             = "literal"
@@ -59,6 +63,7 @@ const (
 	OffsetNanos         = "offset_nanos"
 	Params              = "params"
 	Pattern             = "pattern"
+	Quantile            = "quantile"
 	PostFilterers       = "post_filterers"
 	Range               = "range"
 	RangeAgg            = "range_agg"
@@ -67,8 +72,13 @@ const (
 	Replacement         = "replacement"
 	ReturnBool          = "return_bool"
 	RHS                 = "rhs"
+	Separator           = "separator"
 	Src                 = "src"
+	StartOrEnd          = "start_or_end"
+	StepNanos           = "step_nanos"
 	StringField         = "string"
+	Subquery            = "subquery"
+	TimestampNanos      = "timestamp_nanos"
 	NoopField           = "noop"
 	Type                = "type"
 	Unwrap              = "unwrap"
@@ -96,6 +106,14 @@ func DecodeJSON(raw string) (Expr, error) {
 		return decodeVector(iter)
 	case LabelReplace:
 		return decodeLabelReplace(iter)
+	case LabelJoin:
+		return decodeLabelJoin(iter)
+	case HistogramQuantile:
+		return decodeHistogramQuantile(iter)
+	case Subquery:
+		return decodeSubquery(iter)
+	case Function:
+		return decodeFunction(iter)
 	case LogSelector:
 		return decodeLogSelector(iter)
 	default:
@@ -155,6 +173,12 @@ func (v *JSONSerializer) VisitVectorAggregation(e *VectorAggregationExpr) {
 	v.WriteObjectField(Params)
 	v.WriteInt(e.Params)
 
+	if e.Label != "" {
+		v.WriteMore()
+		v.WriteObjectField(Label)
+		v.WriteString(e.Label)
+	}
+
 	v.WriteMore()
 	v.WriteObjectField(Op)
 	v.WriteString(e.Operation)
@@ -213,6 +237,12 @@ func (v *JSONSerializer) VisitLogRange(e *LogRange) {
 	v.WriteObjectField(OffsetNanos)
 	v.WriteInt64(int64(e.Offset))
 
+	if e.At != nil {
+		v.WriteMore()
+		v.WriteObjectField(At)
+		encodeAtModifier(v.Stream, e.At)
+	}
+
 	// Serialize log selector pipeline as string.
 	v.WriteMore()
 	v.WriteObjectField(LogSelector)
@@ -258,6 +288,130 @@ func (v *JSONSerializer) VisitLabelReplace(e *LabelReplaceExpr) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitLabelJoin(e *LabelJoinExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(LabelJoin)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteMore()
+	v.WriteObjectField(Dst)
+	v.WriteString(e.Dst)
+
+	v.WriteMore()
+	v.WriteObjectField(Separator)
+	v.WriteString(e.Separator)
+
+	v.WriteMore()
+	v.WriteObjectField(Src)
+	v.WriteArrayStart()
+	for i, src := range e.Src {
+		if i > 0 {
+			v.WriteMore()
+		}
+		v.WriteString(src)
+	}
+	v.WriteArrayEnd()
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitHistogramQuantile(e *HistogramQuantileExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(HistogramQuantile)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Quantile)
+	v.WriteFloat64(e.Quantile)
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitSubquery(e *SubqueryExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Subquery)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Op)
+	v.WriteString(e.Operation)
+
+	if e.Params != nil {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteFloat64(*e.Params)
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(IntervalNanos)
+	v.WriteInt64(int64(e.Range))
+	v.WriteMore()
+	v.WriteObjectField(StepNanos)
+	v.WriteInt64(int64(e.Step))
+	v.WriteMore()
+	v.WriteObjectField(OffsetNanos)
+	v.WriteInt64(int64(e.Offset))
+
+	if e.At != nil {
+		v.WriteMore()
+		v.WriteObjectField(At)
+		encodeAtModifier(v.Stream, e.At)
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitFunction(e *FunctionExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Function)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Name)
+	v.WriteString(e.Function)
+
+	if len(e.Args) > 0 {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteArrayStart()
+		for i, arg := range e.Args {
+			if i > 0 {
+				v.WriteMore()
+			}
+			v.WriteFloat64(arg)
+		}
+		v.WriteArrayEnd()
+	}
+
+	if e.Left != nil {
+		v.WriteMore()
+		v.WriteObjectField(Inner)
+		e.Left.Accept(v)
+	}
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 func (v *JSONSerializer) VisitLiteral(e *LiteralExpr) {
 	v.WriteObjectStart()
 
@@ -306,17 +460,23 @@ func (v *JSONSerializer) VisitPipeline(e *PipelineExpr) {
 
 // Below are StageExpr visitors that we are skipping since a pipeline is
 // serialized as a string.
+func (*JSONSerializer) VisitAround(*AroundExpr)                             {}
+func (*JSONSerializer) VisitCSVExpressionParser(*CSVExpressionParser)       {}
 func (*JSONSerializer) VisitDecolorize(*DecolorizeExpr)                     {}
+func (*JSONSerializer) VisitDedup(*DedupExpr)                               {}
 func (*JSONSerializer) VisitDropLabels(*DropLabelsExpr)                     {}
 func (*JSONSerializer) VisitJSONExpressionParser(*JSONExpressionParser)     {}
 func (*JSONSerializer) VisitKeepLabel(*KeepLabelsExpr)                      {}
 func (*JSONSerializer) VisitLabelFilter(*LabelFilterExpr)                   {}
 func (*JSONSerializer) VisitLabelFmt(*LabelFmtExpr)                         {}
 func (*JSONSerializer) VisitLabelParser(*LabelParserExpr)                   {}
+func (*JSONSerializer) VisitLimitBy(*LimitByExpr)                           {}
 func (*JSONSerializer) VisitLineFilter(*LineFilterExpr)                     {}
 func (*JSONSerializer) VisitLineFmt(*LineFmtExpr)                           {}
 func (*JSONSerializer) VisitLogfmtExpressionParser(*LogfmtExpressionParser) {}
 func (*JSONSerializer) VisitLogfmtParser(*LogfmtParserExpr)                 {}
+func (*JSONSerializer) VisitStats(*StatsExpr)                               {}
+func (*JSONSerializer) VisitXMLExpressionParser(*XMLExpressionParser)       {}
 
 func encodeGrouping(s *jsoniter.Stream, g *Grouping) {
 	s.WriteObjectStart()
@@ -395,6 +555,31 @@ func decodeUnwrap(iter *jsoniter.Iterator) *UnwrapExpr {
 	return e
 }
 
+func encodeAtModifier(s *jsoniter.Stream, a *AtModifier) {
+	s.WriteObjectStart()
+	if a.StartOrEnd != "" {
+		s.WriteObjectField(StartOrEnd)
+		s.WriteString(a.StartOrEnd)
+	} else {
+		s.WriteObjectField(TimestampNanos)
+		s.WriteInt64(a.Timestamp.UnixNano())
+	}
+	s.WriteObjectEnd()
+}
+
+func decodeAtModifier(iter *jsoniter.Iterator) *AtModifier {
+	a := &AtModifier{}
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case StartOrEnd:
+			a.StartOrEnd = iter.ReadString()
+		case TimestampNanos:
+			a.Timestamp = time.Unix(0, iter.ReadInt64())
+		}
+	}
+	return a
+}
+
 func encodeLabelFilter(s *jsoniter.Stream, filter log.LabelFilterer) {
 	switch concrete := filter.(type) {
 	case *log.BinaryLabelFilter:
@@ -717,6 +902,14 @@ func decodeSample(iter *jsoniter.Iterator) (SampleExpr, error) {
 			expr, err = decodeVector(iter)
 		case LabelReplace:
 			expr, err = decodeLabelReplace(iter)
+		case LabelJoin:
+			expr, err = decodeLabelJoin(iter)
+		case HistogramQuantile:
+			expr, err = decodeHistogramQuantile(iter)
+		case Subquery:
+			expr, err = decodeSubquery(iter)
+		case Function:
+			expr, err = decodeFunction(iter)
 		default:
 			return nil, fmt.Errorf("unknown sample expression type: %s", key)
 		}
@@ -827,6 +1020,8 @@ func decodeVectorAgg(iter *jsoniter.Iterator) (*VectorAggregationExpr, error) {
 			expr.Operation = iter.ReadString()
 		case Params:
 			expr.Params = iter.ReadInt()
+		case Label:
+			expr.Label = iter.ReadString()
 		case GroupingField:
 			expr.Grouping, err = decodeGrouping(iter)
 		case Inner:
@@ -870,6 +1065,8 @@ func decodeLogRange(iter *jsoniter.Iterator) (*LogRange, error) {
 			expr.Interval = time.Duration(iter.ReadInt64())
 		case OffsetNanos:
 			expr.Offset = time.Duration(iter.ReadInt64())
+		case At:
+			expr.At = decodeAtModifier(iter)
 		case Unwrap:
 			expr.Unwrap = decodeUnwrap(iter)
 		}
@@ -904,6 +1101,105 @@ func decodeLabelReplace(iter *jsoniter.Iterator) (*LabelReplaceExpr, error) {
 	return mustNewLabelReplaceExpr(left, dst, replacement, src, regex), nil
 }
 
+func decodeLabelJoin(iter *jsoniter.Iterator) (*LabelJoinExpr, error) {
+	var err error
+	var left SampleExpr
+	var dst, separator string
+	var src []string
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Inner:
+			left, err = decodeSample(iter)
+			if err != nil {
+				return nil, err
+			}
+		case Dst:
+			dst = iter.ReadString()
+		case Separator:
+			separator = iter.ReadString()
+		case Src:
+			for iter.ReadArray() {
+				src = append(src, iter.ReadString())
+			}
+		}
+	}
+
+	return mustNewLabelJoinExpr(left, dst, separator, src), nil
+}
+
+func decodeHistogramQuantile(iter *jsoniter.Iterator) (*HistogramQuantileExpr, error) {
+	expr := &HistogramQuantileExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Quantile:
+			expr.Quantile = iter.ReadFloat64()
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return expr, err
+}
+
+func decodeSubquery(iter *jsoniter.Iterator) (*SubqueryExpr, error) {
+	expr := &SubqueryExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Op:
+			expr.Operation = iter.ReadString()
+		case Params:
+			tmp := iter.ReadFloat64()
+			expr.Params = &tmp
+		case IntervalNanos:
+			expr.Range = time.Duration(iter.ReadInt64())
+		case StepNanos:
+			expr.Step = time.Duration(iter.ReadInt64())
+		case OffsetNanos:
+			expr.Offset = time.Duration(iter.ReadInt64())
+		case At:
+			expr.At = decodeAtModifier(iter)
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return expr, err
+}
+
+func decodeFunction(iter *jsoniter.Iterator) (*FunctionExpr, error) {
+	expr := &FunctionExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Name:
+			expr.Function = iter.ReadString()
+		case Params:
+			for iter.ReadArray() {
+				expr.Args = append(expr.Args, iter.ReadFloat64())
+			}
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return expr, err
+}
+
 func decodeLiteral(iter *jsoniter.Iterator) (*LiteralExpr, error) {
 	expr := &LiteralExpr{}
 
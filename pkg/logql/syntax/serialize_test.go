@@ -30,6 +30,15 @@ func TestJSONSerializationRoundTrip(t *testing.T) {
 		"regexp": {
 			query: `{env="prod", app=~"loki.*"} |~ ".*foo.*"`,
 		},
+		"stats": {
+			query: `{app="foo"} | json | stats count(), percentile(0.99, latency) by (status, route)`,
+		},
+		"around": {
+			query: `{app="foo"} |= "error" | around 3`,
+		},
+		"limit and dedup": {
+			query: `{app="foo"} |= "error" | dedup | limit 10 by (pod)`,
+		},
 		"line filter": {
 			query: `{env="prod", app=~"loki.*"} |= "foo" |= "bar" or "baz" | line_format "blip{{ .foo }}blop" |= "blip"`,
 		},
@@ -39,9 +48,33 @@ func TestJSONSerializationRoundTrip(t *testing.T) {
 		"sum over or vector": {
 			query: `(sum(count_over_time({foo="bar"}[5m])) or vector(1.000000))`,
 		},
+		"subquery": {
+			query: `quantile_over_time(0.99, sum by (app) (rate({app="api"} |= "error" [1m]))[1h:5m] offset 1d)`,
+		},
+		"at modifier": {
+			query: `sum(rate({app="api"}[5m] @ 1609746000)) / sum(rate({app="api"}[5m] @ start() offset 1h))`,
+		},
+		"subquery at modifier": {
+			query: `max_over_time(sum(rate({app="api"}[1m]))[1h:] @ end())`,
+		},
+		"functions": {
+			query: `round(clamp_max(sum by (app) (rate({app="api"}[1m])), 10), 0.5) and on() (day_of_week() < 6)`,
+		},
+		"approx topk": {
+			query: `approx_topk(10, sum by (user_id) (count_over_time({app="api"}[5m])))`,
+		},
+		"approx count distinct": {
+			query: `approx_count_distinct by (app) (user_id, count_over_time({app="api"} | json [5m]))`,
+		},
 		"label replace": {
 			query: `label_replace(vector(0.000000),"foo","bar","","")`,
 		},
+		"label join": {
+			query: `label_join(rate({app="api"}[1m]),"dst","-","app","pod")`,
+		},
+		"histogram quantile": {
+			query: `histogram_quantile(0.99,sum by (le)(sum_over_time({app="api"} | unwrap count[1m])))`,
+		},
 		"filters with bytes": {
 			query: `{app="foo"} |= "bar" | json | ( status_code <500 or ( status_code>200 , size>=2.5KiB ) )`,
 		},
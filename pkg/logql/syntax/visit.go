@@ -14,10 +14,14 @@ type RootVisitor interface {
 
 type SampleExprVisitor interface {
 	VisitBinOp(*BinOpExpr)
+	VisitFunction(*FunctionExpr)
+	VisitHistogramQuantile(*HistogramQuantileExpr)
 	VisitVectorAggregation(*VectorAggregationExpr)
 	VisitRangeAggregation(*RangeAggregationExpr)
+	VisitLabelJoin(*LabelJoinExpr)
 	VisitLabelReplace(*LabelReplaceExpr)
 	VisitLiteral(*LiteralExpr)
+	VisitSubquery(*SubqueryExpr)
 	VisitVector(*VectorExpr)
 }
 
@@ -29,31 +33,44 @@ type LogSelectorExprVisitor interface {
 }
 
 type StageExprVisitor interface {
+	VisitAround(*AroundExpr)
+	VisitCSVExpressionParser(*CSVExpressionParser)
 	VisitDecolorize(*DecolorizeExpr)
+	VisitDedup(*DedupExpr)
 	VisitDropLabels(*DropLabelsExpr)
 	VisitJSONExpressionParser(*JSONExpressionParser)
 	VisitKeepLabel(*KeepLabelsExpr)
 	VisitLabelFilter(*LabelFilterExpr)
 	VisitLabelFmt(*LabelFmtExpr)
 	VisitLabelParser(*LabelParserExpr)
+	VisitLimitBy(*LimitByExpr)
 	VisitLineFilter(*LineFilterExpr)
 	VisitLineFmt(*LineFmtExpr)
 	VisitLogfmtExpressionParser(*LogfmtExpressionParser)
 	VisitLogfmtParser(*LogfmtParserExpr)
+	VisitStats(*StatsExpr)
+	VisitXMLExpressionParser(*XMLExpressionParser)
 }
 
 var _ RootVisitor = &DepthFirstTraversal{}
 
 type DepthFirstTraversal struct {
+	VisitAroundFn                 func(v RootVisitor, e *AroundExpr)
 	VisitBinOpFn                  func(v RootVisitor, e *BinOpExpr)
+	VisitCSVExpressionParserFn    func(v RootVisitor, e *CSVExpressionParser)
 	VisitDecolorizeFn             func(v RootVisitor, e *DecolorizeExpr)
+	VisitDedupFn                  func(v RootVisitor, e *DedupExpr)
 	VisitDropLabelsFn             func(v RootVisitor, e *DropLabelsExpr)
+	VisitFunctionFn               func(v RootVisitor, e *FunctionExpr)
+	VisitHistogramQuantileFn      func(v RootVisitor, e *HistogramQuantileExpr)
 	VisitJSONExpressionParserFn   func(v RootVisitor, e *JSONExpressionParser)
 	VisitKeepLabelFn              func(v RootVisitor, e *KeepLabelsExpr)
 	VisitLabelFilterFn            func(v RootVisitor, e *LabelFilterExpr)
 	VisitLabelFmtFn               func(v RootVisitor, e *LabelFmtExpr)
+	VisitLabelJoinFn              func(v RootVisitor, e *LabelJoinExpr)
 	VisitLabelParserFn            func(v RootVisitor, e *LabelParserExpr)
 	VisitLabelReplaceFn           func(v RootVisitor, e *LabelReplaceExpr)
+	VisitLimitByFn                func(v RootVisitor, e *LimitByExpr)
 	VisitLineFilterFn             func(v RootVisitor, e *LineFilterExpr)
 	VisitLineFmtFn                func(v RootVisitor, e *LineFmtExpr)
 	VisitLiteralFn                func(v RootVisitor, e *LiteralExpr)
@@ -63,8 +80,11 @@ type DepthFirstTraversal struct {
 	VisitMatchersFn               func(v RootVisitor, e *MatchersExpr)
 	VisitPipelineFn               func(v RootVisitor, e *PipelineExpr)
 	VisitRangeAggregationFn       func(v RootVisitor, e *RangeAggregationExpr)
+	VisitStatsFn                  func(v RootVisitor, e *StatsExpr)
+	VisitSubqueryFn               func(v RootVisitor, e *SubqueryExpr)
 	VisitVectorFn                 func(v RootVisitor, e *VectorExpr)
 	VisitVectorAggregationFn      func(v RootVisitor, e *VectorAggregationExpr)
+	VisitXMLExpressionParserFn    func(v RootVisitor, e *XMLExpressionParser)
 }
 
 // VisitBinOp implements RootVisitor.
@@ -80,6 +100,26 @@ func (v *DepthFirstTraversal) VisitBinOp(e *BinOpExpr) {
 	}
 }
 
+// VisitAround implements RootVisitor.
+func (v *DepthFirstTraversal) VisitAround(e *AroundExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitAroundFn != nil {
+		v.VisitAroundFn(v, e)
+	}
+}
+
+// VisitCSVExpressionParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitCSVExpressionParser(e *CSVExpressionParser) {
+	if e == nil {
+		return
+	}
+	if v.VisitCSVExpressionParserFn != nil {
+		v.VisitCSVExpressionParserFn(v, e)
+	}
+}
+
 // VisitDecolorize implements RootVisitor.
 func (v *DepthFirstTraversal) VisitDecolorize(e *DecolorizeExpr) {
 	if e == nil {
@@ -90,6 +130,16 @@ func (v *DepthFirstTraversal) VisitDecolorize(e *DecolorizeExpr) {
 	}
 }
 
+// VisitDedup implements RootVisitor.
+func (v *DepthFirstTraversal) VisitDedup(e *DedupExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitDedupFn != nil {
+		v.VisitDedupFn(v, e)
+	}
+}
+
 // VisitDropLabels implements RootVisitor.
 func (v *DepthFirstTraversal) VisitDropLabels(e *DropLabelsExpr) {
 	if e == nil {
@@ -100,6 +150,30 @@ func (v *DepthFirstTraversal) VisitDropLabels(e *DropLabelsExpr) {
 	}
 }
 
+// VisitFunction implements RootVisitor.
+func (v *DepthFirstTraversal) VisitFunction(e *FunctionExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitFunctionFn != nil {
+		v.VisitFunctionFn(v, e)
+	} else if e.Left != nil {
+		e.Left.Accept(v)
+	}
+}
+
+// VisitHistogramQuantile implements RootVisitor.
+func (v *DepthFirstTraversal) VisitHistogramQuantile(e *HistogramQuantileExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitHistogramQuantileFn != nil {
+		v.VisitHistogramQuantileFn(v, e)
+	} else if e.Left != nil {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitJSONExpressionParser implements RootVisitor.
 func (v *DepthFirstTraversal) VisitJSONExpressionParser(e *JSONExpressionParser) {
 	if e == nil {
@@ -140,6 +214,18 @@ func (v *DepthFirstTraversal) VisitLabelFmt(e *LabelFmtExpr) {
 	}
 }
 
+// VisitLabelJoin implements RootVisitor.
+func (v *DepthFirstTraversal) VisitLabelJoin(e *LabelJoinExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitLabelJoinFn != nil {
+		v.VisitLabelJoinFn(v, e)
+	} else if e.Left != nil {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitLabelParser implements RootVisitor.
 func (v *DepthFirstTraversal) VisitLabelParser(e *LabelParserExpr) {
 	if e == nil {
@@ -160,6 +246,16 @@ func (v *DepthFirstTraversal) VisitLabelReplace(e *LabelReplaceExpr) {
 	}
 }
 
+// VisitLimitBy implements RootVisitor.
+func (v *DepthFirstTraversal) VisitLimitBy(e *LimitByExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitLimitByFn != nil {
+		v.VisitLimitByFn(v, e)
+	}
+}
+
 // VisitLineFilter implements RootVisitor.
 func (v *DepthFirstTraversal) VisitLineFilter(e *LineFilterExpr) {
 	if e == nil {
@@ -266,6 +362,28 @@ func (v *DepthFirstTraversal) VisitRangeAggregation(e *RangeAggregationExpr) {
 	}
 }
 
+// VisitStats implements RootVisitor.
+func (v *DepthFirstTraversal) VisitStats(e *StatsExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitStatsFn != nil {
+		v.VisitStatsFn(v, e)
+	}
+}
+
+// VisitSubquery implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSubquery(e *SubqueryExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSubqueryFn != nil {
+		v.VisitSubqueryFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitVector implements RootVisitor.
 func (v *DepthFirstTraversal) VisitVector(e *VectorExpr) {
 	if e == nil {
@@ -287,3 +405,13 @@ func (v *DepthFirstTraversal) VisitVectorAggregation(e *VectorAggregationExpr) {
 		e.Left.Accept(v)
 	}
 }
+
+// VisitXMLExpressionParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitXMLExpressionParser(e *XMLExpressionParser) {
+	if e == nil {
+		return
+	}
+	if v.VisitXMLExpressionParserFn != nil {
+		v.VisitXMLExpressionParserFn(v, e)
+	}
+}
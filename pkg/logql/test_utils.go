@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/iter"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/sketch"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
 )
@@ -227,7 +228,7 @@ type MockDownstreamer struct {
 
 func (m MockDownstreamer) Downstreamer(_ context.Context) Downstreamer { return m }
 
-func (m MockDownstreamer) Downstream(ctx context.Context, queries []DownstreamQuery, _ Accumulator) ([]logqlmodel.Result, error) {
+func (m MockDownstreamer) Downstream(ctx context.Context, queries []DownstreamQuery, acc Accumulator) ([]logqlmodel.Result, error) {
 	results := make([]logqlmodel.Result, 0, len(queries))
 	for _, query := range queries {
 		res, err := m.Query(query.Params).Exec(ctx)
@@ -246,6 +247,15 @@ func (m MockDownstreamer) Downstream(ctx context.Context, queries []DownstreamQu
 		}
 		return []logqlmodel.Result{{Data: matrix}}, nil
 	}
+	switch results[0].Data.(type) {
+	case sketch.TopKMatrix, CountDistinctSketchMatrix:
+		for i, res := range results {
+			if err := acc.Accumulate(ctx, res, i); err != nil {
+				return nil, err
+			}
+		}
+		return acc.Result(), nil
+	}
 	return results, nil
 }
 
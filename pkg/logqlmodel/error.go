@@ -92,6 +92,12 @@ func NewSeriesLimitError(limit int) *LimitError {
 	}
 }
 
+func NewStatsEntriesLimitError(limit int) *LimitError {
+	return &LimitError{
+		error: fmt.Errorf("maximum of log entries (%d) aggregated by a stats query reached, reduce the time range of the query", limit),
+	}
+}
+
 // Is allows to use errors.Is(err,ErrLimit) on this error.
 func (e LimitError) Is(target error) bool {
 	return target == ErrLimit
@@ -13,6 +13,9 @@ import (
 // ValueTypeStreams promql.ValueType for log streams
 const ValueTypeStreams = "streams"
 
+// ValueTypeTable promql.ValueType for the tables of log queries with a stats stage
+const ValueTypeTable = "table"
+
 // PackedEntryKey is a special JSON key used by the pack promtail stage and unpack parser
 const PackedEntryKey = "_entry"
 
@@ -48,3 +51,19 @@ func (streams Streams) Lines() int64 {
 	}
 	return res
 }
+
+// Table is promql.Value for the result of a log query ending with a stats
+// stage. Each row holds the values of the grouping labels followed by the
+// values of the aggregations, in the order of Columns.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Type implements `promql.Value` and `parser.Value`
+func (Table) Type() parser.ValueType { return ValueTypeTable }
+
+// String implements `promql.Value` and `parser.Value`
+func (Table) String() string {
+	return ""
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/dns"
+	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/kv/codec"
 	"github.com/grafana/dskit/kv/memberlist"
 	"github.com/grafana/dskit/middleware"
@@ -98,6 +99,7 @@ const maxChunkAgeForTableManager = 12 * time.Hour
 // The various modules that make up Loki.
 const (
 	Ring                     string = "ring"
+	PartitionRing            string = "partition-ring"
 	RuntimeConfig            string = "runtime-config"
 	Overrides                string = "overrides"
 	OverridesExporter        string = "overrides-exporter"
@@ -265,6 +267,27 @@ func (t *Loki) initRing() (_ services.Service, err error) {
 	return t.ring, nil
 }
 
+func (t *Loki) initPartitionRing() (services.Service, error) {
+	if !t.Cfg.Distributor.KafkaEnabled && !t.Cfg.Ingester.KafkaIngestion.Enabled {
+		return nil, nil
+	}
+
+	kvClient, err := kv.NewClient(t.Cfg.Ingester.LifecyclerConfig.RingConfig.KVStore, ring.GetPartitionRingCodec(), kv.RegistererWithKVName(prometheus.DefaultRegisterer, ingester.PartitionRingName+"-watcher"), util_log.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating KV store for partition ring watcher: %w", err)
+	}
+
+	watcher := ring.NewPartitionRingWatcher(ingester.PartitionRingName, ingester.PartitionRingKey, kvClient, util_log.Logger, prometheus.WrapRegistererWithPrefix(t.Cfg.MetricsNamespace+"_", prometheus.DefaultRegisterer))
+	t.partitionRingWatcher = watcher
+
+	handler := ring.NewPartitionRingPageHandler(watcher, ring.NewPartitionRingEditor(ingester.PartitionRingKey, kvClient))
+	t.Server.HTTP.Path("/partition-ring").Methods("GET", "POST").Handler(handler)
+	if t.Cfg.InternalServer.Enable {
+		t.InternalServer.HTTP.Path("/partition-ring").Methods("GET", "POST").Handler(handler)
+	}
+	return watcher, nil
+}
+
 func (t *Loki) initRuntimeConfig() (services.Service, error) {
 	if len(t.Cfg.RuntimeConfig.LoadPath) == 0 {
 		if len(t.Cfg.LimitsConfig.PerTenantOverrideConfig) != 0 {
@@ -344,11 +367,13 @@ func (t *Loki) initDistributor() (services.Service, error) {
 
 	var err error
 	logger := log.With(util_log.Logger, "component", "distributor")
+	t.Cfg.Distributor.KafkaConfig = t.Cfg.KafkaConfig
 	t.distributor, err = distributor.New(
 		t.Cfg.Distributor,
 		t.Cfg.IngesterClient,
 		t.tenantConfigs,
 		t.ring,
+		t.partitionRingWatcher,
 		t.Overrides,
 		prometheus.DefaultRegisterer,
 		t.Cfg.MetricsNamespace,
@@ -387,6 +412,7 @@ func (t *Loki) initDistributor() (services.Service, error) {
 	t.Server.HTTP.Path("/api/prom/push").Methods("POST").Handler(lokiPushHandler)
 	t.Server.HTTP.Path("/loki/api/v1/push").Methods("POST").Handler(lokiPushHandler)
 	t.Server.HTTP.Path("/otlp/v1/logs").Methods("POST").Handler(otlpPushHandler)
+	t.Server.HTTP.Path("/loki/api/v1/sampling_hints").Methods("GET").Handler(httpPushHandlerMiddleware.Wrap(http.HandlerFunc(t.distributor.SamplingHintsHandler)))
 	return t.distributor, nil
 }
 
@@ -572,6 +598,13 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	// on the external router.
 	t.Server.HTTP.Path("/loki/api/v1/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.querierAPI.TailHandler)))
 	t.Server.HTTP.Path("/api/prom/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.querierAPI.TailHandler)))
+	// Lookups only read the structured metadata index of the ingesters and are handled directly by the queriers too.
+	t.Server.HTTP.Path("/loki/api/v1/lookup").Methods("GET", "POST").Handler(
+		middleware.Merge(
+			httpMiddleware,
+			querier.WrapQuerySpanAndTimeout("query.Lookup", t.Overrides),
+		).Wrap(http.HandlerFunc(t.querierAPI.LookupHandler)),
+	)
 
 	internalMiddlewares := []queryrangebase.Middleware{
 		serverutil.RecoveryMiddleware,
@@ -614,7 +647,8 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 		level.Warn(util_log.Logger).Log("msg", "The config setting shutdown marker path is not set. The /ingester/prepare_shutdown endpoint won't work")
 	}
 
-	t.Ingester, err = ingester.New(t.Cfg.Ingester, t.Cfg.IngesterClient, t.Store, t.Overrides, t.tenantConfigs, prometheus.DefaultRegisterer, t.Cfg.Distributor.WriteFailuresLogging, t.Cfg.MetricsNamespace, logger, t.UsageTracker, t.ring)
+	t.Cfg.Ingester.KafkaIngestion.KafkaConfig = t.Cfg.KafkaConfig
+	t.Ingester, err = ingester.New(t.Cfg.Ingester, t.Cfg.IngesterClient, t.Store, t.Overrides, t.tenantConfigs, prometheus.DefaultRegisterer, t.Cfg.Distributor.WriteFailuresLogging, t.Cfg.MetricsNamespace, logger, t.UsageTracker, t.ring, t.partitionRingWatcher)
 	if err != nil {
 		return
 	}
@@ -626,6 +660,7 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 	logproto.RegisterPusherServer(t.Server.GRPC, t.Ingester)
 	logproto.RegisterQuerierServer(t.Server.GRPC, t.Ingester)
 	logproto.RegisterStreamDataServer(t.Server.GRPC, t.Ingester)
+	ingester.RegisterHandoffServer(t.Server.GRPC, t.Ingester)
 
 	httpMiddleware := middleware.Merge(
 		serverutil.RecoveryHTTPMiddleware,
@@ -639,6 +674,9 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 	t.Server.HTTP.Methods("POST", "GET").Path("/ingester/shutdown").Handler(
 		httpMiddleware.Wrap(http.HandlerFunc(t.Ingester.ShutdownHandler)),
 	)
+	t.Server.HTTP.Methods("GET").Path("/ingester/wal_replay").Handler(
+		httpMiddleware.Wrap(http.HandlerFunc(t.Ingester.WALReplayStatusHandler)),
+	)
 	return t.Ingester, nil
 }
 
@@ -1268,6 +1306,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		// defer tail endpoints to the default handler
 		t.Server.HTTP.Path("/loki/api/v1/tail").Methods("GET", "POST").Handler(defaultHandler)
 		t.Server.HTTP.Path("/api/prom/tail").Methods("GET", "POST").Handler(defaultHandler)
+		t.Server.HTTP.Path("/loki/api/v1/lookup").Methods("GET", "POST").Handler(defaultHandler)
 	}
 
 	if t.frontend == nil {
@@ -1437,6 +1476,7 @@ func (t *Loki) initMemberlistKV() (services.Service, error) {
 	t.Cfg.MemberlistKV.MetricsNamespace = constants.Loki
 	t.Cfg.MemberlistKV.Codecs = []codec.Codec{
 		ring.GetCodec(),
+		ring.GetPartitionRingCodec(),
 		analytics.JSONCodec,
 	}
 
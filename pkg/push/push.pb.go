@@ -5,6 +5,7 @@ package push
 
 import (
 	context "context"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -70,6 +71,11 @@ func (m *PushRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_PushRequest proto.InternalMessageInfo
 
 type PushResponse struct {
+	// rejectedStreams lists the streams of the request whose entries were rejected, partially or entirely.
+	RejectedStreams []RejectedStream `protobuf:"bytes,1,rep,name=rejectedStreams,proto3" json:"rejectedStreams,omitempty"`
+	// load is the load of the ingester which handled the push, 1 when it is fully loaded.
+	// It is reported to the distributors, which do not set it in their responses.
+	Load float64 `protobuf:"fixed64,2,opt,name=load,proto3" json:"load,omitempty"`
 }
 
 func (m *PushResponse) Reset()      { *m = PushResponse{} }
@@ -104,6 +110,99 @@ func (m *PushResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PushResponse proto.InternalMessageInfo
 
+func (m *PushResponse) GetRejectedStreams() []RejectedStream {
+	if m != nil {
+		return m.RejectedStreams
+	}
+	return nil
+}
+
+func (m *PushResponse) GetLoad() float64 {
+	if m != nil {
+		return m.Load
+	}
+	return 0
+}
+
+// RejectedStream describes the entries of a stream of a push request rejected for a given reason.
+type RejectedStream struct {
+	// index of the stream in the push request.
+	Index  int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index"`
+	Labels string `protobuf:"bytes,2,opt,name=labels,proto3" json:"labels"`
+	// reason is a stable code of the rejection reason, such as line_too_long or rate_limited.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason"`
+	// message is the error of the first rejected entry.
+	Message         string `protobuf:"bytes,4,opt,name=message,proto3" json:"message"`
+	RejectedEntries int32  `protobuf:"varint,5,opt,name=rejectedEntries,proto3" json:"rejectedEntries"`
+}
+
+func (m *RejectedStream) Reset()      { *m = RejectedStream{} }
+func (*RejectedStream) ProtoMessage() {}
+func (*RejectedStream) Descriptor() ([]byte, []int) {
+	return fileDescriptor_35ec442956852c9e, []int{2}
+}
+func (m *RejectedStream) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RejectedStream) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RejectedStream.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RejectedStream) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RejectedStream.Merge(m, src)
+}
+func (m *RejectedStream) XXX_Size() int {
+	return m.Size()
+}
+func (m *RejectedStream) XXX_DiscardUnknown() {
+	xxx_messageInfo_RejectedStream.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RejectedStream proto.InternalMessageInfo
+
+func (m *RejectedStream) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *RejectedStream) GetLabels() string {
+	if m != nil {
+		return m.Labels
+	}
+	return ""
+}
+
+func (m *RejectedStream) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *RejectedStream) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *RejectedStream) GetRejectedEntries() int32 {
+	if m != nil {
+		return m.RejectedEntries
+	}
+	return 0
+}
+
 type StreamAdapter struct {
 	Labels  string         `protobuf:"bytes,1,opt,name=labels,proto3" json:"labels"`
 	Entries []EntryAdapter `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries"`
@@ -114,7 +213,7 @@ type StreamAdapter struct {
 func (m *StreamAdapter) Reset()      { *m = StreamAdapter{} }
 func (*StreamAdapter) ProtoMessage() {}
 func (*StreamAdapter) Descriptor() ([]byte, []int) {
-	return fileDescriptor_35ec442956852c9e, []int{2}
+	return fileDescriptor_35ec442956852c9e, []int{3}
 }
 func (m *StreamAdapter) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -172,7 +271,7 @@ type LabelPairAdapter struct {
 func (m *LabelPairAdapter) Reset()      { *m = LabelPairAdapter{} }
 func (*LabelPairAdapter) ProtoMessage() {}
 func (*LabelPairAdapter) Descriptor() ([]byte, []int) {
-	return fileDescriptor_35ec442956852c9e, []int{3}
+	return fileDescriptor_35ec442956852c9e, []int{4}
 }
 func (m *LabelPairAdapter) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -228,7 +327,7 @@ type EntryAdapter struct {
 func (m *EntryAdapter) Reset()      { *m = EntryAdapter{} }
 func (*EntryAdapter) ProtoMessage() {}
 func (*EntryAdapter) Descriptor() ([]byte, []int) {
-	return fileDescriptor_35ec442956852c9e, []int{4}
+	return fileDescriptor_35ec442956852c9e, []int{5}
 }
 func (m *EntryAdapter) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -288,6 +387,7 @@ func (m *EntryAdapter) GetParsed() []LabelPairAdapter {
 func init() {
 	proto.RegisterType((*PushRequest)(nil), "logproto.PushRequest")
 	proto.RegisterType((*PushResponse)(nil), "logproto.PushResponse")
+	proto.RegisterType((*RejectedStream)(nil), "logproto.RejectedStream")
 	proto.RegisterType((*StreamAdapter)(nil), "logproto.StreamAdapter")
 	proto.RegisterType((*LabelPairAdapter)(nil), "logproto.LabelPairAdapter")
 	proto.RegisterType((*EntryAdapter)(nil), "logproto.EntryAdapter")
@@ -296,40 +396,49 @@ func init() {
 func init() { proto.RegisterFile("pkg/push/push.proto", fileDescriptor_35ec442956852c9e) }
 
 var fileDescriptor_35ec442956852c9e = []byte{
-	// 527 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0xf5, 0x26, 0x6e, 0xda, 0x6e, 0x4a, 0xa9, 0x96, 0xb6, 0x18, 0xab, 0x5a, 0x47, 0x16, 0x87,
-	0x1c, 0xc0, 0x96, 0xc2, 0x81, 0x0b, 0x97, 0x58, 0x42, 0xea, 0xa1, 0x48, 0x95, 0x41, 0x20, 0x71,
-	0xdb, 0x34, 0x5b, 0xdb, 0xaa, 0xed, 0x35, 0xbb, 0x6b, 0xa4, 0xde, 0xf8, 0x84, 0xf2, 0x17, 0x7c,
-	0x01, 0xdf, 0xd0, 0x63, 0x8e, 0x15, 0x07, 0x43, 0x9c, 0x0b, 0xca, 0xa9, 0x9f, 0x80, 0xbc, 0xb6,
-	0x49, 0x28, 0x48, 0x5c, 0x36, 0x6f, 0x66, 0x67, 0xde, 0x7b, 0x99, 0x1d, 0xc3, 0x07, 0xd9, 0x45,
-	0xe0, 0x66, 0xb9, 0x08, 0xd5, 0xe1, 0x64, 0x9c, 0x49, 0x86, 0xb6, 0x62, 0x16, 0x28, 0x64, 0xee,
-	0x07, 0x2c, 0x60, 0x0a, 0xba, 0x15, 0xaa, 0xef, 0x4d, 0x2b, 0x60, 0x2c, 0x88, 0xa9, 0xab, 0xa2,
-	0x49, 0x7e, 0xee, 0xca, 0x28, 0xa1, 0x42, 0x92, 0x24, 0xab, 0x0b, 0xec, 0x77, 0xb0, 0x7f, 0x9a,
-	0x8b, 0xd0, 0xa7, 0x1f, 0x72, 0x2a, 0x24, 0x3a, 0x86, 0x9b, 0x42, 0x72, 0x4a, 0x12, 0x61, 0x80,
-	0x41, 0x77, 0xd8, 0x1f, 0x3d, 0x74, 0x5a, 0x05, 0xe7, 0xb5, 0xba, 0x18, 0x4f, 0x49, 0x26, 0x29,
-	0xf7, 0x0e, 0xbe, 0x15, 0x56, 0xaf, 0x4e, 0x2d, 0x0b, 0xab, 0xed, 0xf2, 0x5b, 0x60, 0xef, 0xc2,
-	0x9d, 0x9a, 0x58, 0x64, 0x2c, 0x15, 0xd4, 0xfe, 0x0c, 0xe0, 0xbd, 0x3f, 0x18, 0x90, 0x0d, 0x7b,
-	0x31, 0x99, 0xd0, 0xb8, 0x92, 0x02, 0xc3, 0x6d, 0x0f, 0x2e, 0x0b, 0xab, 0xc9, 0xf8, 0xcd, 0x2f,
-	0x1a, 0xc3, 0x4d, 0x9a, 0x4a, 0x1e, 0x51, 0x61, 0x74, 0x94, 0x9f, 0xc3, 0x95, 0x9f, 0x97, 0xa9,
-	0xe4, 0x97, 0xad, 0x9d, 0xfb, 0xd7, 0x85, 0xa5, 0x55, 0x46, 0x9a, 0x72, 0xbf, 0x05, 0xe8, 0x11,
-	0xd4, 0x43, 0x22, 0x42, 0xa3, 0x3b, 0x00, 0x43, 0xdd, 0xdb, 0x58, 0x16, 0x16, 0x78, 0xea, 0xab,
-	0x94, 0xfd, 0x02, 0xee, 0x9d, 0x54, 0x3a, 0xa7, 0x24, 0xe2, 0xad, 0x2b, 0x04, 0xf5, 0x94, 0x24,
-	0xb4, 0xf6, 0xe4, 0x2b, 0x8c, 0xf6, 0xe1, 0xc6, 0x47, 0x12, 0xe7, 0xd4, 0xe8, 0xa8, 0x64, 0x1d,
-	0xd8, 0x5f, 0x3b, 0x70, 0x67, 0xdd, 0x03, 0x3a, 0x86, 0xdb, 0xbf, 0xc7, 0xab, 0xfa, 0xfb, 0x23,
-	0xd3, 0xa9, 0x1f, 0xc0, 0x69, 0x1f, 0xc0, 0x79, 0xd3, 0x56, 0x78, 0xbb, 0x8d, 0xe5, 0x8e, 0x14,
-	0x57, 0xdf, 0x2d, 0xe0, 0xaf, 0x9a, 0xd1, 0x11, 0xd4, 0xe3, 0x28, 0x6d, 0xf4, 0xbc, 0xad, 0x65,
-	0x61, 0xa9, 0xd8, 0x57, 0x27, 0xca, 0x20, 0x12, 0x92, 0xe7, 0x67, 0x32, 0xe7, 0x74, 0xfa, 0x8a,
-	0x4a, 0x32, 0x25, 0x92, 0x18, 0x5d, 0x35, 0x1f, 0x73, 0x35, 0x9f, 0xbb, 0x7f, 0xcd, 0x7b, 0xdc,
-	0x08, 0x1e, 0xfd, 0xdd, 0xfd, 0x84, 0x25, 0x91, 0xa4, 0x49, 0x26, 0x2f, 0xfd, 0x7f, 0x70, 0xa3,
-	0x13, 0xd8, 0xcb, 0x08, 0x17, 0x74, 0x6a, 0xe8, 0xff, 0x55, 0x31, 0x1a, 0x95, 0xbd, 0xba, 0x63,
-	0x8d, 0xb9, 0xe1, 0x18, 0x8d, 0x61, 0xaf, 0x5a, 0x0d, 0xca, 0xd1, 0x73, 0xa8, 0x57, 0x08, 0x1d,
-	0xac, 0xf8, 0xd6, 0xb6, 0xd1, 0x3c, 0xbc, 0x9b, 0x6e, 0x76, 0x49, 0xf3, 0xde, 0xce, 0xe6, 0x58,
-	0xbb, 0x99, 0x63, 0xed, 0x76, 0x8e, 0xc1, 0xa7, 0x12, 0x83, 0x2f, 0x25, 0x06, 0xd7, 0x25, 0x06,
-	0xb3, 0x12, 0x83, 0x1f, 0x25, 0x06, 0x3f, 0x4b, 0xac, 0xdd, 0x96, 0x18, 0x5c, 0x2d, 0xb0, 0x36,
-	0x5b, 0x60, 0xed, 0x66, 0x81, 0xb5, 0xf7, 0x83, 0x20, 0x92, 0x61, 0x3e, 0x71, 0xce, 0x58, 0xe2,
-	0x06, 0x9c, 0x9c, 0x93, 0x94, 0xb8, 0x31, 0xbb, 0x88, 0xdc, 0xf6, 0xd3, 0x9a, 0xf4, 0x94, 0xda,
-	0xb3, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x7e, 0xaa, 0x57, 0xd3, 0x6d, 0x03, 0x00, 0x00,
+	// 660 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0xcd, 0x6e, 0xd4, 0x3a,
+	0x14, 0x8e, 0xa7, 0x99, 0x69, 0xeb, 0xe9, 0x6d, 0x2b, 0xf7, 0xe7, 0xa6, 0xa3, 0x2a, 0x99, 0x1b,
+	0x5d, 0x50, 0x17, 0x90, 0x48, 0x65, 0xc1, 0x06, 0x16, 0x8d, 0x84, 0xd4, 0x45, 0x91, 0x2a, 0x17,
+	0x81, 0xc4, 0xce, 0xd3, 0xb8, 0x99, 0xd0, 0xfc, 0x11, 0x3b, 0x88, 0xee, 0x78, 0x84, 0xf2, 0x00,
+	0xec, 0x79, 0x02, 0x9e, 0xa1, 0xcb, 0x2e, 0x2b, 0x16, 0x29, 0x4d, 0x37, 0x28, 0xab, 0x3e, 0x02,
+	0x8a, 0x9d, 0x90, 0x99, 0x01, 0xc4, 0x26, 0xfe, 0xfc, 0xf9, 0x9c, 0xef, 0x3b, 0x73, 0x7c, 0x3c,
+	0x70, 0x2d, 0x39, 0xf5, 0xec, 0x24, 0x63, 0x63, 0xf1, 0xb1, 0x92, 0x34, 0xe6, 0x31, 0x5a, 0x08,
+	0x62, 0x4f, 0xa0, 0xc1, 0xba, 0x17, 0x7b, 0xb1, 0x80, 0x76, 0x85, 0xe4, 0xf9, 0xc0, 0xf0, 0xe2,
+	0xd8, 0x0b, 0xa8, 0x2d, 0x76, 0xa3, 0xec, 0xc4, 0xe6, 0x7e, 0x48, 0x19, 0x27, 0x61, 0x22, 0x03,
+	0xcc, 0x57, 0xb0, 0x7f, 0x98, 0xb1, 0x31, 0xa6, 0x6f, 0x33, 0xca, 0x38, 0xda, 0x87, 0xf3, 0x8c,
+	0xa7, 0x94, 0x84, 0x4c, 0x03, 0xc3, 0xb9, 0x9d, 0xfe, 0xee, 0xbf, 0x56, 0xe3, 0x60, 0x1d, 0x89,
+	0x83, 0x3d, 0x97, 0x24, 0x9c, 0xa6, 0xce, 0xc6, 0xd7, 0xdc, 0xe8, 0x49, 0xaa, 0xcc, 0x8d, 0x26,
+	0x0b, 0x37, 0xc0, 0xfc, 0x04, 0xe0, 0x92, 0x54, 0x66, 0x49, 0x1c, 0x31, 0x8a, 0x28, 0x5c, 0x49,
+	0xe9, 0x1b, 0x7a, 0xcc, 0xa9, 0x7b, 0x34, 0x65, 0xa1, 0xb5, 0x16, 0x78, 0x2a, 0xc0, 0xf9, 0xef,
+	0x22, 0x37, 0x94, 0x32, 0x37, 0xb6, 0x66, 0x12, 0x1f, 0xc4, 0xa1, 0xcf, 0x69, 0x98, 0xf0, 0x33,
+	0x3c, 0xab, 0x89, 0xee, 0x43, 0x35, 0x88, 0x89, 0xab, 0x75, 0x86, 0x60, 0x07, 0x38, 0xa8, 0xcc,
+	0x8d, 0xe5, 0x6a, 0x3f, 0x91, 0x22, 0xce, 0xcd, 0x6b, 0x00, 0x97, 0xa7, 0xed, 0x90, 0x01, 0xbb,
+	0x7e, 0xe4, 0xd2, 0xf7, 0x1a, 0x18, 0x82, 0x9d, 0xae, 0xb3, 0x58, 0xe6, 0x86, 0x24, 0xb0, 0x5c,
+	0x90, 0x09, 0x7b, 0x01, 0x19, 0xd1, 0x80, 0x09, 0xf5, 0x45, 0x07, 0x96, 0xb9, 0x51, 0x33, 0xb8,
+	0x5e, 0xab, 0x98, 0x94, 0x12, 0x16, 0x47, 0xda, 0x5c, 0x1b, 0x23, 0x19, 0x5c, 0xaf, 0xe8, 0x1e,
+	0x9c, 0x0f, 0x29, 0x63, 0xc4, 0xa3, 0x9a, 0x2a, 0x82, 0xfa, 0x55, 0x0b, 0x6b, 0x0a, 0x37, 0x00,
+	0x3d, 0x6d, 0x3b, 0xf6, 0x2c, 0xe2, 0xa9, 0x4f, 0x99, 0xd6, 0x15, 0x95, 0xad, 0x95, 0xb9, 0x31,
+	0x7b, 0x84, 0x67, 0x09, 0xf3, 0x23, 0x80, 0xff, 0x4c, 0xdd, 0xd9, 0x44, 0xfd, 0xe0, 0x8f, 0xf5,
+	0xef, 0xc1, 0x79, 0x5a, 0x9b, 0x75, 0xc4, 0xf5, 0x6c, 0xb6, 0xd7, 0x53, 0x29, 0x9f, 0x35, 0x03,
+	0xb0, 0x52, 0x5f, 0x4e, 0x13, 0x8e, 0x1b, 0x80, 0xb6, 0xa0, 0x3a, 0x26, 0x6c, 0x2c, 0x1a, 0xa0,
+	0x3a, 0xdd, 0x32, 0x37, 0xc0, 0x43, 0x2c, 0x28, 0xf3, 0x09, 0x5c, 0x3d, 0xa8, 0x7c, 0x0e, 0x89,
+	0x9f, 0x36, 0x55, 0x21, 0xa8, 0x46, 0x24, 0xa4, 0xb2, 0x26, 0x2c, 0x30, 0x5a, 0x87, 0xdd, 0x77,
+	0x24, 0xc8, 0xa8, 0x6c, 0x34, 0x96, 0x1b, 0xf3, 0x4b, 0x07, 0x2e, 0x4d, 0xd6, 0x80, 0xf6, 0xe1,
+	0xe2, 0xcf, 0x81, 0x16, 0xf9, 0xfd, 0xdd, 0x81, 0x25, 0x47, 0xde, 0x6a, 0x46, 0xde, 0x7a, 0xd1,
+	0x44, 0x38, 0xcb, 0x75, 0xc9, 0x1d, 0xce, 0xce, 0xaf, 0x0d, 0x80, 0xdb, 0x64, 0xb4, 0x0d, 0xd5,
+	0xc0, 0x8f, 0x6a, 0x3f, 0x67, 0xa1, 0xcc, 0x0d, 0xb1, 0xc7, 0xe2, 0x8b, 0x12, 0x88, 0x18, 0x4f,
+	0xb3, 0x63, 0x9e, 0xa5, 0xd4, 0x7d, 0x4e, 0x39, 0x71, 0x09, 0x27, 0xda, 0x9c, 0xe8, 0xcf, 0xa0,
+	0xed, 0xcf, 0xec, 0x4f, 0x73, 0xfe, 0xaf, 0x0d, 0xb7, 0x7f, 0xcd, 0x9e, 0x18, 0xc8, 0xdf, 0x68,
+	0xa3, 0x03, 0xd8, 0x4b, 0x48, 0xca, 0xa8, 0xab, 0xa9, 0x7f, 0x75, 0xd1, 0x6a, 0x97, 0x55, 0x99,
+	0x31, 0xa1, 0x5c, 0x6b, 0xec, 0xee, 0xc1, 0x5e, 0xf5, 0x16, 0x69, 0x8a, 0x1e, 0x43, 0xb5, 0x42,
+	0x68, 0xa3, 0xd5, 0x9b, 0x78, 0xff, 0x83, 0xcd, 0x59, 0x5a, 0x3e, 0x5e, 0x53, 0x71, 0x5e, 0x5e,
+	0xde, 0xe8, 0xca, 0xd5, 0x8d, 0xae, 0xdc, 0xdd, 0xe8, 0xe0, 0x43, 0xa1, 0x83, 0xcf, 0x85, 0x0e,
+	0x2e, 0x0a, 0x1d, 0x5c, 0x16, 0x3a, 0xf8, 0x56, 0xe8, 0xe0, 0x7b, 0xa1, 0x2b, 0x77, 0x85, 0x0e,
+	0xce, 0x6f, 0x75, 0xe5, 0xf2, 0x56, 0x57, 0xae, 0x6e, 0x75, 0xe5, 0xf5, 0xd0, 0xf3, 0xf9, 0x38,
+	0x1b, 0x59, 0xc7, 0x71, 0x68, 0x7b, 0x29, 0x39, 0x21, 0x11, 0xb1, 0x83, 0xf8, 0xd4, 0xb7, 0x9b,
+	0x3f, 0xb3, 0x51, 0x4f, 0xb8, 0x3d, 0xfa, 0x11, 0x00, 0x00, 0xff, 0xff, 0xde, 0x26, 0x50, 0x12,
+	0xdf, 0x04, 0x00, 0x00,
 }
 
 func (this *PushRequest) Equal(that interface{}) bool {
@@ -380,6 +489,53 @@ func (this *PushResponse) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
+	if len(this.RejectedStreams) != len(that1.RejectedStreams) {
+		return false
+	}
+	for i := range this.RejectedStreams {
+		if !this.RejectedStreams[i].Equal(&that1.RejectedStreams[i]) {
+			return false
+		}
+	}
+	if this.Load != that1.Load {
+		return false
+	}
+	return true
+}
+func (this *RejectedStream) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*RejectedStream)
+	if !ok {
+		that2, ok := that.(RejectedStream)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Index != that1.Index {
+		return false
+	}
+	if this.Labels != that1.Labels {
+		return false
+	}
+	if this.Reason != that1.Reason {
+		return false
+	}
+	if this.Message != that1.Message {
+		return false
+	}
+	if this.RejectedEntries != that1.RejectedEntries {
+		return false
+	}
 	return true
 }
 func (this *StreamAdapter) Equal(that interface{}) bool {
@@ -501,8 +657,30 @@ func (this *PushResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 4)
+	s := make([]string, 0, 6)
 	s = append(s, "&push.PushResponse{")
+	if this.RejectedStreams != nil {
+		vs := make([]RejectedStream, len(this.RejectedStreams))
+		for i := range vs {
+			vs[i] = this.RejectedStreams[i]
+		}
+		s = append(s, "RejectedStreams: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "Load: "+fmt.Sprintf("%#v", this.Load)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *RejectedStream) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&push.RejectedStream{")
+	s = append(s, "Index: "+fmt.Sprintf("%#v", this.Index)+",\n")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	s = append(s, "Reason: "+fmt.Sprintf("%#v", this.Reason)+",\n")
+	s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
+	s = append(s, "RejectedEntries: "+fmt.Sprintf("%#v", this.RejectedEntries)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -706,6 +884,80 @@ func (m *PushResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Load != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Load))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if len(m.RejectedStreams) > 0 {
+		for iNdEx := len(m.RejectedStreams) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RejectedStreams[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPush(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RejectedStream) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RejectedStream) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RejectedStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.RejectedEntries != 0 {
+		i = encodeVarintPush(dAtA, i, uint64(m.RejectedEntries))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintPush(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintPush(dAtA, i, uint64(len(m.Reason)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Labels) > 0 {
+		i -= len(m.Labels)
+		copy(dAtA[i:], m.Labels)
+		i = encodeVarintPush(dAtA, i, uint64(len(m.Labels)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Index != 0 {
+		i = encodeVarintPush(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x8
+	}
 	return len(dAtA) - i, nil
 }
 
@@ -893,6 +1145,42 @@ func (m *PushResponse) Size() (n int) {
 	}
 	var l int
 	_ = l
+	if len(m.RejectedStreams) > 0 {
+		for _, e := range m.RejectedStreams {
+			l = e.Size()
+			n += 1 + l + sovPush(uint64(l))
+		}
+	}
+	if m.Load != 0 {
+		n += 9
+	}
+	return n
+}
+
+func (m *RejectedStream) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovPush(uint64(m.Index))
+	}
+	l = len(m.Labels)
+	if l > 0 {
+		n += 1 + l + sovPush(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovPush(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovPush(uint64(l))
+	}
+	if m.RejectedEntries != 0 {
+		n += 1 + sovPush(uint64(m.RejectedEntries))
+	}
 	return n
 }
 
@@ -982,7 +1270,28 @@ func (this *PushResponse) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForRejectedStreams := "[]RejectedStream{"
+	for _, f := range this.RejectedStreams {
+		repeatedStringForRejectedStreams += strings.Replace(strings.Replace(f.String(), "RejectedStream", "RejectedStream", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRejectedStreams += "}"
 	s := strings.Join([]string{`&PushResponse{`,
+		`RejectedStreams:` + repeatedStringForRejectedStreams + `,`,
+		`Load:` + fmt.Sprintf("%v", this.Load) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RejectedStream) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RejectedStream{`,
+		`Index:` + fmt.Sprintf("%v", this.Index) + `,`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`Reason:` + fmt.Sprintf("%v", this.Reason) + `,`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`RejectedEntries:` + fmt.Sprintf("%v", this.RejectedEntries) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1162,6 +1471,238 @@ func (m *PushResponse) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: PushResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RejectedStreams", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPush
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPush
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPush
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RejectedStreams = append(m.RejectedStreams, RejectedStream{})
+			if err := m.RejectedStreams[len(m.RejectedStreams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Load", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Load = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPush(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPush
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPush
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RejectedStream) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPush
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RejectedStream: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RejectedStream: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPush
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPush
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPush
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPush
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPush
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPush
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPush
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPush
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPush
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPush
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RejectedEntries", wireType)
+			}
+			m.RejectedEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPush
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RejectedEntries |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPush(dAtA[iNdEx:])
@@ -269,6 +269,10 @@ func (q *Rf1Querier) Tail(_ context.Context, _ *logproto.TailRequest, _ bool) (*
 	return nil, errors.New("not implemented")
 }
 
+func (q *Rf1Querier) Lookup(_ context.Context, _ *logproto.LookupRequest) (*logproto.LookupResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
 // Series fetches any matching series for a list of matcher sets
 func (q *Rf1Querier) Series(ctx context.Context, req *logproto.SeriesRequest) (*logproto.SeriesResponse, error) {
 	userID, err := tenant.TenantID(ctx)
@@ -402,6 +402,58 @@ func TestIngesterQuerier_DetectedLabels(t *testing.T) {
 	})
 }
 
+func TestIngesterQuerier_Lookup(t *testing.T) {
+	ts := func(s int64) time.Time { return time.Unix(s, 0) }
+	entry := func(s int64) logproto.Entry {
+		return logproto.Entry{Timestamp: ts(s), Line: "line", StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "abc"}}}
+	}
+
+	ingesterClient := newQuerierClientMock()
+	// The replicas hold the same entries, one of them holding a more recent one.
+	ingesterClient.On("Lookup", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.LookupResponse{
+		Refs: []logproto.LookupRef{
+			{Labels: `{app="foo"}`, Fingerprint: 1, From: ts(1), Through: ts(2)},
+		},
+		Streams: []logproto.Stream{
+			{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(2), entry(1)}},
+		},
+	}, nil).Once()
+	ingesterClient.On("Lookup", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.LookupResponse{
+		Refs: []logproto.LookupRef{
+			{Labels: `{app="bar"}`, Fingerprint: 2, From: ts(3), Through: ts(3)},
+			{Labels: `{app="foo"}`, Fingerprint: 1, From: ts(1), Through: ts(4)},
+		},
+		Streams: []logproto.Stream{
+			{Labels: `{app="bar"}`, Entries: []logproto.Entry{entry(3)}},
+			{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(4), entry(2), entry(1)}},
+		},
+	}, nil).Once()
+
+	readRingMock := newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("3.3.3.3", ring.ACTIVE)}, 0)
+	ingesterQuerier, err := newTestIngesterQuerier(readRingMock, ingesterClient)
+	require.NoError(t, err)
+
+	resp, err := ingesterQuerier.Lookup(context.Background(), &logproto.LookupRequest{
+		Key:       "trace_id",
+		Value:     "abc",
+		Start:     ts(0),
+		End:       ts(10),
+		Limit:     3,
+		Direction: logproto.BACKWARD,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []logproto.LookupRef{
+		{Labels: `{app="bar"}`, Fingerprint: 2, From: ts(3), Through: ts(3)},
+		{Labels: `{app="foo"}`, Fingerprint: 1, From: ts(1), Through: ts(4)},
+	}, resp.Refs)
+	require.Len(t, resp.Streams, 2)
+	require.Equal(t, `{app="bar"}`, resp.Streams[0].Labels)
+	require.Equal(t, []logproto.Entry{entry(3)}, resp.Streams[0].Entries)
+	require.Equal(t, `{app="foo"}`, resp.Streams[1].Labels)
+	require.Equal(t, []logproto.Entry{entry(4), entry(2)}, resp.Streams[1].Entries)
+}
+
 func newTestIngesterQuerier(readRingMock *readRingMock, ingesterClient *querierClientMock) (*IngesterQuerier, error) {
 	return newIngesterQuerier(
 		mockIngesterClientConfig(),
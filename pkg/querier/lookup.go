@@ -0,0 +1,169 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/metadata"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+	"github.com/grafana/loki/v3/pkg/util/marshal"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+	util_validation "github.com/grafana/loki/v3/pkg/util/validation"
+)
+
+// lookupNotIndexedWarning is the warning of the lookups whose time range is not held by the ingesters.
+const lookupNotIndexedWarning = "The entries between %s and %s were not looked up, as the structured metadata is only indexed by the ingesters. Query them with a label filter instead, for example {<selector>} | %s=%q."
+
+// Lookup returns the streams having entries with a value of an indexed structured metadata key, and these entries.
+// The structured metadata is only indexed by the ingesters, so the entries older than query_ingesters_within
+// are not looked up, which is reported by a warning.
+func (q *SingleTenantQuerier) Lookup(ctx context.Context, req *logproto.LookupRequest) (*logproto.LookupResponse, error) {
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Start, req.End, err = validateQueryTimeRangeLimits(ctx, userID, q.limits, req.Start, req.End); err != nil {
+		return nil, err
+	}
+
+	ingesterQueryInterval, _ := q.buildQueryIntervals(req.Start, req.End)
+	if q.cfg.QueryStoreOnly || ingesterQueryInterval == nil {
+		warnLookupNotIndexed(ctx, req, req.End)
+		return &logproto.LookupResponse{}, nil
+	}
+	// The ingesters only hold the entries within their max lookback period, even when the query interval is not limited to it.
+	if ingesterMLB := q.calculateIngesterMaxLookbackPeriod(); ingesterMLB != -1 {
+		if ingesterOldestStartTime := time.Now().Add(-ingesterMLB); ingesterOldestStartTime.After(req.Start) {
+			warnLookupNotIndexed(ctx, req, ingesterOldestStartTime)
+		}
+	}
+
+	timeFramedReq := *req
+	timeFramedReq.Start = ingesterQueryInterval.start
+	timeFramedReq.End = ingesterQueryInterval.end
+	timeFramedReq.Deletes, err = q.deletesForUser(ctx, timeFramedReq.Start, timeFramedReq.End)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enforce the query timeout while querying backends
+	queryTimeout := q.limits.QueryTimeout(ctx, userID)
+	ctx, cancel := context.WithDeadlineCause(ctx, time.Now().Add(queryTimeout), errors.New("query timeout reached"))
+	defer cancel()
+
+	return q.ingesterQuerier.Lookup(ctx, &timeFramedReq)
+}
+
+func warnLookupNotIndexed(ctx context.Context, req *logproto.LookupRequest, through time.Time) {
+	_ = metadata.AddWarnings(ctx, fmt.Sprintf(lookupNotIndexedWarning, req.Start.UTC().Format(time.RFC3339), through.UTC().Format(time.RFC3339), req.Key, req.Value))
+}
+
+// Lookup returns the streams and the entries having a value of an indexed structured metadata key held by the ingesters.
+func (q *IngesterQuerier) Lookup(ctx context.Context, req *logproto.LookupRequest) (*logproto.LookupResponse, error) {
+	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
+		return client.Lookup(ctx, req)
+	})
+	if err != nil {
+		if isUnimplementedCallError(err) {
+			// Handle communication with older ingesters gracefully
+			return &logproto.LookupResponse{}, nil
+		}
+		return nil, err
+	}
+
+	casted := make([]*logproto.LookupResponse, 0, len(resps))
+	for _, resp := range resps {
+		casted = append(casted, resp.response.(*logproto.LookupResponse))
+	}
+	return mergeLookupResponses(ctx, casted, req.Direction, req.Limit)
+}
+
+// mergeLookupResponses merges the responses of the ingesters, deduplicating the streams and the entries
+// of the replicas.
+func mergeLookupResponses(ctx context.Context, resps []*logproto.LookupResponse, direction logproto.Direction, limit uint32) (*logproto.LookupResponse, error) {
+	refs := map[string]*logproto.LookupRef{}
+	iters := make([]iter.EntryIterator, 0, len(resps))
+	for _, resp := range resps {
+		for i := range resp.Refs {
+			ref := resp.Refs[i]
+			merged, ok := refs[ref.Labels]
+			if !ok {
+				refs[ref.Labels] = &ref
+				continue
+			}
+			if ref.From.Before(merged.From) {
+				merged.From = ref.From
+			}
+			if ref.Through.After(merged.Through) {
+				merged.Through = ref.Through
+			}
+		}
+		iters = append(iters, iter.NewStreamsIterator(resp.Streams, direction))
+	}
+
+	it := iter.NewMergeEntryIterator(ctx, iters, direction)
+	defer it.Close()
+	batch, _, err := iter.ReadBatch(it, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &logproto.LookupResponse{
+		Refs:    make([]logproto.LookupRef, 0, len(refs)),
+		Streams: batch.Streams,
+	}
+	for _, ref := range refs {
+		result.Refs = append(result.Refs, *ref)
+	}
+	sort.Slice(result.Refs, func(i, j int) bool { return result.Refs[i].Labels < result.Refs[j].Labels })
+	sort.Slice(result.Streams, func(i, j int) bool { return result.Streams[i].Labels < result.Streams[j].Labels })
+	return result, nil
+}
+
+// LookupHandler is a http.HandlerFunc for looking up the entries having a value of an indexed structured metadata key.
+func (q *QuerierAPI) LookupHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+	req, err := loghttp.ParseLookupQuery(r)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+
+	_, ctx := metadata.NewContext(r.Context())
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+	maxEntriesCapture := func(id string) int { return q.limits.MaxEntriesLimitPerQuery(ctx, id) }
+	maxEntriesLimit := util_validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, maxEntriesCapture)
+	if int(req.Limit) > maxEntriesLimit && maxEntriesLimit != 0 {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest,
+			"max entries limit per query exceeded, limit > max_entries_limit (%d > %d)", req.Limit, maxEntriesLimit), w)
+		return
+	}
+
+	resp, err := q.querier.Lookup(ctx, req)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	if err := marshal.WriteLookupResponseJSON(resp, metadata.FromContext(ctx).Warnings(), w); err != nil {
+		level.Error(util_log.WithContext(ctx, util_log.Logger)).Log("msg", "error marshalling lookup response", "err", err)
+	}
+}
@@ -91,7 +91,7 @@ func (cfg *Config) Validate() error {
 	if cfg.QueryStoreOnly && cfg.QueryIngesterOnly {
 		return errors.New("querier.query_store_only and querier.query_ingester_only cannot both be true")
 	}
-	return nil
+	return cfg.Engine.Validate()
 }
 
 // Querier can select logs and samples and handle query requests.
@@ -106,6 +106,7 @@ type Querier interface {
 	DetectedFields(ctx context.Context, req *logproto.DetectedFieldsRequest) (*logproto.DetectedFieldsResponse, error)
 	Patterns(ctx context.Context, req *logproto.QueryPatternsRequest) (*logproto.QueryPatternsResponse, error)
 	DetectedLabels(ctx context.Context, req *logproto.DetectedLabelsRequest) (*logproto.DetectedLabelsResponse, error)
+	Lookup(ctx context.Context, req *logproto.LookupRequest) (*logproto.LookupResponse, error)
 	WithPatternQuerier(patternQuerier PatterQuerier)
 }
 
@@ -162,6 +163,44 @@ func (q *SingleTenantQuerier) SelectLogs(ctx context.Context, params logql.Selec
 
 	ingesterQueryInterval, storeQueryInterval := q.buildQueryIntervals(params.Start, params.End)
 
+	if !q.cfg.QueryStoreOnly && ingesterQueryInterval != nil && !q.cfg.QueryIngesterOnly && storeQueryInterval != nil {
+		expr, err := params.LogSelector()
+		if err != nil {
+			return nil, err
+		}
+		// The lines surrounding a line matched by an around stage can be held by
+		// the ingesters while the matching line is in the store, or the other way
+		// around, so the stage is applied to the merged lines of both.
+		if around, ok := syntax.AroundStage(expr); ok {
+			return q.selectLogsAround(ctx, params, expr, around, ingesterQueryInterval, storeQueryInterval)
+		}
+	}
+
+	return q.selectLogs(ctx, params, ingesterQueryInterval, storeQueryInterval)
+}
+
+// selectLogsAround selects the unprocessed lines of the streams of a log query
+// ending with an around stage from the ingesters and the store, and returns
+// the lines matching the query along with their surrounding lines.
+func (q *SingleTenantQuerier) selectLogsAround(ctx context.Context, params logql.SelectLogParams, expr syntax.LogSelectorExpr, around *syntax.AroundExpr, ingesterQueryInterval, storeQueryInterval *interval) (iter.EntryIterator, error) {
+	pipeline, err := expr.Pipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	selector := expr.(*syntax.PipelineExpr).Left
+	queryRequestCopy := *params.QueryRequest
+	queryRequestCopy.Selector = selector.String()
+	queryRequestCopy.Plan = &plan.QueryPlan{AST: selector}
+
+	it, err := q.selectLogs(ctx, logql.SelectLogParams{QueryRequest: &queryRequestCopy}, ingesterQueryInterval, storeQueryInterval)
+	if err != nil {
+		return nil, err
+	}
+	return iter.NewContextIterator(it, pipeline, params.Direction, around.Before, around.After), nil
+}
+
+func (q *SingleTenantQuerier) selectLogs(ctx context.Context, params logql.SelectLogParams, ingesterQueryInterval, storeQueryInterval *interval) (iter.EntryIterator, error) {
 	sp := opentracing.SpanFromContext(ctx)
 	iters := []iter.EntryIterator{}
 	if !q.cfg.QueryStoreOnly && ingesterQueryInterval != nil {
@@ -122,6 +122,15 @@ func (c *querierClientMock) GetDetectedLabels(ctx context.Context, in *logproto.
 	return res.(*logproto.LabelToValuesResponse), args.Error(1)
 }
 
+func (c *querierClientMock) Lookup(ctx context.Context, in *logproto.LookupRequest, opts ...grpc.CallOption) (*logproto.LookupResponse, error) {
+	args := c.Called(ctx, in, opts)
+	res := args.Get(0)
+	if res == nil {
+		return (*logproto.LookupResponse)(nil), args.Error(1)
+	}
+	return res.(*logproto.LookupResponse), args.Error(1)
+}
+
 func (c *querierClientMock) GetVolume(ctx context.Context, in *logproto.VolumeRequest, opts ...grpc.CallOption) (*logproto.VolumeResponse, error) {
 	args := c.Called(ctx, in, opts)
 	res := args.Get(0)
@@ -722,6 +731,18 @@ func (q *querierMock) DetectedLabels(ctx context.Context, req *logproto.Detected
 	return resp.(*logproto.DetectedLabelsResponse), err
 }
 
+func (q *querierMock) Lookup(ctx context.Context, req *logproto.LookupRequest) (*logproto.LookupResponse, error) {
+	args := q.MethodCalled("Lookup", ctx, req)
+
+	resp := args.Get(0)
+	err := args.Error(1)
+	if resp == nil {
+		return nil, err
+	}
+
+	return resp.(*logproto.LookupResponse), err
+}
+
 func (q *querierMock) WithPatternQuerier(_ PatterQuerier) {}
 
 type engineMock struct {
@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/metadata"
 	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/storage"
 	"github.com/grafana/loki/v3/pkg/util/constants"
@@ -483,6 +484,77 @@ func TestQuerier_IngesterMaxQueryLookback(t *testing.T) {
 	}
 }
 
+func TestQuerier_LookupIngesterWindow(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, tc := range []struct {
+		desc          string
+		start         time.Time
+		skipIngesters bool
+		warning       bool
+	}{
+		{
+			desc:  "within the ingester window",
+			start: now.Add(-30 * time.Minute),
+		},
+		{
+			desc:    "partly before the ingester window",
+			start:   now.Add(-2 * time.Hour),
+			warning: true,
+		},
+		{
+			desc:          "before the ingester window",
+			start:         now.Add(-3 * time.Hour),
+			skipIngesters: true,
+			warning:       true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			end := now
+			if tc.skipIngesters {
+				end = now.Add(-2 * time.Hour)
+			}
+
+			ingesterClient := newQuerierClientMock()
+			if !tc.skipIngesters {
+				ingesterClient.On("Lookup", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.LookupResponse{}, nil)
+			}
+
+			conf := mockQuerierConfig()
+			conf.QueryIngestersWithin = time.Hour
+			q, err := newQuerier(
+				conf,
+				mockIngesterClientConfig(),
+				newIngesterClientMockFactory(ingesterClient),
+				mockReadRingWithOneActiveIngester(),
+				&mockDeleteGettter{},
+				newStoreMock(), limits)
+			require.NoError(t, err)
+
+			md, ctx := metadata.NewContext(user.InjectOrgID(context.Background(), "test"))
+			_, err = q.Lookup(ctx, &logproto.LookupRequest{
+				Key:       "trace_id",
+				Value:     "abc",
+				Start:     tc.start,
+				End:       end,
+				Limit:     10,
+				Direction: logproto.BACKWARD,
+			})
+			require.NoError(t, err)
+
+			if tc.warning {
+				require.Len(t, md.Warnings(), 1)
+				require.Contains(t, md.Warnings()[0], `trace_id="abc"`)
+			} else {
+				require.Empty(t, md.Warnings())
+			}
+			ingesterClient.AssertExpectations(t)
+		})
+	}
+}
+
 func TestQuerier_concurrentTailLimits(t *testing.T) {
 	request := logproto.TailRequest{
 		Query:    "{type=\"test\"}",
@@ -1295,6 +1367,60 @@ func TestQuerier_SelectLogWithDeletes(t *testing.T) {
 	require.Equal(t, "test", delGetter.user)
 }
 
+func TestQuerier_SelectLogsAround(t *testing.T) {
+	// The line matching the query is held by the ingesters while the lines
+	// preceding it are in the store.
+	store := newStoreMock()
+	store.On("SelectLogs", mock.Anything, mock.Anything).Return(mockStreamIterator(1, 5), nil)
+
+	queryClient := newQueryClientMock()
+	queryClient.On("Recv").Return(mockQueryResponse([]logproto.Stream{mockStream(6, 5)}), nil).Once()
+	queryClient.On("Recv").Return(nil, io.EOF)
+
+	ingesterClient := newQuerierClientMock()
+	ingesterClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(queryClient, nil)
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	q, err := newQuerier(
+		mockQuerierConfig(),
+		mockIngesterClientConfig(),
+		newIngesterClientMockFactory(ingesterClient),
+		mockReadRingWithOneActiveIngester(),
+		&mockDeleteGettter{}, store, limits)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	request := logproto.QueryRequest{
+		Selector:  `{type="test"} |= "line 6" | around 1`,
+		Limit:     10,
+		Start:     time.Unix(0, 0),
+		End:       time.Unix(20, 0),
+		Direction: logproto.FORWARD,
+		Plan: &plan.QueryPlan{
+			AST: syntax.MustParseExpr(`{type="test"} |= "line 6" | around 1`),
+		},
+	}
+
+	it, err := q.SelectLogs(ctx, logql.SelectLogParams{QueryRequest: &request})
+	require.NoError(t, err)
+	defer it.Close()
+
+	var lines []string
+	for it.Next() {
+		lines = append(lines, it.At().Line)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"line 5", "line 6", "line 7"}, lines)
+
+	// The ingesters and the store are queried for all the lines of the streams.
+	expectedPlan := &plan.QueryPlan{AST: syntax.MustParseExpr(`{type="test"}`)}
+	require.Equal(t, expectedPlan, store.Calls[0].Arguments.Get(1).(logql.SelectLogParams).Plan)
+	require.Equal(t, expectedPlan, ingesterClient.Calls[0].Arguments.Get(1).(*logproto.QueryRequest).Plan)
+}
+
 func TestQuerier_SelectSamplesWithDeletes(t *testing.T) {
 	queryClient := newQuerySampleClientMock()
 	queryClient.On("Recv").Return(mockQueryResponse([]logproto.Stream{mockStream(1, 2)}), nil)
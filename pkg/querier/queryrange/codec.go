@@ -1188,6 +1188,19 @@ func decodeResponseJSONFrom(buf []byte, req queryrangebase.Request, headers http
 				Headers:  httpResponseHeadersToPromResponseHeaders(headers),
 				Warnings: resp.Warnings,
 			}, nil
+		case loghttp.ResultTypeTable:
+			table := resp.Data.Result.(loghttp.Table)
+			rows := make([]TableRow, 0, len(table.Rows))
+			for _, row := range table.Rows {
+				rows = append(rows, TableRow{Values: row})
+			}
+			return &TableResponse{
+				Columns:    table.Columns,
+				Rows:       rows,
+				Statistics: resp.Data.Statistics,
+				Headers:    httpResponseHeadersToPromResponseHeaders(headers),
+				Warnings:   resp.Warnings,
+			}, nil
 		case loghttp.ResultTypeVector:
 			return &LokiPromResponse{
 				Response: &queryrangebase.PrometheusResponse{
@@ -1263,6 +1276,10 @@ func decodeResponseProtobuf(r *http.Response, req queryrangebase.Request) (query
 			return concrete.TopkSketches.WithHeaders(headers), nil
 		case *QueryResponse_QuantileSketches:
 			return concrete.QuantileSketches.WithHeaders(headers), nil
+		case *QueryResponse_CountDistinctSketches:
+			return concrete.CountDistinctSketches.WithHeaders(headers), nil
+		case *QueryResponse_Table:
+			return concrete.Table.WithHeaders(headers), nil
 		default:
 			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "unsupported response type, got (%T)", resp.Response)
 		}
@@ -1328,6 +1345,10 @@ func encodeResponseJSONTo(version loghttp.Version, res queryrangebase.Response,
 				return err
 			}
 		}
+	case *TableResponse:
+		if err := marshal.WriteQueryResponseJSON(response.Table(), response.Warnings, response.Statistics, w, encodeFlags); err != nil {
+			return err
+		}
 	case *MergedSeriesResponseView:
 		if err := WriteSeriesResponseViewJSON(response, w); err != nil {
 			return err
@@ -375,6 +375,15 @@ func Test_codec_DecodeResponse(t *testing.T) {
 				Statistics: statsResult,
 			}, "",
 		},
+		{
+			"table", &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{"status":"success","data":{"resultType":"table","result":{"columns":["status","count()"],"rows":[["200","7"],["500","3"]]}}}`))},
+			&LokiRequest{Path: "/loki/api/v1/query_range"},
+			&TableResponse{
+				Columns: []string{"status", "count()"},
+				Rows:    []TableRow{{Values: []string{"200", "7"}}, {Values: []string{"500", "3"}}},
+			},
+			"",
+		},
 		{
 			"streams v1", &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(streamsString))},
 			&LokiRequest{Direction: logproto.FORWARD, Limit: 100, Path: "/loki/api/v1/query_range"},
@@ -113,6 +113,12 @@ func withoutOffset(query logql.DownstreamQuery) (string, time.Time, time.Time) {
 		newStart = query.Params.Start()
 		newEnd   = query.Params.End()
 	)
+	if hasSubquery(expr) || syntax.HasAtModifier(expr) {
+		// offsets of range aggregations within a subquery are relative to
+		// the inner query timestamps, not to the query start and end, and
+		// offsets of pinned ranges are relative to the pinned time.
+		return expr.String(), newStart, newEnd
+	}
 	expr.Walk(func(e syntax.Expr) {
 		switch rng := e.(type) {
 		case *syntax.RangeAggregationExpr:
@@ -131,6 +137,16 @@ func withoutOffset(query logql.DownstreamQuery) (string, time.Time, time.Time) {
 	return expr.String(), newStart, newEnd
 }
 
+func hasSubquery(expr syntax.Expr) bool {
+	var found bool
+	expr.Walk(func(e syntax.Expr) {
+		if _, ok := e.(*syntax.SubqueryExpr); ok {
+			found = true
+		}
+	})
+	return found
+}
+
 func (in instance) Downstream(ctx context.Context, queries []logql.DownstreamQuery, acc logql.Accumulator) ([]logqlmodel.Result, error) {
 	return in.For(ctx, queries, acc, func(qry logql.DownstreamQuery) (logqlmodel.Result, error) {
 		var req queryrangebase.Request
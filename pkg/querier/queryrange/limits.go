@@ -314,15 +314,16 @@ func (q *querySizeLimiter) getBytesReadForRequest(ctx context.Context, r queryra
 }
 
 func (q *querySizeLimiter) getSchemaCfg(r queryrangebase.Request) (config.PeriodConfig, error) {
-	maxRVDuration, maxOffset, err := maxRangeVectorAndOffsetDurationFromQueryString(r.GetQuery())
+	expr, err := syntax.ParseExpr(r.GetQuery())
+	if err != nil {
+		return config.PeriodConfig{}, errors.New("failed to get range-vector and offset duration: " + err.Error())
+	}
+	adjustedStart, adjustedEnd, err := dataBounds(expr, r.GetStart(), r.GetEnd())
 	if err != nil {
 		return config.PeriodConfig{}, errors.New("failed to get range-vector and offset duration: " + err.Error())
 	}
 
-	adjustedStart := int64(model.Time(r.GetStart().UnixMilli()).Add(-maxRVDuration).Add(-maxOffset))
-	adjustedEnd := int64(model.Time(r.GetEnd().UnixMilli()).Add(-maxOffset))
-
-	return ShardingConfigs(q.cfg).ValidRange(adjustedStart, adjustedEnd)
+	return ShardingConfigs(q.cfg).ValidRange(adjustedStart.UnixMilli(), adjustedEnd.UnixMilli())
 }
 
 func (q *querySizeLimiter) guessLimitName() string {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/validation"
 )
 
 // Limits extends the cortex limits interface with support for per tenant splitby parameters
@@ -36,4 +37,6 @@ type Limits interface {
 	MaxStatsCacheFreshness(context.Context, string) time.Duration
 	MaxMetadataCacheFreshness(context.Context, string) time.Duration
 	VolumeEnabled(string) bool
+	// MetricAggregationRules returns the metrics pre-aggregated by the ingesters.
+	MetricAggregationRules(string) []validation.MetricAggregationRule
 }
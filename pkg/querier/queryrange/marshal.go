@@ -121,12 +121,29 @@ func ResultToResponse(result logqlmodel.Result, params logql.Params) (queryrange
 			Warnings:   result.Warnings,
 			Statistics: result.Statistics,
 		}, nil
+	case logqlmodel.Table:
+		rows := make([]TableRow, 0, len(data.Rows))
+		for _, row := range data.Rows {
+			rows = append(rows, TableRow{Values: row})
+		}
+		return &TableResponse{
+			Columns:    data.Columns,
+			Rows:       rows,
+			Warnings:   result.Warnings,
+			Statistics: result.Statistics,
+		}, nil
 	case sketch.TopKMatrix:
 		sk, err := data.ToProto()
 		return &TopKSketchesResponse{
 			Response: sk,
 			Warnings: result.Warnings,
 		}, err
+	case logql.CountDistinctSketchMatrix:
+		sk, err := data.ToProto()
+		return &CountDistinctSketchResponse{
+			Response: sk,
+			Warnings: result.Warnings,
+		}, err
 	case logql.ProbabilisticQuantileMatrix:
 		r := data.ToProto()
 		data.Release()
@@ -139,6 +156,15 @@ func ResultToResponse(result logqlmodel.Result, params logql.Params) (queryrange
 	return nil, fmt.Errorf("unsupported data type: %T", result.Data)
 }
 
+// Table returns the columns and rows of the response as a logqlmodel.Table.
+func (m *TableResponse) Table() logqlmodel.Table {
+	rows := make([][]string, 0, len(m.Rows))
+	for _, row := range m.Rows {
+		rows = append(rows, row.Values)
+	}
+	return logqlmodel.Table{Columns: m.Columns, Rows: rows}
+}
+
 func ResponseToResult(resp queryrangebase.Response) (logqlmodel.Result, error) {
 	switch r := resp.(type) {
 	case *LokiResponse:
@@ -177,6 +203,13 @@ func ResponseToResult(resp queryrangebase.Response) (logqlmodel.Result, error) {
 			Headers:    resp.GetHeaders(),
 			Warnings:   r.Response.Warnings,
 		}, nil
+	case *TableResponse:
+		return logqlmodel.Result{
+			Statistics: r.Statistics,
+			Data:       r.Table(),
+			Headers:    resp.GetHeaders(),
+			Warnings:   r.Warnings,
+		}, nil
 	case *TopKSketchesResponse:
 		matrix, err := sketch.TopKMatrixFromProto(r.Response)
 		if err != nil {
@@ -198,6 +231,16 @@ func ResponseToResult(resp queryrangebase.Response) (logqlmodel.Result, error) {
 			Headers:  resp.GetHeaders(),
 			Warnings: r.Warnings,
 		}, nil
+	case *CountDistinctSketchResponse:
+		matrix, err := logql.CountDistinctSketchMatrixFromProto(r.Response)
+		if err != nil {
+			return logqlmodel.Result{}, fmt.Errorf("cannot decode count distinct sketch: %w", err)
+		}
+		return logqlmodel.Result{
+			Data:     matrix,
+			Headers:  resp.GetHeaders(),
+			Warnings: r.Warnings,
+		}, nil
 	default:
 		return logqlmodel.Result{}, fmt.Errorf("cannot decode (%T)", resp)
 	}
@@ -227,6 +270,10 @@ func QueryResponseUnwrap(res *QueryResponse) (queryrangebase.Response, error) {
 		return concrete.TopkSketches, nil
 	case *QueryResponse_QuantileSketches:
 		return concrete.QuantileSketches, nil
+	case *QueryResponse_CountDistinctSketches:
+		return concrete.CountDistinctSketches, nil
+	case *QueryResponse_Table:
+		return concrete.Table, nil
 	case *QueryResponse_PatternsResponse:
 		return concrete.PatternsResponse, nil
 	case *QueryResponse_DetectedLabels:
@@ -266,6 +313,10 @@ func QueryResponseWrap(res queryrangebase.Response) (*QueryResponse, error) {
 		p.Response = &QueryResponse_TopkSketches{response}
 	case *QuantileSketchResponse:
 		p.Response = &QueryResponse_QuantileSketches{response}
+	case *CountDistinctSketchResponse:
+		p.Response = &QueryResponse_CountDistinctSketches{response}
+	case *TableResponse:
+		p.Response = &QueryResponse_Table{response}
 	case *ShardsResponse:
 		p.Response = &QueryResponse_ShardsResponse{response}
 	case *QueryPatternsResponse:
@@ -47,6 +47,21 @@ func TestResultToResponse(t *testing.T) {
 				Headers: []queryrangebase.PrometheusResponseHeader(nil),
 			},
 		},
+		{
+			name: "table",
+			result: logqlmodel.Result{
+				Data: logqlmodel.Table{
+					Columns: []string{"status", "count()"},
+					Rows:    [][]string{{"200", "7"}, {"500", "3"}},
+				},
+				Warnings: []string{"warning"},
+			},
+			response: &TableResponse{
+				Columns:  []string{"status", "count()"},
+				Rows:     []TableRow{{Values: []string{"200", "7"}}, {Values: []string{"500", "3"}}},
+				Warnings: []string{"warning"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -73,6 +88,8 @@ func TestResponseWrap(t *testing.T) {
 		{"streams", &LokiResponse{}, &QueryResponse_Streams{}},
 		{"topk", &TopKSketchesResponse{}, &QueryResponse_TopkSketches{}},
 		{"quantile", &QuantileSketchResponse{}, &QueryResponse_QuantileSketches{}},
+		{"count distinct", &CountDistinctSketchResponse{}, &QueryResponse_CountDistinctSketches{}},
+		{"table", &TableResponse{}, &QueryResponse_Table{}},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			actual, err := QueryResponseWrap(tt.response)
@@ -0,0 +1,127 @@
+package queryrange
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// metricAggregationQuery returns the query of the metric pre-aggregated by the ingesters for one of the rules
+// computing the result of a range query, if any. These are the sum of the count_over_time or the bytes_over_time
+// of a stream selector of a rule, grouped by a subset of its labels, with a range and a step multiple of its interval.
+// The replicas of the ingesters aggregating the same streams write the same samples, the query keeps one of them.
+func metricAggregationQuery(expr syntax.SampleExpr, step time.Duration, rules []validation.MetricAggregationRule) (syntax.SampleExpr, *validation.MetricAggregationRule, bool) {
+	vecAgg, ok := expr.(*syntax.VectorAggregationExpr)
+	if !ok || vecAgg.Operation != syntax.OpTypeSum || (vecAgg.Grouping != nil && vecAgg.Grouping.Without) {
+		return nil, nil, false
+	}
+	rangeAgg, ok := vecAgg.Left.(*syntax.RangeAggregationExpr)
+	if !ok || rangeAgg.Grouping != nil {
+		return nil, nil, false
+	}
+	var field string
+	switch rangeAgg.Operation {
+	case syntax.OpRangeTypeCount:
+		field = validation.MetricAggregationCountField
+	case syntax.OpRangeTypeBytes:
+		field = validation.MetricAggregationBytesField
+	default:
+		return nil, nil, false
+	}
+	logRange := rangeAgg.Left
+	if logRange.Offset != 0 || logRange.At != nil || logRange.Unwrap != nil {
+		return nil, nil, false
+	}
+	selector, ok := logRange.Left.(*syntax.MatchersExpr)
+	if !ok {
+		return nil, nil, false
+	}
+	var groups []string
+	if vecAgg.Grouping != nil {
+		groups = vecAgg.Grouping.Groups
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		interval := time.Duration(rule.Interval)
+		if interval <= 0 || logRange.Interval%interval != 0 || step%interval != 0 {
+			continue
+		}
+		if !sameMatchers(selector.Mts, rule.Matchers) || !subset(groups, rule.By) {
+			continue
+		}
+
+		var grouping string
+		if len(groups) > 0 {
+			grouping = fmt.Sprintf(" by (%s)", strings.Join(groups, ", "))
+		}
+		query := fmt.Sprintf(`sum%s (max by (%s) (sum_over_time({%s=%q} | logfmt %s | unwrap %s [%s])))`,
+			grouping, strings.Join(append(slices.Clone(groups), push.AggregatedMetricOwnerLabel), ", "),
+			push.AggregatedMetricLabel, rule.Name, field, field, model.Duration(logRange.Interval))
+		rewritten, err := syntax.ParseSampleExpr(query)
+		if err != nil {
+			return nil, nil, false
+		}
+		return rewritten, rule, true
+	}
+	return nil, nil, false
+}
+
+// metricAggregationSteps returns the first and the last steps of a range query, rewritten by metricAggregationQuery,
+// whose range is covered by the pre-aggregated metric of the rule: the range must start after the rule was added,
+// and end before the buckets which may not be written yet, the last interval and one more interval, or one minute
+// if longer.
+func metricAggregationSteps(req *LokiRequest, expr syntax.SampleExpr, rule *validation.MetricAggregationRule, now time.Time) (time.Time, time.Time, bool) {
+	step := time.Duration(req.Step) * time.Millisecond
+	if rule.StartTime.IsZero() || step <= 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	rangeInterval := expr.(*syntax.VectorAggregationExpr).Left.(*syntax.RangeAggregationExpr).Left.Interval
+	interval := time.Duration(rule.Interval)
+	from := rule.StartTime.Add(rangeInterval)
+	through := now.Add(-interval - max(interval, time.Minute))
+
+	first := req.StartTs
+	if first.Before(from) {
+		first = first.Add((from.Sub(first) + step - 1) / step * step)
+	}
+	last := req.EndTs
+	if last.After(through) {
+		last = through
+	}
+	if last.Before(first) {
+		return time.Time{}, time.Time{}, false
+	}
+	return first, req.StartTs.Add(last.Sub(req.StartTs) / step * step), true
+}
+
+func sameMatchers(a, b []*labels.Matcher) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as, bs := make([]string, 0, len(a)), make([]string, 0, len(b))
+	for i := range a {
+		as = append(as, a[i].String())
+		bs = append(bs, b[i].String())
+	}
+	slices.Sort(as)
+	slices.Sort(bs)
+	return slices.Equal(as, bs)
+}
+
+func subset(names, of []string) bool {
+	for _, name := range names {
+		if !slices.Contains(of, name) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package queryrange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestMetricAggregationQuery(t *testing.T) {
+	rules := []validation.MetricAggregationRule{
+		{Name: "hourly", Selector: `{namespace="prod"}`, Interval: model.Duration(time.Hour)},
+		{Name: "levels", Selector: `{namespace="prod", app=~"api|web"}`, By: []string{"service_name", "level"}, Interval: model.Duration(time.Minute)},
+	}
+	for i := range rules {
+		matchers, err := syntax.ParseMatchers(rules[i].Selector, true)
+		require.NoError(t, err)
+		rules[i].Matchers = matchers
+	}
+
+	for _, tc := range []struct {
+		query    string
+		step     time.Duration
+		expected string
+		rule     string
+	}{
+		{
+			query:    `sum by (level, service_name) (count_over_time({app=~"api|web", namespace="prod"}[5m]))`,
+			step:     time.Minute,
+			expected: `sum by (level,service_name)(max by (level,service_name,__aggregated_metric_owner__)(sum_over_time({__aggregated_metric__="levels"} | logfmt count="count" | unwrap count[5m])))`,
+			rule:     "levels",
+		},
+		{
+			query:    `sum(bytes_over_time({namespace="prod", app=~"api|web"}[1m]))`,
+			step:     2 * time.Minute,
+			expected: `sum(max by (__aggregated_metric_owner__)(sum_over_time({__aggregated_metric__="levels"} | logfmt bytes="bytes" | unwrap bytes[1m])))`,
+			rule:     "levels",
+		},
+		{
+			query:    `sum(count_over_time({namespace="prod"}[1h]))`,
+			step:     time.Hour,
+			expected: `sum(max by (__aggregated_metric_owner__)(sum_over_time({__aggregated_metric__="hourly"} | logfmt count="count" | unwrap count[1h])))`,
+			rule:     "hourly",
+		},
+		// The range or the step are not multiples of the interval.
+		{query: `sum(count_over_time({namespace="prod"}[30m]))`, step: time.Hour},
+		{query: `sum(count_over_time({namespace="prod"}[1h]))`, step: time.Minute},
+		// The grouping labels are not a subset of the rule labels.
+		{query: `sum by (pod) (count_over_time({namespace="prod", app=~"api|web"}[1m]))`, step: time.Minute},
+		{query: `sum without (level) (count_over_time({namespace="prod", app=~"api|web"}[1m]))`, step: time.Minute},
+		// The selector differs from the rules selectors.
+		{query: `sum(count_over_time({namespace="prod", app="api"}[1m]))`, step: time.Minute},
+		{query: `sum(count_over_time({namespace="prod"} |= "error" [1h]))`, step: time.Hour},
+		// Other aggregations.
+		{query: `max(count_over_time({namespace="prod"}[1h]))`, step: time.Hour},
+		{query: `sum(rate({namespace="prod"}[1h]))`, step: time.Hour},
+		{query: `sum(count_over_time({namespace="prod"}[1h] offset 1h))`, step: time.Hour},
+		{query: `count_over_time({namespace="prod"}[1h])`, step: time.Hour},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := syntax.ParseSampleExpr(tc.query)
+			require.NoError(t, err)
+
+			rewritten, rule, ok := metricAggregationQuery(expr, tc.step, rules)
+			if tc.expected == "" {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tc.rule, rule.Name)
+			require.Equal(t, tc.expected, rewritten.String())
+		})
+	}
+}
+
+func TestMetricAggregationSteps(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	rule := &validation.MetricAggregationRule{Name: "levels", Interval: model.Duration(time.Minute), StartTime: start}
+	expr, err := syntax.ParseSampleExpr(`sum(count_over_time({namespace="prod"}[5m]))`)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		from, to    time.Time
+		now         time.Time
+		rule        *validation.MetricAggregationRule
+		first, last time.Time
+	}{
+		{
+			name: "range covered",
+			from: start.Add(time.Hour), to: start.Add(2 * time.Hour), now: start.Add(3 * time.Hour),
+			rule:  rule,
+			first: start.Add(time.Hour), last: start.Add(2 * time.Hour),
+		},
+		{
+			name: "range starting before the rule",
+			from: start.Add(-time.Hour), to: start.Add(time.Hour), now: start.Add(3 * time.Hour),
+			rule:  rule,
+			first: start.Add(6 * time.Minute), last: start.Add(time.Hour),
+		},
+		{
+			name: "range ending in the buckets not written yet",
+			from: start.Add(time.Hour), to: start.Add(2 * time.Hour), now: start.Add(2 * time.Hour),
+			rule:  rule,
+			first: start.Add(time.Hour), last: start.Add(2*time.Hour - 2*time.Minute),
+		},
+		{
+			name: "range before the rule",
+			from: start.Add(-time.Hour), to: start, now: start.Add(3 * time.Hour),
+			rule: rule,
+		},
+		{
+			name: "rule without start",
+			from: start.Add(time.Hour), to: start.Add(2 * time.Hour), now: start.Add(3 * time.Hour),
+			rule: &validation.MetricAggregationRule{Name: "levels", Interval: model.Duration(time.Minute)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &LokiRequest{StartTs: tc.from, EndTs: tc.to, Step: (2 * time.Minute).Milliseconds()}
+			first, last, ok := metricAggregationSteps(req, expr, tc.rule, tc.now)
+			require.Equal(t, !tc.first.IsZero(), ok)
+			require.Equal(t, tc.first, first)
+			require.Equal(t, tc.last, last)
+		})
+	}
+}
@@ -892,6 +892,163 @@ func (m *QuantileSketchResponse) GetWarnings() []string {
 	return nil
 }
 
+type CountDistinctSketchResponse struct {
+	Response *github_com_grafana_loki_v3_pkg_logproto.CountDistinctSketchMatrix                                      `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/v3/pkg/logproto.CountDistinctSketchMatrix" json:"response,omitempty"`
+	Headers  []github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+	Warnings []string                                                                                                `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
+}
+
+func (m *CountDistinctSketchResponse) Reset()      { *m = CountDistinctSketchResponse{} }
+func (*CountDistinctSketchResponse) ProtoMessage() {}
+func (*CountDistinctSketchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{13}
+}
+func (m *CountDistinctSketchResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CountDistinctSketchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CountDistinctSketchResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CountDistinctSketchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountDistinctSketchResponse.Merge(m, src)
+}
+func (m *CountDistinctSketchResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CountDistinctSketchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountDistinctSketchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountDistinctSketchResponse proto.InternalMessageInfo
+
+func (m *CountDistinctSketchResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+// TableResponse is the result of a log query ending with a stats stage.
+type TableResponse struct {
+	Columns    []string                                                                                                `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows       []TableRow                                                                                              `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows"`
+	Statistics stats.Result                                                                                            `protobuf:"bytes,3,opt,name=statistics,proto3" json:"statistics"`
+	Headers    []github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,4,rep,name=Headers,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+	Warnings   []string                                                                                                `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
+}
+
+func (m *TableResponse) Reset()      { *m = TableResponse{} }
+func (*TableResponse) ProtoMessage() {}
+func (*TableResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{14}
+}
+func (m *TableResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TableResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableResponse.Merge(m, src)
+}
+func (m *TableResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TableResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableResponse proto.InternalMessageInfo
+
+func (m *TableResponse) GetColumns() []string {
+	if m != nil {
+		return m.Columns
+	}
+	return nil
+}
+
+func (m *TableResponse) GetRows() []TableRow {
+	if m != nil {
+		return m.Rows
+	}
+	return nil
+}
+
+func (m *TableResponse) GetStatistics() stats.Result {
+	if m != nil {
+		return m.Statistics
+	}
+	return stats.Result{}
+}
+
+func (m *TableResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+type TableRow struct {
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *TableRow) Reset()      { *m = TableRow{} }
+func (*TableRow) ProtoMessage() {}
+func (*TableRow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{15}
+}
+func (m *TableRow) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TableRow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TableRow.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TableRow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableRow.Merge(m, src)
+}
+func (m *TableRow) XXX_Size() int {
+	return m.Size()
+}
+func (m *TableRow) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableRow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableRow proto.InternalMessageInfo
+
+func (m *TableRow) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
 type ShardsResponse struct {
 	Response *github_com_grafana_loki_v3_pkg_logproto.ShardsResponse                                                 `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/v3/pkg/logproto.ShardsResponse" json:"response,omitempty"`
 	Headers  []github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
@@ -900,7 +1057,7 @@ type ShardsResponse struct {
 func (m *ShardsResponse) Reset()      { *m = ShardsResponse{} }
 func (*ShardsResponse) ProtoMessage() {}
 func (*ShardsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{13}
+	return fileDescriptor_51b9d53b40d11902, []int{16}
 }
 func (m *ShardsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -937,7 +1094,7 @@ type DetectedFieldsResponse struct {
 func (m *DetectedFieldsResponse) Reset()      { *m = DetectedFieldsResponse{} }
 func (*DetectedFieldsResponse) ProtoMessage() {}
 func (*DetectedFieldsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{14}
+	return fileDescriptor_51b9d53b40d11902, []int{17}
 }
 func (m *DetectedFieldsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -974,7 +1131,7 @@ type QueryPatternsResponse struct {
 func (m *QueryPatternsResponse) Reset()      { *m = QueryPatternsResponse{} }
 func (*QueryPatternsResponse) ProtoMessage() {}
 func (*QueryPatternsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{15}
+	return fileDescriptor_51b9d53b40d11902, []int{18}
 }
 func (m *QueryPatternsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1011,7 +1168,7 @@ type DetectedLabelsResponse struct {
 func (m *DetectedLabelsResponse) Reset()      { *m = DetectedLabelsResponse{} }
 func (*DetectedLabelsResponse) ProtoMessage() {}
 func (*DetectedLabelsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{16}
+	return fileDescriptor_51b9d53b40d11902, []int{19}
 }
 func (m *DetectedLabelsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1055,13 +1212,15 @@ type QueryResponse struct {
 	//	*QueryResponse_DetectedFields
 	//	*QueryResponse_PatternsResponse
 	//	*QueryResponse_DetectedLabels
+	//	*QueryResponse_CountDistinctSketches
+	//	*QueryResponse_Table
 	Response isQueryResponse_Response `protobuf_oneof:"response"`
 }
 
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{17}
+	return fileDescriptor_51b9d53b40d11902, []int{20}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1133,19 +1292,27 @@ type QueryResponse_PatternsResponse struct {
 type QueryResponse_DetectedLabels struct {
 	DetectedLabels *DetectedLabelsResponse `protobuf:"bytes,13,opt,name=detectedLabels,proto3,oneof"`
 }
+type QueryResponse_CountDistinctSketches struct {
+	CountDistinctSketches *CountDistinctSketchResponse `protobuf:"bytes,14,opt,name=countDistinctSketches,proto3,oneof"`
+}
+type QueryResponse_Table struct {
+	Table *TableResponse `protobuf:"bytes,15,opt,name=table,proto3,oneof"`
+}
 
-func (*QueryResponse_Series) isQueryResponse_Response()           {}
-func (*QueryResponse_Labels) isQueryResponse_Response()           {}
-func (*QueryResponse_Stats) isQueryResponse_Response()            {}
-func (*QueryResponse_Prom) isQueryResponse_Response()             {}
-func (*QueryResponse_Streams) isQueryResponse_Response()          {}
-func (*QueryResponse_Volume) isQueryResponse_Response()           {}
-func (*QueryResponse_TopkSketches) isQueryResponse_Response()     {}
-func (*QueryResponse_QuantileSketches) isQueryResponse_Response() {}
-func (*QueryResponse_ShardsResponse) isQueryResponse_Response()   {}
-func (*QueryResponse_DetectedFields) isQueryResponse_Response()   {}
-func (*QueryResponse_PatternsResponse) isQueryResponse_Response() {}
-func (*QueryResponse_DetectedLabels) isQueryResponse_Response()   {}
+func (*QueryResponse_Series) isQueryResponse_Response()                {}
+func (*QueryResponse_Labels) isQueryResponse_Response()                {}
+func (*QueryResponse_Stats) isQueryResponse_Response()                 {}
+func (*QueryResponse_Prom) isQueryResponse_Response()                  {}
+func (*QueryResponse_Streams) isQueryResponse_Response()               {}
+func (*QueryResponse_Volume) isQueryResponse_Response()                {}
+func (*QueryResponse_TopkSketches) isQueryResponse_Response()          {}
+func (*QueryResponse_QuantileSketches) isQueryResponse_Response()      {}
+func (*QueryResponse_ShardsResponse) isQueryResponse_Response()        {}
+func (*QueryResponse_DetectedFields) isQueryResponse_Response()        {}
+func (*QueryResponse_PatternsResponse) isQueryResponse_Response()      {}
+func (*QueryResponse_DetectedLabels) isQueryResponse_Response()        {}
+func (*QueryResponse_CountDistinctSketches) isQueryResponse_Response() {}
+func (*QueryResponse_Table) isQueryResponse_Response()                 {}
 
 func (m *QueryResponse) GetResponse() isQueryResponse_Response {
 	if m != nil {
@@ -1245,6 +1412,20 @@ func (m *QueryResponse) GetDetectedLabels() *DetectedLabelsResponse {
 	return nil
 }
 
+func (m *QueryResponse) GetCountDistinctSketches() *CountDistinctSketchResponse {
+	if x, ok := m.GetResponse().(*QueryResponse_CountDistinctSketches); ok {
+		return x.CountDistinctSketches
+	}
+	return nil
+}
+
+func (m *QueryResponse) GetTable() *TableResponse {
+	if x, ok := m.GetResponse().(*QueryResponse_Table); ok {
+		return x.Table
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1260,6 +1441,8 @@ func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 		(*QueryResponse_DetectedFields)(nil),
 		(*QueryResponse_PatternsResponse)(nil),
 		(*QueryResponse_DetectedLabels)(nil),
+		(*QueryResponse_CountDistinctSketches)(nil),
+		(*QueryResponse_Table)(nil),
 	}
 }
 
@@ -1282,7 +1465,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{18}
+	return fileDescriptor_51b9d53b40d11902, []int{21}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1474,6 +1657,9 @@ func init() {
 	proto.RegisterType((*VolumeResponse)(nil), "queryrange.VolumeResponse")
 	proto.RegisterType((*TopKSketchesResponse)(nil), "queryrange.TopKSketchesResponse")
 	proto.RegisterType((*QuantileSketchResponse)(nil), "queryrange.QuantileSketchResponse")
+	proto.RegisterType((*CountDistinctSketchResponse)(nil), "queryrange.CountDistinctSketchResponse")
+	proto.RegisterType((*TableResponse)(nil), "queryrange.TableResponse")
+	proto.RegisterType((*TableRow)(nil), "queryrange.TableRow")
 	proto.RegisterType((*ShardsResponse)(nil), "queryrange.ShardsResponse")
 	proto.RegisterType((*DetectedFieldsResponse)(nil), "queryrange.DetectedFieldsResponse")
 	proto.RegisterType((*QueryPatternsResponse)(nil), "queryrange.QueryPatternsResponse")
@@ -1488,128 +1674,138 @@ func init() {
 }
 
 var fileDescriptor_51b9d53b40d11902 = []byte{
-	// 1935 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcb, 0x6f, 0x23, 0x49,
-	0x19, 0x77, 0xfb, 0x19, 0x7f, 0x79, 0x4c, 0xa8, 0x09, 0xd9, 0x26, 0x3b, 0xeb, 0x36, 0x96, 0xd8,
-	0x0d, 0x08, 0xec, 0x1d, 0x67, 0x77, 0xd8, 0x0d, 0xc3, 0x68, 0xa7, 0x27, 0x33, 0x64, 0x86, 0x59,
-	0x76, 0xb6, 0x13, 0x71, 0xe0, 0x82, 0x2a, 0x76, 0xc5, 0x6e, 0x62, 0x77, 0xf7, 0x74, 0x97, 0x33,
-	0x13, 0x09, 0xa1, 0xfd, 0x07, 0x56, 0xec, 0x5f, 0x81, 0xb8, 0x71, 0xe1, 0xc4, 0x89, 0xe3, 0xee,
-	0x01, 0x69, 0x8e, 0x2b, 0x4b, 0x34, 0x8c, 0x07, 0x21, 0x94, 0xd3, 0x4a, 0x5c, 0x39, 0xa0, 0x7a,
-	0x74, 0xbb, 0xca, 0x76, 0x18, 0x27, 0x20, 0xa4, 0xc0, 0x5e, 0x92, 0x7a, 0x7c, 0xbf, 0xea, 0xaa,
-	0xdf, 0xf7, 0xfb, 0xbe, 0x7a, 0x18, 0xde, 0x08, 0x8e, 0x3a, 0x8d, 0xc7, 0x03, 0x12, 0xba, 0x24,
-	0xe4, 0xff, 0x4f, 0x42, 0xec, 0x75, 0x88, 0x52, 0xac, 0x07, 0xa1, 0x4f, 0x7d, 0x04, 0xe3, 0x96,
-	0x8d, 0x66, 0xc7, 0xa5, 0xdd, 0xc1, 0x41, 0xbd, 0xe5, 0xf7, 0x1b, 0x1d, 0xbf, 0xe3, 0x37, 0x3a,
-	0xbe, 0xdf, 0xe9, 0x11, 0x1c, 0xb8, 0x91, 0x2c, 0x36, 0xc2, 0xa0, 0xd5, 0x88, 0x28, 0xa6, 0x83,
-	0x48, 0xe0, 0x37, 0xd6, 0x98, 0x21, 0x2f, 0x72, 0x88, 0x6c, 0xb5, 0xa4, 0x39, 0xaf, 0x1d, 0x0c,
-	0x0e, 0x1b, 0xd4, 0xed, 0x93, 0x88, 0xe2, 0x7e, 0x90, 0x18, 0xb0, 0xf9, 0xf5, 0xfc, 0x8e, 0x40,
-	0xba, 0x5e, 0x9b, 0x3c, 0xed, 0x60, 0x4a, 0x9e, 0xe0, 0x13, 0x69, 0xf0, 0xaa, 0x66, 0x90, 0x14,
-	0x64, 0xe7, 0x86, 0xd6, 0x19, 0x60, 0x4a, 0x49, 0xe8, 0xc9, 0xbe, 0xaf, 0x69, 0x7d, 0xd1, 0x11,
-	0xa1, 0xad, 0xae, 0xec, 0xaa, 0xca, 0xae, 0xc7, 0xbd, 0xbe, 0xdf, 0x26, 0x3d, 0xbe, 0x90, 0x48,
-	0xfc, 0x95, 0x16, 0x57, 0x99, 0x45, 0x30, 0x88, 0xba, 0xfc, 0x8f, 0x6c, 0xbc, 0xf3, 0x52, 0x2e,
-	0x0f, 0x70, 0x44, 0x1a, 0x6d, 0x72, 0xe8, 0x7a, 0x2e, 0x75, 0x7d, 0x2f, 0x52, 0xcb, 0x72, 0x90,
-	0x1b, 0xf3, 0x0d, 0x32, 0xe9, 0x9f, 0x8d, 0x37, 0x19, 0x2e, 0xa2, 0x7e, 0x88, 0x3b, 0xa4, 0xd1,
-	0xea, 0x0e, 0xbc, 0xa3, 0x46, 0x0b, 0xb7, 0xba, 0xa4, 0x11, 0x92, 0x68, 0xd0, 0xa3, 0x91, 0xa8,
-	0xd0, 0x93, 0x80, 0xc8, 0x2f, 0xd5, 0x3e, 0xcb, 0xc3, 0xe2, 0x43, 0xff, 0xc8, 0x75, 0xc8, 0xe3,
-	0x01, 0x89, 0x28, 0x5a, 0x83, 0x02, 0x1f, 0xd5, 0x34, 0xaa, 0xc6, 0x66, 0xd9, 0x11, 0x15, 0xd6,
-	0xda, 0x73, 0xfb, 0x2e, 0x35, 0xb3, 0x55, 0x63, 0x73, 0xd9, 0x11, 0x15, 0x84, 0x20, 0x1f, 0x51,
-	0x12, 0x98, 0xb9, 0xaa, 0xb1, 0x99, 0x73, 0x78, 0x19, 0x6d, 0xc0, 0x82, 0xeb, 0x51, 0x12, 0x1e,
-	0xe3, 0x9e, 0x59, 0xe6, 0xed, 0x69, 0x1d, 0xdd, 0x82, 0x52, 0x44, 0x71, 0x48, 0xf7, 0x23, 0x33,
-	0x5f, 0x35, 0x36, 0x17, 0x9b, 0x1b, 0x75, 0xe1, 0xf9, 0x7a, 0xe2, 0xf9, 0xfa, 0x7e, 0xe2, 0x79,
-	0x7b, 0xe1, 0xd3, 0xd8, 0xca, 0x7c, 0xf2, 0x27, 0xcb, 0x70, 0x12, 0x10, 0xda, 0x86, 0x02, 0xf1,
-	0xda, 0xfb, 0x91, 0x59, 0x38, 0x07, 0x5a, 0x40, 0xd0, 0x75, 0x28, 0xb7, 0xdd, 0x90, 0xb4, 0x18,
-	0xcb, 0x66, 0xb1, 0x6a, 0x6c, 0xae, 0x34, 0xaf, 0xd6, 0x53, 0xa1, 0xec, 0x24, 0x5d, 0xce, 0xd8,
-	0x8a, 0x2d, 0x2f, 0xc0, 0xb4, 0x6b, 0x96, 0x38, 0x13, 0xbc, 0x8c, 0x6a, 0x50, 0x8c, 0xba, 0x38,
-	0x6c, 0x47, 0xe6, 0x42, 0x35, 0xb7, 0x59, 0xb6, 0xe1, 0x34, 0xb6, 0x64, 0x8b, 0x23, 0xff, 0xa3,
-	0x9f, 0x42, 0x3e, 0xe8, 0x61, 0xcf, 0x04, 0x3e, 0xcb, 0xd5, 0xba, 0xe2, 0xa5, 0x47, 0x3d, 0xec,
-	0xd9, 0xef, 0x0e, 0x63, 0xeb, 0x6d, 0x35, 0x78, 0x42, 0x7c, 0x88, 0x3d, 0xdc, 0xe8, 0xf9, 0x47,
-	0x6e, 0xe3, 0x78, 0xab, 0xa1, 0xfa, 0x9e, 0x0d, 0x54, 0xff, 0x90, 0x0d, 0xc0, 0xa0, 0x0e, 0x1f,
-	0x18, 0x3d, 0x80, 0x45, 0xe6, 0x63, 0x72, 0x87, 0x39, 0x38, 0x32, 0x17, 0xf9, 0x77, 0x5e, 0x19,
-	0xaf, 0x86, 0xb7, 0x3b, 0xe4, 0xf0, 0x07, 0xa1, 0x3f, 0x08, 0xec, 0x2b, 0xa7, 0xb1, 0xa5, 0xda,
-	0x3b, 0x6a, 0x05, 0x3d, 0x80, 0x15, 0x26, 0x0a, 0xd7, 0xeb, 0x7c, 0x10, 0x70, 0x05, 0x9a, 0x4b,
-	0x7c, 0xb8, 0x6b, 0x75, 0x55, 0x32, 0xf5, 0x3b, 0x9a, 0x8d, 0x9d, 0x67, 0xf4, 0x3a, 0x13, 0xc8,
-	0xda, 0x28, 0x07, 0x88, 0x69, 0xe9, 0xbe, 0x17, 0x51, 0xec, 0xd1, 0x8b, 0x48, 0xea, 0x26, 0x14,
-	0x59, 0xf0, 0xef, 0x47, 0x5c, 0x54, 0xf3, 0xfa, 0x58, 0x62, 0x74, 0x27, 0xe7, 0xcf, 0xe5, 0xe4,
-	0xc2, 0x4c, 0x27, 0x17, 0x5f, 0xea, 0xe4, 0xd2, 0x7f, 0xc9, 0xc9, 0x0b, 0xff, 0x59, 0x27, 0x97,
-	0x2f, 0xec, 0x64, 0x13, 0xf2, 0x6c, 0x96, 0x68, 0x15, 0x72, 0x21, 0x7e, 0xc2, 0x7d, 0xba, 0xe4,
-	0xb0, 0x62, 0x6d, 0x94, 0x87, 0x25, 0x91, 0x4a, 0xa2, 0xc0, 0xf7, 0x22, 0xc2, 0x78, 0xdc, 0xe3,
-	0xd9, 0x5f, 0x78, 0x5e, 0xf2, 0xc8, 0x5b, 0x1c, 0xd9, 0x83, 0xde, 0x83, 0xfc, 0x0e, 0xa6, 0x98,
-	0xab, 0x60, 0xb1, 0xb9, 0xa6, 0xf2, 0xc8, 0xc6, 0x62, 0x7d, 0xf6, 0x3a, 0x9b, 0xc8, 0x69, 0x6c,
-	0xad, 0xb4, 0x31, 0xc5, 0xdf, 0xf6, 0xfb, 0x2e, 0x25, 0xfd, 0x80, 0x9e, 0x38, 0x1c, 0x89, 0xde,
-	0x86, 0xf2, 0xdd, 0x30, 0xf4, 0xc3, 0xfd, 0x93, 0x80, 0x70, 0xd5, 0x94, 0xed, 0x57, 0x4e, 0x63,
-	0xeb, 0x2a, 0x49, 0x1a, 0x15, 0xc4, 0xd8, 0x12, 0x7d, 0x13, 0x0a, 0xbc, 0xc2, 0x75, 0x52, 0xb6,
-	0xaf, 0x9e, 0xc6, 0xd6, 0x15, 0x0e, 0x51, 0xcc, 0x85, 0x85, 0x2e, 0xab, 0xc2, 0x5c, 0xb2, 0x4a,
-	0xd5, 0x5d, 0x54, 0xd5, 0x6d, 0x42, 0xe9, 0x98, 0x84, 0x11, 0x1b, 0xa6, 0xc4, 0xdb, 0x93, 0x2a,
-	0xba, 0x0d, 0xc0, 0x88, 0x71, 0x23, 0xea, 0xb6, 0x12, 0x67, 0x2f, 0xd7, 0xc5, 0x66, 0xe3, 0x70,
-	0x1f, 0xd9, 0x48, 0xb2, 0xa0, 0x18, 0x3a, 0x4a, 0x19, 0xfd, 0xc6, 0x80, 0xd2, 0x2e, 0xc1, 0x6d,
-	0x12, 0x32, 0xf7, 0xe6, 0x36, 0x17, 0x9b, 0xdf, 0xa8, 0xab, 0x3b, 0xcb, 0xa3, 0xd0, 0xef, 0x13,
-	0xda, 0x25, 0x83, 0x28, 0x71, 0x90, 0xb0, 0xb6, 0xbd, 0x61, 0x6c, 0x91, 0x39, 0xa5, 0x3a, 0xd7,
-	0x86, 0x76, 0xe6, 0xa7, 0x4e, 0x63, 0xcb, 0xf8, 0x8e, 0x93, 0xcc, 0x12, 0x35, 0x61, 0xe1, 0x09,
-	0x0e, 0x3d, 0xd7, 0xeb, 0x44, 0x26, 0xf0, 0x48, 0x5b, 0x3f, 0x8d, 0x2d, 0x94, 0xb4, 0x29, 0x8e,
-	0x48, 0xed, 0x6a, 0x7f, 0x34, 0xe0, 0x2b, 0x4c, 0x18, 0x7b, 0x6c, 0x3e, 0x91, 0x92, 0x62, 0xfa,
-	0x98, 0xb6, 0xba, 0xa6, 0xc1, 0x86, 0x71, 0x44, 0x45, 0xdd, 0x6f, 0xb2, 0xff, 0xd6, 0x7e, 0x93,
-	0x3b, 0xff, 0x7e, 0x93, 0xe4, 0x95, 0xfc, 0xcc, 0xbc, 0x52, 0x38, 0x2b, 0xaf, 0xd4, 0x7e, 0x29,
-	0x73, 0x68, 0xb2, 0xbe, 0x73, 0x84, 0xd2, 0xbd, 0x34, 0x94, 0x72, 0x7c, 0xb6, 0xa9, 0x42, 0xc5,
-	0x58, 0xf7, 0xdb, 0xc4, 0xa3, 0xee, 0xa1, 0x4b, 0xc2, 0x97, 0x04, 0x94, 0xa2, 0xd2, 0x9c, 0xae,
-	0x52, 0x55, 0x62, 0xf9, 0x4b, 0x21, 0x31, 0x3d, 0xae, 0x0a, 0x17, 0x88, 0xab, 0xda, 0xdf, 0xb3,
-	0xb0, 0xce, 0x3c, 0xf2, 0x10, 0x1f, 0x90, 0xde, 0x8f, 0x70, 0xff, 0x9c, 0x5e, 0x79, 0x5d, 0xf1,
-	0x4a, 0xd9, 0x46, 0x5f, 0xb2, 0x3e, 0x1f, 0xeb, 0xbf, 0x32, 0x60, 0x21, 0xd9, 0x00, 0x50, 0x1d,
-	0x40, 0xc0, 0x78, 0x8e, 0x17, 0x5c, 0xaf, 0x30, 0x70, 0x98, 0xb6, 0x3a, 0x8a, 0x05, 0xfa, 0x19,
-	0x14, 0x45, 0x4d, 0xc6, 0x82, 0xb2, 0x6d, 0xee, 0xd1, 0x90, 0xe0, 0xfe, 0xed, 0x36, 0x0e, 0x28,
-	0x09, 0xed, 0x77, 0xd9, 0x2c, 0x86, 0xb1, 0xf5, 0xc6, 0x59, 0x2c, 0x25, 0x27, 0x7c, 0x89, 0x63,
-	0xfe, 0x15, 0xdf, 0x74, 0xe4, 0x17, 0x6a, 0x1f, 0x1b, 0xb0, 0xca, 0x26, 0xca, 0xa8, 0x49, 0x85,
-	0xb1, 0x03, 0x0b, 0xa1, 0x2c, 0xf3, 0xe9, 0x2e, 0x36, 0x6b, 0x75, 0x9d, 0xd6, 0x19, 0x54, 0xf2,
-	0x0d, 0xd7, 0x70, 0x52, 0x24, 0xda, 0xd2, 0x68, 0xcc, 0xce, 0xa2, 0x51, 0xec, 0xd1, 0x2a, 0x71,
-	0xbf, 0xcf, 0x02, 0xba, 0xcf, 0x6e, 0x48, 0x4c, 0x7f, 0x63, 0xa9, 0x3e, 0x9d, 0x9a, 0xd1, 0xb5,
-	0x31, 0x29, 0xd3, 0xf6, 0xf6, 0xad, 0x61, 0x6c, 0x6d, 0xbf, 0x44, 0x3b, 0xff, 0x02, 0xaf, 0xac,
-	0x42, 0x95, 0x6f, 0xf6, 0x32, 0xc8, 0xb7, 0xf6, 0xdb, 0x2c, 0xac, 0xfc, 0xd8, 0xef, 0x0d, 0xfa,
-	0x24, 0xa5, 0x2f, 0x98, 0xa2, 0xcf, 0x1c, 0xd3, 0xa7, 0xdb, 0xda, 0xdb, 0xc3, 0xd8, 0xba, 0x31,
-	0x2f, 0x75, 0x3a, 0xf6, 0x52, 0xd3, 0xf6, 0xd7, 0x2c, 0xac, 0xed, 0xfb, 0xc1, 0x0f, 0xf7, 0xf8,
-	0x2d, 0x5a, 0x49, 0x93, 0xdd, 0x29, 0xf2, 0xd6, 0xc6, 0xe4, 0x31, 0xc4, 0xfb, 0x98, 0x86, 0xee,
-	0x53, 0xfb, 0xc6, 0x30, 0xb6, 0x9a, 0xf3, 0x12, 0x37, 0xc6, 0x5d, 0x66, 0xd2, 0xb4, 0x33, 0x50,
-	0x6e, 0xce, 0x33, 0xd0, 0x3f, 0xb2, 0xb0, 0xfe, 0xe1, 0x00, 0x7b, 0xd4, 0xed, 0x11, 0x41, 0x76,
-	0x4a, 0xf5, 0xcf, 0xa7, 0xa8, 0xae, 0x8c, 0xa9, 0xd6, 0x31, 0x92, 0xf4, 0xf7, 0x86, 0xb1, 0x75,
-	0x73, 0x5e, 0xd2, 0x67, 0x8d, 0xf0, 0x7f, 0x47, 0xff, 0xef, 0xb2, 0xb0, 0xb2, 0x27, 0x4e, 0x6d,
-	0xc9, 0xc2, 0x8f, 0x67, 0xd0, 0xae, 0x3e, 0x53, 0x05, 0x07, 0x75, 0x1d, 0x71, 0xbe, 0x24, 0xa1,
-	0x63, 0x2f, 0x75, 0x92, 0xf8, 0x43, 0x16, 0xd6, 0x77, 0x08, 0x25, 0x2d, 0x4a, 0xda, 0xf7, 0x5c,
-	0xd2, 0x53, 0x48, 0xfc, 0xc8, 0x98, 0x62, 0xb1, 0xaa, 0x5c, 0xb3, 0x66, 0x82, 0x6c, 0x7b, 0x18,
-	0x5b, 0xb7, 0xe6, 0xe5, 0x71, 0xf6, 0x18, 0x97, 0x9a, 0xcf, 0xcf, 0xb2, 0xf0, 0x55, 0xf1, 0x74,
-	0x20, 0xde, 0x35, 0xc7, 0x74, 0xfe, 0x62, 0x8a, 0x4d, 0x4b, 0x4d, 0x05, 0x33, 0x20, 0xf6, 0xed,
-	0x61, 0x6c, 0x7d, 0x7f, 0xfe, 0x5c, 0x30, 0x63, 0x88, 0xff, 0x19, 0x6d, 0xf2, 0xd3, 0xfe, 0x79,
-	0xb5, 0xa9, 0x83, 0x2e, 0xa6, 0x4d, 0x7d, 0x8c, 0x4b, 0xcd, 0xe7, 0x5f, 0x8a, 0xb0, 0xcc, 0x55,
-	0x92, 0xd2, 0xf8, 0x2d, 0x90, 0xd7, 0x23, 0xc9, 0x21, 0x4a, 0xae, 0xd4, 0x61, 0xd0, 0xaa, 0xef,
-	0xc9, 0x8b, 0x93, 0xb0, 0x40, 0xef, 0x40, 0x31, 0xe2, 0x17, 0x57, 0x79, 0xf2, 0xad, 0x4c, 0xbe,
-	0x0d, 0xe9, 0x57, 0xe4, 0xdd, 0x8c, 0x23, 0xed, 0xd1, 0x4d, 0x28, 0xf6, 0x38, 0x8b, 0xf2, 0xe2,
-	0x5e, 0x9b, 0x44, 0x4e, 0x5f, 0xe5, 0x18, 0x5a, 0x60, 0xd0, 0x0d, 0x28, 0xf0, 0x23, 0xb6, 0x7c,
-	0xa3, 0xd6, 0x3e, 0x3b, 0x7d, 0xd0, 0xdd, 0xcd, 0x38, 0xc2, 0x1c, 0x35, 0x21, 0x1f, 0x84, 0x7e,
-	0x5f, 0x5e, 0x77, 0xae, 0x4d, 0x7e, 0x53, 0xbd, 0x1f, 0xec, 0x66, 0x1c, 0x6e, 0x8b, 0xde, 0x82,
-	0x52, 0xc4, 0x2f, 0x16, 0x11, 0x7f, 0x28, 0x62, 0xa7, 0xca, 0x09, 0x98, 0x02, 0x49, 0x4c, 0xd1,
-	0x5b, 0x50, 0x3c, 0xe6, 0xc7, 0x46, 0xf9, 0xfa, 0xb8, 0xa1, 0x82, 0xf4, 0x03, 0x25, 0x5b, 0x97,
-	0xb0, 0x45, 0xf7, 0x60, 0x89, 0xfa, 0xc1, 0x51, 0x72, 0x3a, 0x93, 0x8f, 0x4c, 0x55, 0x15, 0x3b,
-	0xeb, 0xf4, 0xb6, 0x9b, 0x71, 0x34, 0x1c, 0x7a, 0x04, 0xab, 0x8f, 0xb5, 0x63, 0x00, 0x49, 0x9e,
-	0x13, 0x35, 0x9e, 0x67, 0x1f, 0x50, 0x76, 0x33, 0xce, 0x14, 0x1a, 0xed, 0xc0, 0x4a, 0xa4, 0xed,
-	0x70, 0xf2, 0xe9, 0x5c, 0x5b, 0x97, 0xbe, 0x07, 0xee, 0x66, 0x9c, 0x09, 0x0c, 0x7a, 0x08, 0x2b,
-	0x6d, 0x2d, 0xbf, 0xcb, 0x87, 0x71, 0x6d, 0x56, 0xb3, 0x77, 0x00, 0x36, 0x9a, 0x8e, 0x45, 0x1f,
-	0xc0, 0x6a, 0x30, 0x91, 0xdb, 0xe4, 0xcb, 0xf8, 0xd7, 0xf5, 0x55, 0xce, 0x48, 0x82, 0x6c, 0x91,
-	0x93, 0x60, 0x75, 0x7a, 0x22, 0xc4, 0xcd, 0xe5, 0xb3, 0xa7, 0xa7, 0x27, 0x01, 0x75, 0x7a, 0xa2,
-	0xc7, 0x86, 0x71, 0x3a, 0xaa, 0x7d, 0x5c, 0x84, 0x25, 0x19, 0x66, 0xe2, 0x35, 0xec, 0xbb, 0x69,
-	0xe4, 0x88, 0x28, 0x7b, 0xed, 0xac, 0xc8, 0xe1, 0xe6, 0x4a, 0xe0, 0xbc, 0x99, 0x06, 0x8e, 0x08,
-	0xb9, 0xf5, 0x71, 0x8a, 0xe3, 0xdf, 0x55, 0x10, 0x32, 0x58, 0xb6, 0x92, 0x60, 0x11, 0x91, 0xf6,
-	0xea, 0xec, 0x3b, 0x65, 0x82, 0x92, 0x91, 0xb2, 0x0d, 0x25, 0x57, 0xfc, 0x44, 0x30, 0x2b, 0xc6,
-	0xa6, 0x7f, 0x41, 0x60, 0xda, 0x97, 0x00, 0xb4, 0x35, 0x8e, 0x98, 0x82, 0x7c, 0x12, 0x9f, 0x8a,
-	0x98, 0x14, 0x94, 0x04, 0xcc, 0xf5, 0x34, 0x60, 0x8a, 0x93, 0xcf, 0xe8, 0x49, 0xb8, 0xa4, 0x0b,
-	0x93, 0xd1, 0x72, 0x17, 0x96, 0x13, 0x7d, 0xf1, 0x2e, 0x19, 0x2e, 0xaf, 0x9d, 0x75, 0xac, 0x4b,
-	0xf0, 0x3a, 0x0a, 0xdd, 0x9f, 0x12, 0x65, 0x79, 0x72, 0x2b, 0x9e, 0x94, 0x64, 0x32, 0xd2, 0xa4,
-	0x22, 0x1f, 0xc0, 0x95, 0xb1, 0xa8, 0xc4, 0x9c, 0x60, 0xfa, 0x84, 0xaf, 0xc9, 0x31, 0x19, 0x6a,
-	0x12, 0xa8, 0x4e, 0x4b, 0x8a, 0x71, 0xf1, 0xac, 0x69, 0x25, 0x52, 0x9c, 0x9a, 0x96, 0xe8, 0x40,
-	0xbb, 0xb0, 0xd0, 0x27, 0x14, 0xb7, 0x31, 0xc5, 0x66, 0x89, 0x6f, 0x4b, 0xaf, 0x4f, 0x05, 0x88,
-	0x44, 0xd7, 0xdf, 0x97, 0x86, 0x77, 0x3d, 0x1a, 0x9e, 0xc8, 0xb7, 0x8b, 0x14, 0xbd, 0xf1, 0x3d,
-	0x58, 0xd6, 0x0c, 0xd0, 0x2a, 0xe4, 0x8e, 0x48, 0xf2, 0xb3, 0x11, 0x2b, 0xa2, 0x35, 0x28, 0x1c,
-	0xe3, 0xde, 0x80, 0x70, 0x7d, 0x96, 0x1d, 0x51, 0xd9, 0xce, 0xbe, 0x63, 0xd8, 0x65, 0x28, 0x85,
-	0xe2, 0x2b, 0x76, 0xe7, 0xd9, 0xf3, 0x4a, 0xe6, 0xf3, 0xe7, 0x95, 0xcc, 0x17, 0xcf, 0x2b, 0xc6,
-	0x47, 0xa3, 0x8a, 0xf1, 0xeb, 0x51, 0xc5, 0xf8, 0x74, 0x54, 0x31, 0x9e, 0x8d, 0x2a, 0xc6, 0x9f,
-	0x47, 0x15, 0xe3, 0x6f, 0xa3, 0x4a, 0xe6, 0x8b, 0x51, 0xc5, 0xf8, 0xe4, 0x45, 0x25, 0xf3, 0xec,
-	0x45, 0x25, 0xf3, 0xf9, 0x8b, 0x4a, 0xe6, 0x27, 0xd7, 0xcf, 0xbd, 0x43, 0x1e, 0x14, 0x39, 0x53,
-	0x5b, 0xff, 0x0c, 0x00, 0x00, 0xff, 0xff, 0xd4, 0x35, 0x32, 0x52, 0x3f, 0x1f, 0x00, 0x00,
+	// 2091 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcb, 0x6f, 0x1b, 0xc7,
+	0x19, 0xe7, 0xf2, 0x29, 0x7e, 0x7a, 0x58, 0x1d, 0x2b, 0xca, 0x46, 0x76, 0xb8, 0x2a, 0x8b, 0xc6,
+	0x6a, 0xd1, 0x92, 0xb1, 0x94, 0xb8, 0x89, 0xea, 0x1a, 0xf1, 0x5a, 0x76, 0x65, 0xd7, 0x69, 0x9c,
+	0x95, 0xd0, 0x43, 0x2f, 0xc1, 0x88, 0x1c, 0x91, 0x5b, 0x91, 0xbb, 0xeb, 0xdd, 0xa1, 0x64, 0x01,
+	0x45, 0x11, 0xf4, 0xd0, 0x53, 0x83, 0xe6, 0xaf, 0x28, 0x7a, 0xeb, 0xa5, 0xa7, 0x9e, 0x7a, 0x4c,
+	0x0e, 0x05, 0x7c, 0x0c, 0x08, 0x94, 0xad, 0x69, 0xa0, 0x28, 0x74, 0x0a, 0xd0, 0x4b, 0x0f, 0x3d,
+	0x14, 0xf3, 0xd8, 0xe5, 0x0c, 0x97, 0x8a, 0x29, 0xa7, 0x28, 0xa0, 0x3a, 0x17, 0x72, 0x1e, 0xdf,
+	0xef, 0xdb, 0xd9, 0xdf, 0xf7, 0x9a, 0x99, 0x85, 0x2b, 0xc1, 0x41, 0xab, 0xfe, 0xb0, 0x47, 0x42,
+	0x97, 0x84, 0xfc, 0xff, 0x38, 0xc4, 0x5e, 0x8b, 0x28, 0xcd, 0x5a, 0x10, 0xfa, 0xd4, 0x47, 0x30,
+	0x1a, 0x59, 0x59, 0x6f, 0xb9, 0xb4, 0xdd, 0xdb, 0xab, 0x35, 0xfc, 0x6e, 0xbd, 0xe5, 0xb7, 0xfc,
+	0x7a, 0xcb, 0xf7, 0x5b, 0x1d, 0x82, 0x03, 0x37, 0x92, 0xcd, 0x7a, 0x18, 0x34, 0xea, 0x11, 0xc5,
+	0xb4, 0x17, 0x09, 0xfc, 0xca, 0x12, 0x13, 0xe4, 0x4d, 0x0e, 0x91, 0xa3, 0x96, 0x14, 0xe7, 0xbd,
+	0xbd, 0xde, 0x7e, 0x9d, 0xba, 0x5d, 0x12, 0x51, 0xdc, 0x0d, 0x62, 0x01, 0xb6, 0xbe, 0x8e, 0xdf,
+	0x12, 0x48, 0xd7, 0x6b, 0x92, 0x47, 0x2d, 0x4c, 0xc9, 0x11, 0x3e, 0x96, 0x02, 0x97, 0x34, 0x81,
+	0xb8, 0x21, 0x27, 0x57, 0xb4, 0xc9, 0x00, 0x53, 0x4a, 0x42, 0x4f, 0xce, 0xbd, 0xa2, 0xcd, 0x45,
+	0x07, 0x84, 0x36, 0xda, 0x72, 0x6a, 0x55, 0x4e, 0x3d, 0xec, 0x74, 0xfd, 0x26, 0xe9, 0xf0, 0x17,
+	0x89, 0xc4, 0xaf, 0x94, 0xb8, 0xc8, 0x24, 0x82, 0x5e, 0xd4, 0xe6, 0x3f, 0x72, 0xf0, 0xd6, 0x33,
+	0xb9, 0xdc, 0xc3, 0x11, 0xa9, 0x37, 0xc9, 0xbe, 0xeb, 0xb9, 0xd4, 0xf5, 0xbd, 0x48, 0x6d, 0x4b,
+	0x25, 0xd7, 0xa6, 0x53, 0x32, 0x6e, 0x9f, 0x95, 0xd7, 0x19, 0x2e, 0xa2, 0x7e, 0x88, 0x5b, 0xa4,
+	0xde, 0x68, 0xf7, 0xbc, 0x83, 0x7a, 0x03, 0x37, 0xda, 0xa4, 0x1e, 0x92, 0xa8, 0xd7, 0xa1, 0x91,
+	0xe8, 0xd0, 0xe3, 0x80, 0xc8, 0x27, 0x55, 0x3f, 0xcd, 0xc3, 0xec, 0x7d, 0xff, 0xc0, 0x75, 0xc8,
+	0xc3, 0x1e, 0x89, 0x28, 0x5a, 0x82, 0x02, 0xd7, 0x6a, 0x1a, 0xab, 0xc6, 0x5a, 0xd9, 0x11, 0x1d,
+	0x36, 0xda, 0x71, 0xbb, 0x2e, 0x35, 0xb3, 0xab, 0xc6, 0xda, 0xbc, 0x23, 0x3a, 0x08, 0x41, 0x3e,
+	0xa2, 0x24, 0x30, 0x73, 0xab, 0xc6, 0x5a, 0xce, 0xe1, 0x6d, 0xb4, 0x02, 0x33, 0xae, 0x47, 0x49,
+	0x78, 0x88, 0x3b, 0x66, 0x99, 0x8f, 0x27, 0x7d, 0x74, 0x03, 0x4a, 0x11, 0xc5, 0x21, 0xdd, 0x8d,
+	0xcc, 0xfc, 0xaa, 0xb1, 0x36, 0xbb, 0xbe, 0x52, 0x13, 0x96, 0xaf, 0xc5, 0x96, 0xaf, 0xed, 0xc6,
+	0x96, 0xb7, 0x67, 0x3e, 0x19, 0x58, 0x99, 0x8f, 0xff, 0x6a, 0x19, 0x4e, 0x0c, 0x42, 0x9b, 0x50,
+	0x20, 0x5e, 0x73, 0x37, 0x32, 0x0b, 0x67, 0x40, 0x0b, 0x08, 0xba, 0x0a, 0xe5, 0xa6, 0x1b, 0x92,
+	0x06, 0x63, 0xd9, 0x2c, 0xae, 0x1a, 0x6b, 0x0b, 0xeb, 0x17, 0x6b, 0x89, 0xa3, 0x6c, 0xc5, 0x53,
+	0xce, 0x48, 0x8a, 0xbd, 0x5e, 0x80, 0x69, 0xdb, 0x2c, 0x71, 0x26, 0x78, 0x1b, 0x55, 0xa1, 0x18,
+	0xb5, 0x71, 0xd8, 0x8c, 0xcc, 0x99, 0xd5, 0xdc, 0x5a, 0xd9, 0x86, 0x93, 0x81, 0x25, 0x47, 0x1c,
+	0xf9, 0x8f, 0x3e, 0x80, 0x7c, 0xd0, 0xc1, 0x9e, 0x09, 0x7c, 0x95, 0x8b, 0x35, 0xc5, 0x4a, 0x0f,
+	0x3a, 0xd8, 0xb3, 0xdf, 0xee, 0x0f, 0xac, 0x37, 0xd5, 0xe0, 0x09, 0xf1, 0x3e, 0xf6, 0x70, 0xbd,
+	0xe3, 0x1f, 0xb8, 0xf5, 0xc3, 0x8d, 0xba, 0x6a, 0x7b, 0xa6, 0xa8, 0xf6, 0x3e, 0x53, 0xc0, 0xa0,
+	0x0e, 0x57, 0x8c, 0xee, 0xc1, 0x2c, 0xb3, 0x31, 0xb9, 0xc5, 0x0c, 0x1c, 0x99, 0xb3, 0xfc, 0x39,
+	0x2f, 0x8f, 0xde, 0x86, 0x8f, 0x3b, 0x64, 0xff, 0x87, 0xa1, 0xdf, 0x0b, 0xec, 0x0b, 0x27, 0x03,
+	0x4b, 0x95, 0x77, 0xd4, 0x0e, 0xba, 0x07, 0x0b, 0xcc, 0x29, 0x5c, 0xaf, 0xf5, 0x5e, 0xc0, 0x3d,
+	0xd0, 0x9c, 0xe3, 0xea, 0x2e, 0xd7, 0x54, 0x97, 0xa9, 0xdd, 0xd2, 0x64, 0xec, 0x3c, 0xa3, 0xd7,
+	0x19, 0x43, 0x56, 0x87, 0x39, 0x40, 0xcc, 0x97, 0xee, 0x7a, 0x11, 0xc5, 0x1e, 0x7d, 0x1e, 0x97,
+	0xba, 0x0e, 0x45, 0x16, 0xfc, 0xbb, 0x11, 0x77, 0xaa, 0x69, 0x6d, 0x2c, 0x31, 0xba, 0x91, 0xf3,
+	0x67, 0x32, 0x72, 0x61, 0xa2, 0x91, 0x8b, 0xcf, 0x34, 0x72, 0xe9, 0x7f, 0x64, 0xe4, 0x99, 0xff,
+	0xae, 0x91, 0xcb, 0xcf, 0x6d, 0x64, 0x13, 0xf2, 0x6c, 0x95, 0x68, 0x11, 0x72, 0x21, 0x3e, 0xe2,
+	0x36, 0x9d, 0x73, 0x58, 0xb3, 0x3a, 0xcc, 0xc3, 0x9c, 0x48, 0x25, 0x51, 0xe0, 0x7b, 0x11, 0x61,
+	0x3c, 0xee, 0xf0, 0xec, 0x2f, 0x2c, 0x2f, 0x79, 0xe4, 0x23, 0x8e, 0x9c, 0x41, 0xef, 0x40, 0x7e,
+	0x0b, 0x53, 0xcc, 0xbd, 0x60, 0x76, 0x7d, 0x49, 0xe5, 0x91, 0xe9, 0x62, 0x73, 0xf6, 0x32, 0x5b,
+	0xc8, 0xc9, 0xc0, 0x5a, 0x68, 0x62, 0x8a, 0xbf, 0xe3, 0x77, 0x5d, 0x4a, 0xba, 0x01, 0x3d, 0x76,
+	0x38, 0x12, 0xbd, 0x09, 0xe5, 0xdb, 0x61, 0xe8, 0x87, 0xbb, 0xc7, 0x01, 0xe1, 0x5e, 0x53, 0xb6,
+	0x5f, 0x3e, 0x19, 0x58, 0x17, 0x49, 0x3c, 0xa8, 0x20, 0x46, 0x92, 0xe8, 0x5b, 0x50, 0xe0, 0x1d,
+	0xee, 0x27, 0x65, 0xfb, 0xe2, 0xc9, 0xc0, 0xba, 0xc0, 0x21, 0x8a, 0xb8, 0x90, 0xd0, 0xdd, 0xaa,
+	0x30, 0x95, 0x5b, 0x25, 0xde, 0x5d, 0x54, 0xbd, 0xdb, 0x84, 0xd2, 0x21, 0x09, 0x23, 0xa6, 0xa6,
+	0xc4, 0xc7, 0xe3, 0x2e, 0xba, 0x09, 0xc0, 0x88, 0x71, 0x23, 0xea, 0x36, 0x62, 0x63, 0xcf, 0xd7,
+	0x44, 0xb1, 0x71, 0xb8, 0x8d, 0x6c, 0x24, 0x59, 0x50, 0x04, 0x1d, 0xa5, 0x8d, 0x7e, 0x6f, 0x40,
+	0x69, 0x9b, 0xe0, 0x26, 0x09, 0x99, 0x79, 0x73, 0x6b, 0xb3, 0xeb, 0xdf, 0xac, 0xa9, 0x95, 0xe5,
+	0x41, 0xe8, 0x77, 0x09, 0x6d, 0x93, 0x5e, 0x14, 0x1b, 0x48, 0x48, 0xdb, 0x5e, 0x7f, 0x60, 0x91,
+	0x29, 0x5d, 0x75, 0xaa, 0x82, 0x76, 0xea, 0xa3, 0x4e, 0x06, 0x96, 0xf1, 0x5d, 0x27, 0x5e, 0x25,
+	0x5a, 0x87, 0x99, 0x23, 0x1c, 0x7a, 0xae, 0xd7, 0x8a, 0x4c, 0xe0, 0x91, 0xb6, 0x7c, 0x32, 0xb0,
+	0x50, 0x3c, 0xa6, 0x18, 0x22, 0x91, 0xab, 0xfe, 0xc5, 0x80, 0xaf, 0x31, 0xc7, 0xd8, 0x61, 0xeb,
+	0x89, 0x94, 0x14, 0xd3, 0xc5, 0xb4, 0xd1, 0x36, 0x0d, 0xa6, 0xc6, 0x11, 0x1d, 0xb5, 0xde, 0x64,
+	0xbf, 0x54, 0xbd, 0xc9, 0x9d, 0xbd, 0xde, 0xc4, 0x79, 0x25, 0x3f, 0x31, 0xaf, 0x14, 0x4e, 0xcb,
+	0x2b, 0xd5, 0xdf, 0xc8, 0x1c, 0x1a, 0xbf, 0xdf, 0x19, 0x42, 0xe9, 0x4e, 0x12, 0x4a, 0x39, 0xbe,
+	0xda, 0xc4, 0x43, 0x85, 0xae, 0xbb, 0x4d, 0xe2, 0x51, 0x77, 0xdf, 0x25, 0xe1, 0x33, 0x02, 0x4a,
+	0xf1, 0xd2, 0x9c, 0xee, 0xa5, 0xaa, 0x8b, 0xe5, 0xcf, 0x85, 0x8b, 0xe9, 0x71, 0x55, 0x78, 0x8e,
+	0xb8, 0xaa, 0xfe, 0x33, 0x0b, 0xcb, 0xcc, 0x22, 0xf7, 0xf1, 0x1e, 0xe9, 0xfc, 0x18, 0x77, 0xcf,
+	0x68, 0x95, 0xd7, 0x14, 0xab, 0x94, 0x6d, 0xf4, 0x15, 0xeb, 0xd3, 0xb1, 0xfe, 0x5b, 0x03, 0x66,
+	0xe2, 0x02, 0x80, 0x6a, 0x00, 0x02, 0xc6, 0x73, 0xbc, 0xe0, 0x7a, 0x81, 0x81, 0xc3, 0x64, 0xd4,
+	0x51, 0x24, 0xd0, 0xcf, 0xa0, 0x28, 0x7a, 0x32, 0x16, 0x94, 0xb2, 0xb9, 0x43, 0x43, 0x82, 0xbb,
+	0x37, 0x9b, 0x38, 0xa0, 0x24, 0xb4, 0xdf, 0x66, 0xab, 0xe8, 0x0f, 0xac, 0x2b, 0xa7, 0xb1, 0x14,
+	0xef, 0xf0, 0x25, 0x8e, 0xd9, 0x57, 0x3c, 0xd3, 0x91, 0x4f, 0xa8, 0x7e, 0x64, 0xc0, 0x22, 0x5b,
+	0x28, 0xa3, 0x26, 0x71, 0x8c, 0x2d, 0x98, 0x09, 0x65, 0x9b, 0x2f, 0x77, 0x76, 0xbd, 0x5a, 0xd3,
+	0x69, 0x9d, 0x40, 0x25, 0x2f, 0xb8, 0x86, 0x93, 0x20, 0xd1, 0x86, 0x46, 0x63, 0x76, 0x12, 0x8d,
+	0xa2, 0x46, 0xab, 0xc4, 0xfd, 0x29, 0x0b, 0xe8, 0x2e, 0x3b, 0x21, 0x31, 0xff, 0x1b, 0xb9, 0xea,
+	0xa3, 0xd4, 0x8a, 0x2e, 0x8f, 0x48, 0x49, 0xcb, 0xdb, 0x37, 0xfa, 0x03, 0x6b, 0xf3, 0x19, 0xbe,
+	0xf3, 0x05, 0x78, 0xe5, 0x2d, 0x54, 0xf7, 0xcd, 0x9e, 0x07, 0xf7, 0xad, 0xfe, 0x21, 0x0b, 0x0b,
+	0x3f, 0xf1, 0x3b, 0xbd, 0x2e, 0x49, 0xe8, 0x0b, 0x52, 0xf4, 0x99, 0x23, 0xfa, 0x74, 0x59, 0x7b,
+	0xb3, 0x3f, 0xb0, 0xae, 0x4d, 0x4b, 0x9d, 0x8e, 0x3d, 0xd7, 0xb4, 0xfd, 0x3d, 0x0b, 0x4b, 0xbb,
+	0x7e, 0xf0, 0xa3, 0x1d, 0x7e, 0x8a, 0x56, 0xd2, 0x64, 0x3b, 0x45, 0xde, 0xd2, 0x88, 0x3c, 0x86,
+	0x78, 0x17, 0xd3, 0xd0, 0x7d, 0x64, 0x5f, 0xeb, 0x0f, 0xac, 0xf5, 0x69, 0x89, 0x1b, 0xe1, 0xce,
+	0x33, 0x69, 0xda, 0x1e, 0x28, 0x37, 0xe5, 0x1e, 0xe8, 0xdf, 0x59, 0x58, 0x7e, 0xbf, 0x87, 0x3d,
+	0xea, 0x76, 0x88, 0x20, 0x3b, 0xa1, 0xfa, 0xe7, 0x29, 0xaa, 0x2b, 0x23, 0xaa, 0x75, 0x8c, 0x24,
+	0xfd, 0x9d, 0xfe, 0xc0, 0xba, 0x3e, 0x2d, 0xe9, 0x93, 0x34, 0xbc, 0x70, 0xf4, 0xff, 0x3a, 0x07,
+	0x97, 0x6e, 0xf9, 0x3d, 0x8f, 0x6e, 0xb1, 0x94, 0xeb, 0x35, 0xe8, 0x98, 0x0d, 0x7e, 0x65, 0xa4,
+	0x8c, 0xf0, 0x0d, 0xe5, 0xdc, 0x96, 0x46, 0x4a, 0x4b, 0xdc, 0xee, 0x0f, 0xac, 0x9b, 0xd3, 0x5a,
+	0xe2, 0x54, 0x35, 0x2f, 0x9c, 0x39, 0x7e, 0x99, 0x83, 0xf9, 0x5d, 0xbc, 0xd7, 0x19, 0x25, 0x6b,
+	0x13, 0x4a, 0x0d, 0x96, 0x56, 0xbd, 0x48, 0x9e, 0x07, 0xe2, 0x2e, 0xaa, 0x41, 0x3e, 0xf4, 0x8f,
+	0x62, 0x36, 0xb4, 0xd3, 0xa6, 0x50, 0xe1, 0x1f, 0xc9, 0x92, 0xca, 0xe5, 0xc6, 0x36, 0x32, 0xb9,
+	0x2f, 0x7b, 0x2c, 0xcb, 0x9f, 0x3b, 0x23, 0x14, 0xa6, 0x34, 0x42, 0x15, 0x66, 0x62, 0x02, 0xd1,
+	0x32, 0x14, 0x0f, 0x71, 0xa7, 0x47, 0x62, 0xf6, 0x65, 0xaf, 0xfa, 0xc7, 0x2c, 0x2c, 0xec, 0x88,
+	0xd3, 0x4e, 0x6c, 0xa9, 0xc3, 0x09, 0xe9, 0x4a, 0xbd, 0xde, 0x0d, 0xf6, 0x6a, 0x3a, 0xe2, 0x6c,
+	0xc5, 0x55, 0xc7, 0x9e, 0xeb, 0xe2, 0xfa, 0xe7, 0x2c, 0x2c, 0x6f, 0x11, 0x4a, 0x1a, 0x94, 0x34,
+	0xef, 0xb8, 0xa4, 0xa3, 0x90, 0xf8, 0x61, 0x3a, 0xdf, 0xac, 0x2a, 0xd7, 0x13, 0x13, 0x41, 0xb6,
+	0xdd, 0x1f, 0x58, 0x37, 0xa6, 0xe5, 0x71, 0xb2, 0x8e, 0x73, 0xcd, 0xe7, 0xa7, 0x59, 0x78, 0x49,
+	0x5c, 0xb9, 0x89, 0xef, 0x01, 0x23, 0x3a, 0x7f, 0x91, 0x62, 0xd3, 0x52, 0x4b, 0xe8, 0x04, 0x88,
+	0x7d, 0xb3, 0x3f, 0xb0, 0x7e, 0x30, 0x7d, 0x0d, 0x9d, 0xa0, 0xe2, 0xff, 0xc6, 0x37, 0xf9, 0x29,
+	0xf9, 0xac, 0xbe, 0xa9, 0x83, 0x9e, 0xcf, 0x37, 0x75, 0x1d, 0xe7, 0x9a, 0xcf, 0x7f, 0x95, 0x60,
+	0x9e, 0x7b, 0x49, 0x42, 0xe3, 0xb7, 0x41, 0x5e, 0x2b, 0x48, 0x0e, 0x51, 0x7c, 0x15, 0x15, 0x06,
+	0x8d, 0xda, 0x8e, 0xbc, 0x70, 0x10, 0x12, 0xe8, 0x2d, 0x28, 0x46, 0xfc, 0xc2, 0x47, 0x9e, 0x18,
+	0x2b, 0xe3, 0x77, 0xaa, 0xfa, 0xd5, 0xd2, 0x76, 0xc6, 0x91, 0xf2, 0xe8, 0x3a, 0x14, 0x3b, 0x9c,
+	0x45, 0x59, 0xe9, 0xaa, 0xe3, 0xc8, 0xf4, 0x15, 0x08, 0x43, 0x0b, 0x0c, 0xba, 0x06, 0x05, 0x5e,
+	0x18, 0xe5, 0xb7, 0x1d, 0xed, 0xb1, 0xe9, 0x03, 0xe2, 0x76, 0xc6, 0x11, 0xe2, 0x68, 0x1d, 0xf2,
+	0x41, 0xe8, 0x77, 0xe5, 0x35, 0xc1, 0xe5, 0xf1, 0x67, 0xaa, 0xe7, 0xea, 0xed, 0x8c, 0xc3, 0x65,
+	0xd1, 0x1b, 0x50, 0x8a, 0xf8, 0x81, 0x3c, 0xe2, 0x17, 0xac, 0xec, 0x34, 0x36, 0x06, 0x53, 0x20,
+	0xb1, 0x28, 0x7a, 0x03, 0x8a, 0x87, 0xfc, 0xb8, 0x25, 0x6f, 0xed, 0x57, 0x54, 0x90, 0x7e, 0x10,
+	0x63, 0xef, 0x25, 0x64, 0xd1, 0x1d, 0x98, 0xa3, 0x7e, 0x70, 0x10, 0x9f, 0x6a, 0xe4, 0xe5, 0xec,
+	0xaa, 0xb6, 0x77, 0x98, 0x70, 0xea, 0xd9, 0xce, 0x38, 0x1a, 0x0e, 0x3d, 0x80, 0xc5, 0x87, 0xda,
+	0xf6, 0x99, 0xc4, 0xd7, 0xf0, 0x1a, 0xcf, 0x93, 0x37, 0xf6, 0xdb, 0x19, 0x27, 0x85, 0x46, 0x5b,
+	0xb0, 0x10, 0x69, 0x15, 0x4e, 0x7e, 0x72, 0xd2, 0xde, 0x4b, 0xaf, 0x81, 0xdb, 0x19, 0x67, 0x0c,
+	0x83, 0xee, 0xc3, 0x42, 0x53, 0xcb, 0xef, 0xf2, 0x83, 0x92, 0xb6, 0xaa, 0xc9, 0x15, 0x80, 0x69,
+	0xd3, 0xb1, 0xe8, 0x3d, 0x58, 0x0c, 0xc6, 0x72, 0x9b, 0xfc, 0xa2, 0xf4, 0x75, 0xfd, 0x2d, 0x27,
+	0x24, 0x41, 0xf6, 0x92, 0xe3, 0x60, 0x75, 0x79, 0x22, 0xc4, 0xcd, 0xf9, 0xd3, 0x97, 0xa7, 0x27,
+	0x01, 0x75, 0x79, 0x62, 0x06, 0x7d, 0x00, 0x2f, 0x35, 0xd2, 0x3b, 0x67, 0x12, 0x99, 0x0b, 0x5c,
+	0xe9, 0x15, 0x55, 0xe9, 0x17, 0xec, 0xf1, 0xb7, 0x33, 0xce, 0x64, 0x3d, 0xe8, 0x2a, 0x14, 0x28,
+	0xdb, 0x08, 0x99, 0x17, 0xb8, 0xc2, 0x57, 0xd2, 0x5b, 0x4c, 0x25, 0x00, 0xb8, 0xa4, 0x0d, 0xa3,
+	0x14, 0x59, 0xfd, 0xa8, 0x08, 0x73, 0x32, 0xf4, 0xc5, 0xcd, 0xf6, 0xf7, 0x92, 0x68, 0x16, 0x91,
+	0xff, 0xea, 0x69, 0xd1, 0xcc, 0xc5, 0x95, 0x60, 0x7e, 0x3d, 0x09, 0x66, 0x91, 0x06, 0x96, 0x47,
+	0x69, 0x97, 0x73, 0xa1, 0x20, 0x64, 0x00, 0x6f, 0xc4, 0x01, 0x2c, 0xa2, 0xff, 0xd2, 0xe4, 0xfb,
+	0xa1, 0x18, 0x25, 0xa3, 0x77, 0x13, 0x4a, 0xae, 0xf8, 0xdc, 0x37, 0x29, 0xee, 0xd3, 0x5f, 0x03,
+	0x59, 0x3c, 0x4a, 0x00, 0xda, 0x18, 0x45, 0x71, 0x41, 0x7e, 0xde, 0x4a, 0x45, 0x71, 0x02, 0x8a,
+	0x83, 0xf8, 0x6a, 0x12, 0xc4, 0xc5, 0xf1, 0x4f, 0x62, 0x71, 0x08, 0x27, 0x2f, 0x26, 0x23, 0xf8,
+	0x36, 0xcc, 0xc7, 0x3e, 0xcf, 0xa7, 0x64, 0x08, 0xbf, 0x7a, 0xda, 0x56, 0x33, 0xc6, 0xeb, 0x28,
+	0x74, 0x37, 0x15, 0x28, 0xe5, 0xf1, 0xed, 0xc1, 0x78, 0x98, 0xc4, 0x9a, 0xc6, 0xa3, 0xe4, 0x1e,
+	0x5c, 0x18, 0x39, 0xba, 0x58, 0x13, 0xa4, 0x4f, 0xeb, 0x5a, 0x88, 0xc4, 0xaa, 0xc6, 0x81, 0xea,
+	0xb2, 0x64, 0x80, 0xcc, 0x9e, 0xb6, 0xac, 0x38, 0x3c, 0x52, 0xcb, 0x92, 0xd1, 0xb1, 0x0d, 0x33,
+	0x5d, 0x42, 0x71, 0x13, 0x53, 0x6c, 0x96, 0x78, 0xa9, 0x7c, 0x2d, 0x15, 0xb4, 0x12, 0x5d, 0x7b,
+	0x57, 0x0a, 0xde, 0xf6, 0x68, 0x78, 0x2c, 0x0f, 0x4d, 0x09, 0x7a, 0xe5, 0xfb, 0x30, 0xaf, 0x09,
+	0xa0, 0x45, 0xc8, 0x1d, 0x90, 0xf8, 0x13, 0x30, 0x6b, 0xa2, 0x25, 0x28, 0xf0, 0x83, 0x01, 0xf7,
+	0xcf, 0xb2, 0x23, 0x3a, 0x9b, 0xd9, 0xb7, 0x0c, 0xbb, 0x0c, 0xa5, 0x50, 0x3c, 0xc5, 0x6e, 0x3d,
+	0x7e, 0x52, 0xc9, 0x7c, 0xf6, 0xa4, 0x92, 0xf9, 0xfc, 0x49, 0xc5, 0xf8, 0x70, 0x58, 0x31, 0x7e,
+	0x37, 0xac, 0x18, 0x9f, 0x0c, 0x2b, 0xc6, 0xe3, 0x61, 0xc5, 0xf8, 0xdb, 0xb0, 0x62, 0xfc, 0x63,
+	0x58, 0xc9, 0x7c, 0x3e, 0xac, 0x18, 0x1f, 0x3f, 0xad, 0x64, 0x1e, 0x3f, 0xad, 0x64, 0x3e, 0x7b,
+	0x5a, 0xc9, 0xfc, 0xf4, 0xea, 0x99, 0xab, 0xf6, 0x5e, 0x91, 0x33, 0xb5, 0xf1, 0x9f, 0x00, 0x00,
+	0x00, 0xff, 0xff, 0x37, 0x72, 0xfa, 0xb1, 0x0b, 0x23, 0x00, 0x00,
 }
 
 func (this *LokiRequest) Equal(that interface{}) bool {
@@ -2177,6 +2373,135 @@ func (this *QuantileSketchResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *CountDistinctSketchResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CountDistinctSketchResponse)
+	if !ok {
+		that2, ok := that.(CountDistinctSketchResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Response == nil {
+		if this.Response != nil {
+			return false
+		}
+	} else if !this.Response.Equal(*that1.Response) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *TableResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TableResponse)
+	if !ok {
+		that2, ok := that.(TableResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Columns) != len(that1.Columns) {
+		return false
+	}
+	for i := range this.Columns {
+		if this.Columns[i] != that1.Columns[i] {
+			return false
+		}
+	}
+	if len(this.Rows) != len(that1.Rows) {
+		return false
+	}
+	for i := range this.Rows {
+		if !this.Rows[i].Equal(&that1.Rows[i]) {
+			return false
+		}
+	}
+	if !this.Statistics.Equal(&that1.Statistics) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *TableRow) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TableRow)
+	if !ok {
+		that2, ok := that.(TableRow)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if this.Values[i] != that1.Values[i] {
+			return false
+		}
+	}
+	return true
+}
 func (this *ShardsResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2642,14 +2967,14 @@ func (this *QueryResponse_DetectedLabels) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *QueryRequest) Equal(that interface{}) bool {
+func (this *QueryResponse_CountDistinctSketches) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*QueryRequest)
+	that1, ok := that.(*QueryResponse_CountDistinctSketches)
 	if !ok {
-		that2, ok := that.(QueryRequest)
+		that2, ok := that.(QueryResponse_CountDistinctSketches)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2661,33 +2986,19 @@ func (this *QueryRequest) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if that1.Request == nil {
-		if this.Request != nil {
-			return false
-		}
-	} else if this.Request == nil {
-		return false
-	} else if !this.Request.Equal(that1.Request) {
-		return false
-	}
-	if len(this.Metadata) != len(that1.Metadata) {
+	if !this.CountDistinctSketches.Equal(that1.CountDistinctSketches) {
 		return false
 	}
-	for i := range this.Metadata {
-		if this.Metadata[i] != that1.Metadata[i] {
-			return false
-		}
-	}
 	return true
 }
-func (this *QueryRequest_Series) Equal(that interface{}) bool {
+func (this *QueryResponse_Table) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*QueryRequest_Series)
+	that1, ok := that.(*QueryResponse_Table)
 	if !ok {
-		that2, ok := that.(QueryRequest_Series)
+		that2, ok := that.(QueryResponse_Table)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2699,12 +3010,74 @@ func (this *QueryRequest_Series) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if !this.Series.Equal(that1.Series) {
+	if !this.Table.Equal(that1.Table) {
 		return false
 	}
 	return true
 }
-func (this *QueryRequest_Labels) Equal(that interface{}) bool {
+func (this *QueryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryRequest)
+	if !ok {
+		that2, ok := that.(QueryRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Request == nil {
+		if this.Request != nil {
+			return false
+		}
+	} else if this.Request == nil {
+		return false
+	} else if !this.Request.Equal(that1.Request) {
+		return false
+	}
+	if len(this.Metadata) != len(that1.Metadata) {
+		return false
+	}
+	for i := range this.Metadata {
+		if this.Metadata[i] != that1.Metadata[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *QueryRequest_Series) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryRequest_Series)
+	if !ok {
+		that2, ok := that.(QueryRequest_Series)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Series.Equal(that1.Series) {
+		return false
+	}
+	return true
+}
+func (this *QueryRequest_Labels) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
@@ -3110,6 +3483,48 @@ func (this *QuantileSketchResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *CountDistinctSketchResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&queryrange.CountDistinctSketchResponse{")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TableResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&queryrange.TableResponse{")
+	s = append(s, "Columns: "+fmt.Sprintf("%#v", this.Columns)+",\n")
+	if this.Rows != nil {
+		vs := make([]TableRow, len(this.Rows))
+		for i := range vs {
+			vs[i] = this.Rows[i]
+		}
+		s = append(s, "Rows: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "Statistics: "+strings.Replace(this.Statistics.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TableRow) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&queryrange.TableRow{")
+	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *ShardsResponse) GoString() string {
 	if this == nil {
 		return "nil"
@@ -3158,7 +3573,7 @@ func (this *QueryResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 17)
+	s := make([]string, 0, 19)
 	s = append(s, "&queryrange.QueryResponse{")
 	if this.Status != nil {
 		s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
@@ -3265,6 +3680,22 @@ func (this *QueryResponse_DetectedLabels) GoString() string {
 		`DetectedLabels:` + fmt.Sprintf("%#v", this.DetectedLabels) + `}`}, ", ")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryResponse_CountDistinctSketches{` +
+		`CountDistinctSketches:` + fmt.Sprintf("%#v", this.CountDistinctSketches) + `}`}, ", ")
+	return s
+}
+func (this *QueryResponse_Table) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryResponse_Table{` +
+		`Table:` + fmt.Sprintf("%#v", this.Table) + `}`}, ", ")
+	return s
+}
 func (this *QueryRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -4232,6 +4663,175 @@ func (m *QuantileSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
+func (m *CountDistinctSketchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CountDistinctSketchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountDistinctSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Response != nil {
+		{
+			size := m.Response.Size()
+			i -= size
+			if _, err := m.Response.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TableResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TableResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TableResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	{
+		size, err := m.Statistics.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQueryrange(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	if len(m.Rows) > 0 {
+		for iNdEx := len(m.Rows) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rows[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Columns) > 0 {
+		for iNdEx := len(m.Columns) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Columns[iNdEx])
+			copy(dAtA[i:], m.Columns[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Columns[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TableRow) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TableRow) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TableRow) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Values[iNdEx])
+			copy(dAtA[i:], m.Values[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Values[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ShardsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4712,14 +5312,56 @@ func (m *QueryResponse_DetectedLabels) MarshalToSizedBuffer(dAtA []byte) (int, e
 	}
 	return len(dAtA) - i, nil
 }
-func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
+func (m *QueryResponse_CountDistinctSketches) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResponse_CountDistinctSketches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CountDistinctSketches != nil {
+		{
+			size, err := m.CountDistinctSketches.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x72
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryResponse_Table) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResponse_Table) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Table != nil {
+		{
+			size, err := m.Table.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x7a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
 }
 
 func (m *QueryRequest) MarshalTo(dAtA []byte) (int, error) {
@@ -5334,6 +5976,81 @@ func (m *QuantileSketchResponse) Size() (n int) {
 	return n
 }
 
+func (m *CountDistinctSketchResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TableResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Columns) > 0 {
+		for _, s := range m.Columns {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	if len(m.Rows) > 0 {
+		for _, e := range m.Rows {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	l = m.Statistics.Size()
+	n += 1 + l + sovQueryrange(uint64(l))
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TableRow) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *ShardsResponse) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5570,6 +6287,30 @@ func (m *QueryResponse_DetectedLabels) Size() (n int) {
 	}
 	return n
 }
+func (m *QueryResponse_CountDistinctSketches) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CountDistinctSketches != nil {
+		l = m.CountDistinctSketches.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
+func (m *QueryResponse_Table) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Table != nil {
+		l = m.Table.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
 func (m *QueryRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5900,6 +6641,47 @@ func (this *QuantileSketchResponse) String() string {
 	}, "")
 	return s
 }
+func (this *CountDistinctSketchResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CountDistinctSketchResponse{`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TableResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRows := "[]TableRow{"
+	for _, f := range this.Rows {
+		repeatedStringForRows += strings.Replace(strings.Replace(f.String(), "TableRow", "TableRow", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRows += "}"
+	s := strings.Join([]string{`&TableResponse{`,
+		`Columns:` + fmt.Sprintf("%v", this.Columns) + `,`,
+		`Rows:` + repeatedStringForRows + `,`,
+		`Statistics:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Statistics), "Result", "stats.Result", 1), `&`, ``, 1) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TableRow) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TableRow{`,
+		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *ShardsResponse) String() string {
 	if this == nil {
 		return "nil"
@@ -6075,6 +6857,26 @@ func (this *QueryResponse_DetectedLabels) String() string {
 	}, "")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse_CountDistinctSketches{`,
+		`CountDistinctSketches:` + strings.Replace(fmt.Sprintf("%v", this.CountDistinctSketches), "CountDistinctSketchResponse", "CountDistinctSketchResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryResponse_Table) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse_Table{`,
+		`Table:` + strings.Replace(fmt.Sprintf("%v", this.Table), "TableResponse", "TableResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QueryRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -8770,7 +9572,7 @@ func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
+func (m *CountDistinctSketchResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8793,10 +9595,10 @@ func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ShardsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: CountDistinctSketchResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ShardsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CountDistinctSketchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -8829,7 +9631,7 @@ func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			if m.Response == nil {
-				m.Response = &github_com_grafana_loki_v3_pkg_logproto.ShardsResponse{}
+				m.Response = &github_com_grafana_loki_v3_pkg_logproto.CountDistinctSketchMatrix{}
 			}
 			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
@@ -8869,6 +9671,38 @@ func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
@@ -8893,7 +9727,7 @@ func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
+func (m *TableResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8916,17 +9750,17 @@ func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DetectedFieldsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TableResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DetectedFieldsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TableResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowQueryrange
@@ -8936,31 +9770,27 @@ func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthQueryrange
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Response == nil {
-				m.Response = &github_com_grafana_loki_v3_pkg_logproto.DetectedFieldsResponse{}
-			}
-			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Columns = append(m.Columns, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Rows", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8987,29 +9817,459 @@ func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Headers = append(m.Headers, github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
-			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Rows = append(m.Rows, TableRow{})
+			if err := m.Rows[len(m.Rows)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipQueryrange(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Statistics", wireType)
 			}
-			if skippy < 0 {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthQueryrange
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
 				return ErrInvalidLengthQueryrange
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
+			if err := m.Statistics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TableRow) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TableRow: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TableRow: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShardsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShardsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Response == nil {
+				m.Response = &github_com_grafana_loki_v3_pkg_logproto.ShardsResponse{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DetectedFieldsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DetectedFieldsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Response == nil {
+				m.Response = &github_com_grafana_loki_v3_pkg_logproto.DetectedFieldsResponse{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
 
 	if iNdEx > l {
 		return io.ErrUnexpectedEOF
@@ -9747,6 +11007,76 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Response = &QueryResponse_DetectedLabels{v}
 			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountDistinctSketches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CountDistinctSketchResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Response = &QueryResponse_CountDistinctSketches{v}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Table", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &TableResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Response = &QueryResponse_Table{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
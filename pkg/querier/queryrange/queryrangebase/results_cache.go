@@ -16,6 +16,7 @@ import (
 	"github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache/resultscache"
 	"github.com/grafana/loki/v3/pkg/util/constants"
@@ -259,6 +260,9 @@ func (s resultsCache) isAtModifierCachable(r Request, maxCacheTime int64) bool {
 	if !strings.Contains(query, "@") {
 		return true
 	}
+	if logqlExpr, err := syntax.ParseExpr(query); err == nil {
+		return isLogQLAtModifierCachable(logqlExpr, r, maxCacheTime)
+	}
 	expr, err := parser.ParseExpr(query)
 	if err != nil {
 		// We are being pessimistic in such cases.
@@ -296,6 +300,24 @@ func (s resultsCache) isAtModifierCachable(r Request, maxCacheTime int64) bool {
 	return atModCachable
 }
 
+// isLogQLAtModifierCachable is the LogQL counterpart of isAtModifierCachable.
+func isLogQLAtModifierCachable(expr syntax.Expr, r Request, maxCacheTime int64) bool {
+	end := r.GetEnd().UnixMilli()
+	groups, err := syntax.MatcherGroups(expr)
+	if err != nil {
+		return false
+	}
+	for _, grp := range groups {
+		if grp.At == nil {
+			continue
+		}
+		if ts := grp.At.Time(r.GetStart(), r.GetEnd()).UnixMilli(); ts > end || ts > maxCacheTime {
+			return false
+		}
+	}
+	return true
+}
+
 func getHeaderValuesWithName(r Response, headerName string) (headerValues []string) {
 	for _, hv := range r.GetHeaders() {
 		if hv.GetName() != headerName {
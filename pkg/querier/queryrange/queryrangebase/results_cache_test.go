@@ -314,6 +314,37 @@ func TestShouldCache(t *testing.T) {
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
+		// @ modifier on LogQL ranges and subqueries.
+		{
+			name:     "@ modifier on log range, before end, before maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"} | json [5m] @ 123)`, End: time.UnixMilli(125000)},
+			input:    Response(&PrometheusResponse{}),
+			expected: true,
+		},
+		{
+			name:     "@ modifier on log range, after end, before maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"} | json [5m] @ 127)`, End: time.UnixMilli(125000)},
+			input:    Response(&PrometheusResponse{}),
+			expected: false,
+		},
+		{
+			name:     "@ modifier on log range with end() after maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"} | json [5m] @ end())`, Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
+			input:    Response(&PrometheusResponse{}),
+			expected: false,
+		},
+		{
+			name:     "@ modifier on LogQL subquery, before end, after maxCacheTime",
+			request:  &PrometheusRequest{Query: `max_over_time(rate({app="foo"} | json [5m])[1h:] @ 151)`, End: time.UnixMilli(200000)},
+			input:    Response(&PrometheusResponse{}),
+			expected: false,
+		},
+		{
+			name:     "@ in a LogQL line filter",
+			request:  &PrometheusRequest{Query: `rate({app="foo"} |= "user@example.com" [5m])`, End: time.UnixMilli(200000)},
+			input:    Response(&PrometheusResponse{}),
+			expected: true,
+		},
 		// @ modifier on matrix selectors.
 		{
 			name:     "@ modifier on matrix selector, before end, before maxCacheTime",
@@ -161,13 +161,13 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 		return nil, err
 	}
 
-	maxRVDuration, maxOffset, err := maxRangeVectorAndOffsetDuration(params.GetExpression())
+	from, through, err := dataBounds(params.GetExpression(), r.GetStart(), r.GetEnd())
 	if err != nil {
 		level.Warn(spLogger).Log("err", err.Error(), "msg", "failed to get range-vector and offset duration so skipped AST mapper for request")
 		return ast.next.Do(ctx, r)
 	}
 
-	conf, err := ast.confs.GetConf(int64(model.Time(r.GetStart().UnixMilli()).Add(-maxRVDuration).Add(-maxOffset)), int64(model.Time(r.GetEnd().UnixMilli()).Add(-maxOffset)))
+	conf, err := ast.confs.GetConf(from.UnixMilli(), through.UnixMilli())
 	// cannot shard with this timerange
 	if err != nil {
 		level.Warn(spLogger).Log("err", err.Error(), "msg", "skipped AST mapper for request")
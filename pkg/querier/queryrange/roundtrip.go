@@ -22,6 +22,7 @@ import (
 	logqllog "github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	base "github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
@@ -336,6 +337,36 @@ type roundTripper struct {
 	limits Limits
 }
 
+// metricAggregation runs a range query reading a pre-aggregated metric between the first and the last
+// steps given, and the query itself for the steps before and after these.
+func (r roundTripper) metricAggregation(ctx context.Context, req *LokiRequest, rewritten syntax.SampleExpr, first, last time.Time) (base.Response, error) {
+	step := time.Duration(req.Step) * time.Millisecond
+
+	rewrittenReq := req.WithStartEnd(first, last).(*LokiRequest)
+	rewrittenReq.Query = rewritten.String()
+	rewrittenReq.Plan = &plan.QueryPlan{AST: rewritten}
+	reqs := []base.Request{rewrittenReq}
+	if first.After(req.StartTs) {
+		reqs = append(reqs, req.WithStartEnd(req.StartTs, first.Add(-step)))
+	}
+	if !last.Add(step).After(req.EndTs) {
+		reqs = append(reqs, req.WithStartEnd(last.Add(step), req.EndTs))
+	}
+
+	resps := make([]base.Response, 0, len(reqs))
+	for _, req := range reqs {
+		resp, err := r.metric.Do(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resps = append(resps, resp)
+	}
+	if len(resps) == 1 {
+		return resps[0], nil
+	}
+	return DefaultCodec.MergeResponse(resps...)
+}
+
 // newRoundTripper creates a new queryrange roundtripper
 func newRoundTripper(logger log.Logger, next, limited, log, metric, series, labels, instantMetric, indexStats, seriesVolume, detectedFields, detectedLabels base.Handler, limits Limits) roundTripper {
 	return roundTripper{
@@ -380,6 +411,17 @@ func (r roundTripper) Do(ctx context.Context, req base.Request) (base.Response,
 
 		switch e := op.Plan.AST.(type) {
 		case syntax.SampleExpr:
+			if syntax.HasAtModifier(e) {
+				resolved, err := resolveAtModifiers(e, op.StartTs, op.EndTs)
+				if err != nil {
+					return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+				}
+				clone := *op
+				clone.Query = resolved.String()
+				clone.Plan = &plan.QueryPlan{AST: resolved}
+				req, e = &clone, resolved
+			}
+
 			// The error will be handled later.
 			groups, err := e.MatcherGroups()
 			if err != nil {
@@ -391,6 +433,17 @@ func (r roundTripper) Do(ctx context.Context, req base.Request) (base.Response,
 					return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 				}
 			}
+
+			if tenantIDs, err := tenant.TenantIDs(ctx); err == nil && len(tenantIDs) == 1 {
+				if rules := r.limits.MetricAggregationRules(tenantIDs[0]); len(rules) > 0 {
+					if rewritten, rule, ok := metricAggregationQuery(e, time.Duration(op.Step)*time.Millisecond, rules); ok {
+						if first, last, ok := metricAggregationSteps(req.(*LokiRequest), e, rule, time.Now()); ok {
+							level.Info(logger).Log("msg", "query rewritten to read a pre-aggregated metric", "rule", rule.Name, "rewritten_query", rewritten.String(), "rewritten_start", first.Format(time.RFC3339Nano), "rewritten_end", last.Format(time.RFC3339Nano), "query_hash", queryHash)
+							return r.metricAggregation(ctx, req.(*LokiRequest), rewritten, first, last)
+						}
+					}
+				}
+			}
 			return r.metric.Do(ctx, req)
 		case syntax.LogSelectorExpr:
 			if err := validateMaxEntriesLimits(ctx, op.Limit, r.limits); err != nil {
@@ -401,6 +454,16 @@ func (r roundTripper) Do(ctx context.Context, req base.Request) (base.Response,
 				return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
 			}
 
+			// The rows of a stats stage aggregate every matching line of the query range,
+			// and the lines surrounding a line matched by an around stage can be in another
+			// split, so these queries can be neither split nor sharded.
+			if _, _, ok := syntax.SplitStatsStage(e); ok {
+				return r.next.Do(ctx, req)
+			}
+			if _, ok := syntax.AroundStage(e); ok {
+				return r.next.Do(ctx, req)
+			}
+
 			// Some queries we don't want to parallelize as aggressively, like limited queries and `datasample` queries
 			tags := httpreq.ExtractQueryTagsFromContext(ctx)
 			if !e.HasFilter() || strings.Contains(tags, "datasample") {
@@ -423,8 +486,18 @@ func (r roundTripper) Do(ctx context.Context, req base.Request) (base.Response,
 		queryHash := util.HashedQuery(op.Query)
 		level.Info(logger).Log("msg", "executing query", "type", "instant", "query", op.Query, "query_hash", queryHash)
 
-		switch op.Plan.AST.(type) {
+		switch e := op.Plan.AST.(type) {
 		case syntax.SampleExpr:
+			if syntax.HasAtModifier(e) {
+				resolved, err := resolveAtModifiers(e, op.TimeTs, op.TimeTs)
+				if err != nil {
+					return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+				}
+				clone := *op
+				clone.Query = resolved.String()
+				clone.Plan = &plan.QueryPlan{AST: resolved}
+				req = &clone
+			}
 			return r.instantMetric.Do(ctx, req)
 		default:
 			return r.next.Do(ctx, req)
@@ -474,6 +547,19 @@ func (r roundTripper) Do(ctx context.Context, req base.Request) (base.Response,
 	}
 }
 
+// resolveAtModifiers returns a copy of expr where the `@ start()` and `@ end()`
+// modifiers are replaced by the bounds of the query. This happens before the
+// query is split so that every sub-query, and its results cache key, is pinned
+// to the same absolute time.
+func resolveAtModifiers(expr syntax.SampleExpr, start, end time.Time) (syntax.SampleExpr, error) {
+	resolved, err := syntax.Clone[syntax.SampleExpr](expr)
+	if err != nil {
+		return nil, err
+	}
+	syntax.ResolveAtModifiers(resolved, start, end)
+	return resolved, nil
+}
+
 // transformRegexQuery backport the old regexp params into the v1 query format
 func transformRegexQuery(req *http.Request, expr syntax.LogSelectorExpr) (syntax.LogSelectorExpr, error) {
 	regexp := req.Form.Get("regexp")
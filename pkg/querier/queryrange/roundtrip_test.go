@@ -1010,6 +1010,132 @@ func TestPostQueries(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestUnsplittableLogQueries(t *testing.T) {
+	for _, query := range []string{
+		`{app="foo"} |= "foo" | stats count() by (level)`,
+		`{app="foo"} |= "foo" | around 2`,
+	} {
+		lreq := &LokiRequest{
+			Query: query,
+			Plan: &plan.QueryPlan{
+				AST: syntax.MustParseExpr(query),
+			},
+		}
+		ctx := user.InjectOrgID(context.Background(), "1")
+		handler := base.HandlerFunc(func(context.Context, base.Request) (base.Response, error) {
+			t.Error("unexpected split roundtripper called")
+			return nil, nil
+		})
+		var called bool
+		_, err := newRoundTripper(
+			util_log.Logger,
+			base.HandlerFunc(func(context.Context, base.Request) (base.Response, error) {
+				called = true
+				return nil, nil
+			}),
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			fakeLimits{},
+		).Do(ctx, lreq)
+		require.NoError(t, err)
+		require.True(t, called, query)
+	}
+}
+
+func TestMetricQueriesRewrittenToMetricAggregations(t *testing.T) {
+	matchers, err := syntax.ParseMatchers(`{app="foo"}`, true)
+	require.NoError(t, err)
+	now := time.Now().Truncate(time.Minute)
+	limits := fakeLimits{metricAggregationRules: []valid.MetricAggregationRule{
+		{Name: "foo", Selector: `{app="foo"}`, By: []string{"level"}, Interval: model.Duration(time.Minute), Matchers: matchers, StartTime: now.Add(-time.Hour)},
+	}}
+	rewritten := `sum by (level)(max by (level,__aggregated_metric_owner__)(sum_over_time({__aggregated_metric__="foo"} | logfmt count="count" | unwrap count[5m])))`
+
+	type query struct {
+		query      string
+		start, end time.Time
+	}
+	for _, tc := range []struct {
+		query      string
+		start, end time.Time
+		expected   []query
+	}{
+		{
+			query: `sum by (level) (count_over_time({app="foo"}[5m]))`,
+			start: now.Add(-30 * time.Minute), end: now.Add(-10 * time.Minute),
+			expected: []query{{rewritten, now.Add(-30 * time.Minute), now.Add(-10 * time.Minute)}},
+		},
+		{
+			query: `sum by (level) (count_over_time({app="foo"} |= "error" [5m]))`,
+			start: now.Add(-30 * time.Minute), end: now.Add(-10 * time.Minute),
+			expected: []query{{`sum by (level)(count_over_time({app="foo"} |= "error"[5m]))`, now.Add(-30 * time.Minute), now.Add(-10 * time.Minute)}},
+		},
+		// Only the steps whose range is covered by the pre-aggregated metric are rewritten.
+		{
+			query: `sum by (level) (count_over_time({app="foo"}[5m]))`,
+			start: now.Add(-2 * time.Hour), end: now,
+			expected: []query{
+				{rewritten, now.Add(-55 * time.Minute), now.Add(-2 * time.Minute)},
+				{`sum by (level)(count_over_time({app="foo"}[5m]))`, now.Add(-2 * time.Hour), now.Add(-56 * time.Minute)},
+				{`sum by (level)(count_over_time({app="foo"}[5m]))`, now.Add(-time.Minute), now},
+			},
+		},
+		{
+			query: `sum by (level) (count_over_time({app="foo"}[5m]))`,
+			start: now.Add(-3 * time.Hour), end: now.Add(-2 * time.Hour),
+			expected: []query{{`sum by (level)(count_over_time({app="foo"}[5m]))`, now.Add(-3 * time.Hour), now.Add(-2 * time.Hour)}},
+		},
+	} {
+		lreq := &LokiRequest{
+			Query:   tc.query,
+			Step:    time.Minute.Milliseconds(),
+			StartTs: tc.start,
+			EndTs:   tc.end,
+			Plan: &plan.QueryPlan{
+				AST: syntax.MustParseExpr(tc.query),
+			},
+		}
+		ctx := user.InjectOrgID(context.Background(), "1")
+		handler := base.HandlerFunc(func(context.Context, base.Request) (base.Response, error) {
+			t.Error("unexpected default roundtripper called")
+			return nil, nil
+		})
+		var queries []query
+		_, err = newRoundTripper(
+			util_log.Logger,
+			handler,
+			handler,
+			handler,
+			base.HandlerFunc(func(_ context.Context, r base.Request) (base.Response, error) {
+				req := r.(*LokiRequest)
+				queries = append(queries, query{req.Plan.AST.String(), req.StartTs, req.EndTs})
+				return &LokiPromResponse{Response: &base.PrometheusResponse{
+					Status: "success",
+					Data:   base.PrometheusData{ResultType: loghttp.ResultTypeMatrix},
+				}}, nil
+			}),
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			handler,
+			limits,
+		).Do(ctx, lreq)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, queries)
+	}
+}
+
 func TestTripperware_EntriesLimit(t *testing.T) {
 	tpw, stopper, err := NewMiddleware(testConfig, testEngineOpts, nil, util_log.Logger, fakeLimits{maxEntriesLimitPerQuery: 5000, maxQueryParallelism: 1}, config.SchemaConfig{Configs: testSchemas}, nil, false, nil, constants.Loki)
 	if stopper != nil {
@@ -1401,6 +1527,7 @@ type fakeLimits struct {
 	maxStatsCacheFreshness      time.Duration
 	maxMetadataCacheFreshness   time.Duration
 	volumeEnabled               bool
+	metricAggregationRules      []valid.MetricAggregationRule
 }
 
 func (f fakeLimits) QuerySplitDuration(key string) time.Duration {
@@ -1472,6 +1599,10 @@ func (f fakeLimits) MaxQuerySeries(context.Context, string) int {
 	return f.maxSeries
 }
 
+func (f fakeLimits) MaxStatsEntriesPerQuery(context.Context, string) int {
+	return 0
+}
+
 func (f fakeLimits) MaxCacheFreshness(context.Context, string) time.Duration {
 	return 1 * time.Minute
 }
@@ -1520,6 +1651,10 @@ func (f fakeLimits) VolumeEnabled(_ string) bool {
 	return f.volumeEnabled
 }
 
+func (f fakeLimits) MetricAggregationRules(_ string) []valid.MetricAggregationRule {
+	return f.metricAggregationRules
+}
+
 func (f fakeLimits) TSDBMaxBytesPerShard(_ string) int {
 	return valid.DefaultTSDBMaxBytesPerShard
 }
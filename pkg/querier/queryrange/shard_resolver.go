@@ -92,8 +92,9 @@ func getStatsForMatchers(
 	results := make([]*stats.Stats, len(matcherGroups))
 	if err := concurrency.ForEachJob(ctx, len(matcherGroups), parallelism, func(ctx context.Context, i int) error {
 		matchers := syntax.MatchersString(matcherGroups[i].Matchers)
+		from, through := pinnedBounds(matcherGroups[i], start, end)
 		diff := matcherGroups[i].Interval + matcherGroups[i].Offset
-		adjustedFrom := start.Add(-diff)
+		adjustedFrom := from.Add(-diff)
 		if matcherGroups[i].Interval == 0 {
 			// For limited instant queries, when start == end, the queries would return
 			// zero results. Prometheus has a concept of "look back amount of time for instant queries"
@@ -102,7 +103,7 @@ func getStatsForMatchers(
 			adjustedFrom = adjustedFrom.Add(-defaultLookback)
 		}
 
-		adjustedThrough := end.Add(-matcherGroups[i].Offset)
+		adjustedThrough := through.Add(-matcherGroups[i].Offset)
 
 		resp, err := statsHandler.Do(ctx, &logproto.IndexStatsRequest{
 			From:     adjustedFrom,
@@ -141,6 +142,16 @@ func getStatsForMatchers(
 	return results, nil
 }
 
+// pinnedBounds returns the bounds of the query for the matcher group, both are
+// the pinned time if the range of the group is pinned by an @ modifier.
+func pinnedBounds(grp syntax.MatcherRange, start, end model.Time) (model.Time, model.Time) {
+	if grp.At == nil {
+		return start, end
+	}
+	at := model.TimeFromUnixNano(grp.At.Time(start.Time(), end.Time()).UnixNano())
+	return at, at
+}
+
 func (r *dynamicShardResolver) GetStats(e syntax.Expr) (stats.Stats, error) {
 	sp, ctx := opentracing.StartSpanFromContext(r.ctx, "dynamicShardResolver.GetStats")
 	defer sp.Finish()
@@ -225,7 +236,7 @@ func (r *dynamicShardResolver) ShardingRanges(expr syntax.Expr, targetBytesPerSh
 ) {
 	log := spanlogger.FromContext(r.ctx)
 
-	adjustedFrom := r.from
+	adjustedFrom, adjustedThrough := r.from, r.through
 
 	// NB(owen-d): there should only ever be 1 matcher group passed
 	// to this call as we call it separately for different legs
@@ -236,6 +247,7 @@ func (r *dynamicShardResolver) ShardingRanges(expr syntax.Expr, targetBytesPerSh
 	}
 
 	for _, grp := range grps {
+		from, through := pinnedBounds(grp, r.from, r.through)
 		diff := grp.Interval + grp.Offset
 
 		// For instant queries, when start == end,
@@ -245,8 +257,12 @@ func (r *dynamicShardResolver) ShardingRanges(expr syntax.Expr, targetBytesPerSh
 		}
 
 		// use the oldest adjustedFrom
-		if r.from.Add(-diff).Before(adjustedFrom) {
-			adjustedFrom = r.from.Add(-diff)
+		if from.Add(-diff).Before(adjustedFrom) {
+			adjustedFrom = from.Add(-diff)
+		}
+		// and the latest adjustedThrough, which only moves for pinned ranges
+		if through.After(adjustedThrough) {
+			adjustedThrough = through
 		}
 	}
 
@@ -256,7 +272,7 @@ func (r *dynamicShardResolver) ShardingRanges(expr syntax.Expr, targetBytesPerSh
 	// use the retry handler here to retry transient errors
 	resp, err := r.retryNextHandler.Do(r.ctx, &logproto.ShardsRequest{
 		From:                adjustedFrom,
-		Through:             r.through,
+		Through:             adjustedThrough,
 		Query:               expr.String(),
 		TargetBytesPerShard: targetBytesPerShard,
 	})
@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/dskit/tenant"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/storage/config"
@@ -251,7 +252,34 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 	if err != nil {
 		return nil, err
 	}
-	return h.merger.MergeResponse(resps...)
+	resp, err := h.merger.MergeResponse(resps...)
+	if err != nil {
+		return nil, err
+	}
+	if req, ok := r.(*LokiRequest); ok {
+		return applyResultStages(req, resp)
+	}
+	return resp, nil
+}
+
+// applyResultStages applies the limit and dedup stages of a log query to the
+// response merged from its splits: each split only limits and deduplicates its
+// own entries.
+func applyResultStages(req *LokiRequest, resp queryrangebase.Response) (queryrangebase.Response, error) {
+	lokiResp, ok := resp.(*LokiResponse)
+	if !ok || req.Plan == nil {
+		return resp, nil
+	}
+	expr, ok := req.Plan.AST.(syntax.LogSelectorExpr)
+	if !ok || len(syntax.ResultStages(expr)) == 0 {
+		return resp, nil
+	}
+	streams, err := logql.ApplyResultStages(lokiResp.Data.Result, expr, req.Direction)
+	if err != nil {
+		return nil, err
+	}
+	lokiResp.Data.Result = streams
+	return lokiResp, nil
 }
 
 // maxRangeVectorAndOffsetDurationFromQueryString
@@ -263,6 +291,37 @@ func maxRangeVectorAndOffsetDurationFromQueryString(q string) (time.Duration, ti
 	return maxRangeVectorAndOffsetDuration(parsed)
 }
 
+// dataBounds returns the time range of the data read by a LogQL query between
+// start and end, including the ranges pinned by @ modifiers.
+func dataBounds(expr syntax.Expr, start, end time.Time) (time.Time, time.Time, error) {
+	maxRVDuration, maxOffset, err := maxRangeVectorAndOffsetDuration(expr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from, through := start.Add(-maxRVDuration).Add(-maxOffset), end.Add(-maxOffset)
+	if !syntax.HasAtModifier(expr) {
+		return from, through, nil
+	}
+
+	groups, err := syntax.MatcherGroups(expr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	for _, grp := range groups {
+		if grp.At == nil {
+			continue
+		}
+		at := grp.At.Time(start, end)
+		if pinnedFrom := at.Add(-grp.Interval).Add(-grp.Offset); pinnedFrom.Before(from) {
+			from = pinnedFrom
+		}
+		if pinnedThrough := at.Add(-grp.Offset); pinnedThrough.After(through) {
+			through = pinnedThrough
+		}
+	}
+	return from, through, nil
+}
+
 // maxRangeVectorAndOffsetDuration returns the maximum range vector and offset duration within a LogQL query.
 func maxRangeVectorAndOffsetDuration(expr syntax.Expr) (time.Duration, time.Duration, error) {
 	if _, ok := expr.(syntax.SampleExpr); !ok {
@@ -271,13 +330,24 @@ func maxRangeVectorAndOffsetDuration(expr syntax.Expr) (time.Duration, time.Dura
 
 	var maxRVDuration, maxOffset time.Duration
 	expr.Walk(func(e syntax.Expr) {
-		if r, ok := e.(*syntax.LogRange); ok {
+		switch r := e.(type) {
+		case *syntax.LogRange:
 			if r.Interval > maxRVDuration {
 				maxRVDuration = r.Interval
 			}
 			if r.Offset > maxOffset {
 				maxOffset = r.Offset
 			}
+		case *syntax.SubqueryExpr:
+			// the inner query of a subquery reaches back by the range and
+			// offset of the subquery on top of its own.
+			innerRV, innerOffset, _ := maxRangeVectorAndOffsetDuration(r.Left)
+			if innerRV+r.Range > maxRVDuration {
+				maxRVDuration = innerRV + r.Range
+			}
+			if innerOffset+r.Offset > maxOffset {
+				maxOffset = innerOffset + r.Offset
+			}
 		}
 	})
 	return maxRVDuration, maxOffset, nil
@@ -1548,6 +1548,70 @@ func Test_splitByInterval_Do(t *testing.T) {
 	}
 }
 
+func Test_splitByInterval_ResultStages(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "1")
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		start := r.(*LokiRequest).StartTs.UnixNano()
+		return &LokiResponse{
+			Status:    loghttp.QueryStatusSuccess,
+			Direction: r.(*LokiRequest).Direction,
+			Limit:     r.(*LokiRequest).Limit,
+			Version:   uint32(loghttp.VersionV1),
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result: []logproto.Stream{
+					{
+						Labels: `{foo="bar"}`,
+						Entries: []logproto.Entry{
+							{Timestamp: time.Unix(0, start), Line: "retry"},
+							{Timestamp: time.Unix(0, start+1), Line: "retry"},
+						},
+					},
+				},
+			},
+		}, nil
+	})
+
+	split := SplitByIntervalMiddleware(
+		testSchemas,
+		WithSplitByLimits(fakeLimits{maxQueryParallelism: 1}, time.Hour),
+		DefaultCodec,
+		newDefaultSplitter(fakeLimits{}, nil),
+		nilMetrics,
+	).Wrap(next)
+
+	for _, tc := range []struct {
+		query    string
+		expected []int64
+	}{
+		{`{foo="bar"} | limit 3`, []int64{0, 1, time.Hour.Nanoseconds()}},
+		{`{foo="bar"} | dedup`, []int64{0}},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			res, err := split.Do(ctx, &LokiRequest{
+				StartTs:   time.Unix(0, 0),
+				EndTs:     time.Unix(0, (4 * time.Hour).Nanoseconds()),
+				Query:     tc.query,
+				Limit:     1000,
+				Direction: logproto.FORWARD,
+				Path:      "/loki/api/v1/query_range",
+				Plan: &plan.QueryPlan{
+					AST: syntax.MustParseExpr(tc.query),
+				},
+			})
+			require.NoError(t, err)
+
+			result := res.(*LokiResponse).Data.Result
+			require.Len(t, result, 1)
+			var actual []int64
+			for _, e := range result[0].Entries {
+				actual = append(actual, e.Timestamp.UnixNano())
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func Test_series_splitByInterval_Do(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "1")
 	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
@@ -1948,3 +2012,23 @@ func assertSplits(t *testing.T, want, splits []queryrangebase.Request) {
 		}
 	}
 }
+
+func Test_maxRangeVectorAndOffsetDuration(t *testing.T) {
+	for _, tc := range []struct {
+		query          string
+		expectedRange  time.Duration
+		expectedOffset time.Duration
+	}{
+		{`{app="foo"}`, 0, 0},
+		{`rate({app="foo"}[5m] offset 1m)`, 5 * time.Minute, time.Minute},
+		{`sum(rate({app="foo"}[5m])) / sum(rate({app="bar"}[10m] offset 2m))`, 10 * time.Minute, 2 * time.Minute},
+		{`max_over_time(rate({app="foo"}[5m] offset 1m)[1h:1m] offset 1d)`, time.Hour + 5*time.Minute, 24*time.Hour + time.Minute},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			rng, offset, err := maxRangeVectorAndOffsetDurationFromQueryString(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRange, rng)
+			require.Equal(t, tc.expectedOffset, offset)
+		})
+	}
+}
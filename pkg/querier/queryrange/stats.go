@@ -146,6 +146,10 @@ func StatsCollectorMiddleware() queryrangebase.Middleware {
 					responseStats = &r.Statistics
 					totalEntries = int(logqlmodel.Streams(r.Data.Result).Lines())
 					queryType = queryTypeLog
+				case *TableResponse:
+					responseStats = &r.Statistics
+					totalEntries = len(r.Rows)
+					queryType = queryTypeLog
 				case *LokiPromResponse:
 					responseStats = &r.Statistics
 					if r.Response != nil {
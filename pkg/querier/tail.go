@@ -50,6 +50,11 @@ type Tailer struct {
 
 	tailDisconnectedIngesters func([]string) (map[string]logproto.Querier_TailClient, error)
 
+	// Number of entries not sent by the ingesters since the tail started, and the last counts of each ingester.
+	dropCounts         loghttp.TailDropCounts
+	ingesterDropCounts map[string]loghttp.TailDropCounts
+	dropCountsMtx      sync.Mutex
+
 	querierTailClients    map[string]logproto.Querier_TailClient // addr -> grpc clients for tailing logs from ingesters
 	querierTailClientsMtx sync.RWMutex
 
@@ -86,6 +91,7 @@ func (t *Tailer) loop() {
 	defer tailMaxDurationTicker.Stop()
 
 	droppedEntries := make([]loghttp.DroppedEntry, 0)
+	var sentDropCounts loghttp.TailDropCounts
 
 	for !t.stopped.Load() {
 		select {
@@ -135,6 +141,15 @@ func (t *Tailer) loop() {
 		// If no entry has been consumed we should ensure it's not caused by all ingesters
 		// connections dropped and then throttle for a while
 		if len(tailResponse.Streams) == 0 {
+			// The drop counts are still sent when they change, as the entries might all be sampled out or rate limited.
+			if dropCounts := t.getDropCounts(); dropCounts != sentDropCounts {
+				select {
+				case t.responseChan <- &loghttp.TailResponse{DropCounts: &dropCounts}:
+					sentDropCounts = dropCounts
+				default:
+				}
+			}
+
 			t.querierTailClientsMtx.RLock()
 			numClients := len(t.querierTailClients)
 			t.querierTailClientsMtx.RUnlock()
@@ -162,6 +177,11 @@ func (t *Tailer) loop() {
 		if len(droppedEntries) > 0 {
 			tailResponse.DroppedEntries = droppedEntries
 		}
+		// The drop counts are only sent when they change.
+		dropCounts := t.getDropCounts()
+		if dropCounts != sentDropCounts {
+			tailResponse.DropCounts = &dropCounts
+		}
 
 		select {
 		case t.responseChan <- tailResponse:
@@ -169,6 +189,7 @@ func (t *Tailer) loop() {
 			if len(droppedEntries) > 0 {
 				droppedEntries = make([]loghttp.DroppedEntry, 0)
 			}
+			sentDropCounts = dropCounts
 		default:
 			droppedEntries = dropEntries(droppedEntries, tailResponse.Streams)
 		}
@@ -231,10 +252,44 @@ func (t *Tailer) readTailClient(addr string, querierTailClient logproto.Querier_
 			}
 			break
 		}
-		t.pushTailResponseFromIngester(resp)
+		t.recordDropCounts(addr, resp)
+		// The responses only carrying the drop counts have no entries.
+		if resp.Stream != nil && len(resp.Stream.Entries) > 0 {
+			t.pushTailResponseFromIngester(resp)
+		}
 	}
 }
 
+// recordDropCounts adds the entries an ingester did not send since its previous response to the drop counts.
+// The ingesters only count the entries of the streams they own, one of the replicas of each stream, so the counts
+// of the ingesters add up. The counts of an ingester restart from 0 when the querier reconnects to it.
+func (t *Tailer) recordDropCounts(addr string, resp *logproto.TailResponse) {
+	counts := loghttp.TailDropCounts{
+		Sampled:     resp.SampledEntries,
+		RateLimited: resp.RateLimitedEntries,
+		Dropped:     resp.DroppedEntries,
+	}
+
+	t.dropCountsMtx.Lock()
+	defer t.dropCountsMtx.Unlock()
+
+	last := t.ingesterDropCounts[addr]
+	if counts.Sampled < last.Sampled || counts.RateLimited < last.RateLimited || counts.Dropped < last.Dropped {
+		last = loghttp.TailDropCounts{}
+	}
+	t.dropCounts.Sampled += counts.Sampled - last.Sampled
+	t.dropCounts.RateLimited += counts.RateLimited - last.RateLimited
+	t.dropCounts.Dropped += counts.Dropped - last.Dropped
+	t.ingesterDropCounts[addr] = counts
+}
+
+func (t *Tailer) getDropCounts() loghttp.TailDropCounts {
+	t.dropCountsMtx.Lock()
+	defer t.dropCountsMtx.Unlock()
+
+	return t.dropCounts
+}
+
 // pushes new streams from ingesters synchronously
 func (t *Tailer) pushTailResponseFromIngester(resp *logproto.TailResponse) {
 	t.streamMtx.Lock()
@@ -333,6 +388,7 @@ func newTailer(
 		responseChan:              make(chan *loghttp.TailResponse, maxBufferedTailResponses),
 		closeErrChan:              make(chan error),
 		seenStreams:               make(map[uint64]struct{}),
+		ingesterDropCounts:        make(map[string]loghttp.TailDropCounts),
 		tailDisconnectedIngesters: tailDisconnectedIngesters,
 		tailMaxDuration:           tailMaxDuration,
 		waitEntryThrottle:         waitEntryThrottle,
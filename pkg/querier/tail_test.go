@@ -152,6 +152,21 @@ func TestTailer(t *testing.T) {
 				assert.Equal(t, maxDroppedEntriesPerTailResponse, len(responses[0].DroppedEntries))
 			},
 		},
+		"send the drop counts of the ingesters without entries": {
+			historicEntries: mockStreamIterator(0, 0),
+			tailClient:      newTailClientMock().mockRecvWithTrigger(&logproto.TailResponse{Stream: &logproto.Stream{}, RateLimitedEntries: 3}),
+			tester: func(t *testing.T, tailer *Tailer, tailClient *tailClientMock) {
+				tailClient.triggerRecv()
+
+				select {
+				case response := <-tailer.getResponseChan():
+					assert.Empty(t, response.Streams)
+					assert.Equal(t, &loghttp.TailDropCounts{RateLimited: 3}, response.DropCounts)
+				case <-time.After(timeout):
+					t.Fatal("timeout expired while waiting for the drop counts")
+				}
+			},
+		},
 	}
 
 	for testName, test := range tests {
@@ -464,3 +479,16 @@ func flattenStreamsFromResponses(responses []*loghttp.TailResponse) []logproto.S
 
 	return result
 }
+
+func TestTailer_recordDropCounts(t *testing.T) {
+	tailer := Tailer{ingesterDropCounts: map[string]loghttp.TailDropCounts{}}
+
+	tailer.recordDropCounts("ingester-1", &logproto.TailResponse{SampledEntries: 10, DroppedEntries: 1})
+	tailer.recordDropCounts("ingester-2", &logproto.TailResponse{SampledEntries: 5, RateLimitedEntries: 2})
+	tailer.recordDropCounts("ingester-1", &logproto.TailResponse{SampledEntries: 15, DroppedEntries: 1})
+	require.Equal(t, loghttp.TailDropCounts{Sampled: 20, RateLimited: 2, Dropped: 1}, tailer.getDropCounts())
+
+	// The counts of an ingester restart when the querier reconnects to it.
+	tailer.recordDropCounts("ingester-2", &logproto.TailResponse{SampledEntries: 3})
+	require.Equal(t, loghttp.TailDropCounts{Sampled: 23, RateLimited: 2, Dropped: 1}, tailer.getDropCounts())
+}
@@ -25,8 +25,10 @@ import (
 	"github.com/grafana/loki/v3/pkg/iter"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/querier/astmapper"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/storage/chunk"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
@@ -496,7 +498,21 @@ func (s *LokiStore) SelectLogs(ctx context.Context, req logql.SelectLogParams) (
 		return nil, err
 	}
 
-	lazyChunks, err := s.lazyChunks(ctx, from, through, chunk.NewPredicate(matchers, req.Plan), req.GetStoreChunks())
+	expr, err := req.LogSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	// The lines surrounding the matching lines of an around stage can be in
+	// chunks without any matching line, so chunks must not be filtered by the
+	// line filters of the query.
+	around, hasAround := syntax.AroundStage(expr)
+	predicatePlan := req.Plan
+	if hasAround {
+		predicatePlan = &plan.QueryPlan{AST: expr.(*syntax.PipelineExpr).Left}
+	}
+
+	lazyChunks, err := s.lazyChunks(ctx, from, through, chunk.NewPredicate(matchers, predicatePlan), req.GetStoreChunks())
 	if err != nil {
 		return nil, err
 	}
@@ -505,17 +521,41 @@ func (s *LokiStore) SelectLogs(ctx context.Context, req logql.SelectLogParams) (
 		return iter.NoopEntryIterator, nil
 	}
 
-	expr, err := req.LogSelector()
+	pipeline, err := expr.Pipeline()
 	if err != nil {
 		return nil, err
 	}
 
-	pipeline, err := expr.Pipeline()
+	pipeline, err = s.setupPipeline(ctx, req, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunkFilterer chunk.Filterer
+	if s.chunkFilterer != nil {
+		chunkFilterer = s.chunkFilterer.ForRequest(ctx)
+	}
+
+	if !hasAround {
+		return newLogBatchIterator(ctx, s.schemaCfg, s.chunkMetrics, lazyChunks, s.cfg.MaxChunkBatchSize, matchers, pipeline, req.Direction, req.Start, req.End, chunkFilterer)
+	}
+
+	// The surrounding lines are looked up in the unprocessed lines of the streams.
+	streamsPipeline, err := s.setupPipeline(ctx, req, lokilog.NewNoopPipeline())
+	if err != nil {
+		return nil, err
+	}
+	it, err := newLogBatchIterator(ctx, s.schemaCfg, s.chunkMetrics, lazyChunks, s.cfg.MaxChunkBatchSize, matchers, streamsPipeline, req.Direction, req.Start, req.End, chunkFilterer)
 	if err != nil {
 		return nil, err
 	}
+	return iter.NewContextIterator(it, pipeline, req.Direction, around.Before, around.After), nil
+}
 
-	pipeline, err = deletion.SetupPipeline(req, pipeline)
+// setupPipeline applies the deletes of the request and the pipeline wrapper to
+// the pipeline of a log query.
+func (s *LokiStore) setupPipeline(ctx context.Context, req logql.SelectLogParams, pipeline lokilog.Pipeline) (lokilog.Pipeline, error) {
+	pipeline, err := deletion.SetupPipeline(req, pipeline)
 	if err != nil {
 		return nil, err
 	}
@@ -528,13 +568,7 @@ func (s *LokiStore) SelectLogs(ctx context.Context, req logql.SelectLogParams) (
 
 		pipeline = s.pipelineWrapper.Wrap(ctx, pipeline, req.Plan.String(), userID)
 	}
-
-	var chunkFilterer chunk.Filterer
-	if s.chunkFilterer != nil {
-		chunkFilterer = s.chunkFilterer.ForRequest(ctx)
-	}
-
-	return newLogBatchIterator(ctx, s.schemaCfg, s.chunkMetrics, lazyChunks, s.cfg.MaxChunkBatchSize, matchers, pipeline, req.Direction, req.Start, req.End, chunkFilterer)
+	return pipeline, nil
 }
 
 func (s *LokiStore) SelectSamples(ctx context.Context, req logql.SelectSampleParams) (iter.SampleIterator, error) {
@@ -326,6 +326,29 @@ func Test_store_SelectLogs(t *testing.T) {
 				},
 			},
 		},
+		{
+			"around",
+			newQuery("{foo=\"bar\"} |= \"4\" | around 1", from, from.Add(6*time.Millisecond), nil, nil),
+			[]logproto.Stream{
+				{
+					Labels: "{foo=\"bar\"}",
+					Entries: []logproto.Entry{
+						{
+							Timestamp: from.Add(2 * time.Millisecond),
+							Line:      "3",
+						},
+						{
+							Timestamp: from.Add(3 * time.Millisecond),
+							Line:      "4",
+						},
+						{
+							Timestamp: from.Add(4 * time.Millisecond),
+							Line:      "5",
+						},
+					},
+				},
+			},
+		},
 		{
 			"filter matcher",
 			newQuery("{foo=\"bar\"}", from, from.Add(6*time.Millisecond), nil, nil),
@@ -239,3 +239,41 @@ func WriteDetectedLabelsResponseJSON(r *logproto.DetectedLabelsResponse, w io.Wr
 	s.WriteRaw("\n")
 	return s.Flush()
 }
+
+// WriteLookupResponseJSON marshals a logproto.LookupResponse to JSON and then
+// writes it to the provided io.Writer.
+func WriteLookupResponseJSON(r *logproto.LookupResponse, warnings []string, w io.Writer) error {
+	result := loghttp.LookupResponse{
+		Status:   "success",
+		Warnings: warnings,
+		Data: loghttp.LookupResponseData{
+			Refs:    make([]loghttp.LookupRef, 0, len(r.Refs)),
+			Streams: make(loghttp.Streams, 0, len(r.Streams)),
+		},
+	}
+	for _, ref := range r.Refs {
+		lbls, err := NewLabelSet(ref.Labels)
+		if err != nil {
+			return err
+		}
+		result.Data.Refs = append(result.Data.Refs, loghttp.LookupRef{
+			Stream:      lbls,
+			Fingerprint: fmt.Sprintf("%016x", ref.Fingerprint),
+			From:        ref.From,
+			Through:     ref.Through,
+		})
+	}
+	for _, stream := range r.Streams {
+		s, err := NewStream(stream)
+		if err != nil {
+			return err
+		}
+		result.Data.Streams = append(result.Data.Streams, s)
+	}
+
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	s.WriteVal(result)
+	s.WriteRaw("\n")
+	return s.Flush()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"strconv"
 	"testing"
 	"testing/quick"
 	"time"
@@ -441,6 +442,30 @@ var queryTests = []struct {
 			"warnings": ["this is a warning"]
 		  }`, emptyStats),
 	},
+	{
+		logqlmodel.Table{
+			Columns: []string{"status", "count()", "avg(duration)"},
+			Rows: [][]string{
+				{"200", "12", "0.25"},
+				{"500", "3", "NaN"},
+			},
+		},
+		fmt.Sprintf(`{
+			"data": {
+			  "resultType": "table",
+			  "result": {
+				"columns": ["status", "count()", "avg(duration)"],
+				"rows": [
+				  ["200", "12", "0.25"],
+				  ["500", "3", "NaN"]
+				]
+			  },
+			  "stats" : %s
+			},
+			"status": "success",
+			"warnings": ["this is a warning"]
+		  }`, emptyStats),
+	},
 }
 
 // covers responses from /loki/api/v1/labels and /loki/api/v1/label/{name}/values
@@ -700,6 +725,8 @@ func Test_QueryResponseResultType(t *testing.T) {
 			require.IsTypef(t, loghttp.Matrix{}, value, "Incorrect type %d", i)
 		case loghttp.ResultTypeVector:
 			require.IsTypef(t, loghttp.Vector{}, value, "Incorrect type %d", i)
+		case loghttp.ResultTypeTable:
+			require.IsTypef(t, loghttp.Table{}, value, "Incorrect type %d", i)
 		default:
 			require.Fail(t, "Unknown result type %s", value.Type())
 		}
@@ -921,6 +948,7 @@ func (w wrappedValue) Generate(rand *rand.Rand, _ int) reflect.Value {
 		loghttp.ResultTypeScalar,
 		loghttp.ResultTypeStream,
 		loghttp.ResultTypeVector,
+		loghttp.ResultTypeTable,
 	}
 	t := types[rand.Intn(len(types))]
 
@@ -960,7 +988,12 @@ func (w wrappedValue) Generate(rand *rand.Rand, _ int) reflect.Value {
 			vector = append(vector, sample)
 		}
 		return reflect.ValueOf(wrappedValue{vector})
-
+	case loghttp.ResultTypeTable:
+		table := logqlmodel.Table{Columns: []string{randLabel(rand).Name, "count()"}}
+		for i := 0; i <= rand.Intn(100); i++ {
+			table.Rows = append(table.Rows, []string{randLabel(rand).Value, strconv.Itoa(rand.Int())})
+		}
+		return reflect.ValueOf(wrappedValue{table})
 	}
 	return reflect.ValueOf(nil)
 }
@@ -64,6 +64,14 @@ func NewResultValue(v parser.Value) (loghttp.ResultValue, error) {
 		}
 
 		value = NewMatrix(m)
+	case loghttp.ResultTypeTable:
+		t, ok := v.(logqlmodel.Table)
+
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T for table", t)
+		}
+
+		value = NewTable(t)
 	default:
 		return nil, fmt.Errorf("v1 endpoints do not support type %s", v.Type())
 	}
@@ -71,6 +79,14 @@ func NewResultValue(v parser.Value) (loghttp.ResultValue, error) {
 	return value, nil
 }
 
+// NewTable constructs a Table from a logqlmodel.Table
+func NewTable(t logqlmodel.Table) loghttp.Table {
+	return loghttp.Table{
+		Columns: t.Columns,
+		Rows:    t.Rows,
+	}
+}
+
 // NewStreams constructs a Streams from a logql.Streams
 func NewStreams(s logqlmodel.Streams) (loghttp.Streams, error) {
 	var err error
@@ -223,6 +239,21 @@ func EncodeTailResult(data legacy.TailResponse, s *jsoniter.Stream, encodeFlags
 		}
 	}
 
+	if data.DropCounts != nil {
+		s.WriteMore()
+		s.WriteObjectField("drop_counts")
+		s.WriteObjectStart()
+		s.WriteObjectField("sampled")
+		s.WriteUint64(data.DropCounts.Sampled)
+		s.WriteMore()
+		s.WriteObjectField("rate_limited")
+		s.WriteUint64(data.DropCounts.RateLimited)
+		s.WriteMore()
+		s.WriteObjectField("dropped")
+		s.WriteUint64(data.DropCounts.Dropped)
+		s.WriteObjectEnd()
+	}
+
 	if len(encodeFlags) > 0 {
 		s.WriteMore()
 		s.WriteObjectField("encodingFlags")
@@ -343,6 +374,15 @@ func encodeResult(v parser.Value, s *jsoniter.Stream, encodeFlags httpreq.Encodi
 
 		encodeMatrix(m, s)
 
+	case loghttp.ResultTypeTable:
+		t, ok := v.(logqlmodel.Table)
+
+		if !ok {
+			return fmt.Errorf("unexpected type %T for table", t)
+		}
+
+		encodeTable(t, s)
+
 	default:
 		s.WriteNil()
 		return fmt.Errorf("v1 endpoints do not support type %s", v.Type())
@@ -516,6 +556,36 @@ func encodeMatrix(m promql.Matrix, s *jsoniter.Stream) {
 	}
 }
 
+func encodeTable(t logqlmodel.Table, s *jsoniter.Stream) {
+	s.WriteObjectStart()
+	defer s.WriteObjectEnd()
+
+	s.WriteObjectField("columns")
+	encodeStrings(t.Columns, s)
+
+	s.WriteMore()
+	s.WriteObjectField("rows")
+	s.WriteArrayStart()
+	for i, row := range t.Rows {
+		if i > 0 {
+			s.WriteMore()
+		}
+		encodeStrings(row, s)
+	}
+	s.WriteArrayEnd()
+}
+
+func encodeStrings(values []string, s *jsoniter.Stream) {
+	s.WriteArrayStart()
+	for i, v := range values {
+		if i > 0 {
+			s.WriteMore()
+		}
+		s.WriteString(v)
+	}
+	s.WriteArrayEnd()
+}
+
 func encodeSampleStream(stream promql.Series, s *jsoniter.Stream) {
 	s.WriteObjectStart()
 	defer s.WriteObjectEnd()
@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/loki/v3/pkg/ruler/util"
+)
+
+// StructuredMetadataAction is the ingestion relabel action moving the stream labels whose
+// name matches the regex to the structured metadata of every entry of the stream.
+const StructuredMetadataAction = "structured_metadata"
+
+// IngestionRelabelConfig is a relabel rule applied by the distributors to the labels of the
+// incoming streams. It supports the Prometheus relabel actions plus StructuredMetadataAction.
+type IngestionRelabelConfig struct {
+	util.RelabelConfig `yaml:",inline"`
+
+	// Relabel is the Prometheus relabel config, or nil for StructuredMetadataAction.
+	Relabel *relabel.Config `yaml:"-" json:"-"` // populated during validation.
+	// StructuredMetadataRegex matches the names of the labels moved to structured metadata.
+	StructuredMetadataRegex *relabel.Regexp `yaml:"-" json:"-"` // populated during validation.
+
+	hash uint64 // populated during validation.
+}
+
+// IngestionRelabelConfigsHash returns a hash identifying the content of validated relabel
+// configs, which changes when they are reloaded with a different content.
+func IngestionRelabelConfigsHash(cfgs []*IngestionRelabelConfig) uint64 {
+	if len(cfgs) == 0 {
+		return 0
+	}
+	h := xxhash.New()
+	var b [8]byte
+	for _, c := range cfgs {
+		binary.LittleEndian.PutUint64(b[:], c.hash)
+		_, _ = h.Write(b[:])
+	}
+	return h.Sum64()
+}
+
+func (c *IngestionRelabelConfig) validate() error {
+	out, err := yaml.Marshal(c.RelabelConfig)
+	if err != nil {
+		return err
+	}
+	c.hash = xxhash.Sum64(out)
+
+	if c.Action == StructuredMetadataAction {
+		if c.Regex == "" {
+			return fmt.Errorf("regex is required for %s action", StructuredMetadataAction)
+		}
+		re, err := relabel.NewRegexp(c.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", c.Regex, err)
+		}
+		c.StructuredMetadataRegex = &re
+		return nil
+	}
+
+	// Round trip through YAML to apply the Prometheus defaults and validation.
+	var rc relabel.Config
+	if err := yaml.Unmarshal(out, &rc); err != nil {
+		return err
+	}
+	c.Relabel = &rc
+	return nil
+}
@@ -49,6 +49,11 @@ const (
 	// is used to keep track of the current number of healthy distributor replicas.
 	GlobalIngestionRateStrategy = "global"
 
+	// HighCardinalityLabelsPolicyWarn only reports the high cardinality stream labels.
+	HighCardinalityLabelsPolicyWarn = "warn"
+	// HighCardinalityLabelsPolicyStructuredMetadata moves the high cardinality stream labels to structured metadata.
+	HighCardinalityLabelsPolicyStructuredMetadata = "structured_metadata"
+
 	bytesInMB = 1048576
 
 	defaultPerStreamRateLimit   = 3 << 20 // 3MB
@@ -84,6 +89,13 @@ type Limits struct {
 	DiscoverServiceName         []string         `yaml:"discover_service_name" json:"discover_service_name"`
 	DiscoverLogLevels           bool             `yaml:"discover_log_levels" json:"discover_log_levels"`
 
+	IngestionRelabelConfigs     []*IngestionRelabelConfig `yaml:"ingestion_relabel_configs,omitempty" json:"ingestion_relabel_configs,omitempty" doc:"description=List of relabel configurations applied by the distributors to the labels of the incoming streams, before they are validated and hashed. Besides the Prometheus relabel actions, the 'structured_metadata' action moves the labels whose name matches the regex to the structured metadata of every entry of the stream. Streams dropped by a rule are discarded."`
+	MaxLabelValueCardinality    int                       `yaml:"max_label_value_cardinality" json:"max_label_value_cardinality"`
+	HighCardinalityLabelsPolicy string                    `yaml:"high_cardinality_labels_policy" json:"high_cardinality_labels_policy"`
+
+	IngestionPriorityClass   string                    `yaml:"ingestion_priority_class" json:"ingestion_priority_class"`
+	IngestionPriorityStreams []IngestionPriorityStream `yaml:"ingestion_priority_streams,omitempty" json:"ingestion_priority_streams,omitempty" doc:"description=Priority classes of the streams matching a selector, overriding the ingestion priority class of the tenant.\nExample:\n ingestion_priority_streams:\n - selector: '{namespace=\"dev\"}'\n class: low\nThe class of the first matching rule is used. The classes are defined by the distributor priority_classes."`
+
 	// Ingester enforced limits.
 	UseOwnedStreamCount     bool             `yaml:"use_owned_stream_count" json:"use_owned_stream_count"`
 	MaxLocalStreamsPerUser  int              `yaml:"max_streams_per_user" json:"max_streams_per_user"`
@@ -92,9 +104,14 @@ type Limits struct {
 	PerStreamRateLimit      flagext.ByteSize `yaml:"per_stream_rate_limit" json:"per_stream_rate_limit"`
 	PerStreamRateLimitBurst flagext.ByteSize `yaml:"per_stream_rate_limit_burst" json:"per_stream_rate_limit_burst"`
 
+	MetricAggregationRules []MetricAggregationRule `yaml:"metric_aggregation_rules,omitempty" json:"metric_aggregation_rules,omitempty" doc:"description=Metrics pre-aggregated by the ingesters from the entries pushed.\nExample:\n metric_aggregation_rules:\n - name: levels\n selector: '{namespace=\"prod\"}'\n by: [service_name, level]\n interval: 1m\n start: 2024-05-01T10:00:00Z\nThe number and the size of the entries of the matching streams are written every interval to the streams {__aggregated_metric__=\"<name>\", <by labels>}, along with the __aggregated_metric_owner__ and __aggregated_metric_replica__ labels: each stream is aggregated by the first ingester of its replication set, or by the ingesters of its partition with Kafka ingestion. Range queries like sum by (level) (count_over_time({namespace=\"prod\"}[5m])) are then rewritten by the query frontend to read these streams, if the range and the step are multiples of the interval and the grouping labels are a subset of the 'by' labels. Only the steps whose range starts after the start of the rule, and ends before the last intervals not written yet, are rewritten, the other steps are computed from the entries."`
+
+	IndexedStructuredMetadataKeys []string `yaml:"indexed_structured_metadata_keys,omitempty" json:"indexed_structured_metadata_keys,omitempty" doc:"description=Structured metadata keys, like trace_id or request_id, indexed by the ingesters. The streams and the entries having a value of these keys are returned by the /loki/api/v1/lookup endpoint without scanning the other streams. The index only covers the entries held by the ingesters."`
+
 	// Querier enforced limits.
 	MaxChunksPerQuery          int              `yaml:"max_chunks_per_query" json:"max_chunks_per_query"`
 	MaxQuerySeries             int              `yaml:"max_query_series" json:"max_query_series"`
+	MaxStatsEntriesPerQuery    int              `yaml:"max_stats_entries_per_query" json:"max_stats_entries_per_query"`
 	MaxQueryLookback           model.Duration   `yaml:"max_query_lookback" json:"max_query_lookback"`
 	MaxQueryLength             model.Duration   `yaml:"max_query_length" json:"max_query_length"`
 	MaxQueryRange              model.Duration   `yaml:"max_query_range" json:"max_query_range"`
@@ -106,6 +123,7 @@ type Limits struct {
 	CardinalityLimit           int              `yaml:"cardinality_limit" json:"cardinality_limit"`
 	MaxStreamsMatchersPerQuery int              `yaml:"max_streams_matchers_per_query" json:"max_streams_matchers_per_query"`
 	MaxConcurrentTailRequests  int              `yaml:"max_concurrent_tail_requests" json:"max_concurrent_tail_requests"`
+	MaxTailEntriesPerSecond    int              `yaml:"max_tail_entries_per_second" json:"max_tail_entries_per_second"`
 	MaxEntriesLimitPerQuery    int              `yaml:"max_entries_limit_per_query" json:"max_entries_limit_per_query"`
 	MaxCacheFreshness          model.Duration   `yaml:"max_cache_freshness_per_query" json:"max_cache_freshness_per_query"`
 	MaxMetadataCacheFreshness  model.Duration   `yaml:"max_metadata_cache_freshness" json:"max_metadata_cache_freshness"`
@@ -231,6 +249,13 @@ type StreamRetention struct {
 	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
 }
 
+// IngestionPriorityStream assigns a priority class to the streams matching a selector.
+type IngestionPriorityStream struct {
+	Selector string            `yaml:"selector" json:"selector" doc:"description:Stream selector expression."`
+	Class    string            `yaml:"class" json:"class" doc:"description:Priority class of the matching streams."`
+	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
+}
+
 // LimitError are errors that do not comply with the limits specified.
 type LimitError string
 
@@ -265,6 +290,9 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 		"job",
 	}
 	f.Var((*dskit_flagext.StringSlice)(&l.DiscoverServiceName), "validation.discover-service-name", "If no service_name label exists, Loki maps a single label from the configured list to service_name. If none of the configured labels exist in the stream, label is set to unknown_service. Empty list disables setting the label.")
+	f.StringVar(&l.IngestionPriorityClass, "distributor.ingestion-priority-class", "", "Priority class of the streams of the tenant, one of the distributor priority_classes. When the ingesters are overloaded, the distributors shed the streams of the lowest priority classes first. Streams without a known class are never shed.")
+	f.IntVar(&l.MaxLabelValueCardinality, "validation.max-label-value-cardinality", 0, "Maximum number of distinct values a stream label can have within an hour before it is considered high cardinality. The limit is enforced by each distributor on the streams it receives, not across the cluster. High cardinality labels are reported with a warning in the push response and handled according to the high cardinality labels policy. 0 to disable.")
+	f.StringVar(&l.HighCardinalityLabelsPolicy, "validation.high-cardinality-labels-policy", HighCardinalityLabelsPolicyWarn, "How the distributor handles high cardinality stream labels. Supported values: 'warn' only reports them, 'structured_metadata' moves them to the structured metadata of the entries, if structured metadata is allowed.")
 	f.BoolVar(&l.DiscoverLogLevels, "validation.discover-log-levels", true, "Discover and add log levels during ingestion, if not present already. Levels would be added to Structured Metadata with name level/LEVEL/Level/Severity/severity/SEVERITY/lvl/LVL/Lvl (case-sensitive) and one of the values from 'trace', 'debug', 'info', 'warn', 'error', 'critical', 'fatal' (case insensitive).")
 
 	_ = l.RejectOldSamplesMaxAge.Set("7d")
@@ -290,6 +318,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	_ = l.MaxQueryLength.Set("721h")
 	f.Var(&l.MaxQueryLength, "store.max-query-length", "The limit to length of chunk store queries. 0 to disable.")
 	f.IntVar(&l.MaxQuerySeries, "querier.max-query-series", 500, "Limit the maximum of unique series that is returned by a metric query. When the limit is reached an error is returned.")
+	f.IntVar(&l.MaxStatsEntriesPerQuery, "querier.max-stats-entries-per-query", 10000000, "Limit the number of log entries aggregated by a query with a stats stage. Stats queries are executed by a single querier without splitting nor sharding, when the limit is reached an error is returned. 0 to disable.")
 	_ = l.MaxQueryRange.Set("0s")
 	f.Var(&l.MaxQueryRange, "querier.max-query-range", "Limit the length of the [range] inside a range query. Default is 0 or unlimited")
 	_ = l.QueryTimeout.Set(DefaultPerTenantQueryTimeout)
@@ -314,6 +343,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.CardinalityLimit, "store.cardinality-limit", 1e5, "Cardinality limit for index queries.")
 	f.IntVar(&l.MaxStreamsMatchersPerQuery, "querier.max-streams-matcher-per-query", 1000, "Maximum number of stream matchers per query.")
 	f.IntVar(&l.MaxConcurrentTailRequests, "querier.max-concurrent-tail-requests", 10, "Maximum number of concurrent tail requests.")
+	f.IntVar(&l.MaxTailEntriesPerSecond, "ingester.max-tail-entries-per-second", 0, "Maximum number of entries per second sent by each ingester to a tail request. Only the first ingester of the replicas of a stream sends it when the limit is set. The entries exceeding the limit are not sent, and their number is returned to the client. 0 to disable.")
 
 	_ = l.MinShardingLookback.Set("0s")
 	f.Var(&l.MinShardingLookback, "frontend.min-sharding-lookback", "Limit queries that can be sharded. Queries within the time range of now and now minus this sharding lookback are not sharded. The default value of 0s disables the lookback, causing sharding of all queries at all times.")
@@ -463,6 +493,52 @@ func (l *Limits) Validate() error {
 		}
 	}
 
+	seenRules := make(map[string]struct{}, len(l.MetricAggregationRules))
+	for i := range l.MetricAggregationRules {
+		rule := &l.MetricAggregationRules[i]
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("invalid metric aggregation rule at index %d: %w", i, err)
+		}
+		if _, ok := seenRules[rule.Name]; ok {
+			return fmt.Errorf("invalid metric aggregation rule at index %d: duplicate name %q", i, rule.Name)
+		}
+		seenRules[rule.Name] = struct{}{}
+	}
+
+	for i := range l.IngestionPriorityStreams {
+		rule := &l.IngestionPriorityStreams[i]
+		matchers, err := syntax.ParseMatchers(rule.Selector, true)
+		if err != nil {
+			return fmt.Errorf("invalid ingestion priority stream selector at index %d: %w", i, err)
+		}
+		if rule.Class == "" {
+			return fmt.Errorf("invalid ingestion priority stream at index %d: the class must not be empty", i)
+		}
+		rule.Matchers = matchers
+	}
+
+	for _, key := range l.IndexedStructuredMetadataKeys {
+		if !model.LabelName(key).IsValid() {
+			return fmt.Errorf("invalid indexed structured metadata key %q", key)
+		}
+	}
+
+	for i, cfg := range l.IngestionRelabelConfigs {
+		if cfg == nil {
+			return fmt.Errorf("invalid ingestion relabel config at index %d: empty config", i)
+		}
+		// populate the relabel configs during validation
+		if err := cfg.validate(); err != nil {
+			return fmt.Errorf("invalid ingestion relabel config at index %d: %w", i, err)
+		}
+	}
+
+	switch l.HighCardinalityLabelsPolicy {
+	case "", HighCardinalityLabelsPolicyWarn, HighCardinalityLabelsPolicyStructuredMetadata:
+	default:
+		return fmt.Errorf("invalid high cardinality labels policy: %q, supported: %s, %s", l.HighCardinalityLabelsPolicy, HighCardinalityLabelsPolicyWarn, HighCardinalityLabelsPolicyStructuredMetadata)
+	}
+
 	if _, err := deletionmode.ParseMode(l.DeletionMode); err != nil {
 		return err
 	}
@@ -629,6 +705,11 @@ func (o *Overrides) MaxQuerySeries(_ context.Context, userID string) int {
 	return o.getOverridesForUser(userID).MaxQuerySeries
 }
 
+// MaxStatsEntriesPerQuery returns the limit of the log entries aggregated by a stats query.
+func (o *Overrides) MaxStatsEntriesPerQuery(_ context.Context, userID string) int {
+	return o.getOverridesForUser(userID).MaxStatsEntriesPerQuery
+}
+
 // MaxQueryRange returns the limit for the max [range] value that can be in a range query
 func (o *Overrides) MaxQueryRange(_ context.Context, userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MaxQueryRange)
@@ -736,6 +817,11 @@ func (o *Overrides) MaxConcurrentTailRequests(_ context.Context, userID string)
 	return o.getOverridesForUser(userID).MaxConcurrentTailRequests
 }
 
+// MaxTailEntriesPerSecond returns the maximum number of entries per second sent by each ingester to a tail request.
+func (o *Overrides) MaxTailEntriesPerSecond(userID string) int {
+	return o.getOverridesForUser(userID).MaxTailEntriesPerSecond
+}
+
 // MaxLineSize returns the maximum size in bytes the distributor should allow.
 func (o *Overrides) MaxLineSize(userID string) int {
 	return o.getOverridesForUser(userID).MaxLineSize.Val()
@@ -909,6 +995,26 @@ func (o *Overrides) StreamRetention(userID string) []StreamRetention {
 	return o.getOverridesForUser(userID).StreamRetention
 }
 
+// MetricAggregationRules returns the metrics pre-aggregated by the ingesters for a given user.
+func (o *Overrides) MetricAggregationRules(userID string) []MetricAggregationRule {
+	return o.getOverridesForUser(userID).MetricAggregationRules
+}
+
+// IngestionPriorityClass returns the ingestion priority class of the streams of a given user.
+func (o *Overrides) IngestionPriorityClass(userID string) string {
+	return o.getOverridesForUser(userID).IngestionPriorityClass
+}
+
+// IngestionPriorityStreams returns the ingestion priority classes of the streams matching a selector for a given user.
+func (o *Overrides) IngestionPriorityStreams(userID string) []IngestionPriorityStream {
+	return o.getOverridesForUser(userID).IngestionPriorityStreams
+}
+
+// IndexedStructuredMetadataKeys returns the structured metadata keys indexed by the ingesters for a given user.
+func (o *Overrides) IndexedStructuredMetadataKeys(userID string) []string {
+	return o.getOverridesForUser(userID).IndexedStructuredMetadataKeys
+}
+
 func (o *Overrides) UnorderedWrites(userID string) bool {
 	return o.getOverridesForUser(userID).UnorderedWrites
 }
@@ -958,6 +1064,19 @@ func (o *Overrides) DiscoverLogLevels(userID string) bool {
 	return o.getOverridesForUser(userID).DiscoverLogLevels
 }
 
+func (o *Overrides) MaxLabelValueCardinality(userID string) int {
+	return o.getOverridesForUser(userID).MaxLabelValueCardinality
+}
+
+func (o *Overrides) HighCardinalityLabelsPolicy(userID string) string {
+	return o.getOverridesForUser(userID).HighCardinalityLabelsPolicy
+}
+
+// IngestionRelabelConfigs returns the relabel configs applied to the labels of the incoming streams for a given user.
+func (o *Overrides) IngestionRelabelConfigs(userID string) []*IngestionRelabelConfig {
+	return o.getOverridesForUser(userID).IngestionRelabelConfigs
+}
+
 // VolumeEnabled returns whether volume endpoints are enabled for a user.
 func (o *Overrides) VolumeEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).VolumeEnabled
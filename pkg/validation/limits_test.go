@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/compactor/deletionmode"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/ruler/util"
 )
 
 func TestLimitsTagsYamlMatchJson(t *testing.T) {
@@ -354,3 +355,85 @@ func TestLimitsValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestIngestionRelabelConfigsValidation(t *testing.T) {
+	var limits Limits
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+deletion_mode: disabled
+bloom_block_encoding: none
+tsdb_sharding_strategy: power_of_two
+ingestion_relabel_configs:
+  - source_labels: [host]
+    target_label: hostname
+  - action: structured_metadata
+    regex: trace_id|span_id
+`), &limits))
+	limits.TSDBMaxBytesPerShard = DefaultTSDBMaxBytesPerShard
+	require.NoError(t, limits.Validate())
+
+	require.Len(t, limits.IngestionRelabelConfigs, 2)
+	require.NotNil(t, limits.IngestionRelabelConfigs[0].Relabel)
+	require.Equal(t, "hostname", limits.IngestionRelabelConfigs[0].Relabel.TargetLabel)
+	require.Nil(t, limits.IngestionRelabelConfigs[0].StructuredMetadataRegex)
+	require.Nil(t, limits.IngestionRelabelConfigs[1].Relabel)
+	require.True(t, limits.IngestionRelabelConfigs[1].StructuredMetadataRegex.MatchString("span_id"))
+	require.False(t, limits.IngestionRelabelConfigs[1].StructuredMetadataRegex.MatchString("trace_id_foo"))
+
+	// The same config unmarshaled from JSON is equivalent.
+	out, err := json.Marshal(limits.IngestionRelabelConfigs)
+	require.NoError(t, err)
+	var fromJSON []*IngestionRelabelConfig
+	require.NoError(t, json.Unmarshal(out, &fromJSON))
+	require.Equal(t, limits.IngestionRelabelConfigs[1].RelabelConfig, fromJSON[1].RelabelConfig)
+
+	for _, invalid := range []*IngestionRelabelConfig{
+		{RelabelConfig: util.RelabelConfig{Action: "unknown"}},
+		{RelabelConfig: util.RelabelConfig{Action: StructuredMetadataAction}},
+		{RelabelConfig: util.RelabelConfig{Action: StructuredMetadataAction, Regex: "("}},
+	} {
+		limits.IngestionRelabelConfigs = []*IngestionRelabelConfig{invalid}
+		require.ErrorContains(t, limits.Validate(), "invalid ingestion relabel config at index 0")
+	}
+}
+
+func TestMetricAggregationRulesValidation(t *testing.T) {
+	var limits Limits
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+deletion_mode: disabled
+bloom_block_encoding: none
+tsdb_sharding_strategy: power_of_two
+metric_aggregation_rules:
+  - name: levels
+    selector: '{namespace="prod"}'
+    by: [service_name, level]
+  - name: hourly
+    selector: '{namespace="prod", app=~"api|web"}'
+    interval: 1h
+    start: 2024-05-01T10:00:00Z
+`), &limits))
+	limits.TSDBMaxBytesPerShard = DefaultTSDBMaxBytesPerShard
+	require.NoError(t, limits.Validate())
+
+	require.Len(t, limits.MetricAggregationRules, 2)
+	require.Equal(t, model.Duration(time.Minute), limits.MetricAggregationRules[0].Interval)
+	require.Len(t, limits.MetricAggregationRules[0].Matchers, 1)
+	require.Equal(t, model.Duration(time.Hour), limits.MetricAggregationRules[1].Interval)
+	require.Len(t, limits.MetricAggregationRules[1].Matchers, 2)
+	require.True(t, limits.MetricAggregationRules[0].StartTime.IsZero())
+	require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), limits.MetricAggregationRules[1].StartTime)
+
+	for _, tc := range []struct {
+		rules []MetricAggregationRule
+		err   string
+	}{
+		{[]MetricAggregationRule{{Selector: `{app="foo"}`}}, `invalid name ""`},
+		{[]MetricAggregationRule{{Name: "foo", Selector: `app="foo"`}}, "invalid selector"},
+		{[]MetricAggregationRule{{Name: "foo", Selector: `{app="foo"}`, By: []string{"not-valid"}}}, `invalid label name "not-valid"`},
+		{[]MetricAggregationRule{{Name: "foo", Selector: `{app="foo"}`, Interval: model.Duration(-time.Minute)}}, "interval must be positive"},
+		{[]MetricAggregationRule{{Name: "foo", Selector: `{app="foo"}`, Start: "2024-05-01"}}, "invalid start"},
+		{[]MetricAggregationRule{{Name: "foo", Selector: `{app="foo"}`}, {Name: "foo", Selector: `{app="bar"}`}}, `invalid metric aggregation rule at index 1: duplicate name "foo"`},
+	} {
+		limits.MetricAggregationRules = tc.rules
+		require.ErrorContains(t, limits.Validate(), tc.err)
+	}
+}
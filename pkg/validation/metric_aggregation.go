@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+const (
+	// MetricAggregationCountField is the logfmt field of the lines of the pre-aggregated metric streams
+	// holding the number of entries of a bucket.
+	MetricAggregationCountField = "count"
+	// MetricAggregationBytesField is the logfmt field of the lines of the pre-aggregated metric streams
+	// holding the size in bytes of the entries of a bucket.
+	MetricAggregationBytesField = "bytes"
+
+	defaultMetricAggregationInterval = model.Duration(time.Minute)
+)
+
+// MetricAggregationRule defines a metric pre-aggregated by the ingesters: the number and the size of the entries
+// of the streams matching the selector, grouped by labels, in buckets of the interval. The buckets are written
+// to the stream {__aggregated_metric__="<name>", <by labels>...} of the tenant.
+type MetricAggregationRule struct {
+	Name     string            `yaml:"name" json:"name" doc:"description=Name of the rule, the value of the __aggregated_metric__ label of the pre-aggregated metric streams."`
+	Selector string            `yaml:"selector" json:"selector" doc:"description=Stream selector of the streams aggregated."`
+	By       []string          `yaml:"by" json:"by" doc:"description=Labels the entries are grouped by. The values are taken from the stream labels, or from the structured metadata of the entries."`
+	Interval model.Duration    `yaml:"interval" json:"interval" doc:"description=Width of the aggregation buckets. Defaults to 1m."`
+	Start    string            `yaml:"start" json:"start" doc:"description=Time the rule was added at, in RFC3339 format. The queries are only rewritten to read the pre-aggregated metric streams after it, and not at all if unset."`
+	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
+	// StartTime is populated during validation.
+	StartTime time.Time `yaml:"-" json:"-"`
+}
+
+func (r *MetricAggregationRule) validate() error {
+	if !model.LabelValue(r.Name).IsValid() || r.Name == "" {
+		return fmt.Errorf("invalid name %q", r.Name)
+	}
+	matchers, err := syntax.ParseMatchers(r.Selector, true)
+	if err != nil {
+		return fmt.Errorf("invalid selector: %w", err)
+	}
+	for _, name := range r.By {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+	if r.Interval == 0 {
+		r.Interval = defaultMetricAggregationInterval
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("interval must be positive was %s", r.Interval)
+	}
+	if r.Start != "" {
+		start, err := time.Parse(time.RFC3339, r.Start)
+		if err != nil {
+			return fmt.Errorf("invalid start: %w", err)
+		}
+		r.StartTime = start
+	}
+	// populate matchers during validation
+	r.Matchers = matchers
+	return nil
+}
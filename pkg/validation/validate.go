@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,12 +17,19 @@ const (
 	InvalidLabels = "invalid_labels"
 	MissingLabels = "missing_labels"
 
+	// RelabelDropped is a reason for discarding log lines of streams dropped by the ingestion relabel configs.
+	RelabelDropped = "relabel_dropped"
+
 	MissingLabelsErrorMsg = "error at least one label pair is required per stream"
 	InvalidLabelsErrorMsg = "Error parsing labels '%s' with error: %s"
 	// RateLimited is one of the values for the reason to discard samples.
 	// Declared here to avoid duplication in ingester and distributor.
 	RateLimited         = "rate_limited"
 	RateLimitedErrorMsg = "Ingestion rate limit exceeded for user %s (limit: %d bytes/sec) while attempting to ingest '%d' lines totaling '%d' bytes, reduce log volume or contact your Loki administrator to see if the limit can be increased"
+	// IngestersOverloaded is a reason for discarding the log lines of the streams shed by the distributors
+	// because their priority class is too low for the load of their ingesters.
+	IngestersOverloaded         = "ingesters_overloaded"
+	IngestersOverloadedErrorMsg = "Ingestion of the streams of priority class '%s' shed by the distributor because the ingesters are overloaded (load: %.2f), retry later"
 	// LineTooLong is a reason for discarding too long log lines.
 	LineTooLong         = "line_too_long"
 	LineTooLongErrorMsg = "Max entry size '%d' bytes exceeded for stream '%s' while adding an entry with length '%d' bytes"
@@ -82,6 +90,34 @@ func (e *ErrStreamRateLimit) Error() string {
 		e.Bytes.String())
 }
 
+// ErrDiscarded is a validation error carrying the reason the log lines were discarded for.
+type ErrDiscarded struct {
+	Reason string
+	Err    error
+}
+
+// NewErrDiscarded returns an ErrDiscarded formatting its error like fmt.Errorf.
+func NewErrDiscarded(reason, format string, args ...any) error {
+	return &ErrDiscarded{Reason: reason, Err: fmt.Errorf(format, args...)}
+}
+
+func (e *ErrDiscarded) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ErrDiscarded) Unwrap() error {
+	return e.Err
+}
+
+// DiscardReason returns the reason of an ErrDiscarded, or defaultReason if err is not one.
+func DiscardReason(err error, defaultReason string) string {
+	var discarded *ErrDiscarded
+	if errors.As(err, &discarded) {
+		return discarded.Reason
+	}
+	return defaultReason
+}
+
 // MutatedSamples is a metric of the total number of lines mutated, by reason.
 var MutatedSamples = promauto.NewCounterVec(
 	prometheus.CounterOpts{
@@ -363,6 +363,8 @@ func getFieldCustomType(t reflect.Type) (string, bool) {
 		return fieldString, true
 	case reflect.TypeOf([]*util.RelabelConfig{}).String():
 		return fieldRelabelConfig, true
+	case reflect.TypeOf([]*validation.IngestionRelabelConfig{}).String():
+		return fieldRelabelConfig, true
 	case reflect.TypeOf([]*relabel.Config{}).String():
 		return fieldRelabelConfig, true
 	case reflect.TypeOf([]*util_validation.BlockedQuery{}).String():
@@ -458,6 +460,8 @@ func getCustomFieldType(t reflect.Type) (string, bool) {
 		return fieldRelabelConfig, true
 	case reflect.TypeOf([]*util.RelabelConfig{}).String():
 		return fieldRelabelConfig, true
+	case reflect.TypeOf([]*validation.IngestionRelabelConfig{}).String():
+		return fieldRelabelConfig, true
 	case reflect.TypeOf(&prometheus_config.RemoteWriteConfig{}).String():
 		return "remote_write_config...", true
 	case reflect.TypeOf(validation.OverwriteMarshalingStringMap{}).String():